@@ -0,0 +1,153 @@
+package gosmpp
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/linxGnu/gosmpp/clock"
+)
+
+// Default limit of auto replies to single subscriber.
+const (
+	DefaultAutoReplyLimit    = 3
+	DefaultAutoReplyInterval = time.Hour
+)
+
+// AutoReplier replies to incoming messages with canned texts by keyword, e.g. "You are unsubscribed" to STOP.
+// Messages are fed by HandleMessage, e.g. from Settings.OnMessage of receiver session, and replies are sent
+// from the short code by Messenger, e.g. of linked transmitter session:
+//
+//	replier := gosmpp.NewAutoReplier(gosmpp.NewMessenger(transmitter), router.HandleMessage)
+//	replier.Reply("STOP", "You are unsubscribed")
+//	settings.OnMessage = replier.HandleMessage
+//
+// Replies to subscriber are limited, so auto responders could not reply to each other endlessly.
+type AutoReplier struct {
+	messenger *Messenger
+	next      func(IncomingMessage)
+	limit     int
+	interval  time.Duration
+	onError   func(m IncomingMessage, err error)
+	clock     clock.Clock
+
+	mu        sync.Mutex
+	replies   map[string]string
+	sent      map[string]*replyWindow // by subscriber
+	lastSweep time.Time
+}
+
+type replyWindow struct {
+	start time.Time
+	count int
+}
+
+// AutoReplierOption configures AutoReplier.
+type AutoReplierOption func(*AutoReplier)
+
+// WithAutoReplyLimit limits number of replies to subscriber in interval, DefaultAutoReplyLimit
+// in DefaultAutoReplyInterval by default.
+func WithAutoReplyLimit(limit int, interval time.Duration) AutoReplierOption {
+	return func(a *AutoReplier) {
+		a.limit, a.interval = limit, interval
+	}
+}
+
+// WithAutoReplyErrorHandler sets function called with message which reply failed to be sent.
+func WithAutoReplyErrorHandler(onError func(m IncomingMessage, err error)) AutoReplierOption {
+	return func(a *AutoReplier) {
+		a.onError = onError
+	}
+}
+
+// WithAutoReplyClock sets clock of reply limits, real clock by default.
+func WithAutoReplyClock(c clock.Clock) AutoReplierOption {
+	return func(a *AutoReplier) {
+		a.clock = c
+	}
+}
+
+// NewAutoReplier returns auto replier sending replies by messenger and passing all messages on to next,
+// if not nil.
+func NewAutoReplier(messenger *Messenger, next func(IncomingMessage), opts ...AutoReplierOption) *AutoReplier {
+	a := &AutoReplier{
+		messenger: messenger,
+		next:      next,
+		limit:     DefaultAutoReplyLimit,
+		interval:  DefaultAutoReplyInterval,
+		replies:   make(map[string]string),
+		sent:      make(map[string]*replyWindow),
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	a.clock = clock.OrReal(a.clock)
+	return a
+}
+
+// Reply sets reply to messages of keyword, see Keyword. Empty text removes the reply.
+func (a *AutoReplier) Reply(keyword, text string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if keyword = normalizeKeyword(keyword); text == "" {
+		delete(a.replies, keyword)
+	} else {
+		a.replies[keyword] = text
+	}
+}
+
+// HandleMessage replies to m if there is reply to its keyword and subscriber is within the limit, then passes
+// m on. Reply is sent in background, without waiting for the response.
+func (a *AutoReplier) HandleMessage(m IncomingMessage) {
+	if text, ok := a.reply(m); ok {
+		go func() {
+			if _, err := a.messenger.SendText(context.Background(), m.To, m.From, text); err != nil && a.onError != nil {
+				a.onError(m, err)
+			}
+		}()
+	}
+
+	if a.next != nil {
+		a.next(m)
+	}
+}
+
+// reply returns reply to m, counting it in limit of its subscriber.
+func (a *AutoReplier) reply(m IncomingMessage) (string, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	text, ok := a.replies[Keyword(m.Text)]
+	if !ok {
+		return "", false
+	}
+
+	now := a.clock.Now()
+	a.sweep(now)
+
+	w := a.sent[m.From]
+	if w == nil || now.Sub(w.start) >= a.interval {
+		w = &replyWindow{start: now}
+		a.sent[m.From] = w
+	}
+	if w.count >= a.limit {
+		return "", false
+	}
+	w.count++
+	return text, true
+}
+
+// sweep removes ended limit windows, once per interval.
+func (a *AutoReplier) sweep(now time.Time) {
+	if now.Sub(a.lastSweep) < a.interval {
+		return
+	}
+	a.lastSweep = now
+
+	for subscriber, w := range a.sent {
+		if now.Sub(w.start) >= a.interval {
+			delete(a.sent, subscriber)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package gosmpp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp/clock"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAutoReplier(t *testing.T) {
+	quotes := make(chan MessageQuote, 8)
+	m := newTestMessenger(t, WithPricingHook(func(_ context.Context, q MessageQuote) error {
+		quotes <- q
+		return nil
+	}))
+
+	var passed []IncomingMessage
+	fake := clock.NewFake(time.Now())
+	replier := NewAutoReplier(m, func(m IncomingMessage) { passed = append(passed, m) },
+		WithAutoReplyLimit(2, time.Minute), WithAutoReplyClock(fake))
+	replier.Reply("stop", "You are unsubscribed")
+
+	mo := func(text string) IncomingMessage {
+		msg, ok := ParseIncomingMessage(newMO("+84901234567", "8888", text))
+		require.True(t, ok)
+		return msg
+	}
+	expectReplies := func(n int) {
+		for i := 0; i < n; i++ {
+			select {
+			case q := <-quotes:
+				require.Equal(t, "8888", q.SourceAddr.Address())
+				require.Equal(t, "84901234567", q.DestAddr.Address())
+			case <-time.After(5 * time.Second):
+				require.FailNow(t, "reply not sent")
+			}
+		}
+		time.Sleep(50 * time.Millisecond)
+		require.Len(t, quotes, 0)
+	}
+
+	replier.HandleMessage(mo("Stop."))
+	replier.HandleMessage(mo("hello"))
+	expectReplies(1)
+
+	// limited within interval
+	replier.HandleMessage(mo("STOP"))
+	replier.HandleMessage(mo("STOP"))
+	expectReplies(1)
+
+	fake.Advance(time.Minute)
+	replier.HandleMessage(mo("STOP"))
+	expectReplies(1)
+
+	replier.Reply("STOP", "")
+	replier.HandleMessage(mo("STOP"))
+	expectReplies(0)
+
+	require.Len(t, passed, 6)
+}
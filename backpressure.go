@@ -0,0 +1,137 @@
+package gosmpp
+
+import (
+	"sync/atomic"
+)
+
+const (
+	// DefaultBackpressureHighWatermark is default number of queued PDUs making session saturated.
+	DefaultBackpressureHighWatermark = 8
+
+	// DefaultBackpressureLowWatermark is default number of queued PDUs at which saturated session resumes.
+	DefaultBackpressureLowWatermark = 4
+)
+
+// backpressure tracks saturation of session.
+type backpressure struct {
+	high, low int
+
+	saturated  int32
+	submitting int32 // Submit(s) which are not returned yet
+}
+
+// WithBackpressureWatermarks sets queue watermarks for backpressure signaling.
+//
+// Session becomes saturated when number of queued PDUs (handed to Submit but not written yet) reaches high,
+// and resumes when it drops to low. Default values are DefaultBackpressureHighWatermark and
+// DefaultBackpressureLowWatermark.
+func WithBackpressureWatermarks(high, low int) SessionOption {
+	return func(s *Session) {
+		if high > 0 {
+			s.pressure.high = high
+		}
+		if low >= 0 && low < s.pressure.high {
+			s.pressure.low = low
+		} else {
+			s.pressure.low = s.pressure.high / 2
+		}
+	}
+}
+
+// queued returns number of PDUs handed to Submit but not written to SMSC yet.
+func (s *Session) queued() int {
+	n := int(atomic.LoadInt32(&s.pressure.submitting))
+	if b := s.bound(); b != nil && b.out != nil {
+		if depth := len(b.out.input); depth > n {
+			n = depth
+		}
+	}
+	return n
+}
+
+// windowFull tells whether request window is full, if WindowedRequestTracking or WithSubmitWindow is set.
+func (s *Session) windowFull(b *transceivable) bool {
+	return s.window.full() ||
+		s.settings.WindowedRequestTracking != nil && b.stats.outstanding() >= int(s.settings.MaxWindowSize)
+}
+
+// AvailableCapacity returns number of PDUs which could be submitted now without saturating the session.
+//
+// It is bounded by high watermark of the queue and by free slots of request window (if WindowedRequestTracking or
+// WithSubmitWindow is set).
+// Zero is returned when session is not bound.
+func (s *Session) AvailableCapacity() int {
+	if !s.IsBound() {
+		return 0
+	}
+
+	capacity := s.pressure.high - s.queued()
+	if s.settings.WindowedRequestTracking != nil {
+		if free := int(s.settings.MaxWindowSize) - s.bound().stats.outstanding(); free < capacity {
+			capacity = free
+		}
+	}
+	if free := s.window.free(); free >= 0 && free < capacity {
+		capacity = free
+	}
+
+	if capacity < 0 {
+		return 0
+	}
+	return capacity
+}
+
+// Saturated tells whether producers should pause submitting: session is not bound, request window is full or
+// queue reached high watermark. Once saturated, session resumes when queue drops to low watermark.
+func (s *Session) Saturated() bool {
+	s.updatePressure()
+	return atomic.LoadInt32(&s.pressure.saturated) == 1
+}
+
+// Backpressure returns channel receiving true when session becomes saturated and false when it resumes.
+//
+// Signals are dropped when the channel buffer is full. Calling returned func
+// stops the subscription and closes the channel.
+func (s *Session) Backpressure(buffer int) (<-chan bool, func()) {
+	ch := make(chan bool, buffer)
+	stop := s.subscribeChan(func(e Event) {
+		switch e.Type {
+		case EventSaturated:
+			select {
+			case ch <- true:
+			default:
+			}
+
+		case EventResumed:
+			select {
+			case ch <- false:
+			default:
+			}
+		}
+	}, func() {
+		close(ch)
+	})
+	return ch, stop
+}
+
+// updatePressure re-evaluates saturation and publishes EventSaturated/EventResumed on change.
+func (s *Session) updatePressure() {
+	b := s.bound()
+	bound := s.IsBound()
+	queued := s.queued()
+
+	if atomic.LoadInt32(&s.pressure.saturated) == 0 {
+		if !bound || s.windowFull(b) || queued >= s.pressure.high {
+			if atomic.CompareAndSwapInt32(&s.pressure.saturated, 0, 1) {
+				s.events.publish(Event{Type: EventSaturated})
+			}
+		}
+		return
+	}
+
+	if bound && !s.windowFull(b) && queued <= s.pressure.low {
+		if atomic.CompareAndSwapInt32(&s.pressure.saturated, 1, 0) {
+			s.events.publish(Event{Type: EventResumed})
+		}
+	}
+}
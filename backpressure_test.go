@@ -0,0 +1,72 @@
+package gosmpp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithBackpressureWatermarks(t *testing.T) {
+	s := &Session{pressure: backpressure{high: DefaultBackpressureHighWatermark, low: DefaultBackpressureLowWatermark}}
+
+	WithBackpressureWatermarks(10, 3)(s)
+	require.Equal(t, backpressure{high: 10, low: 3}, s.pressure)
+
+	// invalid low watermark falls back to half of high
+	WithBackpressureWatermarks(20, 20)(s)
+	require.Equal(t, backpressure{high: 20, low: 10}, s.pressure)
+}
+
+func TestSessionBackpressure(t *testing.T) {
+	auth := nextAuth()
+	s, err := NewSession(
+		TRXConnector(NonTLSDialer, auth),
+		Settings{
+			ReadTimeout: 2 * time.Second,
+			WindowedRequestTracking: &WindowedRequestTracking{
+				OnExpectedPduResponse: func(Response) {},
+				EnableAutoRespond:     true,
+				MaxWindowSize:         3,
+				StoreAccessTimeOut:    100,
+			},
+		}, -1, WithBackpressureWatermarks(2, 1))
+	require.Nil(t, err)
+
+	ch, stop := s.Backpressure(10)
+	defer stop()
+
+	require.False(t, s.Saturated())
+	require.Equal(t, 2, s.AvailableCapacity())
+
+	// fill request window
+	stats := s.bound().stats
+	for seq := int32(-1); seq >= -3; seq-- {
+		stats.inflight.store(seq, time.Now().UnixNano())
+	}
+
+	require.Zero(t, s.AvailableCapacity())
+	require.True(t, s.Saturated())
+	require.True(t, <-ch)
+
+	// free the window
+	for seq := int32(-1); seq >= -3; seq-- {
+		stats.inflight.take(seq)
+	}
+
+	require.False(t, s.Saturated())
+	require.False(t, <-ch)
+
+	// submitting keeps session resumed
+	require.Nil(t, s.Submit(newSubmitSM(auth.SystemID)))
+	require.Eventually(t, func() bool {
+		return s.Stats().PDUsReceived > 0 && s.OutstandingCount() == 0
+	}, time.Second, 10*time.Millisecond)
+	require.False(t, s.Saturated())
+
+	// unbound session is saturated
+	require.Nil(t, s.Close())
+	require.Zero(t, s.AvailableCapacity())
+	require.True(t, s.Saturated())
+	require.True(t, <-ch)
+}
@@ -0,0 +1,202 @@
+package gosmpp
+
+import (
+	"container/list"
+	"errors"
+	"sync"
+
+	"github.com/linxGnu/gosmpp/pdu"
+)
+
+// ErrEvicted indicates queued request is dropped to keep MemoryBudget.
+var ErrEvicted = errors.New("evicted to keep memory budget")
+
+// EvictedBuffer tells which buffer evicted data was kept in.
+type EvictedBuffer byte
+
+const (
+	// EvictedParts are parts of incomplete concatenated message kept by MemoryReassemblyStore.
+	EvictedParts EvictedBuffer = iota
+
+	// EvictedSeen is reassembled message remembered by MemoryReassemblyStore for deduplication,
+	// its copies are not dropped after eviction.
+	EvictedSeen
+
+	// EvictedQueue are requests waiting in queue of destination of WithDestinationOrdering,
+	// their calls fail with ErrEvicted.
+	EvictedQueue
+)
+
+// String interface.
+func (b EvictedBuffer) String() string {
+	switch b {
+	case EvictedParts:
+		return "Parts"
+
+	case EvictedSeen:
+		return "Seen"
+
+	case EvictedQueue:
+		return "Queue"
+
+	default:
+		return ""
+	}
+}
+
+// Eviction describes data evicted to keep MemoryBudget.
+type Eviction struct {
+	Buffer EvictedBuffer
+
+	// Key of evicted data: key of concatenated message, or destination of queued requests.
+	Key string
+
+	// Bytes released by the eviction.
+	Bytes int
+}
+
+// MemoryBudget caps memory held by buffers charged to it. Once over the budget, data least recently updated
+// is evicted, whichever buffer keeps it, until the rest fits.
+//
+// Budget could be per session, given only to buffers of the session, or global, shared by buffers of all
+// sessions. Buffers could be charged to both:
+//
+//	global := gosmpp.NewMemoryBudget(64<<20, onEvicted)
+//	store := gosmpp.NewMemoryReassemblyStore(0, gosmpp.WithStoreBudget(gosmpp.NewMemoryBudget(1<<20, onEvicted), global))
+//
+// MemoryBudget is safe for concurrent use.
+type MemoryBudget struct {
+	max       int
+	onEvicted func(Eviction)
+
+	mu   sync.Mutex
+	used int
+	lru  list.List // *budgetEntry, the most recently updated at front
+}
+
+// budgetEntry is data charged to MemoryBudget.
+type budgetEntry struct {
+	buffer EvictedBuffer
+	key    string
+
+	// release drops the data from its buffer once evicted, it is called out of lock of the budget.
+	release func()
+
+	size int
+	elem *list.Element
+}
+
+// eviction is entry evicted by MemoryBudget, to be released out of locks of buffers.
+type eviction struct {
+	Eviction
+	release func()
+}
+
+// NewMemoryBudget returns budget of maxBytes, calling onEvicted, if not nil, after data is evicted.
+// Callback must not block, it is called from goroutine updating the evicting buffer.
+func NewMemoryBudget(maxBytes int, onEvicted func(Eviction)) *MemoryBudget {
+	return &MemoryBudget{max: maxBytes, onEvicted: onEvicted}
+}
+
+// Used returns number of bytes charged to the budget.
+func (b *MemoryBudget) Used() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.used
+}
+
+// charge sets size of e, marking it the most recently updated, and evicts the least recently updated entries,
+// e itself too, until the budget is kept. Evicted entries are to be passed to evict.
+func (b *MemoryBudget) charge(e *budgetEntry, size int) (evicted []eviction) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if e.elem == nil {
+		e.elem, e.size = b.lru.PushFront(e), 0
+	} else {
+		b.lru.MoveToFront(e.elem)
+	}
+	b.used += size - e.size
+	e.size = size
+
+	for b.used > b.max {
+		victim := b.lru.Back().Value.(*budgetEntry)
+		evicted = append(evicted, eviction{
+			Eviction: Eviction{Buffer: victim.buffer, Key: victim.key, Bytes: victim.size},
+			release:  victim.release,
+		})
+		b.remove(victim)
+	}
+	return
+}
+
+// uncharge removes e from the budget, without eviction.
+func (b *MemoryBudget) uncharge(e *budgetEntry) {
+	b.mu.Lock()
+	b.remove(e)
+	b.mu.Unlock()
+}
+
+func (b *MemoryBudget) remove(e *budgetEntry) {
+	if e.elem != nil {
+		b.lru.Remove(e.elem)
+		b.used -= e.size
+		e.elem, e.size = nil, 0
+	}
+}
+
+// evict releases evicted entries from their buffers and reports them. It must be called out of locks of buffers.
+func (b *MemoryBudget) evict(evicted []eviction) {
+	for _, e := range evicted {
+		e.release()
+		if b.onEvicted != nil {
+			b.onEvicted(e.Eviction)
+		}
+	}
+}
+
+// budgetCharges are entries of data charged to budgets of its buffer, one per budget.
+type budgetCharges []*budgetEntry
+
+func newBudgetCharges(budgets []*MemoryBudget, buffer EvictedBuffer, key string, release func()) budgetCharges {
+	if len(budgets) == 0 {
+		return nil
+	}
+	charges := make(budgetCharges, len(budgets))
+	for i := range charges {
+		charges[i] = &budgetEntry{buffer: buffer, key: key, release: release}
+	}
+	return charges
+}
+
+// charge sets size of the data in all budgets, returning func evicting entries over the budgets.
+// The func must be called out of lock of the buffer.
+func (c budgetCharges) charge(budgets []*MemoryBudget, size int) (evict func()) {
+	if len(c) == 0 {
+		return func() {}
+	}
+
+	evicted := make([][]eviction, len(c))
+	for i, e := range c {
+		evicted[i] = budgets[i].charge(e, size)
+	}
+	return func() {
+		for i := range evicted {
+			budgets[i].evict(evicted[i])
+		}
+	}
+}
+
+// uncharge removes the data from all budgets.
+func (c budgetCharges) uncharge(budgets []*MemoryBudget) {
+	for i, e := range c {
+		budgets[i].uncharge(e)
+	}
+}
+
+// encodedLen returns length of p encoded.
+func encodedLen(p pdu.PDU) int {
+	buf := pdu.NewBuffer(make([]byte, 0, 64))
+	p.Marshal(buf)
+	return buf.Len()
+}
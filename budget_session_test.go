@@ -0,0 +1,62 @@
+package gosmpp_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp"
+	"github.com/linxGnu/gosmpp/pdu"
+	"github.com/linxGnu/gosmpp/server/smsctest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionQueueBudget(t *testing.T) {
+	smsc := smsctest.NewPipeServer(nil)
+	defer smsc.Close()
+
+	evicted := make(chan gosmpp.Eviction, 10)
+	budget := gosmpp.NewMemoryBudget(250, func(e gosmpp.Eviction) {
+		evicted <- e
+	})
+
+	s, err := gosmpp.NewSession(gosmpp.TRXConnector(smsc.Dialer(), gosmpp.Auth{SMSC: "pipe", SystemID: "esme"}),
+		gosmpp.Settings{ReadTimeout: 2 * time.Second}, -1,
+		gosmpp.WithDestinationOrdering(0, 0), gosmpp.WithQueueBudget(budget))
+	require.Nil(t, err)
+	defer func() {
+		_ = s.Close()
+	}()
+
+	// heads of queues are never responded, so the rest waits
+	smsc.SetFault(func(pdu.PDU) smsctest.Fault { return smsctest.Fault{Drop: true} })
+	text := strings.Repeat("x", 100)
+
+	s.SubmitAsync(newTextSubmitSM("1", text))
+	waiting1 := s.SubmitAsync(newTextSubmitSM("1", text))
+	s.SubmitAsync(newTextSubmitSM("2", text))
+	require.Zero(t, len(evicted))
+	require.Greater(t, budget.Used(), 100)
+
+	waiting2 := s.SubmitAsync(newTextSubmitSM("2", text))
+	select {
+	case e := <-evicted:
+		require.Equal(t, gosmpp.EvictedQueue, e.Buffer)
+		require.Equal(t, "1", e.Key)
+	case <-time.After(time.Second):
+		t.Fatal("queue is not evicted")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, err = waiting1.Wait(ctx)
+	require.ErrorIs(t, err, gosmpp.ErrEvicted)
+
+	select {
+	case <-waiting2.Done():
+		t.Fatal("recently submitted destination is evicted")
+	default:
+	}
+}
@@ -0,0 +1,94 @@
+package gosmpp
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryBudget(t *testing.T) {
+	var evicted []Eviction
+	b := NewMemoryBudget(10, func(e Eviction) {
+		evicted = append(evicted, e)
+	})
+
+	released := make(map[string]int)
+	entry := func(key string) *budgetEntry {
+		return &budgetEntry{buffer: EvictedParts, key: key, release: func() {
+			released[key]++
+		}}
+	}
+	e1, e2, e3 := entry("1"), entry("2"), entry("3")
+
+	require.Empty(t, b.charge(e1, 4))
+	require.Empty(t, b.charge(e2, 4))
+	require.Empty(t, b.charge(e1, 5))
+	require.Equal(t, 9, b.Used())
+
+	// e2 is the least recently updated
+	b.evict(b.charge(e3, 3))
+	require.Equal(t, []Eviction{{Buffer: EvictedParts, Key: "2", Bytes: 4}}, evicted)
+	require.Equal(t, map[string]int{"2": 1}, released)
+	require.Equal(t, 8, b.Used())
+
+	b.uncharge(e1)
+	b.uncharge(e1)
+	require.Equal(t, 3, b.Used())
+
+	// entry over the whole budget is evicted itself
+	b.evict(b.charge(e1, 11))
+	require.Equal(t, []Eviction{
+		{Buffer: EvictedParts, Key: "2", Bytes: 4},
+		{Buffer: EvictedParts, Key: "3", Bytes: 3},
+		{Buffer: EvictedParts, Key: "1", Bytes: 11},
+	}, evicted)
+	require.Equal(t, 0, b.Used())
+}
+
+func TestMemoryReassemblyStoreBudget(t *testing.T) {
+	var evicted []Eviction
+	session := NewMemoryBudget(40, func(e Eviction) {
+		evicted = append(evicted, e)
+	})
+	global := NewMemoryBudget(1000, nil)
+	store := NewMemoryReassemblyStore(0, WithStoreBudget(session, global))
+	ctx := context.Background()
+
+	// orphan parts flooded by peer, 17 bytes with key each
+	for i := 0; i < 10; i++ {
+		_, complete, err := store.AddPart(ctx, fmt.Sprint("orphan", i), 3, 1, []byte("0123456789"))
+		require.Nil(t, err)
+		require.False(t, complete)
+	}
+	require.Equal(t, 2, store.Len())
+	require.Len(t, evicted, 8)
+	require.Equal(t, Eviction{Buffer: EvictedParts, Key: "orphan0", Bytes: 17}, evicted[0])
+	require.Equal(t, 34, session.Used())
+	require.Equal(t, 34, global.Used())
+
+	// the least recently updated message is evicted
+	_, _, err := store.AddPart(ctx, "orphan8", 3, 2, []byte("x"))
+	require.Nil(t, err)
+	_, _, err = store.AddPart(ctx, "other", 3, 1, []byte("0123456789"))
+	require.Nil(t, err)
+	require.Equal(t, Eviction{Buffer: EvictedParts, Key: "orphan9", Bytes: 17}, evicted[len(evicted)-1])
+	require.Equal(t, 33, session.Used())
+	require.Equal(t, 33, global.Used())
+
+	// completed message is released
+	parts, complete, err := store.AddPart(ctx, "orphan8", 3, 3, []byte("y"))
+	require.Nil(t, err)
+	require.True(t, complete)
+	require.Equal(t, [][]byte{[]byte("0123456789"), []byte("x"), []byte("y")}, parts)
+	require.Equal(t, 15, session.Used())
+	require.Equal(t, 15, global.Used())
+
+	// remembered messages are charged too
+	first, err := store.FirstSeen(ctx, "seen")
+	require.Nil(t, err)
+	require.True(t, first)
+	require.Equal(t, 19, session.Used())
+	require.Len(t, evicted, 9)
+}
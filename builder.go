@@ -0,0 +1,217 @@
+package gosmpp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+)
+
+// MessageWaitingType is type of message waiting indication.
+type MessageWaitingType byte
+
+// Message waiting indication types.
+const (
+	MessageWaitingVoicemail MessageWaitingType = iota
+	MessageWaitingFax
+	MessageWaitingEmail
+	MessageWaitingOther
+)
+
+// ErrDataCodingConflict indicates builder is given both message class and message waiting indication,
+// which are exclusive in the data coding scheme.
+var ErrDataCodingConflict = errors.New("message class and message waiting indication are exclusive")
+
+// data coding scheme groups, see 3GPP TS 23.038 section 4
+const (
+	dcsMessageClass   = 0x10 // general data coding with message class in bits 1..0
+	dcsMWIDiscard     = 0xC0 // message waiting indication group, discard message
+	dcsMWIStoreGSM7   = 0xD0
+	dcsMWIStoreUCS2   = 0xE0
+	dcsMWIActive      = 0x08
+	mwiActive         = 0x80 // ms_msg_wait_facilities indication active bit
+	maxNumberMessages = 99
+)
+
+// SubmitSMBuilder builds submit_sm of a text message, split into parts if needed.
+//
+// Errors of setters are reported by Build.
+type SubmitSMBuilder struct {
+	from, to string
+	text     string
+
+	protocolID   *byte
+	messageClass *byte
+	mwi          *messageWaiting
+
+	err error
+}
+
+type messageWaiting struct {
+	kind   MessageWaitingType
+	active bool
+	count  int
+}
+
+// NewSubmitSMBuilder returns empty builder.
+func NewSubmitSMBuilder() *SubmitSMBuilder {
+	return &SubmitSMBuilder{}
+}
+
+// From sets source address, see WithAddressNormalizer.
+func (b *SubmitSMBuilder) From(addr string) *SubmitSMBuilder {
+	b.from = addr
+	return b
+}
+
+// To sets destination address, see WithAddressNormalizer.
+func (b *SubmitSMBuilder) To(addr string) *SubmitSMBuilder {
+	b.to = addr
+	return b
+}
+
+// Text sets text, encoded in GSM 7-bit if possible, UCS2 otherwise.
+func (b *SubmitSMBuilder) Text(text string) *SubmitSMBuilder {
+	b.text = text
+	return b
+}
+
+// ProtocolID sets protocol_id, e.g. data.PID_RETURN_CALL_MESSAGE.
+func (b *SubmitSMBuilder) ProtocolID(pid byte) *SubmitSMBuilder {
+	b.protocolID = &pid
+	return b
+}
+
+// ReplaceIfPresent sets protocol_id of replace short message type 1 to 7: handset replaces previously
+// received message of the same type and source address, e.g. so repeated OTP or status messages
+// do not stack up.
+func (b *SubmitSMBuilder) ReplaceIfPresent(replaceType byte) *SubmitSMBuilder {
+	if replaceType < 1 || replaceType > 7 {
+		b.fail(fmt.Errorf("gosmpp: invalid replace type %d", replaceType))
+		return b
+	}
+	return b.ProtocolID(data.PID_SHORT_MESSAGE_TYPE_0 + replaceType)
+}
+
+// SetMessageClass sets message class 0 to 3 in data coding: 0 is flash message, 1 is stored in handset,
+// 2 in SIM and 3 in terminal equipment.
+func (b *SubmitSMBuilder) SetMessageClass(class byte) *SubmitSMBuilder {
+	if class > 3 {
+		b.fail(fmt.Errorf("gosmpp: invalid message class %d", class))
+		return b
+	}
+	b.messageClass = &class
+	return b
+}
+
+// SetMessageWaiting sets or clears message waiting indication of the type, e.g. voicemail icon, with number of
+// waiting messages from 0 to 99. Data coding and ms_msg_wait_facilities and number_of_messages TLVs are set.
+//
+// Message without text is discarded by handset after updating the indication, otherwise stored.
+func (b *SubmitSMBuilder) SetMessageWaiting(kind MessageWaitingType, active bool, count int) *SubmitSMBuilder {
+	if kind > MessageWaitingOther {
+		b.fail(fmt.Errorf("gosmpp: invalid message waiting type %d", kind))
+		return b
+	}
+	if count < 0 || count > maxNumberMessages {
+		b.fail(fmt.Errorf("gosmpp: invalid number of waiting messages %d", count))
+		return b
+	}
+	b.mwi = &messageWaiting{kind: kind, active: active, count: count}
+	return b
+}
+
+// Build returns parts of the message, with addresses normalized by DefaultAddressNormalizer.
+func (b *SubmitSMBuilder) Build() ([]*pdu.SubmitSM, error) {
+	submit, err := newSubmit(DefaultAddressNormalizer, b.from, b.to)
+	if err != nil {
+		return nil, err
+	}
+	return b.build(submit)
+}
+
+// build sets the message of submit and splits it.
+func (b *SubmitSMBuilder) build(submit *pdu.SubmitSM) ([]*pdu.SubmitSM, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	if b.messageClass != nil && b.mwi != nil {
+		return nil, ErrDataCodingConflict
+	}
+
+	if b.protocolID != nil {
+		submit.ProtocolID = *b.protocolID
+	}
+	if b.messageClass != nil && *b.messageClass == 0 && conflictsWithFlash(submit.ProtocolID) {
+		return nil, ErrFlashProtocolID
+	}
+
+	enc := textEncoding(b.text)
+	switch {
+	case b.messageClass != nil:
+		enc = dcsEncoding{Encoding: enc, coding: enc.DataCoding() | dcsMessageClass | *b.messageClass}
+
+	case b.mwi != nil:
+		coding := byte(dcsMWIDiscard)
+		if b.text != "" {
+			coding = dcsMWIStoreGSM7
+			if enc == data.UCS2 {
+				coding = dcsMWIStoreUCS2
+			}
+		}
+		facilities := byte(b.mwi.kind)
+		if b.mwi.active {
+			coding |= dcsMWIActive
+			facilities |= mwiActive
+		}
+		enc = dcsEncoding{Encoding: enc, coding: coding | byte(b.mwi.kind)}
+
+		submit.RegisterOptionalParam(pdu.Field{Tag: pdu.TagMsMsgWaitFacilities, Data: []byte{facilities}})
+		submit.RegisterOptionalParam(pdu.Field{Tag: pdu.TagNumberOfMessages, Data: []byte{byte(b.mwi.count)}})
+	}
+
+	if err := submit.Message.SetLongMessageWithEnc(b.text, enc); err != nil {
+		return nil, err
+	}
+	return submit.Split()
+}
+
+func (b *SubmitSMBuilder) fail(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
+
+// Send sends message built by b. Messenger settings apply to parts, unless overridden by b.
+func (m *Messenger) Send(ctx context.Context, b *SubmitSMBuilder) (*MessageHandle, error) {
+	submit, err := m.newSubmit(b.from, b.to)
+	if err != nil {
+		return nil, err
+	}
+
+	parts, err := b.build(submit)
+	if err != nil {
+		return nil, err
+	}
+	return m.send(ctx, parts)
+}
+
+// dcsEncoding is encoding with data coding scheme other than of its alphabet, e.g. with message class.
+type dcsEncoding struct {
+	data.Encoding
+	coding byte
+}
+
+func (e dcsEncoding) DataCoding() byte {
+	return e.coding
+}
+
+func (e dcsEncoding) ShouldSplit(text string, octetLimit uint) bool {
+	return e.Encoding.(data.Splitter).ShouldSplit(text, octetLimit)
+}
+
+func (e dcsEncoding) EncodeSplit(text string, octetLimit uint) ([][]byte, error) {
+	return e.Encoding.(data.Splitter).EncodeSplit(text, octetLimit)
+}
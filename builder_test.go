@@ -0,0 +1,115 @@
+package gosmpp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+
+	"github.com/stretchr/testify/require"
+)
+
+// dataCodingOf returns data_coding of marshalled submit.
+func dataCodingOf(p *pdu.SubmitSM) byte {
+	b := pdu.NewBuffer(nil)
+	p.Message.Marshal(b)
+	return b.Bytes()[0]
+}
+
+func TestSubmitSMBuilderMessageClass(t *testing.T) {
+	parts, err := NewSubmitSMBuilder().From("MyBank").To("+84901234567").Text("hello").SetMessageClass(1).Build()
+	require.Nil(t, err)
+	require.Len(t, parts, 1)
+	require.Equal(t, byte(0x11), dataCodingOf(parts[0]))
+	require.Equal(t, "84901234567", parts[0].DestAddr.Address())
+	text, _ := parts[0].Message.GetMessage()
+	require.Equal(t, "hello", text)
+
+	parts, err = NewSubmitSMBuilder().From("MyBank").To("84901234567").Text(mess).SetMessageClass(2).Build()
+	require.Nil(t, err)
+	require.Equal(t, byte(0x1A), dataCodingOf(parts[0]))
+
+	_, err = NewSubmitSMBuilder().From("MyBank").To("84901234567").SetMessageClass(4).Build()
+	require.NotNil(t, err)
+}
+
+func TestSubmitSMBuilderMessageWaiting(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		kind       MessageWaitingType
+		active     bool
+		text       string
+		coding     byte
+		facilities byte
+	}{
+		{"discard voicemail on", MessageWaitingVoicemail, true, "", 0xC8, 0x80},
+		{"discard fax off", MessageWaitingFax, false, "", 0xC1, 0x01},
+		{"store email on", MessageWaitingEmail, true, "You have mail", 0xDA, 0x82},
+		{"store ucs2 other", MessageWaitingOther, true, mess, 0xEB, 0x83},
+	} {
+		parts, err := NewSubmitSMBuilder().From("123").To("84901234567").Text(tc.text).
+			SetMessageWaiting(tc.kind, tc.active, 3).Build()
+		require.Nil(t, err, tc.name)
+		require.Equal(t, tc.coding, dataCodingOf(parts[0]), tc.name)
+
+		params := parts[0].OptionalParameters
+		require.Equal(t, []byte{tc.facilities}, params[pdu.TagMsMsgWaitFacilities].Data, tc.name)
+		require.Equal(t, []byte{3}, params[pdu.TagNumberOfMessages].Data, tc.name)
+	}
+
+	_, err := NewSubmitSMBuilder().From("123").To("84901234567").SetMessageWaiting(MessageWaitingVoicemail, true, 100).Build()
+	require.NotNil(t, err)
+	_, err = NewSubmitSMBuilder().From("123").To("84901234567").SetMessageWaiting(4, true, 1).Build()
+	require.NotNil(t, err)
+
+	_, err = NewSubmitSMBuilder().From("123").To("84901234567").
+		SetMessageClass(0).SetMessageWaiting(MessageWaitingVoicemail, true, 1).Build()
+	require.Equal(t, ErrDataCodingConflict, err)
+}
+
+func TestMessengerSend(t *testing.T) {
+	auth := nextAuth()
+	s, err := NewSession(TXConnector(NonTLSDialer, auth), Settings{ReadTimeout: 2 * time.Second}, -1)
+	require.Nil(t, err)
+	defer func() {
+		_ = s.Close()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	m := NewMessenger(s, WithRegisteredDelivery(data.SM_SMSC_RECEIPT_REQUESTED))
+	h, err := m.Send(ctx, NewSubmitSMBuilder().From("123").To("84901234567").
+		SetMessageWaiting(MessageWaitingVoicemail, true, 2))
+	require.Nil(t, err)
+	require.Len(t, h.Parts, 1)
+	require.NotEmpty(t, h.MessageIDs[0])
+	require.Equal(t, data.SM_SMSC_RECEIPT_REQUESTED, h.Parts[0].RegisteredDelivery)
+
+	_, err = m.Send(ctx, NewSubmitSMBuilder().To("84901234567"))
+	require.NotNil(t, err)
+}
+
+func TestSubmitSMBuilderProtocolID(t *testing.T) {
+	parts, err := NewSubmitSMBuilder().From("MyBank").To("84901234567").Text("OTP 1234").ReplaceIfPresent(3).Build()
+	require.Nil(t, err)
+	require.Equal(t, data.PID_REPLACE_SHORT_MESSAGE_TYPE_3, parts[0].ProtocolID)
+
+	parts, err = NewSubmitSMBuilder().From("MyBank").To("84901234567").ProtocolID(data.PID_RETURN_CALL_MESSAGE).Build()
+	require.Nil(t, err)
+	require.Equal(t, data.PID_RETURN_CALL_MESSAGE, parts[0].ProtocolID)
+
+	for _, replaceType := range []byte{0, 8} {
+		_, err = NewSubmitSMBuilder().From("MyBank").To("84901234567").ReplaceIfPresent(replaceType).Build()
+		require.NotNil(t, err)
+	}
+
+	_, err = NewSubmitSMBuilder().From("MyBank").To("84901234567").ReplaceIfPresent(1).SetMessageClass(0).Build()
+	require.Equal(t, ErrFlashProtocolID, err)
+
+	parts, err = NewSubmitSMBuilder().From("MyBank").To("84901234567").ReplaceIfPresent(1).SetMessageClass(1).Build()
+	require.Nil(t, err)
+	require.Equal(t, data.PID_REPLACE_SHORT_MESSAGE_TYPE_1, parts[0].ProtocolID)
+}
@@ -0,0 +1,320 @@
+package gosmpp
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/linxGnu/gosmpp/clock"
+	"github.com/linxGnu/gosmpp/pdu"
+)
+
+var (
+	// ErrResponseLost indicates bind was closed before response of the request was received.
+	ErrResponseLost = errors.New("bind closed before response is received")
+
+	// ErrCallCanceled indicates call was canceled by Call.Cancel.
+	ErrCallCanceled = errors.New("call canceled")
+
+	// ErrResponseTimeout indicates response of the call was not received within its timeout.
+	ErrResponseTimeout = errors.New("response timeout")
+)
+
+const (
+	callPending int32 = iota
+	callWritten
+	callCanceled
+)
+
+// Call is request submitted by Session.SubmitAsync, completed with its response or error.
+type Call struct {
+	// PDU is the submitted request.
+	PDU pdu.PDU
+
+	// Correlation is caller data attached by WithCorrelation.
+	Correlation Correlation
+
+	once     sync.Once
+	done     chan struct{}
+	response pdu.PDU
+	err      error
+
+	state    int32
+	registry *callRegistry
+
+	// ctx bounds waiting for slot of submit window
+	ctx context.Context
+
+	// inner is the call of current attempt, for calls submitted by ordering
+	mu    sync.Mutex
+	inner *Call
+	timer clock.Timer
+}
+
+// CallOption configures single call of Session.SubmitAsync.
+type CallOption func(*callOptions)
+
+type callOptions struct {
+	timeout     time.Duration
+	correlation Correlation
+	ctx         context.Context
+}
+
+// withContext bounds waiting of the call for slot of submit window by ctx, for callers waiting on ctx anyway.
+func withContext(ctx context.Context) CallOption {
+	return func(o *callOptions) {
+		o.ctx = ctx
+	}
+}
+
+// WithCallTimeout sets response deadline of the call, overriding session default set by WithResponseTimeout.
+//
+// Non-positive value disables the timeout for the call.
+func WithCallTimeout(d time.Duration) CallOption {
+	return func(o *callOptions) {
+		o.timeout = d
+	}
+}
+
+// WithResponseTimeout sets default response deadline of calls submitted by SubmitAsync, counted from submitting.
+// Call which is not responded in time fails with ErrResponseTimeout and its late response is dropped.
+//
+// Default is no timeout: call waits until responded or bind is closed.
+func WithResponseTimeout(d time.Duration) SessionOption {
+	return func(s *Session) {
+		s.responseTimeout = d
+	}
+}
+
+func newCall(p pdu.PDU) *Call {
+	return &Call{
+		PDU:  p,
+		ctx:  context.Background(),
+		done: make(chan struct{}),
+	}
+}
+
+func (c *Call) finish(resp pdu.PDU, err error) {
+	c.once.Do(func() {
+		c.mu.Lock()
+		if c.timer != nil {
+			c.timer.Stop()
+		}
+		c.mu.Unlock()
+
+		c.response, c.err = resp, err
+		close(c.done)
+	})
+}
+
+// Done returns channel which is closed when the call is completed.
+func (c *Call) Done() <-chan struct{} {
+	return c.done
+}
+
+// Result returns response and error of completed call.
+// Response is nil for requests which have no response, e.g. generic_nack.
+//
+// Result must be called after Done is closed, otherwise it returns nil values.
+func (c *Call) Result() (pdu.PDU, error) {
+	select {
+	case <-c.done:
+		return c.response, c.err
+	default:
+		return nil, nil
+	}
+}
+
+// Written tells whether request has reached the wire, i.e. it was handed to the connection for writing.
+func (c *Call) Written() bool {
+	c.mu.Lock()
+	inner := c.inner
+	c.mu.Unlock()
+
+	if inner != nil {
+		return inner.Written()
+	}
+	return atomic.LoadInt32(&c.state) == callWritten
+}
+
+// Cancel cancels the call, it completes with ErrCallCanceled unless it is already completed.
+//
+// Request which is not written yet is removed from the queue and will not be sent. Otherwise its
+// eventual response is dropped. Returned value tells whether the request had reached the wire.
+func (c *Call) Cancel() (written bool) {
+	return c.abort(ErrCallCanceled)
+}
+
+// abort completes the call with err, request is not written anymore and its response is dropped.
+func (c *Call) abort(err error) (written bool) {
+	c.mu.Lock()
+	inner := c.inner
+	atomic.CompareAndSwapInt32(&c.state, callPending, callCanceled)
+	c.mu.Unlock()
+
+	if inner != nil {
+		written = inner.abort(err)
+	} else if atomic.LoadInt32(&c.state) == callWritten {
+		written = true
+		c.registry.remove(c)
+	}
+
+	c.finish(nil, err)
+	return
+}
+
+// Wait blocks until the call is completed or ctx is done.
+func (c *Call) Wait(ctx context.Context) (pdu.PDU, error) {
+	select {
+	case <-c.done:
+		return c.response, c.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// callRegistry correlates responses with calls waiting for them by sequence number.
+//
+// All methods are safe to call on nil receiver.
+type callRegistry struct {
+	calls seqMap[*Call]
+}
+
+func (r *callRegistry) add(c *Call) {
+	c.registry = r
+	r.calls.store(c.PDU.GetSequenceNumber(), c)
+}
+
+// take removes and returns call waiting for given sequence number.
+func (r *callRegistry) take(seq int32) (c *Call) {
+	if r == nil {
+		return nil
+	}
+	c, _ = r.calls.take(seq)
+	return
+}
+
+// remove removes call, its response will be dropped.
+func (r *callRegistry) remove(c *Call) {
+	if r == nil {
+		return
+	}
+	r.calls.takeIf(c.PDU.GetSequenceNumber(), func(v *Call) bool { return v == c })
+}
+
+// claim marks call of request p as written. It returns false if the call is canceled,
+// then p must not be written.
+func (r *callRegistry) claim(p pdu.PDU) bool {
+	if r == nil {
+		return true
+	}
+
+	c, _ := r.calls.load(p.GetSequenceNumber())
+	if c == nil || c.PDU != p ||
+		atomic.CompareAndSwapInt32(&c.state, callPending, callWritten) ||
+		atomic.LoadInt32(&c.state) == callWritten {
+		return true
+	}
+
+	// canceled before written
+	r.remove(c)
+	return false
+}
+
+// resolve completes call waiting for response p, returning its correlation.
+func (r *callRegistry) resolve(p pdu.PDU) Correlation {
+	c := r.take(p.GetSequenceNumber())
+	if c == nil {
+		return Correlation{}
+	}
+	c.finish(p, nil)
+	return c.Correlation
+}
+
+// fail completes call of request p with error.
+func (r *callRegistry) fail(p pdu.PDU, err error) {
+	if r == nil || p == nil {
+		return
+	}
+
+	if c, ok := r.calls.takeIf(p.GetSequenceNumber(), func(c *Call) bool { return c.PDU == p }); ok {
+		c.finish(nil, err)
+	}
+}
+
+// failAll completes all waiting calls with error.
+func (r *callRegistry) failAll(err error) {
+	for _, c := range r.calls.drain() {
+		c.finish(nil, err)
+	}
+}
+
+// SubmitAsync submits a PDU like Submit and returns Call completed with the response from SMSC.
+//
+// Call fails with the submitting error, or ErrResponseLost if bind is closed before the response is received.
+// Requests having no response are completed once submitted.
+//
+// With WithDestinationOrdering, requests to the same destination are submitted one after another.
+//
+// SubmitAsync is safe for concurrent use, with ordering and fairness of Submit, and it waits like Submit while
+// the queue of the bind is full. Requests ordered by WithDestinationOrdering are queued per destination instead.
+func (s *Session) SubmitAsync(p pdu.PDU, opts ...CallOption) *Call {
+	o := callOptions{timeout: s.responseTimeout, ctx: context.Background()}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	c := newCall(p)
+	c.Correlation = o.correlation.clone()
+	c.ctx = o.ctx
+	if o.timeout > 0 {
+		c.mu.Lock()
+		c.timer = clock.OrReal(s.settings.Clock).AfterFunc(o.timeout, func() {
+			c.abort(ErrResponseTimeout)
+		})
+		c.mu.Unlock()
+	}
+
+	if s.ordering != nil {
+		if dest := destinationOf(p); dest != "" {
+			s.ordering.enqueue(s, dest, c)
+			return c
+		}
+	}
+
+	s.startCall(c)
+	return c
+}
+
+// SubmitAndWait submits p like SubmitAsync and waits for its response, e.g. submit_sm_resp, correlated by sequence
+// number. Response is returned whatever its command status, check it by IsOk.
+//
+// Call is canceled once ctx is done, ctx.Err() is returned then and late response is dropped. Other errors are
+// those of Call.
+func (s *Session) SubmitAndWait(ctx context.Context, p pdu.PDU, opts ...CallOption) (pdu.PDU, error) {
+	c := s.SubmitAsync(p, append([]CallOption{withContext(ctx)}, opts...)...)
+	resp, err := c.Wait(ctx)
+	if err != nil && ctx.Err() != nil {
+		c.Cancel()
+	}
+	return resp, err
+}
+
+func (s *Session) startCall(c *Call) {
+	p := c.PDU
+	if !p.CanResponse() {
+		err := s.submitContext(c.ctx, p)
+		if err == nil {
+			atomic.StoreInt32(&c.state, callWritten)
+		}
+		c.finish(nil, err)
+		return
+	}
+
+	s.calls.add(c)
+	if err := s.submitContext(c.ctx, p); err != nil {
+		s.calls.fail(p, err)
+	}
+}
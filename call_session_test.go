@@ -0,0 +1,52 @@
+package gosmpp_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp"
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+	"github.com/linxGnu/gosmpp/server/smsctest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionSubmitAndWait(t *testing.T) {
+	smsc := smsctest.NewPipeServer(nil)
+	defer smsc.Close()
+
+	s, err := gosmpp.NewSession(gosmpp.TRXConnector(smsc.Dialer(), gosmpp.Auth{SMSC: "pipe", SystemID: "esme"}),
+		gosmpp.Settings{ReadTimeout: 2 * time.Second}, -1)
+	require.Nil(t, err)
+	defer func() {
+		_ = s.Close()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	submit := newTextSubmitSM("1", "hello")
+	resp, err := s.SubmitAndWait(ctx, submit)
+	require.Nil(t, err)
+	require.IsType(t, &pdu.SubmitSMResp{}, resp)
+	require.Equal(t, submit.GetSequenceNumber(), resp.GetSequenceNumber())
+	require.NotEmpty(t, resp.(*pdu.SubmitSMResp).MessageID)
+
+	// rejection is response too
+	smsc.SetFault(smsctest.RespondStatus(data.ESME_RTHROTTLED))
+	resp, err = s.SubmitAndWait(ctx, newTextSubmitSM("1", "hello"))
+	require.Nil(t, err)
+	require.Equal(t, data.ESME_RTHROTTLED, resp.GetHeader().CommandStatus)
+
+	// not responded in time
+	smsc.SetFault(func(pdu.PDU) smsctest.Fault { return smsctest.Fault{Drop: true} })
+	short, cancelShort := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancelShort()
+	_, err = s.SubmitAndWait(short, newTextSubmitSM("1", "hello"))
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	_, err = s.SubmitAndWait(ctx, newTextSubmitSM("1", "hello"), gosmpp.WithCallTimeout(50*time.Millisecond))
+	require.ErrorIs(t, err, gosmpp.ErrResponseTimeout)
+}
@@ -0,0 +1,216 @@
+package gosmpp
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp/clock"
+	"github.com/linxGnu/gosmpp/pdu"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCallRegistry(t *testing.T) {
+	var r callRegistry
+
+	req := pdu.NewSubmitSM()
+	c := newCall(req)
+	resp, err := c.Result()
+	require.Nil(t, resp)
+	require.Nil(t, err)
+
+	r.add(c)
+	r.resolve(req.GetResponse())
+	<-c.Done()
+	resp, err = c.Result()
+	require.Nil(t, err)
+	require.Equal(t, req.GetSequenceNumber(), resp.GetSequenceNumber())
+
+	// failing other pdu with the same sequence number does not affect the call
+	other := newCall(req)
+	r.add(other)
+	copied := pdu.NewSubmitSM()
+	copied.SetSequenceNumber(req.GetSequenceNumber())
+	r.fail(copied, errors.New("other"))
+	require.Equal(t, 1, r.calls.len())
+
+	r.fail(req, ErrWindowsFull)
+	_, err = other.Wait(context.Background())
+	require.ErrorIs(t, err, ErrWindowsFull)
+
+	lost := newCall(pdu.NewSubmitSM())
+	r.add(lost)
+	r.failAll(ErrResponseLost)
+	_, err = lost.Wait(context.Background())
+	require.ErrorIs(t, err, ErrResponseLost)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = newCall(req).Wait(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+
+	// nil registry is no-op
+	var nilRegistry *callRegistry
+	nilRegistry.resolve(req)
+	nilRegistry.fail(req, ErrWindowsFull)
+}
+
+func TestSessionSubmitAsync(t *testing.T) {
+	auth := nextAuth()
+	s, err := NewSession(
+		TRXConnector(NonTLSDialer, auth),
+		Settings{
+			ReadTimeout: 2 * time.Second,
+		}, -1)
+	require.Nil(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	submit := newSubmitSM(auth.SystemID)
+	resp, err := s.SubmitAsync(submit).Wait(ctx)
+	require.Nil(t, err)
+	require.IsType(t, &pdu.SubmitSMResp{}, resp)
+	require.Equal(t, submit.GetSequenceNumber(), resp.GetSequenceNumber())
+
+	// no response expected
+	resp, err = s.SubmitAsync(pdu.NewGenericNack()).Wait(ctx)
+	require.Nil(t, err)
+	require.Nil(t, resp)
+
+	require.Nil(t, s.Close())
+	_, err = s.SubmitAsync(newSubmitSM(auth.SystemID)).Wait(ctx)
+	require.ErrorIs(t, err, ErrConnectionClosing)
+}
+
+func TestCallCancel(t *testing.T) {
+	var r callRegistry
+
+	t.Run("beforeWritten", func(t *testing.T) {
+		req := pdu.NewSubmitSM()
+		c := newCall(req)
+		r.add(c)
+
+		require.False(t, c.Cancel())
+		_, err := c.Wait(context.Background())
+		require.ErrorIs(t, err, ErrCallCanceled)
+
+		// writer skips canceled request
+		require.False(t, r.claim(req))
+		require.False(t, c.Written())
+		require.Zero(t, r.calls.len())
+	})
+
+	t.Run("afterWritten", func(t *testing.T) {
+		req := pdu.NewSubmitSM()
+		c := newCall(req)
+		r.add(c)
+
+		require.True(t, r.claim(req))
+		require.True(t, r.claim(req))
+		require.True(t, c.Written())
+
+		require.True(t, c.Cancel())
+		require.Zero(t, r.calls.len())
+
+		// late response is dropped
+		r.resolve(req.GetResponse())
+		_, err := c.Wait(context.Background())
+		require.ErrorIs(t, err, ErrCallCanceled)
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		require.True(t, r.claim(pdu.NewEnquireLink()))
+	})
+}
+
+func TestSessionCancel(t *testing.T) {
+	auth := nextAuth()
+	s, err := NewSession(
+		TRXConnector(NonTLSDialer, auth),
+		Settings{
+			ReadTimeout: 2 * time.Second,
+		}, -1, WithDestinationOrdering(100, 20*time.Millisecond))
+	require.Nil(t, err)
+	defer func() {
+		_ = s.Close()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	// completed call
+	c := s.SubmitAsync(newSubmitSM(auth.SystemID))
+	_, err = c.Wait(ctx)
+	require.Nil(t, err)
+	require.True(t, c.Cancel())
+	_, err = c.Result()
+	require.Nil(t, err)
+
+	// ordered calls are held while bind is lost
+	require.Nil(t, s.bound().Close())
+	first := s.SubmitAsync(newSubmitSM(auth.SystemID))
+	second := s.SubmitAsync(newSubmitSM(auth.SystemID))
+
+	require.False(t, second.Cancel())
+	require.False(t, first.Cancel())
+
+	for _, c := range []*Call{first, second} {
+		_, err = c.Wait(ctx)
+		require.ErrorIs(t, err, ErrCallCanceled)
+	}
+
+	require.Eventually(t, func() bool {
+		s.ordering.mu.Lock()
+		defer s.ordering.mu.Unlock()
+		return len(s.ordering.queues) == 0
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestSessionCallTimeout(t *testing.T) {
+	auth := nextAuth()
+	clk := clock.NewFake(time.Now())
+	s, err := NewSession(
+		TRXConnector(NonTLSDialer, auth),
+		Settings{
+			ReadTimeout: 2 * time.Second,
+			Clock:       clk,
+		}, -1, WithResponseTimeout(time.Minute), WithDestinationOrdering(100, time.Second))
+	require.Nil(t, err)
+	defer func() {
+		_ = s.Close()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	// responded in time, timer is stopped
+	_, err = s.SubmitAsync(newSubmitSM(auth.SystemID)).Wait(ctx)
+	require.Nil(t, err)
+	require.Zero(t, clk.Waiters())
+
+	// held by retrying while bind is lost
+	require.Nil(t, s.bound().Close())
+	short := s.SubmitAsync(newSubmitSM(auth.SystemID), WithCallTimeout(2*time.Second))
+	long := s.SubmitAsync(newSubmitSM(auth.SystemID))
+
+	// two call timers and retry interval
+	clk.BlockUntil(3)
+	clk.Advance(2 * time.Second)
+
+	_, err = short.Wait(ctx)
+	require.ErrorIs(t, err, ErrResponseTimeout)
+	require.False(t, short.Written())
+
+	select {
+	case <-long.Done():
+		t.Fatal("call with session default timeout must not be completed yet")
+	default:
+	}
+
+	clk.Advance(time.Minute)
+	_, err = long.Wait(ctx)
+	require.ErrorIs(t, err, ErrResponseTimeout)
+}
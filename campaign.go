@@ -0,0 +1,287 @@
+package gosmpp
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/linxGnu/gosmpp/clock"
+)
+
+// DefaultCampaignConcurrency is default number of campaign messages waiting for responses at once.
+const DefaultCampaignConcurrency = 8
+
+// ErrCampaignAborted indicates campaign is stopped by Abort.
+var ErrCampaignAborted = errors.New("campaign aborted")
+
+// Destinations iterates over destination addresses of campaign, returning false once exhausted.
+// It is called from single goroutine.
+type Destinations func() (to string, ok bool)
+
+// DestinationList returns Destinations iterating over addresses.
+func DestinationList(addresses ...string) Destinations {
+	return func() (string, bool) {
+		if len(addresses) == 0 {
+			return "", false
+		}
+		to := addresses[0]
+		addresses = addresses[1:]
+		return to, true
+	}
+}
+
+// CampaignProgress is progress of campaign.
+type CampaignProgress struct {
+	// Submitted is number of messages handed to SMSC.
+	Submitted int
+
+	// Accepted is number of messages with all parts accepted by SMSC.
+	Accepted int
+
+	// Failed is number of messages failed to be built or rejected by SMSC.
+	Failed int
+}
+
+// Campaign sends the same message to many destinations.
+//
+// Messages are sent by Messenger with bounded concurrency, optionally limited in rate. Sending waits while
+// session is saturated, e.g. its request window is full, see Session.Saturated.
+type Campaign struct {
+	messenger    *Messenger
+	from, text   string
+	destinations Destinations
+
+	concurrency int
+	rate        int
+	onProgress  func(CampaignProgress)
+	onResult    func(to string, h *MessageHandle, err error)
+	clock       clock.Clock
+
+	mu       sync.Mutex
+	progress CampaignProgress
+	paused   chan struct{} // closed on resume, nil if not paused
+	aborted  chan struct{}
+	abort    sync.Once
+
+	reportMu sync.Mutex
+}
+
+// CampaignOption configures Campaign.
+type CampaignOption func(*Campaign)
+
+// WithCampaignConcurrency sets number of messages waiting for responses at once, DefaultCampaignConcurrency by default.
+func WithCampaignConcurrency(n int) CampaignOption {
+	return func(c *Campaign) {
+		if n > 0 {
+			c.concurrency = n
+		}
+	}
+}
+
+// WithCampaignRate limits number of messages sent per second, e.g. to the rate agreed with SMSC. Unlimited by default.
+func WithCampaignRate(perSecond int) CampaignOption {
+	return func(c *Campaign) {
+		c.rate = perSecond
+	}
+}
+
+// WithCampaignProgress sets function called with progress after each message is accepted or failed.
+// Calls are serialized.
+func WithCampaignProgress(onProgress func(CampaignProgress)) CampaignOption {
+	return func(c *Campaign) {
+		c.onProgress = onProgress
+	}
+}
+
+// WithCampaignResult sets function called with result of each message: its handle, if built, and error.
+// Calls are serialized, each before reporting progress.
+func WithCampaignResult(onResult func(to string, h *MessageHandle, err error)) CampaignOption {
+	return func(c *Campaign) {
+		c.onResult = onResult
+	}
+}
+
+// NewCampaign returns campaign sending text from source address to destinations by messenger.
+func NewCampaign(messenger *Messenger, from, text string, destinations Destinations, opts ...CampaignOption) *Campaign {
+	c := &Campaign{
+		messenger:    messenger,
+		from:         from,
+		text:         text,
+		destinations: destinations,
+		concurrency:  DefaultCampaignConcurrency,
+		clock:        messenger.clock,
+		aborted:      make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Run sends message to all destinations and waits for their responses. It should be called once.
+//
+// Run returns ErrCampaignAborted if campaign is aborted, ctx error if ctx is done, with progress at that point.
+// Messages handed to SMSC before are still waited for.
+func (c *Campaign) Run(ctx context.Context) (CampaignProgress, error) {
+	pressure, stop := c.messenger.session.Backpressure(1)
+	defer stop()
+
+	var tick <-chan time.Time
+	if c.rate > 0 {
+		ticker := c.clock.NewTicker(time.Second / time.Duration(c.rate))
+		defer ticker.Stop()
+		tick = ticker.C()
+	}
+
+	var (
+		wg    sync.WaitGroup
+		slots = make(chan struct{}, c.concurrency)
+		err   error
+	)
+	for err == nil {
+		to, ok := c.destinations()
+		if !ok {
+			break
+		}
+
+		if err = c.wait(ctx, slots, tick, pressure); err != nil {
+			break
+		}
+
+		c.update(func(p *CampaignProgress) { p.Submitted++ })
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+			c.send(ctx, to)
+		}()
+	}
+	wg.Wait()
+	return c.Progress(), err
+}
+
+// wait waits until campaign is not paused, concurrency and rate allow sending and session is not saturated.
+func (c *Campaign) wait(ctx context.Context, slots chan struct{}, tick <-chan time.Time, pressure <-chan bool) error {
+	for {
+		c.mu.Lock()
+		paused := c.paused
+		c.mu.Unlock()
+		if paused == nil {
+			break
+		}
+
+		select {
+		case <-paused:
+		case <-c.aborted:
+			return ErrCampaignAborted
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	select {
+	case slots <- struct{}{}:
+	case <-c.aborted:
+		return ErrCampaignAborted
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	release := func(err error) error {
+		<-slots
+		return err
+	}
+	if tick != nil {
+		select {
+		case <-tick:
+		case <-c.aborted:
+			return release(ErrCampaignAborted)
+		case <-ctx.Done():
+			return release(ctx.Err())
+		}
+	}
+
+	// every signal is a reason to check again, missed ones leave the channel full
+	for c.messenger.session.Saturated() {
+		select {
+		case <-pressure:
+		case <-c.aborted:
+			return release(ErrCampaignAborted)
+		case <-ctx.Done():
+			return release(ctx.Err())
+		}
+	}
+	return nil
+}
+
+func (c *Campaign) send(ctx context.Context, to string) {
+	h, err := c.messenger.SendText(ctx, c.from, to, c.text)
+
+	// reported progress only grows
+	c.reportMu.Lock()
+	defer c.reportMu.Unlock()
+
+	p := c.update(func(p *CampaignProgress) {
+		if err != nil {
+			p.Failed++
+		} else {
+			p.Accepted++
+		}
+	})
+	if c.onResult != nil {
+		c.onResult(to, h, err)
+	}
+	if c.onProgress != nil {
+		c.onProgress(p)
+	}
+}
+
+func (c *Campaign) update(fn func(p *CampaignProgress)) CampaignProgress {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	fn(&c.progress)
+	return c.progress
+}
+
+// Pause pauses sending, messages handed to SMSC are still waited for.
+func (c *Campaign) Pause() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.paused == nil {
+		c.paused = make(chan struct{})
+	}
+}
+
+// Resume resumes paused sending.
+func (c *Campaign) Resume() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.paused != nil {
+		close(c.paused)
+		c.paused = nil
+	}
+}
+
+// Abort stops sending, Run returns ErrCampaignAborted once messages handed to SMSC are waited for.
+func (c *Campaign) Abort() {
+	c.abort.Do(func() {
+		close(c.aborted)
+	})
+}
+
+// Paused reports whether campaign is paused.
+func (c *Campaign) Paused() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.paused != nil
+}
+
+// Progress returns current progress.
+func (c *Campaign) Progress() CampaignProgress {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.progress
+}
@@ -0,0 +1,108 @@
+package gosmpp
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestMessenger(t *testing.T, opts ...MessengerOption) *Messenger {
+	s, err := NewSession(TRXConnector(NonTLSDialer, nextAuth()), Settings{ReadTimeout: 2 * time.Second}, -1)
+	require.Nil(t, err)
+	t.Cleanup(func() {
+		_ = s.Close()
+	})
+	return NewMessenger(s, opts...)
+}
+
+func campaignDestinations(n int) []string {
+	to := make([]string, n)
+	for i := range to {
+		to[i] = fmt.Sprintf("+8490123%04d", i)
+	}
+	return to
+}
+
+func TestCampaignRun(t *testing.T) {
+	var (
+		reports []CampaignProgress
+		failed  []string
+	)
+	c := NewCampaign(newTestMessenger(t), "MyBank", "Sale starts today",
+		DestinationList(append(campaignDestinations(20), "")...),
+		WithCampaignConcurrency(4),
+		WithCampaignProgress(func(p CampaignProgress) {
+			reports = append(reports, p)
+		}),
+		WithCampaignResult(func(to string, h *MessageHandle, err error) {
+			if err != nil {
+				failed = append(failed, to)
+			}
+		}),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	p, err := c.Run(ctx)
+	require.Nil(t, err)
+	require.Equal(t, CampaignProgress{Submitted: 21, Accepted: 20, Failed: 1}, p)
+	require.Len(t, reports, 21)
+	require.Equal(t, p, reports[20])
+	require.Equal(t, []string{""}, failed)
+}
+
+func TestCampaignPauseAbort(t *testing.T) {
+	c := NewCampaign(newTestMessenger(t), "MyBank", "Sale starts today", DestinationList(campaignDestinations(5)...))
+	c.Pause()
+	require.True(t, c.Paused())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := c.Run(ctx)
+		done <- err
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+	require.Equal(t, CampaignProgress{}, c.Progress())
+
+	c.Resume()
+	require.False(t, c.Paused())
+	require.Nil(t, <-done)
+	require.Equal(t, 5, c.Progress().Accepted)
+
+	c = NewCampaign(newTestMessenger(t), "MyBank", "Sale starts today", DestinationList(campaignDestinations(5)...))
+	c.Pause()
+	go func() {
+		_, err := c.Run(ctx)
+		done <- err
+	}()
+	c.Abort()
+	require.Equal(t, ErrCampaignAborted, <-done)
+	require.Equal(t, CampaignProgress{}, c.Progress())
+}
+
+func TestCampaignRate(t *testing.T) {
+	c := NewCampaign(newTestMessenger(t), "MyBank", "Sale starts today", DestinationList(campaignDestinations(5)...),
+		WithCampaignRate(20))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	p, err := c.Run(ctx)
+	require.Nil(t, err)
+	require.Equal(t, 5, p.Accepted)
+	require.GreaterOrEqual(t, time.Since(start), 200*time.Millisecond)
+
+	// canceled context stops the campaign
+	cancel()
+	_, err = NewCampaign(newTestMessenger(t), "MyBank", "Sale", DestinationList(campaignDestinations(5)...)).Run(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,87 @@
+// Package clock abstracts time for gosmpp timers (enquire_link, response timeouts,
+// rebinding backoff, reassembly TTL), so that code built on gosmpp could be tested
+// deterministically with Fake clock instead of real sleeps.
+package clock
+
+import "time"
+
+// Clock provides current time and timers.
+type Clock interface {
+	// Now returns current time.
+	Now() time.Time
+
+	// Since returns time elapsed since t.
+	Since(t time.Time) time.Duration
+
+	// Sleep pauses current goroutine for at least duration d.
+	Sleep(d time.Duration)
+
+	// After waits for the duration to elapse and then sends the current time on the returned channel.
+	After(d time.Duration) <-chan time.Time
+
+	// NewTimer creates a new Timer which fires after duration d.
+	NewTimer(d time.Duration) Timer
+
+	// NewTicker returns a new Ticker which ticks with period d.
+	NewTicker(d time.Duration) Ticker
+
+	// AfterFunc waits for the duration to elapse and then calls f in its own goroutine.
+	// Returned Timer could be used to cancel the call, its channel is not used.
+	AfterFunc(d time.Duration, f func()) Timer
+}
+
+// Timer represents single event, like time.Timer.
+type Timer interface {
+	// C returns channel on which the time is delivered.
+	C() <-chan time.Time
+
+	// Stop prevents the Timer from firing.
+	Stop() bool
+
+	// Reset changes the timer to expire after duration d.
+	Reset(d time.Duration) bool
+}
+
+// Ticker delivers ticks at intervals, like time.Ticker.
+type Ticker interface {
+	// C returns channel on which the ticks are delivered.
+	C() <-chan time.Time
+
+	// Stop turns off the ticker.
+	Stop()
+}
+
+// Real is Clock backed by package time.
+var Real Clock = realClock{}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) Since(t time.Time) time.Duration        { return time.Since(t) }
+func (realClock) Sleep(d time.Duration)                  { time.Sleep(d) }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+func (realClock) NewTimer(d time.Duration) Timer         { return realTimer{time.NewTimer(d)} }
+func (realClock) NewTicker(d time.Duration) Ticker       { return realTicker{time.NewTicker(d)} }
+func (realClock) AfterFunc(d time.Duration, f func()) Timer {
+	return realTimer{time.AfterFunc(d, f)}
+}
+
+type realTimer struct {
+	*time.Timer
+}
+
+func (t realTimer) C() <-chan time.Time { return t.Timer.C }
+
+type realTicker struct {
+	*time.Ticker
+}
+
+func (t realTicker) C() <-chan time.Time { return t.Ticker.C }
+
+// OrReal returns c, or Real if c is nil.
+func OrReal(c Clock) Clock {
+	if c == nil {
+		return Real
+	}
+	return c
+}
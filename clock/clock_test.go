@@ -0,0 +1,114 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReal(t *testing.T) {
+	require.Equal(t, Real, OrReal(nil))
+
+	start := Real.Now()
+	Real.Sleep(time.Millisecond)
+	require.GreaterOrEqual(t, Real.Since(start), time.Millisecond)
+
+	timer := Real.NewTimer(time.Millisecond)
+	<-timer.C()
+	require.False(t, timer.Stop())
+
+	ticker := Real.NewTicker(time.Millisecond)
+	<-ticker.C()
+	ticker.Stop()
+
+	<-Real.After(time.Millisecond)
+
+	fired := make(chan struct{})
+	Real.AfterFunc(time.Millisecond, func() { close(fired) })
+	<-fired
+}
+
+func TestFake(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("now", func(t *testing.T) {
+		f := NewFake(start)
+		require.Equal(t, f, OrReal(f))
+		require.Equal(t, start, f.Now())
+
+		f.Advance(time.Hour)
+		require.Equal(t, time.Hour, f.Since(start))
+
+		// backward only changes time
+		f.Set(start)
+		require.Equal(t, start, f.Now())
+	})
+
+	t.Run("timer", func(t *testing.T) {
+		f := NewFake(start)
+		timer := f.NewTimer(time.Second)
+
+		f.Advance(999 * time.Millisecond)
+		select {
+		case <-timer.C():
+			t.Fatal("timer fired too early")
+		default:
+		}
+
+		f.Advance(time.Millisecond)
+		require.Equal(t, start.Add(time.Second), <-timer.C())
+		require.False(t, timer.Stop())
+
+		require.False(t, timer.Reset(time.Second))
+		require.True(t, timer.Stop())
+		f.Advance(time.Minute)
+		require.Zero(t, f.Waiters())
+
+		timer.Reset(0)
+		<-timer.C()
+	})
+
+	t.Run("ticker", func(t *testing.T) {
+		f := NewFake(start)
+		ticker := f.NewTicker(time.Second)
+		require.Panics(t, func() { f.NewTicker(0) })
+
+		f.Advance(time.Second)
+		require.Equal(t, start.Add(time.Second), <-ticker.C())
+
+		// slow receiver: ticks are dropped
+		f.Advance(5 * time.Second)
+		require.Equal(t, start.Add(2*time.Second), <-ticker.C())
+		require.Equal(t, start.Add(6*time.Second), f.Now())
+
+		ticker.Stop()
+		require.Zero(t, f.Waiters())
+	})
+
+	t.Run("afterFunc", func(t *testing.T) {
+		f := NewFake(start)
+
+		fired := make(chan struct{})
+		f.AfterFunc(time.Second, func() { close(fired) })
+		stopped := f.AfterFunc(time.Second, func() { t.Fatal("stopped timer fired") })
+		require.True(t, stopped.Stop())
+
+		f.Advance(time.Second)
+		<-fired
+	})
+
+	t.Run("sleep", func(t *testing.T) {
+		f := NewFake(start)
+
+		done := make(chan struct{})
+		go func() {
+			f.Sleep(time.Minute)
+			close(done)
+		}()
+
+		f.BlockUntil(1)
+		f.Advance(time.Minute)
+		<-done
+	})
+}
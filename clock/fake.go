@@ -0,0 +1,210 @@
+package clock
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Fake is manually advanced Clock for tests.
+//
+// Timers, tickers and sleepers only fire when Advance/Set moves the fake time past their deadline.
+type Fake struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	now     time.Time
+	waiters []*fakeWaiter
+}
+
+type fakeWaiter struct {
+	deadline time.Time
+	period   time.Duration // non-zero for ticker
+	ch       chan time.Time
+	f        func() // set for AfterFunc
+}
+
+// NewFake returns Fake clock starting at given time.
+func NewFake(now time.Time) *Fake {
+	f := &Fake{now: now}
+	f.cond = sync.NewCond(&f.mu)
+	return f
+}
+
+// Now returns current fake time.
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+// Since returns fake time elapsed since t.
+func (f *Fake) Since(t time.Time) time.Duration {
+	return f.Now().Sub(t)
+}
+
+// Sleep blocks until fake time is advanced by at least d.
+func (f *Fake) Sleep(d time.Duration) {
+	<-f.After(d)
+}
+
+// After returns channel receiving fake time once it is advanced by d.
+func (f *Fake) After(d time.Duration) <-chan time.Time {
+	return f.NewTimer(d).C()
+}
+
+// NewTimer returns Timer firing once fake time is advanced by d.
+func (f *Fake) NewTimer(d time.Duration) Timer {
+	t := &fakeTimer{f: f, w: &fakeWaiter{ch: make(chan time.Time, 1)}}
+	t.Reset(d)
+	return t
+}
+
+// AfterFunc calls f in its own goroutine once fake time is advanced by d.
+func (f *Fake) AfterFunc(d time.Duration, fn func()) Timer {
+	t := &fakeTimer{f: f, w: &fakeWaiter{ch: make(chan time.Time, 1), f: fn}}
+	t.Reset(d)
+	return t
+}
+
+// NewTicker returns Ticker ticking on every d of advanced fake time.
+func (f *Fake) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("non-positive interval for NewTicker")
+	}
+
+	w := &fakeWaiter{period: d, ch: make(chan time.Time, 1)}
+
+	f.mu.Lock()
+	w.deadline = f.now.Add(d)
+	f.add(w)
+	f.mu.Unlock()
+
+	return &fakeTicker{f: f, w: w}
+}
+
+// Advance moves fake time forward by d, firing all due timers and tickers in deadline order.
+func (f *Fake) Advance(d time.Duration) {
+	f.Set(f.Now().Add(d))
+}
+
+// Set moves fake time to t, firing all due timers and tickers in deadline order.
+// Moving backward only changes current time.
+func (f *Fake) Set(t time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for {
+		sort.Slice(f.waiters, func(i, j int) bool {
+			return f.waiters[i].deadline.Before(f.waiters[j].deadline)
+		})
+
+		if len(f.waiters) == 0 || f.waiters[0].deadline.After(t) {
+			break
+		}
+
+		w := f.waiters[0]
+		f.now = w.deadline
+
+		f.fire(w, w.deadline)
+
+		if w.period > 0 {
+			w.deadline = w.deadline.Add(w.period)
+		} else {
+			f.remove(w)
+		}
+	}
+
+	f.now = t
+}
+
+// Waiters returns number of pending timers, tickers and sleepers.
+func (f *Fake) Waiters() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.waiters)
+}
+
+// BlockUntil blocks until there are at least n pending timers, tickers and sleepers.
+//
+// Useful to make sure the code under test is waiting on the clock before calling Advance.
+func (f *Fake) BlockUntil(n int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for len(f.waiters) < n {
+		f.cond.Wait()
+	}
+}
+
+func (f *Fake) fire(w *fakeWaiter, now time.Time) {
+	if w.f != nil {
+		go w.f()
+		return
+	}
+
+	// like time.Ticker, drop tick if receiver is slow
+	select {
+	case w.ch <- now:
+	default:
+	}
+}
+
+func (f *Fake) add(w *fakeWaiter) {
+	f.waiters = append(f.waiters, w)
+	f.cond.Broadcast()
+}
+
+func (f *Fake) remove(w *fakeWaiter) (found bool) {
+	for i := range f.waiters {
+		if f.waiters[i] == w {
+			f.waiters = append(f.waiters[:i], f.waiters[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+type fakeTimer struct {
+	f *Fake
+	w *fakeWaiter
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.w.ch
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.f.mu.Lock()
+	defer t.f.mu.Unlock()
+	return t.f.remove(t.w)
+}
+
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	t.f.mu.Lock()
+	defer t.f.mu.Unlock()
+
+	active := t.f.remove(t.w)
+	t.w.deadline = t.f.now.Add(d)
+	if d <= 0 {
+		t.f.fire(t.w, t.f.now)
+		return active
+	}
+
+	t.f.add(t.w)
+	return active
+}
+
+type fakeTicker struct {
+	f *Fake
+	w *fakeWaiter
+}
+
+func (t *fakeTicker) C() <-chan time.Time {
+	return t.w.ch
+}
+
+func (t *fakeTicker) Stop() {
+	t.f.mu.Lock()
+	defer t.f.mu.Unlock()
+	t.f.remove(t.w)
+}
@@ -0,0 +1,72 @@
+// Command smpp-conformance checks SMSC against behaviors mandated by SMPP specification and prints pass/fail
+// report, exiting with status 1 if any check fails.
+//
+//	smpp-conformance -addr localhost:2775 -system-id 169994 -password EDXPJU
+//	smpp-conformance -addr localhost:2775 -system-id 169994 -password EDXPJU -checks enquire_link,unbind
+//
+// See package conformance for the checks.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/linxGnu/gosmpp/conformance"
+)
+
+func main() {
+	var (
+		addr       = flag.String("addr", "localhost:2775", "SMSC address")
+		systemID   = flag.String("system-id", "", "system_id of the binds")
+		password   = flag.String("password", "", "password of the binds")
+		systemType = flag.String("system-type", "", "system_type of the binds")
+		timeout    = flag.Duration("timeout", conformance.DefaultTimeout, "time to wait for every response")
+		interval   = flag.Duration("enquire-link", conformance.DefaultEnquireLinkInterval, "interval of enquire_link probes")
+		names      = flag.String("checks", "", "comma separated names of checks to run, all if empty")
+	)
+	flag.Parse()
+
+	checks, err := selectChecks(*names)
+	if err != nil {
+		log.Fatal("smpp-conformance: ", err)
+	}
+
+	report := conformance.Run(conformance.Target{
+		Addr:                *addr,
+		SystemID:            *systemID,
+		Password:            *password,
+		SystemType:          *systemType,
+		Timeout:             *timeout,
+		EnquireLinkInterval: *interval,
+	}, checks...)
+	fmt.Print(report)
+
+	if !report.Passed() {
+		os.Exit(1)
+	}
+}
+
+// selectChecks returns default checks of given names, all if names is empty.
+func selectChecks(names string) ([]conformance.Check, error) {
+	if names == "" {
+		return conformance.DefaultChecks, nil
+	}
+
+	var checks []conformance.Check
+	for _, name := range strings.Split(names, ",") {
+		found := false
+		for _, check := range conformance.DefaultChecks {
+			if check.Name == name {
+				checks, found = append(checks, check), true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown check %q", name)
+		}
+	}
+	return checks, nil
+}
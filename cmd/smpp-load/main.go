@@ -0,0 +1,128 @@
+// Command smpp-load generates load of messages against SMSC, e.g. the simulator, and prints throughput,
+// latency percentiles and distribution of errors.
+//
+//	smpp-load -addr localhost:2775 -system-id 169994 -password EDXPJU -sessions 4 -to +447700900123 -rate 500 -duration 1m
+//	smpp-load -config smpp.yaml -pool bulk -to +447700900123,+447700900124 -count 10000 -size 300 -encoding ucs2
+//
+// Messages are sent round robin by transmitter sessions bound with given credentials, or by pool of sessions
+// of configuration file, see package config. Sending stops on interrupt, and the report of messages sent so
+// far is printed.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/linxGnu/gosmpp"
+	"github.com/linxGnu/gosmpp/config"
+	"github.com/linxGnu/gosmpp/loadgen"
+)
+
+func main() {
+	var (
+		addr        = flag.String("addr", "localhost:2775", "SMSC address")
+		systemID    = flag.String("system-id", "", "system_id of the binds")
+		password    = flag.String("password", "", "password of the binds")
+		systemType  = flag.String("system-type", "", "system_type of the binds")
+		sessions    = flag.Int("sessions", 1, "number of transmitter sessions to bind")
+		configFile  = flag.String("config", "", "configuration file of sessions, instead of -addr and credentials")
+		pool        = flag.String("pool", "", "pool of sessions of the configuration file sending messages")
+		from        = flag.String("from", "LoadTest", "source address")
+		to          = flag.String("to", "", "comma separated destination addresses, cycled")
+		rate        = flag.Float64("rate", 0, "messages per second, unlimited if 0")
+		concurrency = flag.Int("concurrency", loadgen.DefaultConcurrency, "maximum number of messages in flight")
+		size        = flag.Int("size", loadgen.DefaultSize, "size of messages, in characters or bytes of binary")
+		encoding    = flag.String("encoding", string(loadgen.GSM7), "encoding of messages: gsm7, ucs2 or binary")
+		count       = flag.Int("count", 0, "number of messages to send, unlimited if 0")
+		duration    = flag.Duration("duration", 0, "time of sending messages, unlimited if 0")
+		timeout     = flag.Duration("timeout", loadgen.DefaultTimeout, "time to wait for responses of a message")
+	)
+	flag.Parse()
+
+	if *to == "" {
+		log.Fatal("smpp-load: -to is required")
+	}
+
+	var (
+		messengers []*gosmpp.Messenger
+		closeAll   func() error
+	)
+	if *configFile != "" {
+		c, err := config.Load(*configFile)
+		if err != nil {
+			log.Fatal("smpp-load: ", err)
+		}
+		opened, err := c.Open()
+		if err != nil {
+			log.Fatal("smpp-load: ", err)
+		}
+		if messengers = opened.Pools[*pool]; len(messengers) == 0 {
+			_ = opened.Close()
+			log.Fatalf("smpp-load: no pool %q in %s", *pool, *configFile)
+		}
+		closeAll = opened.Close
+	} else {
+		auth := gosmpp.Auth{SMSC: *addr, SystemID: *systemID, Password: *password, SystemType: *systemType}
+		opened := make([]*gosmpp.Session, 0, *sessions)
+		closeAll = func() error {
+			for _, s := range opened {
+				_ = s.Close()
+			}
+			return nil
+		}
+		for i := 0; i < *sessions; i++ {
+			session, err := gosmpp.NewSession(
+				gosmpp.TXConnector(gosmpp.NonTLSDialer, auth),
+				gosmpp.Settings{
+					EnquireLink: 5 * time.Second,
+
+					ReadTimeout: 10 * time.Second,
+
+					OnReceivingError: func(err error) {
+						fmt.Fprintln(os.Stderr, "Receiving PDU/Network error:", err)
+					},
+				}, -1)
+			if err != nil {
+				_ = closeAll()
+				log.Fatal("smpp-load: ", err)
+			}
+			opened = append(opened, session)
+			messengers = append(messengers, gosmpp.NewMessenger(session))
+		}
+	}
+	defer func() {
+		_ = closeAll()
+	}()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	g := &loadgen.Generator{
+		Messengers:  messengers,
+		From:        *from,
+		To:          strings.Split(*to, ","),
+		Rate:        *rate,
+		Concurrency: *concurrency,
+		Size:        *size,
+		Encoding:    loadgen.Encoding(*encoding),
+		Count:       *count,
+		Duration:    *duration,
+		Timeout:     *timeout,
+	}
+	if *count == 0 && *duration == 0 {
+		fmt.Fprintln(os.Stderr, "Sending until interrupted")
+	}
+
+	report, err := g.Run(ctx)
+	if err != nil {
+		log.Fatal("smpp-load: ", err)
+	}
+	fmt.Print(report)
+}
@@ -0,0 +1,102 @@
+// Command smpp-pcap prints SMPP PDUs of packet captures in pcap or pcapng format, one per line.
+//
+//	smpp-pcap capture.pcapng
+//	smpp-pcap -ports 2775,2776 -errors capture.pcap
+//	tcpdump -i eth0 -w - port 2775 | smpp-pcap -
+//	smpp-pcap -format wireshark capture.pcapng
+//
+// Each line has capture time, endpoints of the connection and the PDU. With -format wireshark, PDUs are
+// printed field per line in layout of Wireshark's packet details instead. Passwords of binds are masked.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/linxGnu/gosmpp/pcap"
+	"github.com/linxGnu/gosmpp/pdu"
+)
+
+func main() {
+	var (
+		ports  = flag.String("ports", strconv.Itoa(pcap.DefaultPort), "comma separated TCP ports of SMPP traffic")
+		errs   = flag.Bool("errors", false, "print streams which could not be decoded, e.g. of missing segments")
+		layout = flag.String("time", "2006-01-02 15:04:05.000000", "layout of capture time, see package time")
+		format = flag.String("format", "line", "format of PDUs: line or wireshark")
+	)
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "Usage: smpp-pcap [flags] file|-")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	filter, err := parsePorts(*ports)
+	if err != nil {
+		log.Fatal("smpp-pcap: ", err)
+	}
+
+	sprint := pdu.Sprint
+	switch *format {
+	case "line":
+	case "wireshark":
+		sprint = func(p pdu.PDU) string { return "\n" + pdu.Dissect(p) + "\n" }
+	default:
+		log.Fatalf("smpp-pcap: unknown format %q, expected line or wireshark", *format)
+	}
+
+	var r io.Reader = os.Stdin
+	if name := flag.Arg(0); name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			log.Fatal("smpp-pcap: ", err)
+		}
+		defer func() {
+			_ = f.Close()
+		}()
+		r = f
+	}
+
+	err = pcap.Decode(r, filter, func(rec pcap.Record) error {
+		if rec.Err != nil {
+			if *errs {
+				fmt.Fprintf(os.Stderr, "%s %v\n", formatTime(rec.Time, *layout), rec.Err)
+			}
+			return nil
+		}
+		_, err := fmt.Printf("%s %s > %s %s\n", formatTime(rec.Time, *layout), rec.Src, rec.Dst, sprint(rec.PDU))
+		return err
+	})
+	if err != nil {
+		log.Fatal("smpp-pcap: ", err)
+	}
+}
+
+func parsePorts(s string) ([]uint16, error) {
+	var ports []uint16
+	for _, field := range strings.Split(s, ",") {
+		port, err := strconv.ParseUint(strings.TrimSpace(field), 10, 16)
+		if err != nil {
+			return nil, fmt.Errorf("invalid port %q", field)
+		}
+		ports = append(ports, uint16(port))
+	}
+	return ports, nil
+}
+
+func formatTime(t time.Time, layout string) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format(layout)
+}
@@ -0,0 +1,101 @@
+// Command smpp-recv binds to SMSC as receiver and prints received mobile originated messages and delivery receipts.
+//
+//	smpp-recv -addr localhost:2775 -system-id 169994 -password EDXPJU
+//
+// Concatenated messages are printed once all parts are received, unless -parts is given.
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/linxGnu/gosmpp"
+)
+
+func main() {
+	var (
+		addr       = flag.String("addr", "localhost:2775", "SMSC address")
+		systemID   = flag.String("system-id", "", "system_id of the bind")
+		password   = flag.String("password", "", "password of the bind")
+		systemType = flag.String("system-type", "", "system_type of the bind")
+		parts      = flag.Bool("parts", false, "print parts of concatenated messages as received")
+	)
+	flag.Parse()
+
+	onMessage := printMessage
+	if !*parts {
+		reassembler := gosmpp.NewReassembler(printMessage)
+		onMessage = func(m gosmpp.IncomingMessage) {
+			if err := reassembler.HandleMessage(context.Background(), m); err != nil {
+				fmt.Fprintln(os.Stderr, "Reassembling error:", err)
+			}
+		}
+	}
+
+	session, err := gosmpp.NewSession(
+		gosmpp.RXConnector(gosmpp.NonTLSDialer, gosmpp.Auth{
+			SMSC:       *addr,
+			SystemID:   *systemID,
+			Password:   *password,
+			SystemType: *systemType,
+		}),
+		gosmpp.Settings{
+			EnquireLink: 5 * time.Second,
+
+			ReadTimeout: 10 * time.Second,
+
+			OnReceivingError: func(err error) {
+				fmt.Fprintln(os.Stderr, "Receiving PDU/Network error:", err)
+			},
+
+			OnRebindingError: func(err error) {
+				fmt.Fprintln(os.Stderr, "Rebinding but error:", err)
+			},
+
+			OnMessage: onMessage,
+
+			OnDeliveryReceipt: printReceipt,
+
+			OnClosed: func(state gosmpp.State) {
+				fmt.Fprintln(os.Stderr, state)
+			},
+		}, 5*time.Second)
+	if err != nil {
+		log.Fatal("smpp-recv: ", err)
+	}
+	defer func() {
+		_ = session.Close()
+	}()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	<-signals
+}
+
+func printMessage(m gosmpp.IncomingMessage) {
+	fmt.Printf("%s MO from %s to %s, data_coding 0x%02X", m.ReceivedAt.Format(time.RFC3339), m.From, m.To, m.DataCoding)
+	if total, seq, ref, ok := m.Concat(); ok {
+		fmt.Printf(", part %d/%d ref %d", seq, total, ref)
+	}
+	if dest, src, ok := m.ApplicationPort(); ok {
+		fmt.Printf(", port %d from %d", dest, src)
+	}
+
+	if m.Encoding == nil || m.Binary() {
+		fmt.Printf(": %s\n", hex.EncodeToString(m.Data))
+		return
+	}
+	fmt.Printf(": %s\n", m.Text)
+}
+
+func printReceipt(r gosmpp.Receipt) {
+	fmt.Printf("%s DLR of message_id %s: stat %s, err %s, sub %d, dlvrd %d, text %q\n",
+		time.Now().Format(time.RFC3339), r.MessageID, r.Stat, r.Err, r.Submitted, r.Delivered, r.Text)
+}
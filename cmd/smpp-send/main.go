@@ -0,0 +1,218 @@
+// Command smpp-send binds to SMSC, sends a message and waits for its delivery receipts.
+//
+//	smpp-send -addr localhost:2775 -system-id 169994 -password EDXPJU -from MyShop -to +447700900123 -text "Hello"
+//	smpp-send -system-id 169994 -password EDXPJU -to +447700900123 -hex 0102a0ff -port 2948
+//
+// Text is encoded as given by -encoding, GSM 7-bit if possible and UCS2 otherwise by default, and split into
+// concatenated parts if needed. Binary payload is sent in 8-bit binary data coding.
+package main
+
+import (
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/linxGnu/gosmpp"
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+)
+
+var encodings = map[string]data.Encoding{
+	"gsm7":       data.GSM7BIT,
+	"gsm7packed": data.GSM7BITPACKED,
+	"ascii":      data.ASCII,
+	"latin1":     data.LATIN1,
+	"cyrillic":   data.CYRILLIC,
+	"hebrew":     data.HEBREW,
+	"ucs2":       data.UCS2,
+}
+
+func main() {
+	var (
+		addr       = flag.String("addr", "localhost:2775", "SMSC address")
+		systemID   = flag.String("system-id", "", "system_id of the bind")
+		password   = flag.String("password", "", "password of the bind")
+		systemType = flag.String("system-type", "", "system_type of the bind")
+		from       = flag.String("from", "", "source address")
+		to         = flag.String("to", "", "destination address")
+		text       = flag.String("text", "", "text of the message")
+		payload    = flag.String("hex", "", "binary payload in hex, sent instead of text")
+		port       = flag.Uint("port", 0, "destination application port of binary payload, none if 0")
+		encoding   = flag.String("encoding", "auto", "encoding of text: auto, "+strings.Join(encodingNames(), ", "))
+		dlr        = flag.Bool("dlr", true, "request delivery receipt and wait for it")
+		timeout    = flag.Duration("timeout", time.Minute, "time to wait for responses and receipts")
+	)
+	flag.Parse()
+
+	if *to == "" {
+		log.Fatal("smpp-send: -to is required")
+	}
+
+	parts, err := buildParts(*from, *to, *text, *payload, uint16(*port), *encoding)
+	if err != nil {
+		log.Fatal("smpp-send: ", err)
+	}
+	if *dlr {
+		for _, part := range parts {
+			part.RegisteredDelivery = data.SM_SMSC_RECEIPT_REQUESTED
+		}
+	}
+
+	receipts := make(chan gosmpp.Receipt, 64)
+	session, err := gosmpp.NewSession(
+		gosmpp.TRXConnector(gosmpp.NonTLSDialer, gosmpp.Auth{
+			SMSC:       *addr,
+			SystemID:   *systemID,
+			Password:   *password,
+			SystemType: *systemType,
+		}),
+		gosmpp.Settings{
+			EnquireLink: 5 * time.Second,
+
+			ReadTimeout: 10 * time.Second,
+
+			OnReceivingError: func(err error) {
+				fmt.Fprintln(os.Stderr, "Receiving PDU/Network error:", err)
+			},
+
+			OnDeliveryReceipt: func(r gosmpp.Receipt) {
+				select {
+				case receipts <- r:
+				default:
+				}
+			},
+		}, -1)
+	if err != nil {
+		log.Fatal("smpp-send: ", err)
+	}
+	defer func() {
+		_ = session.Close()
+	}()
+
+	deadline := time.After(*timeout)
+	pending := make(map[string]int, len(parts))
+	for i, part := range parts {
+		call := session.SubmitAsync(part)
+		select {
+		case <-call.Done():
+		case <-deadline:
+			log.Fatalf("smpp-send: part %d/%d is not responded in %s", i+1, len(parts), *timeout)
+		}
+
+		resp, err := call.Result()
+		if err != nil {
+			log.Fatalf("smpp-send: part %d/%d: %v", i+1, len(parts), err)
+		}
+		status := resp.GetHeader().CommandStatus
+		if status != data.ESME_ROK {
+			log.Fatalf("smpp-send: part %d/%d is rejected (%s): %s", i+1, len(parts), status, status.Desc())
+		}
+
+		id := resp.(*pdu.SubmitSMResp).MessageID
+		fmt.Printf("Part %d/%d accepted, message_id %s\n", i+1, len(parts), id)
+		pending[id] = i
+	}
+
+	if !*dlr {
+		return
+	}
+
+	for len(pending) > 0 {
+		select {
+		case r := <-receipts:
+			i, ok := pending[r.MessageID]
+			if !ok {
+				continue
+			}
+			fmt.Printf("Part %d/%d receipt: stat %s, err %s, submit date %s, done date %s\n",
+				i+1, len(parts), r.Stat, r.Err, formatDate(r.SubmitDate), formatDate(r.DoneDate))
+			if r.Final() {
+				delete(pending, r.MessageID)
+			}
+
+		case <-deadline:
+			log.Fatalf("smpp-send: %d receipt(s) not received in %s", len(pending), *timeout)
+		}
+	}
+}
+
+// buildParts returns submit_sm parts of text, or of payload if given.
+func buildParts(from, to, text, payload string, port uint16, encoding string) ([]*pdu.SubmitSM, error) {
+	submit := pdu.NewSubmitSM().(*pdu.SubmitSM)
+
+	var err error
+	if from != "" {
+		if submit.SourceAddr, err = gosmpp.NormalizeAddress(from); err != nil {
+			return nil, err
+		}
+	}
+	if submit.DestAddr, err = gosmpp.NormalizeAddress(to); err != nil {
+		return nil, err
+	}
+
+	if payload != "" {
+		return binaryParts(submit, payload, port)
+	}
+
+	enc := data.GSM7BIT
+	if encoding == "auto" {
+		if _, err := data.GSM7BIT.Encode(text); err != nil {
+			enc = data.UCS2
+		}
+	} else if enc = encodings[encoding]; enc == nil {
+		return nil, fmt.Errorf("unknown encoding %q", encoding)
+	}
+
+	if err = submit.Message.SetLongMessageWithEnc(text, enc); err != nil {
+		return nil, err
+	}
+	return submit.Split()
+}
+
+func binaryParts(submit *pdu.SubmitSM, payload string, port uint16) ([]*pdu.SubmitSM, error) {
+	b, err := hex.DecodeString(payload)
+	if err != nil {
+		return nil, fmt.Errorf("invalid hex payload: %w", err)
+	}
+
+	var udh pdu.UDH
+	if port != 0 {
+		udh = pdu.UDH{pdu.NewIEApplicationPort(port, 0)}
+	}
+	messages, err := pdu.NewBinaryMessages(b, udh)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := make([]*pdu.SubmitSM, len(messages))
+	for i, msg := range messages {
+		part := *submit
+		if len(msg.UDH()) > 0 {
+			part.EsmClass |= data.SM_UDH_GSM
+		}
+		part.Message = *msg
+		parts[i] = &part
+	}
+	return parts, nil
+}
+
+func encodingNames() []string {
+	names := make([]string, 0, len(encodings))
+	for name := range encodings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format("2006-01-02 15:04")
+}
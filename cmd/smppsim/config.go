@@ -0,0 +1,223 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+	"github.com/linxGnu/gosmpp/server"
+	"github.com/linxGnu/gosmpp/server/smsctest"
+
+	"gopkg.in/yaml.v3"
+)
+
+// config is configuration of the simulator, in YAML:
+//
+//	system_id: smppsim
+//	listen: [":2775", ":2776"]
+//	enquire_link: 30s
+//	accounts:
+//	  - system_id: esme
+//	    password: secret
+//	    tps: 100
+//	receipts:
+//	  delay: 2s
+//	  states:
+//	    delivered: 90
+//	    undeliverable: 10
+//	scenario:
+//	  steps:
+//	    - command: submit_sm
+//	      requests: 100
+//	    - duration: 10s
+//	      status: ESME_RTHROTTLED
+//
+// Scenario is played from start of the simulator, see smsctest.ParseScenario for its steps.
+type config struct {
+	// SystemID is sent in bind responses, "smppsim" by default.
+	SystemID string `yaml:"system_id"`
+
+	// Listen are TCP addresses to listen on, ":2775" by default.
+	Listen []string `yaml:"listen"`
+
+	// EnquireLink is duration of silence of client after which enquire_link is sent to it, never if zero.
+	EnquireLink time.Duration `yaml:"enquire_link"`
+
+	// MaxBindsPerAccount limits concurrent binds of the same system_id, no limit if zero.
+	MaxBindsPerAccount int `yaml:"max_binds_per_account"`
+
+	// Accounts are accepted binds, all binds are accepted if empty.
+	Accounts []account `yaml:"accounts"`
+
+	// Receipts configures delivery receipts, none are sent if nil.
+	Receipts *receipts `yaml:"receipts"`
+
+	// Scenario is faults played against received requests.
+	Scenario yaml.Node `yaml:"scenario"`
+}
+
+// account is accepted bind, with limits of its requests.
+type account struct {
+	SystemID       string  `yaml:"system_id"`
+	Password       string  `yaml:"password"`
+	TPS            float64 `yaml:"tps"`
+	Burst          int     `yaml:"burst"`
+	MaxOutstanding int     `yaml:"max_outstanding"`
+}
+
+// receipts configures delivery receipts of messages requesting them.
+type receipts struct {
+	// Delay is duration after response of submit to send its receipt in.
+	Delay time.Duration `yaml:"delay"`
+
+	// States are weights of final states of messages by name, e.g. delivered or undeliverable, distributed
+	// evenly: weights 90 and 10 make every tenth message undeliverable. All messages are delivered if empty.
+	States map[string]int `yaml:"states"`
+}
+
+var stateNames = map[string]byte{
+	"enroute":       data.SM_STATE_EN_ROUTE,
+	"delivered":     data.SM_STATE_DELIVERED,
+	"expired":       data.SM_STATE_EXPIRED,
+	"deleted":       data.SM_STATE_DELETED,
+	"undeliverable": data.SM_STATE_UNDELIVERABLE,
+	"accepted":      data.SM_STATE_ACCEPTED,
+	"unknown":       data.SM_STATE_INVALID,
+	"rejected":      data.SM_STATE_REJECTED,
+}
+
+// loadConfig reads configuration from YAML file.
+func loadConfig(path string) (*config, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	c, err := parseConfig(b)
+	if err != nil {
+		return nil, fmt.Errorf("%w (%s)", err, path)
+	}
+	return c, nil
+}
+
+// parseConfig parses configuration from YAML document. Unknown fields are errors.
+func parseConfig(b []byte) (*config, error) {
+	var c config
+	decoder := yaml.NewDecoder(bytes.NewReader(b))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&c); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("invalid YAML: %w", err)
+	}
+
+	if c.SystemID == "" {
+		c.SystemID = "smppsim"
+	}
+	if len(c.Listen) == 0 {
+		c.Listen = []string{":2775"}
+	}
+	for i, a := range c.Accounts {
+		if a.SystemID == "" {
+			return nil, fmt.Errorf("accounts[%d]: system_id is required", i)
+		}
+	}
+	if c.Receipts != nil {
+		for name, weight := range c.Receipts.States {
+			if _, ok := stateNames[strings.ToLower(name)]; !ok {
+				return nil, fmt.Errorf("receipts: unknown state %q", name)
+			}
+			if weight < 0 {
+				return nil, fmt.Errorf("receipts: negative weight of state %q", name)
+			}
+		}
+	}
+	if _, err := c.scenario(); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// scenario returns fault scenario, nil if there is none.
+func (c *config) scenario() (smsctest.Scenario, error) {
+	if c.Scenario.IsZero() {
+		return nil, nil
+	}
+	b, err := yaml.Marshal(&c.Scenario)
+	if err != nil {
+		return nil, err
+	}
+	return smsctest.ParseScenario(b)
+}
+
+// credentials returns passwords of accounts by system_id, nil if all binds are accepted.
+func (c *config) credentials() map[string]string {
+	if len(c.Accounts) == 0 {
+		return nil
+	}
+	credentials := make(map[string]string, len(c.Accounts))
+	for _, a := range c.Accounts {
+		credentials[a.SystemID] = a.Password
+	}
+	return credentials
+}
+
+// limits returns limits of account of system_id.
+func (c *config) limits(systemID string) server.Limits {
+	for _, a := range c.Accounts {
+		if a.SystemID == systemID {
+			return server.Limits{TPS: a.TPS, Burst: a.Burst, MaxOutstanding: a.MaxOutstanding}
+		}
+	}
+	return server.Limits{}
+}
+
+// receipts returns delivery receipts configuration of smsctest.Server, nil if receipts are disabled.
+func (c *config) receipts() *smsctest.Receipts {
+	if c.Receipts == nil {
+		return nil
+	}
+	return &smsctest.Receipts{Delay: c.Receipts.Delay, State: stateFunc(c.Receipts.States)}
+}
+
+// stateFunc returns function distributing final states of messages evenly by their weights, nil if there are
+// no weights.
+func stateFunc(weights map[string]int) func(pdu.PDU) byte {
+	names := make([]string, 0, len(weights))
+	total := 0
+	for name, weight := range weights {
+		if weight > 0 {
+			names = append(names, name)
+			total += weight
+		}
+	}
+	if total == 0 {
+		return nil
+	}
+	sort.Strings(names)
+
+	var (
+		mu      sync.Mutex
+		current = make([]int, len(names))
+	)
+	return func(pdu.PDU) byte {
+		mu.Lock()
+		defer mu.Unlock()
+
+		// smooth weighted round robin: every state gains its weight, the leading one is picked and loses total
+		best := 0
+		for i, name := range names {
+			current[i] += weights[name]
+			if current[i] > current[best] {
+				best = i
+			}
+		}
+		current[best] -= total
+		return stateNames[strings.ToLower(names[best])]
+	}
+}
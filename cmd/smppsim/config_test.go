@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp"
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/server/smsctest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseConfig(t *testing.T) {
+	c, err := loadConfig("smppsim.yaml")
+	require.Nil(t, err)
+	require.Equal(t, "smppsim", c.SystemID)
+	require.Equal(t, []string{":2775"}, c.Listen)
+	require.Equal(t, 30*time.Second, c.EnquireLink)
+	require.Equal(t, map[string]string{"esme": "secret", "qa": "qa"}, c.credentials())
+	require.EqualValues(t, 100, c.limits("esme").TPS)
+	require.Zero(t, c.limits("other").TPS)
+
+	scenario, err := c.scenario()
+	require.Nil(t, err)
+	require.Equal(t, smsctest.Scenario{
+		{Command: data.SUBMIT_SM, Requests: 1000},
+		smsctest.During(10*time.Second, smsctest.Fault{Status: data.ESME_RTHROTTLED}),
+	}, scenario)
+
+	c, err = parseConfig(nil)
+	require.Nil(t, err)
+	require.Equal(t, []string{":2775"}, c.Listen)
+	require.Nil(t, c.credentials())
+	require.Nil(t, c.receipts())
+
+	for name, doc := range map[string]string{
+		"unknown field": "port: 2775",
+		"no system_id":  "accounts: [{password: x}]",
+		"unknown state": "receipts: {states: {lost: 1}}",
+		"bad scenario":  "scenario: {steps: [{status: ESME_RNOPE}]}",
+	} {
+		_, err = parseConfig([]byte(doc))
+		require.NotNil(t, err, name)
+	}
+}
+
+func TestStateFunc(t *testing.T) {
+	require.Nil(t, stateFunc(nil))
+
+	state := stateFunc(map[string]int{"delivered": 80, "undeliverable": 10, "Expired": 10})
+	counts := map[byte]int{}
+	for i := 0; i < 100; i++ {
+		counts[state(nil)]++
+	}
+	require.Equal(t, map[byte]int{
+		data.SM_STATE_DELIVERED:     80,
+		data.SM_STATE_UNDELIVERABLE: 10,
+		data.SM_STATE_EXPIRED:       10,
+	}, counts)
+}
+
+func TestSimulator(t *testing.T) {
+	c, err := parseConfig([]byte(`
+listen: ["127.0.0.1:0", "127.0.0.1:0"]
+accounts: [{system_id: esme, password: secret}]
+receipts: {states: {undeliverable: 1}}
+scenario: {steps: [{status: ESME_RTHROTTLED, requests: 1}]}
+`))
+	require.Nil(t, err)
+
+	smsc, err := newSimulator(c, true)
+	require.Nil(t, err)
+	defer smsc.Close()
+
+	receipts := make(chan gosmpp.Receipt, 1)
+	session, err := gosmpp.NewSession(
+		gosmpp.TRXConnector(gosmpp.NonTLSDialer, gosmpp.Auth{SMSC: smsc.Addr, SystemID: "esme", Password: "secret"}),
+		gosmpp.Settings{
+			ReadTimeout:       2 * time.Second,
+			OnDeliveryReceipt: func(r gosmpp.Receipt) { receipts <- r },
+		}, -1)
+	require.Nil(t, err)
+	defer func() {
+		_ = session.Close()
+	}()
+
+	messenger := gosmpp.NewMessenger(session, gosmpp.WithRegisteredDelivery(data.SM_SMSC_RECEIPT_REQUESTED))
+	_, err = messenger.SendText(context.Background(), "QA", "+447700900123", "Hello")
+	require.ErrorContains(t, err, "ESME_RTHROTTLED")
+
+	h, err := messenger.SendText(context.Background(), "QA", "+447700900123", "Hello")
+	require.Nil(t, err)
+
+	select {
+	case r := <-receipts:
+		require.Equal(t, h.MessageIDs[0], r.MessageID)
+		require.Equal(t, "UNDELIV", r.Stat)
+	case <-time.After(2 * time.Second):
+		t.Fatal("no receipt")
+	}
+}
@@ -0,0 +1,146 @@
+// Command smppsim runs SMSC simulator configured by YAML file: listening ports, accounts and their limits,
+// delivery receipts and fault scenarios, so QA and CI environments could run mock SMSC without writing Go.
+//
+//	smppsim -config smppsim.yaml
+//	smppsim -listen :2775 -receipts
+//
+// Submits are acknowledged with generated message_id and delivery receipts are sent to clients requesting them,
+// with final states distributed by configured weights. See smppsim.yaml for the configuration. The image of
+// Dockerfile runs the simulator with that configuration:
+//
+//	docker build -f cmd/smppsim/Dockerfile -t smppsim .
+//	docker run -p 2775:2775 smppsim
+package main
+
+import (
+	"flag"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/linxGnu/gosmpp/pdu"
+	"github.com/linxGnu/gosmpp/server"
+	"github.com/linxGnu/gosmpp/server/smsctest"
+)
+
+var bindingTypes = map[pdu.BindingType]string{
+	pdu.Receiver:    "receiver",
+	pdu.Transmitter: "transmitter",
+	pdu.Transceiver: "transceiver",
+}
+
+func main() {
+	var (
+		configFile = flag.String("config", "", "YAML configuration file")
+		listen     = flag.String("listen", "", "comma separated addresses to listen on, overriding configuration")
+		dlr        = flag.Bool("receipts", false, "send delivery receipts of delivered messages, if not configured")
+		verbose    = flag.Bool("v", false, "print received PDUs")
+	)
+	flag.Parse()
+
+	var (
+		c   *config
+		err error
+	)
+	if *configFile != "" {
+		c, err = loadConfig(*configFile)
+	} else {
+		c, err = parseConfig(nil)
+	}
+	if err != nil {
+		log.Fatal("smppsim: ", err)
+	}
+	if *listen != "" {
+		c.Listen = strings.Split(*listen, ",")
+	}
+	if *dlr && c.Receipts == nil {
+		c.Receipts = &receipts{}
+	}
+
+	smsc, err := newSimulator(c, *verbose)
+	if err != nil {
+		log.Fatal("smppsim: ", err)
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	<-signals
+
+	log.Println("Shutting down")
+	smsc.Close()
+}
+
+// newSimulator starts smsctest.Server configured by c on its listen addresses.
+func newSimulator(c *config, verbose bool) (*smsctest.Server, error) {
+	scenario, err := c.scenario()
+	if err != nil {
+		return nil, err
+	}
+
+	listeners := make([]net.Listener, 0, len(c.Listen))
+	for _, addr := range c.Listen {
+		l, err := net.Listen("tcp", addr)
+		if err != nil {
+			for _, l := range listeners {
+				_ = l.Close()
+			}
+			return nil, err
+		}
+		listeners = append(listeners, l)
+	}
+
+	smsc := smsctest.NewUnstartedServer(c.credentials())
+	_ = smsc.Listener.Close()
+	smsc.Listener = listeners[0]
+
+	smsc.Config.SystemID = c.SystemID
+	smsc.Config.EnquireLink = c.EnquireLink
+	smsc.Config.MaxBindsPerAccount = c.MaxBindsPerAccount
+	smsc.Config.Limits = c.limits
+	smsc.Config.OnBound = func(s *server.Session) {
+		log.Printf("Bound %s as %s from %s", s.SystemID(), bindingTypes[s.BindingType()], s.RemoteAddr())
+	}
+	smsc.Config.OnClosed = func(s *server.Session, err error) {
+		if err != nil {
+			log.Printf("Closed %s from %s: %v", s.SystemID(), s.RemoteAddr(), err)
+		} else {
+			log.Printf("Unbound %s from %s", s.SystemID(), s.RemoteAddr())
+		}
+	}
+	if verbose {
+		smsc.Config.Interceptors = append(smsc.Config.Interceptors, logInterceptor())
+	}
+
+	smsc.SetReceipts(c.receipts())
+	if scenario != nil {
+		smsc.Play(scenario)
+	}
+
+	smsc.Start()
+	for _, l := range listeners[1:] {
+		go func(l net.Listener) {
+			_ = smsc.Config.Serve(l)
+		}(l)
+	}
+	for _, l := range listeners {
+		log.Printf("Listening on %s", l.Addr())
+	}
+	return smsc, nil
+}
+
+// logInterceptor prints PDUs received from and written to clients.
+func logInterceptor() server.Interceptor {
+	return server.Interceptor{
+		Inbound: func(s *server.Session, p pdu.PDU, next func(pdu.PDU)) {
+			log.Printf("%s > %s", s.SystemID(), pdu.Sprint(p))
+			next(p)
+		},
+		Outbound: func(s *server.Session, p pdu.PDU, next func(pdu.PDU) error) error {
+			log.Printf("%s < %s", s.SystemID(), pdu.Sprint(p))
+			return next(p)
+		},
+	}
+}
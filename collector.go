@@ -0,0 +1,40 @@
+package gosmpp
+
+import "sync/atomic"
+
+// Names of counters reported to MetricsCollector by Session.
+const (
+	MetricPDUsSent        = "pdus_sent"
+	MetricPDUsReceived    = "pdus_received"
+	MetricSubmitErrors    = "submit_errors"
+	MetricReceivingErrors = "receiving_errors"
+	MetricRebinds         = "rebinds"
+	MetricTimeouts        = "timeouts"
+)
+
+// MetricsCollector receives counters and gauges, e.g. to export them to Prometheus or StatsD.
+//
+// It is shared by client sessions and the server package. Methods are called synchronously
+// from session goroutines, so they should not block.
+type MetricsCollector interface {
+	// IncCounter adds delta to counter of name with labels.
+	IncCounter(name string, labels Labels, delta int64)
+
+	// SetGauge sets gauge of name with labels to value.
+	SetGauge(name string, labels Labels, value float64)
+}
+
+// WithMetricsCollector reports session counters to collector, labelled by session labels.
+func WithMetricsCollector(collector MetricsCollector) SessionOption {
+	return func(s *Session) {
+		s.counters.collector = collector
+	}
+}
+
+// inc increments counter of name.
+func (c *sessionCounters) inc(counter *int64, name string) {
+	atomic.AddInt64(counter, 1)
+	if c.collector != nil {
+		c.collector.IncCounter(name, c.labels, 1)
+	}
+}
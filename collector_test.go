@@ -0,0 +1,63 @@
+package gosmpp
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// recordingCollector sums counters by name.
+type recordingCollector struct {
+	mu       sync.Mutex
+	counters map[string]int64
+	labels   Labels
+}
+
+func (c *recordingCollector) IncCounter(name string, labels Labels, delta int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.counters == nil {
+		c.counters = make(map[string]int64)
+	}
+	c.counters[name] += delta
+	c.labels = labels
+}
+
+func (c *recordingCollector) SetGauge(string, Labels, float64) {}
+
+func (c *recordingCollector) counter(name string) int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.counters[name]
+}
+
+func TestWithMetricsCollector(t *testing.T) {
+	collector := &recordingCollector{}
+	labels := Labels{"route": "otp"}
+	auth := nextAuth()
+
+	s, err := NewSession(
+		TRXConnector(NonTLSDialer, auth),
+		Settings{
+			ReadTimeout: 2 * time.Second,
+		}, -1, WithMetricsCollector(collector), WithLabels(labels))
+	require.Nil(t, err)
+	defer func() {
+		_ = s.Close()
+	}()
+
+	require.Nil(t, s.Transceiver().Submit(newSubmitSM(auth.SystemID)))
+	require.Eventually(t, func() bool {
+		return collector.counter(MetricPDUsReceived) > 0
+	}, time.Second, 10*time.Millisecond)
+
+	stats := s.Stats()
+	require.Equal(t, stats.PDUsSent, collector.counter(MetricPDUsSent))
+
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+	require.Equal(t, labels, collector.labels)
+}
@@ -0,0 +1,89 @@
+package gosmpp
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Upstream linxGnu/gosmpp API, which applications migrating to this module by replace directive compile
+// against. Signatures must not change: extend by new functions, options or variadic parameters only.
+var (
+	_ func(Connector, Settings, time.Duration, ...SessionOption) (*Session, error) = NewSession
+	_ func(RequestStore) SessionOption                                             = WithRequestStore
+	_ func() DefaultStore                                                          = NewDefaultStore
+	_ RequestStore                                                                 = DefaultStore{}
+
+	_ func(Dialer, Auth) Connector              = func(d Dialer, a Auth) Connector { return TXConnector(d, a) }
+	_ func(Dialer, Auth) Connector              = func(d Dialer, a Auth) Connector { return RXConnector(d, a) }
+	_ func(Dialer, Auth) Connector              = func(d Dialer, a Auth) Connector { return TRXConnector(d, a) }
+	_ func(string) (net.Conn, error)            = NonTLSDialer
+	_ func(net.Conn) *Connection                = NewConnection
+	_ func(*Session) Transmitter                = (*Session).Transmitter
+	_ func(*Session) Receiver                   = (*Session).Receiver
+	_ func(*Session) Transceiver                = (*Session).Transceiver
+	_ func(*Session) (int, error)               = (*Session).GetWindowSize
+	_ func(*Connection, pdu.PDU) (int, error)   = (*Connection).WritePDU
+	_ func(*Connection, time.Duration) error    = (*Connection).SetReadTimeout
+	_ func(*Connection, time.Duration) error    = (*Connection).SetWriteTimeout
+	_ func(Connector) pdu.BindingType           = Connector.GetBindType
+	_ func(Connector) (*Connection, error)      = Connector.Connect
+	_ func(RequestStore, context.Context) error = RequestStore.Clear
+
+	_ func(pdu.PDU, bool)           = PDUCallback(nil)
+	_ func(pdu.PDU) (pdu.PDU, bool) = AllPDUCallback(nil)
+	_ func(pdu.PDU, error)          = PDUErrorCallback(nil)
+	_ func(State)                   = ClosedCallback(nil)
+
+	_ func() pdu.PDU                                           = pdu.NewSubmitSM
+	_ func(string, data.Encoding) (pdu.ShortMessage, error)    = pdu.NewShortMessageWithEncoding
+	_ func(string, data.Encoding) ([]*pdu.ShortMessage, error) = pdu.NewLongMessageWithEncoding
+	_ func(*pdu.ShortMessage, string, data.Encoding) error     = (*pdu.ShortMessage).SetMessageWithEncoding
+	_ func(*pdu.ShortMessage, string, data.Encoding) error     = (*pdu.ShortMessage).SetLongMessageWithEnc
+	_ func(*pdu.SubmitSM) ([]*pdu.SubmitSM, error)             = (*pdu.SubmitSM).Split
+	_ func(byte, byte) pdu.Address                             = pdu.NewAddressWithTonNpi
+	_ func(pdu.BindingType) *pdu.BindRequest                   = pdu.NewBindRequest
+	_ func(io.Reader) (pdu.PDU, error)                         = pdu.Parse
+)
+
+func TestUpstreamSettings(t *testing.T) {
+	// fields of upstream Settings, set as upstream examples do
+	settings := Settings{
+		EnquireLink:  5 * time.Second,
+		ReadTimeout:  10 * time.Second,
+		WriteTimeout: time.Second,
+
+		OnSubmitError:    func(pdu.PDU, error) {},
+		OnReceivingError: func(error) {},
+		OnRebindingError: func(error) {},
+		OnPDU:            func(pdu.PDU, bool) {},
+		OnAllPDU:         func(p pdu.PDU) (pdu.PDU, bool) { return p.GetResponse(), false },
+		OnClosed:         func(State) {},
+		OnRebind:         func() {},
+
+		WindowedRequestTracking: &WindowedRequestTracking{
+			OnReceivedPduRequest:    func(p pdu.PDU) (pdu.PDU, bool) { return p.GetResponse(), false },
+			OnExpectedPduResponse:   func(Response) {},
+			OnUnexpectedPduResponse: func(pdu.PDU) {},
+			OnExpiredPduRequest:     func(pdu.PDU) bool { return false },
+			OnClosePduRequest:       func(pdu.PDU) {},
+			PduExpireTimeOut:        time.Minute,
+			ExpireCheckTimer:        10 * time.Second,
+			MaxWindowSize:           30,
+			EnableAutoRespond:       true,
+			StoreAccessTimeOut:      time.Second,
+		},
+	}
+	require.EqualValues(t, 30, settings.MaxWindowSize)
+
+	request := Request{PDU: pdu.NewSubmitSM(), TimeSent: time.Now()}
+	response := Response{PDU: request.PDU.GetResponse(), OriginalRequest: request}
+	require.Equal(t, request.PDU.GetSequenceNumber(), response.PDU.GetSequenceNumber())
+}
@@ -0,0 +1,199 @@
+package gosmpp_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp"
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+	"github.com/linxGnu/gosmpp/server/smsctest"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	stressSubmitters = 16
+	stressSubmits    = 50
+)
+
+func newTextSubmitSM(dest, text string) pdu.PDU {
+	p := pdu.NewSubmitSM().(*pdu.SubmitSM)
+	_ = p.DestAddr.SetAddress(dest)
+	_ = p.Message.SetMessageWithEncoding(text, data.GSM7BIT)
+	return p
+}
+
+// stressSubmit submits from many goroutines at once, half by Submit and half by SubmitAsync, each goroutine
+// to its own destination.
+func stressSubmit(t *testing.T, s *gosmpp.Session) {
+	var wg sync.WaitGroup
+	for g := 0; g < stressSubmitters; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+
+			dest := fmt.Sprint(g)
+			calls := make([]*gosmpp.Call, 0, stressSubmits)
+			for i := 0; i < stressSubmits; i++ {
+				p := newTextSubmitSM(dest, fmt.Sprint(i))
+				if g%2 == 0 {
+					if err := s.Submit(p); err != nil {
+						t.Errorf("submit: %v", err)
+					}
+				} else {
+					calls = append(calls, s.SubmitAsync(p))
+				}
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			for _, c := range calls {
+				if _, err := c.Wait(ctx); err != nil {
+					t.Errorf("submit async: %v", err)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+}
+
+// requireSubmitOrder checks that every goroutine of stressSubmit got its requests written in its submitting order.
+func requireSubmitOrder(t *testing.T, submitted []pdu.PDU) {
+	next := make(map[string]int)
+	for _, p := range submitted {
+		submit := p.(*pdu.SubmitSM)
+		text, _ := submit.Message.GetMessage()
+		dest := submit.DestAddr.Address()
+		require.Equal(t, fmt.Sprint(next[dest]), text, "order of destination %s", dest)
+		next[dest]++
+	}
+	require.Len(t, next, stressSubmitters)
+	for dest, n := range next {
+		require.Equal(t, stressSubmits, n, "submits of destination %s", dest)
+	}
+}
+
+func TestSessionConcurrentSubmit(t *testing.T) {
+	bind := func(t *testing.T, smsc *smsctest.Server, settings gosmpp.Settings) *gosmpp.Session {
+		settings.ReadTimeout = 2 * time.Second
+		settings.EnquireLink = 50 * time.Millisecond
+		s, err := gosmpp.NewSession(gosmpp.TRXConnector(smsc.Dialer(), gosmpp.Auth{SMSC: "pipe", SystemID: "esme"}), settings, -1)
+		require.Nil(t, err)
+		return s
+	}
+
+	t.Run("daemon", func(t *testing.T) {
+		smsc := smsctest.NewPipeServer(nil)
+		defer smsc.Close()
+
+		s := bind(t, smsc, gosmpp.Settings{})
+		defer func() {
+			_ = s.Close()
+		}()
+
+		stressSubmit(t, s)
+		require.Eventually(t, func() bool {
+			return len(smsc.Submitted()) == stressSubmitters*stressSubmits
+		}, 5*time.Second, 10*time.Millisecond)
+		requireSubmitOrder(t, smsc.Submitted())
+	})
+
+	t.Run("reactor", func(t *testing.T) {
+		smsc := smsctest.NewPipeServer(nil)
+		defer smsc.Close()
+
+		r := gosmpp.NewReactor(2)
+		defer func() {
+			_ = r.Close()
+		}()
+
+		s := bind(t, smsc, gosmpp.Settings{Reactor: r})
+		defer func() {
+			_ = s.Close()
+		}()
+
+		stressSubmit(t, s)
+		require.Eventually(t, func() bool {
+			return len(smsc.Submitted()) == stressSubmitters*stressSubmits
+		}, 5*time.Second, 10*time.Millisecond)
+		requireSubmitOrder(t, smsc.Submitted())
+	})
+
+	t.Run("closing", func(t *testing.T) {
+		smsc := smsctest.NewPipeServer(nil)
+		defer smsc.Close()
+
+		s := bind(t, smsc, gosmpp.Settings{})
+
+		var wg sync.WaitGroup
+		for g := 0; g < stressSubmitters; g++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for {
+					if err := s.Submit(pdu.NewSubmitSM()); err != nil {
+						if !errors.Is(err, gosmpp.ErrConnectionClosing) {
+							t.Errorf("submit: %v", err)
+						}
+						return
+					}
+				}
+			}()
+		}
+
+		time.Sleep(20 * time.Millisecond)
+		require.Nil(t, s.Close())
+
+		done := make(chan struct{})
+		go func() {
+			wg.Wait()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("submitters are blocked by closed session")
+		}
+	})
+}
+
+// Session is shared by goroutines submitting at once, without locking of their own.
+func ExampleSession_Submit() {
+	smsc := smsctest.NewPipeServer(nil)
+	defer smsc.Close()
+
+	s, err := gosmpp.NewSession(gosmpp.TRXConnector(smsc.Dialer(), gosmpp.Auth{SMSC: "pipe", SystemID: "esme"}),
+		gosmpp.Settings{ReadTimeout: time.Second}, -1)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer func() {
+		_ = s.Close()
+	}()
+
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+
+			// written in this order, between PDUs of the other goroutines
+			for i := 0; i < 3; i++ {
+				_ = s.Submit(newTextSubmitSM(fmt.Sprint(g), fmt.Sprint(i)))
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	for len(smsc.Submitted()) < 12 {
+		time.Sleep(time.Millisecond)
+	}
+	fmt.Println(len(smsc.Submitted()))
+	// Output: 12
+}
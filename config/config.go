@@ -0,0 +1,333 @@
+// Package config loads sessions, pools of messengers and routes from YAML or JSON and opens them, so that
+// deployments describe their binds in a file instead of mapping it into options by hand.
+//
+//	sessions:
+//	  - name: primary
+//	    address: smsc.example.com:2775
+//	    system_id: shop
+//	    password: secret
+//	    enquire_link: 30s
+//	    read_timeout: 60s
+//	    rebind_interval: 5s
+//	    window: {size: 30, expire_timeout: 30s}
+//	    max_command_length: 4096
+//	  - name: backup
+//	    endpoints: [smsc1.example.com:2775, smsc2.example.com:2775]
+//	    system_id: shop
+//	    password: secret
+//	    tls: {server_name: smsc.example.com}
+//	pools:
+//	  - name: default
+//	    sessions: [primary, backup]
+//	    registered_delivery: true
+//	routes:
+//	  - prefix: "44"
+//	    pool: default
+//
+// Addresses and credentials may refer to environment variables and files, e.g. password: ${file:/run/secrets/smpp},
+// see Config.Resolve. Sessions.ReloadOnSignal rotates credentials changed in them on SIGHUP.
+//
+// Load parses and validates the file, Config.Open binds the sessions:
+//
+//	cfg, err := config.Load("smpp.yaml")
+//	...
+//	sessions, err := cfg.Open(config.WithSettings(gosmpp.Settings{OnMessage: handleMessage}))
+//	...
+//	defer sessions.Close()
+//	gw := &gateway.Gateway{Messengers: sessions.Route("+447700900123")}
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/linxGnu/gosmpp/data"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultReadTimeout is default read_timeout of sessions.
+const DefaultReadTimeout = 10 * time.Second
+
+// Config is configuration of sessions, pools and routes.
+type Config struct {
+	Sessions []Session `yaml:"sessions" json:"sessions"`
+	Pools    []Pool    `yaml:"pools" json:"pools"`
+	Routes   []Route   `yaml:"routes" json:"routes"`
+}
+
+// Session is configuration of gosmpp.Session.
+type Session struct {
+	// Name identifies the session in pools, it is also its "session" label, see gosmpp.WithLabels.
+	Name string `yaml:"name" json:"name"`
+
+	// Bind is type of bind, transceiver by default.
+	Bind BindType `yaml:"bind" json:"bind"`
+
+	// Address is address of SMSC (host:port), Endpoints are addresses rotated through on rebinds,
+	// see gosmpp.WithEndpoints. One of them is required.
+	Address   string   `yaml:"address" json:"address"`
+	Endpoints []string `yaml:"endpoints" json:"endpoints"`
+
+	SystemID   string `yaml:"system_id" json:"system_id"`
+	Password   string `yaml:"password" json:"password"`
+	SystemType string `yaml:"system_type" json:"system_type"`
+
+	// AddressRange is address_range of the bind.
+	AddressRange *AddressRange `yaml:"address_range" json:"address_range"`
+
+	// TLS connects over TLS if set.
+	TLS *TLS `yaml:"tls" json:"tls"`
+
+	// ReadTimeout is DefaultReadTimeout if zero, it must be longer than EnquireLink.
+	ReadTimeout  Duration `yaml:"read_timeout" json:"read_timeout"`
+	WriteTimeout Duration `yaml:"write_timeout" json:"write_timeout"`
+	EnquireLink  Duration `yaml:"enquire_link" json:"enquire_link"`
+
+	// RebindInterval is duration to wait before rebinding, zero disables rebinding.
+	RebindInterval Duration `yaml:"rebind_interval" json:"rebind_interval"`
+
+	// Window enables windowed request tracking, see gosmpp.WindowedRequestTracking.
+	Window *Window `yaml:"window" json:"window"`
+
+	// MaxCommandLength caps size of PDUs exchanged with SMSC, see gosmpp.Settings.MaxCommandLength.
+	// Zero means data.MAX_PDU_LEN.
+	MaxCommandLength int `yaml:"max_command_length" json:"max_command_length"`
+
+	Labels map[string]string `yaml:"labels" json:"labels"`
+}
+
+// AddressRange is address_range of bind.
+type AddressRange struct {
+	TON     TON    `yaml:"ton" json:"ton"`
+	NPI     NPI    `yaml:"npi" json:"npi"`
+	Address string `yaml:"address" json:"address"`
+}
+
+// TLS is configuration of TLS connection.
+type TLS struct {
+	// ServerName verifies certificate of SMSC, host of the address by default.
+	ServerName string `yaml:"server_name" json:"server_name"`
+
+	// CAFile is PEM file of certificates of authorities trusted besides those of the system.
+	CAFile string `yaml:"ca_file" json:"ca_file"`
+
+	// InsecureSkipVerify disables verification of certificate of SMSC.
+	InsecureSkipVerify bool `yaml:"insecure_skip_verify" json:"insecure_skip_verify"`
+}
+
+// Window is configuration of gosmpp.WindowedRequestTracking.
+type Window struct {
+	// Size is maximum number of outstanding requests, 1 to 255.
+	Size int `yaml:"size" json:"size"`
+
+	// ExpireTimeout expires requests not responded in time, ExpireCheck is period of checking them,
+	// half of ExpireTimeout by default.
+	ExpireTimeout Duration `yaml:"expire_timeout" json:"expire_timeout"`
+	ExpireCheck   Duration `yaml:"expire_check" json:"expire_check"`
+
+	// StoreTimeout is timeout of accessing request store, DefaultStoreTimeout by default.
+	StoreTimeout Duration `yaml:"store_timeout" json:"store_timeout"`
+}
+
+// Pool is group of sessions sending messages by messengers, see gosmpp.Messenger.
+type Pool struct {
+	Name string `yaml:"name" json:"name"`
+
+	// Sessions are names of sessions of the pool, they must not be receivers.
+	Sessions []string `yaml:"sessions" json:"sessions"`
+
+	// ServiceType is service_type of messages, see gosmpp.WithServiceType.
+	ServiceType string `yaml:"service_type" json:"service_type"`
+
+	// RegisteredDelivery requests delivery receipts of messages.
+	RegisteredDelivery bool `yaml:"registered_delivery" json:"registered_delivery"`
+}
+
+// Route sends messages to destinations starting with Prefix by Pool.
+type Route struct {
+	// Prefix are leading digits of destination, e.g. "44" or "+44". Empty prefix matches all destinations.
+	Prefix string `yaml:"prefix" json:"prefix"`
+	Pool   string `yaml:"pool" json:"pool"`
+}
+
+// Load reads configuration from file, JSON if its extension is .json and YAML otherwise, and validates it.
+func Load(path string) (*Config, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+
+	var c *Config
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		c, err = ParseJSON(b)
+	} else {
+		c, err = ParseYAML(b)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w (%s)", err, path)
+	}
+	return c, nil
+}
+
+// ParseYAML parses configuration from YAML document and validates it. Unknown fields are errors.
+func ParseYAML(b []byte) (*Config, error) {
+	var c Config
+	decoder := yaml.NewDecoder(bytes.NewReader(b))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&c); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("config: invalid YAML: %w", err)
+	}
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// ParseJSON parses configuration from JSON document and validates it. Unknown fields are errors.
+func ParseJSON(b []byte) (*Config, error) {
+	var c Config
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&c); err != nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			line, col := position(b, syntaxErr.Offset)
+			return nil, fmt.Errorf("config: invalid JSON at line %d, column %d: %w", line, col, err)
+		}
+		return nil, fmt.Errorf("config: invalid JSON: %w", err)
+	}
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// position returns line and column of byte before offset in b, where JSON decoder stopped, counted from 1.
+func position(b []byte, offset int64) (line, col int) {
+	if offset > int64(len(b)) {
+		offset = int64(len(b))
+	}
+	before := b[:offset]
+	line = bytes.Count(before, []byte("\n")) + 1
+	col = len(before) - 1 - bytes.LastIndexByte(before, '\n')
+	return
+}
+
+// Validate checks the configuration, returning all problems found joined, each prefixed by its location,
+// e.g. `sessions[1] "backup": system_id is required`.
+func (c *Config) Validate() error {
+	var errs []error
+	report := func(where, format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf("config: %s: %s", where, fmt.Sprintf(format, args...)))
+	}
+
+	sessions := make(map[string]Session, len(c.Sessions))
+	for i, s := range c.Sessions {
+		where := fmt.Sprintf("sessions[%d] %q", i, s.Name)
+		switch _, dup := sessions[s.Name]; {
+		case s.Name == "":
+			report(where, "name is required")
+		case dup:
+			report(where, "duplicate name")
+		default:
+			sessions[s.Name] = s
+		}
+		for _, problem := range s.validate() {
+			report(where, "%s", problem)
+		}
+	}
+
+	pools := make(map[string]bool, len(c.Pools))
+	for i, p := range c.Pools {
+		where := fmt.Sprintf("pools[%d] %q", i, p.Name)
+		switch {
+		case p.Name == "":
+			report(where, "name is required")
+		case pools[p.Name]:
+			report(where, "duplicate name")
+		default:
+			pools[p.Name] = true
+		}
+		if len(p.Sessions) == 0 {
+			report(where, "sessions are required")
+		}
+		for _, name := range p.Sessions {
+			if s, ok := sessions[name]; !ok {
+				report(where, "unknown session %q", name)
+			} else if s.Bind == Receiver {
+				report(where, "session %q is receiver, it can not send", name)
+			}
+		}
+	}
+
+	for i, r := range c.Routes {
+		where := fmt.Sprintf("routes[%d] %q", i, r.Prefix)
+		if prefix := strings.TrimPrefix(r.Prefix, "+"); !isDigits(prefix) {
+			report(where, "prefix must be digits")
+		}
+		if !pools[r.Pool] {
+			report(where, "unknown pool %q", r.Pool)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s Session) validate() (problems []string) {
+	if _, ok := s.Bind.bindingType(); !ok {
+		problems = append(problems, fmt.Sprintf("invalid bind %q, expected transceiver, transmitter or receiver", s.Bind))
+	}
+	if s.Address == "" && len(s.Endpoints) == 0 {
+		problems = append(problems, "address or endpoints are required")
+	}
+	if s.SystemID == "" {
+		problems = append(problems, "system_id is required")
+	}
+	if s.EnquireLink < 0 || s.ReadTimeout < 0 || s.WriteTimeout < 0 || s.RebindInterval < 0 {
+		problems = append(problems, "durations must not be negative")
+	}
+	if readTimeout := s.readTimeout(); readTimeout <= time.Duration(s.EnquireLink) {
+		problems = append(problems, fmt.Sprintf("read_timeout (%s) must be longer than enquire_link (%s)",
+			readTimeout, time.Duration(s.EnquireLink)))
+	}
+	if s.MaxCommandLength < 0 || s.MaxCommandLength > data.MAX_PDU_LEN {
+		problems = append(problems, fmt.Sprintf("max_command_length %d is out of range 0-%d",
+			s.MaxCommandLength, data.MAX_PDU_LEN))
+	}
+	if w := s.Window; w != nil {
+		if s.Bind == Receiver {
+			problems = append(problems, "window is not available on receiver binds")
+		}
+		if w.Size < 1 || w.Size > 255 {
+			problems = append(problems, fmt.Sprintf("window size %d is out of range 1-255", w.Size))
+		}
+		if w.ExpireTimeout < 0 || w.ExpireCheck < 0 || w.StoreTimeout < 0 {
+			problems = append(problems, "window durations must not be negative")
+		}
+	}
+	return
+}
+
+func (s Session) readTimeout() time.Duration {
+	if s.ReadTimeout > 0 {
+		return time.Duration(s.ReadTimeout)
+	}
+	return DefaultReadTimeout
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
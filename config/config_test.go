@@ -0,0 +1,199 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp"
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/server/smsctest"
+
+	"github.com/stretchr/testify/require"
+)
+
+const sample = `
+sessions:
+  - name: primary
+    address: smsc.example.com:2775
+    system_id: shop
+    password: secret
+    enquire_link: 5s
+    read_timeout: 10s
+    window: {size: 30, expire_timeout: 30s, store_timeout: 500ms}
+    max_command_length: 4096
+    address_range: {ton: international, npi: isdn, address: "^44"}
+    labels: {carrier: acme}
+  - name: backup
+    bind: transmitter
+    endpoints: [smsc1.example.com:2775, smsc2.example.com:2775]
+    system_id: shop
+    tls: {server_name: smsc.example.com}
+  - name: mo
+    bind: receiver
+    address: smsc.example.com:2775
+    system_id: shop
+pools:
+  - name: uk
+    sessions: [primary, backup]
+    registered_delivery: true
+  - name: other
+    sessions: [backup]
+routes:
+  - prefix: "+44"
+    pool: uk
+  - prefix: ""
+    pool: other
+`
+
+func TestParseYAML(t *testing.T) {
+	c, err := ParseYAML([]byte(sample))
+	require.Nil(t, err)
+	require.Len(t, c.Sessions, 3)
+
+	primary := c.Sessions[0]
+	require.Equal(t, Duration(5*time.Second), primary.EnquireLink)
+	require.Equal(t, &AddressRange{TON: TON(data.GSM_TON_INTERNATIONAL), NPI: NPI(data.GSM_NPI_ISDN), Address: "^44"},
+		primary.AddressRange)
+
+	settings := primary.settings(gosmpp.Settings{})
+	require.Equal(t, 10*time.Second, settings.ReadTimeout)
+	require.Equal(t, uint8(30), settings.MaxWindowSize)
+	require.Equal(t, 15*time.Second, settings.ExpireCheckTimer)
+	require.Equal(t, time.Duration(500), settings.StoreAccessTimeOut)
+	require.Equal(t, 4096, settings.MaxCommandLength)
+
+	require.Equal(t, Transmitter, c.Sessions[1].Bind)
+	require.Nil(t, c.Sessions[1].settings(gosmpp.Settings{}).WindowedRequestTracking)
+	require.Equal(t, DefaultReadTimeout, c.Sessions[1].settings(gosmpp.Settings{}).ReadTimeout)
+}
+
+func TestParseJSON(t *testing.T) {
+	c, err := ParseJSON([]byte(`{"sessions": [{"name": "a", "address": "localhost:2775", "system_id": "shop",
+		"address_range": {"ton": 1, "npi": "0x01"}}]}`))
+	require.Nil(t, err)
+	require.Equal(t, TON(1), c.Sessions[0].AddressRange.TON)
+	require.Equal(t, NPI(1), c.Sessions[0].AddressRange.NPI)
+
+	_, err = ParseJSON([]byte("{\"sessions\": [\n{\"name\": \"a\",, }]}"))
+	require.ErrorContains(t, err, "line 2, column 14")
+
+	_, err = ParseJSON([]byte(`{"sessions": [{"name": "a", "adress": "localhost:2775"}]}`))
+	require.ErrorContains(t, err, `unknown field "adress"`)
+}
+
+func TestParseErrors(t *testing.T) {
+	_, err := ParseYAML([]byte(`
+sessions:
+  - name: a
+    adress: localhost:2775
+`))
+	require.ErrorContains(t, err, "line 4: field adress not found")
+
+	_, err = ParseYAML([]byte(`
+sessions:
+  - name: a
+    address: localhost:2775
+    system_id: shop
+    address_range: {ton: worldwide}
+`))
+	require.ErrorContains(t, err, `invalid TON value "worldwide", expected one of abbreviated, alphanumeric`)
+
+	_, err = ParseYAML([]byte(`
+sessions:
+  - name: a
+    address: localhost:2775
+    enquire_link: 30s
+    window: {size: 300}
+    max_command_length: 100000
+  - name: a
+    bind: receiver
+    address: localhost:2775
+    system_id: shop
+pools:
+  - name: p
+    sessions: [a, b]
+routes:
+  - prefix: UK
+    pool: q
+`))
+	for _, problem := range []string{
+		`config: sessions[0] "a": system_id is required`,
+		`config: sessions[0] "a": read_timeout (10s) must be longer than enquire_link (30s)`,
+		`config: sessions[0] "a": window size 300 is out of range 1-255`,
+		`config: sessions[0] "a": max_command_length 100000 is out of range 0-65536`,
+		`config: sessions[1] "a": duplicate name`,
+		`config: pools[0] "p": unknown session "b"`,
+		`config: routes[0] "UK": prefix must be digits`,
+		`config: routes[0] "UK": unknown pool "q"`,
+	} {
+		require.ErrorContains(t, err, problem)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "smpp.yaml")
+	require.Nil(t, os.WriteFile(path, []byte(sample), 0o600))
+	c, err := Load(path)
+	require.Nil(t, err)
+	require.Len(t, c.Pools, 2)
+
+	path = filepath.Join(dir, "smpp.json")
+	require.Nil(t, os.WriteFile(path, []byte(`{"sessions": [{}]}`), 0o600))
+	_, err = Load(path)
+	require.ErrorContains(t, err, `sessions[0] "": name is required`)
+	require.ErrorContains(t, err, path)
+}
+
+func TestOpen(t *testing.T) {
+	smsc := smsctest.NewPipeServer(map[string]string{"shop": "secret"})
+	defer smsc.Close()
+
+	c, err := ParseYAML([]byte(`
+sessions:
+  - name: primary
+    address: pipe
+    system_id: shop
+    password: secret
+    labels: {carrier: acme}
+  - name: backup
+    bind: transmitter
+    address: pipe
+    system_id: shop
+    password: secret
+pools:
+  - name: uk
+    sessions: [primary, backup]
+  - name: other
+    sessions: [backup]
+routes:
+  - prefix: "+44"
+    pool: uk
+  - prefix: "447"
+    pool: other
+`))
+	require.Nil(t, err)
+
+	sessions, err := c.Open(WithDialer(smsc.Dialer()))
+	require.Nil(t, err)
+	defer func() {
+		require.Nil(t, sessions.Close())
+	}()
+
+	require.Equal(t, gosmpp.Labels{"session": "primary", "carrier": "acme"}, sessions.Sessions["primary"].Labels())
+	require.Len(t, sessions.Pools["uk"], 2)
+	require.Equal(t, sessions.Pools["other"], sessions.Route("+44 7700 900123"))
+	require.Equal(t, sessions.Pools["uk"], sessions.Route("+44 20 7946 0000"))
+	require.Nil(t, sessions.Route("+84901234567"))
+
+	h, err := sessions.Route("442079460000")[0].SendText(context.Background(), "MyShop", "+442079460000", "hi")
+	require.Nil(t, err)
+	require.Len(t, h.MessageIDs, 1)
+
+	c.Sessions[1].Password = "wrong"
+	_, err = c.Open(WithDialer(smsc.Dialer()))
+	require.ErrorContains(t, err, `config: opening session "backup"`)
+}
@@ -0,0 +1,258 @@
+package config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/linxGnu/gosmpp"
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+)
+
+// DefaultStoreTimeout is default store_timeout of windows.
+const DefaultStoreTimeout = time.Second
+
+// Option configures Config.Open.
+type Option func(*options)
+
+type options struct {
+	settings         gosmpp.Settings
+	dialer           gosmpp.Dialer
+	sessionOptions   []gosmpp.SessionOption
+	messengerOptions []gosmpp.MessengerOption
+}
+
+// WithSettings sets settings sessions start from, e.g. their callbacks. Values of the configuration
+// override them. Callbacks of WindowedRequestTracking are kept if the session has window.
+func WithSettings(settings gosmpp.Settings) Option {
+	return func(o *options) {
+		o.settings = settings
+	}
+}
+
+// WithDialer dials all sessions by d instead of TCP or TLS dialer of their configuration,
+// e.g. by smsctest.Server.Dialer in tests.
+func WithDialer(d gosmpp.Dialer) Option {
+	return func(o *options) {
+		o.dialer = d
+	}
+}
+
+// WithSessionOptions applies opts to all sessions, e.g. gosmpp.WithRequestStore.
+func WithSessionOptions(opts ...gosmpp.SessionOption) Option {
+	return func(o *options) {
+		o.sessionOptions = append(o.sessionOptions, opts...)
+	}
+}
+
+// WithMessengerOptions applies opts to messengers of all pools, e.g. gosmpp.WithDeliveryTracker.
+func WithMessengerOptions(opts ...gosmpp.MessengerOption) Option {
+	return func(o *options) {
+		o.messengerOptions = append(o.messengerOptions, opts...)
+	}
+}
+
+// Sessions are sessions opened by Config.Open with messengers of their pools.
+type Sessions struct {
+	// Sessions are by name.
+	Sessions map[string]*gosmpp.Session
+
+	// Pools are messengers of sessions of pools, by name.
+	Pools map[string][]*gosmpp.Messenger
+
+	routes []Route
+
+	// config is resolved configuration of sessions, by name, guarded by mu
+	mu     sync.Mutex
+	config map[string]Session
+}
+
+// Open binds all sessions and creates messengers of pools, resolving references first, see Resolve.
+// If any session fails to bind, the opened ones are closed and the error is returned.
+func (c *Config) Open(opts ...Option) (*Sessions, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	c, err := c.Resolve()
+	if err != nil {
+		return nil, err
+	}
+
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	s := &Sessions{
+		Sessions: make(map[string]*gosmpp.Session, len(c.Sessions)),
+		Pools:    make(map[string][]*gosmpp.Messenger, len(c.Pools)),
+		routes:   c.Routes,
+		config:   make(map[string]Session, len(c.Sessions)),
+	}
+	for _, sc := range c.Sessions {
+		session, err := sc.open(&o)
+		if err != nil {
+			_ = s.Close()
+			return nil, fmt.Errorf("config: opening session %q: %w", sc.Name, err)
+		}
+		s.Sessions[sc.Name] = session
+		s.config[sc.Name] = sc
+	}
+
+	for _, p := range c.Pools {
+		messengerOpts := []gosmpp.MessengerOption{gosmpp.WithServiceType(p.ServiceType)}
+		if p.RegisteredDelivery {
+			messengerOpts = append(messengerOpts, gosmpp.WithRegisteredDelivery(data.SM_SMSC_RECEIPT_REQUESTED))
+		}
+		messengerOpts = append(messengerOpts, o.messengerOptions...)
+
+		messengers := make([]*gosmpp.Messenger, len(p.Sessions))
+		for i, name := range p.Sessions {
+			messengers[i] = gosmpp.NewMessenger(s.Sessions[name], messengerOpts...)
+		}
+		s.Pools[p.Name] = messengers
+	}
+	return s, nil
+}
+
+// Route returns messengers of pool of the route with the longest prefix matching destination, nil if none
+// matches. Non-digits of destination are ignored, e.g. "+44 7700 900123" matches prefix "44".
+func (s *Sessions) Route(to string) []*gosmpp.Messenger {
+	digits := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, to)
+
+	best, pool := -1, ""
+	for _, r := range s.routes {
+		prefix := strings.TrimPrefix(r.Prefix, "+")
+		if len(prefix) > best && strings.HasPrefix(digits, prefix) {
+			best, pool = len(prefix), r.Pool
+		}
+	}
+	if best < 0 {
+		return nil
+	}
+	return s.Pools[pool]
+}
+
+// Close closes all sessions.
+func (s *Sessions) Close() error {
+	var errs []error
+	for _, session := range s.Sessions {
+		if err := session.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (sc Session) open(o *options) (*gosmpp.Session, error) {
+	dialer := o.dialer
+	if dialer == nil {
+		var err error
+		if dialer, err = sc.dialer(); err != nil {
+			return nil, err
+		}
+	}
+
+	auth := gosmpp.Auth{SMSC: sc.Address, SystemID: sc.SystemID, Password: sc.Password, SystemType: sc.SystemType}
+	var connectorOpts []gosmpp.ConnectorOption
+	if len(sc.Endpoints) > 0 {
+		connectorOpts = append(connectorOpts, gosmpp.WithEndpoints(sc.Endpoints...))
+	}
+	if r := sc.AddressRange; r != nil {
+		connectorOpts = append(connectorOpts,
+			gosmpp.WithAddressRange(pdu.NewAddressRangeWithTonNpiAddr(byte(r.TON), byte(r.NPI), r.Address)))
+	}
+
+	var connector gosmpp.Connector
+	switch bindingType, _ := sc.Bind.bindingType(); bindingType {
+	case pdu.Transmitter:
+		connector = gosmpp.TXConnector(dialer, auth, connectorOpts...)
+	case pdu.Receiver:
+		connector = gosmpp.RXConnector(dialer, auth, connectorOpts...)
+	default:
+		connector = gosmpp.TRXConnector(dialer, auth, connectorOpts...)
+	}
+
+	labels := gosmpp.Labels{"session": sc.Name}
+	for k, v := range sc.Labels {
+		labels[k] = v
+	}
+	sessionOpts := append([]gosmpp.SessionOption{gosmpp.WithLabels(labels)}, o.sessionOptions...)
+
+	return gosmpp.NewSession(connector, sc.settings(o.settings), time.Duration(sc.RebindInterval), sessionOpts...)
+}
+
+// settings returns base with values of the configuration.
+func (sc Session) settings(base gosmpp.Settings) gosmpp.Settings {
+	settings := base
+	settings.ReadTimeout = sc.readTimeout()
+	settings.WriteTimeout = time.Duration(sc.WriteTimeout)
+	settings.EnquireLink = time.Duration(sc.EnquireLink)
+	settings.MaxCommandLength = sc.MaxCommandLength
+
+	settings.WindowedRequestTracking = nil
+	if w := sc.Window; w != nil {
+		window := gosmpp.WindowedRequestTracking{}
+		if base.WindowedRequestTracking != nil {
+			window = *base.WindowedRequestTracking
+		}
+		window.MaxWindowSize = uint8(w.Size)
+		window.PduExpireTimeOut = time.Duration(w.ExpireTimeout)
+		window.ExpireCheckTimer = time.Duration(w.ExpireCheck)
+		if window.ExpireCheckTimer == 0 {
+			window.ExpireCheckTimer = window.PduExpireTimeOut / 2
+		}
+
+		// StoreAccessTimeOut is in milliseconds
+		storeTimeout := time.Duration(w.StoreTimeout)
+		if storeTimeout <= 0 {
+			storeTimeout = DefaultStoreTimeout
+		}
+		window.StoreAccessTimeOut = storeTimeout / time.Millisecond
+		if window.StoreAccessTimeOut == 0 {
+			window.StoreAccessTimeOut = 1
+		}
+		settings.WindowedRequestTracking = &window
+	}
+	return settings
+}
+
+func (sc Session) dialer() (gosmpp.Dialer, error) {
+	if sc.TLS == nil {
+		return gosmpp.NonTLSDialer, nil
+	}
+
+	config := &tls.Config{ServerName: sc.TLS.ServerName, InsecureSkipVerify: sc.TLS.InsecureSkipVerify}
+	if config.ServerName == "" && sc.Address != "" && len(sc.Endpoints) == 0 {
+		if host, _, err := net.SplitHostPort(sc.Address); err == nil {
+			config.ServerName = host
+		}
+	}
+	if sc.TLS.CAFile != "" {
+		pem, err := os.ReadFile(sc.TLS.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates in ca_file %s", sc.TLS.CAFile)
+		}
+		config.RootCAs = pool
+	}
+	return gosmpp.TLSDialer(config), nil
+}
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// Reload rotates credentials of sessions whose system_id or password changed in c, e.g. rotated password
+// in a secret file, see gosmpp.Session.RotateCredentials. References of c are resolved first.
+//
+// Other changes, e.g. of addresses or of sessions added or removed, take effect on restart only.
+// Errors of all sessions are returned joined.
+func (s *Sessions) Reload(ctx context.Context, c *Config) error {
+	resolved, err := c.Resolve()
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var errs []error
+	for _, sc := range resolved.Sessions {
+		current, ok := s.config[sc.Name]
+		if !ok || (current.SystemID == sc.SystemID && current.Password == sc.Password) {
+			continue
+		}
+		if err := s.Sessions[sc.Name].RotateCredentials(ctx, sc.SystemID, sc.Password); err != nil {
+			errs = append(errs, fmt.Errorf("config: rotating credentials of session %q: %w", sc.Name, err))
+			continue
+		}
+		current.SystemID, current.Password = sc.SystemID, sc.Password
+		s.config[sc.Name] = current
+	}
+	return errors.Join(errs...)
+}
+
+// ReloadOnSignal loads configuration from path and reloads it, see Reload, whenever the process receives
+// one of sigs, SIGHUP if none, until ctx is done. Errors of loading and reloading are passed to onError.
+//
+//	go sessions.ReloadOnSignal(ctx, "smpp.yaml", func(err error) { log.Println(err) })
+func (s *Sessions) ReloadOnSignal(ctx context.Context, path string, onError func(error), sigs ...os.Signal) {
+	if len(sigs) == 0 {
+		sigs = []os.Signal{syscall.SIGHUP}
+	}
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, sigs...)
+	defer signal.Stop(c)
+
+	s.reloadOn(ctx, c, path, onError)
+}
+
+func (s *Sessions) reloadOn(ctx context.Context, c <-chan os.Signal, path string, onError func(error)) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-c:
+		}
+
+		config, err := Load(path)
+		if err == nil {
+			err = s.Reload(ctx, config)
+		}
+		if err != nil && onError != nil {
+			onError(err)
+		}
+	}
+}
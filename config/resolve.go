@@ -0,0 +1,112 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Resolve returns copy of configuration with references in addresses and credentials of sessions replaced:
+//
+//	${NAME}          value of environment variable NAME, which must be set
+//	${NAME:-value}   value of environment variable NAME, or value if it is unset or empty
+//	${file:PATH}     content of file PATH without trailing newline, e.g. Docker or Kubernetes secret
+//	$$               literal $
+//
+// References are resolved in address, endpoints, system_id, password and system_type. Config.Open and
+// Sessions.Reload resolve them, so files are read again on reload.
+func (c *Config) Resolve() (*Config, error) {
+	resolved := *c
+	resolved.Sessions = make([]Session, len(c.Sessions))
+	for i, s := range c.Sessions {
+		var err error
+		for _, field := range []struct {
+			name  string
+			value *string
+		}{
+			{"address", &s.Address},
+			{"system_id", &s.SystemID},
+			{"password", &s.Password},
+			{"system_type", &s.SystemType},
+		} {
+			if *field.value, err = expand(*field.value); err != nil {
+				return nil, fmt.Errorf("config: sessions[%d] %q: %s: %w", i, s.Name, field.name, err)
+			}
+		}
+
+		endpoints := make([]string, len(s.Endpoints))
+		for j, endpoint := range s.Endpoints {
+			if endpoints[j], err = expand(endpoint); err != nil {
+				return nil, fmt.Errorf("config: sessions[%d] %q: endpoints[%d]: %w", i, s.Name, j, err)
+			}
+		}
+		if s.Endpoints != nil {
+			s.Endpoints = endpoints
+		}
+		resolved.Sessions[i] = s
+	}
+	return &resolved, nil
+}
+
+// expand replaces references in s, see Config.Resolve.
+func expand(s string) (string, error) {
+	if !strings.Contains(s, "$") {
+		return s, nil
+	}
+
+	var b strings.Builder
+	for {
+		i := strings.IndexByte(s, '$')
+		if i < 0 {
+			b.WriteString(s)
+			return b.String(), nil
+		}
+		b.WriteString(s[:i])
+		s = s[i:]
+
+		switch {
+		case strings.HasPrefix(s, "$$"):
+			b.WriteByte('$')
+			s = s[2:]
+
+		case strings.HasPrefix(s, "${"):
+			end := strings.IndexByte(s, '}')
+			if end < 0 {
+				return "", fmt.Errorf("unterminated reference %q", s)
+			}
+			value, err := resolve(s[2:end])
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(value)
+			s = s[end+1:]
+
+		default:
+			return "", fmt.Errorf("invalid reference %q, expected ${NAME}, ${file:PATH} or $$", s)
+		}
+	}
+}
+
+// resolve returns value of reference without ${ and }.
+func resolve(ref string) (string, error) {
+	if path := strings.TrimPrefix(ref, "file:"); path != ref {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimRight(string(b), "\r\n"), nil
+	}
+
+	name, fallback, hasFallback := strings.Cut(ref, ":-")
+	if name == "" {
+		return "", fmt.Errorf("invalid reference ${%s}, expected ${NAME}, ${NAME:-value} or ${file:PATH}", ref)
+	}
+	value, ok := os.LookupEnv(name)
+	switch {
+	case hasFallback && value == "":
+		return fallback, nil
+	case !ok:
+		return "", fmt.Errorf("environment variable %s is not set", name)
+	}
+	return value, nil
+}
@@ -0,0 +1,103 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp/server/smsctest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolve(t *testing.T) {
+	secret := filepath.Join(t.TempDir(), "password")
+	require.Nil(t, os.WriteFile(secret, []byte("s3cret\n"), 0o600))
+	t.Setenv("SMPP_HOST", "smsc.example.com")
+	t.Setenv("SMPP_USER", "shop")
+	t.Setenv("SMPP_EMPTY", "")
+
+	c := &Config{Sessions: []Session{{
+		Name:       "a",
+		Address:    "${SMPP_HOST}:2775",
+		Endpoints:  []string{"${SMPP_BACKUP:-backup.example.com}:2775"},
+		SystemID:   "${SMPP_USER}",
+		Password:   "${file:" + secret + "}",
+		SystemType: "$${SMPP_EMPTY:-x}${SMPP_EMPTY}",
+	}}}
+	resolved, err := c.Resolve()
+	require.Nil(t, err)
+	s := resolved.Sessions[0]
+	require.Equal(t, "smsc.example.com:2775", s.Address)
+	require.Equal(t, []string{"backup.example.com:2775"}, s.Endpoints)
+	require.Equal(t, "shop", s.SystemID)
+	require.Equal(t, "s3cret", s.Password)
+	require.Equal(t, "${SMPP_EMPTY:-x}", s.SystemType)
+
+	// the original is kept
+	require.Equal(t, "${SMPP_USER}", c.Sessions[0].SystemID)
+
+	for value, problem := range map[string]string{
+		"${SMPP_MISSING}":      "environment variable SMPP_MISSING is not set",
+		"${file:/nonexistent}": "open /nonexistent",
+		"${SMPP_USER":          "unterminated reference",
+		"$SMPP_USER":           "invalid reference",
+		"${:-default}":         "invalid reference",
+	} {
+		c.Sessions[0].Password = value
+		_, err = c.Resolve()
+		require.ErrorContains(t, err, `config: sessions[0] "a": password: `+problem)
+	}
+}
+
+func TestReload(t *testing.T) {
+	smsc := smsctest.NewPipeServer(map[string]string{"shop": "old", "shop2": "new"})
+	defer smsc.Close()
+
+	dir := t.TempDir()
+	secret := filepath.Join(dir, "password")
+	require.Nil(t, os.WriteFile(secret, []byte("old"), 0o600))
+	path := filepath.Join(dir, "smpp.yaml")
+	require.Nil(t, os.WriteFile(path, []byte(`
+sessions:
+  - name: a
+    address: pipe
+    system_id: ${SMPP_USER}
+    password: ${file:`+secret+`}
+`), 0o600))
+	t.Setenv("SMPP_USER", "shop")
+
+	c, err := Load(path)
+	require.Nil(t, err)
+	sessions, err := c.Open(WithDialer(smsc.Dialer()))
+	require.Nil(t, err)
+	defer func() {
+		_ = sessions.Close()
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	signals := make(chan os.Signal)
+	errs := make(chan error, 1)
+	go sessions.reloadOn(ctx, signals, path, func(err error) { errs <- err })
+
+	// unchanged credentials are not rotated
+	require.Nil(t, sessions.Reload(ctx, c))
+	require.Equal(t, int64(0), sessions.Sessions["a"].Stats().Rebinds)
+
+	// rejected credentials are reported and kept for next reload
+	require.Nil(t, os.WriteFile(secret, []byte("wrong"), 0o600))
+	signals <- os.Interrupt
+	require.ErrorContains(t, <-errs, `config: rotating credentials of session "a"`)
+
+	t.Setenv("SMPP_USER", "shop2")
+	require.Nil(t, os.WriteFile(secret, []byte("new"), 0o600))
+	signals <- os.Interrupt
+	require.Eventually(t, func() bool {
+		bound := smsc.Sessions()
+		return len(bound) == 1 && bound[0].SystemID() == "shop2"
+	}, 5*time.Second, time.Millisecond)
+	require.Empty(t, errs)
+}
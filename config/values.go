@@ -0,0 +1,134 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+)
+
+// Duration is time.Duration written as string in configuration, e.g. "10s" or "1m30s".
+type Duration time.Duration
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (d *Duration) UnmarshalText(b []byte) error {
+	v, err := time.ParseDuration(string(b))
+	if err != nil {
+		return fmt.Errorf("invalid duration %q, expected e.g. \"10s\" or \"1m30s\"", b)
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+var tons = map[string]byte{
+	"unknown":       data.GSM_TON_UNKNOWN,
+	"international": data.GSM_TON_INTERNATIONAL,
+	"national":      data.GSM_TON_NATIONAL,
+	"network":       data.GSM_TON_NETWORK,
+	"subscriber":    data.GSM_TON_SUBSCRIBER,
+	"alphanumeric":  data.GSM_TON_ALPHANUMERIC,
+	"abbreviated":   data.GSM_TON_ABBREVIATED,
+}
+
+var npis = map[string]byte{
+	"unknown":       data.GSM_NPI_UNKNOWN,
+	"isdn":          data.GSM_NPI_ISDN,
+	"e164":          data.GSM_NPI_E164,
+	"x121":          data.GSM_NPI_X121,
+	"telex":         data.GSM_NPI_TELEX,
+	"land_mobile":   data.GSM_NPI_LAND_MOBILE,
+	"national":      data.GSM_NPI_NATIONAL,
+	"private":       data.GSM_NPI_PRIVATE,
+	"ermes":         data.GSM_NPI_ERMES,
+	"internet":      data.GSM_NPI_INTERNET,
+	"wap_client_id": data.GSM_NPI_WAP_CLIENT_ID,
+}
+
+// TON is type of number, written by name, e.g. "international", or number in configuration.
+type TON byte
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (t *TON) UnmarshalText(b []byte) error {
+	v, err := parseByte("TON", string(b), tons, data.GSM_TON_ABBREVIATED)
+	*t = TON(v)
+	return err
+}
+
+// UnmarshalJSON implements json.Unmarshaler, accepting name or number.
+func (t *TON) UnmarshalJSON(b []byte) error {
+	return t.UnmarshalText(jsonText(b))
+}
+
+// NPI is numbering plan indicator, written by name, e.g. "isdn", or number in configuration.
+type NPI byte
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (n *NPI) UnmarshalText(b []byte) error {
+	v, err := parseByte("NPI", string(b), npis, data.GSM_NPI_WAP_CLIENT_ID)
+	*n = NPI(v)
+	return err
+}
+
+// UnmarshalJSON implements json.Unmarshaler, accepting name or number.
+func (n *NPI) UnmarshalJSON(b []byte) error {
+	return n.UnmarshalText(jsonText(b))
+}
+
+// jsonText returns JSON string unquoted, or other JSON value as is.
+func jsonText(b []byte) []byte {
+	var s string
+	if json.Unmarshal(b, &s) == nil {
+		return []byte(s)
+	}
+	return b
+}
+
+// parseByte parses name of names or number up to max.
+func parseByte(kind, s string, names map[string]byte, max byte) (byte, error) {
+	if v, ok := names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	if v, err := strconv.ParseUint(s, 0, 8); err == nil && byte(v) <= max {
+		return byte(v), nil
+	}
+
+	valid := make([]string, 0, len(names))
+	for name := range names {
+		valid = append(valid, name)
+	}
+	sort.Strings(valid)
+	return 0, fmt.Errorf("invalid %s value %q, expected one of %s or number up to %d",
+		kind, s, strings.Join(valid, ", "), max)
+}
+
+// BindType is type of bind: "transceiver", "transmitter" or "receiver".
+type BindType string
+
+// Types of bind.
+const (
+	Transceiver BindType = "transceiver"
+	Transmitter BindType = "transmitter"
+	Receiver    BindType = "receiver"
+)
+
+func (t BindType) bindingType() (pdu.BindingType, bool) {
+	switch t {
+	case Transceiver, "":
+		return pdu.Transceiver, true
+	case Transmitter:
+		return pdu.Transmitter, true
+	case Receiver:
+		return pdu.Receiver, true
+	}
+	return 0, false
+}
@@ -0,0 +1,219 @@
+package conformance
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+)
+
+// DefaultChecks are checks run by Run if none given.
+var DefaultChecks = []Check{
+	{
+		Name:        "bind",
+		Spec:        "4.1",
+		Description: "bind_transceiver is accepted with bind_transceiver_resp of its sequence_number",
+		Run:         checkBind,
+	},
+	{
+		Name:        "request_before_bind",
+		Spec:        "2.2",
+		Description: "submit_sm of open session is rejected with ESME_RINVBNDSTS",
+		Run:         checkRequestBeforeBind,
+	},
+	{
+		Name:        "already_bound",
+		Spec:        "4.1",
+		Description: "bind of bound session is rejected with ESME_RALYBND",
+		Run:         checkAlreadyBound,
+	},
+	{
+		Name:        "enquire_link",
+		Spec:        "4.11",
+		Description: "enquire_link is responded in time, repeatedly at interval",
+		Run:         checkEnquireLink,
+	},
+	{
+		Name:        "generic_nack",
+		Spec:        "4.3",
+		Description: "generic_nack from ESME is not responded and bind stays usable",
+		Run:         checkGenericNack,
+	},
+	{
+		Name:        "sequence_number",
+		Spec:        "3.2",
+		Description: "responses echo sequence_number up to 0x7FFFFFFF, out of range one does not break bind",
+		Run:         checkSequenceNumber,
+	},
+	{
+		Name:        "unbind",
+		Spec:        "4.2",
+		Description: "unbind is responded with unbind_resp, then SMSC closes connection",
+		Run:         checkUnbind,
+	},
+}
+
+func checkBind(t Target) error {
+	c, err := Dial(t)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = c.Close()
+	}()
+
+	resp, err := c.Call(c.BindRequest())
+	if err != nil {
+		return err
+	}
+	if err = expectResponse(resp, data.BIND_TRANSCEIVER_RESP, false); err != nil {
+		return err
+	}
+	return expectStatus(resp, data.ESME_ROK)
+}
+
+func checkRequestBeforeBind(t Target) error {
+	c, err := Dial(t)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = c.Close()
+	}()
+
+	submit := pdu.NewSubmitSM().(*pdu.SubmitSM)
+	_ = submit.DestAddr.SetAddress("447700900123")
+	_ = submit.Message.SetMessageWithEncoding("conformance", data.GSM7BIT)
+
+	resp, err := c.Call(submit)
+	if err != nil {
+		return err
+	}
+	if err = expectResponse(resp, data.SUBMIT_SM_RESP, true); err != nil {
+		return err
+	}
+	return expectStatus(resp, data.ESME_RINVBNDSTS)
+}
+
+func checkAlreadyBound(t Target) error {
+	c, err := Bind(t)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = c.Close()
+	}()
+
+	resp, err := c.Call(c.BindRequest())
+	if err != nil {
+		return err
+	}
+	if err = expectResponse(resp, data.BIND_TRANSCEIVER_RESP, true); err != nil {
+		return err
+	}
+	return expectStatus(resp, data.ESME_RALYBND)
+}
+
+func checkEnquireLink(t Target) error {
+	c, err := Bind(t)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = c.Close()
+	}()
+
+	for i := 0; i < EnquireLinkProbes; i++ {
+		if i > 0 {
+			time.Sleep(t.enquireLinkInterval())
+		}
+		if err = enquireLink(c, pdu.NewEnquireLink()); err != nil {
+			return fmt.Errorf("probe %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+func checkGenericNack(t Target) error {
+	c, err := Bind(t)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = c.Close()
+	}()
+
+	// generic_nack is response, of request SMSC never sent
+	nack := pdu.NewGenericNack()
+	if err = c.Send(nack); err != nil {
+		return err
+	}
+
+	// the next PDU must be response of enquire_link, not of generic_nack
+	return enquireLink(c, pdu.NewEnquireLink())
+}
+
+func checkSequenceNumber(t Target) error {
+	c, err := Bind(t)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = c.Close()
+	}()
+
+	for _, seq := range []int32{1, 0x7FFFFFFF} {
+		req := pdu.NewEnquireLink()
+		req.SetSequenceNumber(seq)
+		if err = enquireLink(c, req); err != nil {
+			return fmt.Errorf("sequence_number 0x%08X: %w", seq, err)
+		}
+	}
+
+	// sequence_number 0 is out of range: SMSC may respond as usual or with generic_nack, bind stays usable
+	req := pdu.NewEnquireLink()
+	req.SetSequenceNumber(0)
+	resp, err := c.Call(req)
+	if err == nil {
+		err = expectResponse(resp, data.ENQUIRE_LINK_RESP, true)
+	}
+	if err != nil {
+		return fmt.Errorf("sequence_number 0x00000000: %w", err)
+	}
+	return enquireLink(c, pdu.NewEnquireLink())
+}
+
+func checkUnbind(t Target) error {
+	c, err := Bind(t)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = c.Close()
+	}()
+
+	resp, err := c.Call(pdu.NewUnbind())
+	if err != nil {
+		return err
+	}
+	if err = expectResponse(resp, data.UNBIND_RESP, false); err != nil {
+		return err
+	}
+	if err = expectStatus(resp, data.ESME_ROK); err != nil {
+		return err
+	}
+	return c.Closed()
+}
+
+// enquireLink calls req, expecting successful enquire_link_resp.
+func enquireLink(c *Client, req pdu.PDU) error {
+	resp, err := c.Call(req)
+	if err != nil {
+		return err
+	}
+	if err = expectResponse(resp, data.ENQUIRE_LINK_RESP, false); err != nil {
+		return err
+	}
+	return expectStatus(resp, data.ESME_ROK)
+}
@@ -0,0 +1,166 @@
+package conformance
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"github.com/linxGnu/gosmpp"
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+)
+
+var (
+	// ErrNoResponse indicates SMSC did not respond in time.
+	ErrNoResponse = errors.New("conformance: no response in time")
+
+	// ErrClosed indicates SMSC closed the connection.
+	ErrClosed = errors.New("conformance: connection closed by SMSC")
+)
+
+// Client is raw connection to Target for checks, writing PDUs as they are: sequence numbers are not
+// assigned nor validated.
+type Client struct {
+	conn    *gosmpp.Connection
+	target  Target
+	timeout time.Duration
+}
+
+// Dial connects to target without binding.
+func Dial(target Target) (*Client, error) {
+	dialer := target.Dialer
+	if dialer == nil {
+		dialer = gosmpp.NonTLSDialer
+	}
+
+	conn, err := dialer(target.Addr)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{conn: gosmpp.NewConnection(conn), target: target, timeout: target.timeout()}, nil
+}
+
+// Bind connects to target and binds as transceiver.
+func Bind(target Target) (*Client, error) {
+	c, err := Dial(target)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.Call(c.BindRequest())
+	if err == nil {
+		err = expectStatus(resp, data.ESME_ROK)
+	}
+	if err != nil {
+		_ = c.Close()
+		return nil, fmt.Errorf("bind: %w", err)
+	}
+	return c, nil
+}
+
+// BindRequest returns bind_transceiver with credentials of target.
+func (c *Client) BindRequest() *pdu.BindRequest {
+	req := pdu.NewBindRequest(pdu.Transceiver)
+	req.SystemID = c.target.SystemID
+	req.Password = c.target.Password
+	req.SystemType = c.target.SystemType
+	return req
+}
+
+// Send writes p to SMSC.
+func (c *Client) Send(p pdu.PDU) error {
+	if err := c.conn.SetWriteTimeout(c.timeout); err != nil {
+		return err
+	}
+	_, err := c.conn.WritePDU(p)
+	return err
+}
+
+// Receive returns next PDU from SMSC, failing with ErrNoResponse if there is none in time and ErrClosed if
+// SMSC closed the connection. Requests of SMSC, e.g. enquire_link or deliver_sm, are responded with ESME_ROK
+// and skipped.
+func (c *Client) Receive() (pdu.PDU, error) {
+	deadline := time.Now().Add(c.timeout)
+	for {
+		if err := c.conn.SetReadDeadline(deadline); err != nil {
+			return nil, err
+		}
+
+		p, err := c.conn.ReadPDU()
+		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				return nil, ErrNoResponse
+			}
+			if errors.Is(err, io.EOF) {
+				return nil, ErrClosed
+			}
+			return nil, err
+		}
+
+		if isResponse(p) || !p.CanResponse() {
+			return p, nil
+		}
+		if err = c.Send(p.GetResponse()); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Call sends request p and returns the response, failing if the next PDU from SMSC does not respond to it.
+func (c *Client) Call(p pdu.PDU) (pdu.PDU, error) {
+	if err := c.Send(p); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.Receive()
+	if err != nil {
+		return nil, err
+	}
+	if resp.GetSequenceNumber() != p.GetSequenceNumber() {
+		return nil, fmt.Errorf("expected response of sequence_number %d, got %s", p.GetSequenceNumber(), pdu.Sprint(resp))
+	}
+	return resp, nil
+}
+
+// Closed waits for SMSC to close the connection, failing if it is not closed in time or sends another PDU.
+func (c *Client) Closed() error {
+	p, err := c.Receive()
+	switch {
+	case err == nil:
+		return fmt.Errorf("expected connection to be closed, got %s", pdu.Sprint(p))
+	case errors.Is(err, ErrNoResponse):
+		return errors.New("connection is not closed in time")
+	default:
+		// EOF or reset by SMSC
+		return nil
+	}
+}
+
+// Close closes the connection without unbinding.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// expectResponse fails unless resp is response of given command ID, or generic_nack if nack is set.
+func expectResponse(resp pdu.PDU, id data.CommandIDType, nack bool) error {
+	got := resp.GetHeader().CommandID
+	if got == id || nack && got == data.GENERIC_NACK {
+		return nil
+	}
+	return fmt.Errorf("expected %s, got %s", id, pdu.Sprint(resp))
+}
+
+// expectStatus fails unless resp has given command status.
+func expectStatus(resp pdu.PDU, status data.CommandStatusType) error {
+	if got := resp.GetHeader().CommandStatus; got != status {
+		return fmt.Errorf("expected command_status %s, got %s", status, got)
+	}
+	return nil
+}
+
+func isResponse(p pdu.PDU) bool {
+	return p.GetHeader().CommandID < 0
+}
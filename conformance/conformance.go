@@ -0,0 +1,152 @@
+// Package conformance checks SMSC against behaviors mandated by SMPP specification, e.g. responses to
+// enquire_link and unbind, and reports which of them pass.
+//
+// Checks run against any SMSC reachable by Target, a gosmpp server as well as external one:
+//
+//	report := conformance.Run(conformance.Target{Addr: "smsc:2775", SystemID: "169994", Password: "EDXPJU"})
+//	fmt.Print(report)
+//	if !report.Passed() {
+//		os.Exit(1)
+//	}
+//
+// Each check connects anew, so failure of one does not affect the others.
+package conformance
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/linxGnu/gosmpp"
+)
+
+const (
+	// DefaultTimeout is default time to wait for response of SMSC.
+	DefaultTimeout = 5 * time.Second
+
+	// DefaultEnquireLinkInterval is default interval of enquire_link probes.
+	DefaultEnquireLinkInterval = time.Second
+
+	// EnquireLinkProbes is number of enquire_link sent by the enquire_link check.
+	EnquireLinkProbes = 3
+)
+
+// Target is SMSC under test and account to bind with.
+type Target struct {
+	// Dialer connects to SMSC, gosmpp.NonTLSDialer by default.
+	Dialer gosmpp.Dialer
+
+	// Addr is address of SMSC.
+	Addr string
+
+	SystemID   string
+	Password   string
+	SystemType string
+
+	// Timeout is time to wait for every response of SMSC, DefaultTimeout by default.
+	Timeout time.Duration
+
+	// EnquireLinkInterval is interval of enquire_link probes, DefaultEnquireLinkInterval by default.
+	EnquireLinkInterval time.Duration
+}
+
+func (t Target) timeout() time.Duration {
+	if t.Timeout <= 0 {
+		return DefaultTimeout
+	}
+	return t.Timeout
+}
+
+func (t Target) enquireLinkInterval() time.Duration {
+	if t.EnquireLinkInterval <= 0 {
+		return DefaultEnquireLinkInterval
+	}
+	return t.EnquireLinkInterval
+}
+
+// Check is behavior of SMSC mandated by specification.
+type Check struct {
+	// Name identifies the check, e.g. "unbind".
+	Name string
+
+	// Spec is section of SMPP v3.4 specification mandating the behavior.
+	Spec string
+
+	// Description is the checked behavior.
+	Description string
+
+	// Run checks the behavior over its own connection to target, returning the violation if any.
+	Run func(t Target) error
+}
+
+// Result is result of a check.
+type Result struct {
+	Check   Check
+	Err     error
+	Elapsed time.Duration
+}
+
+// Passed reports whether the check passed.
+func (r Result) Passed() bool {
+	return r.Err == nil
+}
+
+// Report is results of checks, in the order of running.
+type Report struct {
+	Target  Target
+	Results []Result
+}
+
+// Passed reports whether all checks passed.
+func (r Report) Passed() bool {
+	for _, result := range r.Results {
+		if !result.Passed() {
+			return false
+		}
+	}
+	return true
+}
+
+// Failed returns results of failed checks.
+func (r Report) Failed() (failed []Result) {
+	for _, result := range r.Results {
+		if !result.Passed() {
+			failed = append(failed, result)
+		}
+	}
+	return
+}
+
+// String returns the report in lines of text, a line per check followed by the summary.
+func (r Report) String() string {
+	var b strings.Builder
+	for _, result := range r.Results {
+		status := "PASS"
+		if !result.Passed() {
+			status = "FAIL"
+		}
+		fmt.Fprintf(&b, "%s  %-20s %-6s %-10s %s", status, result.Check.Name, result.Check.Spec,
+			result.Elapsed.Round(time.Millisecond), result.Check.Description)
+		if !result.Passed() {
+			fmt.Fprintf(&b, ": %v", result.Err)
+		}
+		b.WriteByte('\n')
+	}
+	fmt.Fprintf(&b, "%d passed, %d failed\n", len(r.Results)-len(r.Failed()), len(r.Failed()))
+	return b.String()
+}
+
+// Run runs checks against target in order, DefaultChecks if none given.
+func Run(target Target, checks ...Check) Report {
+	if len(checks) == 0 {
+		checks = DefaultChecks
+	}
+
+	report := Report{Target: target, Results: make([]Result, 0, len(checks))}
+	for _, check := range checks {
+		start := time.Now()
+		err := check.Run(target)
+		report.Results = append(report.Results, Result{Check: check, Err: err, Elapsed: time.Since(start)})
+	}
+	return report
+}
@@ -0,0 +1,112 @@
+package conformance
+
+import (
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+	"github.com/linxGnu/gosmpp/server"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTarget(srv *server.Server) Target {
+	return Target{
+		Dialer:              srv.PipeDialer(),
+		Addr:                "smsc",
+		SystemID:            "acme",
+		Password:            "secret",
+		Timeout:             200 * time.Millisecond,
+		EnquireLinkInterval: 10 * time.Millisecond,
+	}
+}
+
+func TestRunServer(t *testing.T) {
+	srv := &server.Server{
+		Authenticator: server.AuthenticatorFunc(func(info server.BindInfo) data.CommandStatusType {
+			if info.SystemID != "acme" || info.Password != "secret" {
+				return data.ESME_RINVPASWD
+			}
+			return data.ESME_ROK
+		}),
+	}
+	defer func() {
+		_ = srv.Close()
+	}()
+
+	report := Run(newTarget(srv))
+	require.True(t, report.Passed(), report.String())
+	require.Len(t, report.Results, len(DefaultChecks))
+	require.Contains(t, report.String(), "PASS  unbind ")
+	require.True(t, strings.HasSuffix(report.String(), "7 passed, 0 failed\n"))
+}
+
+func TestRunViolations(t *testing.T) {
+	srv := &server.Server{
+		Interceptors: []server.Interceptor{{
+			Inbound: func(s *server.Session, p pdu.PDU, next func(pdu.PDU)) {
+				if _, ok := p.(*pdu.GenericNack); ok {
+					// responds to response
+					_ = s.Submit(pdu.NewGenericNack())
+					return
+				}
+				next(p)
+			},
+			Outbound: func(s *server.Session, p pdu.PDU, next func(pdu.PDU) error) error {
+				if _, ok := p.(*pdu.UnbindResp); ok {
+					return nil
+				}
+				return next(p)
+			},
+		}},
+	}
+	defer func() {
+		_ = srv.Close()
+	}()
+
+	// over TCP, as net.Pipe would block response to generic_nack while enquire_link is written
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+	go func() {
+		_ = srv.Serve(l)
+	}()
+
+	target := newTarget(srv)
+	target.Dialer, target.Addr = nil, l.Addr().String()
+
+	report := Run(target)
+	require.False(t, report.Passed())
+
+	failed := report.Failed()
+	require.Len(t, failed, 2)
+	require.Equal(t, "generic_nack", failed[0].Check.Name)
+	require.ErrorContains(t, failed[0].Err, "expected response of sequence_number")
+	require.Equal(t, "unbind", failed[1].Check.Name)
+	require.ErrorIs(t, failed[1].Err, ErrClosed)
+	require.Contains(t, report.String(), "FAIL  unbind ")
+}
+
+func TestRunChecks(t *testing.T) {
+	srv := &server.Server{}
+	defer func() {
+		_ = srv.Close()
+	}()
+
+	violation := errors.New("violation")
+	report := Run(newTarget(srv), Check{Name: "custom", Run: func(t Target) error {
+		c, err := Bind(t)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = c.Close()
+		}()
+		return violation
+	}})
+	require.Len(t, report.Results, 1)
+	require.ErrorIs(t, report.Results[0].Err, violation)
+}
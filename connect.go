@@ -1,8 +1,10 @@
 package gosmpp
 
 import (
+	"crypto/tls"
 	"fmt"
 	"net"
+	"sync"
 
 	"github.com/linxGnu/gosmpp/data"
 	"github.com/linxGnu/gosmpp/pdu"
@@ -15,6 +17,13 @@ var (
 	}
 )
 
+// TLSDialer returns dialer connecting over TLS with given config.
+func TLSDialer(config *tls.Config) Dialer {
+	return func(addr string) (net.Conn, error) {
+		return tls.Dial("tcp", addr, config)
+	}
+}
+
 // Dialer is connection dialer.
 type Dialer func(addr string) (net.Conn, error)
 
@@ -50,11 +59,20 @@ type Connector interface {
 	GetBindType() pdu.BindingType
 }
 
+// CredentialsUpdater is implemented by Connector(s) which support changing
+// credentials used for subsequent binds.
+type CredentialsUpdater interface {
+	Credentials() (systemID, password string)
+	SetCredentials(systemID, password string)
+}
+
 type connector struct {
 	dialer       Dialer
 	auth         Auth
+	authLock     sync.RWMutex
 	bindingType  pdu.BindingType
 	addressRange pdu.AddressRange
+	endpoints    *endpoints
 }
 
 func (c *connector) GetBindType() pdu.BindingType {
@@ -62,10 +80,34 @@ func (c *connector) GetBindType() pdu.BindingType {
 }
 
 func (c *connector) Connect() (conn *Connection, err error) {
-	conn, err = connect(c.dialer, c.auth.SMSC, newBindRequest(c.auth, c.bindingType, c.addressRange))
+	c.authLock.RLock()
+	auth := c.auth
+	c.authLock.RUnlock()
+
+	bindReq := newBindRequest(auth, c.bindingType, c.addressRange)
+	if c.endpoints != nil {
+		return c.endpoints.connect(c.dialer, auth.SMSC, bindReq)
+	}
+
+	conn, err = connect(c.dialer, auth.SMSC, bindReq)
+	return
+}
+
+// Credentials returns system_id and password used for binding.
+func (c *connector) Credentials() (systemID, password string) {
+	c.authLock.RLock()
+	systemID, password = c.auth.SystemID, c.auth.Password
+	c.authLock.RUnlock()
 	return
 }
 
+// SetCredentials sets system_id and password for next binds.
+func (c *connector) SetCredentials(systemID, password string) {
+	c.authLock.Lock()
+	c.auth.SystemID, c.auth.Password = systemID, password
+	c.authLock.Unlock()
+}
+
 func connect(dialer Dialer, addr string, bindReq *pdu.BindRequest) (c *Connection, err error) {
 	conn, err := dialer(addr)
 	if err != nil {
@@ -89,7 +131,7 @@ func connect(dialer Dialer, addr string, bindReq *pdu.BindRequest) (c *Connectio
 	)
 
 	for {
-		if p, err = pdu.Parse(c); err != nil {
+		if p, err = c.ReadPDU(); err != nil {
 			_ = conn.Close()
 			return
 		}
@@ -111,16 +153,20 @@ func connect(dialer Dialer, addr string, bindReq *pdu.BindRequest) (c *Connectio
 }
 
 // TXConnector returns a Transmitter (TX) connector.
-func TXConnector(dialer Dialer, auth Auth) Connector {
-	return &connector{
+func TXConnector(dialer Dialer, auth Auth, opts ...ConnectorOption) Connector {
+	c := &connector{
 		dialer:      dialer,
 		auth:        auth,
 		bindingType: pdu.Transmitter,
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 // RXConnector returns a Receiver (RX) connector.
-func RXConnector(dialer Dialer, auth Auth, opts ...connectorOption) Connector {
+func RXConnector(dialer Dialer, auth Auth, opts ...ConnectorOption) Connector {
 	c := &connector{
 		dialer:      dialer,
 		auth:        auth,
@@ -133,7 +179,7 @@ func RXConnector(dialer Dialer, auth Auth, opts ...connectorOption) Connector {
 }
 
 // TRXConnector returns a Transceiver (TRX) connector.
-func TRXConnector(dialer Dialer, auth Auth, opts ...connectorOption) Connector {
+func TRXConnector(dialer Dialer, auth Auth, opts ...ConnectorOption) Connector {
 	c := &connector{
 		dialer:      dialer,
 		auth:        auth,
@@ -145,9 +191,10 @@ func TRXConnector(dialer Dialer, auth Auth, opts ...connectorOption) Connector {
 	return c
 }
 
-type connectorOption func(c *connector)
+// ConnectorOption configures Connector, e.g. WithEndpoints.
+type ConnectorOption func(c *connector)
 
-func WithAddressRange(addressRange pdu.AddressRange) connectorOption {
+func WithAddressRange(addressRange pdu.AddressRange) ConnectorOption {
 	return func(c *connector) {
 		c.addressRange = addressRange
 	}
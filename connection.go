@@ -13,6 +13,8 @@ type Connection struct {
 	systemID string
 	conn     net.Conn
 	reader   *bufio.Reader
+	limits   pdu.DecodeLimits
+	pool     *pdu.Pool
 }
 
 // NewConnection returns a Connection.
@@ -32,6 +34,27 @@ func (c *Connection) Read(b []byte) (n int, err error) {
 	return
 }
 
+// ReadPDU reads PDU from the connection. PDU is decoded within the read buffer, which is reused for following
+// PDUs: the decoded PDU holds copies of its fields only, so it could be retained.
+//
+// PDU exceeding limits set by SetDecodeLimits is skipped, returning *pdu.TLVLimitError or *pdu.SizeError: reading
+// could go on.
+func (c *Connection) ReadPDU() (pdu.PDU, error) {
+	return c.limits.ParseBufferedPooled(c.reader, c.pool)
+}
+
+// SetDecodeLimits sets limits of PDUs read by ReadPDU, MaxCommandLength of them caps PDUs written by WritePDU too.
+// It must not be called concurrently with ReadPDU or WritePDU.
+func (c *Connection) SetDecodeLimits(limits pdu.DecodeLimits) {
+	c.limits = limits
+}
+
+// SetPool makes ReadPDU get PDUs from pool, the caller releases them. Nil pool, the default, allocates PDUs.
+// It must not be called concurrently with ReadPDU.
+func (c *Connection) SetPool(pool *pdu.Pool) {
+	c.pool = pool
+}
+
 // Write writes data to the connection.
 // Write can be made to time out and return an Error with Timeout() == true
 // after a fixed time limit; see SetDeadline and SetWriteDeadline.
@@ -40,10 +63,14 @@ func (c *Connection) Write(b []byte) (n int, err error) {
 	return
 }
 
-// WritePDU data to the connection.
+// WritePDU data to the connection. PDU longer than MaxCommandLength of decode limits is not written,
+// returning *pdu.SizeError.
 func (c *Connection) WritePDU(p pdu.PDU) (n int, err error) {
 	buf := pdu.NewBuffer(make([]byte, 0, 64))
 	p.Marshal(buf)
+	if err = pdu.CheckLength(buf.Bytes(), c.limits.MaxCommandLength); err != nil {
+		return
+	}
 	n, err = c.conn.Write(buf.Bytes())
 	return
 }
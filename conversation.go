@@ -0,0 +1,214 @@
+package gosmpp
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/linxGnu/gosmpp/clock"
+)
+
+// DefaultConversationTimeout is default inactivity timeout of conversations.
+const DefaultConversationTimeout = 15 * time.Minute
+
+// Conversation is exchange of messages between a short code and a subscriber, from the first message until
+// it ends or times out.
+type Conversation struct {
+	// ShortCode and Subscriber are addresses of the parties, as in IncomingMessage.To and From.
+	ShortCode, Subscriber string
+
+	// Step is number of handled subscriber messages before the current one, zero when conversation begins.
+	Step int
+
+	// Received and Sent are numbers of messages received from and sent to subscriber.
+	Received, Sent int
+
+	// Started is time the conversation began.
+	Started time.Time
+
+	// Values is state of the conversation, kept between messages.
+	Values map[string]interface{}
+
+	owner    *Conversations
+	key      conversationKey
+	handling sync.Mutex
+	timer    clock.Timer
+	ended    bool
+}
+
+type conversationKey struct {
+	shortCode, subscriber string
+}
+
+// Reply sends text to subscriber from the short code, counting it as activity of the conversation.
+func (c *Conversation) Reply(ctx context.Context, text string) (*MessageHandle, error) {
+	c.owner.touch(c, func() { c.Sent++ })
+	return c.owner.messenger.SendText(ctx, c.ShortCode, c.Subscriber, text)
+}
+
+// End ends the conversation, next subscriber message begins new one.
+func (c *Conversation) End() {
+	c.owner.end(c)
+}
+
+// ConversationHandler handles subscriber message of conversation.
+type ConversationHandler func(c *Conversation, m IncomingMessage)
+
+// Conversations tracks conversations of short codes with subscribers, passing subscriber messages to handler
+// with their conversation, e.g. to implement menu-like services:
+//
+//	conversations := gosmpp.NewConversations(messenger, func(c *gosmpp.Conversation, m gosmpp.IncomingMessage) {
+//		if c.Step == 0 {
+//			_, _ = c.Reply(ctx, "Reply 1 for balance, 2 for top up")
+//			return
+//		}
+//		_, _ = c.Reply(ctx, "Thank you")
+//		c.End()
+//	})
+//	settings.OnMessage = conversations.HandleMessage
+//
+// Messages of the same conversation are handled one at a time. Conversations inactive longer than timeout end.
+type Conversations struct {
+	messenger *Messenger
+	handler   ConversationHandler
+	timeout   time.Duration
+	onTimeout func(*Conversation)
+	clock     clock.Clock
+
+	mu            sync.Mutex
+	conversations map[conversationKey]*Conversation
+}
+
+// ConversationsOption configures Conversations.
+type ConversationsOption func(*Conversations)
+
+// WithConversationTimeout sets inactivity timeout of conversations, DefaultConversationTimeout by default.
+func WithConversationTimeout(timeout time.Duration) ConversationsOption {
+	return func(cs *Conversations) {
+		cs.timeout = timeout
+	}
+}
+
+// WithConversationTimeoutHandler sets function called with conversations ended by timeout.
+func WithConversationTimeoutHandler(onTimeout func(*Conversation)) ConversationsOption {
+	return func(cs *Conversations) {
+		cs.onTimeout = onTimeout
+	}
+}
+
+// WithConversationClock sets clock of conversation timeouts, real clock by default.
+func WithConversationClock(c clock.Clock) ConversationsOption {
+	return func(cs *Conversations) {
+		cs.clock = c
+	}
+}
+
+// NewConversations returns Conversations replying to subscribers by messenger.
+func NewConversations(messenger *Messenger, handler ConversationHandler, opts ...ConversationsOption) *Conversations {
+	cs := &Conversations{
+		messenger:     messenger,
+		handler:       handler,
+		timeout:       DefaultConversationTimeout,
+		conversations: make(map[conversationKey]*Conversation),
+	}
+	for _, opt := range opts {
+		opt(cs)
+	}
+	cs.clock = clock.OrReal(cs.clock)
+	return cs
+}
+
+// HandleMessage passes subscriber message to handler with its conversation, beginning new one if there is none.
+func (cs *Conversations) HandleMessage(m IncomingMessage) {
+	c := cs.conversation(conversationKey{shortCode: m.To, subscriber: m.From})
+
+	c.handling.Lock()
+	defer c.handling.Unlock()
+
+	cs.touch(c, func() { c.Received++ })
+	cs.handler(c, m)
+	cs.touch(c, func() { c.Step++ })
+}
+
+// Start begins conversation by sending text to subscriber from short code, ending the current one if any.
+func (cs *Conversations) Start(ctx context.Context, shortCode, subscriber, text string) (*Conversation, *MessageHandle, error) {
+	key := conversationKey{shortCode: shortCode, subscriber: subscriber}
+	if c := cs.Get(shortCode, subscriber); c != nil {
+		c.End()
+	}
+
+	c := cs.conversation(key)
+	h, err := c.Reply(ctx, text)
+	return c, h, err
+}
+
+// Get returns active conversation of short code with subscriber, nil if none.
+func (cs *Conversations) Get(shortCode, subscriber string) *Conversation {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	return cs.conversations[conversationKey{shortCode: shortCode, subscriber: subscriber}]
+}
+
+// Len returns number of active conversations.
+func (cs *Conversations) Len() int {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	return len(cs.conversations)
+}
+
+// conversation returns active conversation of key, beginning new one if there is none.
+func (cs *Conversations) conversation(key conversationKey) *Conversation {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	if c, ok := cs.conversations[key]; ok {
+		return c
+	}
+
+	c := &Conversation{
+		ShortCode:  key.shortCode,
+		Subscriber: key.subscriber,
+		Started:    cs.clock.Now(),
+		Values:     make(map[string]interface{}),
+		owner:      cs,
+		key:        key,
+	}
+	c.timer = cs.clock.AfterFunc(cs.timeout, func() {
+		if cs.remove(c) && cs.onTimeout != nil {
+			cs.onTimeout(c)
+		}
+	})
+	cs.conversations[key] = c
+	return c
+}
+
+// touch updates conversation by fn and restarts its timeout, unless it is ended.
+func (cs *Conversations) touch(c *Conversation, fn func()) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	fn()
+	if !c.ended {
+		c.timer.Reset(cs.timeout)
+	}
+}
+
+// end removes conversation, stopping its timeout.
+func (cs *Conversations) end(c *Conversation) {
+	if cs.remove(c) {
+		c.timer.Stop()
+	}
+}
+
+// remove removes conversation, reporting whether it was not removed already.
+func (cs *Conversations) remove(c *Conversation) bool {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	if c.ended {
+		return false
+	}
+	c.ended = true
+	delete(cs.conversations, c.key)
+	return true
+}
@@ -0,0 +1,93 @@
+package gosmpp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp/clock"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConversations(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	fake := clock.NewFake(time.Now())
+	timedOut := make(chan *Conversation, 1)
+
+	var replyErr error
+	cs := NewConversations(newTestMessenger(t), func(c *Conversation, m IncomingMessage) {
+		switch c.Step {
+		case 0:
+			c.Values["item"] = m.Text
+			_, replyErr = c.Reply(ctx, "How many?")
+		default:
+			_, replyErr = c.Reply(ctx, "Ordered "+m.Text+" "+c.Values["item"].(string))
+			c.End()
+		}
+	}, WithConversationClock(fake), WithConversationTimeout(time.Minute), WithConversationTimeoutHandler(func(c *Conversation) {
+		timedOut <- c
+	}))
+
+	mo := IncomingMessage{From: "+84901234567", To: "8888", Text: "pizza"}
+	cs.HandleMessage(mo)
+	require.Nil(t, replyErr)
+
+	c := cs.Get("8888", "+84901234567")
+	require.NotNil(t, c)
+	require.Equal(t, 1, c.Step)
+	require.Equal(t, 1, c.Received)
+	require.Equal(t, 1, c.Sent)
+
+	// activity restarts timeout
+	fake.Advance(50 * time.Second)
+	mo.Text = "2"
+	cs.HandleMessage(mo)
+	require.Nil(t, replyErr)
+	require.Equal(t, 2, c.Sent)
+	require.Nil(t, cs.Get("8888", "+84901234567"))
+
+	// next message begins new conversation, which times out
+	mo.Text = "pasta"
+	cs.HandleMessage(mo)
+	c = cs.Get("8888", "+84901234567")
+	require.Equal(t, 1, c.Step)
+
+	fake.Advance(50 * time.Second)
+	require.Equal(t, 1, cs.Len())
+	fake.Advance(10 * time.Second)
+	select {
+	case ended := <-timedOut:
+		require.Same(t, c, ended)
+	case <-time.After(time.Second):
+		t.Fatal("conversation is not timed out")
+	}
+	require.Equal(t, 0, cs.Len())
+}
+
+func TestConversationsStart(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var steps []int
+	cs := NewConversations(newTestMessenger(t), func(c *Conversation, m IncomingMessage) {
+		steps = append(steps, c.Step)
+	})
+
+	c, h, err := cs.Start(ctx, "8888", "+84901234567", "Rate our service from 1 to 5")
+	require.Nil(t, err)
+	require.Len(t, h.MessageIDs, 1)
+	require.Equal(t, 1, c.Sent)
+
+	cs.HandleMessage(IncomingMessage{From: "+84901234567", To: "8888", Text: "5"})
+	require.Equal(t, []int{0}, steps)
+	require.Equal(t, 1, c.Received)
+
+	// starting again ends the current conversation
+	again, _, err := cs.Start(ctx, "8888", "+84901234567", "Rate our service from 1 to 5")
+	require.Nil(t, err)
+	require.NotSame(t, c, again)
+	require.Equal(t, 1, cs.Len())
+}
@@ -0,0 +1,71 @@
+package gosmpp
+
+import (
+	"context"
+
+	"github.com/linxGnu/gosmpp/pdu"
+)
+
+// Correlation is opaque caller data attached to submitted request, e.g. id of the order the message belongs to.
+//
+// It is carried with the request through queueing and retries, and comes back with its Call, response in
+// WindowedRequestTracking callbacks, events, latency metrics and, for messages sent by Messenger,
+// MessageHandle and Delivery of its receipts.
+type Correlation struct {
+	// ID identifies the request for the caller.
+	ID string
+
+	// Metadata is any other caller data, it should not be modified once attached.
+	Metadata map[string]string
+}
+
+// IsZero reports whether correlation carries no data.
+func (c Correlation) IsZero() bool {
+	return c.ID == "" && len(c.Metadata) == 0
+}
+
+func (c Correlation) clone() Correlation {
+	if c.Metadata != nil {
+		metadata := make(map[string]string, len(c.Metadata))
+		for k, v := range c.Metadata {
+			metadata[k] = v
+		}
+		c.Metadata = metadata
+	}
+	return c
+}
+
+// WithCorrelation attaches correlation to the call, see Call.Correlation.
+func WithCorrelation(c Correlation) CallOption {
+	return func(o *callOptions) {
+		o.correlation = c
+	}
+}
+
+type correlationKey struct{}
+
+// ContextWithCorrelation returns ctx carrying correlation of messages sent by Messenger with it:
+//
+//	ctx = gosmpp.ContextWithCorrelation(ctx, gosmpp.Correlation{ID: orderID})
+//	h, err := messenger.SendText(ctx, "MyShop", "+447700900123", "Your order is shipped")
+func ContextWithCorrelation(ctx context.Context, c Correlation) context.Context {
+	return context.WithValue(ctx, correlationKey{}, c)
+}
+
+// CorrelationFromContext returns correlation carried by ctx, zero if none.
+func CorrelationFromContext(ctx context.Context) Correlation {
+	c, _ := ctx.Value(correlationKey{}).(Correlation)
+	return c
+}
+
+// correlationOf returns correlation of pending call of request p, zero if there is none.
+func (r *callRegistry) correlationOf(p pdu.PDU) Correlation {
+	if r == nil || p == nil {
+		return Correlation{}
+	}
+
+	if c, _ := r.calls.load(p.GetSequenceNumber()); c != nil && c.PDU == p {
+		return c.Correlation
+	}
+	return Correlation{}
+}
@@ -0,0 +1,107 @@
+package gosmpp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCorrelationContext(t *testing.T) {
+	require.True(t, CorrelationFromContext(context.Background()).IsZero())
+
+	c := Correlation{ID: "order-1", Metadata: map[string]string{"tenant": "acme"}}
+	ctx := ContextWithCorrelation(context.Background(), c)
+	require.Equal(t, c, CorrelationFromContext(ctx))
+	require.False(t, c.IsZero())
+	require.False(t, Correlation{Metadata: map[string]string{"k": "v"}}.IsZero())
+}
+
+func TestCallCorrelation(t *testing.T) {
+	auth := nextAuth()
+	s, err := NewSession(TRXConnector(NonTLSDialer, auth), Settings{ReadTimeout: 2 * time.Second}, -1)
+	require.Nil(t, err)
+	defer func() {
+		_ = s.Close()
+	}()
+
+	metadata := map[string]string{"tenant": "acme"}
+	c := s.SubmitAsync(newSubmitSM(auth.SystemID), WithCorrelation(Correlation{ID: "order-1", Metadata: metadata}))
+	metadata["tenant"] = "other"
+
+	_, err = c.Wait(context.Background())
+	require.Nil(t, err)
+	require.Equal(t, Correlation{ID: "order-1", Metadata: map[string]string{"tenant": "acme"}}, c.Correlation)
+	require.Equal(t, "order-1", s.Metrics(MaxMetricsWindow).LatencyMaxCorrelation.ID)
+}
+
+func TestCorrelationRetried(t *testing.T) {
+	auth := nextAuth()
+	s, err := NewSession(TRXConnector(NonTLSDialer, auth), Settings{ReadTimeout: 2 * time.Second}, -1,
+		WithDestinationOrdering(1, 0))
+	require.Nil(t, err)
+	defer func() {
+		_ = s.Close()
+	}()
+
+	c := s.SubmitAsync(newSubmitSM(auth.SystemID), WithCorrelation(Correlation{ID: "order-1"}))
+	_, err = c.Wait(context.Background())
+	require.Nil(t, err)
+
+	c.mu.Lock()
+	inner := c.inner
+	c.mu.Unlock()
+	require.NotNil(t, inner)
+	require.Equal(t, "order-1", inner.Correlation.ID)
+}
+
+func TestLinkStatsCorrelation(t *testing.T) {
+	var (
+		calls  callRegistry
+		events eventBus
+		got    []Event
+	)
+	events.subscribe(func(e Event) { got = append(got, e) })
+	stats := &linkStats{calls: &calls, events: &events}
+
+	throttled := newCall(pdu.NewSubmitSM())
+	throttled.Correlation = Correlation{ID: "throttled"}
+	calls.add(throttled)
+	full := newCall(pdu.NewSubmitSM())
+	full.Correlation = Correlation{ID: "full"}
+	calls.add(full)
+	require.Equal(t, "full", stats.correlationOf(full.PDU).ID)
+
+	resp := throttled.PDU.GetResponse().(*pdu.SubmitSMResp)
+	resp.CommandStatus = data.ESME_RTHROTTLED
+	stats.onReceived(resp)
+	stats.onSubmitError(full.PDU, ErrWindowsFull)
+
+	require.Len(t, got, 2)
+	require.Equal(t, EventThrottled, got[0].Type)
+	require.Equal(t, "throttled", got[0].Correlation.ID)
+	require.Equal(t, EventWindowFull, got[1].Type)
+	require.Equal(t, "full", got[1].Correlation.ID)
+
+	require.True(t, stats.correlationOf(full.PDU).IsZero())
+	require.True(t, (*linkStats)(nil).correlationOf(full.PDU).IsZero())
+}
+
+func TestMessengerCorrelation(t *testing.T) {
+	tracker := NewDeliveryTracker(nil)
+	m := newTestMessenger(t, WithDeliveryTracker(tracker))
+
+	ctx := ContextWithCorrelation(context.Background(), Correlation{ID: "order-1"})
+	h, err := m.SendText(ctx, "MyShop", "+84901234567", "Your order is shipped")
+	require.Nil(t, err)
+	require.Equal(t, "order-1", h.Correlation.ID)
+
+	d, found, err := tracker.Status(context.Background(), h.ID)
+	require.Nil(t, err)
+	require.True(t, found)
+	require.Equal(t, "order-1", d.Correlation.ID)
+}
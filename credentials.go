@@ -0,0 +1,122 @@
+package gosmpp
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/linxGnu/gosmpp/clock"
+)
+
+var (
+	// ErrCredentialsRotationNotSupported indicates that session's Connector does not implement CredentialsUpdater.
+	ErrCredentialsRotationNotSupported = errors.New("connector does not support credentials rotation")
+
+	// ErrSessionRebinding indicates that session is busy rebinding.
+	ErrSessionRebinding = errors.New("session is rebinding")
+)
+
+const drainCheckInterval = 10 * time.Millisecond
+
+// RotateCredentials replaces system_id/password of the session without losing messages.
+//
+// Submit(s) through Session.Submit are held during rotation, then:
+//  1. outstanding requests on current bind are drained (waiting for their responses)
+//  2. current bind is unbound
+//  3. session binds again with new credentials and held Submit(s) are resumed
+//
+// Responses are waited for as long as requests live: PduExpireTimeOut of WindowedRequestTracking if set,
+// ReadTimeout otherwise; requests not responded by then are given up as expired. If ctx is done or session
+// is closed before the window is drained, rotation is aborted and the current bind is kept.
+// If binding with new credentials fails, session rebinds with the previous credentials and
+// the bind error is returned.
+func (s *Session) RotateCredentials(ctx context.Context, systemID, password string) (err error) {
+	updater, ok := s.c.(CredentialsUpdater)
+	if !ok {
+		return ErrCredentialsRotationNotSupported
+	}
+
+	s.submitGate.Lock()
+	defer s.submitGate.Unlock()
+
+	if atomic.LoadInt32(&s.state) != Alive {
+		return ErrConnectionClosing
+	}
+	if !atomic.CompareAndSwapInt32(&s.rebinding, 0, 1) {
+		return ErrSessionRebinding
+	}
+
+	old := s.bound()
+	if err = s.drain(ctx, old); err != nil {
+		atomic.StoreInt32(&s.rebinding, 0)
+		return
+	}
+
+	// unbind and close current bind
+	_ = old.Close()
+
+	prevSystemID, prevPassword := updater.Credentials()
+	updater.SetCredentials(systemID, password)
+
+	conn, err := s.c.Connect()
+	if err != nil {
+		// rollback to previous credentials
+		updater.SetCredentials(prevSystemID, prevPassword)
+
+		var rollbackErr error
+		if conn, rollbackErr = s.c.Connect(); rollbackErr != nil {
+			if s.settings.OnRebindingError != nil {
+				s.settings.OnRebindingError(rollbackErr)
+			}
+
+			atomic.StoreInt32(&s.rebinding, 0)
+			if s.rebindingInterval > 0 {
+				go s.rebind()
+			}
+			return
+		}
+	}
+
+	s.bind(conn)
+	s.counters.inc(&s.counters.rebinds, MetricRebinds)
+	atomic.StoreInt32(&s.rebinding, 0)
+	s.events.publish(Event{Type: EventReconnected})
+	s.updatePressure()
+
+	if s.settings.OnRebind != nil {
+		s.settings.OnRebind()
+	}
+	return
+}
+
+// drain waits until all outstanding requests on given bind are responded, expired or the bind is lost.
+func (s *Session) drain(ctx context.Context, b *transceivable) error {
+	clk := clock.OrReal(s.settings.Clock)
+	ticker := clk.NewTicker(drainCheckInterval)
+	defer ticker.Stop()
+
+	timeout := s.settings.ReadTimeout
+	if s.settings.WindowedRequestTracking != nil && s.settings.PduExpireTimeOut > 0 {
+		timeout = s.settings.PduExpireTimeOut
+	}
+	expired := clk.NewTimer(timeout)
+	defer expired.Stop()
+
+	for {
+		switch {
+		case atomic.LoadInt32(&s.state) != Alive:
+			return ErrConnectionClosing
+		case b.stats.outstanding() == 0 || atomic.LoadInt32(&b.aliveState) != Alive:
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-expired.C():
+			return nil
+		case <-ticker.C():
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package gosmpp_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp"
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/server/smsctest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotateCredentialsUnansweredRequest(t *testing.T) {
+	smsc := smsctest.NewPipeServer(nil)
+	defer smsc.Close()
+
+	s, err := gosmpp.NewSession(gosmpp.TRXConnector(smsc.Dialer(), gosmpp.Auth{SMSC: "pipe", SystemID: "esme"}),
+		gosmpp.Settings{ReadTimeout: 200 * time.Millisecond}, -1)
+	require.Nil(t, err)
+	defer func() {
+		_ = s.Close()
+	}()
+
+	// request never answered is given up after ReadTimeout, even without deadline of ctx
+	smsc.SetFault(smsctest.Script(smsctest.Fault{Drop: true}))
+	require.Nil(t, s.Submit(newTextSubmitSM("1", "dropped")))
+	smsc.ExpectReceived(t, data.SUBMIT_SM, 1)
+
+	start := time.Now()
+	require.Nil(t, s.RotateCredentials(context.Background(), "rotated", "secret"))
+	require.Less(t, time.Since(start), 2*time.Second)
+	require.True(t, s.IsBound())
+
+	require.Nil(t, s.Submit(newTextSubmitSM("2", "answered")))
+	smsc.ExpectReceived(t, data.SUBMIT_SM, 2)
+}
+
+func TestRotateCredentialsSessionClosed(t *testing.T) {
+	smsc := smsctest.NewPipeServer(nil)
+	defer smsc.Close()
+
+	s, err := gosmpp.NewSession(gosmpp.TRXConnector(smsc.Dialer(), gosmpp.Auth{SMSC: "pipe", SystemID: "esme"}),
+		gosmpp.Settings{ReadTimeout: time.Minute}, -1)
+	require.Nil(t, err)
+
+	smsc.SetFault(smsctest.Drop(100))
+	require.Nil(t, s.Submit(newTextSubmitSM("1", "dropped")))
+	smsc.ExpectReceived(t, data.SUBMIT_SM, 1)
+
+	rotated := make(chan error, 1)
+	go func() {
+		rotated <- s.RotateCredentials(context.Background(), "rotated", "secret")
+	}()
+	time.Sleep(50 * time.Millisecond)
+	require.Nil(t, s.Close())
+
+	select {
+	case err := <-rotated:
+		require.ErrorIs(t, err, gosmpp.ErrConnectionClosing)
+	case <-time.After(2 * time.Second):
+		t.Fatal("rotation does not stop once session is closed")
+	}
+	require.False(t, s.IsBound())
+}
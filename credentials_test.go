@@ -0,0 +1,78 @@
+package gosmpp
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp/pdu"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fixedConnector struct {
+	Connector
+}
+
+func TestRotateCredentials(t *testing.T) {
+	auth := nextAuth()
+	connector := TRXConnector(NonTLSDialer, auth)
+
+	var submitResp int32
+	s, err := NewSession(
+		connector,
+		Settings{
+			ReadTimeout: 2 * time.Second,
+			EnquireLink: 200 * time.Millisecond,
+			OnPDU: func(p pdu.PDU, _ bool) {
+				if _, ok := p.(*pdu.SubmitSMResp); ok {
+					atomic.AddInt32(&submitResp, 1)
+				}
+			},
+		}, 2*time.Second)
+	require.Nil(t, err)
+	defer func() {
+		_ = s.Close()
+	}()
+
+	t.Run("notSupported", func(t *testing.T) {
+		s := &Session{c: fixedConnector{connector}}
+		require.ErrorIs(t, s.RotateCredentials(context.Background(), "a", "b"), ErrCredentialsRotationNotSupported)
+	})
+
+	t.Run("rotate", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				require.Nil(t, s.Submit(newSubmitSM(auth.SystemID)))
+			}()
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		require.Nil(t, s.RotateCredentials(ctx, "rotated", "secret"))
+		wg.Wait()
+
+		systemID, password := connector.(CredentialsUpdater).Credentials()
+		require.Equal(t, "rotated", systemID)
+		require.Equal(t, "secret", password)
+		require.True(t, s.IsBound())
+
+		require.Nil(t, s.Submit(newSubmitSM(auth.SystemID)))
+		require.Eventually(t, func() bool {
+			return atomic.LoadInt32(&submitResp) == 11
+		}, 3*time.Second, 50*time.Millisecond)
+	})
+
+	t.Run("rollback", func(t *testing.T) {
+		require.Error(t, s.RotateCredentials(context.Background(), "invalid", "secret"))
+
+		systemID, _ := connector.(CredentialsUpdater).Credentials()
+		require.Equal(t, "rotated", systemID)
+		require.True(t, s.IsBound())
+	})
+}
@@ -342,6 +342,81 @@ func pack(dst []byte, septets []byte) (nDst int) {
 	return
 }
 
+// asciiSeptets are septets of ASCII characters, looked up without hashing.
+var asciiSeptets [0x80]uint16
+
+const (
+	septetValid   = 1 << 8
+	septetEscaped = 1 << 9
+)
+
+func init() {
+	for r, v := range forwardLookup {
+		if r < 0x80 {
+			asciiSeptets[r] = septetValid | uint16(v)
+		}
+	}
+	for r, v := range forwardEscape {
+		if r < 0x80 {
+			asciiSeptets[r] = septetValid | septetEscaped | uint16(v)
+		}
+	}
+}
+
+// septetOf returns septet of r. Escaped septets are preceded by escape sequence.
+func septetOf(r rune) (v byte, escaped, ok bool) {
+	if r >= 0 && r < 0x80 {
+		e := asciiSeptets[r]
+		return byte(e), e&septetEscaped != 0, e&septetValid != 0
+	}
+	if v, ok = forwardLookup[r]; ok {
+		return v, false, true
+	}
+	v, ok = forwardEscape[r]
+	return v, ok, ok
+}
+
+// septetCount returns number of septets encoding text, escaped characters take two of them.
+func septetCount(text string) (n int, err error) {
+	for _, r := range text {
+		_, escaped, ok := septetOf(r)
+		if !ok {
+			return 0, ErrInvalidCharacter
+		}
+		if escaped {
+			n++
+		}
+		n++
+	}
+	return
+}
+
+// appendPacked appends septets of text packed into octets to dst, with offset padding bits before the first
+// septet. Bits of the last incomplete octet are not appended, they are returned in acc: the lowest bits of them.
+func appendPacked(dst []byte, text string, offset uint) (_ []byte, acc uint32, bits uint, err error) {
+	bits = offset
+	push := func(septet byte) {
+		acc |= uint32(septet&0x7F) << bits
+		if bits += 7; bits >= 8 {
+			dst = append(dst, byte(acc))
+			acc >>= 8
+			bits -= 8
+		}
+	}
+
+	for _, r := range text {
+		v, escaped, ok := septetOf(r)
+		if !ok {
+			return dst, 0, 0, ErrInvalidCharacter
+		}
+		if escaped {
+			push(escapeSequence)
+		}
+		push(v)
+	}
+	return dst, acc, bits, nil
+}
+
 func (g *gsm7Encoder) Transform(dst, src []byte, atEOF bool) (nDst, nSrc int, err error) {
 	if len(src) == 0 {
 		return 0, 0, nil
@@ -128,7 +128,24 @@ type gsm7bitPacked struct {
 }
 
 func (c *gsm7bitPacked) Encode(str string) ([]byte, error) {
-	return encode(str, GSM7(true).NewEncoder())
+	n, err := septetCount(str)
+	if err != nil {
+		return nil, err
+	}
+	return c.AppendEncode(make([]byte, 0, (n*7+7)/8), str)
+}
+
+// AppendEncode appends packed septets of str to dst. It does not allocate if dst has enough capacity.
+func (c *gsm7bitPacked) AppendEncode(dst []byte, str string) ([]byte, error) {
+	n := len(dst)
+	dst, acc, bits, err := appendPacked(dst, str, 0)
+	if err != nil {
+		return dst[:n], err
+	}
+	if bits > 0 {
+		dst = append(dst, byte(acc))
+	}
+	return dst, nil
 }
 
 func (c *gsm7bitPacked) Decode(data []byte) (string, error) {
@@ -138,14 +155,17 @@ func (c *gsm7bitPacked) Decode(data []byte) (string, error) {
 func (c *gsm7bitPacked) DataCoding() byte { return GSM7BITCoding }
 
 func (c *gsm7bitPacked) ShouldSplit(text string, octetLimit uint) (shouldSplit bool) {
-	runeSlice := []rune(text)
-	tLen := len(runeSlice)
-	escCharsLen := len(GetEscapeChars(runeSlice))
-	regCharsLen := tLen - escCharsLen
 	// Esacpe characters occupy 2 octets/septets
 	// https://en.wikipedia.org/wiki/GSM_03.38
 	// https://www.developershome.com/sms/gsmAlphabet.asp
-	return uint((regCharsLen*7+escCharsLen*2*7+7)/8) > octetLimit
+	nSeptet := 0
+	for _, r := range text {
+		if _, escaped, _ := septetOf(r); escaped {
+			nSeptet++
+		}
+		nSeptet++
+	}
+	return uint((nSeptet*7+7)/8) > octetLimit
 }
 
 func (c *gsm7bitPacked) GetSeptetCount(runeSlice []rune) int {
@@ -160,9 +180,35 @@ func (c *gsm7bitPacked) EncodeSplit(text string, octetLimit uint) (allSeg [][]by
 		octetLimit = 134
 	}
 
+	if text == "" {
+		return [][]byte{}, nil
+	}
+
+	lim := int(octetLimit * 8 / 7)
+	nSeptet, err := septetCount(text)
+	if err != nil {
+		return nil, err
+	}
+	if nSeptet <= lim {
+		// single segment, packed at once after padding bit
+		seg, acc, bits, _ := appendPacked(make([]byte, 0, (nSeptet*7+8)/8), text, 1)
+		if bits == 1 {
+			// septets fill octets, the last one spills its top bit
+			if nSeptet != lim {
+				seg = append(seg, byte(acc)|0x0D<<1)
+			}
+		} else if bits > 0 {
+			seg = append(seg, byte(acc))
+		}
+		return [][]byte{seg}, nil
+	}
+	return c.encodeSegments(text, lim)
+}
+
+// encodeSegments encodes text split into segments of lim septets at most.
+func (c *gsm7bitPacked) encodeSegments(text string, lim int) (allSeg [][]byte, err error) {
 	allSeg = [][]byte{}
 	runeSlice := []rune(text)
-	lim := int(octetLimit * 8 / 7)
 
 	fr, to := 0, lim
 	for fr < len(runeSlice) {
@@ -411,3 +457,8 @@ type Splitter interface {
 	ShouldSplit(text string, octetLimit uint) (should bool)
 	EncodeSplit(text string, octetLimit uint) ([][]byte, error)
 }
+
+// AppendEncoder is implemented by encodings which encode into buffer of the caller, without allocating.
+type AppendEncoder interface {
+	AppendEncode(dst []byte, str string) ([]byte, error)
+}
@@ -3,6 +3,7 @@ package data
 import (
 	"encoding/hex"
 	"log"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -371,3 +372,103 @@ func TestCustomEncoding(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, "abc", decoded)
 }
+
+func TestGSM7BitPackedFastPath(t *testing.T) {
+	texts := []string{
+		"", "1", "1234567", "12345678", "123456789", "Your code is 1234",
+		"€uro {braces} [brackets] ~tilde|pipe^ \\back\fslash",
+		strings.Repeat("a", 160),
+		"ΩØ;19Ξòå1-¤6aΞΘANanΣ¡>)òΦ3L;aøΛ-o@>I¥1=-ü!N¤&o9Hmda3jΞ@ÅΣlhEE§/:Çù0Θ&:_&Π;KLÅÅ@fÜ-kFH?ΠB5/ÆΓ?55=<Ω¡N2",
+	}
+	splitter := GSM7BITPACKED.(*gsm7bitPacked)
+	for _, text := range texts {
+		expected, err := GSM7(true).NewEncoder().Bytes([]byte(text))
+		require.Nil(t, err)
+		encoded, err := GSM7BITPACKED.Encode(text)
+		require.Nil(t, err, text)
+		require.Equal(t, expected, encoded, text)
+
+		for _, octetLimit := range []uint{134, 140} {
+			lim := int(octetLimit * 8 / 7)
+			for n := 1; n <= len([]rune(text)); n++ {
+				prefix := string([]rune(text)[:n])
+				if count, _ := septetCount(prefix); count > lim {
+					break
+				}
+				segments, err := splitter.EncodeSplit(prefix, octetLimit)
+				require.Nil(t, err)
+				general, err := splitter.encodeSegments(prefix, lim)
+				require.Nil(t, err)
+				require.Equal(t, general, segments, prefix)
+			}
+		}
+	}
+
+	_, err := GSM7BITPACKED.Encode("a你")
+	require.Equal(t, ErrInvalidCharacter, err)
+	_, err = splitter.EncodeSplit("a你", 134)
+	require.Equal(t, ErrInvalidCharacter, err)
+
+	dst, err := GSM7BITPACKED.(AppendEncoder).AppendEncode([]byte{0xff}, "a你")
+	require.Equal(t, ErrInvalidCharacter, err)
+	require.Equal(t, []byte{0xff}, dst)
+}
+
+func TestGSM7BitPackedAllocs(t *testing.T) {
+	const text = "Your verification code is 123456. Do not share it with anyone! {ref: €5}"
+	splitter := GSM7BITPACKED.(Splitter)
+	appender := GSM7BITPACKED.(AppendEncoder)
+	buf := make([]byte, 0, 140)
+
+	// only the returned slices are allocated
+	require.EqualValues(t, 1, testing.AllocsPerRun(100, func() {
+		_, _ = GSM7BITPACKED.Encode(text)
+	}))
+	require.EqualValues(t, 2, testing.AllocsPerRun(100, func() {
+		_, _ = splitter.EncodeSplit(text, 134)
+	}))
+	require.Zero(t, testing.AllocsPerRun(100, func() {
+		_, _ = appender.AppendEncode(buf[:0], text)
+	}))
+	require.Zero(t, testing.AllocsPerRun(100, func() {
+		_ = splitter.ShouldSplit(text, 134)
+	}))
+}
+
+const benchmarkGSM7Text = "Your verification code is 123456. Do not share it with anyone! {ref: €5}"
+
+func BenchmarkGSM7BitPackedEncode(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = GSM7BITPACKED.Encode(benchmarkGSM7Text)
+	}
+}
+
+func BenchmarkGSM7BitPackedAppendEncode(b *testing.B) {
+	appender := GSM7BITPACKED.(AppendEncoder)
+	buf := make([]byte, 0, 140)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = appender.AppendEncode(buf[:0], benchmarkGSM7Text)
+	}
+}
+
+func BenchmarkGSM7BitPackedEncodeSplit(b *testing.B) {
+	splitter := GSM7BITPACKED.(Splitter)
+
+	b.Run("single", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _ = splitter.EncodeSplit(benchmarkGSM7Text, 134)
+		}
+	})
+
+	long := strings.Repeat(benchmarkGSM7Text, 4)
+	b.Run("multi", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _ = splitter.EncodeSplit(long, 134)
+		}
+	})
+}
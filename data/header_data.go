@@ -38,6 +38,8 @@ const (
 	ALERT_NOTIFICATION    = CommandIDType(0x00000102)
 	DATA_SM               = CommandIDType(0x00000103)
 	DATA_SM_RESP          = CommandIDType(-2147483389)
+	BROADCAST_SM          = CommandIDType(0x00000111)
+	BROADCAST_SM_RESP     = CommandIDType(-2147483375)
 )
 
 // nolint
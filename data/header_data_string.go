@@ -459,24 +459,28 @@ func _() {
 	_ = x[ALERT_NOTIFICATION-258]
 	_ = x[DATA_SM-259]
 	_ = x[DATA_SM_RESP - -2147483389]
+	_ = x[BROADCAST_SM-273]
+	_ = x[BROADCAST_SM_RESP - -2147483375]
 }
 
 const (
-	_CommandIDType_name_0 = "GENERIC_NACKBIND_RECEIVER_RESPBIND_TRANSMITTER_RESPQUERY_SM_RESPSUBMIT_SM_RESPDELIVER_SM_RESPUNBIND_RESPREPLACE_SM_RESPCANCEL_SM_RESPBIND_TRANSCEIVER_RESP"
-	_CommandIDType_name_1 = "ENQUIRE_LINK_RESP"
-	_CommandIDType_name_2 = "SUBMIT_MULTI_RESP"
-	_CommandIDType_name_3 = "DATA_SM_RESP"
-	_CommandIDType_name_4 = "BIND_RECEIVERBIND_TRANSMITTERQUERY_SMSUBMIT_SMDELIVER_SMUNBINDREPLACE_SMCANCEL_SMBIND_TRANSCEIVER"
-	_CommandIDType_name_5 = "OUTBIND"
-	_CommandIDType_name_6 = "ENQUIRE_LINK"
-	_CommandIDType_name_7 = "SUBMIT_MULTI"
-	_CommandIDType_name_8 = "ALERT_NOTIFICATIONDATA_SM"
+	_CommandIDType_name_0  = "GENERIC_NACKBIND_RECEIVER_RESPBIND_TRANSMITTER_RESPQUERY_SM_RESPSUBMIT_SM_RESPDELIVER_SM_RESPUNBIND_RESPREPLACE_SM_RESPCANCEL_SM_RESPBIND_TRANSCEIVER_RESP"
+	_CommandIDType_name_1  = "ENQUIRE_LINK_RESP"
+	_CommandIDType_name_2  = "SUBMIT_MULTI_RESP"
+	_CommandIDType_name_3  = "DATA_SM_RESP"
+	_CommandIDType_name_4  = "BROADCAST_SM_RESP"
+	_CommandIDType_name_5  = "BIND_RECEIVERBIND_TRANSMITTERQUERY_SMSUBMIT_SMDELIVER_SMUNBINDREPLACE_SMCANCEL_SMBIND_TRANSCEIVER"
+	_CommandIDType_name_6  = "OUTBIND"
+	_CommandIDType_name_7  = "ENQUIRE_LINK"
+	_CommandIDType_name_8  = "SUBMIT_MULTI"
+	_CommandIDType_name_9  = "ALERT_NOTIFICATIONDATA_SM"
+	_CommandIDType_name_10 = "BROADCAST_SM"
 )
 
 var (
 	_CommandIDType_index_0 = [...]uint8{0, 12, 30, 51, 64, 78, 93, 104, 119, 133, 154}
-	_CommandIDType_index_4 = [...]uint8{0, 13, 29, 37, 46, 56, 62, 72, 81, 97}
-	_CommandIDType_index_8 = [...]uint8{0, 18, 25}
+	_CommandIDType_index_5 = [...]uint8{0, 13, 29, 37, 46, 56, 62, 72, 81, 97}
+	_CommandIDType_index_9 = [...]uint8{0, 18, 25}
 )
 
 func (i CommandIDType) String() string {
@@ -490,18 +494,22 @@ func (i CommandIDType) String() string {
 		return _CommandIDType_name_2
 	case i == -2147483389:
 		return _CommandIDType_name_3
+	case i == -2147483375:
+		return _CommandIDType_name_4
 	case 1 <= i && i <= 9:
 		i -= 1
-		return _CommandIDType_name_4[_CommandIDType_index_4[i]:_CommandIDType_index_4[i+1]]
+		return _CommandIDType_name_5[_CommandIDType_index_5[i]:_CommandIDType_index_5[i+1]]
 	case i == 11:
-		return _CommandIDType_name_5
-	case i == 21:
 		return _CommandIDType_name_6
-	case i == 33:
+	case i == 21:
 		return _CommandIDType_name_7
+	case i == 33:
+		return _CommandIDType_name_8
 	case 258 <= i && i <= 259:
 		i -= 258
-		return _CommandIDType_name_8[_CommandIDType_index_8[i]:_CommandIDType_index_8[i+1]]
+		return _CommandIDType_name_9[_CommandIDType_index_9[i]:_CommandIDType_index_9[i+1]]
+	case i == 273:
+		return _CommandIDType_name_10
 	default:
 		return "CommandIDType(" + strconv.FormatInt(int64(i), 10) + ")"
 	}
@@ -39,6 +39,7 @@ const (
 	// Interface_Version
 	SMPP_V33 int8 = int8(-0x33)
 	SMPP_V34      = byte(0x34)
+	SMPP_V50      = byte(0x50)
 
 	// Address_TON
 	GSM_TON_UNKNOWN       = byte(0x00)
@@ -245,6 +246,22 @@ const (
 	SM_NOREPLACE = 0
 	SM_REPLACE   = 1
 
+	// Protocol identifier, see 3GPP TS 23.040 section 9.2.3.9
+	PID_DEFAULT                      = byte(0x00)
+	PID_SHORT_MESSAGE_TYPE_0         = byte(0x40)
+	PID_REPLACE_SHORT_MESSAGE_TYPE_1 = byte(0x41)
+	PID_REPLACE_SHORT_MESSAGE_TYPE_2 = byte(0x42)
+	PID_REPLACE_SHORT_MESSAGE_TYPE_3 = byte(0x43)
+	PID_REPLACE_SHORT_MESSAGE_TYPE_4 = byte(0x44)
+	PID_REPLACE_SHORT_MESSAGE_TYPE_5 = byte(0x45)
+	PID_REPLACE_SHORT_MESSAGE_TYPE_6 = byte(0x46)
+	PID_REPLACE_SHORT_MESSAGE_TYPE_7 = byte(0x47)
+	PID_ENHANCED_MESSAGE_SERVICE     = byte(0x5E)
+	PID_RETURN_CALL_MESSAGE          = byte(0x5F)
+	PID_ME_DATA_DOWNLOAD             = byte(0x7D)
+	PID_ME_DEPERSONALIZATION         = byte(0x7E)
+	PID_SIM_DATA_DOWNLOAD            = byte(0x7F)
+
 	// Destination flag
 	SM_DEST_SME_ADDRESS = 1
 	SM_DEST_DL_NAME     = 2
@@ -320,6 +337,23 @@ const (
 	// User Data Header
 	UDH_CONCAT_MSG_8_BIT_REF  = byte(0x00)
 	UDH_CONCAT_MSG_16_BIT_REF = byte(0x08)
+	UDH_APP_PORT_8_BIT        = byte(0x04)
+	UDH_APP_PORT_16_BIT       = byte(0x05)
+
+	// EMS User Data Header, 3GPP TS 23.040 section 9.2.3.24.10
+	UDH_EMS_TEXT_FORMATTING      = byte(0x0A)
+	UDH_EMS_PREDEFINED_SOUND     = byte(0x0B)
+	UDH_EMS_USER_SOUND           = byte(0x0C)
+	UDH_EMS_PREDEFINED_ANIMATION = byte(0x0D)
+	UDH_EMS_LARGE_ANIMATION      = byte(0x0E)
+	UDH_EMS_SMALL_ANIMATION      = byte(0x0F)
+	UDH_EMS_LARGE_PICTURE        = byte(0x10)
+	UDH_EMS_SMALL_PICTURE        = byte(0x11)
+	UDH_EMS_VARIABLE_PICTURE     = byte(0x12)
+
+	// (U)SIM Toolkit security headers, 3GPP TS 31.115
+	UDH_COMMAND_PACKET  = byte(0x70)
+	UDH_RESPONSE_PACKET = byte(0x71)
 
 	/**
 	 * @deprecated As of version 1.3 of the library there are defined
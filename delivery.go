@@ -0,0 +1,173 @@
+package gosmpp
+
+import (
+	"context"
+
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+)
+
+// DeliveryStatus is aggregated delivery status of all parts of a message.
+type DeliveryStatus int
+
+const (
+	// DeliveryPending means some parts are not confirmed yet.
+	DeliveryPending DeliveryStatus = iota
+
+	// DeliveryDelivered means all parts are delivered.
+	DeliveryDelivered
+
+	// DeliveryFailed means a part reached final state other than delivered.
+	DeliveryFailed
+)
+
+func (s DeliveryStatus) String() string {
+	switch s {
+	case DeliveryPending:
+		return "pending"
+	case DeliveryDelivered:
+		return "delivered"
+	case DeliveryFailed:
+		return "failed"
+	}
+	return "unknown"
+}
+
+// Delivery is delivery state of message sent by Messenger.
+type Delivery struct {
+	// HandleID is ID of the message handle.
+	HandleID string
+
+	// MessageIDs are message_id of the parts, in order.
+	MessageIDs []string
+
+	// States are the last received message states of the parts, zero if no receipt is received yet.
+	States []byte
+
+	// Status is aggregated status of the parts.
+	Status DeliveryStatus
+
+	// Correlation is correlation of the message handle.
+	Correlation Correlation
+}
+
+// Final reports whether delivery status would not change anymore.
+func (d Delivery) Final() bool {
+	return d.Status != DeliveryPending
+}
+
+// aggregate returns status of the parts: failed on the first failed part, delivered once all parts are delivered.
+func (d Delivery) aggregate() DeliveryStatus {
+	delivered := 0
+	for _, state := range d.States {
+		switch state {
+		case data.SM_STATE_DELIVERED:
+			delivered++
+		case data.SM_STATE_EXPIRED, data.SM_STATE_DELETED, data.SM_STATE_UNDELIVERABLE, data.SM_STATE_REJECTED:
+			return DeliveryFailed
+		}
+	}
+	if delivered == len(d.States) {
+		return DeliveryDelivered
+	}
+	return DeliveryPending
+}
+
+func (d Delivery) clone() Delivery {
+	d.MessageIDs = append([]string(nil), d.MessageIDs...)
+	d.States = append([]byte(nil), d.States...)
+	d.Correlation = d.Correlation.clone()
+	return d
+}
+
+// DeliveryTracker tracks delivery receipts of message parts, reporting single status per message.
+//
+// Receipts are fed by HandleReceipt, e.g. from Settings.OnDeliveryReceipt of receiving session, or HandlePDU:
+//
+//	tracker := gosmpp.NewDeliveryTracker(func(d gosmpp.Delivery) { ... })
+//	settings.OnDeliveryReceipt = func(r gosmpp.Receipt) { _, _ = tracker.HandleReceipt(context.Background(), r) }
+//
+// Deliveries are kept in memory unless DeliveryStore is given with WithDeliveryStore.
+type DeliveryTracker struct {
+	store      DeliveryStore
+	onDelivery func(Delivery)
+}
+
+// DeliveryTrackerOption configures DeliveryTracker.
+type DeliveryTrackerOption func(*DeliveryTracker)
+
+// WithDeliveryStore keeps tracked deliveries in store, e.g. shared by multiple instances.
+func WithDeliveryStore(store DeliveryStore) DeliveryTrackerOption {
+	return func(t *DeliveryTracker) {
+		t.store = store
+	}
+}
+
+// NewDeliveryTracker returns tracker calling onDelivery, if not nil, once delivery of a message becomes final.
+func NewDeliveryTracker(onDelivery func(Delivery), opts ...DeliveryTrackerOption) *DeliveryTracker {
+	t := &DeliveryTracker{onDelivery: onDelivery}
+	for _, opt := range opts {
+		opt(t)
+	}
+	if t.store == nil {
+		t.store = NewMemoryDeliveryStore()
+	}
+	return t
+}
+
+// Track starts tracking parts of h accepted by SMSC.
+func (t *DeliveryTracker) Track(ctx context.Context, h *MessageHandle) error {
+	return t.store.Save(ctx, Delivery{
+		HandleID:    h.ID,
+		MessageIDs:  append([]string(nil), h.MessageIDs...),
+		States:      make([]byte, len(h.MessageIDs)),
+		Correlation: h.Correlation,
+	})
+}
+
+// HandlePDU handles p if it is delivery receipt, reporting whether it belongs to tracked message.
+func (t *DeliveryTracker) HandlePDU(ctx context.Context, p pdu.PDU) (bool, error) {
+	if deliver, ok := p.(*pdu.DeliverSM); ok {
+		if r, ok := ParseReceipt(deliver); ok {
+			return t.HandleReceipt(ctx, r)
+		}
+	}
+	return false, nil
+}
+
+// HandleReceipt updates delivery of message with the receipted part, reporting whether the part is tracked.
+// Receipts of messages with final delivery are ignored.
+func (t *DeliveryTracker) HandleReceipt(ctx context.Context, r Receipt) (bool, error) {
+	var final bool
+	d, ok, err := t.store.Update(ctx, r.MessageID, func(d *Delivery) {
+		if d.Final() {
+			return
+		}
+
+		for i, id := range d.MessageIDs {
+			if id == r.MessageID {
+				d.States[i] = r.State
+			}
+		}
+		d.Status = d.aggregate()
+		final = d.Final()
+	})
+	if err != nil || !ok {
+		return false, err
+	}
+
+	if final && t.onDelivery != nil {
+		t.onDelivery(d)
+	}
+	return true, nil
+}
+
+// Status returns delivery of message by its handle id.
+func (t *DeliveryTracker) Status(ctx context.Context, handleID string) (Delivery, bool, error) {
+	return t.store.Get(ctx, handleID)
+}
+
+// Forget stops tracking message by its handle id.
+func (t *DeliveryTracker) Forget(ctx context.Context, handleID string) error {
+	return t.store.Delete(ctx, handleID)
+}
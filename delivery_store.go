@@ -0,0 +1,121 @@
+package gosmpp
+
+import (
+	"context"
+	"sync"
+)
+
+// DeliveryStore stores deliveries tracked by DeliveryTracker, keyed by message handle id.
+//
+// Persistent implementation, e.g. on Redis or SQL database, lets tracking survive restarts
+// and could be shared by multiple instances receiving receipts.
+type DeliveryStore interface {
+	// Save stores delivery, replacing the one with the same handle id.
+	Save(ctx context.Context, d Delivery) error
+
+	// Get returns delivery by handle id.
+	Get(ctx context.Context, handleID string) (Delivery, bool, error)
+
+	// Update calls update with delivery having part of the message id and stores the result,
+	// reporting false if there is none. Concurrent updates of the same delivery must be serialized.
+	Update(ctx context.Context, messageID string, update func(*Delivery)) (Delivery, bool, error)
+
+	// Delete removes delivery by handle id.
+	Delete(ctx context.Context, handleID string) error
+}
+
+// MemoryDeliveryStore is DeliveryStore in memory.
+type MemoryDeliveryStore struct {
+	mu         sync.Mutex
+	deliveries map[string]Delivery // by handle id
+	handles    map[string]string   // handle id by message id
+}
+
+// NewMemoryDeliveryStore returns empty MemoryDeliveryStore.
+func NewMemoryDeliveryStore() *MemoryDeliveryStore {
+	return &MemoryDeliveryStore{
+		deliveries: make(map[string]Delivery),
+		handles:    make(map[string]string),
+	}
+}
+
+// Save implements DeliveryStore.
+func (s *MemoryDeliveryStore) Save(ctx context.Context, d Delivery) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.remove(d.HandleID)
+	s.deliveries[d.HandleID] = d.clone()
+	for _, id := range d.MessageIDs {
+		if id != "" {
+			s.handles[id] = d.HandleID
+		}
+	}
+	return nil
+}
+
+// Get implements DeliveryStore.
+func (s *MemoryDeliveryStore) Get(ctx context.Context, handleID string) (Delivery, bool, error) {
+	if err := ctx.Err(); err != nil {
+		return Delivery{}, false, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, ok := s.deliveries[handleID]
+	return d.clone(), ok, nil
+}
+
+// Update implements DeliveryStore.
+func (s *MemoryDeliveryStore) Update(ctx context.Context, messageID string, update func(*Delivery)) (Delivery, bool, error) {
+	if err := ctx.Err(); err != nil {
+		return Delivery{}, false, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	handleID, ok := s.handles[messageID]
+	if !ok {
+		return Delivery{}, false, nil
+	}
+
+	d := s.deliveries[handleID].clone()
+	update(&d)
+	s.deliveries[handleID] = d
+	return d.clone(), true, nil
+}
+
+// Delete implements DeliveryStore.
+func (s *MemoryDeliveryStore) Delete(ctx context.Context, handleID string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.remove(handleID)
+	return nil
+}
+
+// Len returns number of stored deliveries.
+func (s *MemoryDeliveryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.deliveries)
+}
+
+func (s *MemoryDeliveryStore) remove(handleID string) {
+	if d, ok := s.deliveries[handleID]; ok {
+		for _, id := range d.MessageIDs {
+			delete(s.handles, id)
+		}
+		delete(s.deliveries, handleID)
+	}
+}
@@ -0,0 +1,95 @@
+package gosmpp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeliveryTracker(t *testing.T) {
+	ctx := context.Background()
+
+	var final []Delivery
+	tracker := NewDeliveryTracker(func(d Delivery) {
+		final = append(final, d)
+	})
+
+	require.Nil(t, tracker.Track(ctx, &MessageHandle{ID: "h1", MessageIDs: []string{"a", "b"}}))
+	require.Nil(t, tracker.Track(ctx, &MessageHandle{ID: "h2", MessageIDs: []string{"c", "d", "e"}}))
+
+	handle := func(r Receipt) bool {
+		ok, err := tracker.HandleReceipt(ctx, r)
+		require.Nil(t, err)
+		return ok
+	}
+
+	require.True(t, handle(Receipt{MessageID: "a", State: data.SM_STATE_DELIVERED}))
+	d, ok, err := tracker.Status(ctx, "h1")
+	require.Nil(t, err)
+	require.True(t, ok)
+	require.Equal(t, DeliveryPending, d.Status)
+	require.Equal(t, []byte{data.SM_STATE_DELIVERED, 0}, d.States)
+	require.Empty(t, final)
+
+	ok, err = tracker.HandlePDU(ctx, newReceipt("id:b stat:DELIVRD"))
+	require.Nil(t, err)
+	require.True(t, ok)
+	d, _, _ = tracker.Status(ctx, "h1")
+	require.Equal(t, DeliveryDelivered, d.Status)
+	require.True(t, d.Final())
+
+	// failed on first permanent failure, later receipts are ignored
+	require.True(t, handle(Receipt{MessageID: "c", State: data.SM_STATE_EN_ROUTE}))
+	require.True(t, handle(Receipt{MessageID: "d", State: data.SM_STATE_EXPIRED}))
+	require.True(t, handle(Receipt{MessageID: "e", State: data.SM_STATE_DELIVERED}))
+
+	require.Len(t, final, 2)
+	require.Equal(t, "h1", final[0].HandleID)
+	require.Equal(t, DeliveryDelivered, final[0].Status)
+	require.Equal(t, "h2", final[1].HandleID)
+	require.Equal(t, DeliveryFailed, final[1].Status)
+	require.Equal(t, []byte{data.SM_STATE_EN_ROUTE, data.SM_STATE_EXPIRED, 0}, final[1].States)
+
+	require.False(t, handle(Receipt{MessageID: "unknown", State: data.SM_STATE_DELIVERED}))
+	ok, err = tracker.HandlePDU(ctx, pdu.NewSubmitSM())
+	require.Nil(t, err)
+	require.False(t, ok)
+
+	require.Nil(t, tracker.Forget(ctx, "h1"))
+	_, ok, _ = tracker.Status(ctx, "h1")
+	require.False(t, ok)
+	require.False(t, handle(Receipt{MessageID: "a", State: data.SM_STATE_DELIVERED}))
+}
+
+func TestDeliveryTrackerSharedStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryDeliveryStore()
+
+	var final []Delivery
+	onDelivery := func(d Delivery) {
+		final = append(final, d)
+	}
+	sender := NewDeliveryTracker(onDelivery, WithDeliveryStore(store))
+	receiver := NewDeliveryTracker(onDelivery, WithDeliveryStore(store))
+
+	require.Nil(t, sender.Track(ctx, &MessageHandle{ID: "h", MessageIDs: []string{"a", "b"}}))
+	require.Equal(t, 1, store.Len())
+
+	_, err := sender.HandleReceipt(ctx, Receipt{MessageID: "a", State: data.SM_STATE_DELIVERED})
+	require.Nil(t, err)
+	_, err = receiver.HandleReceipt(ctx, Receipt{MessageID: "b", State: data.SM_STATE_DELIVERED})
+	require.Nil(t, err)
+
+	require.Len(t, final, 1)
+	require.Equal(t, DeliveryDelivered, final[0].Status)
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	require.Equal(t, context.Canceled, sender.Track(cancelled, &MessageHandle{ID: "x"}))
+	_, _, err = receiver.Status(cancelled, "h")
+	require.Equal(t, context.Canceled, err)
+}
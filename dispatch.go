@@ -0,0 +1,90 @@
+package gosmpp
+
+import (
+	"sync"
+
+	"github.com/linxGnu/gosmpp/pdu"
+)
+
+// receiveQueueSize is number of received requests queued per worker of Settings.ReceiveWorkers, reading blocks
+// when queue of the worker is full.
+const receiveQueueSize = 64
+
+// dispatcher runs handlers of received requests on a pool of workers. Handlers of the same key, source address
+// of the request, run on the same worker in order of dispatching.
+//
+// All methods are safe to call on nil receiver.
+type dispatcher struct {
+	queues []chan func()
+	wg     sync.WaitGroup
+}
+
+func newDispatcher(workers int) *dispatcher {
+	if workers <= 0 {
+		return nil
+	}
+
+	d := &dispatcher{queues: make([]chan func(), workers)}
+	for i := range d.queues {
+		queue := make(chan func(), receiveQueueSize)
+		d.queues[i] = queue
+
+		d.wg.Add(1)
+		go func() {
+			defer d.wg.Done()
+			for handle := range queue {
+				handle()
+			}
+		}()
+	}
+	return d
+}
+
+// worker returns index of worker handling key, by its FNV-1a hash.
+func (d *dispatcher) worker(key string) int {
+	h := uint32(2166136261)
+	for i := 0; i < len(key); i++ {
+		h ^= uint32(key[i])
+		h *= 16777619
+	}
+	return int(h % uint32(len(d.queues)))
+}
+
+// dispatch queues handle to worker of key, waiting while the worker queue is full.
+func (d *dispatcher) dispatch(key string, handle func()) {
+	d.queues[d.worker(key)] <- handle
+}
+
+// stop waits for queued handlers and stops workers. Nothing could be dispatched after.
+func (d *dispatcher) stop() {
+	if d == nil {
+		return
+	}
+	for _, queue := range d.queues {
+		close(queue)
+	}
+	d.wg.Wait()
+}
+
+// isDispatched tells whether received p is handled by dispatcher: requests except enquire_link and unbind,
+// which are handled on the reading goroutine as well as responses.
+func isDispatched(p pdu.PDU) bool {
+	switch p.(type) {
+	case *pdu.EnquireLink, *pdu.Unbind:
+		return false
+	default:
+		return !isResponsePDU(p)
+	}
+}
+
+// sourceOf returns source address of request p, empty if it has none.
+func sourceOf(p pdu.PDU) string {
+	switch pp := p.(type) {
+	case *pdu.DeliverSM:
+		return pp.SourceAddr.Address()
+	case *pdu.DataSM:
+		return pp.SourceAddr.Address()
+	default:
+		return ""
+	}
+}
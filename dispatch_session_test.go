@@ -0,0 +1,86 @@
+package gosmpp_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp"
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+	"github.com/linxGnu/gosmpp/server/smsctest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newDeliverSM(source, text string) pdu.PDU {
+	p := pdu.NewDeliverSM().(*pdu.DeliverSM)
+	_ = p.SourceAddr.SetAddress(source)
+	_ = p.DestAddr.SetAddress("QA")
+	_ = p.Message.SetMessageWithEncoding(text, data.GSM7BIT)
+	return p
+}
+
+func TestSessionReceiveWorkers(t *testing.T) {
+	smsc := smsctest.NewPipeServer(nil)
+	defer smsc.Close()
+
+	release := make(chan struct{})
+	received := make(chan string, 10)
+	s, err := gosmpp.NewSession(gosmpp.TRXConnector(smsc.Dialer(), gosmpp.Auth{SMSC: "pipe", SystemID: "esme"}),
+		gosmpp.Settings{
+			ReadTimeout:    2 * time.Second,
+			ReceiveWorkers: 4,
+
+			OnPDU: func(p pdu.PDU, responded bool) {
+				deliver, ok := p.(*pdu.DeliverSM)
+				if !ok || !responded {
+					return
+				}
+				text, _ := deliver.Message.GetMessage()
+				if deliver.SourceAddr.Address() == "slow" {
+					<-release
+				}
+				received <- text
+			},
+		}, -1)
+	require.Nil(t, err)
+	defer func() {
+		_ = s.Close()
+	}()
+
+	client := smsc.Sessions()[0]
+	require.Nil(t, client.Submit(newDeliverSM("slow", "slow")))
+
+	// responses are read while handler is blocked
+	_, err = s.SubmitAsync(pdu.NewSubmitSM()).Wait(context.Background())
+	require.Nil(t, err)
+
+	for i := 0; i < 5; i++ {
+		require.Nil(t, client.Submit(newDeliverSM("+447700900123", fmt.Sprint(i))))
+	}
+	close(release)
+
+	var texts []string
+	for len(texts) < 6 {
+		select {
+		case text := <-received:
+			texts = append(texts, text)
+		case <-time.After(2 * time.Second):
+			t.Fatal("deliver_sm is not handled")
+		}
+	}
+	require.Contains(t, texts, "slow")
+	require.Equal(t, []string{"0", "1", "2", "3", "4"}, remove(texts, "slow"))
+	smsc.ExpectReceived(t, data.DELIVER_SM_RESP, 6)
+}
+
+func remove(texts []string, text string) (rest []string) {
+	for _, t := range texts {
+		if t != text {
+			rest = append(rest, t)
+		}
+	}
+	return
+}
@@ -0,0 +1,79 @@
+package gosmpp
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp/pdu"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDispatcher(t *testing.T) {
+	require.Nil(t, newDispatcher(0))
+	(*dispatcher)(nil).stop()
+
+	d := newDispatcher(4)
+
+	// find source handled by other worker than the slow one
+	slow, fast := "slow", ""
+	for i := 0; fast == ""; i++ {
+		if key := fmt.Sprint("fast", i); d.worker(key) != d.worker(slow) {
+			fast = key
+		}
+	}
+
+	release := make(chan struct{})
+	d.dispatch(slow, func() { <-release })
+
+	var (
+		mu      sync.Mutex
+		handled []string
+	)
+	done := make(chan struct{})
+	for i := 0; i < 10; i++ {
+		i := i
+		d.dispatch(fast, func() {
+			mu.Lock()
+			handled = append(handled, fmt.Sprint(i))
+			mu.Unlock()
+			if i == 9 {
+				close(done)
+			}
+		})
+	}
+
+	// slow handler does not stall other sources
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("handlers of other source are stalled")
+	}
+	require.Equal(t, []string{"0", "1", "2", "3", "4", "5", "6", "7", "8", "9"}, handled)
+
+	// queued handlers are run on stop
+	d.dispatch(slow, func() {
+		mu.Lock()
+		handled = append(handled, slow)
+		mu.Unlock()
+	})
+	close(release)
+	d.stop()
+	require.Equal(t, slow, handled[len(handled)-1])
+}
+
+func TestIsDispatched(t *testing.T) {
+	require.True(t, isDispatched(pdu.NewDeliverSM()))
+	require.True(t, isDispatched(pdu.NewAlertNotification()))
+	require.False(t, isDispatched(pdu.NewEnquireLink()))
+	require.False(t, isDispatched(pdu.NewUnbind()))
+	require.False(t, isDispatched(pdu.NewSubmitSMResp()))
+	require.False(t, isDispatched(pdu.NewGenericNack()))
+
+	deliver := pdu.NewDeliverSM().(*pdu.DeliverSM)
+	_ = deliver.SourceAddr.SetAddress("+447700900123")
+	require.Equal(t, "+447700900123", sourceOf(deliver))
+	require.Empty(t, sourceOf(pdu.NewAlertNotification()))
+}
@@ -0,0 +1,29 @@
+package gosmpp
+
+import (
+	"net"
+	"time"
+)
+
+// DefaultFallbackDelay is the delay before IPv4 attempt is started while IPv6 attempt is still pending.
+const DefaultFallbackDelay = 300 * time.Millisecond
+
+// NewDualStackDialer returns happy-eyeballs (RFC 6555) style dialer of net.Dialer.
+//
+// Host resolving into both IPv6 and IPv4 addresses is dialed on the family of the first resolved address,
+// IPv6 where resolver prefers it, the other family is dialed after fallbackDelay or as soon as the first one
+// failed. The first established connection wins. Zero fallbackDelay means DefaultFallbackDelay, negative one
+// disables the fallback.
+//
+// NonTLSDialer dials so with DefaultFallbackDelay already, NewDualStackDialer is for tuning the delay, e.g.
+// shortening it for SMSC whose IPv6 address is unreachable from some networks.
+func NewDualStackDialer(fallbackDelay time.Duration) Dialer {
+	return dualStackDialer(&net.Dialer{FallbackDelay: fallbackDelay})
+}
+
+// dualStackDialer returns dialer of TCP connections by d.
+func dualStackDialer(d *net.Dialer) Dialer {
+	return func(addr string) (net.Conn, error) {
+		return d.Dial("tcp", addr)
+	}
+}
@@ -0,0 +1,128 @@
+package gosmpp
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// dualStackResolver resolves any host into ::1 and 127.0.0.1, answering DNS queries over TCP framing.
+func dualStackResolver() *net.Resolver {
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+			client, server := net.Pipe()
+			go func() {
+				defer server.Close()
+				for {
+					var length [2]byte
+					if _, err := io.ReadFull(server, length[:]); err != nil {
+						return
+					}
+					query := make([]byte, binary.BigEndian.Uint16(length[:]))
+					if _, err := io.ReadFull(server, query); err != nil {
+						return
+					}
+					answer := dnsAnswer(query)
+					binary.BigEndian.PutUint16(length[:], uint16(len(answer)))
+					if _, err := server.Write(append(length[:], answer...)); err != nil {
+						return
+					}
+				}
+			}()
+			return client, nil
+		},
+	}
+}
+
+// dnsAnswer returns response to query of A or AAAA record with loopback address of the family.
+func dnsAnswer(query []byte) []byte {
+	end := 12
+	for query[end] != 0 {
+		end += int(query[end]) + 1
+	}
+	end += 5 // root label, type and class
+	qtype := binary.BigEndian.Uint16(query[end-4:])
+
+	rdata := net.IPv4(127, 0, 0, 1).To4()
+	if qtype == 28 { // AAAA
+		rdata = net.IPv6loopback
+	}
+
+	answer := append([]byte{}, query[:2]...)                             // id
+	answer = append(answer, 0x81, 0x80, 0, 1, 0, 1, 0, 0, 0, 0)          // response, 1 question, 1 answer
+	answer = append(answer, query[12:end]...)                            // question
+	answer = append(answer, 0xc0, 12, byte(qtype>>8), byte(qtype), 0, 1) // name of question, type, class IN
+	answer = append(answer, 0, 0, 0, 60, 0, byte(len(rdata)))            // ttl, rdlength
+	return append(answer, rdata...)
+}
+
+// listenDualStack listens on the same port of ::1 and 127.0.0.1.
+func listenDualStack(t *testing.T) (port string) {
+	for i := 0; i < 10; i++ {
+		ln6, err := net.Listen("tcp6", "[::1]:0")
+		if err != nil {
+			t.Skip("IPv6 loopback is not available:", err)
+		}
+		_, port, _ = net.SplitHostPort(ln6.Addr().String())
+		ln4, err := net.Listen("tcp4", "127.0.0.1:"+port)
+		if err != nil {
+			_ = ln6.Close()
+			continue
+		}
+		t.Cleanup(func() {
+			_ = ln6.Close()
+			_ = ln4.Close()
+		})
+		return
+	}
+	t.Skip("no port free on both ::1 and 127.0.0.1")
+	return
+}
+
+func TestDualStackDialer(t *testing.T) {
+	port := listenDualStack(t)
+	addr := net.JoinHostPort("smsc.test", port)
+
+	// dial returns family of connection dialed with fallbackDelay, while IPv6 attempt takes ipv6Delay
+	dial := func(fallbackDelay, ipv6Delay time.Duration) (family string, elapsed time.Duration) {
+		dialer := dualStackDialer(&net.Dialer{
+			FallbackDelay: fallbackDelay,
+			Resolver:      dualStackResolver(),
+			Control: func(network, _ string, _ syscall.RawConn) error {
+				if network == "tcp6" {
+					time.Sleep(ipv6Delay)
+				}
+				return nil
+			},
+		})
+
+		start := time.Now()
+		conn, err := dialer(addr)
+		require.Nil(t, err)
+		defer func() {
+			_ = conn.Close()
+		}()
+		if conn.RemoteAddr().(*net.TCPAddr).IP.To4() != nil {
+			return "IPv4", time.Since(start)
+		}
+		return "IPv6", time.Since(start)
+	}
+
+	family, _ := dial(0, 0)
+	require.Equal(t, "IPv6", family)
+
+	family, elapsed := dial(50*time.Millisecond, time.Second)
+	require.Equal(t, "IPv4", family)
+	require.GreaterOrEqual(t, elapsed, 50*time.Millisecond)
+	require.Less(t, elapsed, time.Second)
+
+	family, elapsed = dial(time.Second, 200*time.Millisecond)
+	require.Equal(t, "IPv6", family, "IPv4 dialed before fallback delay, after %v", elapsed)
+}
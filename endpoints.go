@@ -0,0 +1,119 @@
+package gosmpp
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync/atomic"
+	"time"
+
+	"github.com/linxGnu/gosmpp/pdu"
+)
+
+const resolveTimeout = 5 * time.Second
+
+// HostResolver resolves host into list of its addresses.
+type HostResolver func(ctx context.Context, host string) (addrs []string, err error)
+
+var (
+	// DefaultHostResolver resolves host using net.DefaultResolver.
+	DefaultHostResolver HostResolver = net.DefaultResolver.LookupHost
+
+	// ErrNoEndpoint indicates that there is no SMSC address to connect to.
+	ErrNoEndpoint = errors.New("no SMSC endpoint to connect")
+)
+
+// endpoints is list of SMSC addresses, rotated through on (re)connecting.
+type endpoints struct {
+	addrs    []string
+	resolver HostResolver
+	next     uint32
+}
+
+// candidates returns addresses to dial, host names are re-resolved on each call if resolver is set.
+//
+// fallback is used when no address is configured.
+func (e *endpoints) candidates(fallback string) (result []string) {
+	addrs := e.addrs
+	if len(addrs) == 0 && fallback != "" {
+		addrs = []string{fallback}
+	}
+
+	if e.resolver == nil {
+		return addrs
+	}
+
+	result = make([]string, 0, len(addrs))
+	for _, addr := range addrs {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil || net.ParseIP(host) != nil {
+			result = append(result, addr)
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), resolveTimeout)
+		ips, err := e.resolver(ctx, host)
+		cancel()
+		if err != nil || len(ips) == 0 {
+			// let the dialer try the host name itself
+			result = append(result, addr)
+			continue
+		}
+
+		for _, ip := range ips {
+			result = append(result, net.JoinHostPort(ip, port))
+		}
+	}
+	return
+}
+
+// connect tries candidates in round-robin order, starting from the one after last attempt.
+//
+// Network errors move on to the next address, a bind error is returned immediately.
+func (e *endpoints) connect(dialer Dialer, fallback string, bindReq *pdu.BindRequest) (c *Connection, err error) {
+	addrs := e.candidates(fallback)
+	if len(addrs) == 0 {
+		return nil, ErrNoEndpoint
+	}
+
+	start := atomic.AddUint32(&e.next, 1) - 1
+	for i := range addrs {
+		addr := addrs[(int(start)+i)%len(addrs)]
+		if c, err = connect(dialer, addr, bindReq); err == nil {
+			return
+		}
+
+		var bindErr BindError
+		if errors.As(err, &bindErr) {
+			return
+		}
+	}
+	return
+}
+
+// WithEndpoints sets list of SMSC addresses (host:port). Connector rotates through them
+// on each (re)connect, falling over to next address on network error.
+//
+// Auth.SMSC is ignored when endpoints are set.
+func WithEndpoints(addrs ...string) ConnectorOption {
+	return func(c *connector) {
+		if c.endpoints == nil {
+			c.endpoints = &endpoints{}
+		}
+		c.endpoints.addrs = append([]string(nil), addrs...)
+	}
+}
+
+// WithHostResolver enables re-resolving host names of endpoints on every connect attempt.
+// Each resolved address becomes a separate endpoint, so SMSC-side IP changes are picked up
+// without restart and all A/AAAA records take part in rotation.
+//
+// Note: the dialer receives resolved ip:port. TLS dialers should set tls.Config.ServerName.
+func WithHostResolver(resolver HostResolver) ConnectorOption {
+	return func(c *connector) {
+		if c.endpoints == nil {
+			c.endpoints = &endpoints{}
+		}
+		c.endpoints.resolver = resolver
+	}
+}
@@ -0,0 +1,89 @@
+package gosmpp
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEndpoints(t *testing.T) {
+	recordingDialer := func(dialed *[]string, mu *sync.Mutex) Dialer {
+		return func(addr string) (net.Conn, error) {
+			mu.Lock()
+			*dialed = append(*dialed, addr)
+			mu.Unlock()
+			return NonTLSDialer(addr)
+		}
+	}
+
+	t.Run("failover", func(t *testing.T) {
+		var (
+			mu     sync.Mutex
+			dialed []string
+		)
+
+		c := TRXConnector(recordingDialer(&dialed, &mu), nextAuth(), WithEndpoints("127.0.0.1:1", smscAddr))
+
+		conn, err := c.Connect()
+		require.Nil(t, err)
+		_ = conn.Close()
+		require.Equal(t, []string{"127.0.0.1:1", smscAddr}, dialed)
+
+		// next connect rotates to following endpoint
+		conn, err = c.Connect()
+		require.Nil(t, err)
+		_ = conn.Close()
+		require.Equal(t, smscAddr, dialed[2])
+	})
+
+	t.Run("bindError", func(t *testing.T) {
+		var (
+			mu     sync.Mutex
+			dialed []string
+		)
+
+		auth := Auth{SystemID: "invalid"}
+		c := TXConnector(recordingDialer(&dialed, &mu), auth, WithEndpoints(smscAddr, smscAddr))
+		_, err := c.Connect()
+		require.ErrorAs(t, err, &BindError{})
+		require.Len(t, dialed, 1)
+	})
+
+	t.Run("noEndpoint", func(t *testing.T) {
+		c := TXConnector(NonTLSDialer, Auth{}, WithEndpoints())
+		_, err := c.Connect()
+		require.ErrorIs(t, err, ErrNoEndpoint)
+	})
+
+	t.Run("reResolve", func(t *testing.T) {
+		var (
+			mu       sync.Mutex
+			dialed   []string
+			resolved int
+		)
+
+		resolver := func(_ context.Context, host string) ([]string, error) {
+			resolved++
+			if host != "smsc.test" {
+				return nil, fmt.Errorf("unknown host %s", host)
+			}
+			return []string{"127.0.0.2", "127.0.0.1"}, nil
+		}
+
+		auth := nextAuth()
+		auth.SMSC = "smsc.test:2775"
+		c := RXConnector(recordingDialer(&dialed, &mu), auth, WithHostResolver(resolver))
+
+		for i := 0; i < 2; i++ {
+			conn, err := c.Connect()
+			require.Nil(t, err)
+			_ = conn.Close()
+		}
+		require.Equal(t, 2, resolved)
+		require.Contains(t, dialed, "127.0.0.1:2775")
+	})
+}
@@ -0,0 +1,234 @@
+package gosmpp
+
+import (
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/linxGnu/gosmpp/clock"
+	"github.com/linxGnu/gosmpp/pdu"
+)
+
+// EventType is type of Event published by Session.
+type EventType byte
+
+const (
+	// EventBound is published when session got bound to SMSC.
+	EventBound EventType = iota
+
+	// EventUnbound is published when bind is closed, Event.State tells the reason.
+	EventUnbound
+
+	// EventThrottled is published when SMSC responded with ESME_RTHROTTLED, Event.PDU is the response.
+	EventThrottled
+
+	// EventWindowFull is published when request could not be sent due to full window, Event.PDU is the request.
+	EventWindowFull
+
+	// EventEnquireLinkTimeout is published when enquire_link was not responded before sending the next one.
+	EventEnquireLinkTimeout
+
+	// EventDecodeError is published when received data could not be decoded into PDU, Event.Err is the error.
+	EventDecodeError
+
+	// EventReconnected is published when session is bound again after losing its bind.
+	EventReconnected
+
+	// EventSaturated is published when session becomes saturated, see Session.Saturated.
+	EventSaturated
+
+	// EventResumed is published when saturated session could accept PDUs again.
+	EventResumed
+
+	// EventDeviation is published when received PDU deviates from SMPP specification, Event.PDU is the PDU and
+	// Event.Err is *pdu.ValidationError listing the deviations.
+	EventDeviation
+)
+
+// String interface.
+func (t EventType) String() string {
+	switch t {
+	case EventBound:
+		return "Bound"
+
+	case EventUnbound:
+		return "Unbound"
+
+	case EventThrottled:
+		return "Throttled"
+
+	case EventWindowFull:
+		return "WindowFull"
+
+	case EventEnquireLinkTimeout:
+		return "EnquireLinkTimeout"
+
+	case EventDecodeError:
+		return "DecodeError"
+
+	case EventReconnected:
+		return "Reconnected"
+
+	case EventSaturated:
+		return "Saturated"
+
+	case EventResumed:
+		return "Resumed"
+
+	case EventDeviation:
+		return "Deviation"
+
+	default:
+		return ""
+	}
+}
+
+// Event is published by Session to its subscribers.
+type Event struct {
+	Type  EventType
+	Time  time.Time
+	State State
+	PDU   pdu.PDU
+	Err   error
+
+	// Labels of the session, shared between events: must not be modified.
+	Labels Labels
+
+	// Correlation of the call of Throttled or WindowFull request, zero if it has none.
+	Correlation Correlation
+}
+
+// EventHandler handles session Event.
+//
+// Handlers are called synchronously from session daemons, they must not block.
+type EventHandler func(Event)
+
+// eventBus is subscribable registry of event handlers.
+type eventBus struct {
+	mu       sync.RWMutex
+	nextID   int
+	handlers map[int]EventHandler
+	clock    clock.Clock
+	labels   Labels
+	onPanic  HandlerPanicCallback
+}
+
+func (b *eventBus) subscribe(h EventHandler) (unsubscribe func()) {
+	b.mu.Lock()
+	if b.handlers == nil {
+		b.handlers = make(map[int]EventHandler)
+	}
+	id := b.nextID
+	b.nextID++
+	b.handlers[id] = h
+	b.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.handlers, id)
+			b.mu.Unlock()
+		})
+	}
+}
+
+func (b *eventBus) publish(e Event) {
+	if b == nil {
+		return
+	}
+
+	b.mu.RLock()
+	if len(b.handlers) == 0 {
+		b.mu.RUnlock()
+		return
+	}
+	handlers := make([]EventHandler, 0, len(b.handlers))
+	for _, h := range b.handlers {
+		handlers = append(handlers, h)
+	}
+	b.mu.RUnlock()
+
+	e.Labels = b.labels
+	if e.Time.IsZero() {
+		e.Time = clock.OrReal(b.clock).Now()
+	}
+	for _, h := range handlers {
+		b.call(h, e)
+	}
+}
+
+func (b *eventBus) call(h EventHandler, e Event) {
+	if b.onPanic != nil {
+		defer recoverHandler(b.onPanic, "EventHandler")
+	}
+	h(e)
+}
+
+// Subscribe registers handler for session events. Calling returned func removes the handler.
+func (s *Session) Subscribe(h EventHandler) (unsubscribe func()) {
+	return s.events.subscribe(h)
+}
+
+// Events returns channel receiving session events, with given buffer size.
+//
+// Events are dropped when the channel buffer is full. Calling returned func
+// stops the subscription and closes the channel.
+func (s *Session) Events(buffer int) (<-chan Event, func()) {
+	ch := make(chan Event, buffer)
+	stop := s.subscribeChan(func(e Event) {
+		select {
+		case ch <- e:
+		default:
+		}
+	}, func() {
+		close(ch)
+	})
+	return ch, stop
+}
+
+// subscribeChan subscribes handler feeding a channel. Returned func unsubscribes and calls closeChan once,
+// handler is never called after that.
+func (s *Session) subscribeChan(h EventHandler, closeChan func()) func() {
+	var (
+		mu     sync.Mutex
+		closed bool
+	)
+
+	unsubscribe := s.events.subscribe(func(e Event) {
+		mu.Lock()
+		if !closed {
+			h(e)
+		}
+		mu.Unlock()
+	})
+
+	return func() {
+		unsubscribe()
+		mu.Lock()
+		if !closed {
+			closed = true
+			closeChan()
+		}
+		mu.Unlock()
+	}
+}
+
+// WithEventHandler subscribes handler to session events from its creation, so initial EventBound is observed.
+func WithEventHandler(h EventHandler) SessionOption {
+	return func(s *Session) {
+		s.events.subscribe(h)
+	}
+}
+
+// isDecodeError tells whether reading error is caused by invalid data rather than network.
+func isDecodeError(err error) bool {
+	if err == nil || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return false
+	}
+
+	var nErr net.Error
+	return !errors.As(err, &nErr)
+}
@@ -0,0 +1,102 @@
+package gosmpp
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventType_String(t *testing.T) {
+	require.Equal(t, "Bound", EventBound.String())
+	require.Equal(t, "Unbound", EventUnbound.String())
+	require.Equal(t, "Throttled", EventThrottled.String())
+	require.Equal(t, "WindowFull", EventWindowFull.String())
+	require.Equal(t, "EnquireLinkTimeout", EventEnquireLinkTimeout.String())
+	require.Equal(t, "DecodeError", EventDecodeError.String())
+	require.Equal(t, "Reconnected", EventReconnected.String())
+	require.Equal(t, "Saturated", EventSaturated.String())
+	require.Equal(t, "Resumed", EventResumed.String())
+	require.Equal(t, "", EventType(255).String())
+}
+
+func TestEventBus(t *testing.T) {
+	var (
+		bus   eventBus
+		mu    sync.Mutex
+		types []EventType
+	)
+
+	unsubscribe := bus.subscribe(func(e Event) {
+		mu.Lock()
+		types = append(types, e.Type)
+		mu.Unlock()
+	})
+
+	stats := newLinkStats()
+	stats.events = &bus
+
+	// throttled response
+	resp := pdu.NewSubmitSMResp()
+	resp.(*pdu.SubmitSMResp).CommandStatus = data.ESME_RTHROTTLED
+	stats.onReceived(resp)
+
+	// window full
+	stats.onSubmitError(pdu.NewSubmitSM(), ErrWindowsFull)
+
+	// second enquire_link while the first one is still not responded
+	stats.onWritten(pdu.NewEnquireLink())
+	stats.onWritten(pdu.NewEnquireLink())
+
+	// decode error
+	stats.onReceivingError(errors.New("invalid pdu"))
+
+	unsubscribe()
+	unsubscribe()
+	stats.onReceived(resp)
+
+	require.Equal(t, []EventType{EventThrottled, EventWindowFull, EventEnquireLinkTimeout, EventDecodeError}, types)
+}
+
+func TestSessionEvents(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		types []EventType
+	)
+
+	s, err := NewSession(
+		TRXConnector(NonTLSDialer, nextAuth()),
+		Settings{
+			ReadTimeout: 2 * time.Second,
+		}, 2*time.Second, WithEventHandler(func(e Event) {
+			mu.Lock()
+			types = append(types, e.Type)
+			mu.Unlock()
+		}))
+	require.Nil(t, err)
+
+	ch, stop := s.Events(10)
+
+	s.rebind()
+	require.Nil(t, s.Close())
+
+	mu.Lock()
+	require.Equal(t, []EventType{
+		EventBound,
+		EventSaturated, EventUnbound, // rebinding
+		EventBound, EventReconnected, EventResumed,
+		EventSaturated, EventUnbound, // closing
+	}, types)
+	mu.Unlock()
+
+	require.Equal(t, EventSaturated, (<-ch).Type)
+	require.Equal(t, EventUnbound, (<-ch).Type)
+	require.Equal(t, EventBound, (<-ch).Type)
+	stop()
+	stop()
+}
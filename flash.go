@@ -0,0 +1,25 @@
+package gosmpp
+
+import (
+	"context"
+	"errors"
+
+	"github.com/linxGnu/gosmpp/data"
+)
+
+// ErrFlashProtocolID indicates protocol_id of Messenger conflicts with flash message.
+var ErrFlashProtocolID = errors.New("protocol id conflicts with flash message")
+
+// SendFlash sends text as flash message (class 0), displayed immediately by handset and not stored.
+// Text is encoded and split as by SendText.
+//
+// Replace types and type 0 protocol_id are not allowed, returning ErrFlashProtocolID.
+func (m *Messenger) SendFlash(ctx context.Context, from, to, text string) (*MessageHandle, error) {
+	return m.Send(ctx, NewSubmitSMBuilder().From(from).To(to).Text(text).SetMessageClass(0))
+}
+
+// conflictsWithFlash reports whether protocol_id is type 0 or one of replace short message types,
+// not allowed for flash messages.
+func conflictsWithFlash(pid byte) bool {
+	return pid >= data.PID_SHORT_MESSAGE_TYPE_0 && pid <= data.PID_REPLACE_SHORT_MESSAGE_TYPE_7
+}
@@ -0,0 +1,50 @@
+package gosmpp
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp/data"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessengerSendFlash(t *testing.T) {
+	auth := nextAuth()
+	s, err := NewSession(TXConnector(NonTLSDialer, auth), Settings{ReadTimeout: 2 * time.Second}, -1)
+	require.Nil(t, err)
+	defer func() {
+		_ = s.Close()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	m := NewMessenger(s)
+	h, err := m.SendFlash(ctx, "MyBank", "84901234567", "Your code is 1234")
+	require.Nil(t, err)
+	require.Len(t, h.Parts, 1)
+	require.NotEmpty(t, h.MessageIDs[0])
+	require.Equal(t, byte(0x10), dataCodingOf(h.Parts[0]))
+	text, err := h.Parts[0].Message.GetMessage()
+	require.Nil(t, err)
+	require.Equal(t, "Your code is 1234", text)
+
+	h, err = m.SendFlash(ctx, "MyBank", "84901234567", strings.Repeat(mess, 5))
+	require.Nil(t, err)
+	require.Greater(t, len(h.Parts), 1)
+	for _, part := range h.Parts {
+		require.Equal(t, byte(0x18), dataCodingOf(part))
+	}
+
+	for _, pid := range []byte{data.PID_SHORT_MESSAGE_TYPE_0, data.PID_REPLACE_SHORT_MESSAGE_TYPE_1, 0x45, data.PID_REPLACE_SHORT_MESSAGE_TYPE_7} {
+		_, err = NewMessenger(s, WithProtocolID(pid)).SendFlash(ctx, "MyBank", "84901234567", "alert")
+		require.Equal(t, ErrFlashProtocolID, err)
+	}
+
+	h, err = NewMessenger(s, WithProtocolID(data.PID_RETURN_CALL_MESSAGE)).SendFlash(ctx, "MyBank", "84901234567", "alert")
+	require.Nil(t, err)
+	require.Equal(t, data.PID_RETURN_CALL_MESSAGE, h.Parts[0].ProtocolID)
+}
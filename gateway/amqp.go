@@ -0,0 +1,206 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/linxGnu/gosmpp"
+)
+
+// AMQPDelivery is message delivered from AMQP queue.
+type AMQPDelivery struct {
+	DeliveryTag   uint64
+	Redelivered   bool
+	RoutingKey    string
+	ReplyTo       string
+	CorrelationID string
+	Body          []byte
+}
+
+// AMQPSource consumes messages of send requests from AMQP 0.9.1 queue, e.g. adapter of channel consuming
+// with manual acknowledgements.
+type AMQPSource interface {
+	// Qos limits number of unacknowledged messages delivered to the consumer.
+	Qos(prefetch int) error
+
+	// Consume returns next delivered message, blocking until there is one or ctx is done.
+	Consume(ctx context.Context) (AMQPDelivery, error)
+
+	// Ack acknowledges message, removing it from the queue.
+	Ack(tag uint64) error
+
+	// Nack rejects message, returning it to the queue if requeue is set.
+	Nack(tag uint64, requeue bool) error
+}
+
+// AMQPConnector sends requests consumed from AMQP queue by Gateway, and publishes their results and events of
+// final deliveries and received messages to exchanges.
+//
+// Messages are SendRequest in JSON. Prefetch of the queue is the window of the connector: that many requests
+// are sent at once. Message is acknowledged once SMSC responded to its submit_sm, or it failed for good, and
+// its Result is published. Requests failing transiently, e.g. throttled ones, are rejected and requeued.
+//
+//	connector := &gateway.AMQPConnector{Gateway: gw, Source: source, Publisher: channel,
+//		ResultsExchange: "sms", ResultsRoutingKey: "results", EventsExchange: "sms-events", Prefetch: 30}
+//	tracker := gosmpp.NewDeliveryTracker(connector.HandleDelivery)
+//	settings.OnMessage = connector.HandleMessage
+//	err := connector.Run(ctx)
+type AMQPConnector struct {
+	// Gateway sends the requests.
+	Gateway *Gateway
+
+	// Source consumes messages of requests.
+	Source AMQPSource
+
+	// Publisher publishes results to ResultsExchange and events to EventsExchange, as JSON Result and Event,
+	// topic being the exchange and key the routing key.
+	// Results are routed by ReplyTo of request, or ResultsRoutingKey if it has none, events by EventType.
+	// Nothing is published to empty exchange.
+	Publisher         Publisher
+	ResultsExchange   string
+	ResultsRoutingKey string
+	EventsExchange    string
+
+	// Prefetch is number of requests sent at once, default is DefaultConnectorConcurrency.
+	// It should not exceed window of the sessions, see gosmpp.Settings.MaxWindowSize, so that requests wait
+	// in the queue rather than in the client.
+	Prefetch int
+
+	// RequeueDelay is duration to wait before requeueing request failing transiently, default is
+	// DefaultConnectorBackoff.
+	RequeueDelay time.Duration
+
+	// OnError is called with errors of publishing events.
+	OnError func(error)
+}
+
+// Run consumes and sends requests until ctx is done or consuming, publishing or acknowledging fails, returning
+// the error. Requests being sent when it stops are not acknowledged, they are redelivered once the channel
+// is closed.
+func (c *AMQPConnector) Run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	prefetch := c.Prefetch
+	if prefetch <= 0 {
+		prefetch = DefaultConnectorConcurrency
+	}
+	if err := c.Source.Qos(prefetch); err != nil {
+		return fmt.Errorf("gateway: setting prefetch: %w", err)
+	}
+
+	var (
+		wg       sync.WaitGroup
+		failOnce sync.Once
+		failure  error
+		slots    = make(chan struct{}, prefetch)
+	)
+	fail := func(err error) {
+		failOnce.Do(func() {
+			failure = err
+			cancel()
+		})
+	}
+
+	for {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		delivery, err := c.Source.Consume(ctx)
+		if err != nil {
+			<-slots
+			if ctx.Err() == nil {
+				fail(err)
+			}
+			break
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			if err := c.handle(ctx, delivery); err != nil {
+				fail(err)
+			}
+		}()
+	}
+
+	wg.Wait()
+	if failure != nil {
+		return failure
+	}
+	return ctx.Err()
+}
+
+// handle sends request of delivery, then publishes its result and acknowledges it, or requeues it if sending
+// failed transiently. Nothing is done if ctx is done before.
+func (c *AMQPConnector) handle(ctx context.Context, delivery AMQPDelivery) error {
+	result, transient := send(ctx, c.Gateway, delivery.Body)
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if transient {
+		delay := c.RequeueDelay
+		if delay <= 0 {
+			delay = DefaultConnectorBackoff
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+		if err := c.Source.Nack(delivery.DeliveryTag, true); err != nil {
+			return fmt.Errorf("gateway: requeueing request: %w", err)
+		}
+		return nil
+	}
+
+	if c.ResultsExchange != "" {
+		if result.CorrelationID == "" {
+			result.CorrelationID = delivery.CorrelationID
+		}
+		value, err := json.Marshal(result)
+		if err != nil {
+			return err
+		}
+		key := delivery.ReplyTo
+		if key == "" {
+			key = c.ResultsRoutingKey
+		}
+		if err = c.Publisher.Publish(ctx, c.ResultsExchange, []byte(key), value); err != nil {
+			return err
+		}
+	}
+
+	if err := c.Source.Ack(delivery.DeliveryTag); err != nil {
+		return fmt.Errorf("gateway: acknowledging request: %w", err)
+	}
+	return nil
+}
+
+// HandleDelivery publishes EventDelivery of d to EventsExchange.
+func (c *AMQPConnector) HandleDelivery(d gosmpp.Delivery) {
+	c.events().handleDelivery(d)
+}
+
+// HandleMessage publishes EventMessage of m to EventsExchange.
+func (c *AMQPConnector) HandleMessage(m gosmpp.IncomingMessage) {
+	c.events().handleMessage(m)
+}
+
+func (c *AMQPConnector) events() events {
+	return events{publisher: c.Publisher, topic: c.EventsExchange, timeout: c.Gateway.timeout(),
+		onError: c.OnError, keyByType: true}
+}
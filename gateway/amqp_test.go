@@ -0,0 +1,132 @@
+package gateway
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp"
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/server/smsctest"
+
+	"github.com/stretchr/testify/require"
+)
+
+// amqpQueue delivers messages in order, requeued ones again at the end, then blocks until ctx is done.
+type amqpQueue struct {
+	mu       sync.Mutex
+	prefetch int
+	queue    []AMQPDelivery
+	unacked  map[uint64]AMQPDelivery
+	acked    []uint64
+	requeued []uint64
+	nextTag  uint64
+}
+
+func (q *amqpQueue) Qos(prefetch int) error {
+	q.prefetch = prefetch
+	return nil
+}
+
+func (q *amqpQueue) Consume(ctx context.Context) (AMQPDelivery, error) {
+	q.mu.Lock()
+	if len(q.queue) > 0 {
+		d := q.queue[0]
+		q.queue = q.queue[1:]
+		q.nextTag++
+		d.DeliveryTag = q.nextTag
+		q.unacked[d.DeliveryTag] = d
+		q.mu.Unlock()
+		return d, nil
+	}
+	q.mu.Unlock()
+
+	<-ctx.Done()
+	return AMQPDelivery{}, ctx.Err()
+}
+
+func (q *amqpQueue) Ack(tag uint64) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	delete(q.unacked, tag)
+	q.acked = append(q.acked, tag)
+	return nil
+}
+
+func (q *amqpQueue) Nack(tag uint64, requeue bool) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	d := q.unacked[tag]
+	delete(q.unacked, tag)
+	if requeue {
+		d.Redelivered = true
+		q.queue = append(q.queue, d)
+		q.requeued = append(q.requeued, tag)
+	}
+	return nil
+}
+
+func (q *amqpQueue) counts() (acked, requeued, unacked int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.acked), len(q.requeued), len(q.unacked) + len(q.queue)
+}
+
+func TestAMQPConnector(t *testing.T) {
+	smsc := smsctest.NewPipeServer(nil)
+	defer smsc.Close()
+	smsc.SetFault(smsctest.Script(smsctest.Fault{Status: data.ESME_RTHROTTLED}))
+
+	queue := &amqpQueue{unacked: make(map[uint64]AMQPDelivery), queue: []AMQPDelivery{
+		{ReplyTo: "shop", Body: []byte(`{"from":"MyShop","to":"+84901234567","text":"first","correlation_id":"order-1"}`)},
+		{CorrelationID: "order-2", Body: []byte(`{"from":"MyShop","text":"no destination"}`)},
+	}}
+	pub := &publisher{}
+	c := &AMQPConnector{
+		Gateway:           newGateway(t, smsc),
+		Source:            queue,
+		Publisher:         pub,
+		ResultsExchange:   "sms",
+		ResultsRoutingKey: "results",
+		EventsExchange:    "sms-events",
+		Prefetch:          1,
+		RequeueDelay:      time.Millisecond,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- c.Run(ctx)
+	}()
+
+	require.Eventually(t, func() bool {
+		acked, _, unacked := queue.counts()
+		return acked == 2 && unacked == 0
+	}, 5*time.Second, time.Millisecond)
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+	require.Equal(t, 1, queue.prefetch)
+
+	// the first one was throttled and requeued
+	_, requeued, _ := queue.counts()
+	require.Equal(t, 1, requeued)
+	smsc.ExpectReceived(t, data.SUBMIT_SM, 2)
+
+	results := pub.results(t, "sms")
+	require.Len(t, results, 2)
+	require.Equal(t, "order-1", results["shop"].CorrelationID)
+	require.NotEmpty(t, results["shop"].ID)
+	require.Equal(t, "order-2", results["results"].CorrelationID)
+	require.Equal(t, "destination is required", results["results"].Error)
+
+	c.HandleDelivery(gosmpp.Delivery{HandleID: "h1", Status: gosmpp.DeliveryDelivered})
+	c.HandleMessage(gosmpp.IncomingMessage{From: "+84901234567", To: "8888", Text: "HELP"})
+	var keys []string
+	for _, r := range pub.records {
+		if r.topic == "sms-events" {
+			keys = append(keys, string(r.key))
+		}
+	}
+	require.Equal(t, []string{EventDelivery, EventMessage}, keys)
+}
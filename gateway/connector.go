@@ -0,0 +1,107 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/linxGnu/gosmpp"
+	"github.com/linxGnu/gosmpp/data"
+)
+
+// DefaultConnectorConcurrency is default number of requests connectors send at once.
+const DefaultConnectorConcurrency = 10
+
+// Publisher publishes records to topic of message broker, e.g. Kafka producer or AMQP channel publishing
+// to exchange. Publish returns once the broker has the record.
+type Publisher interface {
+	Publish(ctx context.Context, topic string, key, value []byte) error
+}
+
+// Result is outcome of send request consumed by connector, published to its results topic as JSON.
+type Result struct {
+	// ID and MessageIDs are of sent message, see SendResponse. Empty if sending failed.
+	ID         string   `json:"id,omitempty"`
+	MessageIDs []string `json:"message_ids,omitempty"`
+
+	// CorrelationID is of the request.
+	CorrelationID string `json:"correlation_id,omitempty"`
+
+	// Error and CommandStatus tell why sending failed, see Error.
+	Error         string `json:"error,omitempty"`
+	CommandStatus uint32 `json:"command_status,omitempty"`
+}
+
+// send decodes send request from value and sends it by g, returning its result and whether failure is
+// transient: no messenger could submit it, SMSC did not respond in time or throttled it.
+func send(ctx context.Context, g *Gateway, value []byte) (r Result, transient bool) {
+	var req SendRequest
+	if err := json.Unmarshal(value, &req); err != nil {
+		return Result{Error: fmt.Sprintf("invalid request: %v", err)}, false
+	}
+	r.CorrelationID = req.CorrelationID
+	if err := req.Validate(); err != nil {
+		r.Error = err.Error()
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, g.timeout())
+	defer cancel()
+
+	h, err := g.Send(ctx, req)
+	if err == nil {
+		r.ID, r.MessageIDs = h.ID, h.MessageIDs
+		return
+	}
+
+	code, e := SendErrorResponse(ctx, h, err)
+	r.Error, r.CommandStatus = e.Error, e.CommandStatus
+	switch code {
+	case http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		transient = true
+	case http.StatusBadGateway:
+		status := data.CommandStatusType(e.CommandStatus)
+		transient = status == data.ESME_RTHROTTLED || status == data.ESME_RMSGQFUL
+	}
+	return
+}
+
+// events publishes events of final deliveries and received messages to topic as JSON, keyed by message
+// id or source address, or by event type if keyByType is set. Nothing is published to empty topic.
+type events struct {
+	publisher Publisher
+	topic     string
+	timeout   time.Duration
+	onError   func(error)
+	keyByType bool
+}
+
+func (es events) handleDelivery(d gosmpp.Delivery) {
+	status := NewStatus(d)
+	es.publish([]byte(status.ID), Event{Type: EventDelivery, Delivery: &status})
+}
+
+func (es events) handleMessage(m gosmpp.IncomingMessage) {
+	msg := NewMessage(m)
+	es.publish([]byte(msg.From), Event{Type: EventMessage, Message: &msg})
+}
+
+func (es events) publish(key []byte, e Event) {
+	if es.topic == "" {
+		return
+	}
+	if es.keyByType {
+		key = []byte(e.Type)
+	}
+	value, err := json.Marshal(e)
+	if err == nil {
+		ctx, cancel := context.WithTimeout(context.Background(), es.timeout)
+		err = es.publisher.Publish(ctx, es.topic, key, value)
+		cancel()
+	}
+	if err != nil && es.onError != nil {
+		es.onError(fmt.Errorf("gateway: publishing %s event: %w", e.Type, err))
+	}
+}
@@ -0,0 +1,90 @@
+package gateway
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/linxGnu/gosmpp"
+)
+
+// DefaultSubscriptionBuffer is default number of events buffered for subscriber.
+const DefaultSubscriptionBuffer = 256
+
+// Events fans out events of final deliveries and received messages to subscribers, e.g. to streams of
+// StreamEvents of the gRPC service in grpc/messaging.proto or to server-sent events clients.
+//
+// Events are fed by HandleDelivery and HandleMessage like Webhook. They are not kept: subscriber receives
+// events dispatched while it is subscribed, and events not fitting into its buffer are dropped for it.
+type Events struct {
+	mu            sync.Mutex
+	subscriptions map[*Subscription]struct{}
+}
+
+// Subscription receives events dispatched by Events until closed.
+type Subscription struct {
+	// C receives events, it is closed by Close.
+	C <-chan Event
+
+	c       chan Event
+	events  *Events
+	dropped uint64
+}
+
+// Subscribe returns subscription buffering given number of events, DefaultSubscriptionBuffer if not positive.
+func (es *Events) Subscribe(buffer int) *Subscription {
+	if buffer <= 0 {
+		buffer = DefaultSubscriptionBuffer
+	}
+	c := make(chan Event, buffer)
+	s := &Subscription{C: c, c: c, events: es}
+
+	es.mu.Lock()
+	defer es.mu.Unlock()
+	if es.subscriptions == nil {
+		es.subscriptions = make(map[*Subscription]struct{})
+	}
+	es.subscriptions[s] = struct{}{}
+	return s
+}
+
+// HandleDelivery dispatches EventDelivery of d.
+func (es *Events) HandleDelivery(d gosmpp.Delivery) {
+	status := NewStatus(d)
+	es.Dispatch(Event{Type: EventDelivery, Delivery: &status})
+}
+
+// HandleMessage dispatches EventMessage of m.
+func (es *Events) HandleMessage(m gosmpp.IncomingMessage) {
+	msg := NewMessage(m)
+	es.Dispatch(Event{Type: EventMessage, Message: &msg})
+}
+
+// Dispatch sends event to all subscribers, without waiting for slow ones.
+func (es *Events) Dispatch(e Event) {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+
+	for s := range es.subscriptions {
+		select {
+		case s.c <- e:
+		default:
+			atomic.AddUint64(&s.dropped, 1)
+		}
+	}
+}
+
+// Dropped returns number of events dropped as the buffer was full.
+func (s *Subscription) Dropped() uint64 {
+	return atomic.LoadUint64(&s.dropped)
+}
+
+// Close unsubscribes, closing C.
+func (s *Subscription) Close() {
+	s.events.mu.Lock()
+	defer s.events.mu.Unlock()
+
+	if _, ok := s.events.subscriptions[s]; ok {
+		delete(s.events.subscriptions, s)
+		close(s.c)
+	}
+}
@@ -0,0 +1,40 @@
+package gateway
+
+import (
+	"testing"
+
+	"github.com/linxGnu/gosmpp"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventsSubscribe(t *testing.T) {
+	var es Events
+	es.HandleMessage(gosmpp.IncomingMessage{Text: "before"})
+
+	all := es.Subscribe(0)
+	slow := es.Subscribe(1)
+
+	es.HandleDelivery(gosmpp.Delivery{HandleID: "h1", Status: gosmpp.DeliveryDelivered})
+	es.HandleMessage(gosmpp.IncomingMessage{From: "+84901234567", To: "8888", Text: "HELP"})
+
+	e := <-all.C
+	require.Equal(t, EventDelivery, e.Type)
+	require.Equal(t, "h1", e.Delivery.ID)
+	require.Equal(t, "delivered", e.Delivery.Status)
+	e = <-all.C
+	require.Equal(t, EventMessage, e.Type)
+	require.Equal(t, "HELP", e.Message.Text)
+	require.Zero(t, all.Dropped())
+
+	require.Equal(t, EventDelivery, (<-slow.C).Type)
+	require.Equal(t, uint64(1), slow.Dropped())
+
+	slow.Close()
+	slow.Close()
+	_, ok := <-slow.C
+	require.False(t, ok)
+
+	es.Dispatch(Event{Type: EventMessage})
+	require.Len(t, all.C, 1)
+}
@@ -0,0 +1,295 @@
+// Package gateway bridges HTTP to SMPP, exposing sessions of the client to services which speak HTTP only.
+//
+// Gateway is http.Handler serving:
+//
+//	POST /messages       sends message, see SendRequest, and responds with 202 and SendResponse
+//	GET  /messages/{id}  responds with Status of sent message
+//
+// Delivery receipts and mobile originated messages are posted to webhook by Webhook, or streamed to subscribers
+// of Events. Module gateway/grpc exposes the same over gRPC, as service of messaging.proto.
+//
+//	tracker := gosmpp.NewDeliveryTracker(webhook.HandleDelivery)
+//	settings.OnDeliveryReceipt = func(r gosmpp.Receipt) { _, _ = tracker.HandleReceipt(context.Background(), r) }
+//	settings.OnMessage = webhook.HandleMessage
+//	...
+//	gw := &gateway.Gateway{
+//		Messengers: []*gosmpp.Messenger{gosmpp.NewMessenger(session,
+//			gosmpp.WithRegisteredDelivery(data.SM_SMSC_RECEIPT_REQUESTED), gosmpp.WithDeliveryTracker(tracker))},
+//		Tracker: tracker,
+//	}
+//	http.Handle("/sms/", http.StripPrefix("/sms", gw))
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/linxGnu/gosmpp"
+)
+
+const (
+	// DefaultSendTimeout is default duration to wait for SMSC to accept all parts of sent message.
+	DefaultSendTimeout = 30 * time.Second
+
+	// MaxRequestBody is maximum size of request body in bytes.
+	MaxRequestBody = 64 << 10
+)
+
+// ErrNoMessengers indicates Gateway has no messengers to send with.
+var ErrNoMessengers = errors.New("gateway: no messengers")
+
+// SendRequest is body of POST /messages: text message, or binary one if Data is set.
+type SendRequest struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+
+	// Text is sent as by Messenger.SendText.
+	Text string `json:"text,omitempty"`
+
+	// Data is binary payload, base64 in JSON, sent to application Port of destination as by Messenger.SendBinary.
+	Data []byte `json:"data,omitempty"`
+	Port uint16 `json:"port,omitempty"`
+
+	// CorrelationID and Metadata are attached to the message as its gosmpp.Correlation.
+	CorrelationID string            `json:"correlation_id,omitempty"`
+	Metadata      map[string]string `json:"metadata,omitempty"`
+}
+
+// SendResponse is body of response to POST /messages.
+type SendResponse struct {
+	// ID identifies the message in GET /messages/{id}.
+	ID string `json:"id"`
+
+	// MessageIDs are message_id assigned by SMSC to the parts, in order.
+	MessageIDs []string `json:"message_ids"`
+}
+
+// Status is delivery status of sent message, body of response to GET /messages/{id} and of delivery events.
+type Status struct {
+	ID string `json:"id"`
+
+	// Status is "pending", "delivered" or "failed", see gosmpp.DeliveryStatus.
+	Status string `json:"status"`
+
+	// MessageIDs are message_id of the parts and States their last received message states, zero until
+	// receipt is received.
+	MessageIDs []string `json:"message_ids"`
+	States     []int    `json:"states"`
+
+	CorrelationID string            `json:"correlation_id,omitempty"`
+	Metadata      map[string]string `json:"metadata,omitempty"`
+}
+
+// NewStatus returns status of delivery d.
+func NewStatus(d gosmpp.Delivery) Status {
+	states := make([]int, len(d.States))
+	for i, state := range d.States {
+		states[i] = int(state)
+	}
+	return Status{
+		ID:            d.HandleID,
+		Status:        d.Status.String(),
+		MessageIDs:    d.MessageIDs,
+		States:        states,
+		CorrelationID: d.Correlation.ID,
+		Metadata:      d.Correlation.Metadata,
+	}
+}
+
+// Error is body of error responses.
+type Error struct {
+	Error string `json:"error"`
+
+	// CommandStatus is command status of submit_sm_resp rejecting the message, zero for other errors.
+	CommandStatus uint32 `json:"command_status,omitempty"`
+}
+
+// Gateway is http.Handler sending messages by pool of messengers, see package doc.
+//
+// Responses of POST /messages are:
+//   - 202 once all parts are accepted by SMSC
+//   - 400 for invalid request
+//   - 422 if message could not be built, e.g. of invalid address, or is vetoed by pricing hook
+//   - 502 if SMSC rejects a part, with its command status
+//   - 503 if no messenger could submit the message
+//   - 504 if SMSC does not accept all parts within Timeout
+type Gateway struct {
+	// Messengers send messages, typically one per session. They are chosen round-robin and if submitting
+	// fails before any part is accepted, e.g. session is rebinding, the next one is tried.
+	Messengers []*gosmpp.Messenger
+
+	// Tracker serves GET /messages/{id}, it should track messages of Messengers, see gosmpp.WithDeliveryTracker.
+	// Status is not served if nil.
+	Tracker *gosmpp.DeliveryTracker
+
+	// Timeout is duration to wait for SMSC to accept all parts, default is DefaultSendTimeout.
+	Timeout time.Duration
+
+	next uint32
+}
+
+// ServeHTTP implements http.Handler.
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch path := strings.TrimSuffix(r.URL.Path, "/"); {
+	case path == "/messages":
+		if r.Method != http.MethodPost {
+			methodNotAllowed(w, http.MethodPost)
+			return
+		}
+		g.serveSend(w, r)
+
+	case strings.HasPrefix(path, "/messages/") && !strings.Contains(path[len("/messages/"):], "/"):
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			methodNotAllowed(w, http.MethodGet)
+			return
+		}
+		g.serveStatus(w, r, path[len("/messages/"):])
+
+	default:
+		writeJSON(w, http.StatusNotFound, Error{Error: "not found"})
+	}
+}
+
+func (g *Gateway) serveSend(w http.ResponseWriter, r *http.Request) {
+	var req SendRequest
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, MaxRequestBody))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, Error{Error: fmt.Sprintf("invalid request: %v", err)})
+		return
+	}
+	if err := req.Validate(); err != nil {
+		writeJSON(w, http.StatusBadRequest, Error{Error: err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), g.timeout())
+	defer cancel()
+
+	h, err := g.Send(ctx, req)
+	if err != nil {
+		code, e := SendErrorResponse(ctx, h, err)
+		writeJSON(w, code, e)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, SendResponse{ID: h.ID, MessageIDs: h.MessageIDs})
+}
+
+func (g *Gateway) serveStatus(w http.ResponseWriter, r *http.Request, id string) {
+	status, ok, err := g.Status(r.Context(), id)
+	switch {
+	case err != nil:
+		writeJSON(w, http.StatusServiceUnavailable, Error{Error: err.Error()})
+	case !ok:
+		writeJSON(w, http.StatusNotFound, Error{Error: "message not found"})
+	default:
+		writeJSON(w, http.StatusOK, status)
+	}
+}
+
+// Status returns status of message sent by Send, reporting false if it is unknown or there is no Tracker.
+func (g *Gateway) Status(ctx context.Context, id string) (Status, bool, error) {
+	if g.Tracker == nil || id == "" {
+		return Status{}, false, nil
+	}
+
+	d, ok, err := g.Tracker.Status(ctx, id)
+	if err != nil || !ok {
+		return Status{}, false, err
+	}
+	return NewStatus(d), true, nil
+}
+
+// Send sends message of req by the next messenger of the pool, trying the others if submitting fails
+// before any part is accepted.
+func (g *Gateway) Send(ctx context.Context, req SendRequest) (h *gosmpp.MessageHandle, err error) {
+	if len(g.Messengers) == 0 {
+		return nil, ErrNoMessengers
+	}
+	if req.CorrelationID != "" || len(req.Metadata) > 0 {
+		ctx = gosmpp.ContextWithCorrelation(ctx, gosmpp.Correlation{ID: req.CorrelationID, Metadata: req.Metadata})
+	}
+
+	start := atomic.AddUint32(&g.next, 1)
+	for i := range g.Messengers {
+		m := g.Messengers[(start+uint32(i))%uint32(len(g.Messengers))]
+		if req.Data != nil {
+			h, err = m.SendBinary(ctx, req.From, req.To, req.Port, 0, req.Data)
+		} else {
+			h, err = m.SendText(ctx, req.From, req.To, req.Text)
+		}
+		if !retryable(ctx, h, err) {
+			return
+		}
+	}
+	return
+}
+
+func (g *Gateway) timeout() time.Duration {
+	if g.Timeout > 0 {
+		return g.Timeout
+	}
+	return DefaultSendTimeout
+}
+
+// retryable reports whether sending failed before any part is accepted, for reasons of the session.
+func retryable(ctx context.Context, h *gosmpp.MessageHandle, err error) bool {
+	var submitErr *gosmpp.SubmitError
+	if err == nil || h == nil || ctx.Err() != nil || errors.As(err, &submitErr) {
+		return false
+	}
+	for _, id := range h.MessageIDs {
+		if id != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// Validate checks that req has addresses and either text or data.
+func (req SendRequest) Validate() error {
+	switch {
+	case req.From == "":
+		return errors.New("source is required")
+	case req.To == "":
+		return errors.New("destination is required")
+	case req.Data != nil && req.Text != "":
+		return errors.New("text and data are exclusive")
+	case req.Data == nil && req.Text == "":
+		return errors.New("text or data is required")
+	}
+	return nil
+}
+
+// SendErrorResponse returns HTTP status code and body of response to error of Send, from which transports
+// other than HTTP map errors to their own codes.
+func SendErrorResponse(ctx context.Context, h *gosmpp.MessageHandle, err error) (int, Error) {
+	var submitErr *gosmpp.SubmitError
+	switch {
+	case errors.As(err, &submitErr):
+		return http.StatusBadGateway, Error{Error: err.Error(), CommandStatus: uint32(submitErr.Status)}
+	case ctx.Err() != nil:
+		return http.StatusGatewayTimeout, Error{Error: err.Error()}
+	case h == nil && !errors.Is(err, ErrNoMessengers):
+		return http.StatusUnprocessableEntity, Error{Error: err.Error()}
+	default:
+		return http.StatusServiceUnavailable, Error{Error: err.Error()}
+	}
+}
+
+func methodNotAllowed(w http.ResponseWriter, allow string) {
+	w.Header().Set("Allow", allow)
+	writeJSON(w, http.StatusMethodNotAllowed, Error{Error: "method not allowed"})
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
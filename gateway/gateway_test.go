@@ -0,0 +1,169 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp"
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/server/smsctest"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newSession returns transceiver session bound to smsc, feeding receipts to tracker.
+func newSession(t *testing.T, smsc *smsctest.Server, tracker *gosmpp.DeliveryTracker) *gosmpp.Session {
+	s, err := gosmpp.NewSession(gosmpp.TRXConnector(smsc.Dialer(), gosmpp.Auth{SMSC: "pipe", SystemID: "esme"}),
+		gosmpp.Settings{
+			ReadTimeout: 2 * time.Second,
+
+			OnDeliveryReceipt: func(r gosmpp.Receipt) { _, _ = tracker.HandleReceipt(context.Background(), r) },
+		}, -1)
+	require.Nil(t, err)
+	t.Cleanup(func() {
+		_ = s.Close()
+	})
+	return s
+}
+
+func newMessenger(s *gosmpp.Session, tracker *gosmpp.DeliveryTracker) *gosmpp.Messenger {
+	return gosmpp.NewMessenger(s,
+		gosmpp.WithRegisteredDelivery(data.SM_SMSC_RECEIPT_REQUESTED), gosmpp.WithDeliveryTracker(tracker))
+}
+
+func serve(g *Gateway, method, path, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	g.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+	return w
+}
+
+func TestGatewaySendAndStatus(t *testing.T) {
+	smsc := smsctest.NewPipeServer(nil)
+	defer smsc.Close()
+
+	delivered := make(chan gosmpp.Delivery, 1)
+	tracker := gosmpp.NewDeliveryTracker(func(d gosmpp.Delivery) { delivered <- d })
+	g := &Gateway{
+		Messengers: []*gosmpp.Messenger{newMessenger(newSession(t, smsc, tracker), tracker)},
+		Tracker:    tracker,
+	}
+
+	w := serve(g, http.MethodPost, "/messages",
+		`{"from":"MyShop","to":"+84901234567","text":"Your order is shipped","correlation_id":"order-1"}`)
+	require.Equal(t, http.StatusAccepted, w.Code)
+	require.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+	var sent SendResponse
+	require.Nil(t, json.Unmarshal(w.Body.Bytes(), &sent))
+	require.NotEmpty(t, sent.ID)
+	require.Len(t, sent.MessageIDs, 1)
+	smsc.ExpectSubmitSM(t, "84901234567", "Your order is shipped")
+
+	w = serve(g, http.MethodGet, "/messages/"+sent.ID, "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var status Status
+	require.Nil(t, json.Unmarshal(w.Body.Bytes(), &status))
+	require.Equal(t, Status{
+		ID:            sent.ID,
+		Status:        "pending",
+		MessageIDs:    sent.MessageIDs,
+		States:        []int{0},
+		CorrelationID: "order-1",
+	}, status)
+
+	smsc.SetReceipts(&smsctest.Receipts{Delay: 100 * time.Millisecond})
+	w = serve(g, http.MethodPost, "/messages", `{"from":"MyShop","to":"+84901234567","data":"AQID","port":2948}`)
+	require.Equal(t, http.StatusAccepted, w.Code)
+	require.Nil(t, json.Unmarshal(w.Body.Bytes(), &sent))
+
+	select {
+	case d := <-delivered:
+		require.Equal(t, sent.ID, d.HandleID)
+	case <-time.After(5 * time.Second):
+		t.Fatal("message is not delivered")
+	}
+
+	w = serve(g, http.MethodGet, "/messages/"+sent.ID, "")
+	require.Nil(t, json.Unmarshal(w.Body.Bytes(), &status))
+	require.Equal(t, "delivered", status.Status)
+	require.Equal(t, []int{data.SM_STATE_DELIVERED}, status.States)
+}
+
+func TestGatewayInvalidRequests(t *testing.T) {
+	smsc := smsctest.NewPipeServer(nil)
+	defer smsc.Close()
+
+	tracker := gosmpp.NewDeliveryTracker(nil)
+	g := &Gateway{
+		Messengers: []*gosmpp.Messenger{newMessenger(newSession(t, smsc, tracker), tracker)},
+		Tracker:    tracker,
+	}
+
+	for _, body := range []string{
+		`{"to":`,
+		`{"to":"+84901234567","text":"hi","unknown":1}`,
+		`{"to":"+84901234567","text":"hi"}`,
+		`{"from":"MyShop","text":"hi"}`,
+		`{"from":"MyShop","to":"+84901234567"}`,
+		`{"from":"MyShop","to":"+84901234567","text":"hi","data":"AQID"}`,
+	} {
+		w := serve(g, http.MethodPost, "/messages", body)
+		require.Equal(t, http.StatusBadRequest, w.Code, body)
+
+		var e Error
+		require.Nil(t, json.Unmarshal(w.Body.Bytes(), &e))
+		require.NotEmpty(t, e.Error)
+	}
+
+	w := serve(g, http.MethodPost, "/messages", `{"from":"not a sender id at all","to":"+84901234567","text":"hi"}`)
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+	require.Equal(t, http.StatusNotFound, serve(g, http.MethodGet, "/messages/unknown", "").Code)
+	require.Equal(t, http.StatusNotFound, serve(g, http.MethodGet, "/other", "").Code)
+
+	w = serve(g, http.MethodGet, "/messages", "")
+	require.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	require.Equal(t, http.MethodPost, w.Header().Get("Allow"))
+	require.Equal(t, http.StatusMethodNotAllowed, serve(g, http.MethodDelete, "/messages/1", "").Code)
+
+	smsc.SetFault(smsctest.RespondStatus(data.ESME_RTHROTTLED))
+	w = serve(g, http.MethodPost, "/messages", `{"from":"MyShop","to":"+84901234567","text":"hi"}`)
+	require.Equal(t, http.StatusBadGateway, w.Code)
+	var e Error
+	require.Nil(t, json.Unmarshal(w.Body.Bytes(), &e))
+	require.Equal(t, uint32(data.ESME_RTHROTTLED), e.CommandStatus)
+
+	require.Equal(t, http.StatusServiceUnavailable,
+		serve(&Gateway{}, http.MethodPost, "/messages", `{"from":"MyShop","to":"+84901234567","text":"hi"}`).Code)
+}
+
+func TestGatewayPoolFailover(t *testing.T) {
+	smsc := smsctest.NewPipeServer(nil)
+	defer smsc.Close()
+
+	tracker := gosmpp.NewDeliveryTracker(nil)
+	closed := newSession(t, smsc, tracker)
+	require.Nil(t, closed.Close())
+
+	g := &Gateway{
+		Messengers: []*gosmpp.Messenger{
+			newMessenger(closed, tracker),
+			newMessenger(newSession(t, smsc, tracker), tracker),
+		},
+	}
+
+	for i := 0; i < 2; i++ {
+		w := serve(g, http.MethodPost, "/messages", `{"from":"MyShop","to":"+84901234567","text":"hi"}`)
+		require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	}
+	smsc.ExpectReceived(t, data.SUBMIT_SM, 2)
+
+	g.Messengers = g.Messengers[:1]
+	require.Equal(t, http.StatusServiceUnavailable,
+		serve(g, http.MethodPost, "/messages", `{"from":"MyShop","to":"+84901234567","text":"hi"}`).Code)
+}
@@ -0,0 +1,234 @@
+// Messaging is gRPC facade of the gateway, for services not speaking Go.
+//
+// This directory is module of its own, so that only its importers depend on gRPC. Generated code is package
+// messagingpb, regenerate it from root of the repository with protoc-gen-go and protoc-gen-go-grpc:
+//
+//   protoc --go_out=. --go_opt=module=github.com/linxGnu/gosmpp \
+//     --go-grpc_out=. --go-grpc_opt=module=github.com/linxGnu/gosmpp gateway/grpc/messaging.proto
+//
+// MessagingService of this package implements the service by gateway.Gateway and gateway.Events:
+//
+//   Send          Gateway.Send
+//   GetStatus     Gateway.Status, NOT_FOUND if not found
+//   StreamEvents  Events.Subscribe, sending events of Subscription.C until the stream is done
+//
+// Errors of Send map to status codes as HTTP responses of Gateway: INVALID_ARGUMENT (400),
+// FAILED_PRECONDITION (422), UNAVAILABLE (502 and 503) and DEADLINE_EXCEEDED (504).
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: gateway/grpc/messaging.proto
+
+package messagingpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	Messaging_Send_FullMethodName         = "/gosmpp.gateway.v1.Messaging/Send"
+	Messaging_GetStatus_FullMethodName    = "/gosmpp.gateway.v1.Messaging/GetStatus"
+	Messaging_StreamEvents_FullMethodName = "/gosmpp.gateway.v1.Messaging/StreamEvents"
+)
+
+// MessagingClient is the client API for Messaging service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type MessagingClient interface {
+	// Send sends message and returns once all parts are accepted by SMSC.
+	Send(ctx context.Context, in *SendRequest, opts ...grpc.CallOption) (*SendResponse, error)
+	// GetStatus returns delivery status of sent message.
+	GetStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*Status, error)
+	// StreamEvents streams final deliveries and received messages, from the time of the call.
+	StreamEvents(ctx context.Context, in *StreamEventsRequest, opts ...grpc.CallOption) (Messaging_StreamEventsClient, error)
+}
+
+type messagingClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewMessagingClient(cc grpc.ClientConnInterface) MessagingClient {
+	return &messagingClient{cc}
+}
+
+func (c *messagingClient) Send(ctx context.Context, in *SendRequest, opts ...grpc.CallOption) (*SendResponse, error) {
+	out := new(SendResponse)
+	err := c.cc.Invoke(ctx, Messaging_Send_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *messagingClient) GetStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*Status, error) {
+	out := new(Status)
+	err := c.cc.Invoke(ctx, Messaging_GetStatus_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *messagingClient) StreamEvents(ctx context.Context, in *StreamEventsRequest, opts ...grpc.CallOption) (Messaging_StreamEventsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Messaging_ServiceDesc.Streams[0], Messaging_StreamEvents_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &messagingStreamEventsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Messaging_StreamEventsClient interface {
+	Recv() (*Event, error)
+	grpc.ClientStream
+}
+
+type messagingStreamEventsClient struct {
+	grpc.ClientStream
+}
+
+func (x *messagingStreamEventsClient) Recv() (*Event, error) {
+	m := new(Event)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// MessagingServer is the server API for Messaging service.
+// All implementations must embed UnimplementedMessagingServer
+// for forward compatibility
+type MessagingServer interface {
+	// Send sends message and returns once all parts are accepted by SMSC.
+	Send(context.Context, *SendRequest) (*SendResponse, error)
+	// GetStatus returns delivery status of sent message.
+	GetStatus(context.Context, *GetStatusRequest) (*Status, error)
+	// StreamEvents streams final deliveries and received messages, from the time of the call.
+	StreamEvents(*StreamEventsRequest, Messaging_StreamEventsServer) error
+	mustEmbedUnimplementedMessagingServer()
+}
+
+// UnimplementedMessagingServer must be embedded to have forward compatible implementations.
+type UnimplementedMessagingServer struct {
+}
+
+func (UnimplementedMessagingServer) Send(context.Context, *SendRequest) (*SendResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Send not implemented")
+}
+func (UnimplementedMessagingServer) GetStatus(context.Context, *GetStatusRequest) (*Status, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStatus not implemented")
+}
+func (UnimplementedMessagingServer) StreamEvents(*StreamEventsRequest, Messaging_StreamEventsServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamEvents not implemented")
+}
+func (UnimplementedMessagingServer) mustEmbedUnimplementedMessagingServer() {}
+
+// UnsafeMessagingServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to MessagingServer will
+// result in compilation errors.
+type UnsafeMessagingServer interface {
+	mustEmbedUnimplementedMessagingServer()
+}
+
+func RegisterMessagingServer(s grpc.ServiceRegistrar, srv MessagingServer) {
+	s.RegisterService(&Messaging_ServiceDesc, srv)
+}
+
+func _Messaging_Send_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MessagingServer).Send(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Messaging_Send_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MessagingServer).Send(ctx, req.(*SendRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Messaging_GetStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MessagingServer).GetStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Messaging_GetStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MessagingServer).GetStatus(ctx, req.(*GetStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Messaging_StreamEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamEventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MessagingServer).StreamEvents(m, &messagingStreamEventsServer{stream})
+}
+
+type Messaging_StreamEventsServer interface {
+	Send(*Event) error
+	grpc.ServerStream
+}
+
+type messagingStreamEventsServer struct {
+	grpc.ServerStream
+}
+
+func (x *messagingStreamEventsServer) Send(m *Event) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// Messaging_ServiceDesc is the grpc.ServiceDesc for Messaging service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Messaging_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "gosmpp.gateway.v1.Messaging",
+	HandlerType: (*MessagingServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Send",
+			Handler:    _Messaging_Send_Handler,
+		},
+		{
+			MethodName: "GetStatus",
+			Handler:    _Messaging_GetStatus_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamEvents",
+			Handler:       _Messaging_StreamEvents_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "gateway/grpc/messaging.proto",
+}
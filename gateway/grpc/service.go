@@ -0,0 +1,152 @@
+// Package grpc serves gateway over gRPC, as Messaging service of messaging.proto. It is module of its own, so
+// that package gateway and its importers do not depend on gRPC:
+//
+//	srv := grpc.NewServer()
+//	messagingpb.RegisterMessagingServer(srv, &gatewaygrpc.MessagingService{Gateway: gw, Events: events})
+package grpc
+
+import (
+	"context"
+	"math"
+	"net/http"
+
+	"github.com/linxGnu/gosmpp/gateway"
+	"github.com/linxGnu/gosmpp/gateway/grpc/messagingpb"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// MessagingService implements Messaging service of messaging.proto by gateway.Gateway and gateway.Events.
+//
+// Errors of Send map to status codes as responses of Gateway to HTTP codes: InvalidArgument (400),
+// FailedPrecondition (422), Unavailable (502 and 503) and DeadlineExceeded (504).
+type MessagingService struct {
+	messagingpb.UnimplementedMessagingServer
+
+	// Gateway serves Send and GetStatus.
+	Gateway *gateway.Gateway
+
+	// Events serves StreamEvents, which is unimplemented if nil.
+	Events *gateway.Events
+}
+
+// Send implements messagingpb.MessagingServer.
+func (ms *MessagingService) Send(ctx context.Context, in *messagingpb.SendRequest) (*messagingpb.SendResponse, error) {
+	if in.GetPort() > math.MaxUint16 {
+		return nil, status.Error(codes.InvalidArgument, "port is out of range")
+	}
+	req := gateway.SendRequest{
+		From:          in.GetFrom(),
+		To:            in.GetTo(),
+		Text:          in.GetText(),
+		Data:          in.GetData(),
+		Port:          uint16(in.GetPort()),
+		CorrelationID: in.GetCorrelationId(),
+		Metadata:      in.GetMetadata(),
+	}
+	if err := req.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	timeout := ms.Gateway.Timeout
+	if timeout <= 0 {
+		timeout = gateway.DefaultSendTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	h, err := ms.Gateway.Send(ctx, req)
+	if err != nil {
+		code, e := gateway.SendErrorResponse(ctx, h, err)
+		return nil, status.Error(grpcCode(code), e.Error)
+	}
+	return &messagingpb.SendResponse{Id: h.ID, MessageIds: h.MessageIDs}, nil
+}
+
+// GetStatus implements messagingpb.MessagingServer.
+func (ms *MessagingService) GetStatus(ctx context.Context, in *messagingpb.GetStatusRequest) (*messagingpb.Status, error) {
+	s, ok, err := ms.Gateway.Status(ctx, in.GetId())
+	switch {
+	case err != nil:
+		return nil, status.Error(codes.Unavailable, err.Error())
+	case !ok:
+		return nil, status.Error(codes.NotFound, "message not found")
+	default:
+		return statusToProto(&s), nil
+	}
+}
+
+// StreamEvents implements messagingpb.MessagingServer.
+func (ms *MessagingService) StreamEvents(in *messagingpb.StreamEventsRequest, stream messagingpb.Messaging_StreamEventsServer) error {
+	if ms.Events == nil {
+		return status.Error(codes.Unimplemented, "events are not served")
+	}
+
+	sub := ms.Events.Subscribe(int(in.GetBuffer()))
+	defer sub.Close()
+
+	for {
+		select {
+		case e, ok := <-sub.C:
+			if !ok {
+				return nil
+			}
+			if err := stream.Send(eventToProto(e)); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return status.FromContextError(stream.Context().Err()).Err()
+		}
+	}
+}
+
+// grpcCode returns status code of error of Send responded by HTTP code.
+func grpcCode(code int) codes.Code {
+	switch code {
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusUnprocessableEntity:
+		return codes.FailedPrecondition
+	case http.StatusGatewayTimeout:
+		return codes.DeadlineExceeded
+	default:
+		return codes.Unavailable
+	}
+}
+
+func statusToProto(s *gateway.Status) *messagingpb.Status {
+	states := make([]uint32, len(s.States))
+	for i, state := range s.States {
+		states[i] = uint32(state)
+	}
+	return &messagingpb.Status{
+		Id:            s.ID,
+		Status:        s.Status,
+		MessageIds:    s.MessageIDs,
+		States:        states,
+		CorrelationId: s.CorrelationID,
+		Metadata:      s.Metadata,
+	}
+}
+
+func eventToProto(e gateway.Event) *messagingpb.Event {
+	switch {
+	case e.Delivery != nil:
+		return &messagingpb.Event{Event: &messagingpb.Event_Delivery{Delivery: statusToProto(e.Delivery)}}
+	case e.Message != nil:
+		msg := &messagingpb.Message{
+			From:       e.Message.From,
+			To:         e.Message.To,
+			Text:       e.Message.Text,
+			Data:       e.Message.Data,
+			DataCoding: uint32(e.Message.DataCoding),
+		}
+		if !e.Message.ReceivedAt.IsZero() {
+			msg.ReceivedAtUnixNano = e.Message.ReceivedAt.UnixNano()
+		}
+		return &messagingpb.Event{Event: &messagingpb.Event_Message{Message: msg}}
+	default:
+		return &messagingpb.Event{}
+	}
+}
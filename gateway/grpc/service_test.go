@@ -0,0 +1,172 @@
+package grpc_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp"
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/gateway"
+	gatewaygrpc "github.com/linxGnu/gosmpp/gateway/grpc"
+	"github.com/linxGnu/gosmpp/gateway/grpc/messagingpb"
+	"github.com/linxGnu/gosmpp/server/smsctest"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// newMessenger returns messenger of transceiver session bound to smsc, tracking messages by tracker.
+func newMessenger(t *testing.T, smsc *smsctest.Server, tracker *gosmpp.DeliveryTracker) *gosmpp.Messenger {
+	s, err := gosmpp.NewSession(gosmpp.TRXConnector(smsc.Dialer(), gosmpp.Auth{SMSC: "pipe", SystemID: "esme"}),
+		gosmpp.Settings{
+			ReadTimeout: 2 * time.Second,
+
+			OnDeliveryReceipt: func(r gosmpp.Receipt) { _, _ = tracker.HandleReceipt(context.Background(), r) },
+		}, -1)
+	require.Nil(t, err)
+	t.Cleanup(func() {
+		_ = s.Close()
+	})
+	return gosmpp.NewMessenger(s,
+		gosmpp.WithRegisteredDelivery(data.SM_SMSC_RECEIPT_REQUESTED), gosmpp.WithDeliveryTracker(tracker))
+}
+
+// newMessagingClient serves ms over in-memory listener and returns client connected to it.
+func newMessagingClient(t *testing.T, ms *gatewaygrpc.MessagingService) messagingpb.MessagingClient {
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	messagingpb.RegisterMessagingServer(srv, ms)
+	go func() {
+		_ = srv.Serve(lis)
+	}()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.Nil(t, err)
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+	return messagingpb.NewMessagingClient(conn)
+}
+
+func TestMessagingService(t *testing.T) {
+	smsc := smsctest.NewPipeServer(nil)
+	defer smsc.Close()
+
+	var events gateway.Events
+	tracker := gosmpp.NewDeliveryTracker(events.HandleDelivery)
+	client := newMessagingClient(t, &gatewaygrpc.MessagingService{
+		Gateway: &gateway.Gateway{
+			Messengers: []*gosmpp.Messenger{newMessenger(t, smsc, tracker)},
+			Tracker:    tracker,
+		},
+		Events: &events,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := client.StreamEvents(ctx, &messagingpb.StreamEventsRequest{Buffer: 4})
+	require.Nil(t, err)
+	// events are streamed from the time of the call, dispatch until the first one is streamed
+	subscribed := make(chan struct{})
+	go func() {
+		for {
+			events.HandleMessage(gosmpp.IncomingMessage{Text: "ping"})
+			select {
+			case <-subscribed:
+				return
+			case <-time.After(10 * time.Millisecond):
+			}
+		}
+	}()
+	_, err = stream.Recv()
+	close(subscribed)
+	require.Nil(t, err)
+	recv := func() *messagingpb.Event {
+		for {
+			e, err := stream.Recv()
+			require.Nil(t, err)
+			if e.GetMessage().GetText() != "ping" {
+				return e
+			}
+		}
+	}
+
+	sent, err := client.Send(ctx, &messagingpb.SendRequest{
+		From: "MyShop", To: "+84901234567", Text: "Your order is shipped", CorrelationId: "order-1",
+	})
+	require.Nil(t, err)
+	require.NotEmpty(t, sent.GetId())
+	require.Len(t, sent.GetMessageIds(), 1)
+	smsc.ExpectSubmitSM(t, "84901234567", "Your order is shipped")
+
+	s, err := client.GetStatus(ctx, &messagingpb.GetStatusRequest{Id: sent.GetId()})
+	require.Nil(t, err)
+	require.Equal(t, "pending", s.GetStatus())
+	require.Equal(t, sent.GetMessageIds(), s.GetMessageIds())
+	require.Equal(t, []uint32{0}, s.GetStates())
+	require.Equal(t, "order-1", s.GetCorrelationId())
+
+	_, err = client.GetStatus(ctx, &messagingpb.GetStatusRequest{Id: "unknown"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	smsc.SetReceipts(&smsctest.Receipts{})
+	sent, err = client.Send(ctx, &messagingpb.SendRequest{From: "MyShop", To: "+84901234567", Data: []byte{1, 2, 3}, Port: 2948})
+	require.Nil(t, err)
+
+	e := recv()
+	require.Equal(t, sent.GetId(), e.GetDelivery().GetId())
+	require.Equal(t, "delivered", e.GetDelivery().GetStatus())
+
+	events.HandleMessage(gosmpp.IncomingMessage{From: "+84901234567", To: "8888", Text: "HELP"})
+	e = recv()
+	require.Equal(t, "+84901234567", e.GetMessage().GetFrom())
+	require.Equal(t, "HELP", e.GetMessage().GetText())
+}
+
+func TestMessagingServiceErrors(t *testing.T) {
+	smsc := smsctest.NewPipeServer(nil)
+	defer smsc.Close()
+
+	tracker := gosmpp.NewDeliveryTracker(nil)
+	client := newMessagingClient(t, &gatewaygrpc.MessagingService{
+		Gateway: &gateway.Gateway{Messengers: []*gosmpp.Messenger{newMessenger(t, smsc, tracker)}},
+	})
+	ctx := context.Background()
+
+	for _, req := range []*messagingpb.SendRequest{
+		{To: "+84901234567", Text: "hi"},
+		{From: "MyShop", Text: "hi"},
+		{From: "MyShop", To: "+84901234567"},
+		{From: "MyShop", To: "+84901234567", Text: "hi", Data: []byte{1}},
+		{From: "MyShop", To: "+84901234567", Data: []byte{1}, Port: 1 << 16},
+	} {
+		_, err := client.Send(ctx, req)
+		require.Equal(t, codes.InvalidArgument, status.Code(err), req.String())
+	}
+
+	_, err := client.Send(ctx, &messagingpb.SendRequest{From: "not a sender id at all", To: "+84901234567", Text: "hi"})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	smsc.SetFault(smsctest.RespondStatus(data.ESME_RTHROTTLED))
+	_, err = client.Send(ctx, &messagingpb.SendRequest{From: "MyShop", To: "+84901234567", Text: "hi"})
+	require.Equal(t, codes.Unavailable, status.Code(err))
+
+	// without Tracker nothing is found, without Events nothing is streamed
+	_, err = client.GetStatus(ctx, &messagingpb.GetStatusRequest{Id: "1"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	stream, err := client.StreamEvents(ctx, &messagingpb.StreamEventsRequest{})
+	require.Nil(t, err)
+	_, err = stream.Recv()
+	require.Equal(t, codes.Unimplemented, status.Code(err))
+}
@@ -0,0 +1,288 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/linxGnu/gosmpp"
+)
+
+const (
+	// DefaultConnectorAttempts is default number of attempts to send request failing transiently.
+	DefaultConnectorAttempts = 3
+
+	// DefaultConnectorBackoff is default duration between the first and the second attempt, doubled by each next one.
+	DefaultConnectorBackoff = time.Second
+)
+
+// KafkaRecord is record of Kafka topic.
+type KafkaRecord struct {
+	Topic     string
+	Partition int32
+	Offset    int64
+	Key       []byte
+	Value     []byte
+}
+
+// KafkaSource consumes records of send requests, e.g. adapter of consumer group of Kafka client
+// with automatic commits disabled.
+type KafkaSource interface {
+	// Fetch returns next record, blocking until there is one or ctx is done. Records of the same partition
+	// are returned in order of offsets.
+	Fetch(ctx context.Context) (KafkaRecord, error)
+
+	// Commit commits offsets of records, marking them and earlier records of their partitions consumed.
+	Commit(ctx context.Context, records ...KafkaRecord) error
+}
+
+// KafkaConnector sends requests consumed from Kafka by Gateway, and publishes their results and events of
+// final deliveries and received messages to output topics.
+//
+// Records are SendRequest in JSON. Delivery is at least once: offset of record is committed only after SMSC
+// responded to its submit_sm, or it failed for good, and its Result is published; and after all earlier
+// records of the partition are. Requests failing transiently, e.g. throttled ones, are retried.
+//
+//	connector := &gateway.KafkaConnector{Gateway: gw, Source: source, Publisher: producer,
+//		ResultsTopic: "sms-results", EventsTopic: "sms-events"}
+//	tracker := gosmpp.NewDeliveryTracker(connector.HandleDelivery)
+//	settings.OnMessage = connector.HandleMessage
+//	err := connector.Run(ctx)
+type KafkaConnector struct {
+	// Gateway sends the requests.
+	Gateway *Gateway
+
+	// Source consumes records of requests.
+	Source KafkaSource
+
+	// Publisher publishes results to ResultsTopic and events to EventsTopic, as JSON Result and Event.
+	// Results are keyed by key of request record, events by message id or source address.
+	// Nothing is published to empty topic.
+	Publisher    Publisher
+	ResultsTopic string
+	EventsTopic  string
+
+	// Concurrency is number of requests sent at once, default is DefaultConnectorConcurrency.
+	// Records of the same partition might be sent out of order.
+	Concurrency int
+
+	// Attempts is number of attempts to send request failing transiently, default is DefaultConnectorAttempts.
+	Attempts int
+
+	// Backoff is duration before the second attempt, doubled by each next one. Default is DefaultConnectorBackoff.
+	Backoff time.Duration
+
+	// OnError is called with errors of publishing events.
+	OnError func(error)
+}
+
+// Run consumes and sends requests until ctx is done or consuming, publishing or committing fails, returning
+// the error. Requests being sent when it stops are not committed.
+func (c *KafkaConnector) Run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	concurrency := c.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultConnectorConcurrency
+	}
+
+	var (
+		wg       sync.WaitGroup
+		failOnce sync.Once
+		failure  error
+		offsets  = kafkaOffsets{pending: make(map[kafkaPartition][]*kafkaPending)}
+		slots    = make(chan struct{}, concurrency)
+	)
+	fail := func(err error) {
+		failOnce.Do(func() {
+			failure = err
+			cancel()
+		})
+	}
+
+	for {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		record, err := c.Source.Fetch(ctx)
+		if err != nil {
+			<-slots
+			if ctx.Err() == nil {
+				fail(err)
+			}
+			break
+		}
+
+		pending := offsets.add(record)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			if err := c.handle(ctx, record); err != nil {
+				fail(err)
+				return
+			}
+			if err := offsets.done(ctx, pending, c.Source.Commit); err != nil {
+				fail(err)
+			}
+		}()
+	}
+
+	wg.Wait()
+	if failure != nil {
+		return failure
+	}
+	return ctx.Err()
+}
+
+// handle sends request of record and publishes its result, unless ctx is done before.
+func (c *KafkaConnector) handle(ctx context.Context, record KafkaRecord) error {
+	attempts := c.Attempts
+	if attempts <= 0 {
+		attempts = DefaultConnectorAttempts
+	}
+	backoff := c.Backoff
+	if backoff <= 0 {
+		backoff = DefaultConnectorBackoff
+	}
+
+	var result Result
+	for attempt := 1; ; attempt++ {
+		var transient bool
+		if result, transient = send(ctx, c.Gateway, record.Value); ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if !transient || attempt == attempts {
+			break
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+		backoff *= 2
+	}
+
+	if c.ResultsTopic == "" {
+		return nil
+	}
+	value, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	return c.Publisher.Publish(ctx, c.ResultsTopic, record.Key, value)
+}
+
+// HandleDelivery publishes EventDelivery of d to EventsTopic.
+func (c *KafkaConnector) HandleDelivery(d gosmpp.Delivery) {
+	c.events().handleDelivery(d)
+}
+
+// HandleMessage publishes EventMessage of m to EventsTopic.
+func (c *KafkaConnector) HandleMessage(m gosmpp.IncomingMessage) {
+	c.events().handleMessage(m)
+}
+
+func (c *KafkaConnector) events() events {
+	return events{publisher: c.Publisher, topic: c.EventsTopic, timeout: c.Gateway.timeout(), onError: c.OnError}
+}
+
+type kafkaPartition struct {
+	topic     string
+	partition int32
+}
+
+type kafkaPending struct {
+	record KafkaRecord
+	done   bool
+}
+
+// kafkaOffsets tracks records being handled per partition, in order of fetching.
+type kafkaOffsets struct {
+	mu      sync.Mutex
+	pending map[kafkaPartition][]*kafkaPending
+
+	// commits holds partitions being committed, with record to commit next once the running commit is over
+	commits map[kafkaPartition]*KafkaRecord
+}
+
+func (o *kafkaOffsets) add(record KafkaRecord) *kafkaPending {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	p := &kafkaPending{record: record}
+	key := kafkaPartition{topic: record.Topic, partition: record.Partition}
+	o.pending[key] = append(o.pending[key], p)
+	return p
+}
+
+// done marks p handled and commits the last record of its partition all earlier records of which are handled.
+// Commits of partition are serialized, so its offsets are committed in order: offset reached while partition
+// is being committed is left to the running commit. Commit is called without holding the lock, so fetching
+// and handling go on meanwhile.
+func (o *kafkaOffsets) done(ctx context.Context, p *kafkaPending,
+	commit func(ctx context.Context, records ...KafkaRecord) error,
+) error {
+	o.mu.Lock()
+	p.done = true
+	key := kafkaPartition{topic: p.record.Topic, partition: p.record.Partition}
+	pending := o.pending[key]
+
+	n := 0
+	for n < len(pending) && pending[n].done {
+		n++
+	}
+	if n == 0 {
+		o.mu.Unlock()
+		return nil
+	}
+
+	last := pending[n-1].record
+	if n == len(pending) {
+		delete(o.pending, key)
+	} else {
+		o.pending[key] = pending[n:]
+	}
+
+	if o.commits == nil {
+		o.commits = make(map[kafkaPartition]*KafkaRecord)
+	}
+	if _, committing := o.commits[key]; committing {
+		o.commits[key] = &last
+		o.mu.Unlock()
+		return nil
+	}
+	o.commits[key] = nil
+	o.mu.Unlock()
+
+	for {
+		err := commit(ctx, last)
+
+		o.mu.Lock()
+		next := o.commits[key]
+		if err != nil || next == nil {
+			delete(o.commits, key)
+			o.mu.Unlock()
+			if err != nil {
+				return fmt.Errorf("gateway: committing offset: %w", err)
+			}
+			return nil
+		}
+		o.commits[key] = nil
+		o.mu.Unlock()
+
+		last = *next
+	}
+}
@@ -0,0 +1,252 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp"
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/server/smsctest"
+
+	"github.com/stretchr/testify/require"
+)
+
+// kafkaSource returns records in order, then blocks until ctx is done.
+type kafkaSource struct {
+	mu        sync.Mutex
+	records   []KafkaRecord
+	committed []KafkaRecord
+}
+
+func (s *kafkaSource) Fetch(ctx context.Context) (KafkaRecord, error) {
+	s.mu.Lock()
+	if len(s.records) > 0 {
+		r := s.records[0]
+		s.records = s.records[1:]
+		s.mu.Unlock()
+		return r, nil
+	}
+	s.mu.Unlock()
+
+	<-ctx.Done()
+	return KafkaRecord{}, ctx.Err()
+}
+
+func (s *kafkaSource) Commit(_ context.Context, records ...KafkaRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.committed = append(s.committed, records...)
+	return nil
+}
+
+func (s *kafkaSource) offsets() map[int32]int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	offsets := make(map[int32]int64)
+	for _, r := range s.committed {
+		offsets[r.Partition] = r.Offset
+	}
+	return offsets
+}
+
+type published struct {
+	topic      string
+	key, value []byte
+}
+
+type publisher struct {
+	mu      sync.Mutex
+	records []published
+	err     error
+}
+
+func (p *publisher) Publish(_ context.Context, topic string, key, value []byte) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.err != nil {
+		return p.err
+	}
+	p.records = append(p.records, published{topic: topic, key: key, value: value})
+	return nil
+}
+
+func (p *publisher) results(t *testing.T, topic string) map[string]Result {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	results := make(map[string]Result)
+	for _, r := range p.records {
+		if r.topic == topic {
+			var result Result
+			require.Nil(t, json.Unmarshal(r.value, &result))
+			results[string(r.key)] = result
+		}
+	}
+	return results
+}
+
+func newGateway(t *testing.T, smsc *smsctest.Server) *Gateway {
+	tracker := gosmpp.NewDeliveryTracker(nil)
+	return &Gateway{Messengers: []*gosmpp.Messenger{newMessenger(newSession(t, smsc, tracker), tracker)}}
+}
+
+func record(partition int32, offset int64, key, value string) KafkaRecord {
+	return KafkaRecord{Topic: "sms", Partition: partition, Offset: offset, Key: []byte(key), Value: []byte(value)}
+}
+
+func TestKafkaConnector(t *testing.T) {
+	smsc := smsctest.NewPipeServer(nil)
+	defer smsc.Close()
+	smsc.SetFault(smsctest.Script(smsctest.Fault{Status: data.ESME_RTHROTTLED}))
+
+	source := &kafkaSource{records: []KafkaRecord{
+		record(0, 10, "a", `{"from":"MyShop","to":"+84901234567","text":"first","correlation_id":"order-1"}`),
+		record(1, 20, "b", `{"from":"MyShop","to":"+84901234568","text":"second"}`),
+		record(0, 11, "c", `{"to":`),
+		record(0, 12, "d", `{"from":"MyShop","text":"no destination"}`),
+	}}
+	pub := &publisher{}
+	c := &KafkaConnector{
+		Gateway:      newGateway(t, smsc),
+		Source:       source,
+		Publisher:    pub,
+		ResultsTopic: "sms-results",
+		EventsTopic:  "sms-events",
+		Concurrency:  1,
+		Backoff:      time.Millisecond,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- c.Run(ctx)
+	}()
+
+	require.Eventually(t, func() bool {
+		return len(source.offsets()) == 2 && source.offsets()[0] == 12
+	}, 5*time.Second, time.Millisecond)
+	require.Equal(t, map[int32]int64{0: 12, 1: 20}, source.offsets())
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+
+	results := pub.results(t, "sms-results")
+	require.Len(t, results, 4)
+	require.Equal(t, "order-1", results["a"].CorrelationID)
+	require.NotEmpty(t, results["a"].ID)
+	require.Len(t, results["a"].MessageIDs, 1)
+	require.Empty(t, results["a"].Error)
+	require.NotEmpty(t, results["b"].ID)
+	require.Contains(t, results["c"].Error, "invalid request")
+	require.Equal(t, "destination is required", results["d"].Error)
+
+	// the first one was throttled and retried
+	smsc.ExpectReceived(t, data.SUBMIT_SM, 3)
+
+	c.HandleDelivery(gosmpp.Delivery{HandleID: "h1", Status: gosmpp.DeliveryDelivered})
+	c.HandleMessage(gosmpp.IncomingMessage{From: "+84901234567", To: "8888", Text: "HELP"})
+	var events []published
+	for _, r := range pub.records {
+		if r.topic == "sms-events" {
+			events = append(events, r)
+		}
+	}
+	require.Len(t, events, 2)
+	require.Equal(t, "h1", string(events[0].key))
+	require.Equal(t, "+84901234567", string(events[1].key))
+	require.JSONEq(t, `{"type":"message","message":{"from":"+84901234567","to":"8888","text":"HELP",
+		"data_coding":0,"received_at":"0001-01-01T00:00:00Z"}}`, string(events[1].value))
+}
+
+func TestKafkaConnectorPublishFailure(t *testing.T) {
+	smsc := smsctest.NewPipeServer(nil)
+	defer smsc.Close()
+
+	errPublish := errors.New("broker is down")
+	source := &kafkaSource{records: []KafkaRecord{
+		record(0, 1, "a", `{"from":"MyShop","to":"+84901234567","text":"hi"}`),
+	}}
+	c := &KafkaConnector{
+		Gateway:      newGateway(t, smsc),
+		Source:       source,
+		Publisher:    &publisher{err: errPublish},
+		ResultsTopic: "sms-results",
+	}
+
+	require.ErrorIs(t, c.Run(context.Background()), errPublish)
+	require.Empty(t, source.offsets())
+}
+
+func TestKafkaOffsets(t *testing.T) {
+	var committed []int64
+	commit := func(_ context.Context, records ...KafkaRecord) error {
+		for _, r := range records {
+			committed = append(committed, r.Offset)
+		}
+		return nil
+	}
+
+	o := kafkaOffsets{pending: make(map[kafkaPartition][]*kafkaPending)}
+	first := o.add(record(0, 1, "", ""))
+	second := o.add(record(0, 2, "", ""))
+	third := o.add(record(0, 3, "", ""))
+	other := o.add(record(1, 7, "", ""))
+
+	ctx := context.Background()
+	require.Nil(t, o.done(ctx, second, commit))
+	require.Empty(t, committed)
+	require.Nil(t, o.done(ctx, other, commit))
+	require.Equal(t, []int64{7}, committed)
+	require.Nil(t, o.done(ctx, first, commit))
+	require.Equal(t, []int64{7, 2}, committed)
+	require.Nil(t, o.done(ctx, third, commit))
+	require.Equal(t, []int64{7, 2, 3}, committed)
+	require.Empty(t, o.pending)
+}
+
+func TestKafkaOffsetsCommitUnlocked(t *testing.T) {
+	var (
+		mu        sync.Mutex
+		committed []int64
+	)
+	started, release := make(chan struct{}), make(chan struct{})
+	commit := func(_ context.Context, records ...KafkaRecord) error {
+		mu.Lock()
+		first := len(committed) == 0
+		for _, r := range records {
+			committed = append(committed, r.Offset)
+		}
+		mu.Unlock()
+		if first {
+			close(started)
+			<-release
+		}
+		return nil
+	}
+
+	o := kafkaOffsets{pending: make(map[kafkaPartition][]*kafkaPending)}
+	first := o.add(record(0, 1, "", ""))
+
+	ctx := context.Background()
+	done := make(chan error, 1)
+	go func() { done <- o.done(ctx, first, commit) }()
+	<-started
+
+	// fetching and handling go on while offset is being committed
+	second := o.add(record(0, 2, "", ""))
+	third := o.add(record(0, 3, "", ""))
+	require.Nil(t, o.done(ctx, second, commit))
+	require.Nil(t, o.done(ctx, third, commit))
+
+	close(release)
+	require.Nil(t, <-done)
+	mu.Lock()
+	defer mu.Unlock()
+	require.Equal(t, []int64{1, 3}, committed)
+	require.Empty(t, o.commits)
+}
@@ -0,0 +1,258 @@
+package gateway
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/linxGnu/gosmpp"
+)
+
+const (
+	// DefaultWebhookAttempts is default number of attempts to post event.
+	DefaultWebhookAttempts = 3
+
+	// DefaultWebhookBackoff is default duration between the first and the second attempt, doubled by each next one.
+	DefaultWebhookBackoff = time.Second
+
+	// DefaultWebhookQueueSize is default number of events waiting to be posted.
+	DefaultWebhookQueueSize = 1024
+)
+
+var (
+	// ErrWebhookQueueFull indicates event is dropped as too many events are waiting to be posted.
+	ErrWebhookQueueFull = errors.New("gateway: webhook queue is full")
+
+	// ErrWebhookClosed indicates event is dropped as webhook is closed.
+	ErrWebhookClosed = errors.New("gateway: webhook closed")
+)
+
+// Types of events.
+const (
+	EventDelivery = "delivery"
+	EventMessage  = "message"
+)
+
+// Event is body of webhook request.
+type Event struct {
+	// Type is EventDelivery or EventMessage.
+	Type string `json:"type"`
+
+	// Delivery is final status of sent message, of EventDelivery.
+	Delivery *Status `json:"delivery,omitempty"`
+
+	// Message is received mobile originated message, of EventMessage.
+	Message *Message `json:"message,omitempty"`
+}
+
+// Message is mobile originated message of EventMessage.
+type Message struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+
+	// Text is the message, Data the payload of binary messages only, base64 in JSON.
+	Text string `json:"text,omitempty"`
+	Data []byte `json:"data,omitempty"`
+
+	DataCoding byte      `json:"data_coding"`
+	ReceivedAt time.Time `json:"received_at"`
+}
+
+// NewMessage returns message of m.
+func NewMessage(m gosmpp.IncomingMessage) Message {
+	msg := Message{
+		From:       m.From,
+		To:         m.To,
+		Text:       m.Text,
+		DataCoding: m.DataCoding,
+		ReceivedAt: m.ReceivedAt,
+	}
+	if m.Binary() {
+		msg.Data = m.Data
+	}
+	return msg
+}
+
+// Webhook posts events of final deliveries and received messages to URL as JSON, one at a time in order
+// of dispatching.
+//
+// Events are fed by HandleDelivery, e.g. as callback of gosmpp.NewDeliveryTracker, and HandleMessage,
+// e.g. from Settings.OnMessage or gosmpp.Reassembler so concatenated messages are posted whole.
+// They are queued and posted in background, not blocking the session; failed requests are retried
+// on network errors and 5xx or 429 responses.
+type Webhook struct {
+	// URL receives POST requests with Event body.
+	URL string
+
+	// Client sends requests, http.DefaultClient if nil.
+	Client *http.Client
+
+	// Header is added to every request, e.g. with authorization token.
+	Header http.Header
+
+	// Attempts is number of attempts to post event, default is DefaultWebhookAttempts.
+	Attempts int
+
+	// Backoff is duration before the second attempt, doubled by each next one. Default is DefaultWebhookBackoff.
+	Backoff time.Duration
+
+	// QueueSize is number of events waiting to be posted, over which events are dropped.
+	// Default is DefaultWebhookQueueSize.
+	QueueSize int
+
+	// OnError is called with dropped events: not posted in all attempts, over the queue size or dispatched
+	// after Close.
+	OnError func(e Event, err error)
+
+	once   sync.Once
+	mu     sync.Mutex
+	closed bool
+	queue  chan Event
+	done   chan struct{}
+	wg     sync.WaitGroup
+}
+
+// HandleDelivery dispatches EventDelivery of d.
+func (w *Webhook) HandleDelivery(d gosmpp.Delivery) {
+	status := NewStatus(d)
+	w.Dispatch(Event{Type: EventDelivery, Delivery: &status})
+}
+
+// HandleMessage dispatches EventMessage of m.
+func (w *Webhook) HandleMessage(m gosmpp.IncomingMessage) {
+	msg := NewMessage(m)
+	w.Dispatch(Event{Type: EventMessage, Message: &msg})
+}
+
+// Dispatch queues event to be posted.
+func (w *Webhook) Dispatch(e Event) {
+	w.once.Do(w.start)
+
+	var err error
+	w.mu.Lock()
+	if w.closed {
+		err = ErrWebhookClosed
+	} else {
+		select {
+		case w.queue <- e:
+		default:
+			err = ErrWebhookQueueFull
+		}
+	}
+	w.mu.Unlock()
+
+	if err != nil {
+		w.fail(e, err)
+	}
+}
+
+// Close stops dispatching, waiting for queued events to be posted. Failed ones are not retried anymore.
+func (w *Webhook) Close() error {
+	w.once.Do(w.start)
+
+	w.mu.Lock()
+	if !w.closed {
+		w.closed = true
+		close(w.done)
+		close(w.queue)
+	}
+	w.mu.Unlock()
+
+	w.wg.Wait()
+	return nil
+}
+
+func (w *Webhook) start() {
+	size := w.QueueSize
+	if size <= 0 {
+		size = DefaultWebhookQueueSize
+	}
+	w.queue = make(chan Event, size)
+	w.done = make(chan struct{})
+
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		for e := range w.queue {
+			if err := w.post(e); err != nil {
+				w.fail(e, err)
+			}
+		}
+	}()
+}
+
+// post posts event, retrying failed attempts.
+func (w *Webhook) post(e Event) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	attempts := w.Attempts
+	if attempts <= 0 {
+		attempts = DefaultWebhookAttempts
+	}
+	backoff := w.Backoff
+	if backoff <= 0 {
+		backoff = DefaultWebhookBackoff
+	}
+
+	for attempt := 1; ; attempt++ {
+		retry, err := w.attempt(body)
+		if err == nil || !retry || attempt == attempts {
+			return err
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-timer.C:
+		case <-w.done:
+			timer.Stop()
+			return err
+		}
+		backoff *= 2
+	}
+}
+
+// attempt posts body once, reporting whether failed request should be retried.
+func (w *Webhook) attempt(body []byte) (retry bool, err error) {
+	req, err := http.NewRequest(http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	for k, v := range w.Header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := w.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return false, nil
+	case resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests:
+		return true, fmt.Errorf("gateway: webhook responded %s", resp.Status)
+	default:
+		return false, fmt.Errorf("gateway: webhook responded %s", resp.Status)
+	}
+}
+
+func (w *Webhook) fail(e Event, err error) {
+	if w.OnError != nil {
+		w.OnError(e, err)
+	}
+}
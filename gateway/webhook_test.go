@@ -0,0 +1,169 @@
+package gateway
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp"
+	"github.com/linxGnu/gosmpp/data"
+
+	"github.com/stretchr/testify/require"
+)
+
+// webhookReceiver records events, responding to requests with statuses in order, then with 204.
+// Zero status records event.
+type webhookReceiver struct {
+	mu       sync.Mutex
+	statuses []int
+	requests int
+	events   []Event
+	headers  []http.Header
+}
+
+func (r *webhookReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.requests++
+	r.headers = append(r.headers, req.Header)
+	if len(r.statuses) > 0 {
+		status := r.statuses[0]
+		r.statuses = r.statuses[1:]
+		if status != 0 {
+			w.WriteHeader(status)
+			return
+		}
+	}
+
+	var e Event
+	if err := json.NewDecoder(req.Body).Decode(&e); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	r.events = append(r.events, e)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (r *webhookReceiver) received() (requests int, events []Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.requests, append([]Event(nil), r.events...)
+}
+
+func TestWebhookDispatch(t *testing.T) {
+	receiver := &webhookReceiver{}
+	srv := httptest.NewServer(receiver)
+	defer srv.Close()
+
+	w := &Webhook{URL: srv.URL, Header: http.Header{"Authorization": {"Bearer token"}}}
+
+	w.HandleDelivery(gosmpp.Delivery{
+		HandleID:    "h1",
+		MessageIDs:  []string{"1", "2"},
+		States:      []byte{data.SM_STATE_DELIVERED, data.SM_STATE_UNDELIVERABLE},
+		Status:      gosmpp.DeliveryFailed,
+		Correlation: gosmpp.Correlation{ID: "order-1", Metadata: map[string]string{"shop": "eu"}},
+	})
+	receivedAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	w.HandleMessage(gosmpp.IncomingMessage{From: "+84901234567", To: "8888", Text: "HELP", Encoding: data.GSM7BIT,
+		ReceivedAt: receivedAt})
+	w.HandleMessage(gosmpp.IncomingMessage{From: "+84901234567", To: "8888", Data: []byte{1, 2}, Encoding: data.BINARY8BIT2,
+		DataCoding: data.BINARY8BIT2Coding, ReceivedAt: receivedAt})
+	require.Nil(t, w.Close())
+
+	_, events := receiver.received()
+	require.Equal(t, []Event{
+		{Type: EventDelivery, Delivery: &Status{
+			ID:            "h1",
+			Status:        "failed",
+			MessageIDs:    []string{"1", "2"},
+			States:        []int{data.SM_STATE_DELIVERED, data.SM_STATE_UNDELIVERABLE},
+			CorrelationID: "order-1",
+			Metadata:      map[string]string{"shop": "eu"},
+		}},
+		{Type: EventMessage, Message: &Message{From: "+84901234567", To: "8888", Text: "HELP", ReceivedAt: receivedAt}},
+		{Type: EventMessage, Message: &Message{From: "+84901234567", To: "8888", Data: []byte{1, 2},
+			DataCoding: data.BINARY8BIT2Coding, ReceivedAt: receivedAt}},
+	}, events)
+	require.Equal(t, "Bearer token", receiver.headers[0].Get("Authorization"))
+	require.Equal(t, "application/json", receiver.headers[0].Get("Content-Type"))
+
+	var dropped []error
+	w.OnError = func(_ Event, err error) { dropped = append(dropped, err) }
+	w.Dispatch(Event{Type: EventMessage})
+	require.Equal(t, []error{ErrWebhookClosed}, dropped)
+}
+
+func TestWebhookRetries(t *testing.T) {
+	receiver := &webhookReceiver{statuses: []int{
+		http.StatusServiceUnavailable, http.StatusTooManyRequests, 0, // retried
+		http.StatusBadRequest, // not retried
+		http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError,
+	}}
+	srv := httptest.NewServer(receiver)
+	defer srv.Close()
+
+	var (
+		mu      sync.Mutex
+		dropped []Event
+	)
+	w := &Webhook{
+		URL:     srv.URL,
+		Backoff: time.Millisecond,
+		OnError: func(e Event, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			dropped = append(dropped, e)
+		},
+	}
+
+	for _, id := range []string{"1", "2", "3", "4"} {
+		w.Dispatch(Event{Type: EventDelivery, Delivery: &Status{ID: id}})
+	}
+	require.Eventually(t, func() bool {
+		requests, _ := receiver.received()
+		return requests == 8
+	}, time.Second, time.Millisecond)
+	require.Nil(t, w.Close())
+
+	_, events := receiver.received()
+	require.Len(t, events, 2)
+	require.Equal(t, "1", events[0].Delivery.ID)
+	require.Equal(t, "4", events[1].Delivery.ID)
+
+	require.Len(t, dropped, 2)
+	require.Equal(t, "2", dropped[0].Delivery.ID)
+	require.Equal(t, "3", dropped[1].Delivery.ID)
+}
+
+func TestWebhookQueueFull(t *testing.T) {
+	block := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-block
+	}))
+	defer srv.Close()
+
+	var (
+		mu   sync.Mutex
+		errs []error
+	)
+	w := &Webhook{URL: srv.URL, QueueSize: 1, OnError: func(_ Event, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		errs = append(errs, err)
+	}}
+
+	// the first one is being posted, the second one is queued
+	w.Dispatch(Event{Type: EventMessage})
+	require.Eventually(t, func() bool { return len(w.queue) == 0 }, time.Second, time.Millisecond)
+	w.Dispatch(Event{Type: EventMessage})
+	w.Dispatch(Event{Type: EventMessage})
+
+	close(block)
+	require.Nil(t, w.Close())
+	require.Equal(t, []error{ErrWebhookQueueFull}, errs)
+}
@@ -0,0 +1,113 @@
+package gosmpp
+
+import (
+	"context"
+	"errors"
+
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+)
+
+var (
+	// ErrMessageCanceled indicates message queued by SendAt was canceled before its time.
+	ErrMessageCanceled = errors.New("message canceled")
+
+	// ErrMessageNotSubmitted indicates message is not accepted by SMSC yet, e.g. queued by SendAt.
+	ErrMessageNotSubmitted = errors.New("message is not submitted")
+
+	// ErrReplaceMultipart indicates replacing message of multiple parts, not supported by replace_sm.
+	ErrReplaceMultipart = errors.New("message of multiple parts could not be replaced")
+)
+
+// Cancel cancels pending delivery of the message: parts accepted by SMSC are canceled with cancel_sm,
+// message queued by SendAt is dropped if not submitted yet, its Wait returning ErrMessageCanceled.
+//
+// Error is *SubmitError if SMSC rejects canceling a part, e.g. if it is already delivered.
+func (h *MessageHandle) Cancel(ctx context.Context) error {
+	if q := h.queued; q != nil && q.timer.Stop() {
+		q.finish(ErrMessageCanceled)
+		return nil
+	}
+
+	if err := h.Wait(ctx); err != nil && ctx.Err() != nil {
+		return err
+	}
+	if len(h.MessageIDs) == 0 {
+		return ErrMessageNotSubmitted
+	}
+
+	calls := make([]*Call, len(h.Parts))
+	for i, part := range h.Parts {
+		if h.MessageIDs[i] == "" {
+			continue
+		}
+
+		c := pdu.NewCancelSM().(*pdu.CancelSM)
+		c.ServiceType = part.ServiceType
+		c.MessageID = h.MessageIDs[i]
+		c.SourceAddr = part.SourceAddr
+		c.DestAddr = part.DestAddr
+		calls[i] = h.messenger.session.SubmitAsync(c, WithCorrelation(h.Correlation), withContext(ctx))
+	}
+	return waitCalls(ctx, calls)
+}
+
+// Replace replaces text of the message pending delivery with replace_sm. Text is encoded in encoding
+// of the message, which could not be changed, and must fit into single part.
+//
+// Error is *SubmitError if SMSC rejects replacing, e.g. if message is already delivered.
+func (h *MessageHandle) Replace(ctx context.Context, text string) error {
+	if len(h.Parts) > 1 {
+		return ErrReplaceMultipart
+	}
+
+	if err := h.Wait(ctx); err != nil {
+		return err
+	}
+	if len(h.MessageIDs) == 0 || h.MessageIDs[0] == "" {
+		return ErrMessageNotSubmitted
+	}
+
+	part := h.Parts[0]
+	enc := part.Message.Encoding()
+	if enc == nil {
+		enc = data.GSM7BIT
+	}
+
+	r := pdu.NewReplaceSM().(*pdu.ReplaceSM)
+	r.MessageID = h.MessageIDs[0]
+	r.SourceAddr = part.SourceAddr
+	r.RegisteredDelivery = part.RegisteredDelivery
+	if err := r.Message.SetMessageWithEncoding(text, enc); err != nil {
+		return err
+	}
+
+	if err := waitCalls(ctx, []*Call{h.messenger.session.SubmitAsync(r, WithCorrelation(h.Correlation), withContext(ctx))}); err != nil {
+		return err
+	}
+	return part.Message.SetMessageWithEncoding(text, enc)
+}
+
+// waitCalls waits for responses of calls of parts, cancelling the rest on first error. Nil calls are skipped.
+func waitCalls(ctx context.Context, calls []*Call) error {
+	for i, c := range calls {
+		if c == nil {
+			continue
+		}
+
+		resp, err := c.Wait(ctx)
+		if err == nil && resp.GetHeader().CommandStatus != data.ESME_ROK {
+			err = &SubmitError{Part: i, Status: resp.GetHeader().CommandStatus}
+		}
+
+		if err != nil {
+			for _, pending := range calls[i+1:] {
+				if pending != nil {
+					pending.Cancel()
+				}
+			}
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package gosmpp_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp"
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+	"github.com/linxGnu/gosmpp/server"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageHandleCancelReplace(t *testing.T) {
+	store := server.NewMemoryStore()
+	srv := &server.Server{Store: store}
+	defer func() {
+		_ = srv.Close()
+	}()
+
+	s, err := gosmpp.NewSession(gosmpp.TXConnector(srv.PipeDialer(), gosmpp.Auth{SMSC: "pipe", SystemID: "esme"}),
+		gosmpp.Settings{ReadTimeout: 2 * time.Second}, -1)
+	require.Nil(t, err)
+	defer func() {
+		_ = s.Close()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	m := gosmpp.NewMessenger(s)
+
+	h, err := m.SendText(ctx, "MyBank", "84901234567", "Your code is 1234")
+	require.Nil(t, err)
+	require.Nil(t, h.Replace(ctx, "Your code is 5678"))
+	text, _ := h.Parts[0].Message.GetMessage()
+	require.Equal(t, "Your code is 5678", text)
+
+	stored, err := store.Get(ctx, h.MessageIDs[0])
+	require.Nil(t, err)
+	text, _ = stored.Request.(*pdu.SubmitSM).Message.GetMessage()
+	require.Equal(t, "Your code is 5678", text)
+
+	require.Nil(t, h.Cancel(ctx))
+	stored, _ = store.Get(ctx, h.MessageIDs[0])
+	require.Equal(t, byte(data.SM_STATE_DELETED), stored.State)
+
+	// final message could not be replaced or canceled again
+	var submitErr *gosmpp.SubmitError
+	require.ErrorAs(t, h.Replace(ctx, "late"), &submitErr)
+	require.ErrorAs(t, h.Cancel(ctx), &submitErr)
+
+	h, err = m.SendText(ctx, "MyBank", "84901234567", strings.Repeat("a", 200))
+	require.Nil(t, err)
+	require.Equal(t, gosmpp.ErrReplaceMultipart, h.Replace(ctx, "short"))
+	require.Nil(t, h.Cancel(ctx))
+	for _, id := range h.MessageIDs {
+		stored, _ = store.Get(ctx, id)
+		require.Equal(t, byte(data.SM_STATE_DELETED), stored.State)
+	}
+}
+
+func TestMessageHandleCancelQueued(t *testing.T) {
+	srv := &server.Server{}
+	defer func() {
+		_ = srv.Close()
+	}()
+
+	s, err := gosmpp.NewSession(gosmpp.TXConnector(srv.PipeDialer(), gosmpp.Auth{SMSC: "pipe", SystemID: "esme"}),
+		gosmpp.Settings{ReadTimeout: 2 * time.Second}, -1)
+	require.Nil(t, err)
+	defer func() {
+		_ = s.Close()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	h, err := gosmpp.NewMessenger(s).SendAt(ctx, gosmpp.NewSubmitSMBuilder().From("MyBank").To("84901234567").Text("later"),
+		time.Now().Add(time.Hour))
+	require.Nil(t, err)
+	require.Nil(t, h.Cancel(ctx))
+	require.Equal(t, gosmpp.ErrMessageCanceled, h.Wait(ctx))
+	require.Equal(t, gosmpp.ErrMessageNotSubmitted, h.Cancel(ctx))
+	require.Equal(t, gosmpp.ErrMessageCanceled, h.Replace(ctx, "never"))
+}
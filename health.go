@@ -0,0 +1,394 @@
+package gosmpp
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/linxGnu/gosmpp/clock"
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+)
+
+// enquireLinkRTTWeight is weight of the latest sample in enquire_link RTT moving average.
+const enquireLinkRTTWeight = 0.2
+
+// linkStats keeps track of bind activity, shared between transmittable and receivable.
+//
+// All methods are safe to call on nil receiver.
+type linkStats struct {
+	lastActivity int64 // unix nano
+
+	enquireLinkSeq     int32
+	enquireLinkSentAt  int64 // unix nano
+	lastEnquireLinkRTT int64 // nanoseconds
+	enquireLinkRTTEWMA int64 // nanoseconds
+
+	clock clock.Clock
+
+	inflight seqMap[int64] // sequence number -> unix nano of sending
+
+	// sending are requests recorded in inflight before writing, their response could be read before
+	// onWritten is called
+	sending seqMap[struct{}]
+
+	counters *sessionCounters
+	meter    *meter
+	events   *eventBus
+	calls    *callRegistry
+	journal  *journal
+	window   *submitWindow
+
+	// onPressure re-evaluates backpressure of the session
+	onPressure func()
+}
+
+func newLinkStats() *linkStats {
+	return &linkStats{}
+}
+
+func (s *linkStats) touch(now time.Time) {
+	atomic.StoreInt64(&s.lastActivity, now.UnixNano())
+}
+
+// onWritten records PDU which is written to SMSC.
+func (s *linkStats) onWritten(p pdu.PDU) {
+	if s == nil || p == nil {
+		return
+	}
+
+	now := clock.OrReal(s.clock).Now()
+	s.touch(now)
+	s.meter.onWritten(now, p)
+	s.journal.onPDU(now, DirectionSent, p)
+	if s.counters != nil {
+		s.counters.inc(&s.counters.pdusSent, MetricPDUsSent)
+	}
+
+	if _, ok := p.(*pdu.EnquireLink); ok {
+		_, unanswered := s.inflight.take(atomic.LoadInt32(&s.enquireLinkSeq))
+		if unanswered {
+			s.timeout()
+			s.events.publish(Event{Type: EventEnquireLinkTimeout, Time: now})
+		}
+
+		atomic.StoreInt64(&s.enquireLinkSentAt, now.UnixNano())
+		atomic.StoreInt32(&s.enquireLinkSeq, p.GetSequenceNumber())
+	}
+
+	if p.CanResponse() {
+		// response of request recorded by onSending could have been read already
+		if seq := p.GetSequenceNumber(); !s.takeSending(seq) {
+			s.inflight.store(seq, now.UnixNano())
+		}
+	}
+	s.pressure()
+}
+
+// onSending records request which is about to be written to SMSC, so that its response is matched even if it
+// is read before onWritten is called.
+func (s *linkStats) onSending(p pdu.PDU) {
+	if s == nil || !p.CanResponse() {
+		return
+	}
+
+	now := clock.OrReal(s.clock).Now()
+	seq := p.GetSequenceNumber()
+	s.sending.store(seq, struct{}{})
+	s.inflight.store(seq, now.UnixNano())
+}
+
+// onWriteFailed forgets request recorded by onSending which could not be written.
+func (s *linkStats) onWriteFailed(p pdu.PDU) {
+	if s == nil || !p.CanResponse() {
+		return
+	}
+
+	seq := p.GetSequenceNumber()
+	s.inflight.take(seq)
+	s.sending.take(seq)
+	s.window.release(seq)
+}
+
+func (s *linkStats) takeSending(seq int32) bool {
+	_, ok := s.sending.take(seq)
+	return ok
+}
+
+// onReceived records PDU which is read from SMSC.
+func (s *linkStats) onReceived(p pdu.PDU) {
+	if s == nil || p == nil {
+		return
+	}
+
+	now := clock.OrReal(s.clock).Now()
+	s.touch(now)
+	s.meter.onReceived(now, p)
+	s.journal.onPDU(now, DirectionReceived, p)
+	if s.counters != nil {
+		s.counters.inc(&s.counters.pdusReceived, MetricPDUsReceived)
+	}
+
+	if !isResponsePDU(p) {
+		return
+	}
+
+	correlation := s.calls.resolve(p)
+	s.window.release(p.GetSequenceNumber())
+
+	if p.GetHeader().CommandStatus == data.ESME_RTHROTTLED {
+		s.events.publish(Event{Type: EventThrottled, Time: now, PDU: p, Correlation: correlation})
+	}
+
+	seq := p.GetSequenceNumber()
+	_, isEnquireLinkResp := p.(*pdu.EnquireLinkResp)
+	if isEnquireLinkResp && seq == atomic.LoadInt32(&s.enquireLinkSeq) {
+		if sentAt := atomic.LoadInt64(&s.enquireLinkSentAt); sentAt > 0 {
+			rtt := now.UnixNano() - sentAt
+			atomic.StoreInt64(&s.lastEnquireLinkRTT, rtt)
+			atomic.StoreInt64(&s.enquireLinkRTTEWMA, ewma(atomic.LoadInt64(&s.enquireLinkRTTEWMA), rtt))
+		}
+	}
+
+	sentAt, found := s.inflight.take(seq)
+
+	if found && !isEnquireLinkResp {
+		s.meter.onLatency(now, time.Duration(now.UnixNano()-sentAt), correlation)
+	}
+	s.pressure()
+}
+
+// expire evicts requests not responded within maxAge, counting them as timeouts. Unanswered enquire_link is
+// left to onWritten of the next one, reporting EventEnquireLinkTimeout.
+func (s *linkStats) expire(maxAge time.Duration) {
+	if s == nil || maxAge <= 0 {
+		return
+	}
+
+	deadline := clock.OrReal(s.clock).Now().Add(-maxAge).UnixNano()
+	stale := func(sentAt int64) bool { return sentAt < deadline }
+	enquireLinkSeq := atomic.LoadInt32(&s.enquireLinkSeq)
+
+	var expired []int32
+	s.inflight.each(func(seq int32, sentAt int64) {
+		if stale(sentAt) && seq != enquireLinkSeq {
+			expired = append(expired, seq)
+		}
+	})
+	for _, seq := range expired {
+		// response could have been read meanwhile
+		if _, ok := s.inflight.takeIf(seq, stale); ok {
+			s.sending.take(seq)
+			s.timeout()
+		}
+	}
+	if len(expired) > 0 {
+		s.pressure()
+	}
+}
+
+func (s *linkStats) timeout() {
+	if s.counters != nil {
+		s.counters.inc(&s.counters.timeouts, MetricTimeouts)
+	}
+}
+
+func (s *linkStats) onSubmitError(p pdu.PDU, err error) {
+	if s == nil {
+		return
+	}
+	if s.counters != nil {
+		s.counters.inc(&s.counters.submitErrors, MetricSubmitErrors)
+	}
+	correlation := s.calls.correlationOf(p)
+	s.calls.fail(p, err)
+	if p != nil {
+		s.window.release(p.GetSequenceNumber())
+	}
+	if errors.Is(err, ErrWindowsFull) {
+		s.events.publish(Event{Type: EventWindowFull, PDU: p, Err: err, Correlation: correlation})
+		s.pressure()
+	}
+}
+
+// correlationOf returns correlation of pending call of request p, zero if there is none.
+func (s *linkStats) correlationOf(p pdu.PDU) Correlation {
+	if s == nil {
+		return Correlation{}
+	}
+	return s.calls.correlationOf(p)
+}
+
+// claim tells whether p should be written, false if its Call is canceled.
+func (s *linkStats) claim(p pdu.PDU) bool {
+	if s == nil || s.calls.claim(p) {
+		return true
+	}
+	s.window.release(p.GetSequenceNumber())
+	return false
+}
+
+func (s *linkStats) pressure() {
+	if s.onPressure != nil {
+		s.onPressure()
+	}
+}
+
+func (s *linkStats) onReceivingError(err error) {
+	if s == nil {
+		return
+	}
+	if s.counters != nil {
+		s.counters.inc(&s.counters.receivingErrors, MetricReceivingErrors)
+	}
+	if isDecodeError(err) {
+		s.events.publish(Event{Type: EventDecodeError, Err: err})
+	}
+}
+
+// onDeviation records received p deviating from specification. Response rejected by ValidationStrict fails
+// call of its request.
+func (s *linkStats) onDeviation(p pdu.PDU, err error, rejected bool) {
+	if s == nil {
+		return
+	}
+	if rejected && isResponsePDU(p) {
+		if c := s.calls.take(p.GetSequenceNumber()); c != nil {
+			c.finish(nil, err)
+		}
+	}
+	s.events.publish(Event{Type: EventDeviation, PDU: p, Err: err})
+}
+
+func (s *linkStats) activity() (t time.Time) {
+	if s != nil {
+		if v := atomic.LoadInt64(&s.lastActivity); v > 0 {
+			t = time.Unix(0, v)
+		}
+	}
+	return
+}
+
+func (s *linkStats) enquireLinkRTT() time.Duration {
+	if s == nil {
+		return 0
+	}
+	return time.Duration(atomic.LoadInt64(&s.lastEnquireLinkRTT))
+}
+
+func (s *linkStats) enquireLinkRTTAverage() time.Duration {
+	if s == nil {
+		return 0
+	}
+	return time.Duration(atomic.LoadInt64(&s.enquireLinkRTTEWMA))
+}
+
+// ewma returns exponentially weighted moving average with new sample, first sample initializes the average.
+func ewma(avg, sample int64) int64 {
+	if avg == 0 {
+		return sample
+	}
+	return int64(enquireLinkRTTWeight*float64(sample) + (1-enquireLinkRTTWeight)*float64(avg))
+}
+
+func (s *linkStats) outstanding() (n int) {
+	if s != nil {
+		n = s.inflight.len()
+	}
+	return
+}
+
+// isResponsePDU checks if command id of the PDU has response bit set.
+func isResponsePDU(p pdu.PDU) bool {
+	return p.GetHeader().CommandID < 0
+}
+
+// IsBound returns true when session currently holds an alive bind to SMSC.
+//
+// It returns false while session is closed or rebinding.
+func (s *Session) IsBound() bool {
+	if atomic.LoadInt32(&s.state) != Alive || atomic.LoadInt32(&s.rebinding) != 0 {
+		return false
+	}
+
+	b := s.bound()
+	return b != nil && atomic.LoadInt32(&b.aliveState) == Alive
+}
+
+// LastEnquireLinkRTT returns round-trip time of the latest answered enquire_link
+// sent automatically by the session.
+//
+// Zero is returned if no enquire_link_resp was received on current bind.
+func (s *Session) LastEnquireLinkRTT() time.Duration {
+	if b := s.bound(); b != nil {
+		return b.stats.enquireLinkRTT()
+	}
+	return 0
+}
+
+// EnquireLinkRTTAverage returns exponentially weighted moving average of enquire_link round-trip times
+// on current bind, the latest sample has weight of 0.2. Unlike LastEnquireLinkRTT, it is not affected
+// much by single slow response, so could be used to compare link quality of multiple binds.
+//
+// Zero is returned if no enquire_link_resp was received on current bind.
+func (s *Session) EnquireLinkRTTAverage() time.Duration {
+	if b := s.bound(); b != nil {
+		return b.stats.enquireLinkRTTAverage()
+	}
+	return 0
+}
+
+// LastActivity returns time of the latest PDU sent to or received from SMSC on current bind.
+//
+// Zero time is returned if there is no activity yet.
+func (s *Session) LastActivity() time.Time {
+	if b := s.bound(); b != nil {
+		return b.stats.activity()
+	}
+	return time.Time{}
+}
+
+// OutstandingCount returns number of requests sent to SMSC which are still waiting for response.
+//
+// If WindowedRequestTracking is set, the count is taken from request store.
+func (s *Session) OutstandingCount() int {
+	b := s.bound()
+	if b == nil {
+		return 0
+	}
+
+	if s.settings.WindowedRequestTracking != nil {
+		ctx, cancelFunc := context.WithTimeout(context.Background(), s.settings.StoreAccessTimeOut*time.Millisecond)
+		defer cancelFunc()
+		if n, err := s.requestStore.Length(ctx); err == nil {
+			return n
+		}
+	}
+
+	return b.stats.outstanding()
+}
+
+// Healthy is combined predicate telling whether traffic could be routed to this session.
+//
+// Session is healthy when:
+//   - it is bound
+//   - there was activity within ReadTimeout (if EnquireLink is enabled)
+//   - request window is not full (if WindowedRequestTracking is set)
+func (s *Session) Healthy() bool {
+	if !s.IsBound() {
+		return false
+	}
+
+	if s.settings.EnquireLink > 0 {
+		if last := s.LastActivity(); !last.IsZero() && clock.OrReal(s.settings.Clock).Since(last) > s.settings.ReadTimeout {
+			return false
+		}
+	}
+
+	if s.settings.WindowedRequestTracking != nil && s.OutstandingCount() >= int(s.settings.MaxWindowSize) {
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,107 @@
+package gosmpp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp/clock"
+	"github.com/linxGnu/gosmpp/pdu"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLinkStats(t *testing.T) {
+	t.Run("nilSafe", func(t *testing.T) {
+		var s *linkStats
+		s.onWritten(pdu.NewEnquireLink())
+		s.onReceived(pdu.NewEnquireLinkResp())
+		require.True(t, s.activity().IsZero())
+		require.Zero(t, s.enquireLinkRTT())
+		require.Zero(t, s.enquireLinkRTTAverage())
+		require.Zero(t, s.outstanding())
+	})
+
+	t.Run("tracking", func(t *testing.T) {
+		s := newLinkStats()
+
+		eq := pdu.NewEnquireLink()
+		s.onWritten(eq)
+		s.onWritten(pdu.NewSubmitSM())
+		require.Equal(t, 2, s.outstanding())
+		require.False(t, s.activity().IsZero())
+
+		time.Sleep(5 * time.Millisecond)
+		s.onReceived(eq.GetResponse())
+		require.Equal(t, 1, s.outstanding())
+		require.GreaterOrEqual(t, s.enquireLinkRTT(), 5*time.Millisecond)
+		require.Equal(t, s.enquireLinkRTT(), s.enquireLinkRTTAverage())
+
+		// non-response pdu does not affect outstanding requests
+		s.onReceived(pdu.NewDeliverSM())
+		require.Equal(t, 1, s.outstanding())
+	})
+}
+
+func TestEWMA(t *testing.T) {
+	require.EqualValues(t, 100, ewma(0, 100))
+	require.EqualValues(t, 120, ewma(100, 200))
+	require.EqualValues(t, 100, ewma(100, 100))
+}
+
+func TestSessionHealth(t *testing.T) {
+	auth := nextAuth()
+	s, err := NewSession(
+		TRXConnector(NonTLSDialer, auth),
+		Settings{
+			ReadTimeout: 2 * time.Second,
+			EnquireLink: 200 * time.Millisecond,
+		}, -1)
+	require.Nil(t, err)
+
+	require.True(t, s.IsBound())
+
+	time.Sleep(700 * time.Millisecond)
+	require.True(t, s.Healthy())
+	require.NotZero(t, s.LastEnquireLinkRTT())
+	require.NotZero(t, s.EnquireLinkRTTAverage())
+	require.WithinDuration(t, time.Now(), s.LastActivity(), time.Second)
+	require.LessOrEqual(t, s.OutstandingCount(), 1) // at most one enquire_link in flight
+
+	require.Nil(t, s.Close())
+	require.False(t, s.IsBound())
+	require.False(t, s.Healthy())
+}
+
+func TestLinkStatsExpire(t *testing.T) {
+	clk := clock.NewFake(time.Unix(1700000000, 0))
+	s := newLinkStats()
+	s.clock = clk
+	s.counters = &sessionCounters{}
+
+	stale := pdu.NewSubmitSM()
+	s.onWritten(stale)
+	eq := pdu.NewEnquireLink()
+	s.onWritten(eq)
+
+	clk.Advance(5 * time.Second)
+	fresh := pdu.NewSubmitSM()
+	s.onWritten(fresh)
+	require.Equal(t, 3, s.outstanding())
+
+	s.expire(2 * time.Second)
+	// unanswered enquire_link is kept until the next one is written
+	require.Equal(t, 2, s.outstanding())
+	require.EqualValues(t, 1, s.counters.timeouts)
+
+	// late response is ignored
+	s.onReceived(stale.GetResponse())
+	require.Equal(t, 2, s.outstanding())
+
+	s.onWritten(pdu.NewEnquireLink())
+	require.Equal(t, 2, s.outstanding())
+	require.EqualValues(t, 2, s.counters.timeouts)
+
+	s.onReceived(fresh.GetResponse())
+	require.Equal(t, 1, s.outstanding())
+	require.EqualValues(t, 2, s.counters.timeouts)
+}
@@ -0,0 +1,154 @@
+package gosmpp
+
+import (
+	"time"
+
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+)
+
+// esmMessageType is mask of message type bits of esm_class, zero for mobile originated messages.
+const esmMessageType = 0x3C
+
+// IncomingMessage is decoded mobile originated message, received in deliver_sm or data_sm.
+type IncomingMessage struct {
+	// From and To are source and destination addresses: international numbers with "+" prefix,
+	// others as received.
+	From, To string
+
+	// SourceAddr and DestAddr are the received addresses with TON/NPI.
+	SourceAddr, DestAddr pdu.Address
+
+	// Text is the message decoded by data coding, empty for binary messages or unknown data coding.
+	Text string
+
+	// Data is the message without user data header, as received.
+	Data []byte
+
+	// DataCoding is data_coding of the message and Encoding its alphabet, nil if unknown.
+	DataCoding byte
+	Encoding   data.Encoding
+
+	// UDH is user data header, nil if none.
+	UDH pdu.UDH
+
+	// ReceivedAt is time the message is received by Session, zero for messages parsed by ParseIncomingMessage.
+	ReceivedAt time.Time
+
+	// PDU is the received deliver_sm or data_sm.
+	PDU pdu.PDU
+}
+
+// Binary reports whether message carries binary data rather than text.
+func (m IncomingMessage) Binary() bool {
+	return m.Encoding == data.BINARY8BIT1 || m.Encoding == data.BINARY8BIT2
+}
+
+// Concat returns concatenation info of message part, found false if message is not a part.
+func (m IncomingMessage) Concat() (total, seq, ref byte, found bool) {
+	return m.UDH.GetConcatInfo()
+}
+
+// ApplicationPort returns application ports of the message, found false if not addressed to application port.
+func (m IncomingMessage) ApplicationPort() (dest, src uint16, found bool) {
+	return m.UDH.GetApplicationPort()
+}
+
+// ParseIncomingMessage decodes mobile originated message from deliver_sm or data_sm, reporting false
+// for other PDUs and for receipts and acknowledgements, see ParseReceipt.
+//
+// Message is taken from short_message, or from message_payload TLV if short_message is empty.
+func ParseIncomingMessage(p pdu.PDU) (m IncomingMessage, ok bool) {
+	var (
+		esmClass byte
+		params   map[pdu.Tag]pdu.Field
+	)
+	switch p := p.(type) {
+	case *pdu.DeliverSM:
+		if p.EsmClass&esmMessageType != 0 {
+			return m, false
+		}
+		esmClass, params = p.EsmClass, p.OptionalParameters
+		m.SourceAddr, m.DestAddr = p.SourceAddr, p.DestAddr
+		m.DataCoding = p.Message.DataCoding()
+		m.UDH = p.Message.UDH()
+		m.Data, _ = p.Message.GetMessageData()
+
+	case *pdu.DataSM:
+		if p.EsmClass&esmMessageType != 0 {
+			return m, false
+		}
+		esmClass, params = p.EsmClass, p.OptionalParameters
+		m.SourceAddr, m.DestAddr, m.DataCoding = p.SourceAddr, p.DestAddr, p.DataCoding
+
+	default:
+		return m, false
+	}
+
+	if payload, found := params[pdu.TagMessagePayload]; found && len(m.Data) == 0 {
+		m.Data = payload.Data
+		if esmClass&data.SM_UDH_GSM != 0 {
+			var udh pdu.UDH
+			if _, err := udh.UnmarshalBinary(m.Data); err == nil && udh.UDHL() <= len(m.Data) {
+				m.UDH, m.Data = udh, m.Data[udh.UDHL():]
+			}
+		}
+	}
+
+	m.From, m.To = addressString(m.SourceAddr), addressString(m.DestAddr)
+	m.Encoding = dcsAlphabet(m.DataCoding)
+	if m.Encoding != nil && !m.Binary() {
+		m.Text, _ = m.Encoding.Decode(m.Data)
+	}
+	m.PDU = p
+	return m, true
+}
+
+// addressString returns address with "+" prefix if it is international number.
+func addressString(a pdu.Address) string {
+	if a.Ton() == data.GSM_TON_INTERNATIONAL && a.Address() != "" {
+		return "+" + a.Address()
+	}
+	return a.Address()
+}
+
+// dcsAlphabet returns alphabet of data coding: SMPP data coding, or coding groups of 3GPP TS 23.038 section 4
+// for other values, e.g. with message class. Nil is returned for compressed or reserved data codings.
+func dcsAlphabet(dcs byte) data.Encoding {
+	if enc := data.FromDataCoding(dcs); enc != nil {
+		return enc
+	}
+
+	switch {
+	case dcs&0xC0 == 0x00: // general data coding
+		if dcs&0x20 != 0 {
+			return nil
+		}
+		return generalAlphabet(dcs)
+
+	case dcs&0xF0 == dcsMWIDiscard, dcs&0xF0 == dcsMWIStoreGSM7:
+		return data.GSM7BIT
+
+	case dcs&0xF0 == dcsMWIStoreUCS2:
+		return data.UCS2
+
+	case dcs&0xF0 == 0xF0: // data coding and message class
+		if dcs&0x04 != 0 {
+			return data.BINARY8BIT2
+		}
+		return data.GSM7BIT
+	}
+	return nil
+}
+
+func generalAlphabet(dcs byte) data.Encoding {
+	switch dcs & 0x0C {
+	case 0x00:
+		return data.GSM7BIT
+	case 0x04:
+		return data.BINARY8BIT2
+	case 0x08:
+		return data.UCS2
+	}
+	return nil
+}
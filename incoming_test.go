@@ -0,0 +1,173 @@
+package gosmpp
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+
+	"github.com/stretchr/testify/require"
+)
+
+// received returns p as parsed from the wire.
+func received(t *testing.T, p pdu.PDU) pdu.PDU {
+	buf := pdu.NewBuffer(nil)
+	p.Marshal(buf)
+	parsed, err := pdu.Parse(bytes.NewReader(buf.Bytes()))
+	require.Nil(t, err)
+	return parsed
+}
+
+func newMO(from, to, text string) *pdu.DeliverSM {
+	p := pdu.NewDeliverSM().(*pdu.DeliverSM)
+	p.SourceAddr, _ = NormalizeAddress(from)
+	p.DestAddr, _ = NormalizeAddress(to)
+	_ = p.Message.SetMessageWithEncoding(text, textEncoding(text))
+	return p
+}
+
+func TestParseIncomingMessage(t *testing.T) {
+	p := newMO("+84901234567", "8888", "Xin chào bạn")
+	m, ok := ParseIncomingMessage(received(t, p))
+	require.True(t, ok)
+	require.Equal(t, "+84901234567", m.From)
+	require.Equal(t, "8888", m.To)
+	require.Equal(t, data.GSM_TON_INTERNATIONAL, m.SourceAddr.Ton())
+	require.Equal(t, "Xin chào bạn", m.Text)
+	require.Equal(t, data.UCS2, m.Encoding)
+	require.Equal(t, data.UCS2Coding, m.DataCoding)
+	require.False(t, m.Binary())
+	require.Nil(t, m.UDH)
+	require.True(t, m.ReceivedAt.IsZero())
+	_, _, _, found := m.Concat()
+	require.False(t, found)
+
+	// concatenated part
+	p = newMO("+84901234567", "8888", "part one")
+	p.EsmClass |= data.SM_UDH_GSM
+	p.Message.SetUDH(pdu.UDH{pdu.NewIEConcatMessage(2, 1, 7)})
+	m, ok = ParseIncomingMessage(received(t, p))
+	require.True(t, ok)
+	require.Equal(t, "part one", m.Text)
+	total, seq, ref, found := m.Concat()
+	require.True(t, found)
+	require.Equal(t, [3]byte{2, 1, 7}, [3]byte{total, seq, ref})
+
+	// binary message to application port
+	p = newMO("+84901234567", "8888", "")
+	p.EsmClass |= data.SM_UDH_GSM
+	_ = p.Message.SetMessageDataWithEncoding([]byte{0xCA, 0xFE}, data.BINARY8BIT2)
+	p.Message.SetUDH(pdu.UDH{pdu.NewIEApplicationPort(VCardPort, 0)})
+	m, ok = ParseIncomingMessage(received(t, p))
+	require.True(t, ok)
+	require.True(t, m.Binary())
+	require.Empty(t, m.Text)
+	require.Equal(t, []byte{0xCA, 0xFE}, m.Data)
+	dest, _, found := m.ApplicationPort()
+	require.True(t, found)
+	require.Equal(t, VCardPort, dest)
+
+	// class 1 GSM 7-bit, unknown to SMPP data codings
+	p = newMO("+84901234567", "8888", "hello")
+	_ = p.Message.SetMessageWithEncoding("hello", dcsEncoding{Encoding: data.GSM7BIT, coding: 0x11})
+	m, ok = ParseIncomingMessage(received(t, p))
+	require.True(t, ok)
+	require.Equal(t, byte(0x11), m.DataCoding)
+	require.Equal(t, "hello", m.Text)
+
+	// receipts and submits are not incoming messages
+	_, ok = ParseIncomingMessage(newReceipt("id:1 stat:DELIVRD"))
+	require.False(t, ok)
+	_, ok = ParseIncomingMessage(pdu.NewSubmitSM())
+	require.False(t, ok)
+}
+
+func TestParseIncomingDataSM(t *testing.T) {
+	d := pdu.NewDataSM().(*pdu.DataSM)
+	d.SourceAddr, _ = NormalizeAddress("+84901234567")
+	d.DestAddr, _ = NormalizeAddress("8888")
+	d.EsmClass = data.SM_UDH_GSM
+	d.DataCoding = data.GSM7BITCoding
+
+	udh, _ := pdu.UDH{pdu.NewIEConcatMessage(3, 2, 9)}.MarshalBinary()
+	d.RegisterOptionalParam(pdu.Field{Tag: pdu.TagMessagePayload, Data: append(udh, "STOP"...)})
+
+	m, ok := ParseIncomingMessage(received(t, d))
+	require.True(t, ok)
+	require.Equal(t, "STOP", m.Text)
+	total, seq, ref, found := m.Concat()
+	require.True(t, found)
+	require.Equal(t, [3]byte{3, 2, 9}, [3]byte{total, seq, ref})
+}
+
+func TestDCSAlphabet(t *testing.T) {
+	for dcs, enc := range map[byte]data.Encoding{
+		0x00: data.GSM7BIT,
+		0x03: data.LATIN1,
+		0x08: data.UCS2,
+		0x10: data.GSM7BIT,
+		0x15: data.BINARY8BIT2,
+		0x18: data.UCS2,
+		0xC8: data.GSM7BIT,
+		0xD1: data.GSM7BIT,
+		0xE0: data.UCS2,
+		0xF1: data.GSM7BIT,
+		0xF6: data.BINARY8BIT2,
+		0x20: nil,
+		0x90: nil,
+	} {
+		require.Equal(t, enc, dcsAlphabet(dcs), "%#x", dcs)
+	}
+}
+
+func TestReceivableOnMessage(t *testing.T) {
+	var (
+		messages  []IncomingMessage
+		pdus      []pdu.PDU
+		responses []pdu.PDU
+	)
+	r := &receivable{settings: Settings{
+		OnMessage: func(m IncomingMessage) { messages = append(messages, m) },
+		OnPDU:     func(p pdu.PDU, _ bool) { pdus = append(pdus, p) },
+		response:  func(p pdu.PDU) { responses = append(responses, p) },
+	}}
+
+	require.False(t, r.handleOrClose(newMO("+84901234567", "8888", "HELP")))
+	require.False(t, r.handleOrClose(newReceipt("id:1 stat:DELIVRD")))
+
+	require.Len(t, messages, 1)
+	require.Equal(t, "HELP", messages[0].Text)
+	require.False(t, messages[0].ReceivedAt.IsZero())
+	require.Len(t, pdus, 1)
+	require.Len(t, responses, 2)
+}
+
+func TestSessionOnMessage(t *testing.T) {
+	messages := make(chan IncomingMessage, 8)
+
+	auth := nextAuth()
+	trans, err := NewSession(
+		TRXConnector(NonTLSDialer, auth),
+		Settings{
+			ReadTimeout: 2 * time.Second,
+
+			OnMessage: func(m IncomingMessage) { messages <- m },
+		}, -1)
+	require.Nil(t, err)
+	defer func() {
+		_ = trans.Close()
+	}()
+
+	// SMSC simulator sends submitted message back as mobile originated one
+	require.Nil(t, trans.Transceiver().Submit(newSubmitSM(auth.SystemID)))
+
+	select {
+	case m := <-messages:
+		require.Equal(t, mess, m.Text)
+		require.False(t, m.ReceivedAt.IsZero())
+	case <-time.After(5 * time.Second):
+		t.Fatal("message is not received")
+	}
+}
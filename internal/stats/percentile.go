@@ -0,0 +1,11 @@
+// Package stats holds statistics helpers shared by session metrics and load generator.
+package stats
+
+// PercentileIndex returns nearest-rank index of p-th percentile in sorted samples of size n.
+func PercentileIndex(n, p int) int {
+	idx := (n*p+99)/100 - 1
+	if idx < 0 {
+		idx = 0
+	}
+	return idx
+}
@@ -0,0 +1,17 @@
+package stats
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPercentileIndex(t *testing.T) {
+	require.Equal(t, 0, PercentileIndex(1, 50))
+	require.Equal(t, 0, PercentileIndex(1, 99))
+	require.Equal(t, 4, PercentileIndex(10, 50))
+	require.Equal(t, 8, PercentileIndex(10, 90))
+	require.Equal(t, 9, PercentileIndex(10, 99))
+	require.Equal(t, 98, PercentileIndex(100, 99))
+	require.Equal(t, 0, PercentileIndex(10, 0))
+}
@@ -0,0 +1,86 @@
+package gosmpp
+
+import (
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// KeywordRouter dispatches incoming messages to handlers by keyword, the first word of the text, and short code,
+// the destination address. Keywords are matched case-insensitively, ignoring punctuation around them, e.g.
+// "stop." matches "STOP".
+//
+// Handler is chosen in order: of the keyword on the short code, of the keyword on any short code,
+// of any keyword on the short code, the default handler. Routed messages should be whole, see Reassembler:
+//
+//	router := gosmpp.NewKeywordRouter(onOther)
+//	router.Handle("STOP", onStop)
+//	router.HandleShortCode("8888", "", onBanking)
+//	reassembler := gosmpp.NewReassembler(router.HandleMessage)
+type KeywordRouter struct {
+	fallback func(IncomingMessage)
+
+	mu       sync.RWMutex
+	handlers map[keywordRoute]func(IncomingMessage)
+}
+
+type keywordRoute struct {
+	shortCode, keyword string
+}
+
+// NewKeywordRouter returns router passing messages without handler to fallback, dropping them if it is nil.
+func NewKeywordRouter(fallback func(IncomingMessage)) *KeywordRouter {
+	return &KeywordRouter{
+		fallback: fallback,
+		handlers: make(map[keywordRoute]func(IncomingMessage)),
+	}
+}
+
+// Handle sets handler of keyword on any short code.
+func (r *KeywordRouter) Handle(keyword string, handler func(IncomingMessage)) {
+	r.HandleShortCode("", keyword, handler)
+}
+
+// HandleShortCode sets handler of keyword on short code, e.g. "8888". Empty keyword matches any keyword.
+func (r *KeywordRouter) HandleShortCode(shortCode, keyword string, handler func(IncomingMessage)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.handlers[keywordRoute{shortCode: shortCode, keyword: normalizeKeyword(keyword)}] = handler
+}
+
+// HandleMessage dispatches m to its handler.
+func (r *KeywordRouter) HandleMessage(m IncomingMessage) {
+	if handler := r.handler(m.To, Keyword(m.Text)); handler != nil {
+		handler(m)
+	}
+}
+
+func (r *KeywordRouter) handler(shortCode, keyword string) func(IncomingMessage) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, route := range []keywordRoute{{shortCode, keyword}, {"", keyword}, {shortCode, ""}} {
+		if route.keyword == "" && route.shortCode == "" {
+			continue
+		}
+		if handler, ok := r.handlers[route]; ok {
+			return handler
+		}
+	}
+	return r.fallback
+}
+
+// Keyword returns the first word of text in upper case, without punctuation around it.
+func Keyword(text string) string {
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return ""
+	}
+	return normalizeKeyword(fields[0])
+}
+
+func normalizeKeyword(keyword string) string {
+	return strings.ToUpper(strings.TrimFunc(keyword, func(r rune) bool {
+		return unicode.IsPunct(r) || unicode.IsSpace(r)
+	}))
+}
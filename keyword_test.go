@@ -0,0 +1,42 @@
+package gosmpp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyword(t *testing.T) {
+	require.Equal(t, "STOP", Keyword("  stop. please"))
+	require.Equal(t, "HELP", Keyword("Help!"))
+	require.Equal(t, "", Keyword(" "))
+}
+
+func TestKeywordRouter(t *testing.T) {
+	var routed []string
+	route := func(name string) func(IncomingMessage) {
+		return func(IncomingMessage) {
+			routed = append(routed, name)
+		}
+	}
+
+	r := NewKeywordRouter(route("default"))
+	r.Handle("stop", route("stop"))
+	r.HandleShortCode("8888", "STOP", route("8888 stop"))
+	r.HandleShortCode("9999", "", route("9999"))
+
+	for _, m := range []IncomingMessage{
+		{To: "8888", Text: "STOP"},
+		{To: "7777", Text: "Stop."},
+		{To: "9999", Text: "stop"},
+		{To: "9999", Text: "balance"},
+		{To: "7777", Text: "balance"},
+		{To: "7777", Text: ""},
+	} {
+		r.HandleMessage(m)
+	}
+	require.Equal(t, []string{"8888 stop", "stop", "stop", "9999", "default", "default"}, routed)
+
+	// dropped without default handler
+	NewKeywordRouter(nil).HandleMessage(IncomingMessage{Text: "HELP"})
+}
@@ -0,0 +1,38 @@
+package gosmpp
+
+// Labels are key/value pairs attached to a session for observability, e.g. route name, carrier or datacenter.
+//
+// Labels are propagated into SessionStats, Metrics and every Event published by the session.
+type Labels map[string]string
+
+func (l Labels) clone() Labels {
+	if len(l) == 0 {
+		return nil
+	}
+
+	c := make(Labels, len(l))
+	for k, v := range l {
+		c[k] = v
+	}
+	return c
+}
+
+// WithLabels attaches labels to the session, merged with labels set by previous WithLabels.
+func WithLabels(labels Labels) SessionOption {
+	return func(s *Session) {
+		merged := s.labels.clone()
+		if merged == nil {
+			merged = make(Labels, len(labels))
+		}
+		for k, v := range labels {
+			merged[k] = v
+		}
+		s.labels = merged
+		s.events.labels = merged
+	}
+}
+
+// Labels returns copy of labels attached to the session.
+func (s *Session) Labels() Labels {
+	return s.labels.clone()
+}
@@ -0,0 +1,52 @@
+package gosmpp
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithLabels(t *testing.T) {
+	var s Session
+	require.Nil(t, s.Labels())
+
+	WithLabels(Labels{"route": "otp", "carrier": "a"})(&s)
+	WithLabels(Labels{"carrier": "b"})(&s)
+	require.Equal(t, Labels{"route": "otp", "carrier": "b"}, s.Labels())
+
+	// returned labels are copy
+	s.Labels()["route"] = "marketing"
+	require.Equal(t, "otp", s.Labels()["route"])
+}
+
+func TestSessionLabels(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		events []Event
+	)
+
+	labels := Labels{"dc": "eu-1"}
+	s, err := NewSession(
+		TRXConnector(NonTLSDialer, nextAuth()),
+		Settings{
+			ReadTimeout: 2 * time.Second,
+		}, -1, WithLabels(labels), WithEventHandler(func(e Event) {
+			mu.Lock()
+			events = append(events, e)
+			mu.Unlock()
+		}))
+	require.Nil(t, err)
+	require.Nil(t, s.Close())
+
+	require.Equal(t, labels, s.Stats().Labels)
+	require.Equal(t, labels, s.Metrics(time.Second).Labels)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.NotEmpty(t, events)
+	for _, e := range events {
+		require.Equal(t, labels, e.Labels)
+	}
+}
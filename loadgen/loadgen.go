@@ -0,0 +1,366 @@
+// Package loadgen generates load of messages against SMSC, e.g. the simulator or smsctest server, and reports
+// throughput, latency percentiles and distribution of errors.
+//
+// Messages are sent by pool of messengers, e.g. of config.Sessions:
+//
+//	sessions, err := cfg.Open()
+//	...
+//	g := &loadgen.Generator{
+//		Messengers: sessions.Pools["bulk"],
+//		From:       "MyShop",
+//		To:         []string{"+447700900123", "+447700900124"},
+//		Rate:       200,
+//		Duration:   time.Minute,
+//	}
+//	report, err := g.Run(ctx)
+//	fmt.Println(report)
+package loadgen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/linxGnu/gosmpp"
+	"github.com/linxGnu/gosmpp/internal/stats"
+)
+
+// Encoding is encoding of generated messages.
+type Encoding string
+
+const (
+	// GSM7 messages are text of GSM 7-bit default alphabet.
+	GSM7 Encoding = "gsm7"
+
+	// UCS2 messages are text outside of GSM 7-bit alphabet, sent in UCS2.
+	UCS2 Encoding = "ucs2"
+
+	// Binary messages are 8-bit binary payloads to application port, see gosmpp.Messenger.SendBinary.
+	Binary Encoding = "binary"
+)
+
+const (
+	// DefaultConcurrency is default number of messages in flight.
+	DefaultConcurrency = 10
+
+	// DefaultSize is default size of messages, in characters of text or bytes of binary payload.
+	DefaultSize = 70
+
+	// DefaultTimeout is default time to wait for all parts of a message to be responded.
+	DefaultTimeout = 30 * time.Second
+
+	// DefaultPort is default destination application port of binary messages.
+	DefaultPort = 9200
+)
+
+var (
+	// ErrNoMessengers indicates Generator has no messengers to send with.
+	ErrNoMessengers = errors.New("loadgen: no messengers")
+
+	// ErrNoDestinations indicates Generator has no destinations to send to.
+	ErrNoDestinations = errors.New("loadgen: no destinations")
+)
+
+// Generator sends messages at given rate, round robin over its messengers and destinations, until Count
+// messages are sent, Duration elapsed or context of Run is done.
+type Generator struct {
+	// Messengers are pool of messengers sending the messages.
+	Messengers []*gosmpp.Messenger
+
+	// From is source address of messages, required.
+	From string
+
+	// To are destination addresses of messages, cycled.
+	To []string
+
+	// Rate is number of messages sent per second, unlimited if 0: as many as Concurrency allows.
+	Rate float64
+
+	// Concurrency is maximum number of messages in flight, DefaultConcurrency by default.
+	Concurrency int
+
+	// Size is size of messages in characters of text or bytes of binary payload, DefaultSize by default.
+	// Messages longer than single part are split into concatenated parts.
+	Size int
+
+	// Encoding of messages, GSM7 by default.
+	Encoding Encoding
+
+	// Port is destination application port of Binary messages, DefaultPort by default.
+	Port uint16
+
+	// Count is number of messages to send, unlimited if 0.
+	Count int
+
+	// Duration is time of sending messages, unlimited if 0. Messages in flight are still waited for.
+	Duration time.Duration
+
+	// Timeout is time to wait for all parts of a message to be responded, DefaultTimeout by default.
+	Timeout time.Duration
+}
+
+// Report is result of Generator.Run.
+type Report struct {
+	// Sent is number of messages sent, Accepted and Failed are those accepted by SMSC and not.
+	Sent     int
+	Accepted int
+	Failed   int
+
+	// Parts is number of accepted parts of messages.
+	Parts int
+
+	// Elapsed is time from sending the first message to the response of the last one.
+	Elapsed time.Duration
+
+	// Throughput is number of accepted messages per second.
+	Throughput float64
+
+	// Latency percentiles of accepted messages, from sending to the response of their last part.
+	LatencyP50 time.Duration
+	LatencyP90 time.Duration
+	LatencyP99 time.Duration
+	LatencyMax time.Duration
+
+	// Errors are numbers of failed messages by reason: command status of rejected ones, e.g. "ESME_RTHROTTLED",
+	// "timeout" of those not responded in time, text of other errors.
+	Errors map[string]int
+}
+
+// String returns the report in lines of text.
+func (r Report) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Sent:       %d messages in %s\n", r.Sent, r.Elapsed.Round(time.Millisecond))
+	fmt.Fprintf(&b, "Accepted:   %d messages (%d parts)\n", r.Accepted, r.Parts)
+	fmt.Fprintf(&b, "Failed:     %d messages\n", r.Failed)
+	fmt.Fprintf(&b, "Throughput: %.1f messages/s\n", r.Throughput)
+	fmt.Fprintf(&b, "Latency:    p50 %s, p90 %s, p99 %s, max %s\n",
+		r.LatencyP50, r.LatencyP90, r.LatencyP99, r.LatencyMax)
+
+	reasons := make([]string, 0, len(r.Errors))
+	for reason := range r.Errors {
+		reasons = append(reasons, reason)
+	}
+	sort.Slice(reasons, func(i, j int) bool {
+		if r.Errors[reasons[i]] != r.Errors[reasons[j]] {
+			return r.Errors[reasons[i]] > r.Errors[reasons[j]]
+		}
+		return reasons[i] < reasons[j]
+	})
+	for _, reason := range reasons {
+		fmt.Fprintf(&b, "  %6d %s\n", r.Errors[reason], reason)
+	}
+	return b.String()
+}
+
+// Run sends messages and waits for all of them to be responded, returning the report. It returns error
+// only if Generator is misconfigured.
+func (g *Generator) Run(ctx context.Context) (Report, error) {
+	if len(g.Messengers) == 0 {
+		return Report{}, ErrNoMessengers
+	}
+	if len(g.To) == 0 {
+		return Report{}, ErrNoDestinations
+	}
+	text, payload, err := g.message()
+	if err != nil {
+		return Report{}, err
+	}
+
+	// messages in flight are waited for beyond Duration, until ctx is done
+	sending := ctx
+	if g.Duration > 0 {
+		var cancel context.CancelFunc
+		sending, cancel = context.WithTimeout(ctx, g.Duration)
+		defer cancel()
+	}
+
+	concurrency := g.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+	slots := make(chan struct{}, concurrency)
+
+	var (
+		wg  sync.WaitGroup
+		rec recorder
+	)
+	start := time.Now()
+
+loop:
+	for i := 0; g.Count == 0 || i < g.Count; i++ {
+		if g.Rate > 0 {
+			at := start.Add(time.Duration(float64(i) / g.Rate * float64(time.Second)))
+			if d := time.Until(at); d > 0 {
+				timer := time.NewTimer(d)
+				select {
+				case <-timer.C:
+				case <-sending.Done():
+					timer.Stop()
+					break loop
+				}
+			}
+		}
+
+		select {
+		case slots <- struct{}{}:
+		case <-sending.Done():
+			break loop
+		}
+		if sending.Err() != nil {
+			<-slots
+			break
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+			rec.record(g.send(ctx, i, text, payload))
+		}(i)
+	}
+
+	wg.Wait()
+	return rec.report(time.Since(start)), nil
+}
+
+// result is outcome of sending a message.
+type result struct {
+	parts   int
+	latency time.Duration
+	err     error
+}
+
+// send sends i-th message, text or binary payload.
+func (g *Generator) send(ctx context.Context, i int, text string, payload []byte) result {
+	messenger, to := g.Messengers[i%len(g.Messengers)], g.To[i%len(g.To)]
+
+	timeout := g.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	var (
+		h   *gosmpp.MessageHandle
+		err error
+	)
+	if payload != nil {
+		h, err = messenger.SendBinary(ctx, g.From, to, g.port(), 0, payload)
+	} else {
+		h, err = messenger.SendText(ctx, g.From, to, text)
+	}
+	r := result{latency: time.Since(start), err: err}
+	if err == nil {
+		r.parts = len(h.Parts)
+	}
+	return r
+}
+
+func (g *Generator) port() uint16 {
+	if g.Port == 0 {
+		return DefaultPort
+	}
+	return g.Port
+}
+
+// message returns text or binary payload of generated messages.
+func (g *Generator) message() (text string, payload []byte, err error) {
+	size := g.Size
+	if size <= 0 {
+		size = DefaultSize
+	}
+
+	switch g.Encoding {
+	case GSM7, "":
+		text = repeat("Load test 0123456789 ", size)
+	case UCS2:
+		text = repeat("Нагрузочный тест ", size)
+	case Binary:
+		payload = make([]byte, size)
+		for i := range payload {
+			payload[i] = byte(i)
+		}
+	default:
+		err = fmt.Errorf("loadgen: unknown encoding %q", g.Encoding)
+	}
+	return
+}
+
+// repeat returns s repeated to n characters.
+func repeat(s string, n int) string {
+	runes := []rune(strings.Repeat(s, n/len([]rune(s))+1))
+	return string(runes[:n])
+}
+
+// recorder collects results of sent messages.
+type recorder struct {
+	mu        sync.Mutex
+	sent      int
+	parts     int
+	latencies []time.Duration
+	errors    map[string]int
+}
+
+func (r *recorder) record(res result) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.sent++
+	if res.err != nil {
+		if r.errors == nil {
+			r.errors = make(map[string]int)
+		}
+		r.errors[reason(res.err)]++
+		return
+	}
+	r.parts += res.parts
+	r.latencies = append(r.latencies, res.latency)
+}
+
+func (r *recorder) report(elapsed time.Duration) (report Report) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	report = Report{
+		Sent:     r.sent,
+		Accepted: len(r.latencies),
+		Failed:   r.sent - len(r.latencies),
+		Parts:    r.parts,
+		Elapsed:  elapsed,
+		Errors:   r.errors,
+	}
+	if elapsed > 0 {
+		report.Throughput = float64(report.Accepted) / elapsed.Seconds()
+	}
+
+	if n := len(r.latencies); n > 0 {
+		latencies := r.latencies
+		sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+		report.LatencyP50 = latencies[stats.PercentileIndex(n, 50)]
+		report.LatencyP90 = latencies[stats.PercentileIndex(n, 90)]
+		report.LatencyP99 = latencies[stats.PercentileIndex(n, 99)]
+		report.LatencyMax = latencies[n-1]
+	}
+	return
+}
+
+// reason returns reason of failed message: command status of rejection, "timeout" or text of the error.
+func reason(err error) string {
+	var submitErr *gosmpp.SubmitError
+	switch {
+	case errors.As(err, &submitErr):
+		return submitErr.Status.String()
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, gosmpp.ErrResponseTimeout):
+		return "timeout"
+	default:
+		return err.Error()
+	}
+}
@@ -0,0 +1,113 @@
+package loadgen
+
+import (
+	"context"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/linxGnu/gosmpp"
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+	"github.com/linxGnu/gosmpp/server/smsctest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newMessengers(t *testing.T, smsc *smsctest.Server, n int) []*gosmpp.Messenger {
+	messengers := make([]*gosmpp.Messenger, n)
+	for i := range messengers {
+		session, err := gosmpp.NewSession(
+			gosmpp.TXConnector(smsc.Dialer(), gosmpp.Auth{SMSC: "smsc", SystemID: "load", Password: "secret"}),
+			gosmpp.Settings{ReadTimeout: 2 * time.Second}, -1)
+		require.Nil(t, err)
+		t.Cleanup(func() {
+			_ = session.Close()
+		})
+		messengers[i] = gosmpp.NewMessenger(session)
+	}
+	return messengers
+}
+
+func TestGenerator(t *testing.T) {
+	smsc := smsctest.NewPipeServer(map[string]string{"load": "secret"})
+	defer smsc.Close()
+	smsc.SetFault(smsctest.Script(
+		smsctest.Fault{Status: data.ESME_RTHROTTLED},
+		smsctest.Fault{Drop: true},
+		smsctest.Fault{Status: data.ESME_RTHROTTLED},
+	))
+
+	g := &Generator{
+		Messengers:  newMessengers(t, smsc, 2),
+		From:        "Load",
+		To:          []string{"+447700900123", "+447700900124"},
+		Concurrency: 1,
+		Count:       20,
+		Timeout:     200 * time.Millisecond,
+	}
+	report, err := g.Run(context.Background())
+	require.Nil(t, err)
+
+	require.Equal(t, 20, report.Sent)
+	require.Equal(t, 17, report.Accepted)
+	require.Equal(t, 3, report.Failed)
+	require.Equal(t, 17, report.Parts)
+	require.Equal(t, map[string]int{"ESME_RTHROTTLED": 2, "timeout": 1}, report.Errors)
+	require.True(t, report.Throughput > 0)
+	require.True(t, report.LatencyP50 <= report.LatencyP99 && report.LatencyP99 <= report.LatencyMax)
+	require.Contains(t, report.String(), "     2 ESME_RTHROTTLED\n")
+
+	// rejected ones are not submitted
+	require.Len(t, smsc.Submitted(), 18)
+}
+
+func TestGeneratorRate(t *testing.T) {
+	smsc := smsctest.NewPipeServer(map[string]string{"load": "secret"})
+	defer smsc.Close()
+
+	g := &Generator{
+		Messengers: newMessengers(t, smsc, 1),
+		From:       "Load",
+		To:         []string{"+447700900123"},
+		Rate:       50,
+		Duration:   200 * time.Millisecond,
+		Size:       200,
+		Encoding:   UCS2,
+	}
+	report, err := g.Run(context.Background())
+	require.Nil(t, err)
+
+	// 50/s over 200ms, the first one sent at once
+	require.InDelta(t, 10, report.Sent, 2)
+	require.Equal(t, report.Sent, report.Accepted, report.Errors)
+	require.Equal(t, 3*report.Accepted, report.Parts)
+
+	submit := smsc.Submitted()[0].(*pdu.SubmitSM)
+	require.Equal(t, data.UCS2Coding, submit.Message.DataCoding())
+}
+
+func TestGeneratorMessage(t *testing.T) {
+	text, payload, err := (&Generator{Size: 300}).message()
+	require.Nil(t, err)
+	require.Nil(t, payload)
+	require.Len(t, text, 300)
+	_, err = data.GSM7BIT.Encode(text)
+	require.Nil(t, err)
+
+	text, _, err = (&Generator{Encoding: UCS2}).message()
+	require.Nil(t, err)
+	require.Equal(t, DefaultSize, utf8.RuneCountInString(text))
+	_, err = data.GSM7BIT.Encode(text)
+	require.NotNil(t, err)
+
+	_, payload, err = (&Generator{Encoding: Binary, Size: 10}).message()
+	require.Nil(t, err)
+	require.Equal(t, []byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, payload)
+
+	_, _, err = (&Generator{Encoding: "utf8"}).message()
+	require.NotNil(t, err)
+
+	_, err = (&Generator{}).Run(context.Background())
+	require.ErrorIs(t, err, ErrNoMessengers)
+}
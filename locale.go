@@ -0,0 +1,92 @@
+package gosmpp
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+)
+
+// ErrNoVariant indicates there is no variant of message for the locale of destination nor the default one.
+var ErrNoVariant = errors.New("no message variant for locale")
+
+// LocaleResolver returns locale of destination, e.g. "vi" or "en-GB", empty if unknown.
+type LocaleResolver interface {
+	Locale(dest pdu.Address) string
+}
+
+// LocaleResolverFunc is function implementing LocaleResolver.
+type LocaleResolverFunc func(dest pdu.Address) string
+
+// Locale implements LocaleResolver.
+func (f LocaleResolverFunc) Locale(dest pdu.Address) string {
+	return f(dest)
+}
+
+// PrefixLocales is LocaleResolver of international numbers by the longest matching prefix, e.g. calling code:
+//
+//	gosmpp.PrefixLocales{"84": "vi", "44": "en-GB", "41": "de-CH", "4191": "it-CH"}
+type PrefixLocales map[string]string
+
+// Locale implements LocaleResolver.
+func (p PrefixLocales) Locale(dest pdu.Address) string {
+	if dest.Ton() != data.GSM_TON_INTERNATIONAL {
+		return ""
+	}
+
+	number := dest.Address()
+	for n := len(number); n > 0; n-- {
+		if locale, ok := p[number[:n]]; ok {
+			return locale
+		}
+	}
+	return ""
+}
+
+// WithLocaleResolver sets resolver of destination locales choosing variants sent by SendVariants.
+func WithLocaleResolver(resolver LocaleResolver) MessengerOption {
+	return func(m *Messenger) {
+		m.locales = resolver
+	}
+}
+
+// SendVariants sends variant of text for locale of destination, see WithLocaleResolver. Variants are texts
+// keyed by locale, e.g. {"en": "Your code is 1234", "vi": "Mã của bạn là 1234"}.
+//
+// Variant is chosen in order: of the locale, of its language, e.g. "en" for "en-GB", of defaultLocale.
+// Chosen text is encoded and split as by SendText.
+func (m *Messenger) SendVariants(ctx context.Context, from, to string, variants map[string]string, defaultLocale string) (*MessageHandle, error) {
+	var locale string
+	if m.locales != nil {
+		dest, err := m.normalizer.Normalize(to)
+		if err != nil {
+			return nil, err
+		}
+		locale = m.locales.Locale(dest)
+	}
+
+	text, ok := chooseVariant(variants, locale, defaultLocale)
+	if !ok {
+		return nil, ErrNoVariant
+	}
+	return m.SendText(ctx, from, to, text)
+}
+
+// chooseVariant returns variant of locale, of its language or of default locale.
+func chooseVariant(variants map[string]string, locale, defaultLocale string) (string, bool) {
+	if locale != "" {
+		if text, ok := variants[locale]; ok {
+			return text, true
+		}
+		if i := strings.IndexAny(locale, "-_"); i > 0 {
+			if text, ok := variants[locale[:i]]; ok {
+				return text, true
+			}
+		}
+	}
+
+	text, ok := variants[defaultLocale]
+	return text, ok
+}
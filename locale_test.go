@@ -0,0 +1,72 @@
+package gosmpp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrefixLocales(t *testing.T) {
+	locales := PrefixLocales{"84": "vi", "41": "de-CH", "4191": "it-CH"}
+
+	international := func(number string) pdu.Address {
+		a, err := pdu.NewAddressWithTonNpiAddr(data.GSM_TON_INTERNATIONAL, data.GSM_NPI_ISDN, number)
+		require.NoError(t, err)
+		return a
+	}
+	require.Equal(t, "vi", locales.Locale(international("84901234567")))
+	require.Equal(t, "de-CH", locales.Locale(international("41441234567")))
+	require.Equal(t, "it-CH", locales.Locale(international("41911234567")))
+	require.Equal(t, "", locales.Locale(international("447700900123")))
+
+	national, err := pdu.NewAddressWithTonNpiAddr(data.GSM_TON_UNKNOWN, data.GSM_NPI_ISDN, "84901234567")
+	require.NoError(t, err)
+	require.Equal(t, "", locales.Locale(national))
+}
+
+func TestChooseVariant(t *testing.T) {
+	variants := map[string]string{"en": "Hello", "vi": "Xin chào bạn", "de-CH": "Grüezi"}
+
+	for _, tc := range []struct {
+		locale, want string
+		ok           bool
+	}{
+		{"vi", "Xin chào bạn", true},
+		{"de-CH", "Grüezi", true},
+		{"en-GB", "Hello", true},
+		{"en_US", "Hello", true},
+		{"fr", "Hello", true},
+		{"", "Hello", true},
+	} {
+		text, ok := chooseVariant(variants, tc.locale, "en")
+		require.Equal(t, tc.ok, ok, tc.locale)
+		require.Equal(t, tc.want, text, tc.locale)
+	}
+
+	_, ok := chooseVariant(variants, "fr", "es")
+	require.False(t, ok)
+}
+
+func TestMessengerSendVariants(t *testing.T) {
+	m := newTestMessenger(t, WithLocaleResolver(PrefixLocales{"84": "vi"}))
+	variants := map[string]string{"en": "Your code is 1234", "vi": "Mã của bạn là 1234"}
+
+	h, err := m.SendVariants(context.Background(), "MyBank", "+84901234567", variants, "en")
+	require.NoError(t, err)
+	text, _ := h.Parts[0].Message.GetMessage()
+	require.Equal(t, "Mã của bạn là 1234", text)
+	require.Equal(t, data.UCS2, h.Parts[0].Message.Encoding())
+
+	h, err = m.SendVariants(context.Background(), "MyBank", "+447700900123", variants, "en")
+	require.NoError(t, err)
+	text, _ = h.Parts[0].Message.GetMessage()
+	require.Equal(t, "Your code is 1234", text)
+	require.Equal(t, data.GSM7BIT, h.Parts[0].Message.Encoding())
+
+	_, err = m.SendVariants(context.Background(), "MyBank", "+447700900123", variants, "fr")
+	require.ErrorIs(t, err, ErrNoVariant)
+}
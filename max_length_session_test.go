@@ -0,0 +1,95 @@
+package gosmpp_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp"
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+	"github.com/linxGnu/gosmpp/server/smsctest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionMaxCommandLength(t *testing.T) {
+	smsc := smsctest.NewPipeServer(nil)
+	defer smsc.Close()
+
+	receivingErrs := make(chan error, 1)
+	s, err := gosmpp.NewSession(gosmpp.TRXConnector(smsc.Dialer(), gosmpp.Auth{SMSC: "pipe", SystemID: "esme"}),
+		gosmpp.Settings{
+			ReadTimeout:      2 * time.Second,
+			MaxCommandLength: 200,
+			OnReceivingError: func(err error) {
+				receivingErrs <- err
+			},
+		}, -1)
+	require.Nil(t, err)
+	defer func() {
+		_ = s.Close()
+	}()
+
+	require.Nil(t, s.Transceiver().Submit(newTextSubmitSM("1", "short")))
+
+	long := newTextSubmitSM("1", "")
+	long.RegisterOptionalParam(pdu.Field{Tag: pdu.TagMessagePayload, Data: []byte(strings.Repeat("x", 200))})
+	err = s.Transceiver().Submit(long)
+	var sizeErr *pdu.SizeError
+	require.True(t, errors.As(err, &sizeErr))
+	require.Equal(t, data.SUBMIT_SM, sizeErr.Header.CommandID)
+	require.Equal(t, 200, sizeErr.Max)
+
+	// larger PDU from SMSC is refused, session goes on
+	deliver := pdu.NewDeliverSM().(*pdu.DeliverSM)
+	deliver.RegisterOptionalParam(pdu.Field{Tag: pdu.TagMessagePayload, Data: []byte(strings.Repeat("x", 200))})
+	require.Nil(t, smsc.Sessions()[0].Submit(deliver))
+	select {
+	case err := <-receivingErrs:
+		require.True(t, errors.As(err, &sizeErr))
+		require.Equal(t, deliver.GetSequenceNumber(), sizeErr.Header.SequenceNumber)
+	case <-time.After(time.Second):
+		t.Fatal("oversized deliver_sm is not reported")
+	}
+	smsc.ExpectReceived(t, data.GENERIC_NACK, 1)
+	nack := smsc.Received()[len(smsc.Received())-1]
+	require.Equal(t, data.ESME_RINVCMDLEN, nack.GetHeader().CommandStatus)
+
+	require.Nil(t, s.Transceiver().Submit(newTextSubmitSM("2", "short")))
+	smsc.ExpectReceived(t, data.SUBMIT_SM, 2)
+}
+
+func TestSessionMaxCommandLengthManualResponse(t *testing.T) {
+	smsc := smsctest.NewPipeServer(nil)
+	defer smsc.Close()
+
+	received := make(chan pdu.PDU, 1)
+	s, err := gosmpp.NewSession(gosmpp.TRXConnector(smsc.Dialer(), gosmpp.Auth{SMSC: "pipe", SystemID: "esme"}),
+		gosmpp.Settings{
+			ReadTimeout:      2 * time.Second,
+			MaxCommandLength: 200,
+			// request left unanswered, as in example of manual responses: nil response is not checked
+			OnAllPDU: func(p pdu.PDU) (pdu.PDU, bool) {
+				if _, ok := p.(*pdu.DeliverSM); ok {
+					received <- p
+				}
+				return nil, false
+			},
+		}, -1)
+	require.Nil(t, err)
+	defer func() {
+		_ = s.Close()
+	}()
+
+	require.Nil(t, smsc.Sessions()[0].Submit(pdu.NewDeliverSM()))
+	select {
+	case <-received:
+	case <-time.After(time.Second):
+		t.Fatal("deliver_sm is not received")
+	}
+
+	require.Nil(t, s.Transceiver().Submit(newTextSubmitSM("1", "short")))
+	smsc.ExpectReceived(t, data.SUBMIT_SM, 1)
+}
@@ -0,0 +1,366 @@
+package gosmpp
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/linxGnu/gosmpp/clock"
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+)
+
+// SubmitError indicates part of message was rejected by SMSC with error status.
+type SubmitError struct {
+	// Part is index of rejected part.
+	Part int
+
+	// Status is command status of the response.
+	Status data.CommandStatusType
+}
+
+func (err *SubmitError) Error() string {
+	return fmt.Sprintf("gosmpp: part %d is rejected (%s): %s", err.Part+1, err.Status, err.Status.Desc())
+}
+
+// MessageHandle is message sent by Messenger.
+type MessageHandle struct {
+	// ID identifies the message, generated by Messenger.
+	ID string
+
+	// Parts are submit_sm of the message parts, in order.
+	Parts []*pdu.SubmitSM
+
+	// MessageIDs are message_id assigned by SMSC to the parts, in order. Empty for not accepted parts.
+	// For message queued by SendAt, they are set once Wait returns.
+	MessageIDs []string
+
+	// Correlation is attached to the parts, taken from context of sending, see ContextWithCorrelation.
+	Correlation Correlation
+
+	messenger *Messenger
+	queued    *queuedMessage
+}
+
+// Messenger sends text messages over Session, covering the common case with one call:
+// addresses are normalized, encoding is picked and long text is split into concatenated parts.
+type Messenger struct {
+	session *Session
+
+	serviceType        string
+	registeredDelivery byte
+	protocolID         byte
+	concatenation      Concatenation
+	scheduled          bool
+	normalizer         AddressNormalizer
+	tracker            *DeliveryTracker
+	templates          *Templates
+	pricing            PricingHook
+	locales            LocaleResolver
+	lists              *RecipientLists
+	clock              clock.Clock
+}
+
+// MessengerOption configures Messenger.
+type MessengerOption func(*Messenger)
+
+// WithServiceType sets service_type of messages sent by Messenger.
+func WithServiceType(serviceType string) MessengerOption {
+	return func(m *Messenger) {
+		m.serviceType = serviceType
+	}
+}
+
+// WithRegisteredDelivery sets registered_delivery of messages sent by Messenger,
+// e.g. data.SM_SMSC_RECEIPT_REQUESTED to request delivery receipts.
+func WithRegisteredDelivery(registeredDelivery byte) MessengerOption {
+	return func(m *Messenger) {
+		m.registeredDelivery = registeredDelivery
+	}
+}
+
+// WithProtocolID sets protocol_id of messages sent by Messenger, e.g. 0x41 to replace message of type 1.
+func WithProtocolID(protocolID byte) MessengerOption {
+	return func(m *Messenger) {
+		m.protocolID = protocolID
+	}
+}
+
+// Concatenation tells how parts of long message are linked together, see WithConcatenation.
+type Concatenation byte
+
+const (
+	// ConcatUDH links parts by concatenation information element of user data header, taking 6 octets of
+	// every part.
+	ConcatUDH Concatenation = iota
+
+	// ConcatSAR links parts by sar_msg_ref_num, sar_total_segments and sar_segment_seqnum optional parameters,
+	// for SMSC expecting them instead of UDH.
+	ConcatSAR
+)
+
+// WithConcatenation sets how parts of long text messages sent by Messenger are linked, ConcatUDH by default.
+func WithConcatenation(concatenation Concatenation) MessengerOption {
+	return func(m *Messenger) {
+		m.concatenation = concatenation
+	}
+}
+
+// WithAddressNormalizer sets normalizer of source and destination addresses of messages sent by Messenger,
+// DefaultAddressNormalizer by default. Messengers of sessions to different SMSCs may use different normalizers,
+// e.g. E164Normalizer with default countries of the route.
+func WithAddressNormalizer(normalizer AddressNormalizer) MessengerOption {
+	return func(m *Messenger) {
+		m.normalizer = normalizer
+	}
+}
+
+// WithDeliveryTracker tracks messages sent by Messenger with tracker, once all parts are accepted.
+// Delivery receipts should be requested, see WithRegisteredDelivery.
+func WithDeliveryTracker(tracker *DeliveryTracker) MessengerOption {
+	return func(m *Messenger) {
+		m.tracker = tracker
+	}
+}
+
+// NewMessenger returns Messenger sending over session.
+func NewMessenger(session *Session, opts ...MessengerOption) *Messenger {
+	m := &Messenger{
+		session:     session,
+		serviceType: data.DFLT_SRVTYPE,
+		normalizer:  DefaultAddressNormalizer,
+		clock:       clock.OrReal(session.settings.Clock),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// SendText sends text from source to destination address, see WithAddressNormalizer.
+//
+// Text is encoded in GSM 7-bit if possible, UCS2 otherwise, and split into concatenated parts if it does not
+// fit into single message. SendText submits all parts and waits for their responses until ctx is done.
+//
+// Returned handle is non-nil once parts are built, also with error: e.g. *SubmitError if a part is rejected.
+// It is nil if message is vetoed by pricing hook, see WithPricingHook.
+func (m *Messenger) SendText(ctx context.Context, from, to, text string) (*MessageHandle, error) {
+	return m.SendTextWithEncoding(ctx, from, to, text, textEncoding(text))
+}
+
+// SendTextWithEncoding sends text like SendText, encoded by enc, e.g. data.LATIN1 for SMSC not supporting
+// GSM 7-bit. Encodings implementing data.Splitter split long text into parts linked as set by WithConcatenation,
+// others must fit into single message.
+func (m *Messenger) SendTextWithEncoding(ctx context.Context, from, to, text string, enc data.Encoding) (*MessageHandle, error) {
+	return m.SubmitLongMessage(ctx, from, to, text, enc, m.concatenation)
+}
+
+// SubmitLongMessage sends text like SendTextWithEncoding, linking its parts by concatenation given per message
+// instead of the one set by WithConcatenation, e.g. ConcatSAR for routes whose SMSC drops UDH of some
+// destinations while the rest of messages go with UDH.
+func (m *Messenger) SubmitLongMessage(ctx context.Context, from, to, text string, enc data.Encoding, concatenation Concatenation) (*MessageHandle, error) {
+	submit, err := m.newSubmit(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = submit.Message.SetLongMessageWithEnc(text, enc); err != nil {
+		return nil, err
+	}
+
+	var parts []*pdu.SubmitSM
+	if concatenation == ConcatSAR {
+		parts, err = submit.SplitSAR()
+	} else {
+		parts, err = submit.Split()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return m.send(ctx, parts)
+}
+
+// SendBinary sends payload to application port of destination, e.g. to a handset application.
+//
+// Payload is sent in 8-bit binary data coding with application port addressing UDH, split into
+// concatenated parts if it does not fit into single message.
+func (m *Messenger) SendBinary(ctx context.Context, from, to string, destPort, srcPort uint16, payload []byte) (*MessageHandle, error) {
+	return m.sendBinary(ctx, from, to, pdu.UDH{pdu.NewIEApplicationPort(destPort, srcPort)}, payload)
+}
+
+// sendBinary sends payload in 8-bit binary parts with user data header udh.
+func (m *Messenger) sendBinary(ctx context.Context, from, to string, udh pdu.UDH, payload []byte) (*MessageHandle, error) {
+	submit, err := m.newSubmit(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	messages, err := pdu.NewBinaryMessages(payload, udh)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := make([]*pdu.SubmitSM, len(messages))
+	for i, msg := range messages {
+		part := *submit
+		part.EsmClass |= data.SM_UDH_GSM
+		part.Message = *msg
+		parts[i] = &part
+	}
+	return m.send(ctx, parts)
+}
+
+// newSubmit returns submit_sm from source to destination address with settings of Messenger.
+func (m *Messenger) newSubmit(from, to string) (*pdu.SubmitSM, error) {
+	submit, err := newSubmit(m.normalizer, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	submit.ServiceType = m.serviceType
+	submit.RegisteredDelivery = m.registeredDelivery
+	submit.ProtocolID = m.protocolID
+	return submit, nil
+}
+
+// newSubmit returns submit_sm from source to destination address, normalized by normalizer.
+// Alphanumeric source is validated, see ValidateSenderID.
+func newSubmit(normalizer AddressNormalizer, from, to string) (*pdu.SubmitSM, error) {
+	source, err := normalizeSource(normalizer, from)
+	if err != nil {
+		return nil, err
+	}
+
+	dest, err := normalizer.Normalize(to)
+	if err != nil {
+		return nil, err
+	}
+
+	submit := pdu.NewSubmitSM().(*pdu.SubmitSM)
+	submit.SourceAddr = source
+	submit.DestAddr = dest
+	return submit, nil
+}
+
+// normalizeSource returns source address normalized by normalizer, validating alphanumeric one.
+func normalizeSource(normalizer AddressNormalizer, from string) (pdu.Address, error) {
+	source, err := normalizer.Normalize(from)
+	if err != nil {
+		return source, err
+	}
+	if source.Ton() == data.GSM_TON_ALPHANUMERIC {
+		return NewSenderID(source.Address())
+	}
+	return source, nil
+}
+
+// send submits parts of message, tracking them if all are accepted.
+func (m *Messenger) send(ctx context.Context, parts []*pdu.SubmitSM) (*MessageHandle, error) {
+	if err := m.price(ctx, parts); err != nil {
+		return nil, err
+	}
+
+	h := &MessageHandle{ID: newMessageID(), Parts: parts, Correlation: CorrelationFromContext(ctx), messenger: m}
+	return h, m.deliver(ctx, h)
+}
+
+// deliver submits parts of h, tracking them if all are accepted.
+func (m *Messenger) deliver(ctx context.Context, h *MessageHandle) error {
+	if err := m.submit(ctx, h); err != nil {
+		return err
+	}
+
+	if m.tracker != nil {
+		return m.tracker.Track(ctx, h)
+	}
+	return nil
+}
+
+// submit submits parts of h and waits for their responses.
+func (m *Messenger) submit(ctx context.Context, h *MessageHandle) error {
+	calls := make([]*Call, len(h.Parts))
+	for i, part := range h.Parts {
+		// parts are split from the same PDU
+		part.AssignSequenceNumber()
+		part.OptionalParameters = cloneOptionalParameters(part.OptionalParameters)
+
+		calls[i] = m.session.SubmitAsync(part, WithCorrelation(h.Correlation), withContext(ctx))
+	}
+
+	h.MessageIDs = make([]string, len(h.Parts))
+	for i, c := range calls {
+		resp, err := c.Wait(ctx)
+		if err != nil {
+			for _, pending := range calls[i:] {
+				pending.Cancel()
+			}
+			return err
+		}
+
+		r, ok := resp.(*pdu.SubmitSMResp)
+		if !ok || r.CommandStatus != data.ESME_ROK {
+			return &SubmitError{Part: i, Status: resp.GetHeader().CommandStatus}
+		}
+		h.MessageIDs[i] = r.MessageID
+	}
+	return nil
+}
+
+// NormalizeAddress parses address given in common formats:
+//
+//   - "+44 7700 900123" and "0044 7700 900123" are international numbers (TON international, NPI ISDN);
+//   - "07700 900123" or "12345" are numbers of unknown type (TON unknown, NPI ISDN);
+//   - any other text, e.g. "MyBank", is alphanumeric (TON alphanumeric, NPI unknown).
+//
+// Spaces, dashes, dots and parentheses are removed from numbers.
+func NormalizeAddress(addr string) (a pdu.Address, err error) {
+	addr = strings.TrimSpace(addr)
+	if addr == "" {
+		return a, fmt.Errorf("gosmpp: empty address")
+	}
+
+	number := cleanNumber(addr)
+	if international, ok := internationalNumber(number); ok {
+		return pdu.NewAddressWithTonNpiAddr(data.GSM_TON_INTERNATIONAL, data.GSM_NPI_ISDN, international)
+	}
+	if isDigits(number) {
+		return pdu.NewAddressWithTonNpiAddr(data.GSM_TON_UNKNOWN, data.GSM_NPI_ISDN, number)
+	}
+	return pdu.NewAddressWithTonNpiAddr(data.GSM_TON_ALPHANUMERIC, data.GSM_NPI_UNKNOWN, addr)
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// textEncoding returns GSM 7-bit if text could be encoded in it, UCS2 otherwise.
+func textEncoding(text string) data.Encoding {
+	if len(data.ValidateGSM7String(text)) == 0 {
+		return data.GSM7BIT
+	}
+	return data.UCS2
+}
+
+func cloneOptionalParameters(params map[pdu.Tag]pdu.Field) map[pdu.Tag]pdu.Field {
+	c := make(map[pdu.Tag]pdu.Field, len(params))
+	for tag, field := range params {
+		c[tag] = field
+	}
+	return c
+}
+
+func newMessageID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
@@ -0,0 +1,95 @@
+package gosmpp_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp"
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+	"github.com/linxGnu/gosmpp/server/smsctest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessengerConcatSAR(t *testing.T) {
+	smsc := smsctest.NewPipeServer(nil)
+	defer smsc.Close()
+
+	s, err := gosmpp.NewSession(gosmpp.TRXConnector(smsc.Dialer(), gosmpp.Auth{SMSC: "pipe", SystemID: "esme"}),
+		gosmpp.Settings{ReadTimeout: 2 * time.Second}, -1)
+	require.Nil(t, err)
+	defer func() {
+		_ = s.Close()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	m := gosmpp.NewMessenger(s, gosmpp.WithConcatenation(gosmpp.ConcatSAR))
+	text := strings.Repeat("Xin chào ", 20)
+	h, err := m.SendTextWithEncoding(ctx, "MyShop", "+447700900123", text, data.UCS2)
+	require.Nil(t, err)
+	require.Len(t, h.Parts, 3)
+
+	submitted := smsc.Submitted()
+	require.Len(t, submitted, 3)
+	var received string
+	for i, p := range submitted {
+		submit := p.(*pdu.SubmitSM)
+		require.Zero(t, submit.EsmClass&data.SM_UDH_GSM)
+		require.Equal(t, []byte{3}, submit.OptionalParameters[pdu.TagSarTotalSegments].Data)
+		require.Equal(t, []byte{byte(i + 1)}, submit.OptionalParameters[pdu.TagSarSegmentSeqnum].Data)
+		require.Equal(t, submitted[0].(*pdu.SubmitSM).OptionalParameters[pdu.TagSarMsgRefNum],
+			submit.OptionalParameters[pdu.TagSarMsgRefNum])
+
+		message, err := submit.Message.GetMessageWithEncoding(data.UCS2)
+		require.Nil(t, err)
+		received += message
+	}
+	require.Equal(t, text, received)
+}
+
+func TestMessengerSubmitLongMessage(t *testing.T) {
+	smsc := smsctest.NewPipeServer(nil)
+	defer smsc.Close()
+
+	s, err := gosmpp.NewSession(gosmpp.TRXConnector(smsc.Dialer(), gosmpp.Auth{SMSC: "pipe", SystemID: "esme"}),
+		gosmpp.Settings{ReadTimeout: 2 * time.Second}, -1)
+	require.Nil(t, err)
+	defer func() {
+		_ = s.Close()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	// concatenation of the message overrides the one of messenger
+	m := gosmpp.NewMessenger(s)
+	text := strings.Repeat("Hello world ", 20)
+	h, err := m.SubmitLongMessage(ctx, "MyShop", "+447700900123", text, data.GSM7BIT, gosmpp.ConcatSAR)
+	require.Nil(t, err)
+	require.Len(t, h.Parts, 2)
+	for i, p := range smsc.Submitted() {
+		submit := p.(*pdu.SubmitSM)
+		require.Zero(t, submit.EsmClass&data.SM_UDH_GSM)
+		require.Equal(t, []byte{2}, submit.OptionalParameters[pdu.TagSarTotalSegments].Data)
+		require.Equal(t, []byte{byte(i + 1)}, submit.OptionalParameters[pdu.TagSarSegmentSeqnum].Data)
+	}
+
+	m = gosmpp.NewMessenger(s, gosmpp.WithConcatenation(gosmpp.ConcatSAR))
+	_, err = m.SubmitLongMessage(ctx, "MyShop", "+447700900123", text, data.GSM7BIT, gosmpp.ConcatUDH)
+	require.Nil(t, err)
+	submitted := smsc.Submitted()
+	require.Len(t, submitted, 4)
+	for i, p := range submitted[2:] {
+		submit := p.(*pdu.SubmitSM)
+		require.NotZero(t, submit.EsmClass&data.SM_UDH_GSM)
+		require.NotContains(t, submit.OptionalParameters, pdu.TagSarTotalSegments)
+		total, seq, _, found := submit.Message.UDH().GetConcatInfo()
+		require.True(t, found)
+		require.Equal(t, [2]byte{2, byte(i + 1)}, [2]byte{total, seq})
+	}
+}
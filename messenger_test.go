@@ -0,0 +1,140 @@
+package gosmpp
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp/data"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeAddress(t *testing.T) {
+	for _, tc := range []struct {
+		addr     string
+		ton, npi byte
+		number   string
+	}{
+		{"+44 7700 900-123", data.GSM_TON_INTERNATIONAL, data.GSM_NPI_ISDN, "447700900123"},
+		{"0044 (7700) 900123", data.GSM_TON_INTERNATIONAL, data.GSM_NPI_ISDN, "447700900123"},
+		{"07700 900123", data.GSM_TON_UNKNOWN, data.GSM_NPI_ISDN, "07700900123"},
+		{" 12345 ", data.GSM_TON_UNKNOWN, data.GSM_NPI_ISDN, "12345"},
+		{"My Bank", data.GSM_TON_ALPHANUMERIC, data.GSM_NPI_UNKNOWN, "My Bank"},
+		{"+44ab", data.GSM_TON_ALPHANUMERIC, data.GSM_NPI_UNKNOWN, "+44ab"},
+	} {
+		a, err := NormalizeAddress(tc.addr)
+		require.Nil(t, err, tc.addr)
+		require.Equal(t, tc.ton, a.Ton(), tc.addr)
+		require.Equal(t, tc.npi, a.Npi(), tc.addr)
+		require.Equal(t, tc.number, a.Address(), tc.addr)
+	}
+
+	_, err := NormalizeAddress("  ")
+	require.NotNil(t, err)
+	_, err = NormalizeAddress(strings.Repeat("1", 30))
+	require.NotNil(t, err)
+}
+
+func TestTextEncoding(t *testing.T) {
+	require.Equal(t, data.GSM7BIT, textEncoding("Hello {world} €"))
+	require.Equal(t, data.UCS2, textEncoding(mess))
+}
+
+func TestMessengerSendText(t *testing.T) {
+	auth := nextAuth()
+	s, err := NewSession(TRXConnector(NonTLSDialer, auth), Settings{ReadTimeout: 2 * time.Second}, -1)
+	require.Nil(t, err)
+	defer func() {
+		_ = s.Close()
+	}()
+
+	tracker := NewDeliveryTracker(nil)
+	m := NewMessenger(s, WithRegisteredDelivery(data.SM_SMSC_RECEIPT_REQUESTED), WithDeliveryTracker(tracker))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	h, err := m.SendText(ctx, "MyBank", "+84 901 234 567", "short")
+	require.Nil(t, err)
+	require.Len(t, h.Parts, 1)
+	require.Len(t, h.MessageIDs, 1)
+	require.NotEmpty(t, h.MessageIDs[0])
+	require.Len(t, h.ID, 32)
+	require.Equal(t, data.GSM_TON_ALPHANUMERIC, h.Parts[0].SourceAddr.Ton())
+	require.Equal(t, "84901234567", h.Parts[0].DestAddr.Address())
+	require.Equal(t, data.SM_SMSC_RECEIPT_REQUESTED, h.Parts[0].RegisteredDelivery)
+
+	d, ok, err := tracker.Status(ctx, h.ID)
+	require.Nil(t, err)
+	require.True(t, ok)
+	require.Equal(t, h.MessageIDs, d.MessageIDs)
+
+	h, err = m.SendText(ctx, "MyBank", "84901234567", strings.Repeat(mess, 5))
+	require.Nil(t, err)
+	require.Greater(t, len(h.Parts), 1)
+	seqs := make(map[int32]bool)
+	for i, part := range h.Parts {
+		require.Equal(t, data.UCS2, part.Message.Encoding())
+		require.NotEmpty(t, h.MessageIDs[i])
+		seqs[part.SequenceNumber] = true
+	}
+	require.Len(t, seqs, len(h.Parts))
+
+	_, err = m.SendText(ctx, "", "84901234567", "text")
+	require.NotNil(t, err)
+}
+
+func TestSubmitError(t *testing.T) {
+	err := &SubmitError{Part: 1, Status: data.ESME_RTHROTTLED}
+	require.Contains(t, err.Error(), "part 2 is rejected (ESME_RTHROTTLED)")
+}
+
+func TestMessengerSendBinary(t *testing.T) {
+	auth := nextAuth()
+	s, err := NewSession(TXConnector(NonTLSDialer, auth), Settings{ReadTimeout: 2 * time.Second}, -1)
+	require.Nil(t, err)
+	defer func() {
+		_ = s.Close()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	m := NewMessenger(s)
+	payload := make([]byte, 500)
+	for i := range payload {
+		payload[i] = byte(i)
+	}
+
+	h, err := m.SendBinary(ctx, "MyApp", "84901234567", 5000, 5001, payload)
+	require.Nil(t, err)
+	require.Len(t, h.Parts, 4)
+
+	var received []byte
+	for i, part := range h.Parts {
+		require.NotEmpty(t, h.MessageIDs[i])
+		require.Equal(t, data.BINARY8BIT2, part.Message.Encoding())
+		require.NotZero(t, part.EsmClass&data.SM_UDH_GSM)
+
+		destPort, srcPort, found := part.Message.UDH().GetApplicationPort()
+		require.True(t, found)
+		require.Equal(t, uint16(5000), destPort)
+		require.Equal(t, uint16(5001), srcPort)
+
+		total, num, _, found := part.Message.UDH().GetConcatInfo()
+		require.True(t, found)
+		require.Equal(t, byte(4), total)
+		require.Equal(t, byte(i+1), num)
+
+		b, _ := part.Message.GetMessageData()
+		received = append(received, b...)
+	}
+	require.Equal(t, payload, received)
+
+	h, err = m.SendBinary(ctx, "MyApp", "84901234567", 5000, 0, []byte{1, 2, 3})
+	require.Nil(t, err)
+	require.Len(t, h.Parts, 1)
+	_, _, _, found := h.Parts[0].Message.UDH().GetConcatInfo()
+	require.False(t, found)
+}
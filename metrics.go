@@ -0,0 +1,186 @@
+package gosmpp
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/linxGnu/gosmpp/clock"
+	"github.com/linxGnu/gosmpp/internal/stats"
+	"github.com/linxGnu/gosmpp/pdu"
+)
+
+const (
+	// MaxMetricsWindow is the longest sliding window supported by Session.Metrics.
+	MaxMetricsWindow = time.Duration(meterBuckets) * time.Second
+
+	meterBuckets   = 60
+	latencySamples = 1024
+)
+
+// Metrics is throughput and latency of a session over a sliding window.
+type Metrics struct {
+	// Window is the sliding window the metrics are computed over.
+	Window time.Duration
+
+	// Labels of the session.
+	Labels Labels
+
+	// SubmitPerSecond is number of submit_sm/submit_multi/data_sm sent to SMSC per second.
+	SubmitPerSecond float64
+
+	// DeliverPerSecond is number of deliver_sm received from SMSC per second.
+	DeliverPerSecond float64
+
+	// BytesIn/BytesOut are total PDU bytes received/sent within the window.
+	BytesIn  int64
+	BytesOut int64
+
+	// BytesInPerSecond/BytesOutPerSecond are averaged over the window.
+	BytesInPerSecond  float64
+	BytesOutPerSecond float64
+
+	// Response latency (request sent -> response received) percentiles, enquire_link excluded.
+	LatencyP50 time.Duration
+	LatencyP90 time.Duration
+	LatencyP99 time.Duration
+	LatencyMax time.Duration
+
+	// LatencyMaxCorrelation is correlation of the slowest response, exemplar of LatencyMax.
+	LatencyMaxCorrelation Correlation
+}
+
+type meterBucket struct {
+	sec      int64
+	submits  int64
+	delivers int64
+	bytesIn  int64
+	bytesOut int64
+}
+
+type latencySample struct {
+	at          int64 // unix nano
+	d           time.Duration
+	correlation Correlation
+}
+
+// meter records session traffic in per-second buckets.
+type meter struct {
+	mu        sync.Mutex
+	buckets   [meterBuckets]meterBucket
+	latencies [latencySamples]latencySample
+	latIdx    int
+}
+
+func (m *meter) bucket(now time.Time) *meterBucket {
+	sec := now.Unix()
+	b := &m.buckets[sec%meterBuckets]
+	if b.sec != sec {
+		*b = meterBucket{sec: sec}
+	}
+	return b
+}
+
+func (m *meter) onWritten(now time.Time, p pdu.PDU) {
+	if m == nil {
+		return
+	}
+
+	m.mu.Lock()
+	b := m.bucket(now)
+	b.bytesOut += int64(p.GetHeader().CommandLength)
+	switch p.(type) {
+	case *pdu.SubmitSM, *pdu.SubmitMulti, *pdu.DataSM:
+		b.submits++
+	}
+	m.mu.Unlock()
+}
+
+func (m *meter) onReceived(now time.Time, p pdu.PDU) {
+	if m == nil {
+		return
+	}
+
+	m.mu.Lock()
+	b := m.bucket(now)
+	b.bytesIn += int64(p.GetHeader().CommandLength)
+	if _, ok := p.(*pdu.DeliverSM); ok {
+		b.delivers++
+	}
+	m.mu.Unlock()
+}
+
+func (m *meter) onLatency(now time.Time, d time.Duration, correlation Correlation) {
+	if m == nil {
+		return
+	}
+
+	m.mu.Lock()
+	m.latencies[m.latIdx] = latencySample{at: now.UnixNano(), d: d, correlation: correlation}
+	m.latIdx = (m.latIdx + 1) % latencySamples
+	m.mu.Unlock()
+}
+
+func (m *meter) snapshot(now time.Time, window time.Duration) (r Metrics) {
+	if window <= 0 || window > MaxMetricsWindow {
+		window = MaxMetricsWindow
+	}
+	if window < time.Second {
+		window = time.Second
+	}
+	r.Window = window
+
+	windowSecs := int64(window / time.Second)
+	nowSec := now.Unix()
+	from := now.Add(-window).UnixNano()
+
+	var (
+		submits, delivers int64
+		latencies         []time.Duration
+		slowest           latencySample
+	)
+
+	m.mu.Lock()
+	for i := range m.buckets {
+		b := &m.buckets[i]
+		if b.sec > nowSec-windowSecs && b.sec <= nowSec {
+			submits += b.submits
+			delivers += b.delivers
+			r.BytesIn += b.bytesIn
+			r.BytesOut += b.bytesOut
+		}
+	}
+	for i := range m.latencies {
+		if s := m.latencies[i]; s.at > from {
+			latencies = append(latencies, s.d)
+			if s.d > slowest.d {
+				slowest = s
+			}
+		}
+	}
+	m.mu.Unlock()
+
+	secs := float64(windowSecs)
+	r.SubmitPerSecond = float64(submits) / secs
+	r.DeliverPerSecond = float64(delivers) / secs
+	r.BytesInPerSecond = float64(r.BytesIn) / secs
+	r.BytesOutPerSecond = float64(r.BytesOut) / secs
+
+	if n := len(latencies); n > 0 {
+		sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+		r.LatencyP50 = latencies[stats.PercentileIndex(n, 50)]
+		r.LatencyP90 = latencies[stats.PercentileIndex(n, 90)]
+		r.LatencyP99 = latencies[stats.PercentileIndex(n, 99)]
+		r.LatencyMax = latencies[n-1]
+		r.LatencyMaxCorrelation = slowest.correlation
+	}
+	return
+}
+
+// Metrics returns throughput and latency over the latest sliding window,
+// capped at MaxMetricsWindow. Metrics are kept through session lifetime, across rebinds.
+func (s *Session) Metrics(window time.Duration) Metrics {
+	m := s.meter.snapshot(clock.OrReal(s.settings.Clock).Now(), window)
+	m.Labels = s.Labels()
+	return m
+}
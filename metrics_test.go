@@ -0,0 +1,77 @@
+package gosmpp
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp/pdu"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMeter(t *testing.T) {
+	var m meter
+	now := time.Unix(1700000000, 0)
+
+	submit := pdu.NewSubmitSM()
+	submit.Marshal(pdu.NewBuffer(nil)) // assign command length
+	length := int64(submit.GetHeader().CommandLength)
+
+	for i := 0; i < 10; i++ {
+		m.onWritten(now.Add(-time.Duration(i)*time.Second), submit)
+	}
+	m.onReceived(now, pdu.NewDeliverSM())
+	for i := 1; i <= 100; i++ {
+		m.onLatency(now, time.Duration(i)*time.Millisecond, Correlation{ID: strconv.Itoa(i)})
+	}
+
+	r := m.snapshot(now, 10*time.Second)
+	require.Equal(t, 10*time.Second, r.Window)
+	require.Equal(t, 1.0, r.SubmitPerSecond)
+	require.Equal(t, 0.1, r.DeliverPerSecond)
+	require.Equal(t, 10*length, r.BytesOut)
+	require.Equal(t, 50*time.Millisecond, r.LatencyP50)
+	require.Equal(t, 90*time.Millisecond, r.LatencyP90)
+	require.Equal(t, 99*time.Millisecond, r.LatencyP99)
+	require.Equal(t, 100*time.Millisecond, r.LatencyMax)
+	require.Equal(t, "100", r.LatencyMaxCorrelation.ID)
+
+	// older buckets slide out of shorter window
+	r = m.snapshot(now, 5*time.Second)
+	require.Equal(t, 5*length, r.BytesOut)
+
+	// everything expired
+	r = m.snapshot(now.Add(2*MaxMetricsWindow), 0)
+	require.Equal(t, MaxMetricsWindow, r.Window)
+	require.Zero(t, r.SubmitPerSecond)
+	require.Zero(t, r.LatencyMax)
+
+	// nil meter is no-op
+	var nilMeter *meter
+	nilMeter.onWritten(now, submit)
+	nilMeter.onReceived(now, submit)
+	nilMeter.onLatency(now, time.Second, Correlation{})
+}
+
+func TestSessionMetrics(t *testing.T) {
+	auth := nextAuth()
+	s, err := NewSession(
+		TRXConnector(NonTLSDialer, auth),
+		Settings{
+			ReadTimeout: 2 * time.Second,
+		}, -1)
+	require.Nil(t, err)
+	defer func() {
+		_ = s.Close()
+	}()
+
+	for i := 0; i < 5; i++ {
+		require.Nil(t, s.Submit(newSubmitSM(auth.SystemID)))
+	}
+
+	require.Eventually(t, func() bool {
+		m := s.Metrics(10 * time.Second)
+		return m.SubmitPerSecond == 0.5 && m.LatencyMax > 0 && m.BytesIn > 0 && m.BytesOut > 0
+	}, 2*time.Second, 50*time.Millisecond)
+}
@@ -0,0 +1,161 @@
+package gosmpp
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+)
+
+// AddressNormalizer parses address given in human format into SMPP address with TON/NPI.
+type AddressNormalizer interface {
+	Normalize(addr string) (pdu.Address, error)
+}
+
+// AddressNormalizerFunc is function implementing AddressNormalizer.
+type AddressNormalizerFunc func(addr string) (pdu.Address, error)
+
+// Normalize implements AddressNormalizer.
+func (f AddressNormalizerFunc) Normalize(addr string) (pdu.Address, error) {
+	return f(addr)
+}
+
+// DefaultAddressNormalizer is NormalizeAddress, used by Messenger unless WithAddressNormalizer is given.
+var DefaultAddressNormalizer AddressNormalizer = AddressNormalizerFunc(NormalizeAddress)
+
+// DefaultMaxShortCodeLength is default maximal length of short codes of E164Normalizer.
+const DefaultMaxShortCodeLength = 6
+
+// Country is numbering plan of a country, used to convert its national numbers to E.164.
+type Country struct {
+	// CallingCode is country calling code, e.g. "44".
+	CallingCode string
+
+	// TrunkPrefix is prefix of national numbers dialed within the country, e.g. "0". Optional.
+	TrunkPrefix string
+
+	// Lengths are lengths of national significant numbers, without trunk prefix, e.g. 10 for "7700900123".
+	// Numbers of any length are accepted if empty.
+	Lengths []int
+}
+
+// withTrunkPrefix returns national significant number of number dialed with trunk prefix.
+func (c Country) withTrunkPrefix(number string) (string, bool) {
+	if c.TrunkPrefix == "" || !strings.HasPrefix(number, c.TrunkPrefix) {
+		return "", false
+	}
+	nsn := number[len(c.TrunkPrefix):]
+	return nsn, c.validLength(nsn)
+}
+
+// withCallingCode returns national significant number of number with calling code, but without "+".
+// Lengths must be known to tell it from national number.
+func (c Country) withCallingCode(number string) (string, bool) {
+	if len(c.Lengths) == 0 || !strings.HasPrefix(number, c.CallingCode) {
+		return "", false
+	}
+	nsn := number[len(c.CallingCode):]
+	return nsn, c.validLength(nsn)
+}
+
+// withoutPrefix returns number if it is national significant number. Lengths must be known.
+func (c Country) withoutPrefix(number string) (string, bool) {
+	return number, len(c.Lengths) > 0 && c.validLength(number)
+}
+
+func (c Country) validLength(nsn string) bool {
+	if len(c.Lengths) == 0 {
+		return nsn != ""
+	}
+	for _, l := range c.Lengths {
+		if len(nsn) == l {
+			return true
+		}
+	}
+	return false
+}
+
+// E164Normalizer converts numbers in local formats of default countries to E.164, picking TON/NPI by kind of address:
+//
+//   - "+44 7700 900123" and "0044 7700 900123" are international numbers (TON international, NPI ISDN);
+//   - national numbers of a default country are converted to international ones: e.g. for
+//     Country{CallingCode: "44", TrunkPrefix: "0", Lengths: []int{10}}, numbers dialed with trunk prefix
+//     "07700 900123", with calling code "447700900123" or without prefix "7700900123";
+//   - numbers with trunk prefix of a default country, but of other length, are national (TON national, NPI ISDN);
+//   - numbers not longer than MaxShortCodeLength, e.g. "8888", are short codes (TON network specific, NPI unknown);
+//   - other numbers are of unknown type (TON unknown, NPI ISDN);
+//   - any other text, e.g. "MyBank", is alphanumeric (TON alphanumeric, NPI unknown).
+//
+// National formats are tried in the order above, countries in order for each.
+// Spaces, dashes, dots and parentheses are removed from numbers.
+type E164Normalizer struct {
+	// Countries are default countries of national numbers.
+	Countries []Country
+
+	// MaxShortCodeLength is maximal length of short codes, DefaultMaxShortCodeLength if zero.
+	MaxShortCodeLength int
+}
+
+// Normalize implements AddressNormalizer.
+func (n E164Normalizer) Normalize(addr string) (a pdu.Address, err error) {
+	addr = strings.TrimSpace(addr)
+	if addr == "" {
+		return a, fmt.Errorf("gosmpp: empty address")
+	}
+
+	number := cleanNumber(addr)
+	if international, ok := internationalNumber(number); ok {
+		return pdu.NewAddressWithTonNpiAddr(data.GSM_TON_INTERNATIONAL, data.GSM_NPI_ISDN, international)
+	}
+	if !isDigits(number) {
+		return pdu.NewAddressWithTonNpiAddr(data.GSM_TON_ALPHANUMERIC, data.GSM_NPI_UNKNOWN, addr)
+	}
+
+	maxShortCode := n.MaxShortCodeLength
+	if maxShortCode == 0 {
+		maxShortCode = DefaultMaxShortCodeLength
+	}
+	if len(number) <= maxShortCode {
+		return pdu.NewAddressWithTonNpiAddr(data.GSM_TON_NETWORK, data.GSM_NPI_UNKNOWN, number)
+	}
+
+	// the most specific format first, e.g. trunk prefix of one country over length of another
+	for _, national := range []func(Country, string) (string, bool){
+		Country.withTrunkPrefix, Country.withCallingCode, Country.withoutPrefix,
+	} {
+		for _, c := range n.Countries {
+			if nsn, ok := national(c, number); ok {
+				return pdu.NewAddressWithTonNpiAddr(data.GSM_TON_INTERNATIONAL, data.GSM_NPI_ISDN, c.CallingCode+nsn)
+			}
+		}
+	}
+	for _, c := range n.Countries {
+		if c.TrunkPrefix != "" && strings.HasPrefix(number, c.TrunkPrefix) {
+			return pdu.NewAddressWithTonNpiAddr(data.GSM_TON_NATIONAL, data.GSM_NPI_ISDN, number)
+		}
+	}
+	return pdu.NewAddressWithTonNpiAddr(data.GSM_TON_UNKNOWN, data.GSM_NPI_ISDN, number)
+}
+
+// cleanNumber removes spaces, dashes, dots and parentheses from number.
+func cleanNumber(addr string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '.', '(', ')':
+			return -1
+		}
+		return r
+	}, addr)
+}
+
+// internationalNumber returns digits of number given with "+" or "00" prefix.
+func internationalNumber(number string) (string, bool) {
+	switch {
+	case strings.HasPrefix(number, "+") && isDigits(number[1:]):
+		return number[1:], true
+	case strings.HasPrefix(number, "00") && isDigits(number[2:]):
+		return number[2:], true
+	}
+	return "", false
+}
@@ -0,0 +1,66 @@
+package gosmpp
+
+import (
+	"testing"
+
+	"github.com/linxGnu/gosmpp/data"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestE164Normalizer(t *testing.T) {
+	n := E164Normalizer{Countries: []Country{
+		{CallingCode: "44", TrunkPrefix: "0", Lengths: []int{10}},
+		{CallingCode: "84", TrunkPrefix: "0", Lengths: []int{9}},
+	}}
+
+	for _, tc := range []struct {
+		addr     string
+		ton, npi byte
+		number   string
+	}{
+		{"+44 7700 900-123", data.GSM_TON_INTERNATIONAL, data.GSM_NPI_ISDN, "447700900123"},
+		{"0084 901234567", data.GSM_TON_INTERNATIONAL, data.GSM_NPI_ISDN, "84901234567"},
+		{"07700 900123", data.GSM_TON_INTERNATIONAL, data.GSM_NPI_ISDN, "447700900123"},
+		{"090 123 4567", data.GSM_TON_INTERNATIONAL, data.GSM_NPI_ISDN, "84901234567"},
+		{"447700900123", data.GSM_TON_INTERNATIONAL, data.GSM_NPI_ISDN, "447700900123"},
+		{"7700900123", data.GSM_TON_INTERNATIONAL, data.GSM_NPI_ISDN, "447700900123"},
+		{"0123456789012", data.GSM_TON_NATIONAL, data.GSM_NPI_ISDN, "0123456789012"},
+		{"8888", data.GSM_TON_NETWORK, data.GSM_NPI_UNKNOWN, "8888"},
+		{"1234567", data.GSM_TON_UNKNOWN, data.GSM_NPI_ISDN, "1234567"},
+		{"MyBank", data.GSM_TON_ALPHANUMERIC, data.GSM_NPI_UNKNOWN, "MyBank"},
+	} {
+		a, err := n.Normalize(tc.addr)
+		require.Nil(t, err, tc.addr)
+		require.Equal(t, tc.ton, a.Ton(), tc.addr)
+		require.Equal(t, tc.npi, a.Npi(), tc.addr)
+		require.Equal(t, tc.number, a.Address(), tc.addr)
+	}
+
+	_, err := n.Normalize(" ")
+	require.NotNil(t, err)
+
+	// any length without known lengths, short codes up to the limit
+	n = E164Normalizer{Countries: []Country{{CallingCode: "33", TrunkPrefix: "0"}}, MaxShortCodeLength: 3}
+	a, err := n.Normalize("0612")
+	require.Nil(t, err)
+	require.Equal(t, data.GSM_TON_INTERNATIONAL, a.Ton())
+	require.Equal(t, "33612", a.Address())
+
+	a, err = n.Normalize("612345")
+	require.Nil(t, err)
+	require.Equal(t, data.GSM_TON_UNKNOWN, a.Ton())
+}
+
+func TestMessengerAddressNormalizer(t *testing.T) {
+	m := NewMessenger(&Session{}, WithAddressNormalizer(E164Normalizer{Countries: []Country{{CallingCode: "44", TrunkPrefix: "0"}}}))
+	submit, err := m.newSubmit("MyBank", "07700 900123")
+	require.Nil(t, err)
+	require.Equal(t, data.GSM_TON_ALPHANUMERIC, submit.SourceAddr.Ton())
+	require.Equal(t, data.GSM_TON_INTERNATIONAL, submit.DestAddr.Ton())
+	require.Equal(t, "447700900123", submit.DestAddr.Address())
+
+	submit, err = NewMessenger(&Session{}).newSubmit("MyBank", "07700 900123")
+	require.Nil(t, err)
+	require.Equal(t, data.GSM_TON_UNKNOWN, submit.DestAddr.Ton())
+}
@@ -0,0 +1,234 @@
+package gosmpp
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/linxGnu/gosmpp/clock"
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+)
+
+// ErrSessionClosed indicates session is closed.
+var ErrSessionClosed = errors.New("session is closed")
+
+// ordering keeps per-destination FIFO queues of SubmitAsync calls.
+type ordering struct {
+	maxRetries    int
+	retryInterval time.Duration
+
+	mu     sync.Mutex
+	queues map[string]*destinationQueue
+}
+
+// destinationQueue is queue of calls to destination, the first one is being submitted and the rest wait.
+type destinationQueue struct {
+	calls []*Call
+
+	// sizes are encoded lengths of calls and waiting is the sum of waiting ones, kept with budgets only
+	sizes   []int
+	waiting int
+	charges budgetCharges
+}
+
+// WithDestinationOrdering guarantees that SubmitAsync requests to the same destination (submit_sm, data_sm)
+// are submitted in order: next request is submitted only after the previous one is responded.
+//
+// Request failing transiently, with ErrResponseTimeout or ErrResponseLost as its bind was lost, or rejected
+// with ESME_RTHROTTLED or ESME_RMSGQFUL, is retried up to maxRetries times every retryInterval before the next
+// request of the destination is submitted. Other errors, e.g. ErrConnectionClosing or *pdu.SizeError, fail the
+// request at once, as do closing the session and canceling the call while waiting for retry. Requests to
+// different destinations are still submitted concurrently.
+func WithDestinationOrdering(maxRetries int, retryInterval time.Duration) SessionOption {
+	return func(s *Session) {
+		s.ordering = &ordering{
+			maxRetries:    maxRetries,
+			retryInterval: retryInterval,
+			queues:        make(map[string]*destinationQueue),
+		}
+	}
+}
+
+// WithQueueBudget charges requests waiting in queues of WithDestinationOrdering to budgets, e.g. one of
+// the session and one global. Over any budget, waiting requests of the least recently submitted destination
+// are evicted, their calls fail with ErrEvicted.
+func WithQueueBudget(budgets ...*MemoryBudget) SessionOption {
+	return func(s *Session) {
+		s.queueBudgets = append(s.queueBudgets, budgets...)
+	}
+}
+
+// destinationOf returns destination address of p, empty if p is not ordered.
+func destinationOf(p pdu.PDU) string {
+	switch pp := p.(type) {
+	case *pdu.SubmitSM:
+		return pp.DestAddr.Address()
+	case *pdu.DataSM:
+		return pp.DestAddr.Address()
+	default:
+		return ""
+	}
+}
+
+func (o *ordering) enqueue(s *Session, dest string, c *Call) {
+	var size int
+	if len(s.queueBudgets) > 0 {
+		size = encodedLen(c.PDU)
+	}
+
+	o.mu.Lock()
+	q, ok := o.queues[dest]
+	if !ok {
+		q = &destinationQueue{}
+		q.charges = newBudgetCharges(s.queueBudgets, EvictedQueue, dest, o.releaser(s, dest, q))
+		o.queues[dest] = q
+	}
+	q.calls = append(q.calls, c)
+	first := len(q.calls) == 1
+
+	evict := func() {}
+	if q.charges != nil {
+		q.sizes = append(q.sizes, size)
+		if !first {
+			q.waiting += size
+			evict = q.charges.charge(s.queueBudgets, q.waiting)
+		}
+	}
+	o.mu.Unlock()
+	evict()
+
+	// first call of the destination starts its sender
+	if first {
+		go o.send(s, dest)
+	}
+}
+
+// send submits queued calls of destination one by one, until the queue is empty.
+func (o *ordering) send(s *Session, dest string) {
+	for {
+		o.mu.Lock()
+		c := o.queues[dest].calls[0]
+		o.mu.Unlock()
+
+		c.finish(o.submit(s, c))
+
+		o.mu.Lock()
+		q := o.queues[dest]
+		q.calls = q.calls[1:]
+		if len(q.calls) == 0 {
+			delete(o.queues, dest)
+			q.charges.uncharge(s.queueBudgets)
+			o.mu.Unlock()
+			return
+		}
+		evict := func() {}
+		if q.charges != nil {
+			// the next call is not waiting anymore
+			q.sizes = q.sizes[1:]
+			if q.waiting -= q.sizes[0]; q.waiting == 0 {
+				q.charges.uncharge(s.queueBudgets)
+			} else {
+				evict = q.charges.charge(s.queueBudgets, q.waiting)
+			}
+		}
+		o.mu.Unlock()
+		evict()
+	}
+}
+
+// releaser returns func failing waiting calls of queue q evicted from budget.
+func (o *ordering) releaser(s *Session, dest string, q *destinationQueue) func() {
+	return func() {
+		o.mu.Lock()
+		if o.queues[dest] != q || len(q.calls) < 2 {
+			o.mu.Unlock()
+			return
+		}
+		waiting := append([]*Call(nil), q.calls[1:]...)
+		q.calls, q.sizes, q.waiting = q.calls[:1], q.sizes[:1], 0
+		q.charges.uncharge(s.queueBudgets)
+		o.mu.Unlock()
+
+		for _, c := range waiting {
+			c.finish(nil, ErrEvicted)
+		}
+	}
+}
+
+// submit submits request of c and waits for its response, retrying on transient errors.
+func (o *ordering) submit(s *Session, c *Call) (resp pdu.PDU, err error) {
+	p := c.PDU
+
+	// writer may still read PDU of previous attempt, so retries take copy encoded before the first one
+	var encoded []byte
+	if o.maxRetries > 0 {
+		buf := pdu.NewBuffer(nil)
+		p.Marshal(buf)
+		encoded = buf.Bytes()
+	}
+
+	for attempt := 0; ; attempt++ {
+		if atomic.LoadInt32(&s.state) != Alive {
+			return nil, ErrSessionClosed
+		}
+
+		if attempt > 0 {
+			// new sequence number, so late response of previous attempt is not taken
+			if p, err = pdu.Parse(bytes.NewReader(encoded)); err != nil {
+				return nil, err
+			}
+			p.AssignSequenceNumber()
+		}
+
+		call := newCall(p)
+		call.Correlation = c.Correlation
+		call.ctx = c.ctx
+		c.mu.Lock()
+		if atomic.LoadInt32(&c.state) == callCanceled {
+			c.mu.Unlock()
+			return nil, ErrCallCanceled
+		}
+		c.inner = call
+		c.mu.Unlock()
+
+		s.startCall(call)
+		<-call.Done()
+		if resp, err = call.Result(); !isTransient(resp, err) || attempt >= o.maxRetries {
+			return
+		}
+
+		o.wait(s, c)
+	}
+}
+
+// wait waits retryInterval before next attempt of c, or until session is closed or c is completed meanwhile,
+// e.g. canceled.
+func (o *ordering) wait(s *Session, c *Call) {
+	timer := clock.OrReal(s.settings.Clock).NewTimer(o.retryInterval)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C():
+	case <-s.closed:
+	case <-c.Done():
+	}
+}
+
+// isTransient tells whether request could succeed if submitted again: its response timed out or was lost with
+// expired bind, or SMSC throttled it or its queue was full.
+func isTransient(resp pdu.PDU, err error) bool {
+	if err != nil {
+		return errors.Is(err, ErrResponseTimeout) || errors.Is(err, ErrResponseLost)
+	}
+
+	if resp != nil {
+		switch resp.GetHeader().CommandStatus {
+		case data.ESME_RTHROTTLED, data.ESME_RMSGQFUL:
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,81 @@
+package gosmpp_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp"
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/server/smsctest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionDestinationOrderingRetries(t *testing.T) {
+	smsc := smsctest.NewPipeServer(nil)
+	defer smsc.Close()
+
+	s, err := gosmpp.NewSession(gosmpp.TRXConnector(smsc.Dialer(), gosmpp.Auth{SMSC: "pipe", SystemID: "esme"}),
+		gosmpp.Settings{ReadTimeout: 2 * time.Second}, -1, gosmpp.WithDestinationOrdering(3, 20*time.Millisecond))
+	require.Nil(t, err)
+	defer func() {
+		_ = s.Close()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	// throttled request is submitted again
+	smsc.SetFault(smsctest.Script(smsctest.Fault{Status: data.ESME_RTHROTTLED}))
+	resp, err := s.SubmitAsync(newTextSubmitSM("1", "throttled")).Wait(ctx)
+	require.Nil(t, err)
+	require.Equal(t, data.ESME_ROK, resp.GetHeader().CommandStatus)
+	smsc.ExpectReceived(t, data.SUBMIT_SM, 2)
+
+	// request rejected for good is not
+	smsc.SetFault(smsctest.Script(smsctest.Fault{Status: data.ESME_RINVDSTADR}))
+	resp, err = s.SubmitAsync(newTextSubmitSM("1", "rejected")).Wait(ctx)
+	require.Nil(t, err)
+	require.Equal(t, data.ESME_RINVDSTADR, resp.GetHeader().CommandStatus)
+	time.Sleep(50 * time.Millisecond)
+	smsc.ExpectReceived(t, data.SUBMIT_SM, 3)
+}
+
+func TestSessionDestinationOrderingClose(t *testing.T) {
+	smsc := smsctest.NewPipeServer(nil)
+	defer smsc.Close()
+
+	s, err := gosmpp.NewSession(gosmpp.TRXConnector(smsc.Dialer(), gosmpp.Auth{SMSC: "pipe", SystemID: "esme"}),
+		gosmpp.Settings{ReadTimeout: 2 * time.Second}, -1, gosmpp.WithDestinationOrdering(3, time.Minute))
+	require.Nil(t, err)
+
+	smsc.SetFault(smsctest.RespondStatus(data.ESME_RTHROTTLED))
+	c := s.SubmitAsync(newTextSubmitSM("1", "throttled"))
+	smsc.ExpectReceived(t, data.SUBMIT_SM, 1)
+
+	// retry waiting for its interval is given up once session is closed
+	time.Sleep(20 * time.Millisecond)
+	require.Nil(t, s.Close())
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, err = c.Wait(ctx)
+	require.ErrorIs(t, err, gosmpp.ErrSessionClosed)
+
+	// so is retry of canceled call
+	s, err = gosmpp.NewSession(gosmpp.TRXConnector(smsc.Dialer(), gosmpp.Auth{SMSC: "pipe", SystemID: "esme"}),
+		gosmpp.Settings{ReadTimeout: 2 * time.Second}, -1, gosmpp.WithDestinationOrdering(3, time.Minute))
+	require.Nil(t, err)
+	defer func() {
+		_ = s.Close()
+	}()
+	c = s.SubmitAsync(newTextSubmitSM("1", "throttled"))
+	smsc.ExpectReceived(t, data.SUBMIT_SM, 2)
+	time.Sleep(20 * time.Millisecond)
+	c.Cancel()
+
+	smsc.SetFault(nil)
+	next := s.SubmitAsync(newTextSubmitSM("1", "next"))
+	_, err = next.Wait(ctx)
+	require.Nil(t, err)
+}
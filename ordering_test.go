@@ -0,0 +1,90 @@
+package gosmpp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDestinationOf(t *testing.T) {
+	submit := pdu.NewSubmitSM().(*pdu.SubmitSM)
+	_ = submit.DestAddr.SetAddress("123")
+	require.Equal(t, "123", destinationOf(submit))
+
+	dataSM := pdu.NewDataSM().(*pdu.DataSM)
+	_ = dataSM.DestAddr.SetAddress("456")
+	require.Equal(t, "456", destinationOf(dataSM))
+
+	require.Equal(t, "", destinationOf(pdu.NewSubmitMulti()))
+}
+
+func TestIsTransient(t *testing.T) {
+	require.True(t, isTransient(nil, ErrResponseLost))
+	require.True(t, isTransient(nil, ErrResponseTimeout))
+	require.False(t, isTransient(nil, ErrConnectionClosing))
+	require.False(t, isTransient(nil, &pdu.SizeError{Max: 200}))
+	require.False(t, isTransient(nil, nil))
+
+	resp := pdu.NewSubmitSMResp()
+	require.False(t, isTransient(resp, nil))
+
+	resp.(*pdu.SubmitSMResp).CommandStatus = data.ESME_RTHROTTLED
+	require.True(t, isTransient(resp, nil))
+
+	resp.(*pdu.SubmitSMResp).CommandStatus = data.ESME_RMSGQFUL
+	require.True(t, isTransient(resp, nil))
+
+	resp.(*pdu.SubmitSMResp).CommandStatus = data.ESME_RINVDSTADR
+	require.False(t, isTransient(resp, nil))
+}
+
+func TestSessionDestinationOrdering(t *testing.T) {
+	auth := nextAuth()
+	s, err := NewSession(
+		TRXConnector(NonTLSDialer, auth),
+		Settings{
+			ReadTimeout: 2 * time.Second,
+		}, -1, WithDestinationOrdering(20, 50*time.Millisecond))
+	require.Nil(t, err)
+	defer func() {
+		_ = s.Close()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	t.Run("inOrder", func(t *testing.T) {
+		calls := make([]*Call, 10)
+		for i := range calls {
+			calls[i] = s.SubmitAsync(newSubmitSM(auth.SystemID))
+		}
+
+		// each call is submitted after the previous one is responded
+		var last int32
+		for _, c := range calls {
+			resp, err := c.Wait(ctx)
+			require.Nil(t, err)
+			require.Greater(t, resp.GetSequenceNumber(), last)
+			last = resp.GetSequenceNumber()
+		}
+
+		require.Eventually(t, func() bool {
+			s.ordering.mu.Lock()
+			defer s.ordering.mu.Unlock()
+			return len(s.ordering.queues) == 0
+		}, time.Second, time.Millisecond)
+	})
+
+	t.Run("noRetryOnClosedBind", func(t *testing.T) {
+		// bind is lost, submitting fails at once instead of being retried
+		require.Nil(t, s.bound().Close())
+
+		_, err := s.SubmitAsync(newSubmitSM(auth.SystemID)).Wait(ctx)
+		require.ErrorIs(t, err, ErrConnectionClosing)
+	})
+}
@@ -0,0 +1,217 @@
+package gosmpp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+)
+
+// Bits of the first byte of OTA security parameter indicator (SPI), 3GPP TS 23.048 section 5.1.1.
+const (
+	OTANoChecksum            byte = 0x00
+	OTARedundancyCheck       byte = 0x01
+	OTACryptographicChecksum byte = 0x02
+	OTADigitalSignature      byte = 0x03
+	OTACiphered              byte = 0x04
+	OTANoCounter             byte = 0x00
+	OTACounterNoReplayCheck  byte = 0x08
+	OTACounterHigher         byte = 0x10
+	OTACounterOneHigher      byte = 0x18
+)
+
+// Bits of the second byte of SPI, telling how card sends proof of receipt (PoR).
+const (
+	OTANoPoR               byte = 0x00
+	OTAPoRRequired         byte = 0x01
+	OTAPoROnError          byte = 0x02
+	OTAPoRRedundancyCheck  byte = 0x04
+	OTAPoRCryptoChecksum   byte = 0x08
+	OTAPoRDigitalSignature byte = 0x0C
+	OTAPoRCiphered         byte = 0x10
+	OTAPoRBySMSSubmit      byte = 0x20
+)
+
+const (
+	otaChecksumMask byte = 0x03
+
+	// 8-bit data coding of class 2 messages, stored on SIM card
+	otaDataCoding byte = 0xF6
+)
+
+// OTACipher ciphers command packets, e.g. with triple DES in CBC mode keyed by KIc.
+type OTACipher interface {
+	// BlockSize returns block size, ciphered data is padded to its multiple.
+	BlockSize() int
+
+	// Encrypt returns ciphered data.
+	Encrypt(plain []byte) ([]byte, error)
+}
+
+// OTAChecksum computes redundancy check, cryptographic checksum or digital signature of command packets,
+// keyed by KID.
+type OTAChecksum interface {
+	// Size returns length of the result.
+	Size() int
+
+	// Sum returns checksum of data.
+	Sum(data []byte) ([]byte, error)
+}
+
+// OTACommand is 3GPP TS 23.048 command packet of secured data for SIM card application, e.g. RFM or RAM
+// commands of SIM OTA campaign.
+type OTACommand struct {
+	// SPI is security parameter indicator, see OTACiphered, OTACryptographicChecksum and OTAPoRRequired.
+	SPI [2]byte
+
+	// KIc and KID identify keys and algorithms of ciphering and checksum.
+	KIc, KID byte
+
+	// TAR is toolkit application reference of the receiving application.
+	TAR [3]byte
+
+	// Counter is replay counter, 5 bytes.
+	Counter uint64
+
+	// Data is secured data, e.g. APDUs of the application.
+	Data []byte
+
+	// Cipher ciphers the packet, required if SPI tells it is ciphered.
+	Cipher OTACipher
+
+	// Checksum computes checksum of the packet, required if SPI tells it has one.
+	Checksum OTAChecksum
+}
+
+// Packet returns command packet: command packet length, header and secured data, ciphered if SPI tells so.
+//
+// Checksum is computed over the header and secured data with padding. Ciphering covers counter, padding
+// counter, checksum and secured data with padding.
+func (c OTACommand) Packet() ([]byte, error) {
+	if c.Counter >= 1<<40 {
+		return nil, fmt.Errorf("gosmpp: OTA counter %d exceeds 5 bytes", c.Counter)
+	}
+
+	checksumSize := 0
+	if c.SPI[0]&otaChecksumMask != OTANoChecksum {
+		if c.Checksum == nil {
+			return nil, fmt.Errorf("gosmpp: OTA checksum required by SPI %02X", c.SPI[0])
+		}
+		checksumSize = c.Checksum.Size()
+	}
+
+	ciphered := c.SPI[0]&OTACiphered != 0
+	padding := 0
+	if ciphered {
+		if c.Cipher == nil {
+			return nil, fmt.Errorf("gosmpp: OTA cipher required by SPI %02X", c.SPI[0])
+		}
+		// counter, padding counter, checksum and data
+		if size := c.Cipher.BlockSize(); size > 0 {
+			padding = (size - (6+checksumSize+len(c.Data))%size) % size
+		}
+	}
+
+	headerLen := 13 + checksumSize
+	packetLen := 2 + headerLen + len(c.Data) + padding
+	if packetLen > 0xFFFF {
+		return nil, fmt.Errorf("gosmpp: OTA command packet of %d bytes is too long", packetLen)
+	}
+
+	header := []byte{
+		byte(packetLen >> 8), byte(packetLen), // CPL
+		0x00,            // CHI
+		byte(headerLen), // CHL
+		c.SPI[0], c.SPI[1],
+		c.KIc, c.KID,
+		c.TAR[0], c.TAR[1], c.TAR[2],
+		byte(c.Counter >> 32), byte(c.Counter >> 24), byte(c.Counter >> 16), byte(c.Counter >> 8), byte(c.Counter),
+		byte(padding), // PCNTR
+	}
+	secured := append(append([]byte{}, c.Data...), make([]byte, padding)...)
+
+	var checksum []byte
+	if checksumSize > 0 {
+		var err error
+		if checksum, err = c.Checksum.Sum(append(append([]byte{}, header...), secured...)); err != nil {
+			return nil, err
+		}
+		if len(checksum) != checksumSize {
+			return nil, fmt.Errorf("gosmpp: OTA checksum of %d bytes, expected %d", len(checksum), checksumSize)
+		}
+	}
+
+	// counter onwards is ciphered
+	plain := append(append(append([]byte{}, header[11:]...), checksum...), secured...)
+	packet := append([]byte{}, header[:11]...)
+	if !ciphered {
+		return append(packet, plain...), nil
+	}
+
+	encrypted, err := c.Cipher.Encrypt(plain)
+	if err != nil {
+		return nil, err
+	}
+	if len(encrypted) != len(plain) {
+		return nil, fmt.Errorf("gosmpp: OTA cipher returned %d bytes of %d", len(encrypted), len(plain))
+	}
+	return append(packet, encrypted...), nil
+}
+
+// SendOTA sends command packet to SIM card of destination as SIM data download (protocol_id 0x7F,
+// class 2 8-bit data coding), with command packet identifier in UDH of the first part.
+func (m *Messenger) SendOTA(ctx context.Context, from, to string, cmd OTACommand) (*MessageHandle, error) {
+	packet, err := cmd.Packet()
+	if err != nil {
+		return nil, err
+	}
+
+	submit, err := m.newSubmit(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	messages, err := pdu.NewBinaryMessages(packet, pdu.UDH{{ID: data.UDH_COMMAND_PACKET, Data: []byte{}}})
+	if err != nil {
+		return nil, err
+	}
+
+	parts := make([]*pdu.SubmitSM, len(messages))
+	for i, msg := range messages {
+		payload, _ := msg.GetMessageData()
+		if err = msg.SetMessageDataWithEncoding(payload, otaEncoding{}); err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			// command packet identifier is in the first part only
+			msg.SetUDH(withoutCommandPacket(msg.UDH()))
+		}
+
+		part := *submit
+		part.EsmClass |= data.SM_UDH_GSM
+		part.ProtocolID = data.PID_SIM_DATA_DOWNLOAD
+		part.Message = *msg
+		parts[i] = &part
+	}
+	return m.send(ctx, parts)
+}
+
+func withoutCommandPacket(udh pdu.UDH) pdu.UDH {
+	var rest pdu.UDH
+	for _, ie := range udh {
+		if ie.ID != data.UDH_COMMAND_PACKET {
+			rest = append(rest, ie)
+		}
+	}
+	return rest
+}
+
+// otaEncoding is 8-bit data coding of class 2 messages, stored on SIM card.
+type otaEncoding struct{}
+
+func (otaEncoding) Encode(str string) ([]byte, error) { return []byte(str), nil }
+
+func (otaEncoding) Decode(b []byte) (string, error) { return string(b), nil }
+
+func (otaEncoding) DataCoding() byte { return otaDataCoding }
@@ -0,0 +1,124 @@
+package gosmpp
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"hash/crc32"
+	"testing"
+
+	"github.com/linxGnu/gosmpp/data"
+
+	"github.com/stretchr/testify/require"
+)
+
+// xorCipher is test cipher, xor of every byte with key.
+type xorCipher byte
+
+func (xorCipher) BlockSize() int { return 8 }
+
+func (c xorCipher) Encrypt(plain []byte) ([]byte, error) {
+	out := make([]byte, len(plain))
+	for i, b := range plain {
+		out[i] = b ^ byte(c)
+	}
+	return out, nil
+}
+
+// crcChecksum is test checksum, CRC32 of data.
+type crcChecksum struct{}
+
+func (crcChecksum) Size() int { return 4 }
+
+func (crcChecksum) Sum(b []byte) ([]byte, error) {
+	sum := crc32.ChecksumIEEE(b)
+	return []byte{byte(sum >> 24), byte(sum >> 16), byte(sum >> 8), byte(sum)}, nil
+}
+
+func TestOTACommandPacket(t *testing.T) {
+	t.Run("plain", func(t *testing.T) {
+		packet, err := OTACommand{
+			SPI:     [2]byte{OTACounterNoReplayCheck, OTANoPoR},
+			KIc:     0x00,
+			KID:     0x00,
+			TAR:     [3]byte{0xB0, 0x00, 0x10},
+			Counter: 1,
+			Data:    []byte{0xA0, 0xA4, 0x00, 0x00, 0x02, 0x3F, 0x00},
+		}.Packet()
+		require.NoError(t, err)
+		require.Equal(t, "0016000d08000000b00010000000000100a0a40000023f00", hex.EncodeToString(packet))
+	})
+
+	t.Run("ciphered with checksum", func(t *testing.T) {
+		cmd := OTACommand{
+			SPI:      [2]byte{OTACryptographicChecksum | OTACiphered | OTACounterHigher, OTAPoRRequired},
+			KIc:      0x15,
+			KID:      0x15,
+			TAR:      [3]byte{0x00, 0x00, 0x00},
+			Counter:  0x0102030405,
+			Data:     []byte{0x80, 0xE6, 0x02, 0x00},
+			Cipher:   xorCipher(0x5A),
+			Checksum: crcChecksum{},
+		}
+		packet, err := cmd.Packet()
+		require.NoError(t, err)
+
+		// counter, padding counter, checksum and data are padded to 2 blocks
+		pad := 16 - (6 + 4 + 4)
+		require.Len(t, packet, 11+16)
+		require.Equal(t, []byte{0x00, byte(len(packet) - 2), 0x00, 17, cmd.SPI[0], cmd.SPI[1], 0x15, 0x15, 0, 0, 0}, packet[:11])
+
+		plain, _ := xorCipher(0x5A).Encrypt(packet[11:])
+		require.Equal(t, []byte{1, 2, 3, 4, 5, byte(pad)}, plain[:6])
+		secured := append(append([]byte{}, cmd.Data...), make([]byte, pad)...)
+		require.Equal(t, secured, plain[10:])
+
+		sum, _ := crcChecksum{}.Sum(append(append(append([]byte{}, packet[:11]...), plain[:6]...), secured...))
+		require.Equal(t, sum, plain[6:10])
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := OTACommand{SPI: [2]byte{OTACryptographicChecksum}}.Packet()
+		require.Error(t, err)
+
+		_, err = OTACommand{SPI: [2]byte{OTACiphered}}.Packet()
+		require.Error(t, err)
+
+		_, err = OTACommand{Counter: 1 << 40}.Packet()
+		require.Error(t, err)
+	})
+}
+
+func TestMessengerSendOTA(t *testing.T) {
+	m := newTestMessenger(t)
+
+	cmd := OTACommand{
+		SPI:  [2]byte{OTACounterNoReplayCheck, OTAPoRRequired},
+		TAR:  [3]byte{0xB0, 0x00, 0x10},
+		Data: bytes.Repeat([]byte{0xA0}, 300),
+	}
+	h, err := m.SendOTA(context.Background(), "OTA", "+84901234567", cmd)
+	require.NoError(t, err)
+	require.Len(t, h.Parts, 3)
+
+	for i, part := range h.Parts {
+		require.Equal(t, data.PID_SIM_DATA_DOWNLOAD, part.ProtocolID)
+		require.Equal(t, byte(0xF6), part.Message.Encoding().DataCoding())
+		require.NotZero(t, part.EsmClass&data.SM_UDH_GSM)
+
+		_, found := part.Message.UDH().FindInfoElement(data.UDH_COMMAND_PACKET)
+		require.Equal(t, i == 0, found)
+		total, seq, _, found := part.Message.UDH().GetConcatInfo()
+		require.True(t, found)
+		require.Equal(t, byte(3), total)
+		require.Equal(t, byte(i+1), seq)
+	}
+
+	packet, _ := cmd.Packet()
+	var sent []byte
+	for _, part := range h.Parts {
+		payload, _ := part.Message.GetMessageData()
+		sent = append(sent, payload...)
+	}
+	require.Equal(t, packet, sent)
+}
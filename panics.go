@@ -0,0 +1,131 @@
+package gosmpp
+
+import (
+	"runtime/debug"
+
+	"github.com/linxGnu/gosmpp/pdu"
+)
+
+// recoverHandler recovers panic of user callback and notifies it to onPanic. Must be deferred.
+func recoverHandler(onPanic HandlerPanicCallback, handler string) {
+	if r := recover(); r != nil {
+		onPanic(handler, r, debug.Stack())
+	}
+}
+
+// protected returns settings with user callbacks wrapped to recover panics, if OnHandlerPanic is set.
+func (s Settings) protected() Settings {
+	onPanic := s.OnHandlerPanic
+	if onPanic == nil {
+		return s
+	}
+
+	if h := s.OnPDU; h != nil {
+		s.OnPDU = func(p pdu.PDU, responded bool) {
+			defer recoverHandler(onPanic, "OnPDU")
+			h(p, responded)
+		}
+	}
+
+	if h := s.OnMessage; h != nil {
+		s.OnMessage = func(m IncomingMessage) {
+			defer recoverHandler(onPanic, "OnMessage")
+			h(m)
+		}
+	}
+
+	if h := s.OnDeliveryReceipt; h != nil {
+		s.OnDeliveryReceipt = func(r Receipt) {
+			defer recoverHandler(onPanic, "OnDeliveryReceipt")
+			h(r)
+		}
+	}
+
+	if h := s.OnAllPDU; h != nil {
+		s.OnAllPDU = protectAllPDU(onPanic, "OnAllPDU", h)
+	}
+
+	if h := s.OnReceivingError; h != nil {
+		s.OnReceivingError = protectError(onPanic, "OnReceivingError", h)
+	}
+
+	if h := s.OnSubmitError; h != nil {
+		s.OnSubmitError = func(p pdu.PDU, err error) {
+			defer recoverHandler(onPanic, "OnSubmitError")
+			h(p, err)
+		}
+	}
+
+	if h := s.OnRebindingError; h != nil {
+		s.OnRebindingError = protectError(onPanic, "OnRebindingError", h)
+	}
+
+	if h := s.OnClosed; h != nil {
+		s.OnClosed = func(state State) {
+			defer recoverHandler(onPanic, "OnClosed")
+			h(state)
+		}
+	}
+
+	if h := s.OnRebind; h != nil {
+		s.OnRebind = func() {
+			defer recoverHandler(onPanic, "OnRebind")
+			h()
+		}
+	}
+
+	if s.WindowedRequestTracking != nil {
+		// copy, do not modify user settings
+		w := *s.WindowedRequestTracking
+		s.WindowedRequestTracking = &w
+
+		if h := w.OnReceivedPduRequest; h != nil {
+			w.OnReceivedPduRequest = protectAllPDU(onPanic, "OnReceivedPduRequest", h)
+		}
+
+		if h := w.OnExpectedPduResponse; h != nil {
+			w.OnExpectedPduResponse = func(r Response) {
+				defer recoverHandler(onPanic, "OnExpectedPduResponse")
+				h(r)
+			}
+		}
+
+		if h := w.OnUnexpectedPduResponse; h != nil {
+			w.OnUnexpectedPduResponse = protectPDU(onPanic, "OnUnexpectedPduResponse", h)
+		}
+
+		if h := w.OnExpiredPduRequest; h != nil {
+			w.OnExpiredPduRequest = func(p pdu.PDU) (closeBind bool) {
+				defer recoverHandler(onPanic, "OnExpiredPduRequest")
+				return h(p)
+			}
+		}
+
+		if h := w.OnClosePduRequest; h != nil {
+			w.OnClosePduRequest = protectPDU(onPanic, "OnClosePduRequest", h)
+		}
+	}
+
+	return s
+}
+
+func protectAllPDU(onPanic HandlerPanicCallback, handler string, h AllPDUCallback) AllPDUCallback {
+	return func(p pdu.PDU) (responsePdu pdu.PDU, closeBind bool) {
+		defer recoverHandler(onPanic, handler)
+		return h(p)
+	}
+}
+
+func protectError(onPanic HandlerPanicCallback, handler string, h ErrorCallback) ErrorCallback {
+	return func(err error) {
+		defer recoverHandler(onPanic, handler)
+		h(err)
+	}
+}
+
+func protectPDU(onPanic HandlerPanicCallback, handler string, h func(pdu.PDU)) func(pdu.PDU) {
+	return func(p pdu.PDU) {
+		defer recoverHandler(onPanic, handler)
+		h(p)
+	}
+}
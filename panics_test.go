@@ -0,0 +1,122 @@
+package gosmpp
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp/pdu"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSettingsProtected(t *testing.T) {
+	var handlers []string
+	onPanic := func(handler string, recovered interface{}, stack []byte) {
+		require.Equal(t, "boom", recovered)
+		require.NotEmpty(t, stack)
+		handlers = append(handlers, handler)
+	}
+
+	window := &WindowedRequestTracking{
+		OnReceivedPduRequest:    func(pdu.PDU) (pdu.PDU, bool) { panic("boom") },
+		OnExpectedPduResponse:   func(Response) { panic("boom") },
+		OnUnexpectedPduResponse: func(pdu.PDU) { panic("boom") },
+		OnExpiredPduRequest:     func(pdu.PDU) bool { panic("boom") },
+		OnClosePduRequest:       func(pdu.PDU) { panic("boom") },
+	}
+
+	settings := Settings{
+		OnPDU:             func(pdu.PDU, bool) { panic("boom") },
+		OnMessage:         func(IncomingMessage) { panic("boom") },
+		OnDeliveryReceipt: func(Receipt) { panic("boom") },
+		OnAllPDU:          func(pdu.PDU) (pdu.PDU, bool) { panic("boom") },
+		OnReceivingError:  func(error) { panic("boom") },
+		OnSubmitError:     func(pdu.PDU, error) { panic("boom") },
+		OnRebindingError:  func(error) { panic("boom") },
+		OnClosed:          func(State) { panic("boom") },
+		OnRebind:          func() { panic("boom") },
+
+		WindowedRequestTracking: window,
+	}
+
+	// not recovered without OnHandlerPanic
+	require.Panics(t, func() { settings.protected().OnRebind() })
+
+	settings.OnHandlerPanic = onPanic
+	p := settings.protected()
+	require.NotSame(t, window, p.WindowedRequestTracking)
+
+	p.OnPDU(nil, false)
+	p.OnMessage(IncomingMessage{})
+	p.OnDeliveryReceipt(Receipt{})
+	r, closeBind := p.OnAllPDU(nil)
+	require.Nil(t, r)
+	require.False(t, closeBind)
+	p.OnReceivingError(errors.New(""))
+	p.OnSubmitError(nil, nil)
+	p.OnRebindingError(nil)
+	p.OnClosed(ExplicitClosing)
+	p.OnRebind()
+	p.OnReceivedPduRequest(nil)
+	p.OnExpectedPduResponse(Response{})
+	p.OnUnexpectedPduResponse(nil)
+	require.False(t, p.OnExpiredPduRequest(nil))
+	p.OnClosePduRequest(nil)
+
+	require.Equal(t, []string{
+		"OnPDU", "OnMessage", "OnDeliveryReceipt", "OnAllPDU", "OnReceivingError", "OnSubmitError", "OnRebindingError", "OnClosed", "OnRebind",
+		"OnReceivedPduRequest", "OnExpectedPduResponse", "OnUnexpectedPduResponse", "OnExpiredPduRequest", "OnClosePduRequest",
+	}, handlers)
+
+	// user settings are untouched
+	require.Panics(t, func() { window.OnClosePduRequest(nil) })
+}
+
+func TestSessionHandlerPanic(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		handlers []string
+	)
+
+	auth := nextAuth()
+	s, err := NewSession(
+		TRXConnector(NonTLSDialer, auth),
+		Settings{
+			ReadTimeout: 2 * time.Second,
+			OnPDU: func(p pdu.PDU, _ bool) {
+				if _, ok := p.(*pdu.SubmitSMResp); ok {
+					panic("boom")
+				}
+			},
+			OnHandlerPanic: func(handler string, _ interface{}, _ []byte) {
+				mu.Lock()
+				handlers = append(handlers, handler)
+				mu.Unlock()
+			},
+		}, -1, WithEventHandler(func(e Event) {
+			if e.Type == EventBound {
+				panic("boom")
+			}
+		}))
+	require.Nil(t, err)
+	defer func() {
+		_ = s.Close()
+	}()
+
+	// bind keeps running after handler panics
+	for i := 0; i < 3; i++ {
+		require.Nil(t, s.Submit(newSubmitSM(auth.SystemID)))
+	}
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(handlers) == 4
+	}, 2*time.Second, 10*time.Millisecond)
+	require.True(t, s.IsBound())
+
+	mu.Lock()
+	require.Equal(t, []string{"EventHandler", "OnPDU", "OnPDU", "OnPDU"}, handlers)
+	mu.Unlock()
+}
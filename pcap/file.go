@@ -0,0 +1,278 @@
+package pcap
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+// ErrFormat indicates file is neither pcap nor pcapng.
+var ErrFormat = errors.New("pcap: unknown file format")
+
+// Link types of captured packets, see https://www.tcpdump.org/linktypes.html.
+const (
+	LinkTypeNull     = 0
+	LinkTypeEthernet = 1
+	LinkTypeRaw      = 101
+	LinkTypeLinuxSLL = 113
+	LinkTypeIPv4     = 228
+	LinkTypeIPv6     = 229
+	LinkTypeLoop     = 108
+	LinkTypeSLL2     = 276
+
+	// DLT_RAW is 12 or 14 on some BSDs, and old captures have it as link type.
+	linkTypeRaw12 = 12
+	linkTypeRaw14 = 14
+)
+
+const (
+	blockSectionHeader  = 0x0A0D0D0A
+	blockInterface      = 0x00000001
+	blockSimplePacket   = 0x00000003
+	blockEnhancedPacket = 0x00000006
+
+	byteOrderMagic = 0x1A2B3C4D
+
+	// maxBlockLen limits blocks and records, guarding against corrupted lengths.
+	maxBlockLen = 16 << 20
+)
+
+// packet is captured frame of link layer.
+type packet struct {
+	time     time.Time
+	linkType uint32
+	data     []byte
+}
+
+type packetReader interface {
+	next() (packet, error)
+}
+
+// newPacketReader detects format of r by its magic number.
+func newPacketReader(r io.Reader) (packetReader, error) {
+	br := bufio.NewReaderSize(r, 64<<10)
+	magic, err := br.Peek(4)
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			err = ErrFormat
+		}
+		return nil, err
+	}
+
+	switch {
+	case binary.BigEndian.Uint32(magic) == blockSectionHeader:
+		return &ngReader{r: br}, nil
+	default:
+		return newClassicReader(br)
+	}
+}
+
+// classicReader reads libpcap files.
+type classicReader struct {
+	r        io.Reader
+	order    binary.ByteOrder
+	nano     bool
+	linkType uint32
+	header   [16]byte
+}
+
+func newClassicReader(r io.Reader) (*classicReader, error) {
+	var header [24]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, ErrFormat
+	}
+
+	c := &classicReader{r: r}
+	switch binary.LittleEndian.Uint32(header[:4]) {
+	case 0xA1B2C3D4:
+		c.order = binary.LittleEndian
+	case 0xA1B23C4D:
+		c.order, c.nano = binary.LittleEndian, true
+	case 0xD4C3B2A1:
+		c.order = binary.BigEndian
+	case 0x4D3CB2A1:
+		c.order, c.nano = binary.BigEndian, true
+	default:
+		return nil, ErrFormat
+	}
+	// FCS and other flags are in upper bits of link type
+	c.linkType = c.order.Uint32(header[20:]) & 0x0FFFFFFF
+	return c, nil
+}
+
+func (c *classicReader) next() (p packet, err error) {
+	if _, err = io.ReadFull(c.r, c.header[:]); err != nil {
+		return
+	}
+
+	sec, frac := c.order.Uint32(c.header[0:]), c.order.Uint32(c.header[4:])
+	length := c.order.Uint32(c.header[8:])
+	if length > maxBlockLen {
+		err = fmt.Errorf("pcap: record of %d bytes exceeds limit", length)
+		return
+	}
+
+	p.data = make([]byte, length)
+	if _, err = io.ReadFull(c.r, p.data); err != nil {
+		err = unexpectedEOF(err)
+		return
+	}
+
+	if !c.nano {
+		frac *= 1000
+	}
+	p.time, p.linkType = time.Unix(int64(sec), int64(frac)), c.linkType
+	return
+}
+
+// ngReader reads pcapng files.
+type ngReader struct {
+	r          io.Reader
+	order      binary.ByteOrder
+	interfaces []ngInterface
+}
+
+type ngInterface struct {
+	linkType uint32
+
+	// unitsPerSecond is resolution of timestamps.
+	unitsPerSecond uint64
+}
+
+func (c *ngReader) next() (p packet, err error) {
+	for {
+		var blockType uint32
+		var body []byte
+		if blockType, body, err = c.readBlock(); err != nil {
+			return
+		}
+
+		switch blockType {
+		case blockInterface:
+			if len(body) < 8 {
+				return p, errors.New("pcap: invalid interface description block")
+			}
+			c.interfaces = append(c.interfaces, ngInterface{
+				linkType:       uint32(c.order.Uint16(body)),
+				unitsPerSecond: c.resolution(body[8:]),
+			})
+
+		case blockEnhancedPacket:
+			if len(body) < 20 {
+				return p, errors.New("pcap: invalid enhanced packet block")
+			}
+			id, length := c.order.Uint32(body), c.order.Uint32(body[12:])
+			if int(id) >= len(c.interfaces) || int(length) > len(body)-20 {
+				return p, errors.New("pcap: invalid enhanced packet block")
+			}
+			iface := c.interfaces[id]
+			ts := uint64(c.order.Uint32(body[4:]))<<32 | uint64(c.order.Uint32(body[8:]))
+			sec, units := ts/iface.unitsPerSecond, ts%iface.unitsPerSecond
+			nsec := float64(units) * 1e9 / float64(iface.unitsPerSecond)
+
+			p.time = time.Unix(int64(sec), int64(nsec))
+			p.linkType, p.data = iface.linkType, body[20:20+length]
+			return
+
+		case blockSimplePacket:
+			// simple packets have no timestamp and belong to the first interface
+			if len(body) < 4 || len(c.interfaces) == 0 {
+				return p, errors.New("pcap: invalid simple packet block")
+			}
+			length := int(c.order.Uint32(body))
+			if length > len(body)-4 {
+				length = len(body) - 4
+			}
+			p.linkType, p.data = c.interfaces[0].linkType, body[4:4+length]
+			return
+		}
+	}
+}
+
+// readBlock reads next block, returning its type and body without trailing length.
+func (c *ngReader) readBlock() (blockType uint32, body []byte, err error) {
+	var header [8]byte
+	if _, err = io.ReadFull(c.r, header[:]); err != nil {
+		return
+	}
+
+	if binary.BigEndian.Uint32(header[:]) == blockSectionHeader {
+		// byte order of the new section is given by its byte order magic
+		var bom [4]byte
+		if _, err = io.ReadFull(c.r, bom[:]); err != nil {
+			err = unexpectedEOF(err)
+			return
+		}
+		switch {
+		case binary.LittleEndian.Uint32(bom[:]) == byteOrderMagic:
+			c.order = binary.LittleEndian
+		case binary.BigEndian.Uint32(bom[:]) == byteOrderMagic:
+			c.order = binary.BigEndian
+		default:
+			err = ErrFormat
+			return
+		}
+		c.interfaces = c.interfaces[:0]
+
+		length := c.order.Uint32(header[4:])
+		if length < 16 || length > maxBlockLen {
+			err = fmt.Errorf("pcap: invalid length %d of section header block", length)
+			return
+		}
+		_, err = io.CopyN(io.Discard, c.r, int64(length)-12)
+		return blockSectionHeader, nil, unexpectedEOF(err)
+	}
+
+	if c.order == nil {
+		err = ErrFormat
+		return
+	}
+
+	blockType, length := c.order.Uint32(header[:]), c.order.Uint32(header[4:])
+	if length < 12 || length%4 != 0 || length > maxBlockLen {
+		err = fmt.Errorf("pcap: invalid length %d of block", length)
+		return
+	}
+
+	body = make([]byte, length-8)
+	if _, err = io.ReadFull(c.r, body); err != nil {
+		err = unexpectedEOF(err)
+		return
+	}
+	body = body[:len(body)-4]
+	return
+}
+
+// resolution returns units per second of timestamps given by if_tsresol option of interface, microseconds
+// by default.
+func (c *ngReader) resolution(options []byte) uint64 {
+	for len(options) >= 4 {
+		code, length := c.order.Uint16(options), int(c.order.Uint16(options[2:]))
+		if code == 0 || 4+length > len(options) {
+			break
+		}
+
+		if code == 9 && length >= 1 {
+			v := options[4]
+			switch {
+			case v&0x80 != 0 && v&0x7F < 64:
+				return 1 << (v & 0x7F)
+			case v&0x80 == 0 && v <= 19:
+				return uint64(math.Pow10(int(v)))
+			}
+		}
+		options = options[4+(length+3)&^3:]
+	}
+	return 1e6
+}
+
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
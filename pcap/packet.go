@@ -0,0 +1,139 @@
+package pcap
+
+import (
+	"encoding/binary"
+	"net/netip"
+)
+
+const (
+	etherTypeIPv4 = 0x0800
+	etherTypeIPv6 = 0x86DD
+	etherTypeVLAN = 0x8100
+	etherTypeQinQ = 0x88A8
+
+	protocolTCP = 6
+)
+
+const (
+	tcpFlagFIN = 0x01
+	tcpFlagSYN = 0x02
+	tcpFlagRST = 0x04
+)
+
+// segment is TCP segment of captured packet.
+type segment struct {
+	src, dst netip.AddrPort
+	seq      uint32
+	flags    byte
+	payload  []byte
+}
+
+// parseSegment decodes TCP segment carried by frame of link type. It returns false if frame
+// is not TCP over IPv4 or IPv6, or is truncated.
+func parseSegment(linkType uint32, frame []byte) (s segment, ok bool) {
+	var ip []byte
+	switch linkType {
+	case LinkTypeEthernet:
+		if len(frame) < 14 {
+			return
+		}
+		etherType, offset := binary.BigEndian.Uint16(frame[12:]), 14
+		for (etherType == etherTypeVLAN || etherType == etherTypeQinQ) && len(frame) >= offset+4 {
+			etherType, offset = binary.BigEndian.Uint16(frame[offset+2:]), offset+4
+		}
+		if etherType != etherTypeIPv4 && etherType != etherTypeIPv6 {
+			return
+		}
+		ip = frame[offset:]
+
+	case LinkTypeNull, LinkTypeLoop:
+		// address family of loopback header is in byte order of capturing host, or network order for
+		// LinkTypeLoop, IP version of the payload tells anyway
+		if len(frame) < 4 {
+			return
+		}
+		ip = frame[4:]
+
+	case LinkTypeRaw, LinkTypeIPv4, LinkTypeIPv6, linkTypeRaw12, linkTypeRaw14:
+		ip = frame
+
+	case LinkTypeLinuxSLL:
+		if len(frame) < 16 {
+			return
+		}
+		ip = frame[16:]
+
+	case LinkTypeSLL2:
+		if len(frame) < 20 {
+			return
+		}
+		ip = frame[20:]
+
+	default:
+		return
+	}
+
+	if len(ip) == 0 {
+		return
+	}
+
+	var srcIP, dstIP netip.Addr
+	var tcp []byte
+	switch ip[0] >> 4 {
+	case 4:
+		headerLen := int(ip[0]&0x0F) * 4
+		if headerLen < 20 || len(ip) < headerLen || ip[9] != protocolTCP {
+			return
+		}
+		// fragments other than the first one carry no TCP header
+		if binary.BigEndian.Uint16(ip[6:])&0x1FFF != 0 {
+			return
+		}
+		if total := int(binary.BigEndian.Uint16(ip[2:])); total >= headerLen && total < len(ip) {
+			ip = ip[:total] // trailing padding of Ethernet
+		}
+		srcIP, dstIP = netip.AddrFrom4([4]byte(ip[12:16])), netip.AddrFrom4([4]byte(ip[16:20]))
+		tcp = ip[headerLen:]
+
+	case 6:
+		if len(ip) < 40 {
+			return
+		}
+		if payload := int(binary.BigEndian.Uint16(ip[4:])); payload > 0 && 40+payload < len(ip) {
+			ip = ip[:40+payload]
+		}
+		srcIP, dstIP = netip.AddrFrom16([16]byte(ip[8:24])), netip.AddrFrom16([16]byte(ip[24:40]))
+
+		next, rest := ip[6], ip[40:]
+		for next != protocolTCP {
+			// skip hop-by-hop, routing and destination options extension headers
+			if (next != 0 && next != 43 && next != 60) || len(rest) < 8 {
+				return
+			}
+			l := 8 + int(rest[1])*8
+			if len(rest) < l {
+				return
+			}
+			next, rest = rest[0], rest[l:]
+		}
+		tcp = rest
+
+	default:
+		return
+	}
+
+	if len(tcp) < 20 {
+		return
+	}
+	headerLen := int(tcp[12]>>4) * 4
+	if headerLen < 20 || len(tcp) < headerLen {
+		return
+	}
+
+	s.src = netip.AddrPortFrom(srcIP, binary.BigEndian.Uint16(tcp))
+	s.dst = netip.AddrPortFrom(dstIP, binary.BigEndian.Uint16(tcp[2:]))
+	s.seq = binary.BigEndian.Uint32(tcp[4:])
+	s.flags = tcp[13]
+	s.payload = tcp[headerLen:]
+	return s, true
+}
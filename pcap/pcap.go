@@ -0,0 +1,96 @@
+// Package pcap decodes SMPP traffic of packet captures offline, e.g. taken by tcpdump or Wireshark.
+//
+// Files in pcap and pcapng formats are read, TCP streams on SMPP ports are reassembled and PDUs within them
+// are decoded by the library, so that traffic of production incidents can be inspected with the same code
+// that produced it:
+//
+//	f, _ := os.Open("smpp.pcapng")
+//	err := pcap.Decode(f, []uint16{2775}, func(r pcap.Record) error {
+//		if r.Err == nil {
+//			fmt.Println(r.Time, r.Src, ">", r.Dst, pdu.Sprint(r.PDU))
+//		}
+//		return nil
+//	})
+//
+// Supported link types are Ethernet (with VLAN tags), loopback, raw IP and Linux cooked captures, carrying
+// TCP over IPv4 or IPv6. Capture may start in the middle of connection, PDUs are decoded from the first
+// segment starting with PDU header.
+package pcap
+
+import (
+	"errors"
+	"io"
+	"net/netip"
+	"time"
+
+	"github.com/linxGnu/gosmpp/pdu"
+)
+
+// DefaultPort is SMPP port of IANA, used when Decode is given no ports.
+const DefaultPort = 2775
+
+// Record is PDU decoded from capture.
+type Record struct {
+	// Time is capture time of the packet completing PDU. It's zero for simple packet blocks of pcapng,
+	// which have no timestamp.
+	Time time.Time
+
+	// Src and Dst are endpoints of TCP connection sending and receiving the PDU.
+	Src, Dst netip.AddrPort
+
+	// PDU is decoded PDU, nil if Err is set.
+	PDU pdu.PDU
+
+	// Err is set when stream could not be decoded, e.g. ErrGap for bytes missing from capture or error
+	// of malformed PDU. Decoding continues after it.
+	Err error
+}
+
+// Decode reads capture in pcap or pcapng format from r and calls fn with PDUs of TCP connections having
+// one of ports on either side, DefaultPort if none given, in order of capture.
+//
+// Decoding stops at error of fn, which is returned, or of reading capture.
+func Decode(r io.Reader, ports []uint16, fn func(Record) error) error {
+	packets, err := newPacketReader(r)
+	if err != nil {
+		return err
+	}
+
+	if len(ports) == 0 {
+		ports = []uint16{DefaultPort}
+	}
+	filter := make(map[uint16]bool, len(ports))
+	for _, port := range ports {
+		filter[port] = true
+	}
+
+	streams := make(map[flow]*stream)
+	for {
+		p, err := packets.next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+
+		seg, ok := parseSegment(p.linkType, p.data)
+		if !ok || (!filter[seg.src.Port()] && !filter[seg.dst.Port()]) {
+			continue
+		}
+
+		key := flow{src: seg.src, dst: seg.dst}
+		s := streams[key]
+		if s == nil {
+			s = &stream{flow: key}
+			streams[key] = s
+		}
+
+		if err = s.add(seg, fn, Record{Time: p.time, Src: seg.src, Dst: seg.dst}); err != nil {
+			return err
+		}
+		if seg.flags&(tcpFlagFIN|tcpFlagRST) != 0 {
+			delete(streams, key)
+		}
+	}
+}
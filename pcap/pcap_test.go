@@ -0,0 +1,259 @@
+package pcap
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	esme = netip.MustParseAddrPort("10.0.0.1:40000")
+	smsc = netip.MustParseAddrPort("10.0.0.2:2775")
+)
+
+func marshal(p pdu.PDU) []byte {
+	b := pdu.NewBuffer(nil)
+	p.Marshal(b)
+	return b.Bytes()
+}
+
+func submitSM(seq int32, text string) []byte {
+	p := pdu.NewSubmitSM().(*pdu.SubmitSM)
+	p.SequenceNumber = seq
+	_ = p.DestAddr.SetAddress("84901234567")
+	_ = p.Message.SetMessageWithEncoding(text, data.GSM7BIT)
+	return marshal(p)
+}
+
+func submitSMResp(seq int32, id string) []byte {
+	p := pdu.NewSubmitSMResp().(*pdu.SubmitSMResp)
+	p.SequenceNumber = seq
+	p.MessageID = id
+	return marshal(p)
+}
+
+// tcpSegment returns TCP segment with IP header, IPv4 or IPv6 by addresses.
+func tcpSegment(src, dst netip.AddrPort, seq uint32, flags byte, payload []byte) []byte {
+	tcp := make([]byte, 20, 20+len(payload))
+	binary.BigEndian.PutUint16(tcp, src.Port())
+	binary.BigEndian.PutUint16(tcp[2:], dst.Port())
+	binary.BigEndian.PutUint32(tcp[4:], seq)
+	tcp[12], tcp[13] = 5<<4, flags|0x10
+	tcp = append(tcp, payload...)
+
+	if src.Addr().Is4() {
+		ip := make([]byte, 20, 20+len(tcp))
+		ip[0] = 0x45
+		binary.BigEndian.PutUint16(ip[2:], uint16(20+len(tcp)))
+		ip[8], ip[9] = 64, protocolTCP
+		copy(ip[12:], src.Addr().AsSlice())
+		copy(ip[16:], dst.Addr().AsSlice())
+		return append(ip, tcp...)
+	}
+
+	ip := make([]byte, 40, 40+len(tcp))
+	ip[0] = 0x60
+	binary.BigEndian.PutUint16(ip[4:], uint16(len(tcp)))
+	ip[6], ip[7] = protocolTCP, 64
+	copy(ip[8:], src.Addr().AsSlice())
+	copy(ip[24:], dst.Addr().AsSlice())
+	return append(ip, tcp...)
+}
+
+func ethernet(ip []byte) []byte {
+	frame := make([]byte, 14, 14+len(ip)+4)
+	binary.BigEndian.PutUint16(frame[12:], etherTypeIPv4)
+	// padding after IP packet must be ignored
+	return append(append(frame, ip...), 0, 0, 0, 0)
+}
+
+type capture struct {
+	frames [][]byte
+	times  []time.Time
+}
+
+func (c *capture) add(t time.Time, frame []byte) {
+	c.frames, c.times = append(c.frames, frame), append(c.times, t)
+}
+
+// pcap returns classic capture in little endian with microsecond timestamps.
+func (c *capture) pcap(linkType uint32) []byte {
+	var b bytes.Buffer
+	header := make([]byte, 24)
+	binary.LittleEndian.PutUint32(header, 0xA1B2C3D4)
+	binary.LittleEndian.PutUint16(header[4:], 2)
+	binary.LittleEndian.PutUint16(header[6:], 4)
+	binary.LittleEndian.PutUint32(header[16:], 65535)
+	binary.LittleEndian.PutUint32(header[20:], linkType)
+	b.Write(header)
+
+	for i, frame := range c.frames {
+		record := make([]byte, 16)
+		binary.LittleEndian.PutUint32(record, uint32(c.times[i].Unix()))
+		binary.LittleEndian.PutUint32(record[4:], uint32(c.times[i].Nanosecond()/1000))
+		binary.LittleEndian.PutUint32(record[8:], uint32(len(frame)))
+		binary.LittleEndian.PutUint32(record[12:], uint32(len(frame)))
+		b.Write(record)
+		b.Write(frame)
+	}
+	return b.Bytes()
+}
+
+// pcapng returns capture in big endian with nanosecond timestamps.
+func (c *capture) pcapng(linkType uint16) []byte {
+	var b bytes.Buffer
+	block := func(blockType uint32, body []byte) {
+		for len(body)%4 != 0 {
+			body = append(body, 0)
+		}
+		length := uint32(12 + len(body))
+		_ = binary.Write(&b, binary.BigEndian, []uint32{blockType, length})
+		b.Write(body)
+		_ = binary.Write(&b, binary.BigEndian, length)
+	}
+
+	shb := make([]byte, 16)
+	binary.BigEndian.PutUint32(shb, byteOrderMagic)
+	binary.BigEndian.PutUint16(shb[4:], 1)
+	binary.BigEndian.PutUint64(shb[8:], ^uint64(0))
+	block(blockSectionHeader, shb)
+
+	idb := make([]byte, 8, 20)
+	binary.BigEndian.PutUint16(idb, linkType)
+	// if_tsresol of nanoseconds and end of options
+	idb = append(idb, 0, 9, 0, 1, 9, 0, 0, 0, 0, 0, 0, 0)
+	block(blockInterface, idb)
+
+	for i, frame := range c.frames {
+		ts := uint64(c.times[i].UnixNano())
+		epb := make([]byte, 20, 20+len(frame))
+		binary.BigEndian.PutUint32(epb[4:], uint32(ts>>32))
+		binary.BigEndian.PutUint32(epb[8:], uint32(ts))
+		binary.BigEndian.PutUint32(epb[12:], uint32(len(frame)))
+		binary.BigEndian.PutUint32(epb[16:], uint32(len(frame)))
+		block(blockEnhancedPacket, append(epb, frame...))
+	}
+	return b.Bytes()
+}
+
+func decodeAll(t *testing.T, file []byte, ports ...uint16) (records []Record) {
+	require.Nil(t, Decode(bytes.NewReader(file), ports, func(r Record) error {
+		records = append(records, r)
+		return nil
+	}))
+	return
+}
+
+func TestDecodePcap(t *testing.T) {
+	start := time.Date(2026, 10, 15, 9, 30, 0, 0, time.UTC)
+	at := func(ms int) time.Time { return start.Add(time.Duration(ms) * time.Millisecond) }
+
+	first, second := submitSM(1, "Hello"), submitSM(2, "World")
+	stream := append(append([]byte(nil), first...), second...)
+	cut := len(first) + 10
+
+	var c capture
+	c.add(at(0), ethernet(tcpSegment(esme, smsc, 999, tcpFlagSYN, nil)))
+	c.add(at(1), ethernet(tcpSegment(smsc, esme, 4999, tcpFlagSYN, nil)))
+	// second part of the stream arrives first, and the first one is retransmitted
+	c.add(at(2), ethernet(tcpSegment(esme, smsc, 1000+uint32(cut), 0, stream[cut:])))
+	c.add(at(3), ethernet(tcpSegment(esme, smsc, 1000, 0, stream[:cut])))
+	c.add(at(4), ethernet(tcpSegment(esme, smsc, 1000, 0, stream[:cut])))
+	// other traffic is ignored
+	c.add(at(5), ethernet(tcpSegment(netip.MustParseAddrPort("10.0.0.1:40001"),
+		netip.MustParseAddrPort("10.0.0.3:443"), 1, 0, []byte("GET / HTTP/1.1\r\n"))))
+	c.add(at(6), ethernet(tcpSegment(smsc, esme, 5000, 0, append(submitSMResp(1, "a"), submitSMResp(2, "b")...))))
+
+	records := decodeAll(t, c.pcap(LinkTypeEthernet))
+	require.Len(t, records, 4)
+	for _, r := range records {
+		require.Nil(t, r.Err)
+	}
+
+	require.Equal(t, esme, records[0].Src)
+	require.Equal(t, smsc, records[0].Dst)
+	require.True(t, at(3).Equal(records[0].Time))
+	require.Equal(t, `submit_sm seq=1 status=ESME_ROK service_type="" source_addr=0/0/"" dest_addr=0/0/"84901234567" `+
+		`esm_class=0x00 protocol_id=0x00 priority_flag=0x00 schedule_delivery_time="" validity_period="" `+
+		`registered_delivery=0x00 replace_if_present_flag=0x00 short_message="Hello" data_coding=0x00`,
+		pdu.Sprint(records[0].PDU))
+	require.EqualValues(t, 2, records[1].PDU.GetHeader().SequenceNumber)
+
+	require.Equal(t, smsc, records[2].Src)
+	require.Equal(t, `submit_sm_resp seq=1 status=ESME_ROK message_id="a"`, pdu.Sprint(records[2].PDU))
+	require.Equal(t, `submit_sm_resp seq=2 status=ESME_ROK message_id="b"`, pdu.Sprint(records[3].PDU))
+
+	// not on the given port
+	require.Empty(t, decodeAll(t, c.pcap(LinkTypeEthernet), 2776))
+}
+
+func TestDecodePcapng(t *testing.T) {
+	esme6 := netip.MustParseAddrPort("[2001:db8::1]:40000")
+	smsc6 := netip.MustParseAddrPort("[2001:db8::2]:3000")
+	ts := time.Date(2026, 10, 15, 9, 30, 0, 123456789, time.UTC)
+
+	first := submitSM(1, "Hello")
+	var c capture
+	// capture started in the middle of PDU
+	c.add(ts, tcpSegment(esme6, smsc6, 77, 0, first[20:]))
+	c.add(ts, tcpSegment(esme6, smsc6, 77+uint32(len(first)-20), 0, submitSM(2, "World")))
+
+	records := decodeAll(t, c.pcapng(LinkTypeRaw), 3000)
+	require.Len(t, records, 1)
+	require.Nil(t, records[0].Err)
+	require.True(t, ts.Equal(records[0].Time))
+	require.Equal(t, esme6, records[0].Src)
+	require.EqualValues(t, 2, records[0].PDU.GetHeader().SequenceNumber)
+}
+
+func TestDecodeGap(t *testing.T) {
+	var c capture
+	now := time.Now()
+	c.add(now, ethernet(tcpSegment(esme, smsc, 999, tcpFlagSYN, nil)))
+	c.add(now, ethernet(tcpSegment(esme, smsc, 1000, 0, submitSM(1, "lost")[:30])))
+
+	// segments after the missing one, more than are buffered
+	seq, n := uint32(2000), 0
+	for size := 0; size <= maxPending; n++ {
+		b := submitSM(int32(n+2), string(bytes.Repeat([]byte("x"), 160)))
+		c.add(now, ethernet(tcpSegment(esme, smsc, seq, 0, b)))
+		seq, size = seq+uint32(len(b)), size+len(b)
+	}
+
+	records := decodeAll(t, c.pcap(LinkTypeEthernet))
+	require.ErrorIs(t, records[0].Err, ErrGap)
+	require.Nil(t, records[1].Err)
+	require.EqualValues(t, 2, records[1].PDU.GetHeader().SequenceNumber)
+	require.Len(t, records, n+1)
+	require.EqualValues(t, n+1, records[n].PDU.GetHeader().SequenceNumber)
+}
+
+func TestDecodeErrors(t *testing.T) {
+	err := Decode(bytes.NewReader([]byte("not a capture at all")), nil, nil)
+	require.ErrorIs(t, err, ErrFormat)
+
+	var c capture
+	c.add(time.Now(), ethernet(tcpSegment(esme, smsc, 1, 0, append(submitSMResp(1, "a"), submitSMResp(2, "b")...))))
+	file := c.pcap(LinkTypeEthernet)
+
+	stop := errors.New("stop")
+	calls := 0
+	err = Decode(bytes.NewReader(file), nil, func(Record) error {
+		calls++
+		return stop
+	})
+	require.ErrorIs(t, err, stop)
+	require.Equal(t, 1, calls)
+
+	err = Decode(bytes.NewReader(file[:len(file)-5]), nil, func(Record) error { return nil })
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
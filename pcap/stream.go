@@ -0,0 +1,190 @@
+package pcap
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/netip"
+	"sort"
+
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+)
+
+// ErrGap indicates bytes of TCP stream were not captured, PDUs around the gap are lost.
+var ErrGap = errors.New("pcap: gap in TCP stream")
+
+// maxPending limits out of order bytes buffered per stream before the missing ones are given up.
+const maxPending = 1 << 20
+
+type flow struct {
+	src, dst netip.AddrPort
+}
+
+// stream reassembles one direction of TCP connection.
+type stream struct {
+	flow
+
+	// next is sequence number of the next expected byte.
+	next    uint32
+	started bool
+
+	// pending are out of order segments by sequence number.
+	pending     map[uint32][]byte
+	pendingSize int
+
+	buf []byte
+
+	// lost is set when stream is out of sync with PDU boundaries, until a PDU is decoded again.
+	lost bool
+}
+
+// add appends segment to stream and emits its complete PDUs as r.
+func (s *stream) add(seg segment, emit func(Record) error, r Record) error {
+	if seg.flags&tcpFlagSYN != 0 {
+		s.next, s.started = seg.seq+1, true
+		s.buf, s.pending, s.pendingSize, s.lost = s.buf[:0], nil, 0, false
+		return nil
+	}
+	if len(seg.payload) == 0 {
+		return nil
+	}
+	if !s.started {
+		// capture started in the middle of connection, maybe of PDU
+		s.next, s.started, s.lost = seg.seq, true, true
+	}
+
+	seq, payload := seg.seq, seg.payload
+	if diff := int32(seq - s.next); diff < 0 {
+		// retransmission, possibly overlapping new bytes
+		if int(-diff) >= len(payload) {
+			return nil
+		}
+		payload = payload[-diff:]
+	} else if diff > 0 {
+		if s.pending == nil {
+			s.pending = make(map[uint32][]byte)
+		}
+		if old, ok := s.pending[seq]; !ok || len(old) < len(payload) {
+			s.pendingSize += len(payload) - len(old)
+			s.pending[seq] = append([]byte(nil), payload...)
+		}
+		if s.pendingSize <= maxPending {
+			return nil
+		}
+		return s.skipGap(emit, r)
+	}
+
+	s.append(payload)
+	s.drain()
+	if s.lost {
+		s.resync()
+	}
+	return s.decode(emit, r)
+}
+
+func (s *stream) append(payload []byte) {
+	s.buf = append(s.buf, payload...)
+	s.next += uint32(len(payload))
+}
+
+// drain appends pending segments which became contiguous.
+func (s *stream) drain() {
+	for progress := true; progress && len(s.pending) > 0; {
+		progress = false
+		for seq, payload := range s.pending {
+			diff := int32(seq - s.next)
+			if diff > 0 {
+				continue
+			}
+			delete(s.pending, seq)
+			s.pendingSize -= len(payload)
+			if int(-diff) < len(payload) {
+				s.append(payload[-diff:])
+			}
+			progress = true
+		}
+	}
+}
+
+// skipGap gives up missing bytes, resuming at the first pending segment.
+func (s *stream) skipGap(emit func(Record) error, r Record) error {
+	seqs := make([]uint32, 0, len(s.pending))
+	for seq := range s.pending {
+		seqs = append(seqs, seq)
+	}
+	sort.Slice(seqs, func(i, j int) bool { return int32(seqs[i]-s.next) < int32(seqs[j]-s.next) })
+
+	s.next, s.buf, s.lost = seqs[0], s.buf[:0], true
+	s.drain()
+
+	r.Err = fmt.Errorf("%w: %s > %s", ErrGap, s.src, s.dst)
+	if err := emit(r); err != nil {
+		return err
+	}
+	s.resync()
+	return s.decode(emit, r)
+}
+
+// resync drops bytes until something looking like PDU header, as the stream is at unknown offset.
+// Last bytes are kept if they may be start of header.
+func (s *stream) resync() {
+	for i := 0; i+16 <= len(s.buf); i++ {
+		if plausibleHeader(s.buf[i:]) {
+			s.buf = s.buf[i:]
+			return
+		}
+	}
+	if len(s.buf) > 15 {
+		s.buf = s.buf[len(s.buf)-15:]
+	}
+}
+
+// decode emits complete PDUs of buffer.
+func (s *stream) decode(emit func(Record) error, r Record) error {
+	for len(s.buf) >= 4 {
+		length := binary.BigEndian.Uint32(s.buf)
+		if length < 16 || length > data.MAX_PDU_LEN {
+			if !s.lost {
+				s.lost = true
+				r.PDU, r.Err = nil, fmt.Errorf("pcap: invalid command_length %d in %s > %s", length, s.src, s.dst)
+				if err := emit(r); err != nil {
+					return err
+				}
+			}
+			s.buf = s.buf[1:]
+			s.resync()
+			continue
+		}
+		if uint32(len(s.buf)) < length {
+			break
+		}
+
+		r.PDU, r.Err = nil, nil
+		p, err := pdu.Decode(s.buf[:length])
+		if err != nil {
+			r.Err = fmt.Errorf("pcap: %s > %s: %w", s.src, s.dst, err)
+		} else {
+			r.PDU = p
+		}
+		s.buf, s.lost = s.buf[length:], false
+		if err = emit(r); err != nil {
+			return err
+		}
+	}
+
+	if len(s.buf) == 0 {
+		s.buf = nil
+	}
+	return nil
+}
+
+// plausibleHeader reports whether b starts with header of known command.
+func plausibleHeader(b []byte) bool {
+	length := binary.BigEndian.Uint32(b)
+	if length < 16 || length > data.MAX_PDU_LEN {
+		return false
+	}
+	_, err := pdu.CreatePDUFromCmdID(data.CommandIDType(binary.BigEndian.Uint32(b[4:])))
+	return err == nil
+}
@@ -71,9 +71,12 @@ func (c *BindResp) GetResponse() PDU {
 // Marshal implements PDU interface.
 func (c *BindResp) Marshal(b *ByteBuffer) {
 	c.base.marshal(b, func(w *ByteBuffer) {
-		w.Grow(len(c.SystemID) + 1)
+		// body is not returned on error, except bind_transceiver_resp
+		if c.CommandID == data.BIND_TRANSCEIVER_RESP || c.CommandStatus == data.ESME_ROK {
+			w.Grow(len(c.SystemID) + 1)
 
-		_ = w.WriteCString(c.SystemID)
+			_ = w.WriteCString(c.SystemID)
+		}
 	})
 }
 
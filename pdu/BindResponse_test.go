@@ -53,4 +53,16 @@ func TestBindResponse(t *testing.T) {
 			data.BIND_TRANSCEIVER_RESP,
 		)
 	})
+
+	t.Run("rejected", func(t *testing.T) {
+		v := NewBindTransmitterResp().(*BindResp)
+		v.SequenceNumber = 13
+		v.CommandStatus = data.ESME_RINVPASWD
+
+		validate(t,
+			v,
+			"00000010800000020000000e0000000d",
+			data.BIND_TRANSMITTER_RESP,
+		)
+	})
 }
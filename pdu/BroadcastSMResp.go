@@ -0,0 +1,57 @@
+package pdu
+
+import (
+	"github.com/linxGnu/gosmpp/data"
+)
+
+// BroadcastSMResp PDU.
+type BroadcastSMResp struct {
+	base
+	MessageID string
+}
+
+// NewBroadcastSMResp returns new BroadcastSMResp.
+func NewBroadcastSMResp() PDU {
+	c := &BroadcastSMResp{
+		base:      newBase(),
+		MessageID: data.DFLT_MSGID,
+	}
+	c.CommandID = data.BROADCAST_SM_RESP
+	return c
+}
+
+// NewBroadcastSMRespFromReq returns new BroadcastSMResp.
+func NewBroadcastSMRespFromReq(req *BroadcastSM) PDU {
+	c := NewBroadcastSMResp().(*BroadcastSMResp)
+	if req != nil {
+		c.SequenceNumber = req.SequenceNumber
+	}
+	return c
+}
+
+// CanResponse implements PDU interface.
+func (c *BroadcastSMResp) CanResponse() bool {
+	return false
+}
+
+// GetResponse implements PDU interface.
+func (c *BroadcastSMResp) GetResponse() PDU {
+	return nil
+}
+
+// Marshal implements PDU interface.
+func (c *BroadcastSMResp) Marshal(b *ByteBuffer) {
+	c.base.marshal(b, func(b *ByteBuffer) {
+		b.Grow(len(c.MessageID) + 1)
+
+		_ = b.WriteCString(c.MessageID)
+	})
+}
+
+// Unmarshal implements PDU interface.
+func (c *BroadcastSMResp) Unmarshal(b *ByteBuffer) error {
+	return c.base.unmarshal(b, func(b *ByteBuffer) (err error) {
+		c.MessageID, err = b.ReadCString()
+		return
+	})
+}
@@ -0,0 +1,26 @@
+package pdu
+
+import (
+	"testing"
+
+	"github.com/linxGnu/gosmpp/data"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBroadcastSMResp(t *testing.T) {
+	req := NewBroadcastSM().(*BroadcastSM)
+	req.SequenceNumber = 13
+
+	v := NewBroadcastSMRespFromReq(req).(*BroadcastSMResp)
+	require.False(t, v.CanResponse())
+	require.Nil(t, v.GetResponse())
+
+	v.MessageID = "football"
+
+	validate(t,
+		v,
+		"0000001980000111000000000000000d666f6f7462616c6c00",
+		data.BROADCAST_SM_RESP,
+	)
+}
@@ -0,0 +1,35 @@
+package pdu
+
+import (
+	"testing"
+
+	"github.com/linxGnu/gosmpp/data"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBroadcastSM(t *testing.T) {
+	v := NewBroadcastSM().(*BroadcastSM)
+	require.True(t, v.CanResponse())
+	v.SequenceNumber = 13
+
+	validate(t,
+		v.GetResponse(),
+		"0000001180000111000000000000000d00",
+		data.BROADCAST_SM_RESP,
+	)
+
+	v.ServiceType = "abc"
+	_ = v.SourceAddr.SetAddress("Alicer")
+	v.SourceAddr.SetTon(28)
+	v.SourceAddr.SetNpi(29)
+	v.MessageID = "away"
+	v.PriorityFlag = 1
+	v.DataCoding = 8
+
+	validate(t,
+		v,
+		"0000002800000111000000000000000d616263001c1d416c69636572006177617900010000000800",
+		data.BROADCAST_SM,
+	)
+}
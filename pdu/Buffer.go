@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/binary"
 	"fmt"
+	"io"
 
 	"github.com/linxGnu/gosmpp/data"
 )
@@ -32,6 +33,9 @@ var (
 // ByteBuffer wraps over bytes.Buffer with additional features.
 type ByteBuffer struct {
 	*bytes.Buffer
+
+	// limits of PDU being decoded from the buffer
+	limits DecodeLimits
 }
 
 // NewBuffer create new buffer from preallocated buffer array.
@@ -43,6 +47,8 @@ func NewBuffer(inp []byte) *ByteBuffer {
 }
 
 // ReadN read n-bytes from buffer.
+//
+// Returned bytes are copied, so decoded PDUs could retain them while the buffer is reused.
 func (c *ByteBuffer) ReadN(n int) (r []byte, err error) {
 	if n > 0 {
 		if c.Len() >= n { // optimistic branching
@@ -57,11 +63,10 @@ func (c *ByteBuffer) ReadN(n int) (r []byte, err error) {
 
 // ReadShort reads short from buffer.
 func (c *ByteBuffer) ReadShort() (r int16, err error) {
-	v, err := c.ReadN(SizeShort)
-	if err == nil {
-		r = int16(endianese.Uint16(v))
+	if c.Len() < SizeShort {
+		return 0, ErrBufferNotEnoughByteToRead
 	}
-	return
+	return int16(endianese.Uint16(c.Next(SizeShort))), nil
 }
 
 // WriteShort writes short to buffer.
@@ -73,11 +78,10 @@ func (c *ByteBuffer) WriteShort(v int16) {
 
 // ReadInt reads int from buffer.
 func (c *ByteBuffer) ReadInt() (r int32, err error) {
-	v, err := c.ReadN(SizeInt)
-	if err == nil {
-		r = int32(endianese.Uint32(v))
+	if c.Len() < SizeInt {
+		return 0, ErrBufferNotEnoughByteToRead
 	}
-	return
+	return int32(endianese.Uint32(c.Next(SizeInt))), nil
 }
 
 // WriteInt writes int to buffer.
@@ -121,11 +125,12 @@ func (c *ByteBuffer) WriteCStringWithEnc(s string, enc data.Encoding) error {
 
 // ReadCString read c-string.
 func (c *ByteBuffer) ReadCString() (st string, err error) {
-	buf, err := c.ReadBytes(0)
-	if err == nil && len(buf) > 0 { // optimistic branching
-		st = string(buf[:len(buf)-1])
+	i := bytes.IndexByte(c.Bytes(), 0)
+	if i < 0 {
+		c.Next(c.Len())
+		return "", io.EOF
 	}
-	return
+	return string(c.Next(i + 1)[:i]), nil
 }
 
 // HexDump returns hex dump.
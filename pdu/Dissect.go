@@ -0,0 +1,437 @@
+package pdu
+
+import (
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/linxGnu/gosmpp/data"
+)
+
+// Names of values as shown by Wireshark.
+var (
+	tonNames = []string{"Unknown", "International", "National", "Network specific", "Subscriber number",
+		"Alphanumeric", "Abbreviated"}
+
+	npiNames = map[byte]string{0: "Unknown", 1: "ISDN (E163/E164)", 3: "Data (X.121)", 4: "Telex (F.69)",
+		6: "Land mobile (E.212)", 8: "National", 9: "Private", 10: "ERMES", 14: "Internet (IP)", 18: "WAP client Id"}
+
+	messageStateNames = []string{"SCHEDULED", "ENROUTE", "DELIVERED", "EXPIRED", "DELETED", "UNDELIVERABLE",
+		"ACCEPTED", "UNKNOWN", "REJECTED", "SKIPPED"}
+
+	messagingModeNames = []string{"Default SMSC mode", "Datagram mode", "Forward mode", "Store and forward mode"}
+
+	messageTypeNames = map[byte]string{0: "Default message type", 1: "SMSC delivery receipt",
+		2: "Delivery acknowledgement", 4: "Manual/user acknowledgement", 6: "Conversation abort",
+		8: "Intermediate delivery notification"}
+
+	gsmFeaturesNames = []string{"No specific features selected", "UDHI indicator", "Reply path",
+		"UDHI and reply path"}
+
+	receiptNames = []string{"No SMSC delivery receipt requested", "Delivery receipt requested (for success or failure)",
+		"Delivery receipt requested (for failure)", "Reserved"}
+
+	acknowledgementNames = []string{"No recipient SME acknowledgement requested", "SME delivery acknowledgement requested",
+		"SME manual/user acknowledgement requested", "Both delivery and manual/user acknowledgement requested"}
+
+	notificationNames = []string{"No intermediate notification requested", "Intermediate notification requested"}
+)
+
+type tlvKind byte
+
+const (
+	tlvInt tlvKind = iota
+	tlvText
+	tlvBytes
+)
+
+// tlvFields are labels of values of TLVs as shown by Wireshark.
+var tlvFields = map[Tag]struct {
+	label string
+	kind  tlvKind
+}{
+	TagDestAddrSubunit:            {"Subunit destination", tlvInt},
+	TagDestNetworkType:            {"Destination network", tlvInt},
+	TagDestBearerType:             {"Destination bearer", tlvInt},
+	TagDestTelematicsID:           {"Telematic interworking (dest)", tlvInt},
+	TagSourceAddrSubunit:          {"Subunit origin", tlvInt},
+	TagSourceNetworkType:          {"Originator network", tlvInt},
+	TagSourceBearerType:           {"Originator bearer", tlvInt},
+	TagSourceTelematicsID:         {"Telematic interworking (orig)", tlvInt},
+	TagQosTimeToLive:              {"Validity period", tlvInt},
+	TagPayloadType:                {"Payload", tlvInt},
+	TagAdditionalStatusInfoText:   {"Information", tlvText},
+	TagReceiptedMessageID:         {"SMSC identifier", tlvText},
+	TagMsMsgWaitFacilities:        {"Indication", tlvInt},
+	TagPrivacyIndicator:           {"Privacy indicator", tlvInt},
+	TagSourceSubaddress:           {"Source Subaddress", tlvBytes},
+	TagDestSubaddress:             {"Destination Subaddress", tlvBytes},
+	TagUserMessageReference:       {"Message reference", tlvInt},
+	TagUserResponseCode:           {"Application response code", tlvInt},
+	TagSourcePort:                 {"Source port", tlvInt},
+	TagDestinationPort:            {"Destination port", tlvInt},
+	TagSarMsgRefNum:               {"SAR reference number", tlvInt},
+	TagLanguageIndicator:          {"Language", tlvInt},
+	TagSarTotalSegments:           {"SAR size", tlvInt},
+	TagSarSegmentSeqnum:           {"SAR sequence number", tlvInt},
+	TagScInterfaceVersion:         {"SMSC-supported version", tlvInt},
+	TagCallbackNumPresInd:         {"Presentation", tlvInt},
+	TagCallbackNumAtag:            {"Callback number - alphanumeric display tag", tlvBytes},
+	TagNumberOfMessages:           {"Number of messages", tlvInt},
+	TagCallbackNum:                {"Callback number", tlvBytes},
+	TagDpfResult:                  {"Delivery stored", tlvInt},
+	TagSetDpf:                     {"Request DPF set", tlvInt},
+	TagMsAvailabilityStatus:       {"Availability", tlvInt},
+	TagNetworkErrorCode:           {"Network error code", tlvBytes},
+	TagMessagePayload:             {"Payload", tlvBytes},
+	TagDeliveryFailureReason:      {"Delivery failure reason", tlvInt},
+	TagMoreMessagesToSend:         {"More messages?", tlvInt},
+	TagMessageStateOption:         {"Message state", tlvInt},
+	TagCongestionState:            {"Congestion State", tlvInt},
+	TagUssdServiceOp:              {"USSD service operation", tlvInt},
+	TagDisplayTime:                {"Display time", tlvInt},
+	TagSmsSignal:                  {"SMS signal", tlvInt},
+	TagMsValidity:                 {"Validity info", tlvInt},
+	TagAlertOnMessageDelivery:     {"Alert on delivery", tlvInt},
+	TagItsReplyType:               {"Reply method", tlvInt},
+	TagItsSessionInfo:             {"Session info", tlvBytes},
+	TagBroadcastChannelIndicator:  {"Broadcast Channel Indicator", tlvInt},
+	TagBroadcastContentType:       {"Broadcast Content Type", tlvBytes},
+	TagBroadcastContentTypeInfo:   {"Broadcast Content Type Info", tlvBytes},
+	TagBroadcastMessageClass:      {"Broadcast Message Class", tlvInt},
+	TagBroadcastRepNum:            {"Broadcast Message - Number of repetitions requested", tlvInt},
+	TagBroadcastFrequencyInterval: {"Broadcast Message - frequency interval", tlvBytes},
+	TagBroadcastAreaIdentifier:    {"Broadcast Message - Area Identifier", tlvBytes},
+	TagBroadcastErrorStatus:       {"Broadcast Message - Error Status", tlvInt},
+	TagBroadcastAreaSuccess:       {"Broadcast Message - Area Success", tlvInt},
+	TagBroadcastEndTime:           {"Broadcast Message - End Time", tlvText},
+	TagBroadcastServiceGroup:      {"Broadcast Message - Service Group", tlvBytes},
+}
+
+// Dissect returns p in layout of Wireshark's SMPP dissector: a field per line with the same field names and
+// nesting, so that traffic logs read like packet details of Wireshark. Password is masked. Length is
+// command_length of the header, set once PDU is marshaled or parsed.
+//
+//	Short Message Peer to Peer, Command: Submit_sm, Seq: 7, Len: 61
+//	    Length: 61
+//	    Operation: Submit_sm (0x00000004)
+//	    Sequence #: 7
+//	    Service type: (Default)
+//	    Type of number (originator): Alphanumeric (0x05)
+//	    ...
+func Dissect(p PDU) string {
+	h := p.GetHeader()
+	d := &dissector{}
+
+	command := commandName(h.CommandID)
+	d.line(0, "Short Message Peer to Peer, Command: %s, Seq: %d, Len: %d", command, h.SequenceNumber, h.CommandLength)
+	d.line(1, "Length: %d", h.CommandLength)
+	d.line(1, "Operation: %s (0x%08x)", command, uint32(h.CommandID))
+	// result is shown for responses only, which have the most significant bit of command_id set
+	if uint32(h.CommandID)&0x80000000 != 0 {
+		d.line(1, "Result: %s (0x%08x)", statusName(h.CommandStatus), uint32(h.CommandStatus))
+	}
+	d.line(1, "Sequence #: %d", h.SequenceNumber)
+
+	switch v := p.(type) {
+	case *BindRequest:
+		d.text("System ID", v.SystemID)
+		d.password(v.Password)
+		d.text("System type", v.SystemType)
+		d.line(1, "Version (if): 0x%02x", v.InterfaceVersion)
+		d.ton("Type of number", v.AddressRange.Ton)
+		d.npi("Numbering plan indicator", v.AddressRange.Npi)
+		d.text("Address", v.AddressRange.AddressRange)
+	case *BindResp:
+		d.text("System ID", v.SystemID)
+	case *Outbind:
+		d.text("System ID", v.SystemID)
+		d.password(v.Password)
+	case *SubmitSM:
+		d.serviceType(v.ServiceType)
+		d.address("originator", "Originator address", v.SourceAddr)
+		d.address("recipient", "Recipient address", v.DestAddr)
+		d.submit(v.EsmClass, v.ProtocolID, v.PriorityFlag, v.ScheduleDeliveryTime, v.ValidityPeriod,
+			v.RegisteredDelivery, v.ReplaceIfPresentFlag, &v.Message)
+	case *DeliverSM:
+		d.serviceType(v.ServiceType)
+		d.address("originator", "Originator address", v.SourceAddr)
+		d.address("recipient", "Recipient address", v.DestAddr)
+		d.submit(v.EsmClass, v.ProtocolID, v.PriorityFlag, v.ScheduleDeliveryTime, v.ValidityPeriod,
+			v.RegisteredDelivery, v.ReplaceIfPresentFlag, &v.Message)
+	case *SubmitMulti:
+		d.serviceType(v.ServiceType)
+		d.address("originator", "Originator address", v.SourceAddr)
+		dests := v.DestAddrs.Get()
+		d.line(1, "Number of destinations: %d", len(dests))
+		for i := range dests {
+			if dests[i].IsDistributionList() {
+				d.line(1, "Destination flag: Distribution list (0x02)")
+				d.text("Distribution list", dests[i].DistributionList().Name())
+			} else {
+				d.line(1, "Destination flag: SME address (0x01)")
+				d.address("recipient", "Recipient address", dests[i].Address())
+			}
+		}
+		d.submit(v.EsmClass, v.ProtocolID, v.PriorityFlag, v.ScheduleDeliveryTime, v.ValidityPeriod,
+			v.RegisteredDelivery, v.ReplaceIfPresentFlag, &v.Message)
+	case *SubmitMultiResp:
+		d.text("Message id.", v.MessageID)
+		smes := v.UnsuccessSMEs.Get()
+		d.line(1, "Number of unsuccessful: %d", len(smes))
+		for i := range smes {
+			d.address("recipient", "Recipient address", smes[i].Address)
+			status := smes[i].ErrorStatusCode()
+			d.line(1, "Error status code: %s (0x%08x)", statusName(status), uint32(status))
+		}
+	case *DataSM:
+		d.serviceType(v.ServiceType)
+		d.address("originator", "Originator address", v.SourceAddr)
+		d.address("recipient", "Recipient address", v.DestAddr)
+		d.esmClass(v.EsmClass)
+		d.registeredDelivery(v.RegisteredDelivery)
+		d.line(1, "Data coding: 0x%02x", v.DataCoding)
+	case *QuerySM:
+		d.text("Message id.", v.MessageID)
+		d.address("originator", "Originator address", v.SourceAddr)
+	case *QuerySMResp:
+		d.text("Message id.", v.MessageID)
+		d.text("Final date", v.FinalDate)
+		d.line(1, "Message state: %s (%d)", nameOf(messageStateNames, v.MessageState), v.MessageState)
+		d.line(1, "Error code: %d", v.ErrorCode)
+	case *CancelSM:
+		d.serviceType(v.ServiceType)
+		d.text("Message id.", v.MessageID)
+		d.address("originator", "Originator address", v.SourceAddr)
+		d.address("recipient", "Recipient address", v.DestAddr)
+	case *ReplaceSM:
+		d.text("Message id.", v.MessageID)
+		d.address("originator", "Originator address", v.SourceAddr)
+		d.text("Scheduled delivery time", v.ScheduleDeliveryTime)
+		d.text("Validity period", v.ValidityPeriod)
+		d.registeredDelivery(v.RegisteredDelivery)
+		d.message(&v.Message)
+	case *AlertNotification:
+		d.address("originator", "Originator address", v.SourceAddr)
+		d.address("ESME", "ESME address", v.EsmeAddr)
+	case *BroadcastSM:
+		d.serviceType(v.ServiceType)
+		d.address("originator", "Originator address", v.SourceAddr)
+		d.text("Message id.", v.MessageID)
+		d.line(1, "Priority level: %d", v.PriorityFlag)
+		d.text("Scheduled delivery time", v.ScheduleDeliveryTime)
+		d.text("Validity period", v.ValidityPeriod)
+		d.line(1, "Replace: %d", v.ReplaceIfPresentFlag)
+		d.line(1, "Data coding: 0x%02x", v.DataCoding)
+		d.line(1, "Predefined message: %d", v.SmDefaultMsgID)
+	case *SubmitSMResp:
+		d.text("Message id.", v.MessageID)
+	case *DeliverSMResp:
+		d.text("Message id.", v.MessageID)
+	case *DataSMResp:
+		d.text("Message id.", v.MessageID)
+	case *BroadcastSMResp:
+		d.text("Message id.", v.MessageID)
+	}
+
+	if params := optionalParameters(p); len(params) > 0 {
+		tags := make([]Tag, 0, len(params))
+		for tag := range params {
+			tags = append(tags, tag)
+		}
+		sort.Slice(tags, func(i, j int) bool { return tags[i] < tags[j] })
+
+		d.line(1, "Optional parameters")
+		for _, tag := range tags {
+			d.tlv(tag, params[tag].Data)
+		}
+	}
+	return strings.TrimSuffix(d.b.String(), "\n")
+}
+
+type dissector struct {
+	b strings.Builder
+}
+
+func (d *dissector) line(depth int, format string, args ...interface{}) {
+	d.b.WriteString(strings.Repeat("    ", depth))
+	d.b.WriteString(strings.TrimRight(fmt.Sprintf(format, args...), " "))
+	d.b.WriteByte('\n')
+}
+
+func (d *dissector) text(label, v string) {
+	d.line(1, "%s: %s", label, v)
+}
+
+func (d *dissector) password(v string) {
+	if v != "" {
+		v = "***"
+	}
+	d.text("Password", v)
+}
+
+func (d *dissector) serviceType(v string) {
+	if v == "" {
+		v = "(Default)"
+	}
+	d.text("Service type", v)
+}
+
+func (d *dissector) ton(label string, v byte) {
+	d.line(1, "%s: %s (0x%02x)", label, nameOf(tonNames, v), v)
+}
+
+func (d *dissector) npi(label string, v byte) {
+	name, ok := npiNames[v]
+	if !ok {
+		name = "Unknown"
+	}
+	d.line(1, "%s: %s (0x%02x)", label, name, v)
+}
+
+func (d *dissector) address(role, label string, a Address) {
+	d.ton("Type of number ("+role+")", a.Ton())
+	d.npi("Numbering plan indicator ("+role+")", a.Npi())
+	d.text(label, a.Address())
+}
+
+func (d *dissector) submit(esmClass, protocolID, priority byte, schedule, validity string, registeredDelivery,
+	replace byte, m *ShortMessage) {
+	d.esmClass(esmClass)
+	d.line(1, "Protocol id.: 0x%02x", protocolID)
+	d.line(1, "Priority level: %d", priority)
+	d.text("Scheduled delivery time", schedule)
+	d.text("Validity period", validity)
+	d.registeredDelivery(registeredDelivery)
+	d.line(1, "Replace: %d", replace)
+	d.message(m)
+}
+
+func (d *dissector) esmClass(v byte) {
+	d.line(1, "ESM class: 0x%02x", v)
+	d.bits(v, 0x03, "Messaging mode", nameOf(messagingModeNames, v&0x03))
+	d.bits(v, 0x3C, "Message type", messageTypeName(v&0x3C>>2))
+	d.bits(v, 0xC0, "GSM features", nameOf(gsmFeaturesNames, v>>6))
+}
+
+func (d *dissector) registeredDelivery(v byte) {
+	d.line(1, "Registered delivery: 0x%02x", v)
+	d.bits(v, 0x03, "Delivery receipt", nameOf(receiptNames, v&0x03))
+	d.bits(v, 0x0C, "Message type", nameOf(acknowledgementNames, v&0x0C>>2))
+	d.bits(v, 0x10, "Intermediate notif", nameOf(notificationNames, v&0x10>>4))
+}
+
+// bits writes field of v under mask as bits, e.g. "..00 00.. = Message type: Default message type (0x0)".
+func (d *dissector) bits(v, mask byte, label, name string) {
+	var b strings.Builder
+	for i := 7; i >= 0; i-- {
+		switch {
+		case mask&(1<<i) == 0:
+			b.WriteByte('.')
+		case v&(1<<i) != 0:
+			b.WriteByte('1')
+		default:
+			b.WriteByte('0')
+		}
+		if i == 4 {
+			b.WriteByte(' ')
+		}
+	}
+
+	shift := 0
+	for mask&(1<<shift) == 0 {
+		shift++
+	}
+	d.line(2, "%s = %s: %s (0x%x)", b.String(), label, name, (v&mask)>>shift)
+}
+
+func (d *dissector) message(m *ShortMessage) {
+	if !m.withoutDataCoding {
+		d.line(1, "Data coding: 0x%02x", m.DataCoding())
+	}
+	d.line(1, "Predefined message: %d", m.SmDefaultMsgID)
+
+	b, _ := m.GetMessageData()
+	udh := m.UDH()
+	length := len(b)
+	if len(udh) > 0 {
+		if raw, err := udh.MarshalBinary(); err == nil {
+			length += len(raw)
+			d.line(1, "User Data Header: %s", hex.EncodeToString(raw))
+		}
+	}
+	d.line(1, "Message length: %d", length)
+
+	enc := m.Encoding()
+	if enc != nil && enc != data.BINARY8BIT1 && enc != data.BINARY8BIT2 {
+		if text, err := m.GetMessage(); err == nil {
+			d.text("Message", text)
+			return
+		}
+	}
+	d.text("Message", hex.EncodeToString(b))
+}
+
+func (d *dissector) tlv(tag Tag, v []byte) {
+	d.line(2, "Optional parameter: %s (0x%04x)", tag, uint16(tag))
+	d.line(3, "Tag: 0x%04x", uint16(tag))
+	d.line(3, "Length: %d", len(v))
+
+	field, ok := tlvFields[tag]
+	if !ok {
+		d.line(3, "Value: %s", hex.EncodeToString(v))
+		return
+	}
+	switch {
+	case field.kind == tlvText:
+		if l := len(v); l > 0 && v[l-1] == 0 {
+			v = v[:l-1]
+		}
+		d.line(3, "%s: %s", field.label, v)
+	case field.kind == tlvInt && len(v) >= 1 && len(v) <= 4:
+		n := uint32(0)
+		for _, c := range v {
+			n = n<<8 | uint32(c)
+		}
+		d.line(3, "%s: %d", field.label, n)
+	default:
+		d.line(3, "%s: %s", field.label, hex.EncodeToString(v))
+	}
+}
+
+// commandName returns name of command as shown by Wireshark, e.g. "Submit_sm" or "Submit_sm - resp".
+func commandName(id data.CommandIDType) string {
+	name := strings.ToLower(id.String())
+	if strings.HasPrefix(name, "commandidtype(") {
+		return "Unknown"
+	}
+
+	resp := strings.HasSuffix(name, "_resp") && id != data.GENERIC_NACK
+	name = strings.ToUpper(name[:1]) + strings.TrimSuffix(name[1:], "_resp")
+	if resp {
+		name += " - resp"
+	}
+	return name
+}
+
+func statusName(status data.CommandStatusType) string {
+	if status == data.ESME_ROK {
+		return "Ok"
+	}
+	return status.Desc()
+}
+
+func messageTypeName(v byte) string {
+	if name, ok := messageTypeNames[v]; ok {
+		return name
+	}
+	return "Reserved"
+}
+
+func nameOf(names []string, v byte) string {
+	if int(v) < len(names) {
+		return names[v]
+	}
+	return "Reserved"
+}
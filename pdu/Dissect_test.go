@@ -0,0 +1,87 @@
+package pdu
+
+import (
+	"testing"
+
+	"github.com/linxGnu/gosmpp/data"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDissect(t *testing.T) {
+	t.Run("submit_sm", func(t *testing.T) {
+		v := NewSubmitSM().(*SubmitSM)
+		v.SequenceNumber = 7
+		_ = v.SourceAddr.SetAddress("MyShop")
+		v.SourceAddr.SetTon(5)
+		_ = v.DestAddr.SetAddress("84901234567")
+		v.DestAddr.SetTon(1)
+		v.DestAddr.SetNpi(1)
+		v.RegisteredDelivery = 1
+		_ = v.Message.SetMessageWithEncoding("Hello", data.GSM7BIT)
+		v.RegisterOptionalParam(Field{Tag: TagSarMsgRefNum, Data: []byte{0, 1}})
+		v.Marshal(NewBuffer(nil))
+
+		require.Equal(t, `Short Message Peer to Peer, Command: Submit_sm, Seq: 7, Len: 61
+    Length: 61
+    Operation: Submit_sm (0x00000004)
+    Sequence #: 7
+    Service type: (Default)
+    Type of number (originator): Alphanumeric (0x05)
+    Numbering plan indicator (originator): Unknown (0x00)
+    Originator address: MyShop
+    Type of number (recipient): International (0x01)
+    Numbering plan indicator (recipient): ISDN (E163/E164) (0x01)
+    Recipient address: 84901234567
+    ESM class: 0x00
+        .... ..00 = Messaging mode: Default SMSC mode (0x0)
+        ..00 00.. = Message type: Default message type (0x0)
+        00.. .... = GSM features: No specific features selected (0x0)
+    Protocol id.: 0x00
+    Priority level: 0
+    Scheduled delivery time:
+    Validity period:
+    Registered delivery: 0x01
+        .... ..01 = Delivery receipt: Delivery receipt requested (for success or failure) (0x1)
+        .... 00.. = Message type: No recipient SME acknowledgement requested (0x0)
+        ...0 .... = Intermediate notif: No intermediate notification requested (0x0)
+    Replace: 0
+    Data coding: 0x00
+    Predefined message: 0
+    Message length: 5
+    Message: Hello
+    Optional parameters
+        Optional parameter: sar_msg_ref_num (0x020c)
+            Tag: 0x020c
+            Length: 2
+            SAR reference number: 1`, Dissect(v))
+	})
+
+	t.Run("response", func(t *testing.T) {
+		v := NewSubmitSMResp().(*SubmitSMResp)
+		v.SequenceNumber = 3
+		v.CommandStatus = data.ESME_RTHROTTLED
+		v.MessageID = "id-1"
+		v.Marshal(NewBuffer(nil))
+
+		require.Equal(t, `Short Message Peer to Peer, Command: Submit_sm - resp, Seq: 3, Len: 21
+    Length: 21
+    Operation: Submit_sm - resp (0x80000004)
+    Result: `+data.ESME_RTHROTTLED.Desc()+` (0x00000058)
+    Sequence #: 3
+    Message id.: id-1`, Dissect(v))
+	})
+
+	t.Run("bind masks password", func(t *testing.T) {
+		v := NewBindRequest(Transceiver)
+		v.Password = "secret"
+		s := Dissect(v)
+		require.Contains(t, s, "Command: Bind_transceiver,")
+		require.Contains(t, s, "\n    Password: ***\n")
+		require.NotContains(t, s, "secret")
+	})
+
+	t.Run("generic_nack", func(t *testing.T) {
+		require.Contains(t, Dissect(NewGenericNack()), "Operation: Generic_nack (0x80000000)\n    Result: Ok")
+	})
+}
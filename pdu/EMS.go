@@ -0,0 +1,190 @@
+package pdu
+
+import (
+	"fmt"
+
+	"github.com/linxGnu/gosmpp/data"
+)
+
+// EMS (Enhanced Messaging Service) information elements, 3GPP TS 23.040 section 9.2.3.24.10.
+//
+// Position of an element is the character position in the text of the message part it is sent in,
+// before which the object is shown.
+
+// EMSTextFormat is format mode of text formatting IE: alignment, font size and style flags combined.
+type EMSTextFormat byte
+
+// EMS text format modes.
+const (
+	EMSAlignLeft     EMSTextFormat = 0x00
+	EMSAlignCenter   EMSTextFormat = 0x01
+	EMSAlignRight    EMSTextFormat = 0x02
+	EMSAlignDefault  EMSTextFormat = 0x03 // language dependent
+	EMSFontNormal    EMSTextFormat = 0x00
+	EMSFontLarge     EMSTextFormat = 0x04
+	EMSFontSmall     EMSTextFormat = 0x08
+	EMSBold          EMSTextFormat = 0x10
+	EMSItalic        EMSTextFormat = 0x20
+	EMSUnderlined    EMSTextFormat = 0x40
+	EMSStrikethrough EMSTextFormat = 0x80
+)
+
+// EMSColor is text color of text formatting IE.
+type EMSColor byte
+
+// EMS text colors.
+const (
+	EMSBlack EMSColor = iota
+	EMSDarkGrey
+	EMSDarkRed
+	EMSDarkYellow
+	EMSDarkGreen
+	EMSDarkCyan
+	EMSDarkBlue
+	EMSDarkMagenta
+	EMSGrey
+	EMSWhite
+	EMSBrightRed
+	EMSBrightYellow
+	EMSBrightGreen
+	EMSBrightCyan
+	EMSBrightBlue
+	EMSBrightMagenta
+)
+
+// EMS predefined sounds.
+const (
+	EMSSoundChimesHigh byte = iota
+	EMSSoundChimesLow
+	EMSSoundDing
+	EMSSoundTaDa
+	EMSSoundNotify
+	EMSSoundDrum
+	EMSSoundClaps
+	EMSSoundFanFar
+	EMSSoundChordHigh
+	EMSSoundChordLow
+)
+
+// EMS predefined animations.
+const (
+	EMSAnimationICanSeeYou byte = iota
+	EMSAnimationLaughing
+	EMSAnimationCrying
+	EMSAnimationWink
+	EMSAnimationBored
+	EMSAnimationTease
+	EMSAnimationBlinking
+	EMSAnimationEyeRolling
+	EMSAnimationFlirty
+	EMSAnimationConfused
+	EMSAnimationKiss
+	EMSAnimationDevil
+	EMSAnimationCool
+	EMSAnimationHappy
+	EMSAnimationSad
+)
+
+// Sizes of EMS object data in bytes: pictures are 1 bit per pixel, animations 4 frames.
+const (
+	EMSSmallPictureSize   = 32  // 16x16 pixels
+	EMSLargePictureSize   = 128 // 32x32 pixels
+	EMSSmallAnimationSize = 32  // 4 frames of 8x8 pixels
+	EMSLargeAnimationSize = 128 // 4 frames of 16x16 pixels
+
+	// EMSMaxObjectData is maximum data of user defined sound or variable picture fitting into single part.
+	EMSMaxObjectData = 128
+)
+
+// NewIETextFormatting returns IE formatting length characters of text from position.
+func NewIETextFormatting(position, length byte, format EMSTextFormat) InfoElement {
+	return InfoElement{
+		ID:   data.UDH_EMS_TEXT_FORMATTING,
+		Data: []byte{position, length, byte(format)},
+	}
+}
+
+// NewIETextFormattingColor returns IE formatting length characters of text from position, with colors.
+func NewIETextFormattingColor(position, length byte, format EMSTextFormat, foreground, background EMSColor) InfoElement {
+	return InfoElement{
+		ID:   data.UDH_EMS_TEXT_FORMATTING,
+		Data: []byte{position, length, byte(format), byte(background&0x0F)<<4 | byte(foreground&0x0F)},
+	}
+}
+
+// NewIEPredefinedSound returns IE playing predefined sound, e.g. EMSSoundDing, at position.
+func NewIEPredefinedSound(position, sound byte) InfoElement {
+	return InfoElement{
+		ID:   data.UDH_EMS_PREDEFINED_SOUND,
+		Data: []byte{position, sound},
+	}
+}
+
+// NewIEPredefinedAnimation returns IE showing predefined animation, e.g. EMSAnimationWink, at position.
+func NewIEPredefinedAnimation(position, animation byte) InfoElement {
+	return InfoElement{
+		ID:   data.UDH_EMS_PREDEFINED_ANIMATION,
+		Data: []byte{position, animation},
+	}
+}
+
+// NewIEUserSound returns IE playing melody in iMelody format at position.
+func NewIEUserSound(position byte, melody []byte) (InfoElement, error) {
+	if len(melody) == 0 || len(melody) > EMSMaxObjectData {
+		return InfoElement{}, fmt.Errorf("EMS user sound must be 1 to %d bytes, got %d", EMSMaxObjectData, len(melody))
+	}
+	return newEMSObject(data.UDH_EMS_USER_SOUND, position, melody), nil
+}
+
+// NewIESmallPicture returns IE showing 16x16 pixels picture at position, EMSSmallPictureSize bytes
+// of rows from top, most significant bit is the leftmost pixel, set bits are black.
+func NewIESmallPicture(position byte, picture []byte) (InfoElement, error) {
+	return newEMSFixedObject(data.UDH_EMS_SMALL_PICTURE, "small picture", position, picture, EMSSmallPictureSize)
+}
+
+// NewIELargePicture returns IE showing 32x32 pixels picture at position, EMSLargePictureSize bytes
+// coded as by NewIESmallPicture.
+func NewIELargePicture(position byte, picture []byte) (InfoElement, error) {
+	return newEMSFixedObject(data.UDH_EMS_LARGE_PICTURE, "large picture", position, picture, EMSLargePictureSize)
+}
+
+// NewIEVariablePicture returns IE showing picture of width, multiple of 8, and height pixels at position,
+// coded as by NewIESmallPicture.
+func NewIEVariablePicture(position byte, width, height int, picture []byte) (InfoElement, error) {
+	if width <= 0 || width%8 != 0 || height <= 0 {
+		return InfoElement{}, fmt.Errorf("EMS variable picture must be multiple of 8 pixels wide, got %dx%d", width, height)
+	}
+	if len(picture) != width/8*height {
+		return InfoElement{}, fmt.Errorf("EMS variable picture of %dx%d must be %d bytes, got %d", width, height, width/8*height, len(picture))
+	}
+	if len(picture) > EMSMaxObjectData {
+		return InfoElement{}, fmt.Errorf("EMS variable picture must be at most %d bytes, got %d", EMSMaxObjectData, len(picture))
+	}
+	return newEMSObject(data.UDH_EMS_VARIABLE_PICTURE, position, append([]byte{byte(width / 8), byte(height)}, picture...)), nil
+}
+
+// NewIESmallAnimation returns IE showing animation of 4 frames of 8x8 pixels at position,
+// EMSSmallAnimationSize bytes of frames in order, coded as by NewIESmallPicture.
+func NewIESmallAnimation(position byte, frames []byte) (InfoElement, error) {
+	return newEMSFixedObject(data.UDH_EMS_SMALL_ANIMATION, "small animation", position, frames, EMSSmallAnimationSize)
+}
+
+// NewIELargeAnimation returns IE showing animation of 4 frames of 16x16 pixels at position,
+// EMSLargeAnimationSize bytes of frames in order, coded as by NewIESmallPicture.
+func NewIELargeAnimation(position byte, frames []byte) (InfoElement, error) {
+	return newEMSFixedObject(data.UDH_EMS_LARGE_ANIMATION, "large animation", position, frames, EMSLargeAnimationSize)
+}
+
+func newEMSFixedObject(id byte, name string, position byte, object []byte, size int) (InfoElement, error) {
+	if len(object) != size {
+		return InfoElement{}, fmt.Errorf("EMS %s must be %d bytes, got %d", name, size, len(object))
+	}
+	return newEMSObject(id, position, object), nil
+}
+
+func newEMSObject(id, position byte, object []byte) InfoElement {
+	return InfoElement{
+		ID:   id,
+		Data: append([]byte{position}, object...),
+	}
+}
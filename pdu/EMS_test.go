@@ -0,0 +1,95 @@
+package pdu
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/linxGnu/gosmpp/data"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEMSInfoElements(t *testing.T) {
+	t.Run("textFormatting", func(t *testing.T) {
+		u := UDH{
+			NewIETextFormatting(0, 5, EMSAlignCenter|EMSFontLarge|EMSBold),
+			NewIETextFormattingColor(6, 5, EMSItalic, EMSBrightRed, EMSWhite),
+		}
+		b, err := u.MarshalBinary()
+		require.NoError(t, err)
+		require.Equal(t, "0b0a030005150a040605209a", toHex(b))
+	})
+
+	t.Run("predefined", func(t *testing.T) {
+		u := UDH{NewIEPredefinedSound(3, EMSSoundDing), NewIEPredefinedAnimation(10, EMSAnimationWink)}
+		b, err := u.MarshalBinary()
+		require.NoError(t, err)
+		require.Equal(t, "080b0203020d020a03", toHex(b))
+	})
+
+	t.Run("userSound", func(t *testing.T) {
+		ie, err := NewIEUserSound(0, []byte("BEGIN:IMELODY"))
+		require.NoError(t, err)
+		require.Equal(t, data.UDH_EMS_USER_SOUND, ie.ID)
+		require.Equal(t, append([]byte{0}, "BEGIN:IMELODY"...), ie.Data)
+
+		_, err = NewIEUserSound(0, nil)
+		require.Error(t, err)
+		_, err = NewIEUserSound(0, make([]byte, EMSMaxObjectData+1))
+		require.Error(t, err)
+	})
+
+	t.Run("pictures", func(t *testing.T) {
+		ie, err := NewIESmallPicture(1, bytes.Repeat([]byte{0xFF}, EMSSmallPictureSize))
+		require.NoError(t, err)
+		require.Equal(t, data.UDH_EMS_SMALL_PICTURE, ie.ID)
+		require.Len(t, ie.Data, 1+EMSSmallPictureSize)
+
+		ie, err = NewIELargePicture(1, make([]byte, EMSLargePictureSize))
+		require.NoError(t, err)
+		require.Equal(t, data.UDH_EMS_LARGE_PICTURE, ie.ID)
+
+		_, err = NewIESmallPicture(1, make([]byte, EMSLargePictureSize))
+		require.Error(t, err)
+		_, err = NewIELargePicture(1, make([]byte, EMSSmallPictureSize))
+		require.Error(t, err)
+	})
+
+	t.Run("variablePicture", func(t *testing.T) {
+		ie, err := NewIEVariablePicture(2, 16, 4, make([]byte, 8))
+		require.NoError(t, err)
+		require.Equal(t, data.UDH_EMS_VARIABLE_PICTURE, ie.ID)
+		require.Equal(t, []byte{2, 2, 4, 0, 0, 0, 0, 0, 0, 0, 0}, ie.Data)
+
+		_, err = NewIEVariablePicture(2, 12, 4, make([]byte, 8))
+		require.Error(t, err)
+		_, err = NewIEVariablePicture(2, 16, 4, make([]byte, 7))
+		require.Error(t, err)
+		_, err = NewIEVariablePicture(2, 64, 32, make([]byte, 256))
+		require.Error(t, err)
+	})
+
+	t.Run("animations", func(t *testing.T) {
+		ie, err := NewIESmallAnimation(0, make([]byte, EMSSmallAnimationSize))
+		require.NoError(t, err)
+		require.Equal(t, data.UDH_EMS_SMALL_ANIMATION, ie.ID)
+
+		ie, err = NewIELargeAnimation(0, make([]byte, EMSLargeAnimationSize))
+		require.NoError(t, err)
+		require.Equal(t, data.UDH_EMS_LARGE_ANIMATION, ie.ID)
+
+		_, err = NewIESmallAnimation(0, make([]byte, EMSLargeAnimationSize))
+		require.Error(t, err)
+	})
+
+	t.Run("roundTrip", func(t *testing.T) {
+		u := UDH{NewIETextFormatting(0, 5, EMSBold), NewIEPredefinedSound(5, EMSSoundTaDa)}
+		b, err := u.MarshalBinary()
+		require.NoError(t, err)
+
+		var parsed UDH
+		_, err = parsed.UnmarshalBinary(b)
+		require.NoError(t, err)
+		require.Equal(t, u, parsed)
+	})
+}
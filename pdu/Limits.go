@@ -0,0 +1,90 @@
+package pdu
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/linxGnu/gosmpp/data"
+)
+
+// DecodeLimits caps optional parameters of decoded PDUs, against frames crafted to amplify memory of the
+// decoding side, e.g. thousands of tiny TLVs each taking a map entry. Zero fields mean no limit, so zero value
+// decodes every PDU up to data.MAX_PDU_LEN.
+//
+// PDUs exceeding the limits are rejected with *TLVLimitError before their optional parameters are decoded,
+// or with *SizeError before they are decoded at all.
+type DecodeLimits struct {
+	// MaxTLVs is maximum number of optional parameters of PDU.
+	MaxTLVs int
+
+	// MaxTLVBytes is maximum size of all optional parameters of PDU, their tags and lengths included.
+	MaxTLVBytes int
+
+	// MaxCommandLength is maximum command_length of PDU, lower than data.MAX_PDU_LEN, e.g. 4096 of SMSC
+	// rejecting larger PDUs.
+	MaxCommandLength int
+}
+
+// TLVLimitError is returned decoding PDU whose optional parameters exceed DecodeLimits.
+type TLVLimitError struct {
+	// Header of the rejected PDU, e.g. to respond generic_nack with its sequence number.
+	Header Header
+
+	// TLVs is number of optional parameters, counted up to the first one over MaxTLVs.
+	TLVs int
+
+	// TLVBytes is size of all optional parameters.
+	TLVBytes int
+
+	Limits DecodeLimits
+}
+
+// Error implements error interface.
+func (e *TLVLimitError) Error() string {
+	return fmt.Sprintf("%s seq=%d: optional parameters exceed limits (%d TLVs of %d bytes, limits %d TLVs of %d bytes)",
+		e.Header.CommandID, e.Header.SequenceNumber, e.TLVs, e.TLVBytes, e.Limits.MaxTLVs, e.Limits.MaxTLVBytes)
+}
+
+// SizeError is returned decoding PDU whose command_length exceeds DecodeLimits.MaxCommandLength, or encoding it
+// for peer accepting no larger PDUs, see CheckLength.
+type SizeError struct {
+	// Header of the PDU. Its command_length is the size of the PDU.
+	Header Header
+
+	// Max is the maximum command_length exceeded.
+	Max int
+}
+
+// Error implements error interface.
+func (e *SizeError) Error() string {
+	return fmt.Sprintf("%s seq=%d: command_length %d exceeds maximum %d",
+		e.Header.CommandID, e.Header.SequenceNumber, e.Header.CommandLength, e.Max)
+}
+
+// CheckLength checks that PDU encoded as frame is not longer than max, returning *SizeError if it is.
+// Zero max means no limit.
+func CheckLength(frame []byte, max int) error {
+	if max <= 0 || len(frame) <= max {
+		return nil
+	}
+	var header [data.PDU_HEADER_SIZE]byte
+	copy(header[:], frame)
+	return &SizeError{Header: ParseHeader(header), Max: max}
+}
+
+// check checks optional parameters of PDU, scanning their lengths only.
+func (l DecodeLimits) check(h Header, optParam []byte) error {
+	if l.MaxTLVBytes > 0 && len(optParam) > l.MaxTLVBytes {
+		return &TLVLimitError{Header: h, TLVBytes: len(optParam), Limits: l}
+	}
+
+	if l.MaxTLVs > 0 {
+		n := 0
+		for i := 0; i+4 <= len(optParam); i += 4 + int(binary.BigEndian.Uint16(optParam[i+2:])) {
+			if n++; n > l.MaxTLVs {
+				return &TLVLimitError{Header: h, TLVs: n, TLVBytes: len(optParam), Limits: l}
+			}
+		}
+	}
+	return nil
+}
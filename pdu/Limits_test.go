@@ -0,0 +1,119 @@
+package pdu
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/linxGnu/gosmpp/data"
+
+	"github.com/stretchr/testify/require"
+)
+
+func marshalWithTLVs(n, size int) []byte {
+	p := NewDataSM()
+	for i := 0; i < n; i++ {
+		p.RegisterOptionalParam(Field{Tag: Tag(0x1400 + i), Data: make([]byte, size)})
+	}
+	b := NewBuffer(nil)
+	p.Marshal(b)
+	return b.Bytes()
+}
+
+func TestDecodeLimits(t *testing.T) {
+	frame := marshalWithTLVs(10, 6)
+
+	// no limits
+	p, err := Decode(frame)
+	require.Nil(t, err)
+	require.Len(t, p.(*DataSM).OptionalParameters, 10)
+
+	p, err = DecodeLimits{MaxTLVs: 10, MaxTLVBytes: 100}.Decode(frame)
+	require.Nil(t, err)
+	require.Len(t, p.(*DataSM).OptionalParameters, 10)
+
+	_, err = DecodeLimits{MaxTLVs: 9}.Decode(frame)
+	var limitErr *TLVLimitError
+	require.True(t, errors.As(err, &limitErr))
+	require.Equal(t, data.DATA_SM, limitErr.Header.CommandID)
+	require.Equal(t, 10, limitErr.TLVs)
+	require.Equal(t, 100, limitErr.TLVBytes)
+
+	_, err = DecodeLimits{MaxTLVBytes: 99}.Decode(frame)
+	require.True(t, errors.As(err, &limitErr))
+	require.Equal(t, 100, limitErr.TLVBytes)
+	require.Contains(t, err.Error(), "DATA_SM seq=")
+
+	t.Run("buffered", func(t *testing.T) {
+		valid := marshalWithTLVs(1, 1)
+		r := bufio.NewReader(bytes.NewReader(append(append([]byte{}, frame...), valid...)))
+
+		limits := DecodeLimits{MaxTLVs: 1}
+		_, err := limits.ParseBuffered(r)
+		require.True(t, errors.As(err, &limitErr))
+
+		// reading goes on with the next PDU
+		p, err := limits.ParseBuffered(r)
+		require.Nil(t, err)
+		require.Len(t, p.(*DataSM).OptionalParameters, 1)
+	})
+}
+
+func TestDecodeMaxCommandLength(t *testing.T) {
+	frame := marshalWithTLVs(10, 6)
+
+	p, err := DecodeLimits{MaxCommandLength: len(frame)}.Decode(frame)
+	require.Nil(t, err)
+	require.Len(t, p.(*DataSM).OptionalParameters, 10)
+
+	_, err = DecodeLimits{MaxCommandLength: len(frame) - 1}.Decode(frame)
+	var sizeErr *SizeError
+	require.True(t, errors.As(err, &sizeErr))
+	require.Equal(t, data.DATA_SM, sizeErr.Header.CommandID)
+	require.EqualValues(t, len(frame), sizeErr.Header.CommandLength)
+	require.Equal(t, len(frame)-1, sizeErr.Max)
+	require.Contains(t, err.Error(), "exceeds maximum")
+
+	t.Run("buffered", func(t *testing.T) {
+		valid := marshalWithTLVs(1, 1)
+		r := bufio.NewReader(bytes.NewReader(append(append([]byte{}, frame...), valid...)))
+
+		limits := DecodeLimits{MaxCommandLength: len(valid)}
+		_, err := limits.ParseBuffered(r)
+		require.True(t, errors.As(err, &sizeErr))
+
+		// reading goes on with the next PDU
+		p, err := limits.ParseBuffered(r)
+		require.Nil(t, err)
+		require.Len(t, p.(*DataSM).OptionalParameters, 1)
+	})
+
+	t.Run("unbuffered", func(t *testing.T) {
+		valid := marshalWithTLVs(1, 1)
+		r := bytes.NewReader(append(append([]byte{}, frame...), valid...))
+
+		limits := DecodeLimits{MaxCommandLength: len(valid)}
+		_, err := limits.Parse(r)
+		require.True(t, errors.As(err, &sizeErr))
+		require.EqualValues(t, len(frame), sizeErr.Header.CommandLength)
+		require.Equal(t, len(valid), r.Len())
+
+		p, err := limits.Parse(r)
+		require.Nil(t, err)
+		require.Len(t, p.(*DataSM).OptionalParameters, 1)
+	})
+}
+
+func TestCheckLength(t *testing.T) {
+	frame := marshalWithTLVs(1, 1)
+
+	require.Nil(t, CheckLength(frame, 0))
+	require.Nil(t, CheckLength(frame, len(frame)))
+
+	err := CheckLength(frame, len(frame)-1)
+	var sizeErr *SizeError
+	require.True(t, errors.As(err, &sizeErr))
+	require.Equal(t, data.DATA_SM, sizeErr.Header.CommandID)
+	require.EqualValues(t, len(frame), sizeErr.Header.CommandLength)
+}
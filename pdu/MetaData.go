@@ -0,0 +1,77 @@
+package pdu
+
+import (
+	"net/url"
+	"strings"
+)
+
+// MetaDataGroup is group of Kannel's meta-data with SMPP parameters, the one decoded of grouped payloads.
+const MetaDataGroup = "smpp"
+
+// MetaData is extended parameters of Kannel and Jasmin gateways, carried as URL-encoded key/value payload
+// of a vendor specific TLV, e.g. "campaign=spring&cost=0.02". Tag of the TLV is agreed with the gateway,
+// there is no standard one.
+type MetaData map[string]string
+
+// ParseMetaData decodes URL-encoded payload of meta-data TLV, terminated by NULL or not. Kannel's grouped
+// form, e.g. "?smpp?campaign=spring&cost=0.02?http?x=1", is accepted as well, of which parameters of
+// MetaDataGroup are decoded. Repeated keys keep the first value.
+func ParseMetaData(b []byte) (MetaData, error) {
+	s := strings.TrimSuffix(string(b), "\x00")
+
+	if strings.HasPrefix(s, "?") {
+		grouped := s
+		s = ""
+		for len(grouped) > 1 && grouped[0] == '?' {
+			end := strings.IndexByte(grouped[1:], '?')
+			if end < 0 {
+				break
+			}
+			group, params := grouped[1:end+1], grouped[end+2:]
+
+			next := strings.IndexByte(params, '?')
+			if next < 0 {
+				next = len(params)
+			}
+			if group == MetaDataGroup {
+				s = params[:next]
+				break
+			}
+			grouped = params[next:]
+		}
+	}
+
+	values, err := url.ParseQuery(s)
+	if err != nil {
+		return nil, err
+	}
+
+	m := make(MetaData, len(values))
+	for k, v := range values {
+		m[k] = v[0]
+	}
+	return m, nil
+}
+
+// MetaDataOf decodes meta-data of p carried in TLV of tag. It returns nil if p has no such TLV.
+func MetaDataOf(p PDU, tag Tag) (MetaData, error) {
+	field, ok := optionalParameters(p)[tag]
+	if !ok {
+		return nil, nil
+	}
+	return ParseMetaData(field.Data)
+}
+
+// String returns URL-encoded payload of m, sorted by key.
+func (m MetaData) String() string {
+	values := make(url.Values, len(m))
+	for k, v := range m {
+		values.Set(k, v)
+	}
+	return values.Encode()
+}
+
+// Field returns TLV of tag carrying m, to be registered to PDU by RegisterOptionalParam.
+func (m MetaData) Field(tag Tag) Field {
+	return Field{Tag: tag, Data: []byte(m.String())}
+}
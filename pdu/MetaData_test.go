@@ -0,0 +1,54 @@
+package pdu
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetaData(t *testing.T) {
+	const tag = Tag(0x1401)
+
+	m := MetaData{"campaign": "spring sale", "cost": "0.02", "note": "a&b=c"}
+	require.Equal(t, "campaign=spring+sale&cost=0.02&note=a%26b%3Dc", m.String())
+
+	v := NewDeliverSM().(*DeliverSM)
+	v.RegisterOptionalParam(m.Field(tag))
+
+	buf := NewBuffer(nil)
+	v.Marshal(buf)
+	p, err := Parse(buf)
+	require.Nil(t, err)
+
+	decoded, err := MetaDataOf(p, tag)
+	require.Nil(t, err)
+	require.Equal(t, m, decoded)
+
+	decoded, err = MetaDataOf(p, Tag(0x1402))
+	require.Nil(t, err)
+	require.Nil(t, decoded)
+}
+
+func TestParseMetaData(t *testing.T) {
+	for name, c := range map[string]struct {
+		payload string
+		expect  MetaData
+	}{
+		"plain":          {"a=1&b=%3F", MetaData{"a": "1", "b": "?"}},
+		"null":           {"a=1\x00", MetaData{"a": "1"}},
+		"empty":          {"", MetaData{}},
+		"repeated":       {"a=1&a=2", MetaData{"a": "1"}},
+		"kannel":         {"?smpp?a=1&b=2", MetaData{"a": "1", "b": "2"}},
+		"kannel groups":  {"?http?x=1?smpp?a=1?other?y=2", MetaData{"a": "1"}},
+		"kannel no smpp": {"?http?x=1", MetaData{}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			m, err := ParseMetaData([]byte(c.payload))
+			require.Nil(t, err)
+			require.Equal(t, c.expect, m)
+		})
+	}
+
+	_, err := ParseMetaData([]byte("a=%zz"))
+	require.NotNil(t, err)
+}
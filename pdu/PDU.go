@@ -1,7 +1,12 @@
 package pdu
 
 import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
 	"io"
+	"sync"
 
 	"github.com/linxGnu/gosmpp/data"
 	"github.com/linxGnu/gosmpp/errors"
@@ -49,6 +54,9 @@ type PDU interface {
 type base struct {
 	Header
 	OptionalParameters map[Tag]Field
+
+	// released is set by Pool with release checks
+	released *release
 }
 
 func newBase() (v base) {
@@ -57,6 +65,10 @@ func newBase() (v base) {
 	return
 }
 
+func (c *base) pooledBase() *base {
+	return c
+}
+
 // GetHeader returns pdu header.
 func (c *base) GetHeader() Header {
 	return c.Header
@@ -85,9 +97,14 @@ func (c *base) unmarshal(b *ByteBuffer, bodyReader func(*ByteBuffer) error) (err
 
 			// body < command_length, still have optional parameters ?
 			if got < cmdLength {
-				var optParam []byte
-				if optParam, err = b.ReadN(cmdLength - got); err == nil {
-					err = c.unmarshalOptionalParam(optParam)
+				// not copied, fields copy their values out of the buffer
+				if b.Len() < cmdLength-got {
+					err = ErrBufferNotEnoughByteToRead
+				} else {
+					optParam := b.Next(cmdLength - got)
+					if err = b.limits.check(c.Header, optParam); err == nil {
+						err = c.unmarshalOptionalParam(optParam)
+					}
 				}
 				if err != nil {
 					return
@@ -119,6 +136,10 @@ func (c *base) unmarshalOptionalParam(optParam []byte) (err error) {
 
 // Marshal to buffer.
 func (c *base) marshal(b *ByteBuffer, bodyWriter func(*ByteBuffer)) {
+	if c.released != nil {
+		panic(fmt.Sprintf("pdu: %v is used after release at %s", c.CommandID, c.released.at))
+	}
+
 	bodyBuf := NewBuffer(nil)
 
 	// body
@@ -154,37 +175,148 @@ func (c *base) IsGNack() bool {
 	return c.CommandID == data.GENERIC_NACK
 }
 
+// framePool keeps buffers over frames being decoded.
+var framePool = sync.Pool{
+	New: func() interface{} {
+		return &ByteBuffer{Buffer: new(bytes.Buffer)}
+	},
+}
+
 // Parse PDU from reader.
 func Parse(r io.Reader) (pdu PDU, err error) {
-	var headerBytes [16]byte
+	return DecodeLimits{}.Parse(r)
+}
+
+// ParseBuffered parses PDU from buffered reader, decoding it within the reader buffer: the frame is neither
+// copied nor allocated, and is released to the reader once decoded. PDUs larger than the reader buffer are read
+// as by Parse.
+//
+// Reading is retried from the start of the PDU after an error of the underlying reader, e.g. read timeout, as
+// incomplete frame is not consumed.
+func ParseBuffered(r *bufio.Reader) (pdu PDU, err error) {
+	return DecodeLimits{}.ParseBuffered(r)
+}
+
+// Decode decodes PDU from frame holding exactly one PDU, header included.
+//
+// Decoded PDU does not retain frame: every field is copied out of it, so the caller owns frame and could
+// reuse it right after.
+func Decode(frame []byte) (pdu PDU, err error) {
+	return DecodeLimits{}.Decode(frame)
+}
+
+// Parse is Parse applying the limits. Frame of PDU over MaxCommandLength is skipped unread, reading could
+// go on with the next PDU.
+func (l DecodeLimits) Parse(r io.Reader) (pdu PDU, err error) {
+	return l.parse(r, nil)
+}
+
+// ParseBuffered is ParseBuffered applying the limits. Frame of PDU exceeding them is consumed, so reading
+// could go on with the next PDU.
+func (l DecodeLimits) ParseBuffered(r *bufio.Reader) (pdu PDU, err error) {
+	return l.parseBuffered(r, nil)
+}
+
+// Decode is Decode applying the limits.
+func (l DecodeLimits) Decode(frame []byte) (pdu PDU, err error) {
+	return l.decode(frame, nil)
+}
+
+// parse parses PDU from reader, getting it from pool if not nil.
+func (l DecodeLimits) parse(r io.Reader, pool *Pool) (pdu PDU, err error) {
+	var headerBytes [data.PDU_HEADER_SIZE]byte
 
 	if _, err = io.ReadFull(r, headerBytes[:]); err != nil {
 		return
 	}
 
-	header := ParseHeader(headerBytes)
-	if header.CommandLength < 16 || header.CommandLength > data.MAX_PDU_LEN {
-		err = errors.ErrInvalidPDU
+	length, err := frameLength(headerBytes[:])
+	if err != nil {
+		return
+	}
+	if l.MaxCommandLength > 0 && length > l.MaxCommandLength {
+		// skip body of too long pdu without buffering it
+		if _, err = io.CopyN(io.Discard, r, int64(length-data.PDU_HEADER_SIZE)); err == nil {
+			err = &SizeError{Header: ParseHeader(headerBytes), Max: l.MaxCommandLength}
+		}
 		return
 	}
 
 	// read pdu body
-	bodyBytes := make([]byte, header.CommandLength-16)
-	if len(bodyBytes) > 0 {
-		if _, err = io.ReadFull(r, bodyBytes); err != nil {
-			return
-		}
+	frame := make([]byte, length)
+	copy(frame, headerBytes[:])
+	if _, err = io.ReadFull(r, frame[data.PDU_HEADER_SIZE:]); err != nil {
+		return
+	}
+
+	return l.decode(frame, pool)
+}
+
+// parseBuffered parses PDU from buffered reader, getting it from pool if not nil.
+func (l DecodeLimits) parseBuffered(r *bufio.Reader, pool *Pool) (pdu PDU, err error) {
+	header, err := r.Peek(data.PDU_HEADER_SIZE)
+	if err != nil {
+		return
+	}
+
+	length, err := frameLength(header)
+	if err != nil {
+		return
+	}
+	if length > r.Size() {
+		return l.parse(r, pool)
+	}
+
+	frame, err := r.Peek(length)
+	if err != nil {
+		return
+	}
+	pdu, err = l.decode(frame, pool)
+	_, _ = r.Discard(length)
+	return
+}
+
+// decode decodes PDU from frame, getting it from pool if not nil.
+func (l DecodeLimits) decode(frame []byte, pool *Pool) (pdu PDU, err error) {
+	length, err := frameLength(frame)
+	if err != nil {
+		return
+	}
+	if length != len(frame) {
+		err = errors.ErrInvalidPDU
+		return
+	}
+	if err = CheckLength(frame, l.MaxCommandLength); err != nil {
+		return
 	}
 
 	// try to create pdu
-	if pdu, err = CreatePDUFromCmdID(header.CommandID); err == nil {
-		buf := NewBuffer(make([]byte, 0, header.CommandLength))
-		_, _ = buf.Write(headerBytes[:])
-		if len(bodyBytes) > 0 {
-			_, _ = buf.Write(bodyBytes)
-		}
+	cmdID := data.CommandIDType(binary.BigEndian.Uint32(frame[4:]))
+	if pool != nil {
+		pdu, err = pool.Get(cmdID)
+	} else {
+		pdu, err = CreatePDUFromCmdID(cmdID)
+	}
+	if err == nil {
+		buf := framePool.Get().(*ByteBuffer)
+		*buf.Buffer = *bytes.NewBuffer(frame)
+		buf.limits = l
 		err = pdu.Unmarshal(buf)
+		*buf.Buffer = bytes.Buffer{}
+		framePool.Put(buf)
 	}
 
 	return
 }
+
+// frameLength returns command_length of PDU starting with header.
+func frameLength(header []byte) (int, error) {
+	if len(header) < data.PDU_HEADER_SIZE {
+		return 0, errors.ErrInvalidPDU
+	}
+	length := int32(binary.BigEndian.Uint32(header))
+	if length < data.PDU_HEADER_SIZE || length > data.MAX_PDU_LEN {
+		return 0, errors.ErrInvalidPDU
+	}
+	return int(length), nil
+}
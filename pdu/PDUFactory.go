@@ -35,6 +35,8 @@ var pduMap = map[data.CommandIDType]pduGenerator{
 	data.ENQUIRE_LINK_RESP:     NewEnquireLinkResp,
 	data.ALERT_NOTIFICATION:    NewAlertNotification,
 	data.GENERIC_NACK:          NewGenericNack,
+	data.BROADCAST_SM:          NewBroadcastSM,
+	data.BROADCAST_SM_RESP:     NewBroadcastSMResp,
 }
 
 // CreatePDUFromCmdID creates PDU from cmd id.
@@ -74,6 +74,11 @@ func (c *Header) SetSequenceNumber(v int32) {
 	c.SequenceNumber = v
 }
 
+// SetCommandStatus manually sets command status.
+func (c *Header) SetCommandStatus(v data.CommandStatusType) {
+	c.CommandStatus = v
+}
+
 // Marshal to buffer.
 func (c *Header) Marshal(b *ByteBuffer) {
 	b.Grow(16)
@@ -4,6 +4,8 @@ import (
 	"math"
 	"testing"
 
+	"github.com/linxGnu/gosmpp/data"
+
 	"github.com/stretchr/testify/require"
 )
 
@@ -11,3 +13,11 @@ func TestNextSeq(t *testing.T) {
 	var v int32 = math.MaxInt32
 	require.EqualValues(t, 1, nextSequenceNumber(&v))
 }
+
+func TestSetCommandStatus(t *testing.T) {
+	p := NewSubmitSMResp()
+	p.(interface {
+		SetCommandStatus(data.CommandStatusType)
+	}).SetCommandStatus(data.ESME_RTHROTTLED)
+	require.Equal(t, data.ESME_RTHROTTLED, p.GetHeader().CommandStatus)
+}
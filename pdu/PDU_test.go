@@ -1,8 +1,13 @@
 package pdu
 
 import (
+	"bufio"
+	"bytes"
+	"io"
+	"os"
 	"testing"
 
+	"github.com/linxGnu/gosmpp/data"
 	"github.com/linxGnu/gosmpp/errors"
 
 	"github.com/stretchr/testify/require"
@@ -57,3 +62,118 @@ func TestParsePDU(t *testing.T) {
 		}))
 	})
 }
+
+func marshalSubmitSM(t testing.TB, text string) []byte {
+	p := NewSubmitSM().(*SubmitSM)
+	_ = p.SourceAddr.SetAddress("Alice")
+	_ = p.DestAddr.SetAddress("Bob")
+	require.Nil(t, p.Message.SetMessageWithEncoding(text, data.GSM7BIT))
+	p.RegisterOptionalParam(Field{Tag: TagUserMessageReference, Data: []byte{0, 7}})
+
+	buf := NewBuffer(nil)
+	p.Marshal(buf)
+	return buf.Bytes()
+}
+
+// failingReader returns err once after n bytes.
+type failingReader struct {
+	r   io.Reader
+	n   int
+	err error
+}
+
+func (f *failingReader) Read(b []byte) (int, error) {
+	if f.err != nil && f.n == 0 {
+		err := f.err
+		f.err = nil
+		return 0, err
+	}
+	if f.err != nil && len(b) > f.n {
+		b = b[:f.n]
+	}
+	n, err := f.r.Read(b)
+	f.n -= n
+	return n, err
+}
+
+func TestParseBuffered(t *testing.T) {
+	stream := append(marshalSubmitSM(t, "first"), marshalSubmitSM(t, "second")...)
+
+	t.Run("frames", func(t *testing.T) {
+		// buffer of the reader holds one PDU only, so the first frame is overwritten by the second one
+		r := bufio.NewReaderSize(bytes.NewReader(stream), len(stream)/2)
+
+		first, err := ParseBuffered(r)
+		require.Nil(t, err)
+		second, err := ParseBuffered(r)
+		require.Nil(t, err)
+		_, err = ParseBuffered(r)
+		require.Equal(t, io.EOF, err)
+
+		message, err := first.(*SubmitSM).Message.GetMessage()
+		require.Nil(t, err)
+		require.Equal(t, "first", message)
+		require.Equal(t, "Alice", first.(*SubmitSM).SourceAddr.Address())
+		require.Equal(t, []byte{0, 7}, first.(*SubmitSM).OptionalParameters[TagUserMessageReference].Data)
+
+		message, err = second.(*SubmitSM).Message.GetMessage()
+		require.Nil(t, err)
+		require.Equal(t, "second", message)
+	})
+
+	t.Run("retry", func(t *testing.T) {
+		// incomplete frame is kept in the buffer after read timeout
+		r := bufio.NewReaderSize(&failingReader{r: bytes.NewReader(stream), n: 20, err: os.ErrDeadlineExceeded}, 64<<10)
+
+		_, err := ParseBuffered(r)
+		require.Equal(t, os.ErrDeadlineExceeded, err)
+
+		p, err := ParseBuffered(r)
+		require.Nil(t, err)
+		message, _ := p.(*SubmitSM).Message.GetMessage()
+		require.Equal(t, "first", message)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := ParseBuffered(bufio.NewReader(bytes.NewReader(fromHex("0000000f800000060000000000000001"))))
+		require.Equal(t, errors.ErrInvalidPDU, err)
+	})
+}
+
+func TestDecode(t *testing.T) {
+	frame := marshalSubmitSM(t, "hello")
+
+	p, err := Decode(frame)
+	require.Nil(t, err)
+	for i := range frame {
+		frame[i] = 0
+	}
+	message, err := p.(*SubmitSM).Message.GetMessage()
+	require.Nil(t, err)
+	require.Equal(t, "hello", message)
+
+	_, err = Decode(append(marshalSubmitSM(t, "hello"), 0))
+	require.Equal(t, errors.ErrInvalidPDU, err)
+
+	_, err = Decode(fromHex("000000108000"))
+	require.Equal(t, errors.ErrInvalidPDU, err)
+}
+
+func BenchmarkParseBuffered(b *testing.B) {
+	frame := marshalSubmitSM(b, "Your code is 1234")
+	stream := bytes.Repeat(frame, 1000)
+	src := bytes.NewReader(stream)
+	r := bufio.NewReaderSize(src, 128<<10)
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(frame)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if src.Len() == 0 && r.Buffered() == 0 {
+			src.Reset(stream)
+		}
+		if _, err := ParseBuffered(r); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package pdu
+
+import (
+	"bufio"
+	"fmt"
+	"reflect"
+	"runtime"
+	"sync"
+
+	"github.com/linxGnu/gosmpp/data"
+)
+
+// pooledCommands are PDUs recycled by Pool: those of message traffic.
+var pooledCommands = []data.CommandIDType{
+	data.SUBMIT_SM, data.SUBMIT_SM_RESP,
+	data.DELIVER_SM, data.DELIVER_SM_RESP,
+	data.DATA_SM, data.DATA_SM_RESP,
+	data.ENQUIRE_LINK, data.ENQUIRE_LINK_RESP,
+}
+
+// Pool recycles PDU objects of high-throughput servers, sparing allocation of PDU and its optional parameters
+// on every PDU decoded or created. It is opt-in: PDUs of New* functions, Parse and Decode are never recycled.
+//
+// PDU got from Get or Decode is owned by the caller until Release, which hands it back to the pool: it must not
+// be used after, nor retained anywhere, e.g. in a store or by a goroutine. Values read out of PDU, e.g. message
+// data, are not reused, so they stay valid after Release.
+//
+// Only PDUs of message traffic are recycled: submit_sm, deliver_sm, data_sm, enquire_link and their responses.
+// Other PDUs are allocated as by CreatePDUFromCmdID and dropped by Release.
+//
+// Pool is safe for concurrent use.
+type Pool struct {
+	checks bool
+	types  map[data.CommandIDType]*pooledType
+}
+
+type pooledType struct {
+	template reflect.Value // struct of new PDU
+	pool     sync.Pool
+}
+
+// PoolOption configures Pool.
+type PoolOption func(*Pool)
+
+// WithReleaseChecks enables debug mode detecting use of PDU after Release. Released PDU is reset and marked,
+// then Marshal of it, releasing it again or getting it back modified since Release panics, telling where it was
+// released. Released PDUs are still recycled, use the checks in tests.
+func WithReleaseChecks() PoolOption {
+	return func(p *Pool) {
+		p.checks = true
+	}
+}
+
+// NewPool returns empty pool.
+func NewPool(opts ...PoolOption) *Pool {
+	p := &Pool{types: make(map[data.CommandIDType]*pooledType, len(pooledCommands))}
+	for _, id := range pooledCommands {
+		template, _ := CreatePDUFromCmdID(id)
+		p.types[id] = &pooledType{template: reflect.ValueOf(template).Elem()}
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// Get returns PDU of command id, as created by CreatePDUFromCmdID, with newly assigned sequence number.
+func (p *Pool) Get(cmdID data.CommandIDType) (PDU, error) {
+	t, ok := p.types[cmdID]
+	if !ok {
+		return CreatePDUFromCmdID(cmdID)
+	}
+
+	v, ok := t.pool.Get().(PDU)
+	if !ok {
+		return CreatePDUFromCmdID(cmdID)
+	}
+
+	p.recycle(t, v)
+	return v, nil
+}
+
+// recycle prepares v released to the pool for reuse.
+func (p *Pool) recycle(t *pooledType, v PDU) {
+	if p.checks {
+		b := baseOf(v)
+		released := b.released
+		b.released = nil
+		// state shares optional parameters map with v, which is empty since release
+		if len(b.OptionalParameters) > 0 || !reflect.DeepEqual(reflect.ValueOf(v).Elem().Interface(), released.state) {
+			panic(fmt.Sprintf("pdu: %v is modified after release at %s", b.CommandID, released.at))
+		}
+	}
+	t.reset(v)
+	v.AssignSequenceNumber()
+}
+
+// Release hands pdu back to the pool, see Pool for ownership.
+func (p *Pool) Release(pdu PDU) {
+	t, ok := p.types[pdu.GetHeader().CommandID]
+	if !ok || reflect.TypeOf(pdu).Elem() != t.template.Type() {
+		return
+	}
+
+	p.release(t, pdu)
+	t.pool.Put(pdu)
+}
+
+// release marks v released, with release checks.
+func (p *Pool) release(t *pooledType, v PDU) {
+	if !p.checks {
+		return
+	}
+
+	b := baseOf(v)
+	if b.released != nil {
+		panic(fmt.Sprintf("pdu: %v is released twice, first at %s", b.CommandID, b.released.at))
+	}
+
+	// reset state is kept to find modifications after release
+	t.reset(v)
+	state := reflect.New(t.template.Type()).Elem()
+	state.Set(reflect.ValueOf(v).Elem())
+	b.released = &release{at: caller(), state: state.Interface()}
+}
+
+// Decode decodes PDU from frame like Decode, getting it from the pool.
+func (p *Pool) Decode(frame []byte) (PDU, error) {
+	return DecodeLimits{}.decode(frame, p)
+}
+
+// ParseBufferedPooled is ParseBuffered getting PDU from pool, if not nil.
+func (l DecodeLimits) ParseBufferedPooled(r *bufio.Reader, pool *Pool) (PDU, error) {
+	return l.parseBuffered(r, pool)
+}
+
+// reset resets v to state of new PDU, keeping its optional parameters map.
+func (t *pooledType) reset(v PDU) {
+	b := baseOf(v)
+	params := b.OptionalParameters
+	for tag := range params {
+		delete(params, tag)
+	}
+	reflect.ValueOf(v).Elem().Set(t.template)
+	b.OptionalParameters = params
+}
+
+// baseOf returns base of pooled PDU.
+func baseOf(v PDU) *base {
+	return v.(interface{ pooledBase() *base }).pooledBase()
+}
+
+// release marks PDU released to Pool with release checks.
+type release struct {
+	at    string
+	state interface{}
+}
+
+// caller returns location calling Pool.Release.
+func caller() string {
+	_, file, line, ok := runtime.Caller(3)
+	if !ok {
+		return "unknown location"
+	}
+	return fmt.Sprintf("%s:%d", file, line)
+}
+
+// Arena collects PDUs of a batch, e.g. those read and dispatched together, releasing all of them at once by
+// Free. Arena is not safe for concurrent use.
+type Arena struct {
+	pool *Pool
+	pdus []PDU
+}
+
+// NewArena returns empty arena getting PDUs from the pool.
+func (p *Pool) NewArena() *Arena {
+	return &Arena{pool: p}
+}
+
+// Get is Pool.Get, the PDU is released by Free.
+func (a *Arena) Get(cmdID data.CommandIDType) (PDU, error) {
+	v, err := a.pool.Get(cmdID)
+	if err == nil {
+		a.pdus = append(a.pdus, v)
+	}
+	return v, err
+}
+
+// Decode is Pool.Decode, the PDU is released by Free.
+func (a *Arena) Decode(frame []byte) (PDU, error) {
+	v, err := a.pool.Decode(frame)
+	if v != nil {
+		a.pdus = append(a.pdus, v)
+	}
+	return v, err
+}
+
+// Free releases all PDUs of the arena to the pool, the arena could be used for the next batch.
+func (a *Arena) Free() {
+	for i, v := range a.pdus {
+		a.pool.Release(v)
+		a.pdus[i] = nil
+	}
+	a.pdus = a.pdus[:0]
+}
@@ -0,0 +1,194 @@
+package pdu
+
+import (
+	"bufio"
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/linxGnu/gosmpp/data"
+
+	"github.com/stretchr/testify/require"
+)
+
+// requireFresh checks that p is in state of new PDU, except sequence number.
+func requireFresh(t *testing.T, p PDU) {
+	fresh, err := CreatePDUFromCmdID(p.GetHeader().CommandID)
+	require.Nil(t, err)
+	fresh.SetSequenceNumber(p.GetSequenceNumber())
+	require.Equal(t, fresh, p)
+}
+
+// sharedSlices returns paths of slices of v which have capacity, they would be shared by PDUs reset to v.
+func sharedSlices(v reflect.Value, path string) (paths []string) {
+	switch v.Kind() {
+	case reflect.Slice:
+		if v.Cap() > 0 {
+			paths = append(paths, path)
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			paths = append(paths, sharedSlices(v.Field(i), path+"."+v.Type().Field(i).Name)...)
+		}
+	}
+	return
+}
+
+func TestPoolTemplates(t *testing.T) {
+	for id, pt := range NewPool().types {
+		require.Empty(t, sharedSlices(pt.template, id.String()))
+	}
+}
+
+func TestPool(t *testing.T) {
+	pool := NewPool()
+
+	p, err := pool.Get(data.SUBMIT_SM)
+	require.Nil(t, err)
+	requireFresh(t, p)
+
+	submit := p.(*SubmitSM)
+	_ = submit.DestAddr.SetAddress("+447700900123")
+	_ = submit.Message.SetMessageWithEncoding("hello", data.UCS2)
+	submit.RegisterOptionalParam(Field{Tag: TagUserMessageReference, Data: []byte{0, 1}})
+	message := submit.Message.messageData
+	seq := submit.SequenceNumber
+
+	// released PDU is reset for reuse, values read out of it are kept
+	markReleased(pool, p)
+	pool.recycle(pool.types[data.SUBMIT_SM], p)
+	requireFresh(t, p)
+	require.NotEqual(t, seq, p.GetSequenceNumber())
+	require.Equal(t, []byte{0, 'h', 0, 'e', 0, 'l', 0, 'l', 0, 'o'}, message)
+
+	// other PDUs are not recycled
+	bind, err := pool.Get(data.BIND_TRANSCEIVER)
+	require.Nil(t, err)
+	requireFresh(t, bind)
+	pool.Release(bind)
+
+	_, err = pool.Get(data.CommandIDType(0x1234))
+	require.NotNil(t, err)
+}
+
+func TestPoolDecode(t *testing.T) {
+	pool := NewPool()
+
+	deliver := NewDeliverSM().(*DeliverSM)
+	_ = deliver.SourceAddr.SetAddress("+447700900123")
+	_ = deliver.Message.SetMessageWithEncoding("hello", data.GSM7BIT)
+	deliver.RegisterOptionalParam(Field{Tag: TagUserMessageReference, Data: []byte{0, 1}})
+	frame := marshalPDU(deliver)
+	decoded, err := Decode(frame)
+	require.Nil(t, err)
+
+	for i := 0; i < 3; i++ {
+		p, err := pool.Decode(frame)
+		require.Nil(t, err)
+		require.Equal(t, decoded, p)
+		pool.Release(p)
+	}
+
+	r := bufio.NewReader(bytes.NewReader(append(append([]byte{}, frame...), frame...)))
+	for i := 0; i < 2; i++ {
+		p, err := DecodeLimits{MaxTLVs: 1}.ParseBufferedPooled(r, pool)
+		require.Nil(t, err)
+		require.Equal(t, decoded, p)
+		pool.Release(p)
+	}
+}
+
+func TestPoolReleaseChecks(t *testing.T) {
+	pool := NewPool(WithReleaseChecks())
+	pt := pool.types[data.DELIVER_SM]
+
+	p, err := pool.Get(data.DELIVER_SM)
+	require.Nil(t, err)
+	markReleased(pool, p)
+
+	require.PanicsWithValue(t, "pdu: DELIVER_SM is released twice, first at "+releasedAt(t, p), func() {
+		markReleased(pool, p)
+	})
+	require.PanicsWithValue(t, "pdu: DELIVER_SM is used after release at "+releasedAt(t, p), func() {
+		p.Marshal(NewBuffer(nil))
+	})
+
+	// released PDU got back unmodified is reused
+	pool.recycle(pt, p)
+	requireFresh(t, p)
+	p.Marshal(NewBuffer(nil))
+
+	t.Run("modified", func(t *testing.T) {
+		for _, modify := range []func(*DeliverSM){
+			func(p *DeliverSM) { p.EsmClass = data.SM_UDH_GSM },
+			func(p *DeliverSM) { _ = p.Message.SetMessageWithEncoding("late", data.GSM7BIT) },
+			func(p *DeliverSM) { p.RegisterOptionalParam(Field{Tag: TagUserMessageReference, Data: []byte{0, 1}}) },
+		} {
+			p := NewDeliverSM()
+			markReleased(pool, p)
+
+			modify(p.(*DeliverSM))
+			require.PanicsWithValue(t, "pdu: DELIVER_SM is modified after release at "+releasedAt(t, p), func() {
+				pool.recycle(pt, p)
+			})
+		}
+	})
+
+	t.Run("arena", func(t *testing.T) {
+		arena := pool.NewArena()
+		submit, err := arena.Get(data.SUBMIT_SM)
+		require.Nil(t, err)
+		resp, err := arena.Decode(marshalPDU(submit.GetResponse()))
+		require.Nil(t, err)
+
+		arena.Free()
+		require.Panics(t, func() {
+			submit.Marshal(NewBuffer(nil))
+		})
+		require.Panics(t, func() {
+			resp.Marshal(NewBuffer(nil))
+		})
+		require.Empty(t, arena.pdus)
+	})
+}
+
+// markReleased releases p like Pool.Release, without handing it to the pool, so the test gets it back for sure.
+func markReleased(pool *Pool, p PDU) {
+	pool.release(pool.types[p.GetHeader().CommandID], p)
+}
+
+// releasedAt returns location p is released at, by Pool with release checks.
+func releasedAt(t *testing.T, p PDU) string {
+	released := baseOf(p).released
+	require.NotNil(t, released)
+	require.Contains(t, released.at, "Pool_test.go:")
+	return released.at
+}
+
+func marshalPDU(p PDU) []byte {
+	b := NewBuffer(nil)
+	p.Marshal(b)
+	return b.Bytes()
+}
+
+func BenchmarkPoolDecode(b *testing.B) {
+	deliver := NewDeliverSM().(*DeliverSM)
+	_ = deliver.Message.SetMessageWithEncoding("hello", data.GSM7BIT)
+	frame := marshalPDU(deliver)
+
+	b.Run("alloc", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _ = Decode(frame)
+		}
+	})
+
+	b.Run("pool", func(b *testing.B) {
+		pool := NewPool()
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			p, _ := pool.Decode(frame)
+			pool.Release(p)
+		}
+	})
+}
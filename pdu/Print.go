@@ -0,0 +1,230 @@
+package pdu
+
+import (
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/linxGnu/gosmpp/data"
+)
+
+var tagNames = map[Tag]string{
+	TagDestAddrSubunit:            "dest_addr_subunit",
+	TagDestNetworkType:            "dest_network_type",
+	TagDestBearerType:             "dest_bearer_type",
+	TagDestTelematicsID:           "dest_telematics_id",
+	TagSourceAddrSubunit:          "source_addr_subunit",
+	TagSourceNetworkType:          "source_network_type",
+	TagSourceBearerType:           "source_bearer_type",
+	TagSourceTelematicsID:         "source_telematics_id",
+	TagQosTimeToLive:              "qos_time_to_live",
+	TagPayloadType:                "payload_type",
+	TagAdditionalStatusInfoText:   "additional_status_info_text",
+	TagReceiptedMessageID:         "receipted_message_id",
+	TagMsMsgWaitFacilities:        "ms_msg_wait_facilities",
+	TagPrivacyIndicator:           "privacy_indicator",
+	TagSourceSubaddress:           "source_subaddress",
+	TagDestSubaddress:             "dest_subaddress",
+	TagUserMessageReference:       "user_message_reference",
+	TagUserResponseCode:           "user_response_code",
+	TagSourcePort:                 "source_port",
+	TagDestinationPort:            "destination_port",
+	TagSarMsgRefNum:               "sar_msg_ref_num",
+	TagLanguageIndicator:          "language_indicator",
+	TagSarTotalSegments:           "sar_total_segments",
+	TagSarSegmentSeqnum:           "sar_segment_seqnum",
+	TagScInterfaceVersion:         "sc_interface_version",
+	TagCallbackNumPresInd:         "callback_num_pres_ind",
+	TagCallbackNumAtag:            "callback_num_atag",
+	TagNumberOfMessages:           "number_of_messages",
+	TagCallbackNum:                "callback_num",
+	TagDpfResult:                  "dpf_result",
+	TagSetDpf:                     "set_dpf",
+	TagMsAvailabilityStatus:       "ms_availability_status",
+	TagNetworkErrorCode:           "network_error_code",
+	TagMessagePayload:             "message_payload",
+	TagDeliveryFailureReason:      "delivery_failure_reason",
+	TagMoreMessagesToSend:         "more_messages_to_send",
+	TagMessageStateOption:         "message_state",
+	TagCongestionState:            "congestion_state",
+	TagUssdServiceOp:              "ussd_service_op",
+	TagDisplayTime:                "display_time",
+	TagSmsSignal:                  "sms_signal",
+	TagMsValidity:                 "ms_validity",
+	TagAlertOnMessageDelivery:     "alert_on_message_delivery",
+	TagItsReplyType:               "its_reply_type",
+	TagItsSessionInfo:             "its_session_info",
+	TagBroadcastChannelIndicator:  "broadcast_channel_indicator",
+	TagBroadcastContentType:       "broadcast_content_type",
+	TagBroadcastContentTypeInfo:   "broadcast_content_type_info",
+	TagBroadcastMessageClass:      "broadcast_message_class",
+	TagBroadcastRepNum:            "broadcast_rep_num",
+	TagBroadcastFrequencyInterval: "broadcast_frequency_interval",
+	TagBroadcastAreaIdentifier:    "broadcast_area_identifier",
+	TagBroadcastErrorStatus:       "broadcast_error_status",
+	TagBroadcastAreaSuccess:       "broadcast_area_success",
+	TagBroadcastEndTime:           "broadcast_end_time",
+	TagBroadcastServiceGroup:      "broadcast_service_group",
+}
+
+// textTags are TLVs of C-octet strings.
+var textTags = map[Tag]bool{
+	TagAdditionalStatusInfoText: true,
+	TagReceiptedMessageID:       true,
+	TagBroadcastEndTime:         true,
+}
+
+// String returns name of tag as in SMPP specification, e.g. "sar_msg_ref_num", or its hex for unknown tags.
+func (t Tag) String() string {
+	if name, ok := tagNames[t]; ok {
+		return name
+	}
+	return "0x" + t.Hex()
+}
+
+// fieldNames are names of fields which differ from snake case of their Go names.
+var fieldNames = map[string]string{
+	"Message":   "short_message",
+	"DestAddrs": "dest_address",
+}
+
+// Sprint returns p on single line for logs and tools: command, header and then fields of the body in order
+// of the specification, and TLVs ordered by tag. Password is masked.
+//
+//	submit_sm seq=7 status=ESME_ROK service_type="" source_addr=5/0/"MyShop" dest_addr=1/1/"84901234567" ...
+//	 short_message="Hello" data_coding=0x00 sar_msg_ref_num=0x0001
+func Sprint(p PDU) string {
+	h := p.GetHeader()
+
+	var b strings.Builder
+	b.WriteString(strings.ToLower(h.CommandID.String()))
+	fmt.Fprintf(&b, " seq=%d status=%s", h.SequenceNumber, h.CommandStatus)
+
+	v := reflect.Indirect(reflect.ValueOf(p))
+	if v.Kind() == reflect.Struct {
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if field.Anonymous || !field.IsExported() || field.Type == reflect.TypeOf(BindingType(0)) {
+				continue
+			}
+			b.WriteByte(' ')
+			writeField(&b, fieldName(field.Name), v.Field(i).Interface())
+		}
+	}
+
+	if params := optionalParameters(p); len(params) > 0 {
+		tags := make([]Tag, 0, len(params))
+		for tag := range params {
+			tags = append(tags, tag)
+		}
+		sort.Slice(tags, func(i, j int) bool { return tags[i] < tags[j] })
+
+		for _, tag := range tags {
+			b.WriteByte(' ')
+			b.WriteString(tag.String())
+			b.WriteByte('=')
+			b.WriteString(tlvValue(tag, params[tag].Data))
+		}
+	}
+	return b.String()
+}
+
+func writeField(b *strings.Builder, name string, value interface{}) {
+	switch v := value.(type) {
+	case string:
+		if name == "password" && v != "" {
+			v = "***"
+		}
+		fmt.Fprintf(b, "%s=%q", name, v)
+	case byte:
+		fmt.Fprintf(b, "%s=0x%02X", name, v)
+	case Address:
+		fmt.Fprintf(b, "%s=%s", name, formatAddress(v))
+	case AddressRange:
+		fmt.Fprintf(b, "%s=%d/%d/%q", name, v.Ton, v.Npi, v.AddressRange)
+	case ShortMessage:
+		fmt.Fprintf(b, "%s=%s data_coding=0x%02X", name, formatShortMessage(&v), v.DataCoding())
+	case DestinationAddresses:
+		list := make([]string, 0, len(v.l))
+		for _, d := range v.l {
+			if d.IsDistributionList() {
+				list = append(list, fmt.Sprintf("dl:%q", d.dl.Name()))
+			} else {
+				list = append(list, formatAddress(d.address))
+			}
+		}
+		fmt.Fprintf(b, "%s=[%s]", name, strings.Join(list, " "))
+	case UnsuccessSMEs:
+		list := make([]string, 0, len(v.l))
+		for _, u := range v.l {
+			list = append(list, fmt.Sprintf("%s:%s", formatAddress(u.Address), u.ErrorStatusCode()))
+		}
+		fmt.Fprintf(b, "%s=[%s]", name, strings.Join(list, " "))
+	default:
+		fmt.Fprintf(b, "%s=%v", name, v)
+	}
+}
+
+func formatAddress(a Address) string {
+	return fmt.Sprintf("%d/%d/%q", a.Ton(), a.Npi(), a.Address())
+}
+
+func formatShortMessage(m *ShortMessage) string {
+	var s string
+	if udh := m.UDH(); len(udh) > 0 {
+		if b, err := udh.MarshalBinary(); err == nil {
+			s = "udh:" + hex.EncodeToString(b) + " "
+		}
+	}
+
+	enc := m.Encoding()
+	if enc != nil && enc != data.BINARY8BIT1 && enc != data.BINARY8BIT2 {
+		if text, err := m.GetMessage(); err == nil {
+			return s + fmt.Sprintf("%q", text)
+		}
+	}
+	d, _ := m.GetMessageData()
+	return s + "0x" + hex.EncodeToString(d)
+}
+
+func tlvValue(tag Tag, v []byte) string {
+	if textTags[tag] {
+		if l := len(v); l > 0 && v[l-1] == 0 {
+			v = v[:l-1]
+		}
+		return fmt.Sprintf("%q", v)
+	}
+	return "0x" + hex.EncodeToString(v)
+}
+
+func optionalParameters(p PDU) map[Tag]Field {
+	if v, ok := p.(interface{ optionalParameters() map[Tag]Field }); ok {
+		return v.optionalParameters()
+	}
+	return nil
+}
+
+func (c *base) optionalParameters() map[Tag]Field {
+	return c.OptionalParameters
+}
+
+// fieldName returns name of field of PDU in snake case, e.g. "protocol_id" of ProtocolID.
+func fieldName(goName string) string {
+	if name, ok := fieldNames[goName]; ok {
+		return name
+	}
+
+	runes := []rune(goName)
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) &&
+			(unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]) && runes[i+1] != 's')) {
+			b.WriteByte('_')
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
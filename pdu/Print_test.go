@@ -0,0 +1,59 @@
+package pdu
+
+import (
+	"testing"
+
+	"github.com/linxGnu/gosmpp/data"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSprint(t *testing.T) {
+	t.Run("submit_sm", func(t *testing.T) {
+		v := NewSubmitSM().(*SubmitSM)
+		v.SequenceNumber = 7
+		_ = v.SourceAddr.SetAddress("MyShop")
+		v.SourceAddr.SetTon(5)
+		_ = v.DestAddr.SetAddress("84901234567")
+		v.DestAddr.SetTon(1)
+		v.DestAddr.SetNpi(1)
+		v.RegisteredDelivery = 1
+		_ = v.Message.SetMessageWithEncoding("Hello", data.GSM7BIT)
+		v.RegisterOptionalParam(Field{Tag: TagSarMsgRefNum, Data: []byte{0, 1}})
+		v.RegisterOptionalParam(Field{Tag: TagReceiptedMessageID, Data: []byte("abc\x00")})
+
+		require.Equal(t, `submit_sm seq=7 status=ESME_ROK service_type="" source_addr=5/0/"MyShop" `+
+			`dest_addr=1/1/"84901234567" esm_class=0x00 protocol_id=0x00 priority_flag=0x00 `+
+			`schedule_delivery_time="" validity_period="" registered_delivery=0x01 replace_if_present_flag=0x00 `+
+			`short_message="Hello" data_coding=0x00 receipted_message_id="abc" sar_msg_ref_num=0x0001`, Sprint(v))
+	})
+
+	t.Run("binary", func(t *testing.T) {
+		v := NewDeliverSM().(*DeliverSM)
+		_ = v.Message.SetMessageDataWithEncoding([]byte{0xCA, 0xFE}, data.BINARY8BIT2)
+		require.Contains(t, Sprint(v), ` short_message=0xcafe data_coding=0x04`)
+	})
+
+	t.Run("bind masks password", func(t *testing.T) {
+		v := NewBindRequest(Transceiver)
+		v.SystemID = "shop"
+		v.Password = "secret"
+		s := Sprint(v)
+		require.Contains(t, s, `bind_transceiver seq=`)
+		require.Contains(t, s, `system_id="shop" password="***"`)
+		require.NotContains(t, s, "secret")
+	})
+
+	t.Run("response", func(t *testing.T) {
+		v := NewSubmitSMResp().(*SubmitSMResp)
+		v.SequenceNumber = 3
+		v.CommandStatus = data.ESME_RTHROTTLED
+		v.MessageID = "id-1"
+		require.Equal(t, `submit_sm_resp seq=3 status=ESME_RTHROTTLED message_id="id-1"`, Sprint(v))
+	})
+}
+
+func TestTagString(t *testing.T) {
+	require.Equal(t, "message_payload", TagMessagePayload.String())
+	require.Equal(t, "0x1400", Tag(0x1400).String())
+}
@@ -16,6 +16,7 @@ type ShortMessage struct {
 	enc               data.Encoding
 	udHeader          UDH
 	messageData       []byte
+	dataCoding        byte // as received, if encoding is unknown
 	withoutDataCoding bool // purpose of ReplaceSM usage
 }
 
@@ -57,6 +58,49 @@ func NewLongMessageWithEncoding(message string, enc data.Encoding) (s []*ShortMe
 	return sm.split()
 }
 
+// NewBinaryMessages returns payload in 8-bit binary short messages with user data header udh, e.g. application
+// port addressing. Payload not fitting into single message is split into parts with concat message IE prepended.
+func NewBinaryMessages(payload []byte, udh UDH) (s []*ShortMessage, err error) {
+	single := udh.UDHL()
+	if single < 0 {
+		return nil, errors.ErrUDHTooLong
+	}
+
+	if len(payload)+single <= data.SM_GSM_MSG_LEN {
+		s = []*ShortMessage{{enc: data.BINARY8BIT2, messageData: payload, udHeader: udh}}
+		return
+	}
+
+	// concat message IE takes 5 octets, 6 if there is no other IE
+	limit := data.SM_GSM_MSG_LEN - single - 5
+	if single == 0 {
+		limit--
+	}
+	if limit <= 0 {
+		return nil, errors.ErrUDHTooLong
+	}
+
+	total := (len(payload) + limit - 1) / limit
+	if total > 255 {
+		return nil, errors.ErrShortMessageLengthTooLarge
+	}
+
+	ref := getRefNum()
+	for i := 0; i < total; i++ {
+		end := (i + 1) * limit
+		if end > len(payload) {
+			end = len(payload)
+		}
+
+		s = append(s, &ShortMessage{
+			enc:         data.BINARY8BIT2,
+			messageData: payload[i*limit : end],
+			udHeader:    append(UDH{NewIEConcatMessage(uint8(total), uint8(i+1), uint8(ref))}, udh...),
+		})
+	}
+	return
+}
+
 // SetMessageWithEncoding sets message with encoding.
 func (c *ShortMessage) SetMessageWithEncoding(message string, enc data.Encoding) (err error) {
 	if c.messageData, err = enc.Encode(message); err == nil {
@@ -191,6 +235,37 @@ func (c *ShortMessage) split() (multiSM []*ShortMessage, err error) {
 	return
 }
 
+// splitSAR splits long message like split, but parts carry no UDH: they are linked by sar_* optional parameters
+// of their PDUs, so every part but the last takes whole 140 octets.
+func (c *ShortMessage) splitSAR() (multiSM []*ShortMessage, err error) {
+	encoding := c.enc
+	if encoding == nil {
+		encoding = data.GSM7BIT
+	}
+
+	splitter, ok := encoding.(data.Splitter)
+	if !ok || !splitter.ShouldSplit(c.message, data.SM_GSM_MSG_LEN) {
+		err = c.SetMessageWithEncoding(c.message, c.enc)
+		multiSM = []*ShortMessage{c}
+		return
+	}
+
+	segments, err := splitter.EncodeSplit(c.message, data.SM_GSM_MSG_LEN)
+	if err != nil {
+		return nil, err
+	}
+
+	multiSM = make([]*ShortMessage, 0, len(segments))
+	for _, seg := range segments {
+		multiSM = append(multiSM, &ShortMessage{
+			enc:               c.enc,
+			messageData:       seg,
+			withoutDataCoding: c.withoutDataCoding,
+		})
+	}
+	return
+}
+
 // Marshal implements PDU interface.
 func (c *ShortMessage) Marshal(b *ByteBuffer) {
 	var (
@@ -253,7 +328,9 @@ func (c *ShortMessage) Unmarshal(b *ByteBuffer, udhi bool) (err error) {
 	if c.messageData, err = b.ReadN(int(n)); err != nil {
 		return
 	}
-	c.enc = data.FromDataCoding(dataCoding)
+	if c.enc = data.FromDataCoding(dataCoding); c.enc == nil {
+		c.dataCoding = dataCoding
+	}
 
 	// If short message length is non zero, short message contains User-Data Header
 	// Else UDH should be in TLV field MessagePayload
@@ -283,6 +360,15 @@ func (c *ShortMessage) Encoding() data.Encoding {
 	return c.enc
 }
 
+// DataCoding returns data_coding of the message: of its encoding, or as received if encoding is unknown,
+// e.g. with message class.
+func (c *ShortMessage) DataCoding() byte {
+	if c.enc != nil {
+		return c.enc.DataCoding()
+	}
+	return c.dataCoding
+}
+
 // returns an atomically incrementing number each time it's called
 func getRefNum() uint32 {
 	return atomic.AddUint32(&ref, 1)
@@ -63,6 +63,16 @@ func TestShortMessage(t *testing.T) {
 		require.Equal(t, "abc", m)
 	})
 
+	t.Run("dataCoding", func(t *testing.T) {
+		var s ShortMessage
+		require.Nil(t, s.Unmarshal(NewBuffer([]byte{0x11, 0x00, 0x03, 0x61, 0x62, 0x63}), false))
+		require.Nil(t, s.Encoding())
+		require.EqualValues(t, 0x11, s.DataCoding())
+
+		require.Nil(t, s.SetMessageWithEncoding("abc", data.UCS2))
+		require.EqualValues(t, data.UCS2Coding, s.DataCoding())
+	})
+
 	t.Run("getMessageData", func(t *testing.T) {
 		s, err := NewBinaryShortMessage([]byte{0x00, 0x01, 0x02, 0x03})
 		require.NoError(t, err)
@@ -205,4 +215,41 @@ func TestShortMessage(t *testing.T) {
 			require.Equal(t, b1.Bytes(), b2.Bytes())
 		}
 	})
+
+	t.Run("binaryMessages", func(t *testing.T) {
+		port := UDH{NewIEApplicationPort(9204, 0)}
+
+		sm, err := NewBinaryMessages(make([]byte, 133), port)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(sm))
+		require.Equal(t, data.BINARY8BIT2, sm[0].Encoding())
+		require.Equal(t, port, sm[0].UDH())
+
+		payload := make([]byte, 300)
+		for i := range payload {
+			payload[i] = byte(i)
+		}
+		sm, err = NewBinaryMessages(payload, port)
+		require.NoError(t, err)
+		require.Equal(t, 3, len(sm))
+
+		var joined []byte
+		for i, m := range sm {
+			total, num, _, found := m.UDH().GetConcatInfo()
+			require.True(t, found)
+			require.Equal(t, byte(3), total)
+			require.Equal(t, byte(i+1), num)
+
+			_, _, found = m.UDH().GetApplicationPort()
+			require.True(t, found)
+			require.LessOrEqual(t, m.UDH().UDHL()+len(m.messageData), data.SM_GSM_MSG_LEN)
+			joined = append(joined, m.messageData...)
+		}
+		require.Equal(t, payload, joined)
+
+		sm, err = NewBinaryMessages(make([]byte, 141), nil)
+		require.NoError(t, err)
+		require.Equal(t, 2, len(sm))
+		require.Equal(t, 134, len(sm[0].messageData))
+	})
 }
@@ -95,6 +95,49 @@ func (c *SubmitSM) Split() (multiSubSM []*SubmitSM, err error) {
 	return
 }
 
+// SplitSAR splits a single long text message into multiple SubmitSM PDU like Split, but parts are linked by
+// sar_msg_ref_num, sar_total_segments and sar_segment_seqnum optional parameters instead of concatenation UDH,
+// for SMSC expecting them. Each part has its own copy of optional parameters of c.
+//
+// If the message doesn't need splitting, SplitSAR returns an array of length 1, without the parameters.
+func (c *SubmitSM) SplitSAR() (multiSubSM []*SubmitSM, err error) {
+	multiMsg, err := c.Message.splitSAR()
+	if err != nil {
+		return
+	}
+
+	ref := uint16(getRefNum())
+	multiSubSM = make([]*SubmitSM, 0, len(multiMsg))
+	for i, msg := range multiMsg {
+		part := &SubmitSM{
+			base:                 c.base,
+			ServiceType:          c.ServiceType,
+			SourceAddr:           c.SourceAddr,
+			DestAddr:             c.DestAddr,
+			EsmClass:             c.EsmClass,
+			ProtocolID:           c.ProtocolID,
+			PriorityFlag:         c.PriorityFlag,
+			ScheduleDeliveryTime: c.ScheduleDeliveryTime,
+			ValidityPeriod:       c.ValidityPeriod,
+			RegisteredDelivery:   c.RegisteredDelivery,
+			ReplaceIfPresentFlag: c.ReplaceIfPresentFlag,
+			Message:              *msg,
+		}
+
+		if len(multiMsg) > 1 {
+			part.OptionalParameters = make(map[Tag]Field, len(c.OptionalParameters)+3)
+			for tag, field := range c.OptionalParameters {
+				part.OptionalParameters[tag] = field
+			}
+			part.RegisterOptionalParam(Field{Tag: TagSarMsgRefNum, Data: []byte{byte(ref >> 8), byte(ref)}})
+			part.RegisterOptionalParam(Field{Tag: TagSarTotalSegments, Data: []byte{byte(len(multiMsg))}})
+			part.RegisterOptionalParam(Field{Tag: TagSarSegmentSeqnum, Data: []byte{byte(i + 1)}})
+		}
+		multiSubSM = append(multiSubSM, part)
+	}
+	return
+}
+
 // Marshal implements PDU interface.
 func (c *SubmitSM) Marshal(b *ByteBuffer) {
 	c.base.marshal(b, func(b *ByteBuffer) {
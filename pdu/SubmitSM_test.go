@@ -1,6 +1,7 @@
 package pdu
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/linxGnu/gosmpp/data"
@@ -41,3 +42,40 @@ func TestSubmitSM(t *testing.T) {
 		data.SUBMIT_SM,
 	)
 }
+
+func TestSubmitSMSplitSAR(t *testing.T) {
+	text := strings.Repeat("abcdefghij", 20)
+
+	v := NewSubmitSM().(*SubmitSM)
+	v.RegisterOptionalParam(Field{Tag: TagUserMessageReference, Data: []byte{0, 7}})
+	require.NoError(t, v.Message.SetLongMessageWithEnc(text, data.GSM7BIT))
+
+	parts, err := v.SplitSAR()
+	require.NoError(t, err)
+	require.Len(t, parts, 2)
+
+	var joined string
+	for i, part := range parts {
+		require.Zero(t, part.EsmClass&data.SM_UDH_GSM)
+		require.Nil(t, part.Message.UDH())
+		require.LessOrEqual(t, len(part.Message.messageData), data.SM_GSM_MSG_LEN)
+
+		require.Equal(t, parts[0].OptionalParameters[TagSarMsgRefNum], part.OptionalParameters[TagSarMsgRefNum])
+		require.Equal(t, []byte{2}, part.OptionalParameters[TagSarTotalSegments].Data)
+		require.Equal(t, []byte{byte(i + 1)}, part.OptionalParameters[TagSarSegmentSeqnum].Data)
+		require.Contains(t, part.OptionalParameters, TagUserMessageReference)
+
+		message, err := part.Message.GetMessage()
+		require.NoError(t, err)
+		joined += message
+	}
+	require.Equal(t, text, joined)
+	require.Len(t, v.OptionalParameters, 1)
+
+	// short message is not split
+	require.NoError(t, v.Message.SetLongMessageWithEnc("short", data.GSM7BIT))
+	parts, err = v.SplitSAR()
+	require.NoError(t, err)
+	require.Len(t, parts, 1)
+	require.NotContains(t, parts[0].OptionalParameters, TagSarMsgRefNum)
+}
@@ -165,6 +165,20 @@ func (u UDH) GetConcatInfo() (totalParts, partNum, mref byte, found bool) {
 	return
 }
 
+// GetApplicationPort return the FIRST application port addressing IE, 8-bit or 16-bit.
+func (u UDH) GetApplicationPort() (destPort, srcPort uint16, found bool) {
+	for i := range u {
+		switch ie := u[i]; {
+		case ie.ID == data.UDH_APP_PORT_16_BIT && len(ie.Data) == 4:
+			return uint16(ie.Data[0])<<8 | uint16(ie.Data[1]), uint16(ie.Data[2])<<8 | uint16(ie.Data[3]), true
+
+		case ie.ID == data.UDH_APP_PORT_8_BIT && len(ie.Data) == 2:
+			return uint16(ie.Data[0]), uint16(ie.Data[1]), true
+		}
+	}
+	return
+}
+
 // InfoElement represent a 3 parts Information-Element
 // as defined in 3GPP TS 23.040 Section 9.2.3.24
 // Each InfoElement is comprised of it's identifier and data
@@ -182,6 +196,14 @@ func NewIEConcatMessage(totalParts, partNum, mref byte) InfoElement {
 	}
 }
 
+// NewIEApplicationPort returns IE for application port addressing with 16-bit ports.
+func NewIEApplicationPort(destPort, srcPort uint16) InfoElement {
+	return InfoElement{
+		ID:   data.UDH_APP_PORT_16_BIT,
+		Data: []byte{byte(destPort >> 8), byte(destPort), byte(srcPort >> 8), byte(srcPort)},
+	}
+}
+
 // UnmarshalBinary unmarshal IE from binary in src, only read a single IE,
 // expect src at least of length 2 with correct IE format:
 //
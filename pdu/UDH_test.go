@@ -27,6 +27,26 @@ func TestUserDataHeader(t *testing.T) {
 		require.Equal(t, reference, uint8(12))
 	})
 
+	t.Run("marshalBinaryUDHApplicationPort", func(t *testing.T) {
+		u := UDH{NewIEApplicationPort(2948, 9200)}
+		b, err := u.MarshalBinary()
+		require.NoError(t, err)
+		require.Equal(t, "0605040b8423f0", toHex(b))
+
+		destPort, srcPort, found := u.GetApplicationPort()
+		require.True(t, found)
+		require.Equal(t, uint16(2948), destPort)
+		require.Equal(t, uint16(9200), srcPort)
+
+		destPort, srcPort, found = UDH{{ID: 0x04, Data: []byte{0x10, 0x20}}}.GetApplicationPort()
+		require.True(t, found)
+		require.Equal(t, uint16(0x10), destPort)
+		require.Equal(t, uint16(0x20), srcPort)
+
+		_, _, found = UDH{NewIEConcatMessage(2, 1, 12)}.GetApplicationPort()
+		require.False(t, found)
+	})
+
 	t.Run("unmarshalBinaryUDHConcatMessage", func(t *testing.T) {
 		u, rd := new(UDH), []byte{0x05, 0x00, 0x03, 0x0c, 0x02, 0x01}
 		read, err := u.UnmarshalBinary(rd)
@@ -0,0 +1,183 @@
+package pdu
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/linxGnu/gosmpp/data"
+)
+
+// Deviation is departure of decoded PDU from SMPP specification which decoding tolerates, e.g. too long field
+// or reserved value.
+type Deviation struct {
+	// Field is name of the deviating field, as named by the specification.
+	Field string
+
+	// Reason tells how the field deviates.
+	Reason string
+
+	// Status is command status of negative response to the request having the deviation.
+	Status data.CommandStatusType
+}
+
+// Error implements error interface.
+func (d Deviation) Error() string {
+	return d.Field + ": " + d.Reason
+}
+
+// ValidationError lists all deviations of PDU found by Validate.
+type ValidationError struct {
+	CommandID  data.CommandIDType
+	Deviations []Deviation
+}
+
+// Error implements error interface.
+func (e *ValidationError) Error() string {
+	reasons := make([]string, 0, len(e.Deviations))
+	for _, d := range e.Deviations {
+		reasons = append(reasons, d.Error())
+	}
+	return fmt.Sprintf("%s deviates from specification: %s", e.CommandID, strings.Join(reasons, "; "))
+}
+
+// Status returns command status of negative response to the request, the status of its first deviation.
+func (e *ValidationError) Status() data.CommandStatusType {
+	return e.Deviations[0].Status
+}
+
+// Validate checks decoded p against SMPP specification: lengths of its C-Octet String fields, including
+// the NULL terminator, and reserved values of its header and fields.
+//
+// Decoding accepts such PDUs as long as they could be read, Validate is applied on top of it when they
+// should be rejected. It returns *ValidationError listing all deviations found, nil if there is none.
+func Validate(p PDU) error {
+	var v validator
+
+	h := p.GetHeader()
+	if h.CommandID >= 0 && h.CommandStatus != data.ESME_ROK {
+		v.deviate("command_status", "must be NULL in request", data.ESME_RINVPARAM)
+	}
+	if h.SequenceNumber <= 0 && h.CommandID != data.GENERIC_NACK {
+		v.deviate("sequence_number", "out of range 0x00000001 to 0x7FFFFFFF", data.ESME_RINVPARAM)
+	}
+
+	switch pp := p.(type) {
+	case *BindRequest:
+		v.cstring("system_id", pp.SystemID, data.SM_SYSID_LEN, data.ESME_RINVSYSID)
+		v.cstring("password", pp.Password, data.SM_PASS_LEN, data.ESME_RINVPASWD)
+		v.cstring("system_type", pp.SystemType, data.SM_SYSTYPE_LEN, data.ESME_RINVSYSTYP)
+		v.tonNpi("addr_ton", "addr_npi", pp.AddressRange.Ton, pp.AddressRange.Npi, data.ESME_RINVPARAM, data.ESME_RINVPARAM)
+		v.cstring("address_range", pp.AddressRange.AddressRange, data.SM_ADDR_RANGE_LEN, data.ESME_RINVPARAM)
+
+	case *BindResp:
+		v.cstring("system_id", pp.SystemID, data.SM_SYSID_LEN, data.ESME_RINVSYSID)
+
+	case *SubmitSM:
+		v.cstring("service_type", pp.ServiceType, data.SM_SRVTYPE_LEN, data.ESME_RINVSERTYP)
+		v.source(pp.SourceAddr, data.SM_ADDR_LEN)
+		v.destination(pp.DestAddr, data.SM_ADDR_LEN)
+		v.priority(pp.PriorityFlag)
+		v.time("schedule_delivery_time", pp.ScheduleDeliveryTime, data.ESME_RINVSCHED)
+		v.time("validity_period", pp.ValidityPeriod, data.ESME_RINVEXPIRY)
+		v.registeredDelivery(pp.RegisteredDelivery)
+		v.replaceIfPresent(pp.ReplaceIfPresentFlag)
+
+	case *DeliverSM:
+		v.cstring("service_type", pp.ServiceType, data.SM_SRVTYPE_LEN, data.ESME_RINVSERTYP)
+		v.source(pp.SourceAddr, data.SM_ADDR_LEN)
+		v.destination(pp.DestAddr, data.SM_ADDR_LEN)
+		v.priority(pp.PriorityFlag)
+		v.registeredDelivery(pp.RegisteredDelivery)
+
+	case *SubmitMulti:
+		v.cstring("service_type", pp.ServiceType, data.SM_SRVTYPE_LEN, data.ESME_RINVSERTYP)
+		v.source(pp.SourceAddr, data.SM_ADDR_LEN)
+		v.priority(pp.PriorityFlag)
+		v.time("schedule_delivery_time", pp.ScheduleDeliveryTime, data.ESME_RINVSCHED)
+		v.time("validity_period", pp.ValidityPeriod, data.ESME_RINVEXPIRY)
+		v.registeredDelivery(pp.RegisteredDelivery)
+		v.replaceIfPresent(pp.ReplaceIfPresentFlag)
+
+	case *DataSM:
+		v.cstring("service_type", pp.ServiceType, data.SM_SRVTYPE_LEN, data.ESME_RINVSERTYP)
+		v.source(pp.SourceAddr, data.SM_DATA_ADDR_LEN)
+		v.destination(pp.DestAddr, data.SM_DATA_ADDR_LEN)
+		v.registeredDelivery(pp.RegisteredDelivery)
+
+	case *SubmitSMResp:
+		v.cstring("message_id", pp.MessageID, data.SM_MSGID_LEN+1, data.ESME_RINVPARAM)
+
+	case *DeliverSMResp:
+		v.cstring("message_id", pp.MessageID, data.SM_MSGID_LEN+1, data.ESME_RINVPARAM)
+
+	case *DataSMResp:
+		v.cstring("message_id", pp.MessageID, data.SM_MSGID_LEN+1, data.ESME_RINVPARAM)
+	}
+
+	if len(v.deviations) == 0 {
+		return nil
+	}
+	return &ValidationError{CommandID: h.CommandID, Deviations: v.deviations}
+}
+
+// validator collects deviations of PDU fields.
+type validator struct {
+	deviations []Deviation
+}
+
+func (v *validator) deviate(field, reason string, status data.CommandStatusType) {
+	v.deviations = append(v.deviations, Deviation{Field: field, Reason: reason, Status: status})
+}
+
+// cstring checks C-Octet String fits max octets, the NULL terminator included.
+func (v *validator) cstring(field, value string, max int, status data.CommandStatusType) {
+	if len(value) >= max {
+		v.deviate(field, fmt.Sprintf("%d octets exceed maximum of %d", len(value)+1, max), status)
+	}
+}
+
+func (v *validator) tonNpi(tonField, npiField string, ton, npi byte, tonStatus, npiStatus data.CommandStatusType) {
+	if int(ton) >= len(tonNames) {
+		v.deviate(tonField, fmt.Sprintf("reserved value %d", ton), tonStatus)
+	}
+	if _, ok := npiNames[npi]; !ok {
+		v.deviate(npiField, fmt.Sprintf("reserved value %d", npi), npiStatus)
+	}
+}
+
+func (v *validator) source(a Address, max int) {
+	v.tonNpi("source_addr_ton", "source_addr_npi", a.Ton(), a.Npi(), data.ESME_RINVSRCTON, data.ESME_RINVSRCNPI)
+	v.cstring("source_addr", a.Address(), max, data.ESME_RINVSRCADR)
+}
+
+func (v *validator) destination(a Address, max int) {
+	v.tonNpi("dest_addr_ton", "dest_addr_npi", a.Ton(), a.Npi(), data.ESME_RINVDSTTON, data.ESME_RINVDSTNPI)
+	v.cstring("destination_addr", a.Address(), max, data.ESME_RINVDSTADR)
+}
+
+// priority checks priority_flag, levels 0 to 4 are defined across SMPP 3.4 and 5.0.
+func (v *validator) priority(flag byte) {
+	if flag > 4 {
+		v.deviate("priority_flag", fmt.Sprintf("reserved value %d", flag), data.ESME_RINVPRTFLG)
+	}
+}
+
+// time checks absolute or relative time format, which is empty or 16 characters.
+func (v *validator) time(field, value string, status data.CommandStatusType) {
+	if len(value) != 0 && len(value) != data.SM_DATE_LEN-1 {
+		v.deviate(field, fmt.Sprintf("%d characters instead of %d", len(value), data.SM_DATE_LEN-1), status)
+	}
+}
+
+// registeredDelivery checks reserved receipt value 3 and reserved bits 5 to 7 of registered_delivery.
+func (v *validator) registeredDelivery(flag byte) {
+	if flag&0x03 == 0x03 || flag&0xE0 != 0 {
+		v.deviate("registered_delivery", fmt.Sprintf("reserved bits set in 0x%02X", flag), data.ESME_RINVREGDLVFLG)
+	}
+}
+
+func (v *validator) replaceIfPresent(flag byte) {
+	if flag > 1 {
+		v.deviate("replace_if_present_flag", fmt.Sprintf("reserved value %d", flag), data.ESME_RINVREPFLAG)
+	}
+}
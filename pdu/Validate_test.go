@@ -0,0 +1,88 @@
+package pdu
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/linxGnu/gosmpp/data"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		submit := NewSubmitSM().(*SubmitSM)
+		_ = submit.SourceAddr.SetAddress("MyShop")
+		submit.SourceAddr.SetTon(5)
+		submit.ValidityPeriod = "000001000000000R"
+		submit.RegisteredDelivery = 1
+		require.Nil(t, Validate(submit))
+
+		bind := NewBindRequest(Transceiver)
+		bind.SystemID = "esme"
+		bind.Password = "12345678"
+		require.Nil(t, Validate(bind))
+
+		resp := NewSubmitSMResp().(*SubmitSMResp)
+		resp.MessageID = strings.Repeat("f", data.SM_MSGID_LEN)
+		require.Nil(t, Validate(resp))
+
+		nack := NewGenericNack()
+		nack.SetSequenceNumber(0)
+		require.Nil(t, Validate(nack))
+	})
+
+	t.Run("deviations", func(t *testing.T) {
+		deliver := NewDeliverSM().(*DeliverSM)
+		deliver.CommandStatus = data.ESME_RSYSERR
+		deliver.ServiceType = "SERVICE"
+		deliver.SourceAddr.SetTon(7)
+		deliver.SourceAddr.SetNpi(2)
+		_ = deliver.DestAddr.SetAddress(strings.Repeat("1", data.SM_ADDR_LEN))
+		deliver.PriorityFlag = 5
+		deliver.RegisteredDelivery = 0x21
+
+		err := Validate(deliver)
+		require.NotNil(t, err)
+
+		v := err.(*ValidationError)
+		require.Equal(t, data.DELIVER_SM, v.CommandID)
+		require.Equal(t, []Deviation{
+			{Field: "command_status", Reason: "must be NULL in request", Status: data.ESME_RINVPARAM},
+			{Field: "service_type", Reason: "8 octets exceed maximum of 6", Status: data.ESME_RINVSERTYP},
+			{Field: "source_addr_ton", Reason: "reserved value 7", Status: data.ESME_RINVSRCTON},
+			{Field: "source_addr_npi", Reason: "reserved value 2", Status: data.ESME_RINVSRCNPI},
+			{Field: "destination_addr", Reason: "22 octets exceed maximum of 21", Status: data.ESME_RINVDSTADR},
+			{Field: "priority_flag", Reason: "reserved value 5", Status: data.ESME_RINVPRTFLG},
+			{Field: "registered_delivery", Reason: "reserved bits set in 0x21", Status: data.ESME_RINVREGDLVFLG},
+		}, v.Deviations)
+		require.Equal(t, data.ESME_RINVPARAM, v.Status())
+		require.True(t, strings.HasPrefix(err.Error(), "DELIVER_SM deviates from specification: command_status: must be NULL in request; "))
+	})
+
+	t.Run("times", func(t *testing.T) {
+		submit := NewSubmitSM().(*SubmitSM)
+		submit.ScheduleDeliveryTime = "2406011200"
+		submit.ReplaceIfPresentFlag = 2
+
+		err := Validate(submit).(*ValidationError)
+		require.Equal(t, []Deviation{
+			{Field: "schedule_delivery_time", Reason: "10 characters instead of 16", Status: data.ESME_RINVSCHED},
+			{Field: "replace_if_present_flag", Reason: "reserved value 2", Status: data.ESME_RINVREPFLAG},
+		}, err.Deviations)
+	})
+
+	t.Run("decoded", func(t *testing.T) {
+		bind := NewBindRequest(Receiver)
+		bind.SystemID = strings.Repeat("s", data.SM_SYSID_LEN)
+
+		b := NewBuffer(nil)
+		bind.Marshal(b)
+		p, err := Decode(b.Bytes())
+		require.Nil(t, err)
+
+		err = Validate(p)
+		require.NotNil(t, err)
+		require.Equal(t, data.ESME_RINVSYSID, err.(*ValidationError).Status())
+	})
+}
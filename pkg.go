@@ -4,10 +4,13 @@ import (
 	"io"
 	"time"
 
+	"github.com/linxGnu/gosmpp/clock"
 	"github.com/linxGnu/gosmpp/pdu"
 )
 
 // Transceiver interface.
+//
+// Submit is safe for concurrent use, with guarantees of Session.Submit.
 type Transceiver interface {
 	io.Closer
 	Submit(pdu.PDU) error
@@ -15,6 +18,8 @@ type Transceiver interface {
 }
 
 // Transmitter interface.
+//
+// Submit is safe for concurrent use, with guarantees of Session.Submit.
 type Transmitter interface {
 	io.Closer
 	Submit(pdu.PDU) error
@@ -41,6 +46,7 @@ type Settings struct {
 
 	// EnquireLink periodically sends EnquireLink to SMSC.
 	// The duration must not be smaller than 1 minute.
+	// Requests not responded within ReadTimeout are forgotten on every tick and counted as timeouts.
 	//
 	// Zero duration disables auto enquire link.
 	EnquireLink time.Duration
@@ -63,6 +69,21 @@ type Settings struct {
 	// Will be ignored if WindowedRequestTracking is set
 	OnAllPDU AllPDUCallback
 
+	// OnMessage handles mobile originated messages received in deliver_sm and data_sm, decoded by
+	// ParseIncomingMessage. PDUs are responded automatically and not passed to OnPDU.
+	//
+	// Will be ignored if OnAllPDU or WindowedRequestTracking is set
+	OnMessage MessageCallback
+
+	// OnDeliveryReceipt handles delivery receipts received in deliver_sm, decoded by ParseReceipt.
+	// PDUs are responded automatically and not passed to OnPDU.
+	//
+	// With OnMessage and OnDeliveryReceipt set, OnPDU receives the other PDUs only, e.g. intermediate
+	// notifications or PDUs which could not be decoded.
+	//
+	// Will be ignored if OnAllPDU or WindowedRequestTracking is set
+	OnDeliveryReceipt ReceiptCallback
+
 	// OnReceivingError notifies happened error while reading PDU
 	// from SMSC.
 	OnReceivingError ErrorCallback
@@ -79,9 +100,45 @@ type Settings struct {
 	// OnRebind notifies `rebind` event due to State.
 	OnRebind RebindCallback
 
+	// OnHandlerPanic notifies panic recovered from user callbacks (including event handlers).
+	//
+	// If set, panics of callbacks are recovered so daemons of the bind keep running. Callbacks
+	// returning values are treated as returning zero values. If not set, panics are not recovered.
+	OnHandlerPanic HandlerPanicCallback
+
 	// SMPP Bind Window tracking feature config
 	*WindowedRequestTracking
 
+	// Clock drives enquire_link, window expiry and rebinding timers.
+	//
+	// Defaults to clock.Real, set clock.Fake to test code built on gosmpp without real sleeps.
+	Clock clock.Clock
+
+	// Reactor lets many sessions share writer and timer goroutines, see Reactor.
+	//
+	// Nil means every bind runs its own daemons.
+	Reactor *Reactor
+
+	// ReceiveWorkers handles requests received from SMSC (deliver_sm, data_sm, ...) on that many goroutines,
+	// so that slow handlers do not stall reading of responses and enquire_link on the same connection.
+	// Requests of the same source address are handled by the same goroutine, in order of receiving.
+	//
+	// Zero handles requests on the reading goroutine.
+	ReceiveWorkers int
+
+	// Validation tells how PDUs received from SMSC deviating from SMPP specification are treated, see pdu.Validate.
+	//
+	// Defaults to ValidationPermissive.
+	Validation ValidationMode
+
+	// MaxCommandLength caps command_length of PDUs exchanged with SMSC, for SMSC accepting smaller PDUs than
+	// data.MAX_PDU_LEN, e.g. 4096. Submit of larger PDU fails with *pdu.SizeError instead of being rejected by
+	// SMSC with ESME_RINVMSGLEN; larger PDUs received are answered with generic_nack of ESME_RINVCMDLEN and
+	// reported to OnReceivingError.
+	//
+	// Zero means no limit.
+	MaxCommandLength int
+
 	response func(pdu.PDU)
 }
 
@@ -0,0 +1,56 @@
+package gosmpp
+
+import (
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+)
+
+// MessagePreview is how text would be sent by Messenger.SendText, e.g. for character counter of a compose form.
+type MessagePreview struct {
+	// Encoding is GSM 7-bit if text could be encoded in it, UCS2 otherwise.
+	Encoding data.Encoding
+
+	// Segments are texts of the parts, as shown by handset once received, in order.
+	Segments []string
+
+	// UCS2Chars are distinct characters of text which could not be encoded in GSM 7-bit, in order of
+	// appearance. Nil for GSM 7-bit text.
+	UCS2Chars []rune
+}
+
+// SegmentCount returns number of parts.
+func (p MessagePreview) SegmentCount() int {
+	return len(p.Segments)
+}
+
+// PreviewMessage returns encoding and parts text is sent in, split exactly as by Messenger.SendText.
+func PreviewMessage(text string) (p MessagePreview, err error) {
+	p.Encoding = textEncoding(text)
+	if p.Encoding == data.UCS2 {
+		seen := make(map[rune]bool)
+		for _, r := range data.ValidateGSM7String(text) {
+			if !seen[r] {
+				seen[r] = true
+				p.UCS2Chars = append(p.UCS2Chars, r)
+			}
+		}
+	}
+
+	submit := pdu.NewSubmitSM().(*pdu.SubmitSM)
+	if err = submit.Message.SetLongMessageWithEnc(text, p.Encoding); err != nil {
+		return
+	}
+
+	parts, err := submit.Split()
+	if err != nil {
+		return
+	}
+
+	p.Segments = make([]string, len(parts))
+	for i, part := range parts {
+		if p.Segments[i], err = part.Message.GetMessage(); err != nil {
+			return
+		}
+	}
+	return
+}
@@ -0,0 +1,52 @@
+package gosmpp
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/linxGnu/gosmpp/data"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreviewMessage(t *testing.T) {
+	t.Run("single", func(t *testing.T) {
+		p, err := PreviewMessage("Hello {world}")
+		require.Nil(t, err)
+		require.Equal(t, data.GSM7BIT, p.Encoding)
+		require.Equal(t, []string{"Hello {world}"}, p.Segments)
+		require.Equal(t, 1, p.SegmentCount())
+		require.Nil(t, p.UCS2Chars)
+	})
+
+	t.Run("gsm7 long", func(t *testing.T) {
+		text := strings.Repeat("a", 140)
+		p, err := PreviewMessage(text)
+		require.Nil(t, err)
+		require.Equal(t, []string{text}, p.Segments)
+
+		text += "b"
+		p, err = PreviewMessage(text)
+		require.Nil(t, err)
+		require.Equal(t, []string{strings.Repeat("a", 134), strings.Repeat("a", 6) + "b"}, p.Segments)
+	})
+
+	t.Run("gsm7 escape", func(t *testing.T) {
+		text := strings.Repeat("a", 130) + "€" + strings.Repeat("b", 10)
+		p, err := PreviewMessage(text)
+		require.Nil(t, err)
+		require.Equal(t, data.GSM7BIT, p.Encoding)
+		require.Equal(t, []string{strings.Repeat("a", 130) + "€bbb", "bbbbbbb"}, p.Segments)
+	})
+
+	t.Run("ucs2", func(t *testing.T) {
+		text := "Xin chào bạn, bạn khỏe không? " + strings.Repeat("x", 50)
+		p, err := PreviewMessage(text)
+		require.Nil(t, err)
+		require.Equal(t, data.UCS2, p.Encoding)
+		require.Equal(t, []rune{'ạ', 'ỏ', 'ô'}, p.UCS2Chars)
+		require.Equal(t, 2, p.SegmentCount())
+		require.Equal(t, text, strings.Join(p.Segments, ""))
+		require.Len(t, []rune(p.Segments[0]), 67)
+	})
+}
@@ -0,0 +1,59 @@
+package gosmpp
+
+import (
+	"context"
+
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+)
+
+// MessageQuote describes message about to be submitted by Messenger, for pricing it.
+type MessageQuote struct {
+	// SourceAddr and DestAddr are the normalized addresses. DestAddr is empty for messages to distribution
+	// list provisioned on SMSC, see RecipientList.
+	SourceAddr, DestAddr pdu.Address
+
+	// Segments is number of parts, each billed by SMSC as a message.
+	Segments int
+
+	// Encoding is encoding of the parts.
+	Encoding data.Encoding
+
+	// Labels of the session the message is routed to.
+	Labels Labels
+
+	// Correlation of the message, see ContextWithCorrelation.
+	Correlation Correlation
+}
+
+// PricingHook prices message before it is submitted, e.g. charging prepaid balance. Returned error vetoes
+// the message: it is not submitted and sending fails with the error.
+type PricingHook func(ctx context.Context, q MessageQuote) error
+
+// WithPricingHook sets hook called with each message sent by Messenger once it is split into parts.
+// Messages queued by SendAt are priced when queued.
+func WithPricingHook(hook PricingHook) MessengerOption {
+	return func(m *Messenger) {
+		m.pricing = hook
+	}
+}
+
+// price calls pricing hook with message of parts.
+func (m *Messenger) price(ctx context.Context, parts []*pdu.SubmitSM) error {
+	if m.pricing == nil || len(parts) == 0 {
+		return nil
+	}
+
+	enc := parts[0].Message.Encoding()
+	if enc == nil {
+		enc = data.GSM7BIT
+	}
+	return m.pricing(ctx, MessageQuote{
+		SourceAddr:  parts[0].SourceAddr,
+		DestAddr:    parts[0].DestAddr,
+		Segments:    len(parts),
+		Encoding:    enc,
+		Labels:      m.session.Labels(),
+		Correlation: CorrelationFromContext(ctx),
+	})
+}
@@ -0,0 +1,52 @@
+package gosmpp
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp/data"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessengerPricingHook(t *testing.T) {
+	errBudget := errors.New("budget exhausted")
+
+	var quotes []MessageQuote
+	m := newTestMessenger(t, WithPricingHook(func(ctx context.Context, q MessageQuote) error {
+		quotes = append(quotes, q)
+		if q.Segments > 1 {
+			return errBudget
+		}
+		return nil
+	}))
+	ctx := ContextWithCorrelation(context.Background(), Correlation{ID: "order-1"})
+
+	h, err := m.SendText(ctx, "MyShop", "+84901234567", "Xin chào bạn")
+	require.Nil(t, err)
+	require.NotNil(t, h)
+	require.Len(t, h.MessageIDs, 1)
+
+	h, err = m.SendText(ctx, "MyShop", "+84901234567", strings.Repeat("long ", 40))
+	require.ErrorIs(t, err, errBudget)
+	require.Nil(t, h)
+
+	require.Len(t, quotes, 2)
+	require.Equal(t, "MyShop", quotes[0].SourceAddr.Address())
+	require.Equal(t, "84901234567", quotes[0].DestAddr.Address())
+	require.Equal(t, 1, quotes[0].Segments)
+	require.Equal(t, data.UCS2, quotes[0].Encoding)
+	require.Equal(t, "order-1", quotes[0].Correlation.ID)
+	require.Equal(t, 2, quotes[1].Segments)
+	require.Equal(t, data.GSM7BIT, quotes[1].Encoding)
+
+	// queued message is priced when queued
+	b := NewSubmitSMBuilder().From("MyShop").To("+84901234567").Text(strings.Repeat("later ", 40))
+	h, err = m.SendAt(ctx, b, time.Now().Add(time.Hour))
+	require.ErrorIs(t, err, errBudget)
+	require.Nil(t, h)
+	require.Len(t, quotes, 3)
+}
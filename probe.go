@@ -0,0 +1,112 @@
+package gosmpp
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+)
+
+// States of session in SessionHealth.
+const (
+	SessionStateBound     = "bound"
+	SessionStateRebinding = "rebinding"
+	SessionStateClosed    = "closed"
+)
+
+// SessionHealth is health of session in HealthReport.
+type SessionHealth struct {
+	SessionStats
+
+	// State is SessionStateBound, SessionStateRebinding or SessionStateClosed.
+	State string `json:"state"`
+
+	// Healthy tells whether traffic could be routed to the session, see Session.Healthy.
+	Healthy bool `json:"healthy"`
+}
+
+// HealthReport is body of responses of HealthHandler.
+type HealthReport struct {
+	Live     bool            `json:"live"`
+	Ready    bool            `json:"ready"`
+	Sessions []SessionHealth `json:"sessions"`
+}
+
+// HealthHandler serves health of sessions as JSON for liveness and readiness probes, e.g. of Kubernetes:
+//
+//	health := &gosmpp.HealthHandler{Sessions: []*gosmpp.Session{primary, backup}}
+//	http.Handle("/livez", health.Liveness())
+//	http.Handle("/readyz", health.Readiness())
+//
+// Both respond with HealthReport, with status 200 if the probe passes and 503 otherwise.
+type HealthHandler struct {
+	// Sessions are the reported sessions.
+	Sessions []*Session
+
+	// MinHealthy is number of healthy sessions required for readiness, 1 if zero.
+	MinHealthy int
+}
+
+// Report returns current health of the sessions.
+//
+// Sessions are live unless closed: rebinding session is live, but closed one, e.g. whose bind was lost
+// with rebinding disabled, will not recover. Sessions are ready if at least MinHealthy of them are healthy.
+func (h *HealthHandler) Report() HealthReport {
+	report := HealthReport{Live: true, Sessions: make([]SessionHealth, len(h.Sessions))}
+
+	healthy := 0
+	for i, s := range h.Sessions {
+		sh := SessionHealth{SessionStats: s.Stats(), State: s.healthState(), Healthy: s.Healthy()}
+		if sh.State == SessionStateClosed {
+			report.Live = false
+		}
+		if sh.Healthy {
+			healthy++
+		}
+		report.Sessions[i] = sh
+	}
+
+	minHealthy := h.MinHealthy
+	if minHealthy <= 0 {
+		minHealthy = 1
+	}
+	report.Ready = healthy >= minHealthy
+	return report
+}
+
+// Liveness returns http.Handler of liveness probe, passing unless a session is closed.
+func (h *HealthHandler) Liveness() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		report := h.Report()
+		writeHealth(w, report.Live, report)
+	})
+}
+
+// Readiness returns http.Handler of readiness probe, passing if at least MinHealthy sessions are healthy.
+func (h *HealthHandler) Readiness() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		report := h.Report()
+		writeHealth(w, report.Ready, report)
+	})
+}
+
+func writeHealth(w http.ResponseWriter, pass bool, report HealthReport) {
+	code := http.StatusOK
+	if !pass {
+		code = http.StatusServiceUnavailable
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(report)
+}
+
+func (s *Session) healthState() string {
+	switch {
+	case atomic.LoadInt32(&s.state) != Alive:
+		return SessionStateClosed
+	case s.IsBound():
+		return SessionStateBound
+	default:
+		return SessionStateRebinding
+	}
+}
@@ -0,0 +1,65 @@
+package gosmpp_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp"
+	"github.com/linxGnu/gosmpp/server/smsctest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthHandler(t *testing.T) {
+	smsc := smsctest.NewPipeServer(nil)
+	defer smsc.Close()
+
+	newSession := func(systemID string) *gosmpp.Session {
+		s, err := gosmpp.NewSession(gosmpp.TRXConnector(smsc.Dialer(), gosmpp.Auth{SMSC: "pipe", SystemID: systemID}),
+			gosmpp.Settings{ReadTimeout: 2 * time.Second}, -1, gosmpp.WithLabels(gosmpp.Labels{"session": systemID}))
+		require.Nil(t, err)
+		return s
+	}
+	primary, backup := newSession("primary"), newSession("backup")
+	defer func() {
+		_ = primary.Close()
+	}()
+
+	probe := func(h http.Handler) (int, gosmpp.HealthReport) {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		require.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+		var report gosmpp.HealthReport
+		require.Nil(t, json.Unmarshal(w.Body.Bytes(), &report))
+		return w.Code, report
+	}
+
+	health := &gosmpp.HealthHandler{Sessions: []*gosmpp.Session{primary, backup}, MinHealthy: 2}
+	code, report := probe(health.Readiness())
+	require.Equal(t, http.StatusOK, code)
+	require.True(t, report.Live)
+	require.True(t, report.Ready)
+	require.Len(t, report.Sessions, 2)
+	require.Equal(t, gosmpp.SessionStateBound, report.Sessions[0].State)
+	require.True(t, report.Sessions[0].Healthy)
+	require.Equal(t, gosmpp.Labels{"session": "primary"}, report.Sessions[0].Labels)
+
+	// closed session fails liveness and, with the other one, readiness
+	require.Nil(t, backup.Close())
+	code, report = probe(health.Liveness())
+	require.Equal(t, http.StatusServiceUnavailable, code)
+	require.False(t, report.Live)
+	require.Equal(t, gosmpp.SessionStateClosed, report.Sessions[1].State)
+
+	code, _ = probe(health.Readiness())
+	require.Equal(t, http.StatusServiceUnavailable, code)
+
+	health.MinHealthy = 0
+	code, report = probe(health.Readiness())
+	require.Equal(t, http.StatusOK, code)
+	require.True(t, report.Ready)
+}
@@ -0,0 +1,211 @@
+package proxy
+
+import (
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/linxGnu/gosmpp"
+	"github.com/linxGnu/gosmpp/pdu"
+	"github.com/linxGnu/gosmpp/server"
+)
+
+// Conn is client bound through proxy, with its upstream bind.
+type Conn struct {
+	proxy    *Proxy
+	info     server.BindInfo
+	upstream Upstream
+
+	client *side
+	up     *side
+
+	closed int32
+}
+
+// side is one connection of Conn, with requests forwarded to it waiting for response.
+type side struct {
+	conn    *gosmpp.Connection
+	writeMu sync.Mutex
+
+	// interceptMu serializes interceptors of PDUs written to this side.
+	interceptMu sync.Mutex
+
+	mu       sync.Mutex
+	sequence int32
+
+	// pending maps sequence numbers of requests forwarded to this side to the original ones of their sender.
+	pending map[int32]int32
+}
+
+func newConn(p *Proxy, info server.BindInfo, upstream Upstream, client, up *gosmpp.Connection) *Conn {
+	return &Conn{
+		proxy:    p,
+		info:     info,
+		upstream: upstream,
+		client:   &side{conn: client, pending: make(map[int32]int32)},
+		up:       &side{conn: up, pending: make(map[int32]int32)},
+	}
+}
+
+// SystemID returns system_id of client.
+func (c *Conn) SystemID() string {
+	return c.info.SystemID
+}
+
+// BindInfo returns bind request of client.
+func (c *Conn) BindInfo() server.BindInfo {
+	return c.info
+}
+
+// Upstream returns upstream the client is bound to.
+func (c *Conn) Upstream() Upstream {
+	return c.upstream
+}
+
+// RemoteAddr returns address of client.
+func (c *Conn) RemoteAddr() net.Addr {
+	return c.client.conn.RemoteAddr()
+}
+
+// WriteClient writes PDU to client as is, bypassing interceptors and sequence mapping.
+func (c *Conn) WriteClient(p pdu.PDU) error {
+	return c.client.write(p, c.proxy.WriteTimeout)
+}
+
+// WriteUpstream writes PDU to upstream as is, bypassing interceptors and sequence mapping.
+func (c *Conn) WriteUpstream(p pdu.PDU) error {
+	return c.up.write(p, c.proxy.WriteTimeout)
+}
+
+// Close closes connections of both client and upstream, without unbinding them.
+func (c *Conn) Close() error {
+	if !atomic.CompareAndSwapInt32(&c.closed, 0, 1) {
+		return nil
+	}
+	err := c.client.conn.Close()
+	if upErr := c.up.conn.Close(); err == nil {
+		err = upErr
+	}
+	return err
+}
+
+// relay forwards PDUs both ways until either side is closed.
+func (c *Conn) relay() error {
+	errs := make(chan error, 2)
+	go func() {
+		errs <- c.forward(c.client, c.up, ToUpstream)
+	}()
+	go func() {
+		errs <- c.forward(c.up, c.client, ToClient)
+	}()
+
+	err := <-errs
+	_ = c.Close()
+	<-errs
+	return err
+}
+
+// forward reads PDUs from src and writes them to dst until either is closed.
+func (c *Conn) forward(src, dst *side, dir Direction) error {
+	// bind timeout does not apply anymore, binds are kept alive by enquire_link of their peers
+	if err := src.conn.SetReadDeadline(time.Time{}); err != nil {
+		return c.closeReason(err)
+	}
+
+	for {
+		p, err := src.conn.ReadPDU()
+		if err != nil {
+			return c.closeReason(err)
+		}
+
+		if err = c.intercept(src, dst, dir, p); err != nil {
+			return c.closeReason(err)
+		}
+
+		switch p.(type) {
+		case *pdu.UnbindResp:
+			// unbind is completed, both binds are done
+			return nil
+		}
+	}
+}
+
+// intercept passes p received from src through interceptors, then maps its sequence number and writes
+// it to dst.
+func (c *Conn) intercept(src, dst *side, dir Direction, p pdu.PDU) error {
+	dst.interceptMu.Lock()
+	defer dst.interceptMu.Unlock()
+
+	interceptors := c.proxy.Interceptors
+	var next func(i int, p pdu.PDU) error
+	next = func(i int, p pdu.PDU) error {
+		if i < len(interceptors) {
+			return interceptors[i](c, dir, p, func(p pdu.PDU) error { return next(i+1, p) })
+		}
+		if isResponse(p) {
+			p.SetSequenceNumber(src.responded(p.GetSequenceNumber()))
+		} else {
+			p.SetSequenceNumber(dst.request(p))
+		}
+		return dst.write(p, c.proxy.WriteTimeout)
+	}
+	return next(0, p)
+}
+
+// closeReason returns nil for errors caused by closing the connection.
+func (c *Conn) closeReason(err error) error {
+	if atomic.LoadInt32(&c.closed) != 0 || errors.Is(err, io.EOF) || errors.Is(err, net.ErrClosed) {
+		return nil
+	}
+	return err
+}
+
+// request returns new sequence number of request p written to this side, remembering the original one
+// for its response.
+func (s *side) request(p pdu.PDU) int32 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.sequence = (s.sequence + 1) & 0x7FFFFFFF; s.sequence == 0 {
+		s.sequence = 1
+	}
+	if p.CanResponse() {
+		s.pending[s.sequence] = p.GetSequenceNumber()
+	}
+	return s.sequence
+}
+
+// responded returns the original sequence number of request responded by this side with seq. Unknown
+// sequence numbers, e.g. of generic_nack to malformed PDU, are returned as they are.
+func (s *side) responded(seq int32) int32 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if original, ok := s.pending[seq]; ok {
+		delete(s.pending, seq)
+		return original
+	}
+	return seq
+}
+
+func (s *side) write(p pdu.PDU, timeout time.Duration) (err error) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	if timeout > 0 {
+		err = s.conn.SetWriteTimeout(timeout)
+	} else {
+		err = s.conn.SetWriteDeadline(time.Time{})
+	}
+	if err == nil {
+		_, err = s.conn.WritePDU(p)
+	}
+	return
+}
+
+func isResponse(p pdu.PDU) bool {
+	return p.GetHeader().CommandID < 0
+}
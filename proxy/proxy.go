@@ -0,0 +1,430 @@
+// Package proxy implements transparent SMPP proxy: clients bind to the proxy with one set of credentials,
+// and their traffic is forwarded over a bind to upstream SMSC made with another, e.g. for auditing traffic
+// or migrating clients between SMSCs gradually.
+//
+// Every bind of client gets its own upstream bind, PDUs are relayed both ways with sequence numbers mapped
+// between the two connections, and pass through interceptors which could inspect, modify or drop them:
+//
+//	p := &proxy.Proxy{
+//		Upstream: func(info server.BindInfo) (proxy.Upstream, data.CommandStatusType) {
+//			if info.Password != "secret" {
+//				return proxy.Upstream{}, data.ESME_RINVPASWD
+//			}
+//			return proxy.Upstream{Dialer: gosmpp.NonTLSDialer, Addr: "smsc.example.com:2775",
+//				SystemID: "acme", Password: "s3cr3t"}, data.ESME_ROK
+//		},
+//		Interceptors: []proxy.Interceptor{audit},
+//	}
+//	err := p.ListenAndServe()
+package proxy
+
+import (
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/linxGnu/gosmpp"
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+	"github.com/linxGnu/gosmpp/server"
+)
+
+// DefaultBindTimeout is default duration to wait for bind request of client and bind response of upstream.
+const DefaultBindTimeout = 10 * time.Second
+
+// ErrProxyClosed is returned by Serve and ListenAndServe after Close.
+var ErrProxyClosed = errors.New("smpp: proxy closed")
+
+// Upstream is SMSC which bind of client is forwarded to.
+type Upstream struct {
+	// Dialer connects to Addr, gosmpp.NonTLSDialer if nil.
+	Dialer gosmpp.Dialer
+	Addr   string
+
+	// SystemID and Password are credentials of the upstream bind.
+	SystemID string
+	Password string
+
+	// SystemType of the upstream bind, system_type of client if empty.
+	SystemType string
+}
+
+// Direction is direction of PDU relayed by proxy.
+type Direction int
+
+const (
+	// ToUpstream is direction of PDUs sent by client to upstream SMSC.
+	ToUpstream Direction = iota
+
+	// ToClient is direction of PDUs sent by upstream SMSC to client.
+	ToClient
+)
+
+func (d Direction) String() string {
+	if d == ToClient {
+		return "to client"
+	}
+	return "to upstream"
+}
+
+// Interceptor intercepts PDUs relayed by proxy, e.g. for audit logging or rewriting of addresses.
+// Calling next passes PDU, possibly modified or replaced, to the rest of the chain and then forwards it.
+// Not calling next drops the PDU: interceptor could respond to it itself with Conn.WriteClient or
+// Conn.WriteUpstream.
+//
+// Sequence number of PDU passed to interceptor is the one of its sender, it's mapped after the chain.
+// Interceptors of a direction are called sequentially.
+type Interceptor func(c *Conn, dir Direction, p pdu.PDU, next func(pdu.PDU) error) error
+
+// Proxy accepts binds of clients and forwards them to upstream SMSCs.
+type Proxy struct {
+	// Addr is TCP address to listen on by ListenAndServe, ":2775" if empty.
+	Addr string
+
+	// Upstream authenticates bind of client and returns upstream to forward it to. Bind is rejected
+	// with returned status other than data.ESME_ROK.
+	//
+	// Upstream might be called concurrently for different connections.
+	Upstream func(info server.BindInfo) (Upstream, data.CommandStatusType)
+
+	// Interceptors intercept relayed PDUs, the first one is outermost. Bind requests and responses
+	// are not intercepted.
+	Interceptors []Interceptor
+
+	// BindTimeout is duration to wait for bind request of client and bind response of upstream,
+	// default is DefaultBindTimeout.
+	BindTimeout time.Duration
+
+	// WriteTimeout is timeout for writing PDU to either side. Zero means no timeout.
+	WriteTimeout time.Duration
+
+	// OnBound is called when client is bound upstream.
+	OnBound func(*Conn)
+
+	// OnClosed is called when connection of bound client is closed, with the reason: nil if either
+	// side unbound or disconnected.
+	OnClosed func(*Conn, error)
+
+	mu        sync.Mutex
+	listeners map[net.Listener]struct{}
+	conns     map[*Conn]struct{}
+	closed    bool
+	wg        sync.WaitGroup
+}
+
+// ListenAndServe listens on Addr and serves accepted connections.
+func (p *Proxy) ListenAndServe() error {
+	addr := p.Addr
+	if addr == "" {
+		addr = ":2775"
+	}
+
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return p.Serve(l)
+}
+
+// Serve accepts connections on l and serves them, each in its own goroutine.
+// Serve always returns non-nil error and closes l.
+func (p *Proxy) Serve(l net.Listener) error {
+	if !p.track(l) {
+		_ = l.Close()
+		return ErrProxyClosed
+	}
+	defer p.untrack(l)
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if p.isClosed() {
+				return ErrProxyClosed
+			}
+
+			var nErr net.Error
+			if errors.As(err, &nErr) && nErr.Timeout() {
+				time.Sleep(10 * time.Millisecond)
+				continue
+			}
+			return err
+		}
+
+		if !p.acquire() {
+			_ = conn.Close()
+			return ErrProxyClosed
+		}
+		go func() {
+			defer p.wg.Done()
+			p.serve(conn)
+		}()
+	}
+}
+
+// ServeConn serves single connection, blocking until it is closed.
+func (p *Proxy) ServeConn(conn net.Conn) error {
+	if !p.acquire() {
+		_ = conn.Close()
+		return ErrProxyClosed
+	}
+	defer p.wg.Done()
+
+	p.serve(conn)
+	return nil
+}
+
+// PipeDialer returns dialer connecting client to the proxy in-process over net.Pipe, without TCP.
+// Address passed to the dialer is ignored.
+func (p *Proxy) PipeDialer() gosmpp.Dialer {
+	return func(string) (net.Conn, error) {
+		client, server := net.Pipe()
+		go func() {
+			_ = p.ServeConn(server)
+		}()
+		return client, nil
+	}
+}
+
+// Close closes listeners and all connections of both sides. It waits for relaying goroutines to stop.
+func (p *Proxy) Close() error {
+	p.mu.Lock()
+	p.closed = true
+	for l := range p.listeners {
+		_ = l.Close()
+	}
+	conns := make([]*Conn, 0, len(p.conns))
+	for c := range p.conns {
+		conns = append(conns, c)
+	}
+	p.mu.Unlock()
+
+	for _, c := range conns {
+		_ = c.Close()
+	}
+
+	p.wg.Wait()
+	return nil
+}
+
+// Conns returns currently bound connections.
+func (p *Proxy) Conns() []*Conn {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	conns := make([]*Conn, 0, len(p.conns))
+	for c := range p.conns {
+		conns = append(conns, c)
+	}
+	return conns
+}
+
+func (p *Proxy) isClosed() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.closed
+}
+
+// acquire registers serving goroutine to be waited by Close, false if the proxy is closed.
+func (p *Proxy) acquire() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return false
+	}
+	p.wg.Add(1)
+	return true
+}
+
+func (p *Proxy) track(l net.Listener) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return false
+	}
+	if p.listeners == nil {
+		p.listeners = make(map[net.Listener]struct{})
+	}
+	p.listeners[l] = struct{}{}
+	return true
+}
+
+func (p *Proxy) untrack(l net.Listener) {
+	p.mu.Lock()
+	delete(p.listeners, l)
+	p.mu.Unlock()
+	_ = l.Close()
+}
+
+func (p *Proxy) addConn(c *Conn) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return false
+	}
+	if p.conns == nil {
+		p.conns = make(map[*Conn]struct{})
+	}
+	p.conns[c] = struct{}{}
+	return true
+}
+
+func (p *Proxy) removeConn(c *Conn) {
+	p.mu.Lock()
+	delete(p.conns, c)
+	p.mu.Unlock()
+}
+
+// serve binds client upstream then relays its traffic until either side is closed.
+func (p *Proxy) serve(netConn net.Conn) {
+	c, err := p.bind(netConn)
+	if err != nil {
+		_ = netConn.Close()
+		return
+	}
+
+	if p.OnBound != nil {
+		p.OnBound(c)
+	}
+
+	err = c.relay()
+	p.removeConn(c)
+
+	if p.OnClosed != nil {
+		p.OnClosed(c, err)
+	}
+}
+
+func (p *Proxy) bindTimeout() time.Duration {
+	if p.BindTimeout > 0 {
+		return p.BindTimeout
+	}
+	return DefaultBindTimeout
+}
+
+// bind waits for bind request of client and forwards it upstream, responding to client with response
+// of upstream. Bound connection is registered to the proxy.
+func (p *Proxy) bind(netConn net.Conn) (*Conn, error) {
+	client := gosmpp.NewConnection(netConn)
+	if err := client.SetReadTimeout(p.bindTimeout()); err != nil {
+		return nil, err
+	}
+
+	req, err := client.ReadPDU()
+	if err != nil {
+		return nil, err
+	}
+
+	bindReq, ok := req.(*pdu.BindRequest)
+	if !ok {
+		// client must bind first
+		resp := pdu.NewGenericNack()
+		if req.CanResponse() {
+			resp = req.GetResponse()
+		}
+		resp.SetSequenceNumber(req.GetSequenceNumber())
+		setStatus(resp, data.ESME_RINVBNDSTS)
+		_, _ = client.WritePDU(resp)
+		return nil, server.ErrNotBound
+	}
+
+	reject := func(status data.CommandStatusType) error {
+		resp := pdu.NewBindResp(*bindReq)
+		resp.CommandStatus = status
+		_, _ = client.WritePDU(resp)
+		return server.BindRejectedError{CommandStatus: status}
+	}
+
+	info := server.BindInfo{
+		SystemID:     bindReq.SystemID,
+		Password:     bindReq.Password,
+		SystemType:   bindReq.SystemType,
+		BindingType:  bindReq.BindingType,
+		AddressRange: bindReq.AddressRange,
+		RemoteAddr:   client.RemoteAddr(),
+	}
+	upstream, status := Upstream{}, data.ESME_ROK
+	if p.Upstream != nil {
+		upstream, status = p.Upstream(info)
+	} else {
+		status = data.ESME_RBINDFAIL
+	}
+	if status != data.ESME_ROK {
+		return nil, reject(status)
+	}
+
+	up, resp, err := p.dialUpstream(upstream, bindReq)
+	if err != nil {
+		return nil, reject(data.ESME_RBINDFAIL)
+	}
+
+	// bind response of upstream is relayed, e.g. with its system_id and sc_interface_version
+	resp.SetSequenceNumber(bindReq.GetSequenceNumber())
+	if resp.CommandStatus != data.ESME_ROK {
+		_ = up.Close()
+		_, _ = client.WritePDU(resp)
+		return nil, server.BindRejectedError{CommandStatus: resp.CommandStatus}
+	}
+
+	c := newConn(p, info, upstream, client, up)
+	if !p.addConn(c) {
+		_ = up.Close()
+		return nil, reject(data.ESME_RBINDFAIL)
+	}
+	if _, err = client.WritePDU(resp); err != nil {
+		p.removeConn(c)
+		_ = up.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// dialUpstream binds to upstream on behalf of client.
+func (p *Proxy) dialUpstream(upstream Upstream, clientReq *pdu.BindRequest) (*gosmpp.Connection, *pdu.BindResp, error) {
+	dialer := upstream.Dialer
+	if dialer == nil {
+		dialer = gosmpp.NonTLSDialer
+	}
+	netConn, err := dialer(upstream.Addr)
+	if err != nil {
+		return nil, nil, err
+	}
+	conn := gosmpp.NewConnection(netConn)
+
+	req := pdu.NewBindRequest(clientReq.BindingType)
+	req.SystemID, req.Password = upstream.SystemID, upstream.Password
+	req.SystemType = upstream.SystemType
+	if req.SystemType == "" {
+		req.SystemType = clientReq.SystemType
+	}
+	req.InterfaceVersion = clientReq.InterfaceVersion
+	req.AddressRange = clientReq.AddressRange
+
+	if err = conn.SetReadTimeout(p.bindTimeout()); err == nil {
+		_, err = conn.WritePDU(req)
+	}
+
+	var resp pdu.PDU
+	if err == nil {
+		resp, err = conn.ReadPDU()
+	}
+	if err == nil {
+		bindResp, ok := resp.(*pdu.BindResp)
+		if ok && bindResp.GetSequenceNumber() == req.GetSequenceNumber() {
+			return conn, bindResp, nil
+		}
+		err = server.ErrNotBound
+	}
+
+	_ = conn.Close()
+	return nil, nil, err
+}
+
+func setStatus(p pdu.PDU, status data.CommandStatusType) {
+	if s, ok := p.(interface{ SetCommandStatus(data.CommandStatusType) }); ok {
+		s.SetCommandStatus(status)
+	}
+}
@@ -0,0 +1,150 @@
+package proxy
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp"
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+	"github.com/linxGnu/gosmpp/server"
+	"github.com/linxGnu/gosmpp/server/smsctest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProxy(t *testing.T) {
+	smsc := smsctest.NewPipeServer(map[string]string{"acme": "s3cr3t"})
+	defer smsc.Close()
+	smsc.SetReceipts(&smsctest.Receipts{})
+
+	var mu sync.Mutex
+	var relayed []string
+	var deliverSeq int32
+	p := &Proxy{
+		Upstream: func(info server.BindInfo) (Upstream, data.CommandStatusType) {
+			if info.SystemID != "shop" || info.Password != "secret" {
+				return Upstream{}, data.ESME_RINVPASWD
+			}
+			return Upstream{Dialer: smsc.Dialer(), SystemID: "acme", Password: "s3cr3t"}, data.ESME_ROK
+		},
+		Interceptors: []Interceptor{
+			func(c *Conn, dir Direction, p pdu.PDU, next func(pdu.PDU) error) error {
+				mu.Lock()
+				relayed = append(relayed, dir.String()+" "+p.GetHeader().CommandID.String())
+				if _, ok := p.(*pdu.DeliverSM); ok {
+					deliverSeq = p.GetSequenceNumber()
+				}
+				mu.Unlock()
+				return next(p)
+			},
+			func(c *Conn, dir Direction, p pdu.PDU, next func(pdu.PDU) error) error {
+				// sender of the shop is rewritten
+				if submit, ok := p.(*pdu.SubmitSM); ok && dir == ToUpstream {
+					_ = submit.SourceAddr.SetAddress("Acme")
+				}
+				return next(p)
+			},
+		},
+	}
+	defer func() {
+		_ = p.Close()
+	}()
+
+	connect := func(password string) (*gosmpp.Session, chan gosmpp.Receipt, error) {
+		receipts := make(chan gosmpp.Receipt, 1)
+		s, err := gosmpp.NewSession(
+			gosmpp.TRXConnector(p.PipeDialer(), gosmpp.Auth{SMSC: "proxy", SystemID: "shop", Password: password}),
+			gosmpp.Settings{
+				ReadTimeout:       2 * time.Second,
+				OnDeliveryReceipt: func(r gosmpp.Receipt) { receipts <- r },
+			}, -1)
+		return s, receipts, err
+	}
+
+	_, _, err := connect("wrong")
+	require.NotNil(t, err)
+
+	session, receipts, err := connect("secret")
+	require.Nil(t, err)
+	defer func() {
+		_ = session.Close()
+	}()
+
+	require.Len(t, p.Conns(), 1)
+	require.Equal(t, "shop", p.Conns()[0].SystemID())
+	require.Len(t, smsc.Sessions(), 1)
+	require.Equal(t, "acme", smsc.Sessions()[0].SystemID())
+
+	messenger := gosmpp.NewMessenger(session, gosmpp.WithRegisteredDelivery(data.SM_SMSC_RECEIPT_REQUESTED))
+	h, err := messenger.SendText(context.Background(), "MyShop", "+84901234567", "Hello")
+	require.Nil(t, err)
+	require.Len(t, h.MessageIDs, 1)
+
+	submit := smsc.ExpectSubmitSM(t, "84901234567", "Hello")
+	require.Equal(t, "Acme", submit.SourceAddr.Address())
+
+	select {
+	case r := <-receipts:
+		require.Equal(t, h.MessageIDs[0], r.MessageID)
+	case <-time.After(2 * time.Second):
+		t.Fatal("receipt is not relayed")
+	}
+	// response of the client reaches upstream with sequence number of its deliver_sm
+	smsc.ExpectReceived(t, data.DELIVER_SM_RESP, 1)
+
+	mu.Lock()
+	for _, resp := range smsc.Received() {
+		if resp.GetHeader().CommandID == data.DELIVER_SM_RESP {
+			require.Equal(t, deliverSeq, resp.GetSequenceNumber())
+		}
+	}
+	require.Contains(t, relayed, "to upstream SUBMIT_SM")
+	require.Contains(t, relayed, "to client SUBMIT_SM_RESP")
+	require.Contains(t, relayed, "to client DELIVER_SM")
+	require.Contains(t, relayed, "to upstream DELIVER_SM_RESP")
+	mu.Unlock()
+
+	// unbind of client is relayed and closes upstream bind
+	require.Nil(t, session.Close())
+	require.Eventually(t, func() bool {
+		return len(smsc.Sessions()) == 0 && len(p.Conns()) == 0
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+func TestProxyUpstreamRejects(t *testing.T) {
+	smsc := smsctest.NewPipeServer(map[string]string{"acme": "s3cr3t"})
+	defer smsc.Close()
+
+	p := &Proxy{
+		Upstream: func(server.BindInfo) (Upstream, data.CommandStatusType) {
+			return Upstream{Dialer: smsc.Dialer(), SystemID: "acme", Password: "expired"}, data.ESME_ROK
+		},
+	}
+	defer func() {
+		_ = p.Close()
+	}()
+
+	_, err := gosmpp.NewSession(
+		gosmpp.TXConnector(p.PipeDialer(), gosmpp.Auth{SMSC: "proxy", SystemID: "shop"}),
+		gosmpp.Settings{ReadTimeout: time.Second}, -1)
+	require.ErrorContains(t, err, data.ESME_RINVPASWD.String())
+}
+
+func TestSequenceMapping(t *testing.T) {
+	s := &side{pending: make(map[int32]int32)}
+
+	req := pdu.NewSubmitSM()
+	req.SetSequenceNumber(1000)
+	require.EqualValues(t, 1, s.request(req))
+	require.EqualValues(t, 2, s.request(pdu.NewGenericNack()))
+
+	require.EqualValues(t, 1000, s.responded(1))
+	require.EqualValues(t, 1, s.responded(1))
+	require.Empty(t, s.pending)
+
+	s.sequence = 0x7FFFFFFF
+	require.EqualValues(t, 1, s.request(req))
+}
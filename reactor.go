@@ -0,0 +1,269 @@
+package gosmpp
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/linxGnu/gosmpp/clock"
+	"github.com/linxGnu/gosmpp/pdu"
+)
+
+const (
+	// DefaultReactorResolution is default granularity of Reactor timers.
+	DefaultReactorResolution = 100 * time.Millisecond
+)
+
+// Reactor is shared scheduler for many sessions.
+//
+// By default every bind runs its own reader, writer and (with WindowedRequestTracking) window cleanup goroutines.
+// Sessions with Settings.Reactor set share the Reactor instead: PDUs are written by a fixed pool of writer goroutines,
+// enquire_link and window expiry are driven by a single timer goroutine. Only reading still needs a goroutine per bind,
+// so an SMS hub with thousands of binds runs roughly one goroutine per bind instead of three.
+//
+// Reactor must outlive sessions using it: close sessions first, then the Reactor.
+type Reactor struct {
+	clock      clock.Clock
+	resolution time.Duration
+
+	mu      sync.Mutex
+	cond    *sync.Cond
+	queue   []func()
+	closed  bool
+	nextJob int
+	jobs    map[int]*reactorJob
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+// ReactorOption configures Reactor.
+type ReactorOption func(*Reactor)
+
+// WithReactorResolution sets granularity of Reactor timers, default is DefaultReactorResolution.
+//
+// Enquire link and window expiry are fired with at most this delay.
+func WithReactorResolution(d time.Duration) ReactorOption {
+	return func(r *Reactor) {
+		if d > 0 {
+			r.resolution = d
+		}
+	}
+}
+
+// WithReactorClock sets clock driving Reactor timers, default is clock.Real.
+func WithReactorClock(c clock.Clock) ReactorOption {
+	return func(r *Reactor) {
+		r.clock = clock.OrReal(c)
+	}
+}
+
+// NewReactor starts Reactor with given number of writer goroutines.
+func NewReactor(writers int, opts ...ReactorOption) *Reactor {
+	if writers <= 0 {
+		writers = 1
+	}
+
+	r := &Reactor{
+		clock:      clock.Real,
+		resolution: DefaultReactorResolution,
+		jobs:       make(map[int]*reactorJob),
+		done:       make(chan struct{}),
+	}
+	r.cond = sync.NewCond(&r.mu)
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	r.wg.Add(writers + 1)
+	for i := 0; i < writers; i++ {
+		go func() {
+			defer r.wg.Done()
+			r.work()
+		}()
+	}
+	go func() {
+		defer r.wg.Done()
+		r.tick()
+	}()
+
+	return r
+}
+
+// Close stops Reactor daemons. Queued work is dropped.
+func (r *Reactor) Close() error {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return nil
+	}
+	r.closed = true
+	r.queue = nil
+	r.cond.Broadcast()
+	r.mu.Unlock()
+
+	close(r.done)
+	r.wg.Wait()
+	return nil
+}
+
+// reactorJob is periodic task. Returning false from run removes the job.
+type reactorJob struct {
+	period time.Duration
+	next   time.Time
+	run    func() bool
+}
+
+// schedule registers periodic job, run on writer goroutines.
+func (r *Reactor) schedule(period time.Duration, run func() bool) {
+	r.mu.Lock()
+	r.jobs[r.nextJob] = &reactorJob{
+		period: period,
+		next:   r.clock.Now().Add(period),
+		run:    run,
+	}
+	r.nextJob++
+	r.mu.Unlock()
+}
+
+// dispatch queues fn to writer goroutines. Never blocks.
+func (r *Reactor) dispatch(fn func()) {
+	r.mu.Lock()
+	if !r.closed {
+		r.queue = append(r.queue, fn)
+		r.cond.Signal()
+	}
+	r.mu.Unlock()
+}
+
+func (r *Reactor) work() {
+	for {
+		r.mu.Lock()
+		for len(r.queue) == 0 && !r.closed {
+			r.cond.Wait()
+		}
+		if r.closed {
+			r.mu.Unlock()
+			return
+		}
+		fn := r.queue[0]
+		r.queue[0] = nil
+		r.queue = r.queue[1:]
+		r.mu.Unlock()
+
+		fn()
+	}
+}
+
+func (r *Reactor) tick() {
+	ticker := r.clock.NewTicker(r.resolution)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.done:
+			return
+
+		case <-ticker.C():
+			now := r.clock.Now()
+
+			r.mu.Lock()
+			for id, job := range r.jobs {
+				if now.Before(job.next) {
+					continue
+				}
+				job.next = now.Add(job.period)
+
+				id, job := id, job
+				r.queue = append(r.queue, func() {
+					if !job.run() {
+						r.mu.Lock()
+						delete(r.jobs, id)
+						r.mu.Unlock()
+					}
+				})
+				r.cond.Signal()
+			}
+			r.mu.Unlock()
+		}
+	}
+}
+
+// reactorTransmittable holds per-bind scheduling state when transmittable runs on Reactor.
+type reactorTransmittable struct {
+	// scheduled is 1 when flushing of the bind is queued or running
+	scheduled int32
+
+	// writeMu serializes writing to the bind connection
+	writeMu sync.Mutex
+}
+
+// startOnReactor attaches transmittable to Reactor instead of running its own daemon.
+func (t *transmittable) startOnReactor() {
+	if t.settings.EnquireLink > 0 {
+		t.settings.Reactor.schedule(t.settings.EnquireLink, func() bool {
+			atomic.AddInt32(&t.pendingWrite, 1)
+			defer atomic.AddInt32(&t.pendingWrite, -1)
+
+			if atomic.LoadInt32(&t.aliveState) != Alive {
+				return false
+			}
+			t.stats.expire(t.settings.ReadTimeout)
+
+			select {
+			case t.input <- pdu.NewEnquireLink():
+				t.schedule()
+			default:
+				// bind is busy with other PDUs, try with next tick
+			}
+			return true
+		})
+	}
+}
+
+// schedule queues flushing of the bind unless it is already queued.
+func (t *transmittable) schedule() {
+	if atomic.CompareAndSwapInt32(&t.reactor.scheduled, 0, 1) {
+		t.settings.Reactor.dispatch(t.run)
+	}
+}
+
+func (t *transmittable) run() {
+	t.reactor.writeMu.Lock()
+	closing := t.flush()
+	t.reactor.writeMu.Unlock()
+
+	if closing {
+		// like daemon, discard the rest so submitters are not blocked until closed
+		go t.drain()
+		return
+	}
+
+	atomic.StoreInt32(&t.reactor.scheduled, 0)
+	if len(t.input) > 0 {
+		t.schedule()
+	}
+}
+
+// flush writes queued PDUs without blocking. Caller must hold writeMu.
+func (t *transmittable) flush() (closing bool) {
+	for {
+		select {
+		case p, ok := <-t.input:
+			if !ok {
+				return true
+			}
+
+			if p != nil {
+				n, err := t.write(p)
+				if t.check(p, n, err) {
+					return true
+				}
+			}
+
+		default:
+			return false
+		}
+	}
+}
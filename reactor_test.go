@@ -0,0 +1,95 @@
+package gosmpp
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp/pdu"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReactor(t *testing.T) {
+	t.Run("jobs", func(t *testing.T) {
+		r := NewReactor(0, WithReactorResolution(time.Millisecond))
+
+		var runs int32
+		r.schedule(5*time.Millisecond, func() bool {
+			return atomic.AddInt32(&runs, 1) < 3
+		})
+
+		require.Eventually(t, func() bool {
+			return atomic.LoadInt32(&runs) == 3
+		}, time.Second, time.Millisecond)
+
+		r.mu.Lock()
+		require.Empty(t, r.jobs)
+		r.mu.Unlock()
+
+		require.Nil(t, r.Close())
+		require.Nil(t, r.Close())
+
+		// dispatching to closed reactor is no-op
+		r.dispatch(func() { t.Fatal("must not run") })
+	})
+
+	t.Run("sessions", func(t *testing.T) {
+		r := NewReactor(2, WithReactorResolution(10*time.Millisecond))
+		defer func() {
+			_ = r.Close()
+		}()
+
+		var (
+			mu        sync.Mutex
+			responses int
+		)
+
+		sessions := make([]*Session, 0, 5)
+		for i := 0; i < 5; i++ {
+			auth := nextAuth()
+			s, err := NewSession(
+				TRXConnector(NonTLSDialer, auth),
+				Settings{
+					EnquireLink: 200 * time.Millisecond,
+					ReadTimeout: time.Second,
+					OnPDU: func(p pdu.PDU, _ bool) {
+						if _, ok := p.(*pdu.SubmitSMResp); ok {
+							mu.Lock()
+							responses++
+							mu.Unlock()
+						}
+					},
+					Reactor: r,
+				}, -1)
+			require.Nil(t, err)
+			sessions = append(sessions, s)
+
+			for j := 0; j < 10; j++ {
+				require.Nil(t, s.Submit(newSubmitSM(auth.SystemID)))
+			}
+		}
+
+		require.Eventually(t, func() bool {
+			mu.Lock()
+			defer mu.Unlock()
+			return responses == 50
+		}, 5*time.Second, 10*time.Millisecond)
+
+		// enquire_link is sent by reactor timer
+		require.Eventually(t, func() bool {
+			for _, s := range sessions {
+				if s.LastEnquireLinkRTT() == 0 {
+					return false
+				}
+			}
+			return true
+		}, 5*time.Second, 10*time.Millisecond)
+
+		for _, s := range sessions {
+			require.Nil(t, s.Close())
+			require.ErrorIs(t, s.Submit(newSubmitSM("")), ErrConnectionClosing)
+		}
+	})
+}
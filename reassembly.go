@@ -0,0 +1,331 @@
+package gosmpp
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/linxGnu/gosmpp/clock"
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+)
+
+// DefaultReassemblyTimeout is default time parts of concatenated message are kept waiting for the rest.
+const DefaultReassemblyTimeout = 5 * time.Minute
+
+// ReassemblyStore keeps parts of concatenated messages until all are received.
+//
+// Persistent implementation, e.g. on Redis, lets parts received by multiple instances be reassembled.
+type ReassemblyStore interface {
+	// AddPart saves data of part seq, numbered from 1, of total parts of message key. Once all parts are saved,
+	// they are removed and returned in order with complete true. Repeated part replaces the saved one.
+	AddPart(ctx context.Context, key string, total, seq byte, data []byte) (parts [][]byte, complete bool, err error)
+}
+
+// DeduplicationStore remembers reassembled messages, so their copies are dropped, e.g. the same message
+// received on multiple binds.
+//
+// Persistent implementation, e.g. on Redis with SETNX, lets copies received by multiple instances be dropped.
+type DeduplicationStore interface {
+	// FirstSeen records key of message, reporting whether it was not recorded before.
+	FirstSeen(ctx context.Context, key string) (bool, error)
+}
+
+// MemoryReassemblyStore is ReassemblyStore in memory, dropping incomplete messages after timeout.
+//
+// It is DeduplicationStore as well, remembering messages for timeout.
+//
+// Without WithStoreBudget, the store grows with every incomplete message until its timeout, e.g. while
+// a peer floods parts which are never completed.
+type MemoryReassemblyStore struct {
+	timeout time.Duration
+	clock   clock.Clock
+	budgets []*MemoryBudget
+
+	mu       sync.Mutex
+	messages map[string]*partialMessage
+	seen     map[string]*seenMessage
+}
+
+type partialMessage struct {
+	parts    [][]byte
+	received int
+	size     int
+	started  time.Time
+	charges  budgetCharges
+}
+
+type seenMessage struct {
+	at      time.Time
+	charges budgetCharges
+}
+
+// MemoryReassemblyStoreOption configures MemoryReassemblyStore.
+type MemoryReassemblyStoreOption func(*MemoryReassemblyStore)
+
+// WithStoreBudget charges parts of incomplete messages and keys of remembered ones to budgets, e.g. one of
+// the session and one global. Over any budget, the least recently updated messages are evicted.
+func WithStoreBudget(budgets ...*MemoryBudget) MemoryReassemblyStoreOption {
+	return func(s *MemoryReassemblyStore) {
+		s.budgets = append(s.budgets, budgets...)
+	}
+}
+
+// NewMemoryReassemblyStore returns empty store dropping parts of messages incomplete for timeout,
+// DefaultReassemblyTimeout if zero.
+func NewMemoryReassemblyStore(timeout time.Duration, opts ...MemoryReassemblyStoreOption) *MemoryReassemblyStore {
+	if timeout <= 0 {
+		timeout = DefaultReassemblyTimeout
+	}
+	s := &MemoryReassemblyStore{
+		timeout:  timeout,
+		clock:    clock.Real,
+		messages: make(map[string]*partialMessage),
+		seen:     make(map[string]*seenMessage),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// AddPart implements ReassemblyStore.
+func (s *MemoryReassemblyStore) AddPart(ctx context.Context, key string, total, seq byte, data []byte) ([][]byte, bool, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, false, err
+	}
+
+	s.mu.Lock()
+	parts, complete, evict := s.addPart(key, total, seq, data)
+	s.mu.Unlock()
+
+	evict()
+	return parts, complete, nil
+}
+
+func (s *MemoryReassemblyStore) addPart(key string, total, seq byte, data []byte) ([][]byte, bool, func()) {
+	now := s.clock.Now()
+	s.expire(now)
+
+	m, ok := s.messages[key]
+	if !ok || len(m.parts) != int(total) {
+		if ok {
+			m.charges.uncharge(s.budgets)
+		}
+		m = &partialMessage{parts: make([][]byte, total), size: len(key), started: now}
+		m.charges = newBudgetCharges(s.budgets, EvictedParts, key, s.releaser(key, m))
+		s.messages[key] = m
+	}
+	if m.parts[seq-1] == nil {
+		m.received++
+	}
+	m.size += len(data) - len(m.parts[seq-1])
+	m.parts[seq-1] = append([]byte{}, data...)
+
+	if m.received < int(total) {
+		return nil, false, m.charges.charge(s.budgets, m.size)
+	}
+	delete(s.messages, key)
+	m.charges.uncharge(s.budgets)
+	return m.parts, true, func() {}
+}
+
+// releaser returns func dropping evicted message m.
+func (s *MemoryReassemblyStore) releaser(key string, m *partialMessage) func() {
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.messages[key] == m {
+			delete(s.messages, key)
+			m.charges.uncharge(s.budgets)
+		}
+	}
+}
+
+// FirstSeen implements DeduplicationStore.
+func (s *MemoryReassemblyStore) FirstSeen(ctx context.Context, key string) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	s.mu.Lock()
+	now := s.clock.Now()
+	s.expire(now)
+
+	if _, ok := s.seen[key]; ok {
+		s.mu.Unlock()
+		return false, nil
+	}
+	m := &seenMessage{at: now}
+	m.charges = newBudgetCharges(s.budgets, EvictedSeen, key, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.seen[key] == m {
+			delete(s.seen, key)
+			m.charges.uncharge(s.budgets)
+		}
+	})
+	s.seen[key] = m
+	evict := m.charges.charge(s.budgets, len(key))
+	s.mu.Unlock()
+
+	evict()
+	return true, nil
+}
+
+// Len returns number of incomplete messages.
+func (s *MemoryReassemblyStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.messages)
+}
+
+func (s *MemoryReassemblyStore) expire(now time.Time) {
+	for key, m := range s.messages {
+		if now.Sub(m.started) >= s.timeout {
+			delete(s.messages, key)
+			m.charges.uncharge(s.budgets)
+		}
+	}
+	for key, m := range s.seen {
+		if now.Sub(m.at) >= s.timeout {
+			delete(s.seen, key)
+			m.charges.uncharge(s.budgets)
+		}
+	}
+}
+
+// Reassembler joins parts of concatenated incoming messages, passing whole messages on. Parts are fed
+// by HandleMessage, e.g. from Settings.OnMessage:
+//
+//	reassembler := gosmpp.NewReassembler(func(m gosmpp.IncomingMessage) { ... })
+//	settings.OnMessage = func(m gosmpp.IncomingMessage) { _ = reassembler.HandleMessage(context.Background(), m) }
+//
+// Parts are recognized by concatenation IE of UDH, 8-bit or 16-bit reference, or by sar_* TLVs.
+// Parts are kept in memory unless ReassemblyStore is given with WithReassemblyStore, e.g. MemoryReassemblyStore
+// with WithStoreBudget capping memory of parts which are never completed.
+type Reassembler struct {
+	store     ReassemblyStore
+	dedup     DeduplicationStore
+	onMessage func(IncomingMessage)
+}
+
+// ReassemblerOption configures Reassembler.
+type ReassemblerOption func(*Reassembler)
+
+// WithReassemblyStore keeps parts in store, e.g. shared by multiple instances.
+func WithReassemblyStore(store ReassemblyStore) ReassemblerOption {
+	return func(r *Reassembler) {
+		r.store = store
+	}
+}
+
+// WithDeduplication drops copies of reassembled messages remembered by store, e.g. the same message received
+// on binds to redundant SMSCs. Copies are messages with the same addresses, concatenation reference and data.
+//
+//	store := gosmpp.NewMemoryReassemblyStore(0)
+//	reassembler := gosmpp.NewReassembler(onMessage, gosmpp.WithReassemblyStore(store), gosmpp.WithDeduplication(store))
+//
+// Whole messages are not deduplicated, equal ones could be sent by subscriber on purpose.
+func WithDeduplication(store DeduplicationStore) ReassemblerOption {
+	return func(r *Reassembler) {
+		r.dedup = store
+	}
+}
+
+// NewReassembler returns reassembler calling onMessage with whole messages.
+func NewReassembler(onMessage func(IncomingMessage), opts ...ReassemblerOption) *Reassembler {
+	r := &Reassembler{onMessage: onMessage}
+	for _, opt := range opts {
+		opt(r)
+	}
+	if r.store == nil {
+		r.store = NewMemoryReassemblyStore(DefaultReassemblyTimeout)
+	}
+	return r
+}
+
+// HandleMessage passes m on if it is not a part; otherwise saves it, passing the whole message on
+// once all parts are received.
+//
+// Whole message is m, i.e. the last received part, with Data and Text of all parts and UDH without
+// concatenation IE.
+func (r *Reassembler) HandleMessage(ctx context.Context, m IncomingMessage) error {
+	ref, total, seq, ok := concatInfo(m)
+	if !ok {
+		r.onMessage(m)
+		return nil
+	}
+
+	key := fmt.Sprintf("%s/%s/%d/%d", m.From, m.To, ref, total)
+	parts, complete, err := r.store.AddPart(ctx, key, total, seq, m.Data)
+	if err != nil || !complete {
+		return err
+	}
+
+	m.Data = nil
+	for _, part := range parts {
+		m.Data = append(m.Data, part...)
+	}
+
+	if r.dedup != nil {
+		first, err := r.dedup.FirstSeen(ctx, fmt.Sprintf("%s/%x", key, sha256.Sum256(m.Data)))
+		if err != nil || !first {
+			return err
+		}
+	}
+	if m.Encoding != nil && !m.Binary() {
+		m.Text, _ = m.Encoding.Decode(m.Data)
+	}
+	m.UDH = withoutConcat(m.UDH)
+	r.onMessage(m)
+	return nil
+}
+
+// concatInfo returns concatenation info of message part: from UDH, or from sar_* TLVs.
+func concatInfo(m IncomingMessage) (ref uint16, total, seq byte, ok bool) {
+	for _, ie := range m.UDH {
+		switch {
+		case ie.ID == data.UDH_CONCAT_MSG_8_BIT_REF && len(ie.Data) == 3:
+			ref, total, seq = uint16(ie.Data[0]), ie.Data[1], ie.Data[2]
+			return ref, total, seq, validPart(total, seq)
+
+		case ie.ID == data.UDH_CONCAT_MSG_16_BIT_REF && len(ie.Data) == 4:
+			ref, total, seq = uint16(ie.Data[0])<<8|uint16(ie.Data[1]), ie.Data[2], ie.Data[3]
+			return ref, total, seq, validPart(total, seq)
+		}
+	}
+
+	var params map[pdu.Tag]pdu.Field
+	switch p := m.PDU.(type) {
+	case *pdu.DeliverSM:
+		params = p.OptionalParameters
+	case *pdu.DataSM:
+		params = p.OptionalParameters
+	}
+	refNum, ok1 := params[pdu.TagSarMsgRefNum]
+	totalSegments, ok2 := params[pdu.TagSarTotalSegments]
+	seqNum, ok3 := params[pdu.TagSarSegmentSeqnum]
+	if !ok1 || !ok2 || !ok3 || len(refNum.Data) != 2 || len(totalSegments.Data) != 1 || len(seqNum.Data) != 1 {
+		return 0, 0, 0, false
+	}
+	ref, total, seq = uint16(refNum.Data[0])<<8|uint16(refNum.Data[1]), totalSegments.Data[0], seqNum.Data[0]
+	return ref, total, seq, validPart(total, seq)
+}
+
+// validPart reports whether part is of concatenated message at all, invalid ones are taken as whole messages.
+func validPart(total, seq byte) bool {
+	return total > 1 && seq >= 1 && seq <= total
+}
+
+func withoutConcat(udh pdu.UDH) pdu.UDH {
+	var rest pdu.UDH
+	for _, ie := range udh {
+		if ie.ID != data.UDH_CONCAT_MSG_8_BIT_REF && ie.ID != data.UDH_CONCAT_MSG_16_BIT_REF {
+			rest = append(rest, ie)
+		}
+	}
+	return rest
+}
@@ -0,0 +1,153 @@
+package gosmpp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp/clock"
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newMOPart returns part seq of total parts of message ref, as received.
+func newMOPart(t *testing.T, ref, total, seq byte, text string) IncomingMessage {
+	p := newMO("+84901234567", "8888", text)
+	p.EsmClass |= data.SM_UDH_GSM
+	p.Message.SetUDH(pdu.UDH{pdu.NewIEConcatMessage(total, seq, ref)})
+
+	m, ok := ParseIncomingMessage(received(t, p))
+	require.True(t, ok)
+	return m
+}
+
+func TestReassembler(t *testing.T) {
+	var messages []IncomingMessage
+	store := NewMemoryReassemblyStore(0)
+	r := NewReassembler(func(m IncomingMessage) {
+		messages = append(messages, m)
+	}, WithReassemblyStore(store))
+	ctx := context.Background()
+
+	// whole message is passed as is
+	require.Nil(t, r.HandleMessage(ctx, newMOPart(t, 1, 1, 1, "single")))
+	require.Len(t, messages, 1)
+	require.Equal(t, "single", messages[0].Text)
+
+	// parts out of order, repeated part, interleaved message
+	require.Nil(t, r.HandleMessage(ctx, newMOPart(t, 2, 3, 3, "three")))
+	require.Nil(t, r.HandleMessage(ctx, newMOPart(t, 2, 3, 1, "one ")))
+	require.Nil(t, r.HandleMessage(ctx, newMOPart(t, 3, 2, 1, "other ")))
+	require.Nil(t, r.HandleMessage(ctx, newMOPart(t, 2, 3, 1, "one ")))
+	require.Len(t, messages, 1)
+	require.Equal(t, 2, store.Len())
+
+	require.Nil(t, r.HandleMessage(ctx, newMOPart(t, 2, 3, 2, "two ")))
+	require.Len(t, messages, 2)
+	require.Equal(t, "one two three", messages[1].Text)
+	require.Empty(t, messages[1].UDH)
+	_, _, _, found := messages[1].Concat()
+	require.False(t, found)
+	require.Equal(t, 1, store.Len())
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	require.NotNil(t, r.HandleMessage(canceled, newMOPart(t, 4, 2, 1, "canceled")))
+}
+
+func TestReassemblerSAR(t *testing.T) {
+	var messages []IncomingMessage
+	r := NewReassembler(func(m IncomingMessage) {
+		messages = append(messages, m)
+	})
+
+	for seq, text := range []string{"Xin chào ", "bạn"} {
+		p := newMO("+84901234567", "8888", text)
+		_ = p.Message.SetMessageWithEncoding(text, data.UCS2)
+		p.RegisterOptionalParam(pdu.Field{Tag: pdu.TagSarMsgRefNum, Data: []byte{0x01, 0x02}})
+		p.RegisterOptionalParam(pdu.Field{Tag: pdu.TagSarTotalSegments, Data: []byte{2}})
+		p.RegisterOptionalParam(pdu.Field{Tag: pdu.TagSarSegmentSeqnum, Data: []byte{byte(seq + 1)}})
+
+		m, ok := ParseIncomingMessage(received(t, p))
+		require.True(t, ok)
+		require.Nil(t, r.HandleMessage(context.Background(), m))
+	}
+	require.Len(t, messages, 1)
+	require.Equal(t, "Xin chào bạn", messages[0].Text)
+}
+
+func TestMemoryReassemblyStoreTimeout(t *testing.T) {
+	fake := clock.NewFake(time.Now())
+	store := NewMemoryReassemblyStore(time.Minute)
+	store.clock = fake
+	ctx := context.Background()
+
+	_, complete, err := store.AddPart(ctx, "a", 2, 1, []byte("a1"))
+	require.Nil(t, err)
+	require.False(t, complete)
+
+	fake.Advance(time.Minute)
+	_, _, _ = store.AddPart(ctx, "b", 2, 1, []byte("b1"))
+	require.Equal(t, 1, store.Len())
+
+	// first part of a is dropped
+	_, complete, _ = store.AddPart(ctx, "a", 2, 2, []byte("a2"))
+	require.False(t, complete)
+
+	parts, complete, _ := store.AddPart(ctx, "b", 2, 2, []byte("b2"))
+	require.True(t, complete)
+	require.Equal(t, [][]byte{[]byte("b1"), []byte("b2")}, parts)
+}
+
+func TestReassemblerDeduplication(t *testing.T) {
+	var messages []IncomingMessage
+	store := NewMemoryReassemblyStore(0)
+	r := NewReassembler(func(m IncomingMessage) {
+		messages = append(messages, m)
+	}, WithReassemblyStore(store), WithDeduplication(store))
+	ctx := context.Background()
+
+	// the same message received on two binds, parts interleaved
+	require.Nil(t, r.HandleMessage(ctx, newMOPart(t, 7, 2, 1, "hello ")))
+	require.Nil(t, r.HandleMessage(ctx, newMOPart(t, 7, 2, 1, "hello ")))
+	require.Nil(t, r.HandleMessage(ctx, newMOPart(t, 7, 2, 2, "world")))
+	require.Nil(t, r.HandleMessage(ctx, newMOPart(t, 7, 2, 2, "world")))
+	require.Len(t, messages, 1)
+	require.Equal(t, "hello world", messages[0].Text)
+
+	// reused reference with other text is another message
+	require.Nil(t, r.HandleMessage(ctx, newMOPart(t, 7, 2, 1, "bye ")))
+	require.Nil(t, r.HandleMessage(ctx, newMOPart(t, 7, 2, 2, "world")))
+	require.Len(t, messages, 2)
+	require.Equal(t, "bye world", messages[1].Text)
+
+	// whole messages are not deduplicated
+	require.Nil(t, r.HandleMessage(ctx, newMOPart(t, 1, 1, 1, "YES")))
+	require.Nil(t, r.HandleMessage(ctx, newMOPart(t, 1, 1, 1, "YES")))
+	require.Len(t, messages, 4)
+}
+
+func TestMemoryReassemblyStoreFirstSeen(t *testing.T) {
+	fake := clock.NewFake(time.Now())
+	store := NewMemoryReassemblyStore(time.Minute)
+	store.clock = fake
+	ctx := context.Background()
+
+	first, err := store.FirstSeen(ctx, "a")
+	require.Nil(t, err)
+	require.True(t, first)
+
+	first, _ = store.FirstSeen(ctx, "a")
+	require.False(t, first)
+
+	fake.Advance(time.Minute)
+	first, _ = store.FirstSeen(ctx, "a")
+	require.True(t, first)
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = store.FirstSeen(canceled, "b")
+	require.ErrorIs(t, err, context.Canceled)
+}
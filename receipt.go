@@ -0,0 +1,134 @@
+package gosmpp
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+)
+
+// Receipt is SMSC delivery receipt of submitted message.
+type Receipt struct {
+	// MessageID is message_id of the submitted message.
+	MessageID string
+
+	// State is message state, e.g. data.SM_STATE_DELIVERED.
+	State byte
+
+	// Stat is state as written in receipt text, e.g. DELIVRD.
+	Stat string
+
+	// Err is network specific error code, "000" if none.
+	Err string
+
+	// Submitted and Delivered are numbers of short messages originally submitted and delivered.
+	Submitted, Delivered int
+
+	// SubmitDate and DoneDate are times of submission and reaching the final state, zero if not given.
+	SubmitDate, DoneDate time.Time
+
+	// Text is the beginning of the message.
+	Text string
+}
+
+// Final reports whether message reached the final state.
+func (r Receipt) Final() bool {
+	switch r.State {
+	case data.SM_STATE_DELIVERED, data.SM_STATE_EXPIRED, data.SM_STATE_DELETED,
+		data.SM_STATE_UNDELIVERABLE, data.SM_STATE_REJECTED:
+		return true
+	}
+	return false
+}
+
+// receiptStates are message states by their receipt stat.
+var receiptStates = map[string]byte{
+	"ENROUTE": data.SM_STATE_EN_ROUTE,
+	"DELIVRD": data.SM_STATE_DELIVERED,
+	"EXPIRED": data.SM_STATE_EXPIRED,
+	"DELETED": data.SM_STATE_DELETED,
+	"UNDELIV": data.SM_STATE_UNDELIVERABLE,
+	"ACCEPTD": data.SM_STATE_ACCEPTED,
+	"UNKNOWN": data.SM_STATE_INVALID,
+	"REJECTD": data.SM_STATE_REJECTED,
+}
+
+// receiptFields are keys of receipt text, in order.
+var receiptFields = []string{"id:", "sub:", "dlvrd:", "submit date:", "done date:", "stat:", "err:", "text:"}
+
+// ParseReceipt parses delivery receipt from deliver_sm, reporting false if p is not a receipt.
+//
+// Receipt text is expected in the common format:
+//
+//	id:IIIIIIIIII sub:SSS dlvrd:DDD submit date:YYMMDDhhmm done date:YYMMDDhhmm stat:DDDDDDD err:E text:...
+//
+// receipted_message_id and message_state TLVs take precedence over the text.
+func ParseReceipt(p *pdu.DeliverSM) (r Receipt, ok bool) {
+	if p.EsmClass&data.SM_SMSC_DLV_RCPT_TYPE == 0 {
+		return
+	}
+
+	text, _ := p.Message.GetMessage()
+	fields := parseReceiptText(text)
+
+	r = Receipt{
+		MessageID: fields["id:"],
+		Stat:      strings.ToUpper(fields["stat:"]),
+		Err:       fields["err:"],
+		Text:      fields["text:"],
+	}
+	r.State = receiptStates[r.Stat]
+	r.Submitted, _ = strconv.Atoi(fields["sub:"])
+	r.Delivered, _ = strconv.Atoi(fields["dlvrd:"])
+	r.SubmitDate = parseReceiptDate(fields["submit date:"])
+	r.DoneDate = parseReceiptDate(fields["done date:"])
+
+	if f, has := p.OptionalParameters[pdu.TagReceiptedMessageID]; has {
+		if id := f.String(); id != "" {
+			r.MessageID = id
+		}
+	}
+	if f, has := p.OptionalParameters[pdu.TagMessageStateOption]; has && len(f.Data) == 1 {
+		r.State = f.Data[0]
+	}
+
+	return r, r.MessageID != ""
+}
+
+// parseReceiptText returns values of receipt fields found in text. Text field takes the rest of text.
+func parseReceiptText(text string) map[string]string {
+	fields := make(map[string]string, len(receiptFields))
+
+	lower := strings.ToLower(text)
+	for _, key := range receiptFields {
+		i := strings.Index(lower, key)
+		if i < 0 {
+			continue
+		}
+
+		value := text[i+len(key):]
+		if key != "text:" {
+			if end := strings.IndexByte(value, ' '); end >= 0 {
+				value = value[:end]
+			}
+		}
+		fields[key] = value
+	}
+	return fields
+}
+
+// parseReceiptDate parses YYMMDDhhmm or YYMMDDhhmmss date, in UTC.
+func parseReceiptDate(s string) time.Time {
+	layout := "0601021504"
+	if len(s) == len(layout)+2 {
+		layout += "05"
+	}
+
+	t, err := time.Parse(layout, s)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
@@ -0,0 +1,49 @@
+package gosmpp_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp"
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+	"github.com/linxGnu/gosmpp/server/smsctest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionOnDeliveryReceipt(t *testing.T) {
+	smsc := smsctest.NewPipeServer(nil)
+	defer smsc.Close()
+	smsc.SetReceipts(&smsctest.Receipts{})
+
+	receipts := make(chan gosmpp.Receipt, 1)
+	s, err := gosmpp.NewSession(gosmpp.TRXConnector(smsc.Dialer(), gosmpp.Auth{SMSC: "pipe", SystemID: "esme"}),
+		gosmpp.Settings{
+			ReadTimeout: 2 * time.Second,
+
+			OnDeliveryReceipt: func(r gosmpp.Receipt) { receipts <- r },
+		}, -1)
+	require.Nil(t, err)
+	defer func() {
+		_ = s.Close()
+	}()
+
+	submit := pdu.NewSubmitSM().(*pdu.SubmitSM)
+	submit.SourceAddr, _ = gosmpp.NormalizeAddress("MyBank")
+	submit.DestAddr, _ = gosmpp.NormalizeAddress("+84901234567")
+	submit.RegisteredDelivery = data.SM_SMSC_RECEIPT_REQUESTED
+	_ = submit.Message.SetMessageWithEncoding("Your code is 1234", data.GSM7BIT)
+
+	resp, err := s.SubmitAsync(submit).Wait(context.Background())
+	require.Nil(t, err)
+
+	select {
+	case r := <-receipts:
+		require.Equal(t, resp.(*pdu.SubmitSMResp).MessageID, r.MessageID)
+		require.Equal(t, byte(data.SM_STATE_DELIVERED), r.State)
+	case <-time.After(5 * time.Second):
+		t.Fatal("receipt is not received")
+	}
+}
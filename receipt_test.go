@@ -0,0 +1,89 @@
+package gosmpp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newReceipt(text string) *pdu.DeliverSM {
+	p := pdu.NewDeliverSM().(*pdu.DeliverSM)
+	p.EsmClass = data.SM_SMSC_DLV_RCPT_TYPE
+	_ = p.Message.SetMessageWithEncoding(text, data.GSM7BIT)
+	return p
+}
+
+func TestParseReceipt(t *testing.T) {
+	p := newReceipt("id:0123abc sub:001 dlvrd:001 submit date:2405011230 done date:240501123059 stat:DELIVRD err:000 text:Hello world")
+	r, ok := ParseReceipt(p)
+	require.True(t, ok)
+	require.Equal(t, Receipt{
+		MessageID:  "0123abc",
+		State:      data.SM_STATE_DELIVERED,
+		Stat:       "DELIVRD",
+		Err:        "000",
+		Submitted:  1,
+		Delivered:  1,
+		SubmitDate: time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC),
+		DoneDate:   time.Date(2024, 5, 1, 12, 30, 59, 0, time.UTC),
+		Text:       "Hello world",
+	}, r)
+	require.True(t, r.Final())
+
+	p = newReceipt("id:1 Stat:undeliv err:012")
+	r, ok = ParseReceipt(p)
+	require.True(t, ok)
+	require.Equal(t, data.SM_STATE_UNDELIVERABLE, int(r.State))
+	require.Equal(t, "012", r.Err)
+	require.True(t, r.SubmitDate.IsZero())
+
+	// TLVs take precedence
+	p.RegisterOptionalParam(pdu.Field{Tag: pdu.TagReceiptedMessageID, Data: []byte("abc\x00")})
+	p.RegisterOptionalParam(pdu.Field{Tag: pdu.TagMessageStateOption, Data: []byte{data.SM_STATE_EN_ROUTE}})
+	r, ok = ParseReceipt(p)
+	require.True(t, ok)
+	require.Equal(t, "abc", r.MessageID)
+	require.Equal(t, data.SM_STATE_EN_ROUTE, int(r.State))
+	require.False(t, r.Final())
+
+	_, ok = ParseReceipt(newReceipt("stat:DELIVRD"))
+	require.False(t, ok)
+
+	p = newReceipt("id:1 stat:DELIVRD")
+	p.EsmClass = data.DFLT_ESM_CLASS
+	_, ok = ParseReceipt(p)
+	require.False(t, ok)
+}
+
+func TestReceivableOnDeliveryReceipt(t *testing.T) {
+	var (
+		receipts  []Receipt
+		messages  []IncomingMessage
+		pdus      []pdu.PDU
+		responses []pdu.PDU
+	)
+	r := &receivable{settings: Settings{
+		OnDeliveryReceipt: func(r Receipt) { receipts = append(receipts, r) },
+		OnMessage:         func(m IncomingMessage) { messages = append(messages, m) },
+		OnPDU:             func(p pdu.PDU, _ bool) { pdus = append(pdus, p) },
+		response:          func(p pdu.PDU) { responses = append(responses, p) },
+	}}
+
+	notification := newReceipt("id:2 stat:ENROUTE")
+	notification.EsmClass = data.SM_INTMD_DLV_NOTIFY_TYPE
+
+	require.False(t, r.handleOrClose(newReceipt("id:1 stat:DELIVRD")))
+	require.False(t, r.handleOrClose(newMO("+84901234567", "8888", "HELP")))
+	require.False(t, r.handleOrClose(notification))
+
+	require.Len(t, receipts, 1)
+	require.Equal(t, "1", receipts[0].MessageID)
+	require.Equal(t, byte(data.SM_STATE_DELIVERED), receipts[0].State)
+	require.Len(t, messages, 1)
+	require.Equal(t, []pdu.PDU{notification}, pdus)
+	require.Len(t, responses, 3)
+}
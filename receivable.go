@@ -2,10 +2,13 @@ package gosmpp
 
 import (
 	"context"
+	"errors"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/linxGnu/gosmpp/clock"
+	"github.com/linxGnu/gosmpp/data"
 	"github.com/linxGnu/gosmpp/pdu"
 )
 
@@ -17,6 +20,8 @@ type receivable struct {
 	conn         *Connection
 	aliveState   int32
 	requestStore RequestStore
+	stats        *linkStats
+	dispatcher   *dispatcher
 }
 
 func newReceivable(conn *Connection, settings Settings, requestStore RequestStore) *receivable {
@@ -61,10 +66,15 @@ func (t *receivable) closing(state State) {
 }
 
 func (t *receivable) start() {
+	t.dispatcher = newDispatcher(t.settings.ReceiveWorkers)
+
 	t.wg.Add(1)
 	go func() {
 		defer t.wg.Done()
 		t.loop()
+
+		// requests already read are still handled, they could be responded already
+		t.dispatcher.stop()
 	}()
 }
 
@@ -73,15 +83,40 @@ func (t *receivable) check(err error) (closing bool) {
 	if err == nil {
 		return
 	}
+	t.stats.onReceivingError(err)
 
 	if t.settings.OnReceivingError != nil {
 		t.settings.OnReceivingError(err)
 	}
 
+	// PDU over decode limits is skipped, reading goes on
+	var limitErr *pdu.TLVLimitError
+	if errors.As(err, &limitErr) {
+		t.nack(limitErr.Header.SequenceNumber, data.ESME_RINVOPTPARSTREAM)
+		return
+	}
+	var sizeErr *pdu.SizeError
+	if errors.As(err, &sizeErr) {
+		t.nack(sizeErr.Header.SequenceNumber, data.ESME_RINVCMDLEN)
+		return
+	}
+
 	closing = true
 	return
 }
 
+// nack answers skipped PDU of sequence number seq with generic_nack of status.
+func (t *receivable) nack(seq int32, status data.CommandStatusType) {
+	nack := pdu.NewGenericNack()
+	nack.SetSequenceNumber(seq)
+	if h, ok := nack.(interface {
+		SetCommandStatus(data.CommandStatusType)
+	}); ok {
+		h.SetCommandStatus(status)
+	}
+	t.settings.response(nack)
+}
+
 func (t *receivable) loop() {
 	var err error
 	for {
@@ -94,7 +129,7 @@ func (t *receivable) loop() {
 		// read pdu from conn
 		var p pdu.PDU
 		if err = t.conn.SetReadTimeout(t.settings.ReadTimeout); err == nil {
-			p, err = pdu.Parse(t.conn)
+			p, err = t.conn.ReadPDU()
 		}
 		closeOnError := t.check(err)
 		if closeOnError {
@@ -104,7 +139,15 @@ func (t *receivable) loop() {
 
 		var closeOnUnbind bool
 		if p != nil {
-			if t.settings.WindowedRequestTracking != nil && t.settings.OnExpectedPduResponse != nil {
+			rejected := t.validate(p)
+			t.stats.onReceived(p)
+			if rejected {
+				continue
+			}
+
+			if t.dispatcher != nil && isDispatched(p) {
+				t.dispatch(p)
+			} else if t.settings.WindowedRequestTracking != nil && t.settings.OnExpectedPduResponse != nil {
 				closeOnUnbind = t.handleWindowPdu(p)
 			} else if t.settings.OnAllPDU != nil {
 				closeOnUnbind = t.handleAllPdu(p)
@@ -119,6 +162,56 @@ func (t *receivable) loop() {
 	}
 }
 
+// validate checks received p against specification. Deviating p is reported and, with ValidationStrict,
+// rejected: requests are responded with the status of the deviation, and neither requests nor responses are
+// passed to handlers.
+func (t *receivable) validate(p pdu.PDU) (rejected bool) {
+	err := pdu.Validate(p)
+	if err == nil {
+		return
+	}
+
+	rejected = t.settings.Validation == ValidationStrict
+	t.stats.onDeviation(p, err, rejected)
+
+	if rejected && p.CanResponse() {
+		resp := p.GetResponse()
+		if h, ok := resp.(interface {
+			SetCommandStatus(data.CommandStatusType)
+		}); ok {
+			h.SetCommandStatus(err.(*pdu.ValidationError).Status())
+		}
+		t.settings.response(resp)
+	}
+	return
+}
+
+// dispatch handles request p on worker of its source address. Requests are responded before dispatching unless
+// the response is made by handler of OnAllPDU or WindowedRequestTracking.
+func (t *receivable) dispatch(p pdu.PDU) {
+	switch {
+	case t.settings.WindowedRequestTracking != nil && t.settings.OnExpectedPduResponse != nil:
+		t.dispatcher.dispatch(sourceOf(p), func() {
+			_ = t.handleWindowPdu(p)
+		})
+
+	case t.settings.OnAllPDU != nil:
+		t.dispatcher.dispatch(sourceOf(p), func() {
+			_ = t.handleAllPdu(p)
+		})
+
+	default:
+		responded := p.CanResponse()
+		if responded {
+			t.settings.response(p.GetResponse())
+		}
+		receivedAt := clock.OrReal(t.settings.Clock).Now()
+		t.dispatcher.dispatch(sourceOf(p), func() {
+			t.deliver(p, responded, receivedAt)
+		})
+	}
+}
+
 func (t *receivable) handleWindowPdu(p pdu.PDU) (closing bool) {
 	if t.settings.WindowedRequestTracking != nil && t.settings.OnExpectedPduResponse != nil && p != nil {
 		// This case must match the same request item list in transmittable write func
@@ -224,11 +317,32 @@ func (t *receivable) handleOrClose(p pdu.PDU) (closing bool) {
 				t.settings.response(p.GetResponse())
 				responded = true
 			}
+			t.deliver(p, responded, clock.OrReal(t.settings.Clock).Now())
+		}
+	}
+	return
+}
 
-			if t.settings.OnPDU != nil {
-				t.settings.OnPDU(p, responded)
+// deliver passes received p to the first of OnMessage, OnDeliveryReceipt or OnPDU handling it.
+func (t *receivable) deliver(p pdu.PDU, responded bool, receivedAt time.Time) {
+	if t.settings.OnMessage != nil {
+		if m, ok := ParseIncomingMessage(p); ok {
+			m.ReceivedAt = receivedAt
+			t.settings.OnMessage(m)
+			return
+		}
+	}
+
+	if t.settings.OnDeliveryReceipt != nil {
+		if deliver, ok := p.(*pdu.DeliverSM); ok {
+			if r, ok := ParseReceipt(deliver); ok {
+				t.settings.OnDeliveryReceipt(r)
+				return
 			}
 		}
 	}
-	return
+
+	if t.settings.OnPDU != nil {
+		t.settings.OnPDU(p, responded)
+	}
 }
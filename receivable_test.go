@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/linxGnu/gosmpp/data"
 	"github.com/linxGnu/gosmpp/pdu"
 
 	"github.com/stretchr/testify/require"
@@ -276,3 +277,22 @@ func Test_receivable_handleWindowPdu(t1 *testing.T) {
 		})
 	}
 }
+
+func Test_receivable_checkDecodeLimits(t *testing.T) {
+	var responses []pdu.PDU
+	r := &receivable{settings: Settings{
+		response: func(p pdu.PDU) { responses = append(responses, p) },
+	}}
+
+	require.False(t, r.check(&pdu.TLVLimitError{Header: pdu.Header{CommandID: data.DATA_SM, SequenceNumber: 7}}))
+	require.False(t, r.check(&pdu.SizeError{Header: pdu.Header{CommandID: data.DATA_SM, SequenceNumber: 8}}))
+	require.True(t, r.check(fmt.Errorf("broken")))
+
+	require.Len(t, responses, 2)
+	for i, status := range []data.CommandStatusType{data.ESME_RINVOPTPARSTREAM, data.ESME_RINVCMDLEN} {
+		h := responses[i].GetHeader()
+		require.Equal(t, data.GENERIC_NACK, h.CommandID)
+		require.Equal(t, status, h.CommandStatus)
+		require.EqualValues(t, 7+i, h.SequenceNumber)
+	}
+}
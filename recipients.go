@@ -0,0 +1,179 @@
+package gosmpp
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+)
+
+// ErrListNotFound indicates recipient list is not defined.
+var ErrListNotFound = errors.New("recipient list not found")
+
+// RecipientList is named list of recipients.
+type RecipientList struct {
+	// Name identifies the list in sends.
+	Name string
+
+	// Members are addresses of recipients, normalized as by SendText.
+	Members []string
+
+	// SMSCName is name of distribution list provisioned on SMSC with the members. If set, messages are
+	// submitted once with submit_multi to the distribution list, otherwise to each member.
+	SMSCName string
+}
+
+// RecipientLists keeps recipient lists, safe for concurrent use.
+type RecipientLists struct {
+	mu    sync.RWMutex
+	lists map[string]RecipientList
+}
+
+// NewRecipientLists returns empty RecipientLists.
+func NewRecipientLists() *RecipientLists {
+	return &RecipientLists{lists: make(map[string]RecipientList)}
+}
+
+// Define defines list, replacing the one of the same name.
+func (l *RecipientLists) Define(list RecipientList) {
+	list.Members = append([]string(nil), list.Members...)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lists[list.Name] = list
+}
+
+// Remove removes list by name.
+func (l *RecipientLists) Remove(name string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.lists, name)
+}
+
+// Get returns list by name.
+func (l *RecipientLists) Get(name string) (RecipientList, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	list, ok := l.lists[name]
+	return list, ok
+}
+
+// WithRecipientLists sets lists of recipients of SendToList.
+func WithRecipientLists(lists *RecipientLists) MessengerOption {
+	return func(m *Messenger) {
+		m.lists = lists
+	}
+}
+
+// ListResult is result of SendToList.
+type ListResult struct {
+	// Handles and Errors are results of sending to members, in order of members. For list provisioned
+	// on SMSC they are empty.
+	Handles []*MessageHandle
+	Errors  []error
+
+	// MessageIDs are message_id assigned by SMSC to submit_multi parts, in order, for list provisioned on SMSC.
+	MessageIDs []string
+}
+
+// Failed returns number of members which message failed to be sent to.
+func (r ListResult) Failed() (n int) {
+	for _, err := range r.Errors {
+		if err != nil {
+			n++
+		}
+	}
+	return
+}
+
+// SendToList sends text to recipients of list defined in lists given by WithRecipientLists. Text is encoded
+// and split as by SendText.
+//
+// Message to list provisioned on SMSC is submitted with submit_multi to its distribution list, waiting
+// for responses; error is *SubmitError if a part is rejected. Otherwise it is sent to members one by one,
+// their results are in ListResult and error is nil.
+func (m *Messenger) SendToList(ctx context.Context, from, name, text string) (r ListResult, err error) {
+	if m.lists == nil {
+		return r, ErrListNotFound
+	}
+	list, ok := m.lists.Get(name)
+	if !ok {
+		return r, ErrListNotFound
+	}
+
+	if list.SMSCName != "" {
+		r.MessageIDs, err = m.sendMulti(ctx, from, list.SMSCName, text)
+		return
+	}
+
+	r.Handles = make([]*MessageHandle, len(list.Members))
+	r.Errors = make([]error, len(list.Members))
+	for i, to := range list.Members {
+		if err = ctx.Err(); err != nil {
+			return
+		}
+		r.Handles[i], r.Errors[i] = m.SendText(ctx, from, to, text)
+	}
+	return
+}
+
+// sendMulti submits text with submit_multi to distribution list provisioned on SMSC, returning message_id of parts.
+func (m *Messenger) sendMulti(ctx context.Context, from, listName, text string) ([]string, error) {
+	dl, err := pdu.NewDistributionList(listName)
+	if err != nil {
+		return nil, err
+	}
+	dest := pdu.NewDestinationAddress()
+	dest.SetDistributionList(dl)
+
+	source, err := normalizeSource(m.normalizer, from)
+	if err != nil {
+		return nil, err
+	}
+
+	submit := pdu.NewSubmitSM().(*pdu.SubmitSM)
+	submit.SourceAddr = source
+	if err = submit.Message.SetLongMessageWithEnc(text, textEncoding(text)); err != nil {
+		return nil, err
+	}
+	parts, err := submit.Split()
+	if err != nil {
+		return nil, err
+	}
+	if err = m.price(ctx, parts); err != nil {
+		return nil, err
+	}
+
+	calls := make([]*Call, len(parts))
+	for i, part := range parts {
+		multi := pdu.NewSubmitMulti().(*pdu.SubmitMulti)
+		multi.ServiceType = m.serviceType
+		multi.SourceAddr = source
+		multi.DestAddrs.Add(dest)
+		multi.EsmClass = part.EsmClass
+		multi.ProtocolID = m.protocolID
+		multi.RegisteredDelivery = m.registeredDelivery
+		multi.Message = part.Message
+		calls[i] = m.session.SubmitAsync(multi, WithCorrelation(CorrelationFromContext(ctx)), withContext(ctx))
+	}
+
+	ids := make([]string, len(parts))
+	for i, c := range calls {
+		resp, err := c.Wait(ctx)
+		if err != nil {
+			for _, pending := range calls[i:] {
+				pending.Cancel()
+			}
+			return ids, err
+		}
+
+		r, ok := resp.(*pdu.SubmitMultiResp)
+		if !ok || r.CommandStatus != data.ESME_ROK {
+			return ids, &SubmitError{Part: i, Status: resp.GetHeader().CommandStatus}
+		}
+		ids[i] = r.MessageID
+	}
+	return ids, nil
+}
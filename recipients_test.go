@@ -0,0 +1,83 @@
+package gosmpp_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp"
+	"github.com/linxGnu/gosmpp/pdu"
+	"github.com/linxGnu/gosmpp/server"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecipientLists(t *testing.T) {
+	lists := gosmpp.NewRecipientLists()
+	members := []string{"+84901234567", "+84901234568"}
+	lists.Define(gosmpp.RecipientList{Name: "staff", Members: members})
+	members[0] = "changed"
+
+	list, ok := lists.Get("staff")
+	require.True(t, ok)
+	require.Equal(t, []string{"+84901234567", "+84901234568"}, list.Members)
+
+	lists.Remove("staff")
+	_, ok = lists.Get("staff")
+	require.False(t, ok)
+}
+
+func TestMessengerSendToList(t *testing.T) {
+	store := server.NewMemoryStore()
+	srv := &server.Server{Store: store}
+	defer func() {
+		_ = srv.Close()
+	}()
+
+	s, err := gosmpp.NewSession(gosmpp.TXConnector(srv.PipeDialer(), gosmpp.Auth{SMSC: "pipe", SystemID: "esme"}),
+		gosmpp.Settings{ReadTimeout: 2 * time.Second}, -1)
+	require.Nil(t, err)
+	defer func() {
+		_ = s.Close()
+	}()
+
+	lists := gosmpp.NewRecipientLists()
+	lists.Define(gosmpp.RecipientList{Name: "staff", Members: []string{"+84901234567", " ", "+84901234568"}})
+	lists.Define(gosmpp.RecipientList{Name: "all", SMSCName: "ALL"})
+	m := gosmpp.NewMessenger(s, gosmpp.WithRecipientLists(lists))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// fanned out to members
+	r, err := m.SendToList(ctx, "MyBank", "staff", "Meeting at 10")
+	require.Nil(t, err)
+	require.Len(t, r.Handles, 3)
+	require.Equal(t, 1, r.Failed())
+	require.Error(t, r.Errors[1])
+	require.Nil(t, r.Handles[1])
+	require.Equal(t, "84901234568", r.Handles[2].Parts[0].DestAddr.Address())
+	require.Empty(t, r.MessageIDs)
+
+	// submitted to distribution list on SMSC
+	r, err = m.SendToList(ctx, "MyBank", "all", strings.Repeat("long ", 40))
+	require.Nil(t, err)
+	require.Empty(t, r.Handles)
+	require.Len(t, r.MessageIDs, 2)
+
+	stored, err := store.Get(ctx, r.MessageIDs[0])
+	require.Nil(t, err)
+	multi := stored.Request.(*pdu.SubmitMulti)
+	require.Equal(t, "MyBank", multi.SourceAddr.Address())
+	dests := multi.DestAddrs.Get()
+	require.Len(t, dests, 1)
+	require.True(t, dests[0].IsDistributionList())
+	require.Equal(t, "ALL", dests[0].DistributionList().Name())
+
+	_, err = m.SendToList(ctx, "MyBank", "none", "text")
+	require.ErrorIs(t, err, gosmpp.ErrListNotFound)
+
+	_, err = gosmpp.NewMessenger(s).SendToList(ctx, "MyBank", "staff", "text")
+	require.ErrorIs(t, err, gosmpp.ErrListNotFound)
+}
@@ -14,6 +14,9 @@ import (
 type Request struct {
 	pdu.PDU
 	TimeSent time.Time
+
+	// Correlation of the call submitting the request, see WithCorrelation.
+	Correlation Correlation
 }
 
 // Response represents a response from a Request in the RequestStore
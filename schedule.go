@@ -0,0 +1,103 @@
+package gosmpp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/linxGnu/gosmpp/clock"
+)
+
+// WithScheduledDelivery tells whether SMSC honors schedule_delivery_time. Otherwise messages sent by
+// SendAt are held by Messenger until their time.
+func WithScheduledDelivery(supported bool) MessengerOption {
+	return func(m *Messenger) {
+		m.scheduled = supported
+	}
+}
+
+// SendAt sends message built by b for delivery at given time.
+//
+// If SMSC honors schedule_delivery_time (see WithScheduledDelivery), parts are submitted now with
+// the time set relative to now, not depending on SMSC clock. Otherwise the message is queued and submitted at the time, see MessageHandle.Wait.
+// Message of past time is sent now.
+func (m *Messenger) SendAt(ctx context.Context, b *SubmitSMBuilder, at time.Time) (*MessageHandle, error) {
+	submit, err := m.newSubmit(b.from, b.to)
+	if err != nil {
+		return nil, err
+	}
+
+	parts, err := b.build(submit)
+	if err != nil {
+		return nil, err
+	}
+
+	delay := at.Sub(m.clock.Now())
+	if delay <= 0 {
+		return m.send(ctx, parts)
+	}
+
+	if m.scheduled {
+		for _, part := range parts {
+			part.ScheduleDeliveryTime = relativeTime(delay)
+		}
+		return m.send(ctx, parts)
+	}
+
+	if err = m.price(ctx, parts); err != nil {
+		return nil, err
+	}
+
+	h := &MessageHandle{
+		ID:          newMessageID(),
+		Parts:       parts,
+		Correlation: CorrelationFromContext(ctx),
+		messenger:   m,
+		queued:      &queuedMessage{done: make(chan struct{})},
+	}
+	h.queued.timer = m.clock.AfterFunc(delay, func() {
+		h.queued.finish(m.deliver(context.Background(), h))
+	})
+	return h, nil
+}
+
+// Wait waits until message queued by SendAt is submitted, returning its error, or until ctx is done.
+// It returns nil at once for other messages.
+func (h *MessageHandle) Wait(ctx context.Context) error {
+	if h.queued == nil {
+		return nil
+	}
+
+	select {
+	case <-h.queued.done:
+		return h.queued.err
+
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// queuedMessage is message held by Messenger until its time.
+type queuedMessage struct {
+	timer clock.Timer
+	done  chan struct{}
+	once  sync.Once
+	err   error
+}
+
+func (q *queuedMessage) finish(err error) {
+	q.once.Do(func() {
+		q.err = err
+		close(q.done)
+	})
+}
+
+// relativeTime formats d in SMPP relative time format YYMMDDhhmmss000R, with years of 365 days
+// and months of 30 days.
+func relativeTime(d time.Duration) string {
+	sec := int64(d / time.Second)
+	days := sec / 86400
+	return fmt.Sprintf("%02d%02d%02d%02d%02d%02d000R",
+		days/365, days%365/30, days%365%30, sec%86400/3600, sec%3600/60, sec%60)
+}
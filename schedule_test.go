@@ -0,0 +1,65 @@
+package gosmpp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp/clock"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessengerSendAt(t *testing.T) {
+	c := clock.NewFake(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+
+	auth := nextAuth()
+	s, err := NewSession(TXConnector(NonTLSDialer, auth), Settings{ReadTimeout: 2 * time.Second, Clock: c}, -1)
+	require.Nil(t, err)
+	defer func() {
+		_ = s.Close()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	msg := func() *SubmitSMBuilder {
+		return NewSubmitSMBuilder().From("MyBank").To("84901234567").Text("reminder")
+	}
+	at := c.Now().Add(time.Hour + 30*time.Second)
+
+	// scheduled by SMSC
+	h, err := NewMessenger(s, WithScheduledDelivery(true)).SendAt(ctx, msg(), at)
+	require.Nil(t, err)
+	require.NotEmpty(t, h.MessageIDs[0])
+	require.Equal(t, "000000010030000R", h.Parts[0].ScheduleDeliveryTime)
+	require.Nil(t, h.Wait(ctx))
+
+	// queued
+	m := NewMessenger(s)
+	h, err = m.SendAt(ctx, msg(), at)
+	require.Nil(t, err)
+	require.Empty(t, h.Parts[0].ScheduleDeliveryTime)
+
+	short, cancelShort := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancelShort()
+	require.Equal(t, context.DeadlineExceeded, h.Wait(short))
+
+	c.Advance(time.Hour)
+	require.Equal(t, context.DeadlineExceeded, h.Wait(short))
+
+	c.Advance(30 * time.Second)
+	require.Nil(t, h.Wait(ctx))
+	require.NotEmpty(t, h.MessageIDs[0])
+
+	// past time is sent now
+	h, err = m.SendAt(ctx, msg(), c.Now().Add(-time.Minute))
+	require.Nil(t, err)
+	require.NotEmpty(t, h.MessageIDs[0])
+}
+
+func TestRelativeTime(t *testing.T) {
+	require.Equal(t, "000000000001000R", relativeTime(1500*time.Millisecond))
+	require.Equal(t, "000001020304000R", relativeTime(26*time.Hour+3*time.Minute+4*time.Second))
+	require.Equal(t, "010102000000000R", relativeTime(397*24*time.Hour))
+}
@@ -0,0 +1,65 @@
+package gosmpp
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+)
+
+// MaxSenderIDLength is maximal length of alphanumeric sender ID, see 3GPP TS 23.040 section 9.1.2.5.
+const MaxSenderIDLength = 11
+
+// senderIDPunctuation are characters other than letters, digits and space accepted in sender IDs by most networks.
+const senderIDPunctuation = "-_.&+'!?#,:/()"
+
+// SenderIDError indicates alphanumeric sender ID which would be rejected with ESME_RINVSRCADR.
+type SenderIDError struct {
+	// SenderID is the invalid sender ID.
+	SenderID string
+
+	// Reason tells what is wrong and how to fix it.
+	Reason string
+}
+
+func (err *SenderIDError) Error() string {
+	return fmt.Sprintf("gosmpp: invalid alphanumeric sender ID %q: %s", err.SenderID, err.Reason)
+}
+
+// ValidateSenderID checks alphanumeric sender ID: up to 11 characters of latin letters, digits, spaces
+// and punctuation -_.&+'!?#,:/(). Returned error is *SenderIDError.
+func ValidateSenderID(id string) error {
+	if strings.TrimSpace(id) == "" {
+		return &SenderIDError{SenderID: id, Reason: "it is empty"}
+	}
+
+	if n := utf8.RuneCountInString(id); n > MaxSenderIDLength {
+		return &SenderIDError{
+			SenderID: id,
+			Reason:   fmt.Sprintf("it has %d characters, shorten it to at most %d", n, MaxSenderIDLength),
+		}
+	}
+
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == ' ':
+		case strings.ContainsRune(senderIDPunctuation, r):
+		default:
+			return &SenderIDError{
+				SenderID: id,
+				Reason:   fmt.Sprintf("character %q is not allowed, use latin letters, digits, spaces or %s", r, senderIDPunctuation),
+			}
+		}
+	}
+	return nil
+}
+
+// NewSenderID returns validated alphanumeric source address, with TON alphanumeric and NPI unknown.
+func NewSenderID(id string) (pdu.Address, error) {
+	if err := ValidateSenderID(id); err != nil {
+		return pdu.Address{}, err
+	}
+	return pdu.NewAddressWithTonNpiAddr(data.GSM_TON_ALPHANUMERIC, data.GSM_NPI_UNKNOWN, id)
+}
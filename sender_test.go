@@ -0,0 +1,62 @@
+package gosmpp
+
+import (
+	"testing"
+
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateSenderID(t *testing.T) {
+	for _, id := range []string{"MyBank", "A", "Shop-24.com", "Tom & Jerry"} {
+		require.Nil(t, ValidateSenderID(id), id)
+	}
+
+	for id, reason := range map[string]string{
+		" ":            "it is empty",
+		"MyBankOnline": "it has 12 characters, shorten it to at most 11",
+		"Café":         `character 'é' is not allowed`,
+		"My@Bank":      `character '@' is not allowed`,
+	} {
+		err := ValidateSenderID(id)
+		var senderErr *SenderIDError
+		require.ErrorAs(t, err, &senderErr, id)
+		require.Equal(t, id, senderErr.SenderID)
+		require.Contains(t, senderErr.Reason, reason)
+	}
+}
+
+func TestNewSenderID(t *testing.T) {
+	a, err := NewSenderID("MyBank")
+	require.Nil(t, err)
+	require.Equal(t, data.GSM_TON_ALPHANUMERIC, a.Ton())
+	require.Equal(t, data.GSM_NPI_UNKNOWN, a.Npi())
+	require.Equal(t, "MyBank", a.Address())
+
+	_, err = NewSenderID("MyBankOnline")
+	require.NotNil(t, err)
+}
+
+func TestSubmitSenderID(t *testing.T) {
+	// wrong NPI of custom normalizer is corrected
+	normalizer := AddressNormalizerFunc(func(addr string) (a pdu.Address, err error) {
+		return pdu.NewAddressWithTonNpiAddr(data.GSM_TON_ALPHANUMERIC, data.GSM_NPI_ISDN, addr)
+	})
+	submit, err := newSubmit(normalizer, "MyBank", "MyFriend")
+	require.Nil(t, err)
+	require.Equal(t, data.GSM_NPI_UNKNOWN, submit.SourceAddr.Npi())
+
+	_, err = newSubmit(DefaultAddressNormalizer, "My Bank Online", "+84901234567")
+	var senderErr *SenderIDError
+	require.ErrorAs(t, err, &senderErr)
+
+	_, err = NewSubmitSMBuilder().From("$Shop").To("+84901234567").Text("hi").Build()
+	require.ErrorAs(t, err, &senderErr)
+
+	// numeric sources are not sender IDs
+	submit, err = newSubmit(DefaultAddressNormalizer, "+44 7700 900123", "+84901234567")
+	require.Nil(t, err)
+	require.Equal(t, data.GSM_TON_INTERNATIONAL, submit.SourceAddr.Ton())
+}
@@ -0,0 +1,104 @@
+package gosmpp
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// seqMapShards is number of shards of seqMap, power of two.
+const seqMapShards = 32
+
+// seqMap is map keyed by sequence number, tracking requests waiting for responses. It is sharded by sequence
+// number, so that producers submitting concurrently and the reader correlating responses rarely contend on the
+// same lock: consecutive sequence numbers fall into different shards.
+//
+// Zero value is empty map ready to use.
+type seqMap[V any] struct {
+	n      int64
+	shards [seqMapShards]seqMapShard[V]
+}
+
+type seqMapShard[V any] struct {
+	mu sync.Mutex
+	m  map[int32]V
+
+	// pads shard to cache line, against false sharing of neighbouring locks
+	_ [48]byte
+}
+
+func (m *seqMap[V]) shard(seq int32) *seqMapShard[V] {
+	return &m.shards[uint32(seq)&(seqMapShards-1)]
+}
+
+// len returns number of entries.
+func (m *seqMap[V]) len() int {
+	return int(atomic.LoadInt64(&m.n))
+}
+
+func (m *seqMap[V]) load(seq int32) (v V, ok bool) {
+	s := m.shard(seq)
+	s.mu.Lock()
+	v, ok = s.m[seq]
+	s.mu.Unlock()
+	return
+}
+
+func (m *seqMap[V]) store(seq int32, v V) {
+	s := m.shard(seq)
+	s.mu.Lock()
+	if s.m == nil {
+		s.m = make(map[int32]V)
+	}
+	if _, ok := s.m[seq]; !ok {
+		atomic.AddInt64(&m.n, 1)
+	}
+	s.m[seq] = v
+	s.mu.Unlock()
+}
+
+// take removes and returns entry of seq.
+func (m *seqMap[V]) take(seq int32) (v V, ok bool) {
+	return m.takeIf(seq, nil)
+}
+
+// takeIf removes and returns entry of seq if match accepts it, nil match accepts any entry.
+func (m *seqMap[V]) takeIf(seq int32, match func(V) bool) (v V, ok bool) {
+	s := m.shard(seq)
+	s.mu.Lock()
+	if v, ok = s.m[seq]; ok && (match == nil || match(v)) {
+		delete(s.m, seq)
+		atomic.AddInt64(&m.n, -1)
+	} else {
+		var zero V
+		v, ok = zero, false
+	}
+	s.mu.Unlock()
+	return
+}
+
+// each calls f for every entry, shard by shard. Entries are not removed.
+func (m *seqMap[V]) each(f func(seq int32, v V)) {
+	for i := range m.shards {
+		s := &m.shards[i]
+		s.mu.Lock()
+		for seq, v := range s.m {
+			f(seq, v)
+		}
+		s.mu.Unlock()
+	}
+}
+
+// drain removes all entries and returns them.
+func (m *seqMap[V]) drain() (values []V) {
+	for i := range m.shards {
+		s := &m.shards[i]
+		s.mu.Lock()
+		for _, v := range s.m {
+			values = append(values, v)
+		}
+		atomic.AddInt64(&m.n, -int64(len(s.m)))
+		s.m = nil
+		s.mu.Unlock()
+	}
+	return
+}
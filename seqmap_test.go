@@ -0,0 +1,85 @@
+package gosmpp
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeqMap(t *testing.T) {
+	var m seqMap[string]
+
+	_, ok := m.load(1)
+	require.False(t, ok)
+	_, ok = m.take(1)
+	require.False(t, ok)
+
+	m.store(1, "a")
+	m.store(1, "b")
+	m.store(-1, "c")
+	m.store(1+seqMapShards, "d")
+	require.Equal(t, 3, m.len())
+
+	v, ok := m.load(1)
+	require.True(t, ok)
+	require.Equal(t, "b", v)
+
+	_, ok = m.takeIf(1, func(v string) bool { return v == "a" })
+	require.False(t, ok)
+	v, ok = m.takeIf(1, func(v string) bool { return v == "b" })
+	require.True(t, ok)
+	require.Equal(t, "b", v)
+	require.Equal(t, 2, m.len())
+
+	seqs := []int32{}
+	m.each(func(seq int32, _ string) { seqs = append(seqs, seq) })
+	sort.Slice(seqs, func(i, j int) bool { return seqs[i] < seqs[j] })
+	require.Equal(t, []int32{-1, 1 + seqMapShards}, seqs)
+
+	values := m.drain()
+	sort.Strings(values)
+	require.Equal(t, []string{"c", "d"}, values)
+	require.Zero(t, m.len())
+	require.Empty(t, m.drain())
+
+	t.Run("concurrent", func(t *testing.T) {
+		var (
+			m  seqMap[int32]
+			wg sync.WaitGroup
+		)
+		for g := int32(0); g < 8; g++ {
+			wg.Add(1)
+			go func(g int32) {
+				defer wg.Done()
+				for seq := g * 1000; seq < (g+1)*1000; seq++ {
+					m.store(seq, seq)
+					v, ok := m.take(seq)
+					require.True(t, ok)
+					require.Equal(t, seq, v)
+				}
+			}(g)
+		}
+		wg.Wait()
+		require.Zero(t, m.len())
+	})
+}
+
+// BenchmarkSeqMap measures correlation of responses with requests of concurrent producers.
+func BenchmarkSeqMap(b *testing.B) {
+	var (
+		m   seqMap[int64]
+		seq int32
+	)
+
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			s := atomic.AddInt32(&seq, 1)
+			m.store(s, 1)
+			m.take(s)
+		}
+	})
+}
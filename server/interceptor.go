@@ -0,0 +1,49 @@
+package server
+
+import (
+	"github.com/linxGnu/gosmpp/pdu"
+)
+
+// Interceptor intercepts PDUs of every session, e.g. for audit logging, TLV normalization or spam filtering.
+// Either function might be nil.
+type Interceptor struct {
+	// Inbound is called for every PDU received from client, including enquire_link and unbind.
+	// Calling next passes PDU, possibly modified or replaced, to the rest of the chain and then to the session.
+	// Not calling next drops the PDU: interceptor could respond to it with Session.Submit itself.
+	Inbound func(s *Session, p pdu.PDU, next func(pdu.PDU))
+
+	// Outbound is called for every PDU written to client. Calling next passes PDU to the rest of the chain
+	// and then writes it. Not calling next drops the PDU.
+	Outbound func(s *Session, p pdu.PDU, next func(pdu.PDU) error) error
+}
+
+// inbound passes p through Inbound interceptors to handle.
+func (s *Session) inbound(p pdu.PDU, handle func(pdu.PDU)) {
+	var next func(i int, p pdu.PDU)
+	next = func(i int, p pdu.PDU) {
+		for ; i < len(s.srv.Interceptors); i++ {
+			if in := s.srv.Interceptors[i].Inbound; in != nil {
+				i := i
+				in(s, p, func(p pdu.PDU) { next(i+1, p) })
+				return
+			}
+		}
+		handle(p)
+	}
+	next(0, p)
+}
+
+// outbound passes p through Outbound interceptors to write.
+func (s *Session) outbound(p pdu.PDU, write func(pdu.PDU) error) error {
+	var next func(i int, p pdu.PDU) error
+	next = func(i int, p pdu.PDU) error {
+		for ; i < len(s.srv.Interceptors); i++ {
+			if out := s.srv.Interceptors[i].Outbound; out != nil {
+				i := i
+				return out(s, p, func(p pdu.PDU) error { return next(i+1, p) })
+			}
+		}
+		return write(p)
+	}
+	return next(0, p)
+}
@@ -0,0 +1,97 @@
+package server
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerInterceptors(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		audit []string
+	)
+	record := func(entry string) {
+		mu.Lock()
+		audit = append(audit, entry)
+		mu.Unlock()
+	}
+
+	sources := make(chan string, 1)
+	srv := &Server{
+		Interceptors: []Interceptor{
+			{
+				// audit logging
+				Inbound: func(s *Session, p pdu.PDU, next func(pdu.PDU)) {
+					record("in " + p.GetHeader().CommandID.String())
+					next(p)
+				},
+				Outbound: func(s *Session, p pdu.PDU, next func(pdu.PDU) error) error {
+					record("out " + p.GetHeader().CommandID.String())
+					return next(p)
+				},
+			},
+			{
+				// spam filtering
+				Inbound: func(s *Session, p pdu.PDU, next func(pdu.PDU)) {
+					if submit, ok := p.(*pdu.SubmitSM); ok {
+						if text, _ := submit.Message.GetMessage(); strings.Contains(text, "spam") {
+							resp := submit.GetResponse()
+							setStatus(resp, data.ESME_RSUBMITFAIL)
+							_ = s.Submit(resp)
+							return
+						}
+					}
+					next(p)
+				},
+			},
+			{}, // no-op
+			{
+				// normalization
+				Inbound: func(s *Session, p pdu.PDU, next func(pdu.PDU)) {
+					if submit, ok := p.(*pdu.SubmitSM); ok {
+						_ = submit.SourceAddr.SetAddress(strings.TrimPrefix(submit.SourceAddr.Address(), "+"))
+					}
+					next(p)
+				},
+			},
+		},
+		Handler: HandlerFunc(func(s *Session, p pdu.PDU) pdu.PDU {
+			if submit, ok := p.(*pdu.SubmitSM); ok {
+				sources <- submit.SourceAddr.Address()
+			}
+			return p.GetResponse()
+		}),
+	}
+	addr := startServer(t, srv)
+	c := bindRaw(t, addr, "esme", pdu.Transceiver)
+
+	submit := func(text string) data.CommandStatusType {
+		p := pdu.NewSubmitSM().(*pdu.SubmitSM)
+		_ = p.SourceAddr.SetAddress("+4477")
+		_ = p.Message.SetMessageWithEncoding(text, data.GSM7BIT)
+		_, err := c.WritePDU(p)
+		require.Nil(t, err)
+
+		resp, err := pdu.Parse(c)
+		require.Nil(t, err)
+		return resp.GetHeader().CommandStatus
+	}
+
+	require.Equal(t, data.ESME_RSUBMITFAIL, submit("buy spam"))
+	require.Equal(t, data.ESME_ROK, submit("hello"))
+	require.Equal(t, "4477", <-sources)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Equal(t, []string{
+		"out BIND_TRANSCEIVER_RESP",
+		"in SUBMIT_SM", "out SUBMIT_SM_RESP",
+		"in SUBMIT_SM", "out SUBMIT_SM_RESP",
+	}, audit)
+}
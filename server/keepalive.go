@@ -0,0 +1,109 @@
+package server
+
+import (
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/linxGnu/gosmpp/pdu"
+)
+
+var (
+	// ErrNotResponding indicates client did not respond to enquire_link within EnquireLinkTimeout.
+	ErrNotResponding = errors.New("smpp: client is not responding to enquire_link")
+
+	// ErrIdle indicates client did not send any request other than enquire_link within IdleTimeout.
+	ErrIdle = errors.New("smpp: client is idle")
+)
+
+// keepalive tracks activity of session.
+type keepalive struct {
+	lastReceived int64 // unix nano of last received PDU
+	lastActive   int64 // unix nano of last received PDU other than enquire_link/enquire_link_resp
+	enquiredAt   int64 // unix nano of sent enquire_link waiting for response, zero if none
+}
+
+func (k *keepalive) received(now time.Time, active bool) {
+	atomic.StoreInt64(&k.lastReceived, now.UnixNano())
+	if active {
+		atomic.StoreInt64(&k.lastActive, now.UnixNano())
+	}
+}
+
+func (k *keepalive) responded() {
+	atomic.StoreInt64(&k.enquiredAt, 0)
+}
+
+// keepaliveInterval returns interval of checking activity, zero if keepalive is disabled.
+func (srv *Server) keepaliveInterval() (interval time.Duration) {
+	for _, d := range []time.Duration{srv.EnquireLink, srv.EnquireLinkTimeout, srv.IdleTimeout} {
+		if d > 0 && (interval == 0 || d < interval) {
+			interval = d
+		}
+	}
+	return interval / 2
+}
+
+// keepalive sends enquire_link to silent client and closes not responding or idle one, until done is closed.
+func (s *Session) keepalive(done <-chan struct{}) {
+	interval := s.srv.keepaliveInterval()
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+
+		case now := <-ticker.C:
+			if err := s.checkAlive(now); err != nil {
+				s.closeBy(err)
+				return
+			}
+		}
+	}
+}
+
+func (s *Session) checkAlive(now time.Time) error {
+	srv, k := s.srv, &s.alive
+
+	if srv.IdleTimeout > 0 && now.Sub(time.Unix(0, atomic.LoadInt64(&k.lastActive))) >= srv.IdleTimeout {
+		return ErrIdle
+	}
+
+	if enquiredAt := atomic.LoadInt64(&k.enquiredAt); enquiredAt != 0 {
+		if srv.EnquireLinkTimeout > 0 && now.Sub(time.Unix(0, enquiredAt)) >= srv.EnquireLinkTimeout {
+			return ErrNotResponding
+		}
+		return nil
+	}
+
+	if srv.EnquireLink > 0 && now.Sub(time.Unix(0, atomic.LoadInt64(&k.lastReceived))) >= srv.EnquireLink {
+		atomic.StoreInt64(&k.enquiredAt, now.UnixNano())
+		_ = s.write(pdu.NewEnquireLink())
+	}
+	return nil
+}
+
+// closeBy closes session for reason: idle client is unbound, not responding one is disconnected.
+func (s *Session) closeBy(reason error) {
+	s.reasonMu.Lock()
+	s.reason = reason
+	s.reasonMu.Unlock()
+
+	if errors.Is(reason, ErrIdle) {
+		_ = s.Close()
+	} else {
+		_ = s.Abort()
+	}
+}
+
+func (s *Session) closeReason() error {
+	s.reasonMu.Lock()
+	defer s.reasonMu.Unlock()
+	return s.reason
+}
@@ -0,0 +1,85 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp/pdu"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeepaliveInterval(t *testing.T) {
+	require.Zero(t, (&Server{}).keepaliveInterval())
+	require.Equal(t, time.Second, (&Server{EnquireLink: 2 * time.Second}).keepaliveInterval())
+	require.Equal(t, 500*time.Millisecond, (&Server{EnquireLink: 2 * time.Second, IdleTimeout: time.Second}).keepaliveInterval())
+}
+
+func TestServerKeepalive(t *testing.T) {
+	reasons := make(chan error, 1)
+	srv := &Server{
+		EnquireLink:        50 * time.Millisecond,
+		EnquireLinkTimeout: 100 * time.Millisecond,
+		OnClosed: func(_ *Session, err error) {
+			reasons <- err
+		},
+	}
+	addr := startServer(t, srv)
+
+	t.Run("responding", func(t *testing.T) {
+		c := bindRaw(t, addr, "esme", pdu.Transceiver)
+
+		for i := 0; i < 3; i++ {
+			p, err := pdu.Parse(c)
+			require.Nil(t, err)
+			require.IsType(t, &pdu.EnquireLink{}, p)
+
+			_, err = c.WritePDU(p.GetResponse())
+			require.Nil(t, err)
+		}
+		require.Len(t, srv.Sessions(), 1)
+		require.Nil(t, c.Close())
+		require.Nil(t, <-reasons)
+	})
+
+	t.Run("notResponding", func(t *testing.T) {
+		c := bindRaw(t, addr, "esme", pdu.Transceiver)
+
+		p, err := pdu.Parse(c)
+		require.Nil(t, err)
+		require.IsType(t, &pdu.EnquireLink{}, p)
+
+		require.ErrorIs(t, <-reasons, ErrNotResponding)
+		_, err = pdu.Parse(c)
+		require.Error(t, err)
+	})
+}
+
+func TestServerIdleTimeout(t *testing.T) {
+	reasons := make(chan error, 1)
+	srv := &Server{
+		IdleTimeout: 200 * time.Millisecond,
+		OnClosed: func(_ *Session, err error) {
+			reasons <- err
+		},
+	}
+	addr := startServer(t, srv)
+	c := bindRaw(t, addr, "esme", pdu.Transceiver)
+
+	// enquire_link does not keep client active
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		_, err := c.WritePDU(pdu.NewEnquireLink())
+		require.Nil(t, err)
+		p, err := pdu.Parse(c)
+		require.Nil(t, err)
+		require.IsType(t, &pdu.EnquireLinkResp{}, p)
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	p, err := pdu.Parse(c)
+	require.Nil(t, err)
+	require.IsType(t, &pdu.Unbind{}, p)
+	require.GreaterOrEqual(t, time.Since(start), 200*time.Millisecond)
+	require.ErrorIs(t, <-reasons, ErrIdle)
+}
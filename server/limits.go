@@ -0,0 +1,129 @@
+package server
+
+import (
+	"sync"
+	"time"
+
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+)
+
+// Limits restricts requests of a bound client. Requests over the limits are responded with ESME_RTHROTTLED,
+// without calling Handler.
+type Limits struct {
+	// MaxOutstanding is maximum number of requests waiting for response, i.e. ones Handler did not respond
+	// immediately and are not responded by Session.Submit yet. Zero means no limit.
+	MaxOutstanding int
+
+	// TPS is maximum number of requests per second, zero means no limit.
+	TPS float64
+
+	// Burst is number of requests allowed to exceed TPS momentarily, default is 1.
+	Burst int
+
+	// MaxThrottled disconnects client after this number of consecutive throttled requests, zero means never.
+	MaxThrottled int
+}
+
+// tokenBucket limits rate of events.
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{rate: rate, burst: float64(burst), tokens: float64(burst)}
+}
+
+// allow takes a token at now if available.
+func (b *tokenBucket) allow(now time.Time) bool {
+	if !b.last.IsZero() {
+		b.tokens += now.Sub(b.last).Seconds() * b.rate
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+	}
+	b.last = now
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// limiter enforces Limits on requests of a session.
+type limiter struct {
+	limits Limits
+	bucket *tokenBucket
+
+	mu      sync.Mutex
+	pending map[int32]struct{}
+
+	// consecutive throttled requests, accessed by reading daemon only
+	throttled int
+}
+
+func newLimiter(limits Limits) *limiter {
+	l := &limiter{limits: limits, pending: make(map[int32]struct{})}
+	if limits.TPS > 0 {
+		l.bucket = newTokenBucket(limits.TPS, limits.Burst)
+	}
+	return l
+}
+
+// allow tells whether request could be handled.
+func (l *limiter) allow(now time.Time) bool {
+	if l.limits.MaxOutstanding > 0 {
+		l.mu.Lock()
+		full := len(l.pending) >= l.limits.MaxOutstanding
+		l.mu.Unlock()
+
+		if full {
+			l.throttled++
+			return false
+		}
+	}
+
+	if l.bucket != nil && !l.bucket.allow(now) {
+		l.throttled++
+		return false
+	}
+
+	l.throttled = 0
+	return true
+}
+
+// abusive tells whether client exceeded MaxThrottled.
+func (l *limiter) abusive() bool {
+	return l.limits.MaxThrottled > 0 && l.throttled >= l.limits.MaxThrottled
+}
+
+// track marks request as waiting for response.
+func (l *limiter) track(p pdu.PDU) {
+	if l.limits.MaxOutstanding > 0 {
+		l.mu.Lock()
+		l.pending[p.GetSequenceNumber()] = struct{}{}
+		l.mu.Unlock()
+	}
+}
+
+// responded marks request of response p as responded.
+func (l *limiter) responded(p pdu.PDU) {
+	if l.limits.MaxOutstanding > 0 {
+		l.mu.Lock()
+		delete(l.pending, p.GetSequenceNumber())
+		l.mu.Unlock()
+	}
+}
+
+// throttle responds request over the limits, returning true if client should be disconnected.
+func (s *Session) throttle(p pdu.PDU) (disconnect bool) {
+	_ = s.respond(p, data.ESME_RTHROTTLED)
+	return s.limiter.abusive()
+}
@@ -0,0 +1,123 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenBucket(t *testing.T) {
+	now := time.Now()
+	b := newTokenBucket(10, 2)
+
+	require.True(t, b.allow(now))
+	require.True(t, b.allow(now))
+	require.False(t, b.allow(now))
+
+	// refilled by rate, up to burst
+	require.True(t, b.allow(now.Add(100*time.Millisecond)))
+	require.False(t, b.allow(now.Add(100*time.Millisecond)))
+	require.True(t, b.allow(now.Add(time.Hour)))
+	require.True(t, b.allow(now.Add(time.Hour)))
+	require.False(t, b.allow(now.Add(time.Hour)))
+}
+
+func TestLimiter(t *testing.T) {
+	now := time.Now()
+	l := newLimiter(Limits{MaxOutstanding: 1, MaxThrottled: 2})
+
+	first := pdu.NewSubmitSM()
+	require.True(t, l.allow(now))
+	l.track(first)
+
+	require.False(t, l.allow(now))
+	require.False(t, l.abusive())
+	require.False(t, l.allow(now))
+	require.True(t, l.abusive())
+
+	l.responded(first.GetResponse())
+	require.True(t, l.allow(now))
+	require.False(t, l.abusive())
+
+	// no limits
+	l = newLimiter(Limits{})
+	for i := 0; i < 100; i++ {
+		require.True(t, l.allow(now))
+		l.track(pdu.NewSubmitSM())
+	}
+}
+
+func TestServerLimits(t *testing.T) {
+	deferred := make(chan pdu.PDU, 10)
+	srv := &Server{
+		Limits: func(systemID string) Limits {
+			if systemID == "window" {
+				return Limits{MaxOutstanding: 1}
+			}
+			return Limits{TPS: 0.001, Burst: 1, MaxThrottled: 2}
+		},
+		Handler: HandlerFunc(func(_ *Session, p pdu.PDU) pdu.PDU {
+			if _, ok := p.(*pdu.SubmitSM); ok {
+				deferred <- p
+				return nil
+			}
+			return p.GetResponse()
+		}),
+	}
+	addr := startServer(t, srv)
+
+	t.Run("window", func(t *testing.T) {
+		c := bindRaw(t, addr, "window", pdu.Transceiver)
+
+		// first request is responded later
+		_, err := c.WritePDU(pdu.NewSubmitSM())
+		require.Nil(t, err)
+		first := <-deferred
+
+		_, err = c.WritePDU(pdu.NewSubmitSM())
+		require.Nil(t, err)
+		p, err := pdu.Parse(c)
+		require.Nil(t, err)
+		require.Equal(t, data.ESME_RTHROTTLED, p.GetHeader().CommandStatus)
+
+		require.Nil(t, srv.SessionsOf("window")[0].Submit(first.GetResponse()))
+		p, err = pdu.Parse(c)
+		require.Nil(t, err)
+		require.Equal(t, first.GetSequenceNumber(), p.GetSequenceNumber())
+		require.True(t, p.IsOk())
+
+		// window is released
+		_, err = c.WritePDU(pdu.NewSubmitSM())
+		require.Nil(t, err)
+		<-deferred
+	})
+
+	t.Run("tps", func(t *testing.T) {
+		c := bindRaw(t, addr, "tps", pdu.Transceiver)
+
+		_, err := c.WritePDU(pdu.NewQuerySM())
+		require.Nil(t, err)
+		p, err := pdu.Parse(c)
+		require.Nil(t, err)
+		require.True(t, p.IsOk())
+
+		// throttled, then disconnected
+		for i := 0; i < 2; i++ {
+			_, err = c.WritePDU(pdu.NewQuerySM())
+			require.Nil(t, err)
+			p, err = pdu.Parse(c)
+			require.Nil(t, err)
+			require.Equal(t, data.ESME_RTHROTTLED, p.GetHeader().CommandStatus)
+		}
+
+		p, err = pdu.Parse(c)
+		require.Nil(t, err)
+		require.IsType(t, &pdu.Unbind{}, p)
+		_, err = pdu.Parse(c)
+		require.Error(t, err)
+	})
+}
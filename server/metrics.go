@@ -0,0 +1,68 @@
+package server
+
+import (
+	"github.com/linxGnu/gosmpp"
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+)
+
+// Names of metrics reported to Server.Metrics, labelled by system_id of account.
+const (
+	// MetricBinds counts accepted binds.
+	MetricBinds = "binds"
+
+	// MetricSubmits counts received submit_sm, submit_multi and data_sm.
+	MetricSubmits = "submits"
+
+	// MetricDeliverAttempts counts deliver_sm and data_sm written to clients, successfully or not.
+	MetricDeliverAttempts = "deliver_attempts"
+
+	// MetricErrors counts responses with error status, including rejected binds.
+	MetricErrors = "errors"
+
+	// MetricActiveSessions is gauge of bound sessions.
+	MetricActiveSessions = "active_sessions"
+)
+
+func accountLabels(systemID string) gosmpp.Labels {
+	return gosmpp.Labels{"system_id": systemID}
+}
+
+func (srv *Server) count(systemID, name string) {
+	if srv.Metrics != nil {
+		srv.Metrics.IncCounter(name, accountLabels(systemID), 1)
+	}
+}
+
+func (srv *Server) reportSessions(systemID string, n int) {
+	if srv.Metrics != nil {
+		srv.Metrics.SetGauge(MetricActiveSessions, accountLabels(systemID), float64(n))
+	}
+}
+
+// countWritten counts PDU written to client of the session.
+func (s *Session) countWritten(p pdu.PDU) {
+	if s.srv.Metrics == nil {
+		return
+	}
+
+	if isResponse(p) {
+		if p.GetHeader().CommandStatus != data.ESME_ROK {
+			s.srv.count(s.systemID, MetricErrors)
+		}
+		return
+	}
+
+	switch p.(type) {
+	case *pdu.DeliverSM, *pdu.DataSM:
+		s.srv.count(s.systemID, MetricDeliverAttempts)
+	}
+}
+
+// countReceived counts PDU received from client of the session.
+func (s *Session) countReceived(p pdu.PDU) {
+	switch p.(type) {
+	case *pdu.SubmitSM, *pdu.SubmitMulti, *pdu.DataSM:
+		s.srv.count(s.systemID, MetricSubmits)
+	}
+}
@@ -0,0 +1,92 @@
+package server
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp"
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+
+	"github.com/stretchr/testify/require"
+)
+
+// recordingCollector keeps counters and gauges by name and system_id label.
+type recordingCollector struct {
+	mu       sync.Mutex
+	counters map[string]int64
+	gauges   map[string]float64
+}
+
+func (c *recordingCollector) IncCounter(name string, labels gosmpp.Labels, delta int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.counters == nil {
+		c.counters = make(map[string]int64)
+	}
+	c.counters[name+"/"+labels["system_id"]] += delta
+}
+
+func (c *recordingCollector) SetGauge(name string, labels gosmpp.Labels, value float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.gauges == nil {
+		c.gauges = make(map[string]float64)
+	}
+	c.gauges[name+"/"+labels["system_id"]] = value
+}
+
+func (c *recordingCollector) counter(name, systemID string) int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.counters[name+"/"+systemID]
+}
+
+func (c *recordingCollector) gauge(name, systemID string) float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.gauges[name+"/"+systemID]
+}
+
+func TestServerMetrics(t *testing.T) {
+	collector := &recordingCollector{}
+	srv := &Server{
+		Metrics: collector,
+		Handler: HandlerFunc(func(s *Session, p pdu.PDU) pdu.PDU {
+			resp := p.GetResponse()
+			if p.GetSequenceNumber()%2 == 0 {
+				setStatus(resp, data.ESME_RSYSERR)
+			}
+			return resp
+		}),
+	}
+	addr := startServer(t, srv)
+
+	c := bindRaw(t, addr, "esme", pdu.Transceiver)
+	bindRaw(t, addr, "esme", pdu.Receiver)
+	require.EqualValues(t, 2, collector.counter(MetricBinds, "esme"))
+	require.EqualValues(t, 2, collector.gauge(MetricActiveSessions, "esme"))
+
+	for i := 0; i < 4; i++ {
+		p := pdu.NewSubmitSM()
+		p.SetSequenceNumber(int32(i + 1))
+		_, err := c.WritePDU(p)
+		require.Nil(t, err)
+		_, err = pdu.Parse(c)
+		require.Nil(t, err)
+	}
+	require.EqualValues(t, 4, collector.counter(MetricSubmits, "esme"))
+	require.EqualValues(t, 2, collector.counter(MetricErrors, "esme"))
+
+	require.Nil(t, srv.Deliver("esme", pdu.NewDeliverSM()))
+	require.Nil(t, srv.Deliver("esme", pdu.NewDeliverSM()))
+	require.EqualValues(t, 2, collector.counter(MetricDeliverAttempts, "esme"))
+
+	require.Nil(t, c.Close())
+	require.Eventually(t, func() bool {
+		return collector.gauge(MetricActiveSessions, "esme") == 1
+	}, time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,49 @@
+package server
+
+import (
+	"github.com/linxGnu/gosmpp"
+	"github.com/linxGnu/gosmpp/pdu"
+)
+
+// Outbind dials ESME at addr and sends outbind with given credentials, then waits for bind request
+// (bind_receiver) of the ESME on the same connection. The bind is authenticated and served as accepted one.
+//
+// Returned session is served until it is closed, like sessions of Serve.
+func (srv *Server) Outbind(dialer gosmpp.Dialer, addr, systemID, password string) (*Session, error) {
+	if !srv.acquire() {
+		return nil, ErrServerClosed
+	}
+
+	s, err := srv.outbind(dialer, addr, systemID, password)
+	if err != nil {
+		srv.wg.Done()
+		return nil, err
+	}
+
+	go func() {
+		defer srv.wg.Done()
+		srv.run(s)
+	}()
+	return s, nil
+}
+
+func (srv *Server) outbind(dialer gosmpp.Dialer, addr, systemID, password string) (*Session, error) {
+	netConn, err := dialer(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	req := pdu.NewOutbind().(*pdu.Outbind)
+	req.SystemID, req.Password = systemID, password
+	if _, err = gosmpp.NewConnection(netConn).WritePDU(req); err != nil {
+		_ = netConn.Close()
+		return nil, err
+	}
+
+	s, err := srv.bind(netConn)
+	if err != nil {
+		_ = netConn.Close()
+		return nil, err
+	}
+	return s, nil
+}
@@ -0,0 +1,80 @@
+package server
+
+import (
+	"net"
+	"testing"
+
+	"github.com/linxGnu/gosmpp"
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerOutbind(t *testing.T) {
+	srv := &Server{
+		SystemID: "smsc",
+		Authenticator: AuthenticatorFunc(func(info BindInfo) data.CommandStatusType {
+			if info.SystemID != "esme" {
+				return data.ESME_RINVSYSID
+			}
+			return data.ESME_ROK
+		}),
+	}
+	defer func() {
+		_ = srv.Close()
+	}()
+
+	// ESME waiting for outbind
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+	defer func() {
+		_ = l.Close()
+	}()
+
+	received := make(chan pdu.PDU, 2)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		c := gosmpp.NewConnection(conn)
+
+		p, err := pdu.Parse(c)
+		if err != nil {
+			return
+		}
+		received <- p
+
+		bind := pdu.NewBindRequest(pdu.Receiver)
+		bind.SystemID = "esme"
+		if _, err = c.WritePDU(bind); err != nil {
+			return
+		}
+
+		if p, err = pdu.Parse(c); err == nil {
+			received <- p
+		}
+	}()
+
+	s, err := srv.Outbind(gosmpp.NonTLSDialer, l.Addr().String(), "smsc", "secret")
+	require.Nil(t, err)
+	require.Equal(t, "esme", s.SystemID())
+	require.Equal(t, pdu.Receiver, s.BindingType())
+	require.Len(t, srv.Sessions(), 1)
+
+	outbind := (<-received).(*pdu.Outbind)
+	require.Equal(t, "smsc", outbind.SystemID)
+	require.Equal(t, "secret", outbind.Password)
+
+	resp := (<-received).(*pdu.BindResp)
+	require.True(t, resp.IsOk())
+	require.Equal(t, "smsc", resp.SystemID)
+
+	_, err = srv.Outbind(gosmpp.NonTLSDialer, "127.0.0.1:1", "smsc", "secret")
+	require.Error(t, err)
+
+	require.Nil(t, srv.Close())
+	_, err = srv.Outbind(gosmpp.NonTLSDialer, l.Addr().String(), "smsc", "secret")
+	require.ErrorIs(t, err, ErrServerClosed)
+}
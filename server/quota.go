@@ -0,0 +1,146 @@
+package server
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+)
+
+var (
+	// ErrDailyQuotaExceeded indicates account submitted Quota.DailyMessages today already.
+	// Submits over the quota are responded with ESME_RMSGQFUL.
+	ErrDailyQuotaExceeded = errors.New("smpp: daily message quota exceeded")
+
+	// ErrBindQuotaExceeded indicates account has Quota.MaxBinds bound sessions already.
+	// Binds over the quota are rejected with ESME_RBINDFAIL.
+	ErrBindQuotaExceeded = errors.New("smpp: bind quota exceeded")
+
+	// ErrBindingTypeNotAllowed indicates account is not allowed to bind with the binding type.
+	// Such binds are rejected with ESME_RBINDFAIL.
+	ErrBindingTypeNotAllowed = errors.New("smpp: binding type is not allowed for account")
+
+	// ErrSourceNotAllowed indicates account is not allowed to submit from the source address.
+	// Such submits are responded with ESME_RINVSRCADR.
+	ErrSourceNotAllowed = errors.New("smpp: source address is not allowed for account")
+)
+
+// Quota restricts usage of an account (system_id) across all its sessions.
+type Quota struct {
+	// DailyMessages is maximum number of messages submitted per day, zero means no limit.
+	// Each destination of submit_multi counts as a message.
+	DailyMessages int
+
+	// MaxBinds is maximum number of concurrent binds, overriding Server.MaxBindsPerAccount if set.
+	MaxBinds int
+
+	// SourceAddresses are allowed source addresses of submits, all are allowed if empty.
+	// Entry ending with '*' allows addresses with the prefix.
+	SourceAddresses []string
+
+	// BindingTypes are allowed binding types, all are allowed if empty.
+	BindingTypes []pdu.BindingType
+}
+
+func (q *Quota) allowsBindingType(t pdu.BindingType) bool {
+	if len(q.BindingTypes) == 0 {
+		return true
+	}
+	for _, allowed := range q.BindingTypes {
+		if allowed == t {
+			return true
+		}
+	}
+	return false
+}
+
+func (q *Quota) allowsSource(addr string) bool {
+	if len(q.SourceAddresses) == 0 {
+		return true
+	}
+	for _, allowed := range q.SourceAddresses {
+		if prefix := strings.TrimSuffix(allowed, "*"); prefix != allowed {
+			if strings.HasPrefix(addr, prefix) {
+				return true
+			}
+		} else if allowed == addr {
+			return true
+		}
+	}
+	return false
+}
+
+// usage counts messages submitted per account per day.
+type usage struct {
+	mu       sync.Mutex
+	day      string
+	messages map[string]int
+}
+
+// take counts n messages of systemID at now, false if it would exceed limit.
+func (u *usage) take(systemID string, n, limit int, now time.Time) bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if day := now.Format("20060102"); day != u.day || u.messages == nil {
+		u.day, u.messages = day, make(map[string]int)
+	}
+
+	if used := u.messages[systemID]; used+n > limit {
+		return false
+	}
+	u.messages[systemID] += n
+	return true
+}
+
+// MessagesToday returns number of messages submitted by account of systemID today.
+func (srv *Server) MessagesToday(systemID string) int {
+	u := &srv.usage
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.day != time.Now().Format("20060102") {
+		return 0
+	}
+	return u.messages[systemID]
+}
+
+func (srv *Server) quotaExceeded(systemID string, err error) {
+	if srv.OnQuotaExceeded != nil {
+		srv.OnQuotaExceeded(systemID, err)
+	}
+}
+
+// checkQuota returns status of submit p according to quota of the session account.
+func (s *Session) checkQuota(p pdu.PDU) data.CommandStatusType {
+	var (
+		source string
+		n      = 1
+	)
+	switch req := p.(type) {
+	case *pdu.SubmitSM:
+		source = req.SourceAddr.Address()
+	case *pdu.DataSM:
+		source = req.SourceAddr.Address()
+	case *pdu.SubmitMulti:
+		source = req.SourceAddr.Address()
+		n = len(req.DestAddrs.Get())
+	default:
+		return data.ESME_ROK
+	}
+
+	q := &s.quota
+	if !q.allowsSource(source) {
+		s.srv.quotaExceeded(s.systemID, ErrSourceNotAllowed)
+		return data.ESME_RINVSRCADR
+	}
+
+	if q.DailyMessages > 0 && !s.srv.usage.take(s.systemID, n, q.DailyMessages, time.Now()) {
+		s.srv.quotaExceeded(s.systemID, ErrDailyQuotaExceeded)
+		return data.ESME_RMSGQFUL
+	}
+	return data.ESME_ROK
+}
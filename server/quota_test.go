@@ -0,0 +1,101 @@
+package server
+
+import (
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp"
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuota(t *testing.T) {
+	q := Quota{SourceAddresses: []string{"Bank", "4477*"}, BindingTypes: []pdu.BindingType{pdu.Transmitter}}
+	require.True(t, q.allowsSource("Bank"))
+	require.True(t, q.allowsSource("447700900123"))
+	require.False(t, q.allowsSource("Banks"))
+	require.False(t, q.allowsSource("4478"))
+	require.True(t, q.allowsBindingType(pdu.Transmitter))
+	require.False(t, q.allowsBindingType(pdu.Receiver))
+
+	var none Quota
+	require.True(t, none.allowsSource("any"))
+	require.True(t, none.allowsBindingType(pdu.Receiver))
+
+	var u usage
+	today := time.Date(2024, 5, 1, 23, 59, 0, 0, time.UTC)
+	require.True(t, u.take("a", 2, 3, today))
+	require.False(t, u.take("a", 2, 3, today))
+	require.True(t, u.take("a", 1, 3, today))
+	require.True(t, u.take("b", 3, 3, today))
+	require.True(t, u.take("a", 3, 3, today.Add(time.Minute)))
+}
+
+func TestServerQuotas(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		exceeded []error
+	)
+	srv := &Server{
+		Quotas: func(systemID string) Quota {
+			return Quota{
+				DailyMessages:   2,
+				MaxBinds:        1,
+				SourceAddresses: []string{systemID},
+				BindingTypes:    []pdu.BindingType{pdu.Transmitter, pdu.Transceiver},
+			}
+		},
+		OnQuotaExceeded: func(systemID string, err error) {
+			mu.Lock()
+			exceeded = append(exceeded, err)
+			mu.Unlock()
+		},
+	}
+	addr := startServer(t, srv)
+
+	bind := func(bindingType pdu.BindingType) data.CommandStatusType {
+		conn, err := net.Dial("tcp", addr)
+		require.Nil(t, err)
+		c := gosmpp.NewConnection(conn)
+		defer func() {
+			_ = c.Close()
+		}()
+
+		req := pdu.NewBindRequest(bindingType)
+		req.SystemID = "esme"
+		_, err = c.WritePDU(req)
+		require.Nil(t, err)
+
+		resp, err := pdu.Parse(c)
+		require.Nil(t, err)
+		return resp.GetHeader().CommandStatus
+	}
+	require.Equal(t, data.ESME_RBINDFAIL, bind(pdu.Receiver))
+
+	c := bindRaw(t, addr, "esme", pdu.Transmitter)
+	require.Equal(t, data.ESME_RBINDFAIL, bind(pdu.Transceiver))
+
+	submit := func(source string) data.CommandStatusType {
+		p := pdu.NewSubmitSM().(*pdu.SubmitSM)
+		_ = p.SourceAddr.SetAddress(source)
+		_, err := c.WritePDU(p)
+		require.Nil(t, err)
+
+		resp, err := pdu.Parse(c)
+		require.Nil(t, err)
+		return resp.GetHeader().CommandStatus
+	}
+	require.Equal(t, data.ESME_RINVSRCADR, submit("other"))
+	require.Equal(t, data.ESME_ROK, submit("esme"))
+	require.Equal(t, data.ESME_ROK, submit("esme"))
+	require.Equal(t, data.ESME_RMSGQFUL, submit("esme"))
+	require.Equal(t, 2, srv.MessagesToday("esme"))
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Equal(t, []error{ErrBindingTypeNotAllowed, ErrBindQuotaExceeded, ErrSourceNotAllowed, ErrDailyQuotaExceeded}, exceeded)
+}
@@ -0,0 +1,128 @@
+package server
+
+import (
+	"errors"
+
+	"github.com/linxGnu/gosmpp/pdu"
+)
+
+// ErrNoReceiver indicates account has no bound receiver nor transceiver.
+var ErrNoReceiver = errors.New("smpp: no receiver bound for account")
+
+// registry keeps bound sessions by system_id. Guarded by Server.mu.
+type registry struct {
+	accounts map[string]*account
+}
+
+// account is bound sessions of system_id, in binding order.
+type account struct {
+	sessions []*Session
+	next     int // round-robin cursor of receivers
+}
+
+func (r *registry) add(s *Session) {
+	if r.accounts == nil {
+		r.accounts = make(map[string]*account)
+	}
+
+	acc := r.accounts[s.systemID]
+	if acc == nil {
+		acc = &account{}
+		r.accounts[s.systemID] = acc
+	}
+	acc.sessions = append(acc.sessions, s)
+}
+
+func (r *registry) remove(s *Session) {
+	acc := r.accounts[s.systemID]
+	if acc == nil {
+		return
+	}
+
+	for i := range acc.sessions {
+		if acc.sessions[i] == s {
+			acc.sessions = append(acc.sessions[:i], acc.sessions[i+1:]...)
+			break
+		}
+	}
+	if len(acc.sessions) == 0 {
+		delete(r.accounts, s.systemID)
+	}
+}
+
+func (r *registry) count(systemID string) int {
+	if acc := r.accounts[systemID]; acc != nil {
+		return len(acc.sessions)
+	}
+	return 0
+}
+
+func (r *registry) all() (sessions []*Session) {
+	for _, acc := range r.accounts {
+		sessions = append(sessions, acc.sessions...)
+	}
+	return
+}
+
+// of returns sessions of systemID having one of binding types, all if no type is given.
+func (r *registry) of(systemID string, types ...pdu.BindingType) (sessions []*Session) {
+	acc := r.accounts[systemID]
+	if acc == nil {
+		return
+	}
+
+	for _, s := range acc.sessions {
+		if len(types) == 0 || hasBindingType(types, s.bindingType) {
+			sessions = append(sessions, s)
+		}
+	}
+	return
+}
+
+// receivers returns receivers and transceivers of systemID, rotated for round-robin.
+func (r *registry) receivers(systemID string) []*Session {
+	sessions := r.of(systemID, pdu.Receiver, pdu.Transceiver)
+	if len(sessions) == 0 {
+		return nil
+	}
+
+	acc := r.accounts[systemID]
+	start := acc.next % len(sessions)
+	acc.next = start + 1
+
+	return append(append(make([]*Session, 0, len(sessions)), sessions[start:]...), sessions[:start]...)
+}
+
+func hasBindingType(types []pdu.BindingType, t pdu.BindingType) bool {
+	for _, v := range types {
+		if v == t {
+			return true
+		}
+	}
+	return false
+}
+
+// SessionsOf returns bound sessions of system_id, filtered by binding types if given.
+func (srv *Server) SessionsOf(systemID string, types ...pdu.BindingType) []*Session {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	return srv.registry.of(systemID, types...)
+}
+
+// Deliver submits p (e.g. deliver_sm) to a receiver or transceiver of system_id.
+//
+// Binds of the account are chosen round-robin; if submitting fails, the next one is tried.
+// ErrNoReceiver is returned if the account has no such bind.
+func (srv *Server) Deliver(systemID string, p pdu.PDU) (err error) {
+	srv.mu.Lock()
+	sessions := srv.registry.receivers(systemID)
+	srv.mu.Unlock()
+
+	err = ErrNoReceiver
+	for _, s := range sessions {
+		if err = s.Submit(p); err == nil {
+			return
+		}
+	}
+	return
+}
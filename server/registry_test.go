@@ -0,0 +1,64 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/linxGnu/gosmpp"
+	"github.com/linxGnu/gosmpp/pdu"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry(t *testing.T) {
+	var r registry
+
+	tx := &Session{systemID: "a", bindingType: pdu.Transmitter}
+	rx1 := &Session{systemID: "a", bindingType: pdu.Receiver}
+	rx2 := &Session{systemID: "a", bindingType: pdu.Transceiver}
+	other := &Session{systemID: "b", bindingType: pdu.Receiver}
+	for _, s := range []*Session{tx, rx1, rx2, other} {
+		r.add(s)
+	}
+
+	require.Equal(t, 3, r.count("a"))
+	require.Len(t, r.all(), 4)
+	require.Equal(t, []*Session{tx, rx1, rx2}, r.of("a"))
+	require.Equal(t, []*Session{tx}, r.of("a", pdu.Transmitter))
+	require.Empty(t, r.of("c"))
+
+	// round-robin
+	require.Equal(t, []*Session{rx1, rx2}, r.receivers("a"))
+	require.Equal(t, []*Session{rx2, rx1}, r.receivers("a"))
+	require.Equal(t, []*Session{rx1, rx2}, r.receivers("a"))
+
+	r.remove(rx1)
+	require.Equal(t, []*Session{rx2}, r.receivers("a"))
+
+	r.remove(tx)
+	r.remove(rx2)
+	require.Zero(t, r.count("a"))
+	require.Nil(t, r.receivers("a"))
+	require.NotContains(t, r.accounts, "a")
+}
+
+func TestServerDeliver(t *testing.T) {
+	srv := &Server{}
+	addr := startServer(t, srv)
+
+	require.ErrorIs(t, srv.Deliver("esme", pdu.NewDeliverSM()), ErrNoReceiver)
+
+	bindRaw(t, addr, "esme", pdu.Transmitter)
+	receivers := []*gosmpp.Connection{bindRaw(t, addr, "esme", pdu.Receiver), bindRaw(t, addr, "esme", pdu.Transceiver)}
+	require.Len(t, srv.SessionsOf("esme"), 3)
+	require.Len(t, srv.SessionsOf("esme", pdu.Receiver, pdu.Transceiver), 2)
+
+	// alternating between receivers
+	for i := 0; i < 4; i++ {
+		deliver := pdu.NewDeliverSM()
+		require.Nil(t, srv.Deliver("esme", deliver))
+
+		p, err := pdu.Parse(receivers[i%2])
+		require.Nil(t, err)
+		require.Equal(t, deliver.GetSequenceNumber(), p.GetSequenceNumber())
+	}
+}
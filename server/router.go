@@ -0,0 +1,193 @@
+package server
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+)
+
+// Matcher tells whether PDU matches a route.
+type Matcher func(p pdu.PDU) bool
+
+// Router is Handler dispatching PDUs to handlers of the first matching route, in registration order.
+//
+// PDUs not matching any route are passed to NotFound. If NotFound is nil, requests are responded with
+// ESME_RINVDSTADR and other PDUs are dropped.
+type Router struct {
+	routes []route
+
+	// NotFound handles PDUs not matching any route.
+	NotFound Handler
+}
+
+type route struct {
+	matchers []Matcher
+	handler  Handler
+}
+
+// NewRouter returns new Router without routes.
+func NewRouter() *Router {
+	return &Router{}
+}
+
+// Handle registers handler for PDUs matching all given matchers. Route without matchers matches all PDUs.
+func (r *Router) Handle(handler Handler, matchers ...Matcher) *Router {
+	r.routes = append(r.routes, route{matchers: matchers, handler: handler})
+	return r
+}
+
+// HandlePDU implements Handler.
+func (r *Router) HandlePDU(s *Session, p pdu.PDU) pdu.PDU {
+	for _, rt := range r.routes {
+		if rt.match(p) {
+			return rt.handler.HandlePDU(s, p)
+		}
+	}
+
+	if r.NotFound != nil {
+		return r.NotFound.HandlePDU(s, p)
+	}
+
+	if !p.CanResponse() {
+		return nil
+	}
+	resp := p.GetResponse()
+	if h, ok := resp.(interface {
+		SetCommandStatus(data.CommandStatusType)
+	}); ok {
+		h.SetCommandStatus(data.ESME_RINVDSTADR)
+	}
+	return resp
+}
+
+func (rt *route) match(p pdu.PDU) bool {
+	for _, m := range rt.matchers {
+		if !m(p) {
+			return false
+		}
+	}
+	return true
+}
+
+// CommandID matches PDUs of given command id, e.g. data.SUBMIT_SM.
+func CommandID(id data.CommandIDType) Matcher {
+	return func(p pdu.PDU) bool {
+		return p.GetHeader().CommandID == id
+	}
+}
+
+// DestinationPrefix matches submit_sm and data_sm with destination address starting with one of prefixes.
+// For submit_multi, all destination addresses must match.
+func DestinationPrefix(prefixes ...string) Matcher {
+	return func(p pdu.PDU) bool {
+		dests := destinationsOf(p)
+		if len(dests) == 0 {
+			return false
+		}
+
+		for _, dest := range dests {
+			if !hasPrefix(dest, prefixes) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// SourcePrefix matches submit_sm, data_sm and submit_multi with source address starting with one of prefixes.
+func SourcePrefix(prefixes ...string) Matcher {
+	return func(p pdu.PDU) bool {
+		source, ok := sourceOf(p)
+		return ok && hasPrefix(source, prefixes)
+	}
+}
+
+// ServiceType matches submit_sm, data_sm and submit_multi of one of service types.
+func ServiceType(serviceTypes ...string) Matcher {
+	return func(p pdu.PDU) bool {
+		switch pp := p.(type) {
+		case *pdu.SubmitSM:
+			return contains(serviceTypes, pp.ServiceType)
+		case *pdu.DataSM:
+			return contains(serviceTypes, pp.ServiceType)
+		case *pdu.SubmitMulti:
+			return contains(serviceTypes, pp.ServiceType)
+		default:
+			return false
+		}
+	}
+}
+
+// TLV matches submit_sm, data_sm and submit_multi having optional parameter of tag.
+// If value is not nil, data of the parameter must also be equal to it.
+func TLV(tag pdu.Tag, value []byte) Matcher {
+	return func(p pdu.PDU) bool {
+		var params map[pdu.Tag]pdu.Field
+		switch pp := p.(type) {
+		case *pdu.SubmitSM:
+			params = pp.OptionalParameters
+		case *pdu.DataSM:
+			params = pp.OptionalParameters
+		case *pdu.SubmitMulti:
+			params = pp.OptionalParameters
+		default:
+			return false
+		}
+
+		field, ok := params[tag]
+		return ok && (value == nil || bytes.Equal(field.Data, value))
+	}
+}
+
+func sourceOf(p pdu.PDU) (string, bool) {
+	switch pp := p.(type) {
+	case *pdu.SubmitSM:
+		return pp.SourceAddr.Address(), true
+	case *pdu.DataSM:
+		return pp.SourceAddr.Address(), true
+	case *pdu.SubmitMulti:
+		return pp.SourceAddr.Address(), true
+	default:
+		return "", false
+	}
+}
+
+func destinationsOf(p pdu.PDU) []string {
+	switch pp := p.(type) {
+	case *pdu.SubmitSM:
+		return []string{pp.DestAddr.Address()}
+	case *pdu.DataSM:
+		return []string{pp.DestAddr.Address()}
+	case *pdu.SubmitMulti:
+		var dests []string
+		for _, d := range pp.DestAddrs.Get() {
+			if d.IsAddress() {
+				addr := d.Address()
+				dests = append(dests, addr.Address())
+			}
+		}
+		return dests
+	default:
+		return nil
+	}
+}
+
+func hasPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
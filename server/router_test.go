@@ -0,0 +1,83 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newRoutedSubmit(source, dest, serviceType string) *pdu.SubmitSM {
+	p := pdu.NewSubmitSM().(*pdu.SubmitSM)
+	_ = p.SourceAddr.SetAddress(source)
+	_ = p.DestAddr.SetAddress(dest)
+	p.ServiceType = serviceType
+	return p
+}
+
+func TestMatchers(t *testing.T) {
+	submit := newRoutedSubmit("brand", "84901", "CMT")
+	submit.RegisterOptionalParam(pdu.Field{Tag: pdu.TagUserMessageReference, Data: []byte{0, 1}})
+
+	require.True(t, CommandID(data.SUBMIT_SM)(submit))
+	require.False(t, CommandID(data.DATA_SM)(submit))
+
+	require.True(t, DestinationPrefix("1", "849")(submit))
+	require.False(t, DestinationPrefix("1")(submit))
+	require.False(t, DestinationPrefix("")(pdu.NewQuerySM()))
+
+	require.True(t, SourcePrefix("bra")(submit))
+	require.False(t, SourcePrefix("x")(submit))
+
+	require.True(t, ServiceType("CMT", "WAP")(submit))
+	require.False(t, ServiceType("WAP")(submit))
+	require.False(t, ServiceType("")(pdu.NewQuerySM()))
+
+	require.True(t, TLV(pdu.TagUserMessageReference, nil)(submit))
+	require.True(t, TLV(pdu.TagUserMessageReference, []byte{0, 1})(submit))
+	require.False(t, TLV(pdu.TagUserMessageReference, []byte{0, 2})(submit))
+	require.False(t, TLV(pdu.TagSourcePort, nil)(submit))
+
+	multi := pdu.NewSubmitMulti().(*pdu.SubmitMulti)
+	for _, addr := range []string{"8490", "8491"} {
+		a, _ := pdu.NewAddressWithAddr(addr)
+		d := pdu.NewDestinationAddress()
+		d.SetAddress(a)
+		multi.DestAddrs.Add(d)
+	}
+	require.True(t, DestinationPrefix("849")(multi))
+	require.False(t, DestinationPrefix("8490")(multi))
+}
+
+func TestRouter(t *testing.T) {
+	handler := func(name string) Handler {
+		return HandlerFunc(func(_ *Session, p pdu.PDU) pdu.PDU {
+			resp := p.GetResponse().(*pdu.SubmitSMResp)
+			resp.MessageID = name
+			return resp
+		})
+	}
+
+	r := NewRouter().
+		Handle(handler("vn-promo"), DestinationPrefix("84"), ServiceType("PROMO")).
+		Handle(handler("vn"), DestinationPrefix("84")).
+		Handle(handler("brand"), SourcePrefix("brand"))
+
+	route := func(p pdu.PDU) pdu.PDU {
+		return r.HandlePDU(nil, p)
+	}
+
+	require.Equal(t, "vn-promo", route(newRoutedSubmit("1", "849", "PROMO")).(*pdu.SubmitSMResp).MessageID)
+	require.Equal(t, "vn", route(newRoutedSubmit("brand", "849", "")).(*pdu.SubmitSMResp).MessageID)
+	require.Equal(t, "brand", route(newRoutedSubmit("brand", "1", "")).(*pdu.SubmitSMResp).MessageID)
+
+	// no route
+	resp := route(newRoutedSubmit("1", "1", ""))
+	require.Equal(t, data.ESME_RINVDSTADR, resp.GetHeader().CommandStatus)
+	require.Nil(t, route(pdu.NewDeliverSMResp()))
+
+	r.NotFound = handler("default")
+	require.Equal(t, "default", route(newRoutedSubmit("1", "1", "")).(*pdu.SubmitSMResp).MessageID)
+}
@@ -0,0 +1,496 @@
+// Package server implements SMPP server (SMSC side) accepting ESME binds.
+//
+// Server listens on TCP, performs bind_receiver/bind_transmitter/bind_transceiver handshakes
+// and runs a Session per bound client. Requests of clients (submit_sm, data_sm, ...) are delivered
+// to Handler, which could push PDUs (e.g. deliver_sm) back to the client through Session.Submit.
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/linxGnu/gosmpp"
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+)
+
+const (
+	// DefaultBindTimeout is default duration to wait for bind request after accepting connection.
+	DefaultBindTimeout = 10 * time.Second
+
+	// DefaultUnbindTimeout is default timeout for writing unbind on closing session, if WriteTimeout is not set.
+	DefaultUnbindTimeout = time.Second
+)
+
+// ErrServerClosed is returned by Serve and ListenAndServe after Close.
+var ErrServerClosed = errors.New("smpp: server closed")
+
+// BindInfo describes bind request to authenticate.
+type BindInfo struct {
+	SystemID     string
+	Password     string
+	SystemType   string
+	BindingType  pdu.BindingType
+	AddressRange pdu.AddressRange
+	RemoteAddr   net.Addr
+
+	// PeerCertificates are certificates presented by client over TLS, empty for plain TCP.
+	PeerCertificates []*x509.Certificate
+}
+
+// Authenticator checks bind request, returning command status of bind_resp, e.g. data.ESME_RINVPASWD.
+// Bind is accepted on data.ESME_ROK.
+//
+// Authenticate might be called concurrently for different connections.
+type Authenticator interface {
+	Authenticate(info BindInfo) data.CommandStatusType
+}
+
+// AuthenticatorFunc is function adapter of Authenticator.
+type AuthenticatorFunc func(info BindInfo) data.CommandStatusType
+
+// Authenticate implements Authenticator.
+func (f AuthenticatorFunc) Authenticate(info BindInfo) data.CommandStatusType {
+	return f(info)
+}
+
+// Handler handles PDUs received from bound clients.
+//
+// HandlePDU is called sequentially from the reading daemon of the session, for requests and
+// responses (e.g. deliver_sm_resp). Returned PDU is written back as response, nil means no response
+// is written now: response could also be sent later with Session.Submit.
+//
+// enquire_link and unbind are handled by the session itself.
+type Handler interface {
+	HandlePDU(s *Session, p pdu.PDU) (response pdu.PDU)
+}
+
+// HandlerFunc is function adapter of Handler.
+type HandlerFunc func(s *Session, p pdu.PDU) pdu.PDU
+
+// HandlePDU implements Handler.
+func (f HandlerFunc) HandlePDU(s *Session, p pdu.PDU) pdu.PDU {
+	return f(s, p)
+}
+
+// Server accepts ESME binds.
+type Server struct {
+	// Addr is TCP address to listen on by ListenAndServe, ":2775" if empty.
+	Addr string
+
+	// SystemID is SMSC identifier sent in bind_resp.
+	SystemID string
+
+	// Authenticator checks bind requests. All binds are accepted if nil.
+	Authenticator Authenticator
+
+	// Limits returns limits of requests for account of system_id. No limits if nil.
+	Limits func(systemID string) Limits
+
+	// MaxBindsPerAccount limits number of concurrent binds of the same system_id, zero means no limit.
+	// Binds over the limit are rejected with ESME_RBINDFAIL.
+	MaxBindsPerAccount int
+
+	// Quotas returns quota of account of system_id, enforced across all its sessions. No quota if nil.
+	Quotas func(systemID string) Quota
+
+	// OnQuotaExceeded is called when request of account is rejected by its quota, with the reason,
+	// e.g. ErrDailyQuotaExceeded.
+	OnQuotaExceeded func(systemID string, err error)
+
+	// Handler handles PDUs from clients. If nil, requests are responded with status ESME_ROK.
+	Handler Handler
+
+	// Interceptors intercept PDUs of sessions, the first one is outermost.
+	// Bind request is not intercepted, its response is.
+	Interceptors []Interceptor
+
+	// Store persists accepted messages and serves query_sm, replace_sm and cancel_sm. Not used if nil.
+	Store MessageStore
+
+	// InterfaceVersion is the highest SMPP version supported by the server, data.SMPP_V50 if zero.
+	// Version of session is the lower of it and interface_version of bind request.
+	InterfaceVersion byte
+
+	// CongestionState returns congestion of the server in percent (0-100), reported in congestion_state
+	// of every response to SMPP 5.0 clients. Not reported if nil.
+	CongestionState func() byte
+
+	// RequireClientCert requires clients of ServeTLS and ListenAndServeTLS to present certificate.
+	RequireClientCert bool
+
+	// BindTimeout is duration to wait for bind request after accepting connection, default is DefaultBindTimeout.
+	BindTimeout time.Duration
+
+	// ReadTimeout closes session when nothing is received from client within this duration.
+	// Zero means no timeout, clients are expected to send enquire_link within it otherwise.
+	ReadTimeout time.Duration
+
+	// WriteTimeout is timeout for writing PDU to client.
+	WriteTimeout time.Duration
+
+	// EnquireLink is duration of silence from client, after which enquire_link is sent to it. Zero disables it.
+	EnquireLink time.Duration
+
+	// EnquireLinkTimeout disconnects client not responding to enquire_link within this duration.
+	// Zero means waiting for the response until ReadTimeout.
+	EnquireLinkTimeout time.Duration
+
+	// DecodeLimits caps optional parameters of PDUs received from clients, against payloads amplifying memory
+	// of the server. PDUs over the limits are responded with generic_nack of ESME_RINVOPTPARSTREAM and dropped.
+	// PDUs longer than MaxCommandLength are responded with generic_nack of ESME_RINVCMDLEN and dropped, PDUs
+	// written to clients are capped by it too, e.g. deliver_sm of Session.Submit fails with *pdu.SizeError.
+	// No limits if zero.
+	DecodeLimits pdu.DecodeLimits
+
+	// Pool, if set, recycles PDUs received from clients: each one is released to the pool once Interceptors and
+	// Handler return, so they must not retain it, e.g. to respond later from another goroutine. PDUs are not
+	// released when Store is set, it keeps submitted requests.
+	Pool *pdu.Pool
+
+	// IdleTimeout unbinds client not sending any request other than enquire_link within this duration.
+	// Zero means no limit.
+	IdleTimeout time.Duration
+
+	// Metrics receives per-account counters and gauges, see MetricBinds and others. Not reported if nil.
+	Metrics gosmpp.MetricsCollector
+
+	// OnBound is called when client is bound.
+	OnBound func(*Session)
+
+	// OnClosed is called when session of bound client is closed, with the reason: nil on unbind or Close,
+	// ErrIdle or ErrNotResponding if closed by keepalive.
+	OnClosed func(*Session, error)
+
+	mu        sync.Mutex
+	listeners map[net.Listener]struct{}
+	registry  registry
+	usage     usage
+	closed    bool
+	wg        sync.WaitGroup
+}
+
+// ListenAndServe listens on Addr and serves accepted connections.
+func (srv *Server) ListenAndServe() error {
+	addr := srv.Addr
+	if addr == "" {
+		addr = ":2775"
+	}
+
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return srv.Serve(l)
+}
+
+// Serve accepts connections on l and serves them, each in its own goroutine.
+// Serve always returns non-nil error and closes l.
+func (srv *Server) Serve(l net.Listener) error {
+	if !srv.track(l) {
+		_ = l.Close()
+		return ErrServerClosed
+	}
+	defer srv.untrack(l)
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if srv.isClosed() {
+				return ErrServerClosed
+			}
+
+			var nErr net.Error
+			if errors.As(err, &nErr) && nErr.Timeout() {
+				time.Sleep(10 * time.Millisecond)
+				continue
+			}
+			return err
+		}
+
+		if !srv.acquire() {
+			_ = conn.Close()
+			return ErrServerClosed
+		}
+		go func() {
+			defer srv.wg.Done()
+			srv.serve(conn)
+		}()
+	}
+}
+
+// ServeConn serves single connection, e.g. one end of net.Pipe, blocking until its session is closed.
+func (srv *Server) ServeConn(conn net.Conn) error {
+	if !srv.acquire() {
+		_ = conn.Close()
+		return ErrServerClosed
+	}
+	defer srv.wg.Done()
+
+	srv.serve(conn)
+	return nil
+}
+
+// PipeDialer returns dialer connecting client to the server in-process over net.Pipe, without TCP.
+// Address passed to the dialer is ignored.
+func (srv *Server) PipeDialer() gosmpp.Dialer {
+	return func(string) (net.Conn, error) {
+		client, server := net.Pipe()
+		go func() {
+			_ = srv.ServeConn(server)
+		}()
+		return client, nil
+	}
+}
+
+// Close closes listeners and all sessions, unbinding clients. It waits for session daemons to stop.
+func (srv *Server) Close() error {
+	srv.mu.Lock()
+	srv.closed = true
+	for l := range srv.listeners {
+		_ = l.Close()
+	}
+	sessions := srv.registry.all()
+	srv.mu.Unlock()
+
+	for _, s := range sessions {
+		_ = s.Close()
+	}
+
+	srv.wg.Wait()
+	return nil
+}
+
+// Sessions returns currently bound sessions.
+func (srv *Server) Sessions() []*Session {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	return srv.registry.all()
+}
+
+func (srv *Server) isClosed() bool {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	return srv.closed
+}
+
+// acquire registers serving goroutine to be waited by Close, false if the server is closed.
+func (srv *Server) acquire() bool {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	if srv.closed {
+		return false
+	}
+	srv.wg.Add(1)
+	return true
+}
+
+func (srv *Server) track(l net.Listener) bool {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	if srv.closed {
+		return false
+	}
+	if srv.listeners == nil {
+		srv.listeners = make(map[net.Listener]struct{})
+	}
+	srv.listeners[l] = struct{}{}
+	return true
+}
+
+func (srv *Server) untrack(l net.Listener) {
+	srv.mu.Lock()
+	delete(srv.listeners, l)
+	srv.mu.Unlock()
+	_ = l.Close()
+}
+
+// addSession registers bound session, returning ErrServerClosed or ErrBindQuotaExceeded if it is refused.
+func (srv *Server) addSession(s *Session) error {
+	srv.mu.Lock()
+
+	if srv.closed {
+		srv.mu.Unlock()
+		return ErrServerClosed
+	}
+
+	maxBinds := srv.MaxBindsPerAccount
+	if s.quota.MaxBinds > 0 {
+		maxBinds = s.quota.MaxBinds
+	}
+	if maxBinds > 0 && srv.registry.count(s.systemID) >= maxBinds {
+		srv.mu.Unlock()
+		return ErrBindQuotaExceeded
+	}
+
+	srv.registry.add(s)
+	n := srv.registry.count(s.systemID)
+	srv.mu.Unlock()
+
+	srv.count(s.systemID, MetricBinds)
+	srv.reportSessions(s.systemID, n)
+	return nil
+}
+
+func (srv *Server) removeSession(s *Session) {
+	srv.mu.Lock()
+	srv.registry.remove(s)
+	n := srv.registry.count(s.systemID)
+	srv.mu.Unlock()
+
+	srv.reportSessions(s.systemID, n)
+}
+
+// serve performs bind handshake then runs session until it is closed.
+func (srv *Server) serve(netConn net.Conn) {
+	s, err := srv.bind(netConn)
+	if err != nil {
+		_ = netConn.Close()
+		return
+	}
+
+	srv.run(s)
+}
+
+// run serves bound session until it is closed.
+func (srv *Server) run(s *Session) {
+	if srv.OnBound != nil {
+		srv.OnBound(s)
+	}
+
+	s.alive.received(time.Now(), true)
+
+	done := make(chan struct{})
+	go s.keepalive(done)
+
+	err := s.loop()
+	close(done)
+	srv.removeSession(s)
+	s.end()
+
+	if srv.OnClosed != nil {
+		srv.OnClosed(s, err)
+	}
+}
+
+// bind waits for bind request and responds to it. Bound session is registered to the server.
+func (srv *Server) bind(netConn net.Conn) (*Session, error) {
+	timeout := srv.BindTimeout
+	if timeout <= 0 {
+		timeout = DefaultBindTimeout
+	}
+
+	conn := gosmpp.NewConnection(netConn)
+	conn.SetDecodeLimits(srv.DecodeLimits)
+	if srv.Store == nil {
+		conn.SetPool(srv.Pool)
+	}
+	if err := conn.SetReadTimeout(timeout); err != nil {
+		return nil, err
+	}
+
+	var certs []*x509.Certificate
+	if tlsConn, ok := netConn.(*tls.Conn); ok {
+		if err := tlsConn.Handshake(); err != nil {
+			return nil, err
+		}
+		certs = tlsConn.ConnectionState().PeerCertificates
+	}
+
+	p, err := conn.ReadPDU()
+	if err != nil {
+		return nil, err
+	}
+
+	s := newSession(srv, conn)
+
+	req, ok := p.(*pdu.BindRequest)
+	if !ok {
+		// client must bind first
+		_ = s.respond(p, data.ESME_RINVBNDSTS)
+		return nil, ErrNotBound
+	}
+
+	s.systemID = req.SystemID
+	s.systemType = req.SystemType
+	s.bindingType = req.BindingType
+	s.addressRange = req.AddressRange
+	s.interfaceVersion = srv.negotiate(req.InterfaceVersion)
+
+	status := data.ESME_ROK
+	if srv.Authenticator != nil {
+		status = srv.Authenticator.Authenticate(BindInfo{
+			SystemID:         req.SystemID,
+			Password:         req.Password,
+			SystemType:       req.SystemType,
+			BindingType:      req.BindingType,
+			AddressRange:     req.AddressRange,
+			RemoteAddr:       conn.RemoteAddr(),
+			PeerCertificates: certs,
+		})
+	}
+	if status == data.ESME_ROK && srv.Quotas != nil {
+		if s.quota = srv.Quotas(req.SystemID); !s.quota.allowsBindingType(req.BindingType) {
+			srv.quotaExceeded(req.SystemID, ErrBindingTypeNotAllowed)
+			status = data.ESME_RBINDFAIL
+		}
+	}
+	if status == data.ESME_ROK {
+		if srv.Limits != nil {
+			s.limiter = newLimiter(srv.Limits(req.SystemID))
+		}
+		if err = srv.addSession(s); err != nil {
+			if errors.Is(err, ErrBindQuotaExceeded) {
+				srv.quotaExceeded(req.SystemID, err)
+			}
+			status = data.ESME_RBINDFAIL
+		}
+	}
+
+	resp := pdu.NewBindResp(*req)
+	resp.SystemID = srv.SystemID
+	resp.CommandStatus = status
+	if req.InterfaceVersion >= data.SMPP_V34 {
+		resp.RegisterOptionalParam(pdu.Field{Tag: pdu.TagScInterfaceVersion, Data: []byte{s.interfaceVersion}})
+	}
+	if err = s.write(resp); err != nil {
+		if status == data.ESME_ROK {
+			srv.removeSession(s)
+			s.end()
+		}
+		return nil, err
+	}
+
+	if status != data.ESME_ROK {
+		return nil, BindRejectedError{CommandStatus: status}
+	}
+	return s, nil
+}
+
+// negotiate returns interface version of session with client supporting given version.
+func (srv *Server) negotiate(version byte) byte {
+	supported := srv.InterfaceVersion
+	if supported == 0 {
+		supported = data.SMPP_V50
+	}
+	if version < supported {
+		return version
+	}
+	return supported
+}
+
+// BindRejectedError indicates bind request was rejected.
+type BindRejectedError struct {
+	CommandStatus data.CommandStatusType
+}
+
+func (err BindRejectedError) Error() string {
+	return "smpp: bind rejected (" + err.CommandStatus.String() + "): " + err.CommandStatus.Desc()
+}
@@ -0,0 +1,494 @@
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp"
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+
+	"github.com/stretchr/testify/require"
+)
+
+func startServer(t *testing.T, srv *Server) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- srv.Serve(l)
+	}()
+
+	t.Cleanup(func() {
+		require.Nil(t, srv.Close())
+		require.ErrorIs(t, <-done, ErrServerClosed)
+	})
+
+	return l.Addr().String()
+}
+
+// bindRaw binds connection to addr with given binding type.
+func bindRaw(t *testing.T, addr string, systemID string, bindingType pdu.BindingType) *gosmpp.Connection {
+	conn, err := net.Dial("tcp", addr)
+	require.Nil(t, err)
+	c := gosmpp.NewConnection(conn)
+	t.Cleanup(func() {
+		_ = c.Close()
+	})
+
+	req := pdu.NewBindRequest(bindingType)
+	req.SystemID = systemID
+	_, err = c.WritePDU(req)
+	require.Nil(t, err)
+
+	resp, err := pdu.Parse(c)
+	require.Nil(t, err)
+	require.True(t, resp.IsOk())
+	return c
+}
+
+func TestServer(t *testing.T) {
+	var submitted int32
+	srv := &Server{
+		SystemID: "smsc",
+		Authenticator: AuthenticatorFunc(func(info BindInfo) data.CommandStatusType {
+			if info.Password != "secret" {
+				return data.ESME_RINVPASWD
+			}
+			return data.ESME_ROK
+		}),
+		Handler: HandlerFunc(func(s *Session, p pdu.PDU) pdu.PDU {
+			submit, ok := p.(*pdu.SubmitSM)
+			if !ok {
+				return nil
+			}
+			atomic.AddInt32(&submitted, 1)
+
+			// push message back to client
+			deliver := pdu.NewDeliverSM().(*pdu.DeliverSM)
+			deliver.SourceAddr = submit.DestAddr
+			deliver.DestAddr = submit.SourceAddr
+			deliver.Message = submit.Message
+			go func() {
+				_ = s.Submit(deliver)
+			}()
+
+			return submit.GetResponse()
+		}),
+	}
+	addr := startServer(t, srv)
+
+	t.Run("rejected", func(t *testing.T) {
+		_, err := gosmpp.NewSession(
+			gosmpp.TRXConnector(gosmpp.NonTLSDialer, gosmpp.Auth{SMSC: addr, SystemID: "esme", Password: "wrong"}),
+			gosmpp.Settings{ReadTimeout: time.Second}, -1)
+		require.Error(t, err)
+	})
+
+	t.Run("transceiver", func(t *testing.T) {
+		delivered := make(chan pdu.PDU, 1)
+		client, err := gosmpp.NewSession(
+			gosmpp.TRXConnector(gosmpp.NonTLSDialer, gosmpp.Auth{SMSC: addr, SystemID: "esme", Password: "secret"}),
+			gosmpp.Settings{
+				ReadTimeout: time.Second,
+				OnPDU: func(p pdu.PDU, _ bool) {
+					if _, ok := p.(*pdu.DeliverSM); ok {
+						delivered <- p
+					}
+				},
+			}, -1)
+		require.Nil(t, err)
+
+		require.Eventually(t, func() bool {
+			return len(srv.Sessions()) == 1
+		}, time.Second, 10*time.Millisecond)
+		s := srv.Sessions()[0]
+		require.Equal(t, "esme", s.SystemID())
+		require.Equal(t, pdu.Transceiver, s.BindingType())
+
+		submit := pdu.NewSubmitSM().(*pdu.SubmitSM)
+		_ = submit.SourceAddr.SetAddress("111")
+		_ = submit.DestAddr.SetAddress("222")
+		_ = submit.Message.SetMessageWithEncoding("hello", data.GSM7BIT)
+		require.Nil(t, client.Transceiver().Submit(submit))
+
+		select {
+		case p := <-delivered:
+			require.Equal(t, "111", p.(*pdu.DeliverSM).DestAddr.Address())
+		case <-time.After(time.Second):
+			t.Fatal("deliver_sm is not received")
+		}
+		require.EqualValues(t, 1, atomic.LoadInt32(&submitted))
+
+		require.Nil(t, client.Close())
+		require.Eventually(t, func() bool {
+			return len(srv.Sessions()) == 0
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("receiver", func(t *testing.T) {
+		client, err := gosmpp.NewSession(
+			gosmpp.RXConnector(gosmpp.NonTLSDialer, gosmpp.Auth{SMSC: addr, SystemID: "rx", Password: "secret"}),
+			gosmpp.Settings{ReadTimeout: time.Second}, -1)
+		require.Nil(t, err)
+		defer func() {
+			_ = client.Close()
+		}()
+
+		require.Eventually(t, func() bool {
+			return len(srv.Sessions()) == 1
+		}, time.Second, 10*time.Millisecond)
+		require.Equal(t, pdu.Receiver, srv.Sessions()[0].BindingType())
+	})
+}
+
+func TestServerBindingType(t *testing.T) {
+	responses := make(chan pdu.PDU, 1)
+	srv := &Server{}
+	addr := startServer(t, srv)
+
+	conn, err := net.Dial("tcp", addr)
+	require.Nil(t, err)
+	c := gosmpp.NewConnection(conn)
+	defer func() {
+		_ = c.Close()
+	}()
+
+	// request before binding
+	_, err = c.WritePDU(pdu.NewSubmitSM())
+	require.Nil(t, err)
+	p, err := pdu.Parse(c)
+	require.Nil(t, err)
+	require.Equal(t, data.ESME_RINVBNDSTS, p.GetHeader().CommandStatus)
+
+	conn, err = net.Dial("tcp", addr)
+	require.Nil(t, err)
+	c = gosmpp.NewConnection(conn)
+
+	bind := pdu.NewBindRequest(pdu.Receiver)
+	bind.SystemID = "rx"
+	_, err = c.WritePDU(bind)
+	require.Nil(t, err)
+	p, err = pdu.Parse(c)
+	require.Nil(t, err)
+	require.IsType(t, &pdu.BindResp{}, p)
+	require.True(t, p.IsOk())
+
+	go func() {
+		for {
+			p, err := pdu.Parse(c)
+			if err != nil {
+				close(responses)
+				return
+			}
+			responses <- p
+		}
+	}()
+
+	// receiver is not allowed to submit
+	_, err = c.WritePDU(pdu.NewSubmitSM())
+	require.Nil(t, err)
+	p = <-responses
+	require.IsType(t, &pdu.SubmitSMResp{}, p)
+	require.Equal(t, data.ESME_RINVBNDSTS, p.GetHeader().CommandStatus)
+
+	_, err = c.WritePDU(pdu.NewEnquireLink())
+	require.Nil(t, err)
+	require.IsType(t, &pdu.EnquireLinkResp{}, <-responses)
+
+	// server unbinds client on Close
+	s := srv.Sessions()[0]
+	require.Nil(t, s.Submit(pdu.NewDeliverSM()))
+	require.IsType(t, &pdu.DeliverSM{}, <-responses)
+	require.Nil(t, s.Close())
+	require.IsType(t, &pdu.Unbind{}, <-responses)
+	require.ErrorIs(t, s.Submit(pdu.NewDeliverSM()), ErrSessionClosed)
+}
+
+func TestSessionSubmitTransmitter(t *testing.T) {
+	s := &Session{bindingType: pdu.Transmitter}
+	require.ErrorIs(t, s.Submit(pdu.NewDeliverSM()), ErrInvalidBindingType)
+}
+
+func selfSignedCert(t *testing.T, commonName string) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		DNSNames:     []string{"localhost"},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.Nil(t, err)
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+func TestServerAuthenticator(t *testing.T) {
+	infos := make(chan BindInfo, 1)
+	srv := &Server{
+		MaxBindsPerAccount: 1,
+		RequireClientCert:  true,
+		Authenticator: AuthenticatorFunc(func(info BindInfo) data.CommandStatusType {
+			infos <- info
+			return data.ESME_ROK
+		}),
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+	go func() {
+		_ = srv.ServeTLS(l, &tls.Config{
+			Certificates: []tls.Certificate{selfSignedCert(t, "smsc")},
+		})
+	}()
+	defer func() {
+		_ = srv.Close()
+	}()
+
+	bind := func() pdu.PDU {
+		conn, err := tls.Dial("tcp", l.Addr().String(), &tls.Config{
+			InsecureSkipVerify: true,
+			Certificates:       []tls.Certificate{selfSignedCert(t, "esme")},
+		})
+		require.Nil(t, err)
+		c := gosmpp.NewConnection(conn)
+		t.Cleanup(func() {
+			_ = c.Close()
+		})
+
+		req := pdu.NewBindRequest(pdu.Transmitter)
+		req.SystemID, req.Password = "esme", "secret"
+		_, err = c.WritePDU(req)
+		require.Nil(t, err)
+
+		resp, err := pdu.Parse(c)
+		require.Nil(t, err)
+		return resp
+	}
+
+	require.True(t, bind().IsOk())
+	info := <-infos
+	require.Equal(t, "esme", info.SystemID)
+	require.Equal(t, "secret", info.Password)
+	require.Equal(t, pdu.Transmitter, info.BindingType)
+	require.NotNil(t, info.RemoteAddr)
+	require.Len(t, info.PeerCertificates, 1)
+	require.Equal(t, "esme", info.PeerCertificates[0].Subject.CommonName)
+
+	// over the limit of the account
+	resp := bind()
+	<-infos
+	require.Equal(t, data.ESME_RBINDFAIL, resp.GetHeader().CommandStatus)
+	require.Len(t, srv.Sessions(), 1)
+}
+
+func TestServerPipe(t *testing.T) {
+	srv := &Server{SystemID: "smsc"}
+
+	conn, err := srv.PipeDialer()("ignored")
+	require.Nil(t, err)
+	c := gosmpp.NewConnection(conn)
+
+	_, err = c.WritePDU(pdu.NewBindRequest(pdu.Transceiver))
+	require.Nil(t, err)
+	p, err := pdu.Parse(c)
+	require.Nil(t, err)
+	require.Equal(t, "smsc", p.(*pdu.BindResp).SystemID)
+
+	_, err = c.WritePDU(pdu.NewUnbind())
+	require.Nil(t, err)
+	p, err = pdu.Parse(c)
+	require.Nil(t, err)
+	require.IsType(t, &pdu.UnbindResp{}, p)
+	require.Eventually(t, func() bool {
+		return len(srv.Sessions()) == 0
+	}, time.Second, time.Millisecond)
+
+	require.Nil(t, srv.Close())
+	require.ErrorIs(t, srv.ServeConn(conn), ErrServerClosed)
+}
+
+func TestServerInterfaceVersion(t *testing.T) {
+	bind := func(t *testing.T, addr string, version byte) (*gosmpp.Connection, *pdu.BindResp) {
+		conn, err := net.Dial("tcp", addr)
+		require.Nil(t, err)
+		c := gosmpp.NewConnection(conn)
+		t.Cleanup(func() {
+			_ = c.Close()
+		})
+
+		req := pdu.NewBindRequest(pdu.Transceiver)
+		req.SystemID = "esme"
+		req.InterfaceVersion = version
+		_, err = c.WritePDU(req)
+		require.Nil(t, err)
+
+		resp, err := pdu.Parse(c)
+		require.Nil(t, err)
+		require.True(t, resp.IsOk())
+		return c, resp.(*pdu.BindResp)
+	}
+
+	versions := make(chan byte, 4)
+	addr := startServer(t, &Server{
+		SystemID: "smsc",
+		OnBound: func(s *Session) {
+			versions <- s.InterfaceVersion()
+		},
+		CongestionState: func() byte { return 42 },
+	})
+
+	t.Run("v3.4", func(t *testing.T) {
+		c, resp := bind(t, addr, data.SMPP_V34)
+		require.Equal(t, data.SMPP_V34, <-versions)
+		require.Equal(t, []byte{data.SMPP_V34}, resp.OptionalParameters[pdu.TagScInterfaceVersion].Data)
+		_, congested := resp.OptionalParameters[pdu.TagCongestionState]
+		require.False(t, congested)
+
+		broadcast := pdu.NewBroadcastSM()
+		_, err := c.WritePDU(broadcast)
+		require.Nil(t, err)
+
+		p, err := pdu.Parse(c)
+		require.Nil(t, err)
+		require.Equal(t, data.BROADCAST_SM_RESP, p.GetHeader().CommandID)
+		require.Equal(t, data.ESME_RINVCMDID, p.GetHeader().CommandStatus)
+	})
+
+	t.Run("v3.3", func(t *testing.T) {
+		_, resp := bind(t, addr, 0x33)
+		require.Equal(t, byte(0x33), <-versions)
+		_, ok := resp.OptionalParameters[pdu.TagScInterfaceVersion]
+		require.False(t, ok)
+	})
+
+	t.Run("v5.0", func(t *testing.T) {
+		c, resp := bind(t, addr, 0x51)
+		require.Equal(t, data.SMPP_V50, <-versions)
+		require.Equal(t, []byte{data.SMPP_V50}, resp.OptionalParameters[pdu.TagScInterfaceVersion].Data)
+		require.Equal(t, []byte{42}, resp.OptionalParameters[pdu.TagCongestionState].Data)
+
+		broadcast := pdu.NewBroadcastSM()
+		_, err := c.WritePDU(broadcast)
+		require.Nil(t, err)
+
+		p, err := pdu.Parse(c)
+		require.Nil(t, err)
+		require.Equal(t, data.BROADCAST_SM_RESP, p.GetHeader().CommandID)
+		require.True(t, p.IsOk())
+		require.Equal(t, []byte{42}, p.(*pdu.BroadcastSMResp).OptionalParameters[pdu.TagCongestionState].Data)
+	})
+}
+
+func TestServerDecodeLimits(t *testing.T) {
+	srv := &Server{DecodeLimits: pdu.DecodeLimits{MaxTLVs: 2}}
+	addr := startServer(t, srv)
+	c := bindRaw(t, addr, "esme", pdu.Transceiver)
+
+	amplifying := pdu.NewSubmitSM()
+	for i := 0; i < 3; i++ {
+		amplifying.RegisterOptionalParam(pdu.Field{Tag: pdu.Tag(0x1400 + i), Data: []byte{1}})
+	}
+	_, err := c.WritePDU(amplifying)
+	require.Nil(t, err)
+
+	resp, err := pdu.Parse(c)
+	require.Nil(t, err)
+	require.True(t, resp.IsGNack())
+	require.Equal(t, amplifying.GetSequenceNumber(), resp.GetSequenceNumber())
+	require.Equal(t, data.ESME_RINVOPTPARSTREAM, resp.GetHeader().CommandStatus)
+
+	// session goes on
+	submit := pdu.NewSubmitSM()
+	submit.RegisterOptionalParam(pdu.Field{Tag: pdu.TagSarMsgRefNum, Data: []byte{0, 1}})
+	_, err = c.WritePDU(submit)
+	require.Nil(t, err)
+
+	resp, err = pdu.Parse(c)
+	require.Nil(t, err)
+	require.Equal(t, data.SUBMIT_SM_RESP, resp.GetHeader().CommandID)
+	require.True(t, resp.IsOk())
+}
+
+func TestServerPool(t *testing.T) {
+	var texts []string
+	srv := &Server{
+		Pool: pdu.NewPool(pdu.WithReleaseChecks()),
+		Handler: HandlerFunc(func(s *Session, p pdu.PDU) pdu.PDU {
+			if submit, ok := p.(*pdu.SubmitSM); ok {
+				text, _ := submit.Message.GetMessage()
+				texts = append(texts, text)
+			}
+			return p.GetResponse()
+		}),
+	}
+	addr := startServer(t, srv)
+	c := bindRaw(t, addr, "esme", pdu.Transceiver)
+
+	for _, text := range []string{"first", "second", "third"} {
+		submit := pdu.NewSubmitSM().(*pdu.SubmitSM)
+		require.Nil(t, submit.Message.SetMessageWithEncoding(text, data.GSM7BIT))
+		_, err := c.WritePDU(submit)
+		require.Nil(t, err)
+
+		resp, err := pdu.Parse(c)
+		require.Nil(t, err)
+		require.Equal(t, data.SUBMIT_SM_RESP, resp.GetHeader().CommandID)
+		require.Equal(t, submit.GetSequenceNumber(), resp.GetSequenceNumber())
+		require.True(t, resp.IsOk())
+	}
+	require.Equal(t, []string{"first", "second", "third"}, texts)
+}
+
+func TestServerMaxCommandLength(t *testing.T) {
+	sessions := make(chan *Session, 1)
+	srv := &Server{
+		DecodeLimits: pdu.DecodeLimits{MaxCommandLength: 100},
+		OnBound: func(s *Session) {
+			sessions <- s
+		},
+	}
+	addr := startServer(t, srv)
+	c := bindRaw(t, addr, "esme", pdu.Transceiver)
+
+	long := pdu.NewSubmitSM()
+	long.RegisterOptionalParam(pdu.Field{Tag: pdu.TagMessagePayload, Data: make([]byte, 100)})
+	_, err := c.WritePDU(long)
+	require.Nil(t, err)
+
+	resp, err := pdu.Parse(c)
+	require.Nil(t, err)
+	require.True(t, resp.IsGNack())
+	require.Equal(t, long.GetSequenceNumber(), resp.GetSequenceNumber())
+	require.Equal(t, data.ESME_RINVCMDLEN, resp.GetHeader().CommandStatus)
+
+	// larger deliver_sm is not written to client
+	deliver := pdu.NewDeliverSM()
+	deliver.RegisterOptionalParam(pdu.Field{Tag: pdu.TagMessagePayload, Data: make([]byte, 100)})
+	var sizeErr *pdu.SizeError
+	require.ErrorAs(t, (<-sessions).Submit(deliver), &sizeErr)
+
+	// session goes on
+	submit := pdu.NewSubmitSM()
+	_, err = c.WritePDU(submit)
+	require.Nil(t, err)
+
+	resp, err = pdu.Parse(c)
+	require.Nil(t, err)
+	require.Equal(t, data.SUBMIT_SM_RESP, resp.GetHeader().CommandID)
+	require.True(t, resp.IsOk())
+}
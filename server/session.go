@@ -0,0 +1,345 @@
+package server
+
+import (
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/linxGnu/gosmpp"
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+)
+
+var (
+	// ErrNotBound indicates client sent request before binding.
+	ErrNotBound = errors.New("smpp: client is not bound")
+
+	// ErrSessionClosed indicates session is closed.
+	ErrSessionClosed = errors.New("smpp: session is closed")
+
+	// ErrInvalidBindingType indicates PDU can not be sent to client of the binding type,
+	// e.g. deliver_sm to transmitter.
+	ErrInvalidBindingType = errors.New("smpp: pdu is not allowed for binding type")
+)
+
+// Session represents bound client.
+type Session struct {
+	srv  *Server
+	conn *gosmpp.Connection
+
+	systemID     string
+	systemType   string
+	bindingType  pdu.BindingType
+	addressRange pdu.AddressRange
+
+	interfaceVersion byte
+
+	quota   Quota
+	limiter *limiter
+	alive   keepalive
+
+	writeMu sync.Mutex
+	closed  int32 // session state, e.g. sessionOpen
+	done    chan struct{}
+	finish  sync.Once
+
+	pendingMu sync.Mutex
+	pending   map[int32]struct{} // sequence numbers of requests waiting for response
+
+	reasonMu sync.Mutex
+	reason   error
+}
+
+func newSession(srv *Server, conn *gosmpp.Connection) *Session {
+	return &Session{srv: srv, conn: conn, limiter: newLimiter(Limits{}), done: make(chan struct{})}
+}
+
+// SystemID returns system_id of bound client.
+func (s *Session) SystemID() string {
+	return s.systemID
+}
+
+// SystemType returns system_type of bound client.
+func (s *Session) SystemType() string {
+	return s.systemType
+}
+
+// BindingType returns binding type of client.
+func (s *Session) BindingType() pdu.BindingType {
+	return s.bindingType
+}
+
+// AddressRange returns address_range of bind request.
+func (s *Session) AddressRange() pdu.AddressRange {
+	return s.addressRange
+}
+
+// InterfaceVersion returns SMPP version negotiated with client, e.g. data.SMPP_V50.
+func (s *Session) InterfaceVersion() byte {
+	return s.interfaceVersion
+}
+
+// RemoteAddr returns address of client.
+func (s *Session) RemoteAddr() net.Addr {
+	return s.conn.RemoteAddr()
+}
+
+// Submit writes PDU (e.g. deliver_sm) to client.
+//
+// Requests other than enquire_link, unbind and alert_notification are not allowed for transmitter.
+func (s *Session) Submit(p pdu.PDU) error {
+	if atomic.LoadInt32(&s.closed) != sessionOpen {
+		return ErrSessionClosed
+	}
+
+	if !isResponse(p) && s.bindingType == pdu.Transmitter {
+		switch p.GetHeader().CommandID {
+		case data.ENQUIRE_LINK, data.UNBIND, data.ALERT_NOTIFICATION:
+		default:
+			return ErrInvalidBindingType
+		}
+	}
+
+	return s.write(p)
+}
+
+// Close unbinds client then closes connection.
+func (s *Session) Close() error {
+	switch atomic.SwapInt32(&s.closed, sessionClosed) {
+	case sessionClosed:
+		return nil
+	case sessionUnbinding:
+		return s.conn.Close()
+	}
+
+	// client might not read anymore
+	timeout := s.srv.WriteTimeout
+	if timeout <= 0 {
+		timeout = DefaultUnbindTimeout
+	}
+	_ = s.writeWithin(pdu.NewUnbind(), timeout)
+
+	return s.conn.Close()
+}
+
+// Abort closes connection without unbinding client, similar to network failure.
+func (s *Session) Abort() error {
+	if atomic.SwapInt32(&s.closed, sessionClosed) == sessionClosed {
+		return nil
+	}
+	return s.conn.Close()
+}
+
+func (s *Session) write(p pdu.PDU) error {
+	return s.writeWithin(p, s.srv.WriteTimeout)
+}
+
+func (s *Session) writeWithin(p pdu.PDU, timeout time.Duration) error {
+	return s.outbound(p, func(p pdu.PDU) error {
+		return s.writeLocked(p, timeout)
+	})
+}
+
+func (s *Session) writeLocked(p pdu.PDU, timeout time.Duration) (err error) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	if timeout > 0 {
+		err = s.conn.SetWriteTimeout(timeout)
+	} else {
+		err = s.conn.SetWriteDeadline(time.Time{})
+	}
+	if err != nil {
+		return
+	}
+
+	if isResponse(p) && s.srv.CongestionState != nil && s.interfaceVersion >= data.SMPP_V50 {
+		p.RegisterOptionalParam(pdu.Field{Tag: pdu.TagCongestionState, Data: []byte{s.srv.CongestionState()}})
+	}
+
+	_, err = s.conn.WritePDU(p)
+	s.countWritten(p)
+	if err == nil {
+		if isResponse(p) {
+			s.limiter.responded(p)
+		} else {
+			s.sent(p)
+		}
+	}
+	return
+}
+
+// respond writes response of p with given status, generic_nack if p has no response.
+func (s *Session) respond(p pdu.PDU, status data.CommandStatusType) error {
+	var resp pdu.PDU
+	if p.CanResponse() {
+		resp = p.GetResponse()
+	} else {
+		resp = pdu.NewGenericNack()
+		resp.SetSequenceNumber(p.GetSequenceNumber())
+	}
+
+	setStatus(resp, status)
+	return s.write(resp)
+}
+
+// loop reads PDUs from client until session is closed.
+func (s *Session) loop() error {
+	for {
+		if s.srv.ReadTimeout > 0 {
+			if err := s.conn.SetReadTimeout(s.srv.ReadTimeout); err != nil {
+				return s.closeWith(err)
+			}
+		} else if err := s.conn.SetReadDeadline(time.Time{}); err != nil {
+			return s.closeWith(err)
+		}
+
+		p, err := s.conn.ReadPDU()
+
+		// frame over the limits is skipped, client could go on
+		var limitErr *pdu.TLVLimitError
+		if errors.As(err, &limitErr) {
+			nack := pdu.NewGenericNack()
+			nack.SetSequenceNumber(limitErr.Header.SequenceNumber)
+			setStatus(nack, data.ESME_RINVOPTPARSTREAM)
+			if err = s.write(nack); err != nil {
+				return s.closeWith(err)
+			}
+			continue
+		}
+		var sizeErr *pdu.SizeError
+		if errors.As(err, &sizeErr) {
+			nack := pdu.NewGenericNack()
+			nack.SetSequenceNumber(sizeErr.Header.SequenceNumber)
+			setStatus(nack, data.ESME_RINVCMDLEN)
+			if err = s.write(nack); err != nil {
+				return s.closeWith(err)
+			}
+			continue
+		}
+		if err != nil {
+			if atomic.LoadInt32(&s.closed) == sessionClosed || errors.Is(err, io.EOF) {
+				return s.closeWith(s.closeReason())
+			}
+			return s.closeWith(err)
+		}
+
+		var done bool
+		s.inbound(p, func(p pdu.PDU) {
+			done = s.handle(p)
+		})
+		if s.srv.Pool != nil && s.srv.Store == nil {
+			s.srv.Pool.Release(p)
+		}
+		if done {
+			return s.closeWith(nil)
+		}
+	}
+}
+
+// handle processes PDU from client, returning true if session is done.
+func (s *Session) handle(p pdu.PDU) (done bool) {
+	switch p.(type) {
+	case *pdu.EnquireLink, *pdu.EnquireLinkResp:
+		s.alive.received(time.Now(), false)
+	default:
+		s.alive.received(time.Now(), true)
+	}
+	s.countReceived(p)
+	if isResponse(p) {
+		s.acknowledged(p)
+	}
+
+	switch pp := p.(type) {
+	case *pdu.EnquireLink:
+		_ = s.write(pp.GetResponse())
+		return
+
+	case *pdu.EnquireLinkResp:
+		s.alive.responded()
+		return
+
+	case *pdu.Unbind:
+		_ = s.write(pp.GetResponse())
+		return true
+
+	case *pdu.UnbindResp:
+		return true
+
+	case *pdu.BindRequest:
+		_ = s.respond(p, data.ESME_RALYBND)
+		return
+	}
+
+	if _, ok := p.(*pdu.BroadcastSM); ok && s.interfaceVersion < data.SMPP_V50 {
+		// broadcast_sm is introduced in SMPP 5.0
+		_ = s.respond(p, data.ESME_RINVCMDID)
+		return
+	}
+
+	if !isResponse(p) && s.bindingType == pdu.Receiver {
+		// receiver is not allowed to submit
+		_ = s.respond(p, data.ESME_RINVBNDSTS)
+		return
+	}
+
+	if !isResponse(p) && p.CanResponse() {
+		if !s.limiter.allow(time.Now()) {
+			if s.throttle(p) {
+				_ = s.Close()
+				return true
+			}
+			return
+		}
+		s.limiter.track(p)
+	}
+
+	if status := s.checkQuota(p); status != data.ESME_ROK {
+		_ = s.respond(p, status)
+		return
+	}
+
+	if s.srv.Store != nil {
+		if resp, ok := s.serveStored(p); ok {
+			_ = s.write(resp)
+			return
+		}
+	}
+
+	var resp pdu.PDU
+	if s.srv.Handler != nil {
+		resp = s.srv.Handler.HandlePDU(s, p)
+	} else if p.CanResponse() {
+		resp = p.GetResponse()
+	}
+
+	if resp != nil && s.srv.Store != nil {
+		resp = s.save(p, resp)
+	}
+
+	if resp != nil {
+		_ = s.write(resp)
+	}
+	return
+}
+
+func (s *Session) closeWith(err error) error {
+	if atomic.SwapInt32(&s.closed, sessionClosed) != sessionClosed {
+		_ = s.conn.Close()
+	}
+	return err
+}
+
+// end marks session daemon is stopped.
+func (s *Session) end() {
+	s.finish.Do(func() {
+		close(s.done)
+	})
+}
+
+func isResponse(p pdu.PDU) bool {
+	return p.GetHeader().CommandID < 0
+}
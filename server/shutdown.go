@@ -0,0 +1,128 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/linxGnu/gosmpp/pdu"
+)
+
+// session states
+const (
+	sessionOpen int32 = iota
+	sessionClosed
+	sessionUnbinding
+)
+
+// shutdownPollInterval is interval of checking pending deliveries on Shutdown.
+const shutdownPollInterval = 10 * time.Millisecond
+
+// Shutdown gracefully shuts down the server. It stops accepting connections, then for every session
+// waits for responses of pending requests (e.g. deliver_sm), sends unbind and waits for unbind_resp.
+//
+// If ctx is done before, remaining sessions are disconnected and ctx error is returned without waiting
+// for daemons. Clients binding meanwhile are rejected.
+func (srv *Server) Shutdown(ctx context.Context) error {
+	srv.mu.Lock()
+	srv.closed = true
+	for l := range srv.listeners {
+		_ = l.Close()
+	}
+	sessions := srv.registry.all()
+	srv.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, s := range sessions {
+		wg.Add(1)
+		go func(s *Session) {
+			defer wg.Done()
+			s.shutdown(ctx)
+		}(s)
+	}
+	wg.Wait()
+
+	done := make(chan struct{})
+	go func() {
+		srv.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+
+	case <-ctx.Done():
+		for _, s := range srv.Sessions() {
+			_ = s.Abort()
+		}
+		return ctx.Err()
+	}
+}
+
+// shutdown waits for pending deliveries, then unbinds client and waits for the session to end.
+// Session is aborted when ctx is done.
+func (s *Session) shutdown(ctx context.Context) {
+	ticker := time.NewTicker(shutdownPollInterval)
+	defer ticker.Stop()
+
+	for s.awaiting() > 0 {
+		select {
+		case <-s.done:
+			return
+
+		case <-ctx.Done():
+			_ = s.Abort()
+			return
+
+		case <-ticker.C:
+		}
+	}
+
+	if atomic.CompareAndSwapInt32(&s.closed, sessionOpen, sessionUnbinding) {
+		if err := s.write(pdu.NewUnbind()); err != nil {
+			_ = s.Abort()
+			return
+		}
+	}
+
+	select {
+	case <-s.done:
+	case <-ctx.Done():
+		_ = s.Abort()
+	}
+}
+
+// sent tracks request written to client until its response is received.
+func (s *Session) sent(p pdu.PDU) {
+	if isResponse(p) || !p.CanResponse() {
+		return
+	}
+
+	switch p.(type) {
+	case *pdu.EnquireLink, *pdu.Unbind:
+		return
+	}
+
+	s.pendingMu.Lock()
+	if s.pending == nil {
+		s.pending = make(map[int32]struct{})
+	}
+	s.pending[p.GetSequenceNumber()] = struct{}{}
+	s.pendingMu.Unlock()
+}
+
+// acknowledged stops tracking request of response p.
+func (s *Session) acknowledged(p pdu.PDU) {
+	s.pendingMu.Lock()
+	delete(s.pending, p.GetSequenceNumber())
+	s.pendingMu.Unlock()
+}
+
+// awaiting returns number of requests written to client and waiting for response.
+func (s *Session) awaiting() int {
+	s.pendingMu.Lock()
+	defer s.pendingMu.Unlock()
+	return len(s.pending)
+}
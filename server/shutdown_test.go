@@ -0,0 +1,69 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp/pdu"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerShutdown(t *testing.T) {
+	srv := &Server{}
+	addr := startServer(t, srv)
+	c := bindRaw(t, addr, "esme", pdu.Transceiver)
+
+	require.Nil(t, srv.Deliver("esme", pdu.NewDeliverSM()))
+	deliver, err := pdu.Parse(c)
+	require.Nil(t, err)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- srv.Shutdown(context.Background())
+	}()
+
+	// pending deliver_sm is waited for
+	time.Sleep(50 * time.Millisecond)
+	select {
+	case err := <-done:
+		t.Fatalf("shutdown returned before deliver_sm_resp: %v", err)
+	default:
+	}
+	_, err = c.WritePDU(deliver.GetResponse())
+	require.Nil(t, err)
+
+	unbind, err := pdu.Parse(c)
+	require.Nil(t, err)
+	require.IsType(t, &pdu.Unbind{}, unbind)
+
+	// session still serves requests until unbind_resp
+	_, err = c.WritePDU(pdu.NewEnquireLink())
+	require.Nil(t, err)
+	p, err := pdu.Parse(c)
+	require.Nil(t, err)
+	require.IsType(t, &pdu.EnquireLinkResp{}, p)
+
+	_, err = c.WritePDU(unbind.GetResponse())
+	require.Nil(t, err)
+	require.Nil(t, <-done)
+	require.Empty(t, srv.Sessions())
+}
+
+func TestServerShutdownDeadline(t *testing.T) {
+	srv := &Server{}
+	addr := startServer(t, srv)
+	c := bindRaw(t, addr, "esme", pdu.Transceiver)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, srv.Shutdown(ctx), context.DeadlineExceeded)
+
+	// client ignoring unbind is disconnected
+	p, err := pdu.Parse(c)
+	require.Nil(t, err)
+	require.IsType(t, &pdu.Unbind{}, p)
+	_, err = pdu.Parse(c)
+	require.NotNil(t, err)
+}
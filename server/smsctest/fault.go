@@ -0,0 +1,97 @@
+package smsctest
+
+import (
+	"sync"
+	"time"
+
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+)
+
+// Fault is misbehavior of the server on a received request. Zero Fault means normal handling.
+type Fault struct {
+	// Delay delays response.
+	Delay time.Duration
+
+	// Drop drops response, request is still accepted.
+	Drop bool
+
+	// Status responds with given command status, request is not accepted.
+	Status data.CommandStatusType
+
+	// Reset abruptly closes connection without response nor unbind.
+	Reset bool
+}
+
+// FaultFunc decides Fault for received request.
+//
+// It is called sequentially for requests of the same session, in receiving order.
+type FaultFunc func(p pdu.PDU) Fault
+
+// Delay delays all responses by d.
+func Delay(d time.Duration) FaultFunc {
+	return func(pdu.PDU) Fault {
+		return Fault{Delay: d}
+	}
+}
+
+// Drop drops given percentage of responses, evenly: Drop(50) drops every second response.
+func Drop(percent int) FaultFunc {
+	var (
+		mu sync.Mutex
+		n  int
+	)
+	return func(pdu.PDU) Fault {
+		mu.Lock()
+		defer mu.Unlock()
+
+		n++
+		return Fault{Drop: n*percent/100 > (n-1)*percent/100}
+	}
+}
+
+// RespondStatus responds all requests with status, e.g. data.ESME_RTHROTTLED or data.ESME_RMSGQFUL.
+func RespondStatus(status data.CommandStatusType) FaultFunc {
+	return func(pdu.PDU) Fault {
+		return Fault{Status: status}
+	}
+}
+
+// Reset closes connection on every request.
+func Reset() FaultFunc {
+	return func(pdu.PDU) Fault {
+		return Fault{Reset: true}
+	}
+}
+
+// Script applies faults to next requests one by one, then handles requests normally.
+func Script(faults ...Fault) FaultFunc {
+	var mu sync.Mutex
+	return func(pdu.PDU) (f Fault) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if len(faults) > 0 {
+			f, faults = faults[0], faults[1:]
+		}
+		return
+	}
+}
+
+// SetFault sets FaultFunc applied to received requests, nil handles requests normally.
+func (s *Server) SetFault(f FaultFunc) {
+	s.mu.Lock()
+	s.fault = f
+	s.mu.Unlock()
+}
+
+func (s *Server) faultOf(p pdu.PDU) Fault {
+	s.mu.Lock()
+	f := s.fault
+	s.mu.Unlock()
+
+	if f == nil {
+		return Fault{}
+	}
+	return f(p)
+}
@@ -0,0 +1,103 @@
+package smsctest
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp"
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+
+	"github.com/stretchr/testify/require"
+)
+
+func bindRaw(t *testing.T, addr string) *gosmpp.Connection {
+	conn, err := net.Dial("tcp", addr)
+	require.Nil(t, err)
+	c := gosmpp.NewConnection(conn)
+	t.Cleanup(func() {
+		_ = c.Close()
+	})
+
+	_, err = c.WritePDU(pdu.NewBindRequest(pdu.Transceiver))
+	require.Nil(t, err)
+	resp, err := pdu.Parse(c)
+	require.Nil(t, err)
+	require.True(t, resp.IsOk())
+	return c
+}
+
+func TestDrop(t *testing.T) {
+	drop := Drop(50)
+	var dropped []bool
+	for i := 0; i < 4; i++ {
+		dropped = append(dropped, drop(nil).Drop)
+	}
+	require.Equal(t, []bool{false, true, false, true}, dropped)
+
+	drop = Drop(25)
+	dropped = dropped[:0]
+	for i := 0; i < 8; i++ {
+		dropped = append(dropped, drop(nil).Drop)
+	}
+	require.Equal(t, []bool{false, false, false, true, false, false, false, true}, dropped)
+}
+
+func TestScript(t *testing.T) {
+	script := Script(Fault{Drop: true}, Fault{Status: data.ESME_RTHROTTLED})
+	require.Equal(t, Fault{Drop: true}, script(nil))
+	require.Equal(t, Fault{Status: data.ESME_RTHROTTLED}, script(nil))
+	require.Equal(t, Fault{}, script(nil))
+}
+
+func TestFaults(t *testing.T) {
+	smsc := NewServer(nil)
+	defer smsc.Close()
+
+	t.Run("status", func(t *testing.T) {
+		smsc.SetFault(Script(Fault{Status: data.ESME_RMSGQFUL}))
+		c := bindRaw(t, smsc.Addr)
+
+		for _, status := range []data.CommandStatusType{data.ESME_RMSGQFUL, data.ESME_ROK} {
+			_, err := c.WritePDU(pdu.NewSubmitSM())
+			require.Nil(t, err)
+			resp, err := pdu.Parse(c)
+			require.Nil(t, err)
+			require.Equal(t, status, resp.GetHeader().CommandStatus)
+		}
+		require.Len(t, smsc.Submitted(), 1)
+	})
+
+	t.Run("dropAndDelay", func(t *testing.T) {
+		smsc.SetFault(Script(Fault{Drop: true}, Fault{Delay: 100 * time.Millisecond}))
+		c := bindRaw(t, smsc.Addr)
+
+		dropped, delayed, normal := pdu.NewSubmitSM(), pdu.NewSubmitSM(), pdu.NewSubmitSM()
+		for _, p := range []pdu.PDU{dropped, delayed, normal} {
+			_, err := c.WritePDU(p)
+			require.Nil(t, err)
+		}
+
+		// delayed response comes after the next one
+		resp, err := pdu.Parse(c)
+		require.Nil(t, err)
+		require.Equal(t, normal.GetSequenceNumber(), resp.GetSequenceNumber())
+
+		resp, err = pdu.Parse(c)
+		require.Nil(t, err)
+		require.Equal(t, delayed.GetSequenceNumber(), resp.GetSequenceNumber())
+	})
+
+	t.Run("reset", func(t *testing.T) {
+		smsc.SetFault(Reset())
+		c := bindRaw(t, smsc.Addr)
+
+		_, err := c.WritePDU(pdu.NewSubmitSM())
+		require.Nil(t, err)
+		_, err = pdu.Parse(c)
+		require.Error(t, err)
+	})
+
+	smsc.SetFault(nil)
+}
@@ -0,0 +1,127 @@
+package smsctest
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+	"github.com/linxGnu/gosmpp/server"
+)
+
+// Receipts configures delivery receipts (DLR) of submitted messages.
+//
+// Receipt is sent as deliver_sm with esm_class of SMSC delivery receipt, receipted_message_id and message_state
+// TLVs and short message in the common "id:... stat:..." format. It is sent if registered_delivery of submit_sm/data_sm
+// requests SMSC delivery receipt, or receipt on failure and the final state is not delivered.
+type Receipts struct {
+	// Delay is duration after response to send receipt in.
+	Delay time.Duration
+
+	// State returns final message state (data.SM_STATE_*) of submitted request.
+	// All messages are delivered if nil.
+	State func(p pdu.PDU) byte
+}
+
+// SetReceipts enables delivery receipts, nil disables them.
+func (s *Server) SetReceipts(r *Receipts) {
+	s.mu.Lock()
+	s.receipts = r
+	s.mu.Unlock()
+}
+
+// receiptOf returns receipt of accepted request, nil if no receipt is requested.
+func (s *Server) receiptOf(p pdu.PDU, messageID string) (receipt pdu.PDU, delay time.Duration) {
+	s.mu.Lock()
+	r := s.receipts
+	s.mu.Unlock()
+
+	if r == nil {
+		return
+	}
+
+	var (
+		source, dest       pdu.Address
+		registeredDelivery byte
+		text               string
+	)
+	switch pp := p.(type) {
+	case *pdu.SubmitSM:
+		source, dest, registeredDelivery = pp.SourceAddr, pp.DestAddr, pp.RegisteredDelivery
+		text, _ = pp.Message.GetMessage()
+	case *pdu.DataSM:
+		source, dest, registeredDelivery = pp.SourceAddr, pp.DestAddr, pp.RegisteredDelivery
+	default:
+		return
+	}
+
+	state := byte(data.SM_STATE_DELIVERED)
+	if r.State != nil {
+		state = r.State(p)
+	}
+
+	switch registeredDelivery & data.SM_SMSC_RECEIPT_MASK {
+	case data.SM_SMSC_RECEIPT_REQUESTED:
+	case data.SM_SMSC_RECEIPT_ON_FAILURE:
+		if state == data.SM_STATE_DELIVERED {
+			return
+		}
+	default:
+		return
+	}
+
+	deliver := pdu.NewDeliverSM().(*pdu.DeliverSM)
+	deliver.SourceAddr = dest
+	deliver.DestAddr = source
+	deliver.EsmClass = data.SM_SMSC_DLV_RCPT_TYPE
+	_ = deliver.Message.SetMessageWithEncoding(receiptText(messageID, state, text, time.Now()), data.GSM7BIT)
+	deliver.RegisterOptionalParam(pdu.Field{Tag: pdu.TagReceiptedMessageID, Data: append([]byte(messageID), 0)})
+	deliver.RegisterOptionalParam(pdu.Field{Tag: pdu.TagMessageStateOption, Data: []byte{state}})
+
+	return deliver, r.Delay
+}
+
+// sendReceipt sends receipt to the submitting session, or to other receiver of the same system_id
+// if the submitting one is transmitter.
+func (s *Server) sendReceipt(session *server.Session, receipt pdu.PDU, delay time.Duration) {
+	time.AfterFunc(delay, func() {
+		if session.BindingType() != pdu.Transmitter && session.Submit(receipt) == nil {
+			return
+		}
+
+		_ = s.Config.Deliver(session.SystemID(), receipt)
+	})
+}
+
+var receiptStates = map[byte]string{
+	data.SM_STATE_EN_ROUTE:      "ENROUTE",
+	data.SM_STATE_DELIVERED:     "DELIVRD",
+	data.SM_STATE_EXPIRED:       "EXPIRED",
+	data.SM_STATE_DELETED:       "DELETED",
+	data.SM_STATE_UNDELIVERABLE: "UNDELIV",
+	data.SM_STATE_ACCEPTED:      "ACCEPTD",
+	data.SM_STATE_INVALID:       "UNKNOWN",
+	data.SM_STATE_REJECTED:      "REJECTD",
+}
+
+func receiptText(messageID string, state byte, text string, now time.Time) string {
+	stat, ok := receiptStates[state]
+	if !ok {
+		stat = "UNKNOWN"
+	}
+
+	delivered, errCode := 0, 0
+	if state == data.SM_STATE_DELIVERED {
+		delivered = 1
+	} else {
+		errCode = 1
+	}
+
+	if len(text) > 20 {
+		text = text[:20]
+	}
+
+	date := now.Format("0601021504")
+	return fmt.Sprintf("id:%s sub:001 dlvrd:%03d submit date:%s done date:%s stat:%s err:%03d text:%s",
+		messageID, delivered, date, date, stat, errCode, text)
+}
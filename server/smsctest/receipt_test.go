@@ -0,0 +1,74 @@
+package smsctest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReceiptText(t *testing.T) {
+	now := time.Date(2021, 3, 4, 5, 6, 0, 0, time.UTC)
+	require.Equal(t,
+		"id:12 sub:001 dlvrd:001 submit date:2103040506 done date:2103040506 stat:DELIVRD err:000 text:hello",
+		receiptText("12", data.SM_STATE_DELIVERED, "hello", now))
+	require.Equal(t,
+		"id:12 sub:001 dlvrd:000 submit date:2103040506 done date:2103040506 stat:UNDELIV err:001 text:01234567890123456789",
+		receiptText("12", data.SM_STATE_UNDELIVERABLE, "0123456789012345678901", now))
+}
+
+func TestReceipts(t *testing.T) {
+	smsc := NewServer(nil)
+	defer smsc.Close()
+
+	smsc.SetReceipts(&Receipts{
+		Delay: 50 * time.Millisecond,
+		State: func(p pdu.PDU) byte {
+			if p.(*pdu.SubmitSM).DestAddr.Address() == "404" {
+				return data.SM_STATE_UNDELIVERABLE
+			}
+			return data.SM_STATE_DELIVERED
+		},
+	})
+
+	c := bindRaw(t, smsc.Addr)
+
+	submit := func(dest string, registeredDelivery byte) string {
+		p := pdu.NewSubmitSM().(*pdu.SubmitSM)
+		_ = p.SourceAddr.SetAddress("111")
+		_ = p.DestAddr.SetAddress(dest)
+		_ = p.Message.SetMessageWithEncoding("hi", data.GSM7BIT)
+		p.RegisteredDelivery = registeredDelivery
+
+		_, err := c.WritePDU(p)
+		require.Nil(t, err)
+
+		resp, err := pdu.Parse(c)
+		require.Nil(t, err)
+		return resp.(*pdu.SubmitSMResp).MessageID
+	}
+
+	// delivered message does not get receipt on failure only
+	submit("200", data.SM_SMSC_RECEIPT_ON_FAILURE)
+	submit("200", data.SM_SMSC_RECEIPT_NOT_REQUESTED)
+	id := submit("404", data.SM_SMSC_RECEIPT_ON_FAILURE)
+
+	p, err := pdu.Parse(c)
+	require.Nil(t, err)
+	receipt := p.(*pdu.DeliverSM)
+	require.Equal(t, byte(data.SM_SMSC_DLV_RCPT_TYPE), receipt.EsmClass)
+	require.Equal(t, "404", receipt.SourceAddr.Address())
+	require.Equal(t, "111", receipt.DestAddr.Address())
+
+	receiptedID := receipt.OptionalParameters[pdu.TagReceiptedMessageID]
+	require.Equal(t, id, receiptedID.String())
+	require.Equal(t, []byte{data.SM_STATE_UNDELIVERABLE}, receipt.OptionalParameters[pdu.TagMessageStateOption].Data)
+
+	text, err := receipt.Message.GetMessage()
+	require.Nil(t, err)
+	require.Contains(t, text, "id:"+id+" ")
+	require.Contains(t, text, "stat:UNDELIV")
+}
@@ -0,0 +1,137 @@
+package smsctest
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+	"github.com/linxGnu/gosmpp/server"
+)
+
+// DefaultExpectTimeout is default duration expectations wait for PDUs to be received.
+const DefaultExpectTimeout = time.Second
+
+// Received returns all PDUs received from clients in receiving order,
+// except enquire_link and unbind handled by sessions.
+func (s *Server) Received() []pdu.PDU {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]pdu.PDU(nil), s.received...)
+}
+
+func (s *Server) record(p pdu.PDU) {
+	if s.DisableRecording {
+		return
+	}
+
+	s.mu.Lock()
+	s.received = append(s.received, p)
+	s.mu.Unlock()
+}
+
+// SubmitSM matches submit_sm to destination address with short message text.
+func SubmitSM(dest, text string) server.Matcher {
+	return func(p pdu.PDU) bool {
+		submit, ok := p.(*pdu.SubmitSM)
+		if !ok || submit.DestAddr.Address() != dest {
+			return false
+		}
+
+		message, err := submit.Message.GetMessage()
+		return err == nil && message == text
+	}
+}
+
+// ExpectSubmitSM waits for submit_sm to dest with text, failing t if it is not received within ExpectTimeout.
+func (s *Server) ExpectSubmitSM(t testing.TB, dest, text string) *pdu.SubmitSM {
+	t.Helper()
+
+	var found pdu.PDU
+	s.expect(t, fmt.Sprintf("submit_sm to %q with text %q", dest, text), func(received []pdu.PDU) bool {
+		found = first(received, SubmitSM(dest, text))
+		return found != nil
+	})
+
+	submit, _ := found.(*pdu.SubmitSM)
+	return submit
+}
+
+// ExpectReceived waits for exactly n received PDUs of command id, failing t otherwise.
+func (s *Server) ExpectReceived(t testing.TB, id data.CommandIDType, n int) {
+	t.Helper()
+
+	s.expect(t, fmt.Sprintf("%d of %s", n, id), func(received []pdu.PDU) bool {
+		return count(received, server.CommandID(id)) == n
+	})
+}
+
+// ExpectInOrder waits for PDUs matching given matchers to be received in the order, possibly
+// interleaved with other PDUs, failing t otherwise.
+func (s *Server) ExpectInOrder(t testing.TB, matchers ...server.Matcher) {
+	t.Helper()
+
+	s.expect(t, fmt.Sprintf("%d PDUs in order", len(matchers)), func(received []pdu.PDU) bool {
+		next := 0
+		for _, p := range received {
+			if next < len(matchers) && matchers[next](p) {
+				next++
+			}
+		}
+		return next == len(matchers)
+	})
+}
+
+// expect polls received PDUs until cond holds or ExpectTimeout passes.
+func (s *Server) expect(t testing.TB, expectation string, cond func([]pdu.PDU) bool) {
+	t.Helper()
+
+	timeout := s.ExpectTimeout
+	if timeout <= 0 {
+		timeout = DefaultExpectTimeout
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		received := s.Received()
+		if cond(received) {
+			return
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatalf("smsctest: expected %s, received %d PDUs: %s", expectation, len(received), describe(received))
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func first(received []pdu.PDU, m server.Matcher) pdu.PDU {
+	for _, p := range received {
+		if m(p) {
+			return p
+		}
+	}
+	return nil
+}
+
+func count(received []pdu.PDU, m server.Matcher) (n int) {
+	for _, p := range received {
+		if m(p) {
+			n++
+		}
+	}
+	return
+}
+
+func describe(received []pdu.PDU) string {
+	s := "["
+	for i, p := range received {
+		if i > 0 {
+			s += " "
+		}
+		s += p.GetHeader().CommandID.String()
+	}
+	return s + "]"
+}
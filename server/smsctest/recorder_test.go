@@ -0,0 +1,89 @@
+package smsctest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp"
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+
+	"github.com/stretchr/testify/require"
+)
+
+// failingTB records failures instead of failing the test.
+type failingTB struct {
+	testing.TB
+	failures []string
+}
+
+func (f *failingTB) Helper() {}
+
+func (f *failingTB) Fatalf(format string, args ...interface{}) {
+	f.failures = append(f.failures, fmt.Sprintf(format, args...))
+}
+
+func newTextSubmit(dest, text string) pdu.PDU {
+	p := pdu.NewSubmitSM().(*pdu.SubmitSM)
+	_ = p.DestAddr.SetAddress(dest)
+	_ = p.Message.SetMessageWithEncoding(text, data.GSM7BIT)
+	return p
+}
+
+func TestRecorder(t *testing.T) {
+	smsc := NewPipeServer(nil)
+	defer smsc.Close()
+	smsc.ExpectTimeout = 100 * time.Millisecond
+
+	session, err := gosmpp.NewSession(
+		gosmpp.TRXConnector(smsc.Dialer(), gosmpp.Auth{SystemID: "esme"}),
+		gosmpp.Settings{ReadTimeout: time.Second}, -1)
+	require.Nil(t, err)
+	defer func() {
+		_ = session.Close()
+	}()
+
+	for _, p := range []pdu.PDU{newTextSubmit("1", "first"), pdu.NewQuerySM(), newTextSubmit("2", "second")} {
+		require.Nil(t, session.Transceiver().Submit(p))
+	}
+
+	submit := smsc.ExpectSubmitSM(t, "2", "second")
+	require.Equal(t, "2", submit.DestAddr.Address())
+	smsc.ExpectReceived(t, data.SUBMIT_SM, 2)
+	smsc.ExpectReceived(t, data.QUERY_SM, 1)
+	smsc.ExpectInOrder(t, SubmitSM("1", "first"), SubmitSM("2", "second"))
+	require.Len(t, smsc.Received(), 3)
+
+	failing := &failingTB{TB: t}
+	smsc.ExpectSubmitSM(failing, "3", "third")
+	smsc.ExpectReceived(failing, data.SUBMIT_SM, 1)
+	smsc.ExpectInOrder(failing, SubmitSM("2", "second"), SubmitSM("1", "first"))
+	require.Len(t, failing.failures, 3)
+	require.Contains(t, failing.failures[0], `submit_sm to "3" with text "third"`)
+	require.Contains(t, failing.failures[0], "[SUBMIT_SM QUERY_SM SUBMIT_SM]")
+}
+
+func TestRecorderDisabled(t *testing.T) {
+	smsc := NewPipeServer(nil)
+	smsc.DisableRecording = true
+	defer smsc.Close()
+
+	session, err := gosmpp.NewSession(
+		gosmpp.TRXConnector(smsc.Dialer(), gosmpp.Auth{SystemID: "esme"}),
+		gosmpp.Settings{ReadTimeout: time.Second}, -1)
+	require.Nil(t, err)
+	defer func() {
+		_ = session.Close()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	resp, err := session.SubmitAsync(newTextSubmit("1", "first")).Wait(ctx)
+	require.Nil(t, err)
+	require.Equal(t, "1", resp.(*pdu.SubmitSMResp).MessageID)
+
+	require.Empty(t, smsc.Received())
+	require.Empty(t, smsc.Submitted())
+}
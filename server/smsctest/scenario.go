@@ -0,0 +1,191 @@
+package smsctest
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/linxGnu/gosmpp/clock"
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Step is a phase of Scenario, applying its Fault to received requests until the step ends.
+type Step struct {
+	Fault
+
+	// Command restricts the step to requests of the command id, e.g. data.SUBMIT_SM.
+	// Other requests are handled normally and not counted. Zero means all requests.
+	Command data.CommandIDType
+
+	// Requests ends the step after this number of requests.
+	Requests int
+
+	// Duration ends the step after this duration since the end of previous step.
+	//
+	// Step without Requests nor Duration lasts forever.
+	Duration time.Duration
+}
+
+// Requests returns step applying f to next n requests.
+func Requests(n int, f Fault) Step {
+	return Step{Fault: f, Requests: n}
+}
+
+// During returns step applying f to requests received within d.
+func During(d time.Duration, f Fault) Step {
+	return Step{Fault: f, Duration: d}
+}
+
+// Scenario is sequence of steps played against received requests, shared by all sessions.
+// Requests are handled normally after the last step.
+//
+// For example, first 100 submits are accepted, then clients are throttled for 10 seconds
+// and finally every request closes the connection:
+//
+//	smsc.Play(smsctest.Scenario{
+//		{Command: data.SUBMIT_SM, Requests: 100},
+//		smsctest.During(10*time.Second, smsctest.Fault{Status: data.ESME_RTHROTTLED}),
+//		{Fault: smsctest.Fault{Reset: true}},
+//	})
+type Scenario []Step
+
+// FaultFunc returns FaultFunc playing the scenario on clock c, real clock if nil.
+// The first step starts now.
+func (sc Scenario) FaultFunc(c clock.Clock) FaultFunc {
+	c = clock.OrReal(c)
+
+	var (
+		mu      sync.Mutex
+		steps   = append(Scenario(nil), sc...)
+		started = c.Now()
+		n       int
+	)
+	return func(p pdu.PDU) Fault {
+		mu.Lock()
+		defer mu.Unlock()
+
+		now := c.Now()
+		for len(steps) > 0 {
+			st := steps[0]
+			if st.Duration > 0 && !now.Before(started.Add(st.Duration)) {
+				steps, started, n = steps[1:], started.Add(st.Duration), 0
+				continue
+			}
+
+			if st.Command != 0 && p.GetHeader().CommandID != st.Command {
+				return Fault{}
+			}
+
+			if n++; st.Requests > 0 && n >= st.Requests {
+				steps, started, n = steps[1:], now, 0
+			}
+			return st.Fault
+		}
+		return Fault{}
+	}
+}
+
+// Play plays scenario against requests received from now, on Clock.
+func (s *Server) Play(sc Scenario) {
+	s.SetFault(sc.FaultFunc(s.Clock))
+}
+
+// yamlStep is Step in YAML, e.g.
+//
+//   - command: submit_sm
+//     requests: 100
+//   - duration: 10s
+//     status: ESME_RTHROTTLED
+//   - reset: true
+type yamlStep struct {
+	Command  string        `yaml:"command"`
+	Requests int           `yaml:"requests"`
+	Duration time.Duration `yaml:"duration"`
+	Delay    time.Duration `yaml:"delay"`
+	Drop     bool          `yaml:"drop"`
+	Status   string        `yaml:"status"`
+	Reset    bool          `yaml:"reset"`
+}
+
+// ParseScenario parses scenario from YAML document with list of steps under "steps" key:
+//
+//	steps:
+//	  - command: submit_sm   # all requests if omitted
+//	    requests: 100
+//	  - duration: 10s
+//	    status: ESME_RTHROTTLED   # or number, e.g. 0x58
+//	  - delay: 2s
+//	    requests: 5
+//	  - drop: true
+//	    requests: 1
+//	  - reset: true
+func ParseScenario(b []byte) (Scenario, error) {
+	var doc struct {
+		Steps []yamlStep `yaml:"steps"`
+	}
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return nil, fmt.Errorf("smsctest: invalid scenario: %w", err)
+	}
+
+	sc := make(Scenario, 0, len(doc.Steps))
+	for i, y := range doc.Steps {
+		st := Step{
+			Fault:    Fault{Delay: y.Delay, Drop: y.Drop, Reset: y.Reset},
+			Requests: y.Requests,
+			Duration: y.Duration,
+		}
+
+		var ok bool
+		if y.Command != "" {
+			if st.Command, ok = parseCommandID(y.Command); !ok {
+				return nil, fmt.Errorf("smsctest: step %d: unknown command %q", i+1, y.Command)
+			}
+		}
+		if y.Status != "" {
+			if st.Status, ok = parseCommandStatus(y.Status); !ok {
+				return nil, fmt.Errorf("smsctest: step %d: unknown status %q", i+1, y.Status)
+			}
+		}
+		sc = append(sc, st)
+	}
+	return sc, nil
+}
+
+// LoadScenario reads scenario from YAML file, see ParseScenario.
+func LoadScenario(path string) (Scenario, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseScenario(b)
+}
+
+// parseCommandID parses request command id by name, e.g. submit_sm.
+func parseCommandID(name string) (data.CommandIDType, bool) {
+	for id := data.CommandIDType(1); id <= data.BROADCAST_SM; id++ {
+		if strings.EqualFold(id.String(), name) {
+			return id, true
+		}
+	}
+	return 0, false
+}
+
+// parseCommandStatus parses command status by name, e.g. ESME_RTHROTTLED, or by number.
+func parseCommandStatus(s string) (data.CommandStatusType, bool) {
+	if v, err := strconv.ParseUint(s, 0, 32); err == nil {
+		return data.CommandStatusType(v), true
+	}
+
+	for status := data.CommandStatusType(0); status <= 0x1FF; status++ {
+		if strings.EqualFold(status.String(), s) {
+			return status, true
+		}
+	}
+	return 0, false
+}
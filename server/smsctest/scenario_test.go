@@ -0,0 +1,105 @@
+package smsctest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp/clock"
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScenario(t *testing.T) {
+	fake := clock.NewFake(time.Unix(0, 0))
+	throttled := Fault{Status: data.ESME_RTHROTTLED}
+	play := Scenario{
+		{Command: data.SUBMIT_SM, Requests: 2},
+		During(10*time.Second, throttled),
+		Requests(1, Fault{Drop: true}),
+		{Fault: Fault{Reset: true}},
+	}.FaultFunc(fake)
+
+	submit, query := pdu.NewSubmitSM(), pdu.NewQuerySM()
+
+	// other requests are not counted
+	require.Equal(t, Fault{}, play(submit))
+	require.Equal(t, Fault{}, play(query))
+	require.Equal(t, Fault{}, play(submit))
+
+	// throttled for 10s since the second submit
+	fake.Advance(time.Second)
+	require.Equal(t, throttled, play(query))
+	fake.Advance(8 * time.Second)
+	require.Equal(t, throttled, play(submit))
+	fake.Advance(time.Second)
+
+	require.Equal(t, Fault{Drop: true}, play(submit))
+	for i := 0; i < 3; i++ {
+		require.Equal(t, Fault{Reset: true}, play(submit))
+	}
+
+	// steps of elapsed durations are skipped
+	play = Scenario{During(time.Second, throttled), Requests(1, Fault{Drop: true})}.FaultFunc(fake)
+	fake.Advance(time.Hour)
+	require.Equal(t, Fault{Drop: true}, play(submit))
+	require.Equal(t, Fault{}, play(submit))
+}
+
+func TestParseScenario(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scenario.yaml")
+	require.Nil(t, os.WriteFile(path, []byte(`
+steps:
+  - command: submit_sm
+    requests: 100
+  - duration: 10s
+    status: ESME_RTHROTTLED
+  - delay: 1500ms
+    status: 0x14
+    requests: 2
+  - drop: true
+    requests: 1
+  - reset: true
+`), 0o600))
+
+	sc, err := LoadScenario(path)
+	require.Nil(t, err)
+	require.Equal(t, Scenario{
+		{Command: data.SUBMIT_SM, Requests: 100},
+		During(10*time.Second, Fault{Status: data.ESME_RTHROTTLED}),
+		Requests(2, Fault{Delay: 1500 * time.Millisecond, Status: data.ESME_RMSGQFUL}),
+		Requests(1, Fault{Drop: true}),
+		{Fault: Fault{Reset: true}},
+	}, sc)
+
+	_, err = ParseScenario([]byte("steps:\n  - command: submit_everything\n"))
+	require.ErrorContains(t, err, `step 1: unknown command "submit_everything"`)
+
+	_, err = ParseScenario([]byte("steps:\n  - status: ESME_RFAILED\n"))
+	require.ErrorContains(t, err, `unknown status "ESME_RFAILED"`)
+
+	_, err = ParseScenario([]byte("steps: 1"))
+	require.ErrorContains(t, err, "invalid scenario")
+}
+
+func TestPlay(t *testing.T) {
+	smsc := NewServer(nil)
+	defer smsc.Close()
+
+	smsc.Play(Scenario{Requests(1, Fault{}), Requests(1, Fault{Status: data.ESME_RTHROTTLED})})
+	c := bindRaw(t, smsc.Addr)
+
+	var statuses []data.CommandStatusType
+	for i := 0; i < 3; i++ {
+		_, err := c.WritePDU(pdu.NewSubmitSM())
+		require.Nil(t, err)
+
+		resp, err := pdu.Parse(c)
+		require.Nil(t, err)
+		statuses = append(statuses, resp.GetHeader().CommandStatus)
+	}
+	require.Equal(t, []data.CommandStatusType{data.ESME_ROK, data.ESME_RTHROTTLED, data.ESME_ROK}, statuses)
+}
@@ -0,0 +1,250 @@
+// Package smsctest provides in-memory SMSC for tests of SMPP clients, in the manner of net/http/httptest.
+//
+//	smsc := smsctest.NewServer(map[string]string{"esme": "secret"})
+//	defer smsc.Close()
+//
+//	session, err := gosmpp.NewSession(gosmpp.TRXConnector(gosmpp.NonTLSDialer, gosmpp.Auth{
+//		SMSC:     smsc.Addr,
+//		SystemID: "esme",
+//		Password: "secret",
+//	}), settings, -1)
+//
+// NewPipeServer and Dialer connect clients over net.Pipe, without any port.
+package smsctest
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/linxGnu/gosmpp"
+	"github.com/linxGnu/gosmpp/clock"
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+	"github.com/linxGnu/gosmpp/server"
+)
+
+// Server is SMSC listening on random port of loopback interface.
+//
+// It accepts binds matching Credentials, acknowledges submit_sm, data_sm and submit_multi
+// with generated message_id and records received PDUs for inspection and expectations.
+// Misbehavior could be injected with SetFault, delivery receipts are enabled with SetReceipts.
+type Server struct {
+	// Addr is address of the server, in form of host:port.
+	Addr string
+
+	// Credentials maps system_id to password of accepted binds. All binds are accepted if nil.
+	// It should not be modified after the server is started.
+	Credentials map[string]string
+
+	// Config may be changed after calling NewUnstartedServer and before Start.
+	Config *server.Server
+
+	// Listener of the server.
+	Listener net.Listener
+
+	// TLS is config of server started with StartTLS.
+	TLS *tls.Config
+
+	// ExpectTimeout is duration expectations wait for PDUs, default is DefaultExpectTimeout.
+	ExpectTimeout time.Duration
+
+	// Clock is used by scenarios started with Play, real clock if nil.
+	Clock clock.Clock
+
+	// DisableRecording stops keeping received PDUs, so memory of long running server does not grow, e.g. in soak
+	// tests. Received, Submitted and expectations see no PDUs then. It should not be modified after the server
+	// is started.
+	DisableRecording bool
+
+	mu        sync.Mutex
+	received  []pdu.PDU
+	submitted []pdu.PDU
+	messageID uint64
+	fault     FaultFunc
+	receipts  *Receipts
+
+	certificate *x509.Certificate
+
+	done chan error
+}
+
+// NewServer starts and returns new Server accepting given credentials.
+// The caller should call Close when finished, to shut it down.
+func NewServer(credentials map[string]string) *Server {
+	s := NewUnstartedServer(credentials)
+	s.Start()
+	return s
+}
+
+// NewUnstartedServer returns new Server but doesn't start it.
+//
+// After changing its configuration, the caller should call Start.
+func NewUnstartedServer(credentials map[string]string) *Server {
+	s := &Server{
+		Credentials: credentials,
+		Listener:    newLocalListener(),
+	}
+	s.Config = s.newConfig()
+	return s
+}
+
+func (s *Server) newConfig() *server.Server {
+	return &server.Server{
+		SystemID:      "smsctest",
+		Authenticator: server.AuthenticatorFunc(s.authenticate),
+		Handler:       server.HandlerFunc(s.handle),
+	}
+}
+
+// NewPipeServer returns Server not listening on any port, clients are connected with Dialer only.
+func NewPipeServer(credentials map[string]string) *Server {
+	s := &Server{Credentials: credentials}
+	s.Config = s.newConfig()
+	return s
+}
+
+// Start starts server from NewUnstartedServer.
+func (s *Server) Start() {
+	s.start(func() error {
+		return s.Config.Serve(s.Listener)
+	})
+}
+
+func (s *Server) start(serve func() error) {
+	if s.done != nil {
+		panic("smsctest: server already started")
+	}
+
+	s.Addr = s.Listener.Addr().String()
+	s.done = make(chan error, 1)
+	go func() {
+		s.done <- serve()
+	}()
+}
+
+// Close shuts down the server, unbinding all clients, and blocks until all sessions are closed.
+func (s *Server) Close() {
+	_ = s.Config.Close()
+	if s.done != nil {
+		<-s.done
+	}
+}
+
+// Dialer returns dialer connecting clients to the server over net.Pipe, without TCP.
+func (s *Server) Dialer() gosmpp.Dialer {
+	return s.Config.PipeDialer()
+}
+
+// Sessions returns currently bound clients.
+func (s *Server) Sessions() []*server.Session {
+	return s.Config.Sessions()
+}
+
+// Submitted returns submitted requests, in receiving order.
+func (s *Server) Submitted() []pdu.PDU {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]pdu.PDU(nil), s.submitted...)
+}
+
+func (s *Server) authenticate(info server.BindInfo) data.CommandStatusType {
+	if s.Credentials == nil {
+		return data.ESME_ROK
+	}
+
+	password, ok := s.Credentials[info.SystemID]
+	if !ok {
+		return data.ESME_RINVSYSID
+	}
+	if password != info.Password {
+		return data.ESME_RINVPASWD
+	}
+	return data.ESME_ROK
+}
+
+func (s *Server) handle(session *server.Session, p pdu.PDU) pdu.PDU {
+	s.record(p)
+
+	if !p.CanResponse() {
+		return nil
+	}
+
+	fault := s.faultOf(p)
+	if fault.Reset {
+		_ = session.Abort()
+		return nil
+	}
+
+	var (
+		resp    = p.GetResponse()
+		receipt pdu.PDU
+		delay   time.Duration
+	)
+	if fault.Status != data.ESME_ROK {
+		if h, ok := resp.(interface {
+			SetCommandStatus(data.CommandStatusType)
+		}); ok {
+			h.SetCommandStatus(fault.Status)
+		}
+	} else {
+		var messageID string
+		switch r := resp.(type) {
+		case *pdu.SubmitSMResp:
+			messageID = s.submit(p)
+			r.MessageID = messageID
+		case *pdu.DataSMResp:
+			messageID = s.submit(p)
+			r.MessageID = messageID
+		case *pdu.SubmitMultiResp:
+			r.MessageID = s.submit(p)
+		}
+
+		if messageID != "" {
+			receipt, delay = s.receiptOf(p, messageID)
+		}
+	}
+
+	// receipt is sent after response
+	respond := func() {
+		if !fault.Drop {
+			_ = session.Submit(resp)
+		}
+		if receipt != nil {
+			s.sendReceipt(session, receipt, delay)
+		}
+	}
+
+	if fault.Delay > 0 {
+		time.AfterFunc(fault.Delay, respond)
+	} else {
+		respond()
+	}
+	return nil
+}
+
+// submit keeps submitted request, returning its message_id.
+func (s *Server) submit(p pdu.PDU) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.DisableRecording {
+		s.submitted = append(s.submitted, p)
+	}
+	s.messageID++
+	return strconv.FormatUint(s.messageID, 10)
+}
+
+func newLocalListener() net.Listener {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		if l, err = net.Listen("tcp6", "[::1]:0"); err != nil {
+			panic(fmt.Sprintf("smsctest: failed to listen on a port: %v", err))
+		}
+	}
+	return l
+}
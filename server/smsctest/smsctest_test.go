@@ -0,0 +1,112 @@
+package smsctest
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp"
+	"github.com/linxGnu/gosmpp/pdu"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer(t *testing.T) {
+	smsc := NewServer(map[string]string{"esme": "secret"})
+	defer smsc.Close()
+
+	_, err := gosmpp.NewSession(
+		gosmpp.TXConnector(gosmpp.NonTLSDialer, gosmpp.Auth{SMSC: smsc.Addr, SystemID: "esme", Password: "wrong"}),
+		gosmpp.Settings{}, -1)
+	require.Error(t, err)
+
+	_, err = gosmpp.NewSession(
+		gosmpp.TXConnector(gosmpp.NonTLSDialer, gosmpp.Auth{SMSC: smsc.Addr, SystemID: "unknown", Password: "secret"}),
+		gosmpp.Settings{}, -1)
+	require.Error(t, err)
+
+	responses := make(chan pdu.PDU, 2)
+	session, err := gosmpp.NewSession(
+		gosmpp.TRXConnector(gosmpp.NonTLSDialer, gosmpp.Auth{SMSC: smsc.Addr, SystemID: "esme", Password: "secret"}),
+		gosmpp.Settings{
+			ReadTimeout: time.Second,
+			OnPDU: func(p pdu.PDU, _ bool) {
+				responses <- p
+			},
+		}, -1)
+	require.Nil(t, err)
+	defer func() {
+		_ = session.Close()
+	}()
+
+	require.Nil(t, session.Transceiver().Submit(pdu.NewSubmitSM()))
+	require.Nil(t, session.Transceiver().Submit(pdu.NewDataSM()))
+
+	var ids []string
+	for i := 0; i < 2; i++ {
+		select {
+		case p := <-responses:
+			switch r := p.(type) {
+			case *pdu.SubmitSMResp:
+				ids = append(ids, r.MessageID)
+			case *pdu.DataSMResp:
+				ids = append(ids, r.MessageID)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("response is not received")
+		}
+	}
+	require.ElementsMatch(t, []string{"1", "2"}, ids)
+
+	require.Len(t, smsc.Submitted(), 2)
+	require.Len(t, smsc.Sessions(), 1)
+}
+
+func TestUnstartedServer(t *testing.T) {
+	smsc := NewUnstartedServer(nil)
+	smsc.Config.SystemID = "custom"
+	smsc.Start()
+	defer smsc.Close()
+
+	conn, err := net.Dial("tcp", smsc.Addr)
+	require.Nil(t, err)
+	c := gosmpp.NewConnection(conn)
+	defer func() {
+		_ = c.Close()
+	}()
+
+	_, err = c.WritePDU(pdu.NewBindRequest(pdu.Transceiver))
+	require.Nil(t, err)
+	resp, err := pdu.Parse(c)
+	require.Nil(t, err)
+	require.Equal(t, "custom", resp.(*pdu.BindResp).SystemID)
+}
+
+func TestPipeServer(t *testing.T) {
+	smsc := NewPipeServer(map[string]string{"esme": "secret"})
+	defer smsc.Close()
+	require.Empty(t, smsc.Addr)
+
+	responses := make(chan pdu.PDU, 1)
+	session, err := gosmpp.NewSession(
+		gosmpp.TRXConnector(smsc.Dialer(), gosmpp.Auth{SystemID: "esme", Password: "secret"}),
+		gosmpp.Settings{
+			ReadTimeout: time.Second,
+			OnPDU: func(p pdu.PDU, _ bool) {
+				responses <- p
+			},
+		}, -1)
+	require.Nil(t, err)
+	defer func() {
+		_ = session.Close()
+	}()
+
+	require.Nil(t, session.Transceiver().Submit(pdu.NewSubmitSM()))
+	select {
+	case p := <-responses:
+		require.Equal(t, "1", p.(*pdu.SubmitSMResp).MessageID)
+	case <-time.After(time.Second):
+		t.Fatal("response is not received")
+	}
+	require.Len(t, smsc.Sessions(), 1)
+}
@@ -0,0 +1,103 @@
+package smsctest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"math/big"
+	"net"
+	"time"
+
+	"github.com/linxGnu/gosmpp"
+)
+
+// NewTLSServer starts and returns new Server serving over TLS with generated self-signed certificate.
+// The caller should call Close when finished, to shut it down.
+func NewTLSServer(credentials map[string]string) *Server {
+	s := NewUnstartedServer(credentials)
+	s.StartTLS()
+	return s
+}
+
+// StartTLS starts TLS on server from NewUnstartedServer.
+//
+// Certificate is generated if TLS has none. Set Config.RequireClientCert (and TLS.ClientCAs) for mutual TLS.
+func (s *Server) StartTLS() {
+	if s.TLS == nil {
+		s.TLS = &tls.Config{}
+	}
+	if len(s.TLS.Certificates) == 0 {
+		s.TLS.Certificates = []tls.Certificate{GenerateCertificate("smsctest")}
+	}
+
+	cert, err := x509.ParseCertificate(s.TLS.Certificates[0].Certificate[0])
+	if err != nil {
+		panic(fmt.Sprintf("smsctest: invalid certificate: %v", err))
+	}
+	s.certificate = cert
+
+	s.start(func() error {
+		return s.Config.ServeTLS(s.Listener, s.TLS)
+	})
+}
+
+// Certificate returns certificate of TLS server, nil if the server is not started with TLS.
+func (s *Server) Certificate() *x509.Certificate {
+	return s.certificate
+}
+
+// ClientTLSConfig returns TLS config of client trusting the server certificate.
+// Client certificates might be added for mutual TLS.
+func (s *Server) ClientTLSConfig() *tls.Config {
+	pool := x509.NewCertPool()
+	if s.certificate != nil {
+		pool.AddCert(s.certificate)
+	}
+	return &tls.Config{RootCAs: pool, ServerName: "127.0.0.1"}
+}
+
+// TLSDialer returns dialer connecting to the TLS server with ClientTLSConfig and given client certificates.
+func (s *Server) TLSDialer(certificates ...tls.Certificate) gosmpp.Dialer {
+	config := s.ClientTLSConfig()
+	config.Certificates = certificates
+	return gosmpp.TLSDialer(config)
+}
+
+// GenerateCertificate returns self-signed certificate of commonName, valid for loopback addresses.
+// It could be used as server or client certificate and as its own CA.
+func GenerateCertificate(commonName string) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		panic(fmt.Sprintf("smsctest: failed to generate key: %v", err))
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 64))
+	if err != nil {
+		panic(fmt.Sprintf("smsctest: failed to generate serial number: %v", err))
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback},
+		DNSNames:              []string{"localhost"},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		panic(fmt.Sprintf("smsctest: failed to create certificate: %v", err))
+	}
+
+	leaf, _ := x509.ParseCertificate(der)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}
+}
@@ -0,0 +1,60 @@
+package smsctest
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp"
+	"github.com/linxGnu/gosmpp/pdu"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTLSServer(t *testing.T) {
+	smsc := NewTLSServer(nil)
+	defer smsc.Close()
+	require.Equal(t, "smsctest", smsc.Certificate().Subject.CommonName)
+
+	session, err := gosmpp.NewSession(
+		gosmpp.TRXConnector(smsc.TLSDialer(), gosmpp.Auth{SMSC: smsc.Addr, SystemID: "esme"}),
+		gosmpp.Settings{ReadTimeout: time.Second}, -1)
+	require.Nil(t, err)
+	defer func() {
+		_ = session.Close()
+	}()
+
+	require.Nil(t, session.Transceiver().Submit(pdu.NewSubmitSM()))
+	smsc.ExpectReceived(t, pdu.NewSubmitSM().GetHeader().CommandID, 1)
+}
+
+func TestMutualTLSServer(t *testing.T) {
+	client := GenerateCertificate("esme")
+	pool := x509.NewCertPool()
+	pool.AddCert(client.Leaf)
+
+	smsc := NewUnstartedServer(nil)
+	smsc.Config.RequireClientCert = true
+	smsc.TLS = &tls.Config{ClientCAs: pool}
+	smsc.StartTLS()
+	defer smsc.Close()
+
+	// without client certificate
+	_, err := gosmpp.NewSession(
+		gosmpp.TXConnector(smsc.TLSDialer(), gosmpp.Auth{SMSC: smsc.Addr, SystemID: "esme"}),
+		gosmpp.Settings{ReadTimeout: time.Second}, -1)
+	require.Error(t, err)
+
+	// not trusted client certificate
+	_, err = gosmpp.NewSession(
+		gosmpp.TXConnector(smsc.TLSDialer(GenerateCertificate("other")), gosmpp.Auth{SMSC: smsc.Addr, SystemID: "esme"}),
+		gosmpp.Settings{ReadTimeout: time.Second}, -1)
+	require.Error(t, err)
+
+	session, err := gosmpp.NewSession(
+		gosmpp.TXConnector(smsc.TLSDialer(client), gosmpp.Auth{SMSC: smsc.Addr, SystemID: "esme"}),
+		gosmpp.Settings{ReadTimeout: time.Second}, -1)
+	require.Nil(t, err)
+	require.Nil(t, session.Close())
+}
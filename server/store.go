@@ -0,0 +1,293 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+)
+
+var (
+	// ErrMessageNotFound indicates there is no stored message of the id.
+	ErrMessageNotFound = errors.New("smpp: message not found")
+
+	// ErrMessageFinal indicates message is already in final state (delivered, expired, deleted, ...).
+	ErrMessageFinal = errors.New("smpp: message is in final state")
+)
+
+// Message is short message accepted by the server.
+type Message struct {
+	// ID is message_id assigned by MessageStore.
+	ID string
+
+	// SystemID is system_id of the client submitted the message.
+	SystemID string
+
+	// Request is accepted submit_sm, data_sm or submit_multi. It is updated by replace_sm.
+	Request pdu.PDU
+
+	// State is message state, e.g. data.SM_STATE_EN_ROUTE.
+	State byte
+
+	// ErrorCode is network specific error code of the final state.
+	ErrorCode byte
+
+	// SubmitDate is time the message is accepted.
+	SubmitDate time.Time
+
+	// DoneDate is time the message reached final state, zero until then.
+	DoneDate time.Time
+}
+
+// Final returns true if message state is final, i.e. it can not be replaced or cancelled anymore.
+func (m *Message) Final() bool {
+	switch m.State {
+	case data.SM_STATE_EN_ROUTE, data.SM_STATE_ACCEPTED:
+		return false
+	default:
+		return true
+	}
+}
+
+// MessageStore persists messages accepted by the server.
+//
+// When Server.Store is set, accepted submit_sm, data_sm and submit_multi are saved and their responses
+// carry message_id assigned by the store; query_sm, replace_sm and cancel_sm are served from the store
+// without reaching the Handler.
+type MessageStore interface {
+	// Save stores new message, returning it with assigned ID.
+	Save(ctx context.Context, m Message) (Message, error)
+
+	// Get returns message of id, ErrMessageNotFound if there is none.
+	Get(ctx context.Context, id string) (Message, error)
+
+	// Update atomically applies fn to message of id and stores the result, unless fn returns error.
+	Update(ctx context.Context, id string, fn func(m *Message) error) (Message, error)
+}
+
+// MemoryStore is in-memory MessageStore, assigning sequential message ids.
+type MemoryStore struct {
+	mu       sync.Mutex
+	last     uint64
+	messages map[string]Message
+}
+
+// NewMemoryStore returns empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{messages: make(map[string]Message)}
+}
+
+// Save implements MessageStore.
+func (st *MemoryStore) Save(_ context.Context, m Message) (Message, error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	st.last++
+	m.ID = strconv.FormatUint(st.last, 16)
+	st.messages[m.ID] = m
+	return m, nil
+}
+
+// Get implements MessageStore.
+func (st *MemoryStore) Get(_ context.Context, id string) (Message, error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	m, ok := st.messages[id]
+	if !ok {
+		return Message{}, ErrMessageNotFound
+	}
+	return m, nil
+}
+
+// Update implements MessageStore.
+func (st *MemoryStore) Update(_ context.Context, id string, fn func(m *Message) error) (Message, error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	m, ok := st.messages[id]
+	if !ok {
+		return Message{}, ErrMessageNotFound
+	}
+	if err := fn(&m); err != nil {
+		return Message{}, err
+	}
+	st.messages[id] = m
+	return m, nil
+}
+
+// Len returns number of stored messages.
+func (st *MemoryStore) Len() int {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	return len(st.messages)
+}
+
+// UpdateMessageState transits stored message to state, e.g. data.SM_STATE_DELIVERED once it is delivered.
+// It returns ErrMessageFinal if the message is already in final state.
+func (srv *Server) UpdateMessageState(ctx context.Context, id string, state, errorCode byte) (Message, error) {
+	if srv.Store == nil {
+		return Message{}, ErrMessageNotFound
+	}
+
+	return srv.Store.Update(ctx, id, func(m *Message) error {
+		if m.Final() {
+			return ErrMessageFinal
+		}
+		m.State, m.ErrorCode = state, errorCode
+		if m.Final() {
+			m.DoneDate = time.Now()
+		}
+		return nil
+	})
+}
+
+// serveStored responds to query_sm, replace_sm and cancel_sm from Store, returning false for other PDUs.
+func (s *Session) serveStored(p pdu.PDU) (resp pdu.PDU, ok bool) {
+	ctx := context.Background()
+
+	switch req := p.(type) {
+	case *pdu.QuerySM:
+		r := req.GetResponse().(*pdu.QuerySMResp)
+		r.MessageID = req.MessageID
+
+		m, err := s.storedMessage(ctx, req.MessageID)
+		if err != nil {
+			r.CommandStatus = statusOf(err, data.ESME_RQUERYFAIL)
+			return r, true
+		}
+
+		r.MessageState, r.ErrorCode = m.State, m.ErrorCode
+		if !m.DoneDate.IsZero() {
+			r.FinalDate = absoluteTime(m.DoneDate)
+		}
+		return r, true
+
+	case *pdu.ReplaceSM:
+		r := req.GetResponse()
+		if _, err := s.storedMessage(ctx, req.MessageID); err != nil {
+			setStatus(r, statusOf(err, data.ESME_RREPLACEFAIL))
+			return r, true
+		}
+
+		_, err := s.srv.Store.Update(ctx, req.MessageID, func(m *Message) error {
+			submit, ok := m.Request.(*pdu.SubmitSM)
+			if !ok || m.Final() {
+				return ErrMessageFinal
+			}
+
+			// replace_sm carries no data_coding, the original one is kept
+			replaced := *submit
+			d, _ := req.Message.GetMessageData()
+			if err := replaced.Message.SetMessageDataWithEncoding(d, submit.Message.Encoding()); err != nil {
+				return err
+			}
+			replaced.Message.SmDefaultMsgID = req.Message.SmDefaultMsgID
+			replaced.RegisteredDelivery = req.RegisteredDelivery
+			if req.ScheduleDeliveryTime != "" {
+				replaced.ScheduleDeliveryTime = req.ScheduleDeliveryTime
+			}
+			if req.ValidityPeriod != "" {
+				replaced.ValidityPeriod = req.ValidityPeriod
+			}
+			m.Request = &replaced
+			return nil
+		})
+		if err != nil {
+			setStatus(r, statusOf(err, data.ESME_RREPLACEFAIL))
+		}
+		return r, true
+
+	case *pdu.CancelSM:
+		r := req.GetResponse()
+		if _, err := s.storedMessage(ctx, req.MessageID); err != nil {
+			setStatus(r, statusOf(err, data.ESME_RCANCELFAIL))
+			return r, true
+		}
+
+		_, err := s.srv.Store.Update(ctx, req.MessageID, func(m *Message) error {
+			if m.Final() {
+				return ErrMessageFinal
+			}
+			m.State, m.DoneDate = data.SM_STATE_DELETED, time.Now()
+			return nil
+		})
+		if err != nil {
+			setStatus(r, statusOf(err, data.ESME_RCANCELFAIL))
+		}
+		return r, true
+	}
+	return nil, false
+}
+
+// storedMessage returns stored message of id submitted by the session account.
+func (s *Session) storedMessage(ctx context.Context, id string) (Message, error) {
+	if id == "" {
+		return Message{}, ErrMessageNotFound
+	}
+
+	m, err := s.srv.Store.Get(ctx, id)
+	if err == nil && m.SystemID != s.systemID {
+		// messages of other accounts are invisible
+		err = ErrMessageNotFound
+	}
+	return m, err
+}
+
+// save stores request accepted with resp, assigning message_id to resp.
+func (s *Session) save(req, resp pdu.PDU) pdu.PDU {
+	var messageID *string
+	switch r := resp.(type) {
+	case *pdu.SubmitSMResp:
+		messageID = &r.MessageID
+	case *pdu.DataSMResp:
+		messageID = &r.MessageID
+	case *pdu.SubmitMultiResp:
+		messageID = &r.MessageID
+	default:
+		return resp
+	}
+
+	if !resp.IsOk() {
+		return resp
+	}
+
+	m, err := s.srv.Store.Save(context.Background(), Message{
+		SystemID:   s.systemID,
+		Request:    req,
+		State:      data.SM_STATE_EN_ROUTE,
+		SubmitDate: time.Now(),
+	})
+	if err != nil {
+		setStatus(resp, data.ESME_RSYSERR)
+		return resp
+	}
+
+	*messageID = m.ID
+	return resp
+}
+
+func statusOf(err error, failed data.CommandStatusType) data.CommandStatusType {
+	if errors.Is(err, ErrMessageNotFound) {
+		return data.ESME_RINVMSGID
+	}
+	return failed
+}
+
+func setStatus(p pdu.PDU, status data.CommandStatusType) {
+	if h, ok := p.(interface {
+		SetCommandStatus(data.CommandStatusType)
+	}); ok {
+		h.SetCommandStatus(status)
+	}
+}
+
+// absoluteTime formats t in SMPP absolute time format with UTC offset.
+func absoluteTime(t time.Time) string {
+	t = t.UTC()
+	return t.Format("060102150405") + strconv.Itoa(t.Nanosecond()/int(100*time.Millisecond)) + "00+"
+}
@@ -0,0 +1,124 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/linxGnu/gosmpp"
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	st := NewMemoryStore()
+
+	_, err := st.Get(ctx, "1")
+	require.ErrorIs(t, err, ErrMessageNotFound)
+
+	m, err := st.Save(ctx, Message{SystemID: "esme", State: data.SM_STATE_EN_ROUTE})
+	require.Nil(t, err)
+	require.Equal(t, "1", m.ID)
+	require.False(t, m.Final())
+
+	m, err = st.Update(ctx, m.ID, func(m *Message) error {
+		m.State = data.SM_STATE_DELIVERED
+		return nil
+	})
+	require.Nil(t, err)
+	require.True(t, m.Final())
+
+	_, err = st.Update(ctx, m.ID, func(*Message) error { return ErrMessageFinal })
+	require.ErrorIs(t, err, ErrMessageFinal)
+
+	m, err = st.Get(ctx, m.ID)
+	require.Nil(t, err)
+	require.EqualValues(t, data.SM_STATE_DELIVERED, m.State)
+	require.Equal(t, 1, st.Len())
+}
+
+func TestServerStore(t *testing.T) {
+	st := NewMemoryStore()
+	srv := &Server{Store: st}
+	addr := startServer(t, srv)
+
+	c := bindRaw(t, addr, "esme", pdu.Transceiver)
+	request := func(c *gosmpp.Connection, p pdu.PDU) pdu.PDU {
+		_, err := c.WritePDU(p)
+		require.Nil(t, err)
+
+		resp, err := pdu.Parse(c)
+		require.Nil(t, err)
+		return resp
+	}
+	submit := func(text string) string {
+		p := pdu.NewSubmitSM().(*pdu.SubmitSM)
+		_ = p.Message.SetMessageWithEncoding(text, data.UCS2)
+		resp := request(c, p).(*pdu.SubmitSMResp)
+		require.True(t, resp.IsOk())
+		return resp.MessageID
+	}
+	query := func(id string) *pdu.QuerySMResp {
+		p := pdu.NewQuerySM().(*pdu.QuerySM)
+		p.MessageID = id
+		return request(c, p).(*pdu.QuerySMResp)
+	}
+	replace := func(id, text string) pdu.PDU {
+		p := pdu.NewReplaceSM().(*pdu.ReplaceSM)
+		p.MessageID = id
+		_ = p.Message.SetMessageDataWithEncoding([]byte(text), data.GSM7BIT)
+		return request(c, p)
+	}
+	cancel := func(id string) pdu.PDU {
+		p := pdu.NewCancelSM().(*pdu.CancelSM)
+		p.MessageID = id
+		return request(c, p)
+	}
+
+	id := submit("first")
+	require.NotEmpty(t, id)
+
+	resp := query(id)
+	require.True(t, resp.IsOk())
+	require.Equal(t, id, resp.MessageID)
+	require.EqualValues(t, data.SM_STATE_EN_ROUTE, resp.MessageState)
+	require.Empty(t, resp.FinalDate)
+
+	// replaced message keeps data_coding
+	require.True(t, replace(id, "\x00h\x00i").IsOk())
+	m, err := st.Get(context.Background(), id)
+	require.Nil(t, err)
+	text, err := m.Request.(*pdu.SubmitSM).Message.GetMessage()
+	require.Nil(t, err)
+	require.Equal(t, "hi", text)
+
+	// delivered message is final
+	_, err = srv.UpdateMessageState(context.Background(), id, data.SM_STATE_DELIVERED, 0)
+	require.Nil(t, err)
+	_, err = srv.UpdateMessageState(context.Background(), id, data.SM_STATE_EXPIRED, 0)
+	require.ErrorIs(t, err, ErrMessageFinal)
+
+	resp = query(id)
+	require.EqualValues(t, data.SM_STATE_DELIVERED, resp.MessageState)
+	require.Len(t, resp.FinalDate, 16)
+	require.Equal(t, data.ESME_RREPLACEFAIL, replace(id, "late").GetHeader().CommandStatus)
+	require.Equal(t, data.ESME_RCANCELFAIL, cancel(id).GetHeader().CommandStatus)
+
+	// pending message is cancelled
+	id = submit("second")
+	require.True(t, cancel(id).IsOk())
+	require.EqualValues(t, data.SM_STATE_DELETED, query(id).MessageState)
+
+	require.Equal(t, data.ESME_RINVMSGID, query("unknown").CommandStatus)
+	require.Equal(t, data.ESME_RINVMSGID, cancel("").GetHeader().CommandStatus)
+
+	// messages are visible to the account only
+	other := bindRaw(t, addr, "other", pdu.Transceiver)
+	p := pdu.NewQuerySM().(*pdu.QuerySM)
+	p.MessageID = id
+	require.Equal(t, data.ESME_RINVMSGID, request(other, p).GetHeader().CommandStatus)
+
+	require.Equal(t, 2, st.Len())
+}
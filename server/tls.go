@@ -0,0 +1,37 @@
+package server
+
+import (
+	"crypto/tls"
+	"net"
+)
+
+// ListenAndServeTLS listens on Addr and serves accepted connections over TLS.
+// If Addr is empty, ":3550" is used.
+func (srv *Server) ListenAndServeTLS(config *tls.Config) error {
+	addr := srv.Addr
+	if addr == "" {
+		addr = ":3550"
+	}
+
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return srv.ServeTLS(l, config)
+}
+
+// ServeTLS accepts connections on l and serves them over TLS with given config.
+//
+// If RequireClientCert is set, clients must present certificate, verified against config.ClientCAs if it is set.
+// Presented certificates are passed to Authenticator in BindInfo.
+func (srv *Server) ServeTLS(l net.Listener, config *tls.Config) error {
+	config = config.Clone()
+	if srv.RequireClientCert {
+		if config.ClientCAs != nil {
+			config.ClientAuth = tls.RequireAndVerifyClientCert
+		} else {
+			config.ClientAuth = tls.RequireAnyClientCert
+		}
+	}
+	return srv.Serve(tls.NewListener(l, config))
+}
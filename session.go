@@ -1,9 +1,12 @@
 package gosmpp
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"github.com/linxGnu/gosmpp/clock"
 	"github.com/linxGnu/gosmpp/pdu"
+	"sync"
 	"sync/atomic"
 	"time"
 )
@@ -29,6 +32,30 @@ type Session struct {
 	state        int32
 	rebinding    int32
 	requestStore RequestStore
+
+	counters sessionCounters
+	meter    meter
+	events   eventBus
+	pressure backpressure
+	calls    callRegistry
+	journal  journal
+	ordering *ordering
+	window   *submitWindow
+
+	// queueBudgets are charged by ordering, see WithQueueBudget
+	queueBudgets []*MemoryBudget
+
+	// responseTimeout is default response deadline of SubmitAsync
+	responseTimeout time.Duration
+
+	// labels are immutable after session creation
+	labels Labels
+
+	// submitGate holds Submit(s) while bind is being replaced, e.g. on credentials rotation.
+	submitGate sync.RWMutex
+
+	// closed is closed by Close, waking up retries of ordering.
+	closed chan struct{}
 }
 
 type SessionOption func(session *Session)
@@ -60,6 +87,7 @@ func NewSession(c Connector, settings Settings, rebindingInterval time.Duration,
 			return nil, ErrExpireCheckTimerNotSet
 		}
 	}
+	settings = settings.protected()
 
 	conn, err := c.Connect()
 	if err == nil {
@@ -68,39 +96,75 @@ func NewSession(c Connector, settings Settings, rebindingInterval time.Duration,
 			rebindingInterval: rebindingInterval,
 			originalOnClosed:  settings.OnClosed,
 			requestStore:      requestStore,
+			closed:            make(chan struct{}),
+			pressure: backpressure{
+				high: DefaultBackpressureHighWatermark,
+				low:  DefaultBackpressureLowWatermark,
+			},
 		}
+		session.events.clock = settings.Clock
+		session.events.onPanic = settings.OnHandlerPanic
+		session.events.subscribe(session.journal.onEvent)
 
 		for _, opt := range opts {
 			opt(session)
 		}
+		session.counters.labels = session.labels
+
+		newSettings := settings
+		newSettings.OnClosed = func(state State) {
+			session.events.publish(Event{Type: EventUnbound, State: state})
+			session.calls.failAll(ErrResponseLost)
+			session.window.releaseAll()
+			session.updatePressure()
 
-		if rebindingInterval > 0 {
-			newSettings := settings
-			newSettings.OnClosed = func(state State) {
-				switch state {
-				case ExplicitClosing:
-					return
-
-				default:
-					if session.originalOnClosed != nil {
-						session.originalOnClosed(state)
-					}
-					session.rebind()
+			if rebindingInterval <= 0 {
+				if session.originalOnClosed != nil {
+					session.originalOnClosed(state)
 				}
+				return
+			}
+
+			switch state {
+			case ExplicitClosing:
+				return
+
+			default:
+				if session.originalOnClosed != nil {
+					session.originalOnClosed(state)
+				}
+				session.rebind()
 			}
-			session.settings = newSettings
-		} else {
-			session.settings = settings
 		}
+		session.settings = newSettings
 
 		// bind to session
-		trans := newTransceivable(conn, session.settings, session.requestStore)
-		trans.start()
-		session.trx.Store(trans)
+		session.bind(conn)
 	}
 	return
 }
 
+// bind starts new transceivable over authenticated connection and attaches it to session.
+func (s *Session) bind(conn *Connection) {
+	// keep limits set by connector, e.g. of optional parameters
+	limits := conn.limits
+	limits.MaxCommandLength = s.settings.MaxCommandLength
+	conn.SetDecodeLimits(limits)
+	trans := newTransceivable(conn, s.settings, s.requestStore)
+	trans.stats.counters = &s.counters
+	trans.stats.meter = &s.meter
+	trans.stats.events = &s.events
+	trans.stats.calls = &s.calls
+	trans.stats.window = s.window
+	trans.stats.journal = &s.journal
+	trans.stats.onPressure = s.updatePressure
+	trans.start()
+	s.trx.Store(trans)
+
+	s.events.publish(Event{Type: EventBound})
+	s.updatePressure()
+}
+
 func WithRequestStore(store RequestStore) SessionOption {
 	return func(s *Session) {
 		s.requestStore = store
@@ -127,6 +191,58 @@ func (s *Session) Transceiver() Transceiver {
 	return s.bound()
 }
 
+// Submit a PDU through currently bound Transmitter/Transceiver.
+//
+// Unlike Transmitter().Submit, this call is held (not failed) while session is
+// replacing its bind in a controlled way, e.g. RotateCredentials.
+//
+// Submit is safe for concurrent use by any number of goroutines, callers need no locking of their own:
+//   - PDUs are queued to the single writer of the bind. Submit returns once the PDU is queued, errors of
+//     writing it are reported to OnSubmitError.
+//   - PDUs submitted by one goroutine are written in order of its Submit calls.
+//   - While the queue is full, waiting Submit calls are served first in, first out: no submitter starves
+//     and PDUs of different goroutines are written in order their Submit calls started waiting.
+//   - Submit never blocks on closed or lost bind, it returns ErrConnectionClosing instead.
+//
+// With WithSubmitWindow, Submit of request over the window waits for its slot or fails with ErrWindowsFull.
+//
+// Submitted PDU is marshaled by the writer: it must not be modified, nor submitted again, until written.
+func (s *Session) Submit(p pdu.PDU) error {
+	return s.submitContext(context.Background(), p)
+}
+
+// submitContext submits p like Submit, giving up waiting for slot of the window once ctx is done.
+func (s *Session) submitContext(ctx context.Context, p pdu.PDU) (err error) {
+	if err = s.window.acquire(ctx, p); err != nil {
+		if errors.Is(err, ErrWindowsFull) {
+			s.events.publish(Event{Type: EventWindowFull, PDU: p, Err: err})
+		}
+		return
+	}
+
+	if err = s.submit(p); err != nil {
+		s.window.release(p.GetSequenceNumber())
+	}
+	return
+}
+
+func (s *Session) submit(p pdu.PDU) error {
+	s.submitGate.RLock()
+	defer s.submitGate.RUnlock()
+
+	b := s.bound()
+	if b == nil {
+		return ErrConnectionClosing
+	}
+
+	atomic.AddInt32(&s.pressure.submitting, 1)
+	s.updatePressure()
+	err := b.Submit(p)
+	atomic.AddInt32(&s.pressure.submitting, -1)
+	s.updatePressure()
+	return err
+}
+
 func (s *Session) GetWindowSize() (int, error) {
 	if s.c.GetBindType() == pdu.Transmitter || s.c.GetBindType() == pdu.Transceiver {
 		size, err := s.bound().GetWindowSize()
@@ -141,7 +257,9 @@ func (s *Session) GetWindowSize() (int, error) {
 // Close session.
 func (s *Session) Close() (err error) {
 	if atomic.CompareAndSwapInt32(&s.state, Alive, Closed) {
+		close(s.closed)
 		err = s.close()
+		s.window.close()
 	}
 	return
 }
@@ -163,15 +281,16 @@ func (s *Session) rebind() {
 				if s.settings.OnRebindingError != nil {
 					s.settings.OnRebindingError(err)
 				}
-				time.Sleep(s.rebindingInterval)
+				clock.OrReal(s.settings.Clock).Sleep(s.rebindingInterval)
 			} else {
 				// bind to session
-				trans := newTransceivable(conn, s.settings, s.requestStore)
-				trans.start()
-				s.trx.Store(trans)
+				s.bind(conn)
+				s.counters.inc(&s.counters.rebinds, MetricRebinds)
+				s.events.publish(Event{Type: EventReconnected})
 
 				// reset rebinding state
 				atomic.StoreInt32(&s.rebinding, 0)
+				s.updatePressure()
 				if s.settings.OnRebind != nil {
 					s.settings.OnRebind()
 				}
@@ -4,6 +4,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/linxGnu/gosmpp/clock"
+
 	"github.com/stretchr/testify/require"
 )
 
@@ -112,3 +114,39 @@ func TestGetWindowSize(t *testing.T) {
 	err = s.Close()
 	require.Nil(t, err)
 }
+
+func TestSessionFakeClock(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clk := clock.NewFake(start)
+
+	s, err := NewSession(
+		TRXConnector(NonTLSDialer, nextAuth()),
+		Settings{
+			EnquireLink: time.Minute,
+			ReadTimeout: 2 * time.Minute,
+			Clock:       clk,
+		}, 2*time.Second)
+	require.Nil(t, err)
+	defer func() {
+		_ = s.Close()
+	}()
+
+	// enquire_link ticker is waiting on fake clock
+	clk.BlockUntil(1)
+	require.Zero(t, s.Stats().PDUsSent)
+
+	clk.Advance(time.Minute)
+	require.Eventually(t, func() bool {
+		return s.Stats().PDUsReceived > 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	require.EqualValues(t, 1, s.Stats().PDUsSent)
+	require.True(t, start.Add(time.Minute).Equal(s.LastActivity()))
+	require.True(t, s.Healthy())
+
+	// next enquire_link is sent only after another period of fake time
+	clk.Advance(time.Minute)
+	require.Eventually(t, func() bool {
+		return s.Stats().PDUsSent == 2
+	}, 5*time.Second, 10*time.Millisecond)
+}
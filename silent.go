@@ -0,0 +1,28 @@
+package gosmpp
+
+import (
+	"context"
+
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+)
+
+// SendSilent sends short message of type 0 ("ping"): handset acknowledges it without storing or displaying it,
+// so its delivery receipt tells whether the handset is reachable, e.g. before time-critical deliveries.
+//
+// SMSC delivery receipt is requested even if Messenger does not request receipts.
+func (m *Messenger) SendSilent(ctx context.Context, from, to string) (*MessageHandle, error) {
+	submit, err := m.newSubmit(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	submit.ProtocolID = data.PID_SHORT_MESSAGE_TYPE_0
+	if submit.RegisteredDelivery&data.SM_SMSC_RECEIPT_MASK == data.SM_SMSC_RECEIPT_NOT_REQUESTED {
+		submit.RegisteredDelivery |= data.SM_SMSC_RECEIPT_REQUESTED
+	}
+	if err = submit.Message.SetMessageWithEncoding("", data.GSM7BIT); err != nil {
+		return nil, err
+	}
+	return m.send(ctx, []*pdu.SubmitSM{submit})
+}
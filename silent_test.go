@@ -0,0 +1,38 @@
+package gosmpp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp/data"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessengerSendSilent(t *testing.T) {
+	auth := nextAuth()
+	s, err := NewSession(TXConnector(NonTLSDialer, auth), Settings{ReadTimeout: 2 * time.Second}, -1)
+	require.Nil(t, err)
+	defer func() {
+		_ = s.Close()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	h, err := NewMessenger(s).SendSilent(ctx, "MyBank", "+84901234567")
+	require.Nil(t, err)
+	require.Len(t, h.Parts, 1)
+	require.NotEmpty(t, h.MessageIDs[0])
+
+	p := h.Parts[0]
+	require.Equal(t, data.PID_SHORT_MESSAGE_TYPE_0, p.ProtocolID)
+	require.Equal(t, data.SM_SMSC_RECEIPT_REQUESTED, p.RegisteredDelivery)
+	b, _ := p.Message.GetMessageData()
+	require.Empty(t, b)
+
+	h, err = NewMessenger(s, WithRegisteredDelivery(data.SM_SMSC_RECEIPT_ON_FAILURE)).SendSilent(ctx, "MyBank", "+84901234567")
+	require.Nil(t, err)
+	require.Equal(t, data.SM_SMSC_RECEIPT_ON_FAILURE, h.Parts[0].RegisteredDelivery)
+}
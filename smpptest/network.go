@@ -0,0 +1,224 @@
+package smpptest
+
+import (
+	"errors"
+	"net"
+	"sync"
+
+	"github.com/linxGnu/gosmpp"
+	"github.com/linxGnu/gosmpp/pdu"
+)
+
+var (
+	// ErrNetworkClosed is returned dialing closed Network.
+	ErrNetworkClosed = errors.New("smpptest: network is closed")
+
+	// ErrNoClient is returned by Network.Deliver when no receiver or transceiver is bound.
+	ErrNoClient = errors.New("smpptest: no client bound to receive")
+)
+
+// Network is in-memory network of fake SMSC, connecting sessions over net.Pipe. Sessions dialing it by Dialer
+// are bound whatever their credentials, their requests are answered by Responder, Accept by default, and
+// enquire_link and unbind are answered as by SMSC.
+//
+// Network is safe for concurrent use.
+type Network struct {
+	mu       sync.Mutex
+	respond  Responder
+	dialErr  error
+	peers    []*peer
+	received []pdu.PDU
+	closed   bool
+	wg       sync.WaitGroup
+}
+
+// NewNetwork returns empty Network. The caller should call Close when finished.
+func NewNetwork() *Network {
+	return &Network{respond: Accept()}
+}
+
+// Dialer returns dialer connecting sessions to fake SMSC of the network.
+func (n *Network) Dialer() gosmpp.Dialer {
+	return func(string) (net.Conn, error) {
+		n.mu.Lock()
+		defer n.mu.Unlock()
+
+		if n.closed {
+			return nil, ErrNetworkClosed
+		}
+		if n.dialErr != nil {
+			return nil, n.dialErr
+		}
+
+		client, smsc := net.Pipe()
+		p := &peer{network: n, conn: gosmpp.NewConnection(smsc)}
+		n.peers = append(n.peers, p)
+
+		n.wg.Add(1)
+		go func() {
+			defer n.wg.Done()
+			p.serve()
+		}()
+		return client, nil
+	}
+}
+
+// SetResponder sets responder of requests submitted by sessions.
+func (n *Network) SetResponder(r Responder) {
+	n.mu.Lock()
+	n.respond = r
+	n.mu.Unlock()
+}
+
+// FailDials makes dialing fail with err, e.g. to test rebinding. Nil restores dialing.
+func (n *Network) FailDials(err error) {
+	n.mu.Lock()
+	n.dialErr = err
+	n.mu.Unlock()
+}
+
+// Cut drops all connections, as lost network does. Sessions rebind if rebinding is enabled.
+func (n *Network) Cut() {
+	n.mu.Lock()
+	peers := n.peers
+	n.peers = nil
+	n.mu.Unlock()
+
+	for _, p := range peers {
+		_ = p.conn.Close()
+	}
+}
+
+// Deliver sends p, e.g. deliver_sm, to the latest bound receiver or transceiver.
+func (n *Network) Deliver(p pdu.PDU) error {
+	n.mu.Lock()
+	var target *peer
+	for i := len(n.peers) - 1; i >= 0 && target == nil; i-- {
+		if n.peers[i].receives() {
+			target = n.peers[i]
+		}
+	}
+	n.mu.Unlock()
+
+	if target == nil {
+		return ErrNoClient
+	}
+	return target.write(p)
+}
+
+// Submitted returns requests received from sessions, except bind, enquire_link and unbind, in receiving order.
+func (n *Network) Submitted() (submitted []pdu.PDU) {
+	for _, p := range n.Received() {
+		if p.CanResponse() {
+			switch p.(type) {
+			case *pdu.BindRequest, *pdu.EnquireLink, *pdu.Unbind:
+			default:
+				submitted = append(submitted, p)
+			}
+		}
+	}
+	return
+}
+
+// Received returns all PDUs received from sessions, in receiving order.
+func (n *Network) Received() []pdu.PDU {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]pdu.PDU(nil), n.received...)
+}
+
+// Close drops all connections and refuses the following dials with ErrNetworkClosed.
+func (n *Network) Close() {
+	n.mu.Lock()
+	n.closed = true
+	n.mu.Unlock()
+
+	n.Cut()
+	n.wg.Wait()
+}
+
+func (n *Network) record(p pdu.PDU) Responder {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.received = append(n.received, p)
+	return n.respond
+}
+
+func (n *Network) remove(p *peer) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for i := range n.peers {
+		if n.peers[i] == p {
+			n.peers = append(n.peers[:i], n.peers[i+1:]...)
+			return
+		}
+	}
+}
+
+// peer is fake SMSC end of connection of a session.
+type peer struct {
+	network *Network
+	conn    *gosmpp.Connection
+
+	writeMu sync.Mutex
+
+	mu          sync.Mutex
+	bindingType pdu.BindingType
+	bound       bool
+}
+
+func (p *peer) serve() {
+	defer func() {
+		p.network.remove(p)
+		_ = p.conn.Close()
+	}()
+
+	for {
+		req, err := p.conn.ReadPDU()
+		if err != nil {
+			return
+		}
+		respond := p.network.record(req)
+
+		switch r := req.(type) {
+		case *pdu.BindRequest:
+			resp := pdu.NewBindResp(*r)
+			resp.SystemID = "smpptest"
+			p.mu.Lock()
+			p.bindingType, p.bound = r.BindingType, true
+			p.mu.Unlock()
+			err = p.write(resp)
+
+		case *pdu.EnquireLink:
+			err = p.write(r.GetResponse())
+
+		case *pdu.Unbind:
+			_ = p.write(r.GetResponse())
+			return
+
+		default:
+			if req.CanResponse() {
+				if resp := respond(req); resp != nil {
+					err = p.write(resp)
+				}
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// receives tells whether the client is bound to receive PDUs from SMSC.
+func (p *peer) receives() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.bound && p.bindingType != pdu.Transmitter
+}
+
+func (p *peer) write(pp pdu.PDU) error {
+	p.writeMu.Lock()
+	defer p.writeMu.Unlock()
+	_, err := p.conn.WritePDU(pp)
+	return err
+}
@@ -0,0 +1,86 @@
+package smpptest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp"
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNetwork(t *testing.T) {
+	network := NewNetwork()
+	defer network.Close()
+
+	messages := make(chan gosmpp.IncomingMessage, 1)
+	rebound := make(chan struct{}, 1)
+	s, err := gosmpp.NewSession(gosmpp.TRXConnector(network.Dialer(), gosmpp.Auth{SMSC: "fake", SystemID: "esme"}),
+		gosmpp.Settings{
+			ReadTimeout: time.Second,
+			OnMessage: func(m gosmpp.IncomingMessage) {
+				messages <- m
+			},
+			OnRebind: func() {
+				rebound <- struct{}{}
+			},
+		}, 10*time.Millisecond)
+	require.Nil(t, err)
+	defer func() {
+		_ = s.Close()
+	}()
+
+	messenger := gosmpp.NewMessenger(s)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	handle, err := messenger.SendText(ctx, "Shop", "84901234567", "hello")
+	require.Nil(t, err)
+	require.Equal(t, []string{"1"}, handle.MessageIDs)
+	require.Len(t, network.Submitted(), 1)
+	require.Equal(t, "84901234567", network.Submitted()[0].(*pdu.SubmitSM).DestAddr.Address())
+
+	network.SetResponder(RespondStatus(data.ESME_RINVDSTADR))
+	_, err = messenger.SendText(ctx, "Shop", "84901234567", "hello")
+	var submitErr *gosmpp.SubmitError
+	require.True(t, errors.As(err, &submitErr))
+	require.Equal(t, data.ESME_RINVDSTADR, submitErr.Status)
+
+	deliver := pdu.NewDeliverSM().(*pdu.DeliverSM)
+	_ = deliver.SourceAddr.SetAddress("84901234567")
+	_ = deliver.Message.SetMessageWithEncoding("reply", data.GSM7BIT)
+	require.Nil(t, network.Deliver(deliver))
+	select {
+	case m := <-messages:
+		require.Equal(t, "reply", m.Text)
+	case <-time.After(time.Second):
+		t.Fatal("deliver_sm is not received")
+	}
+	require.Eventually(t, func() bool {
+		received := network.Received()
+		return received[len(received)-1].GetHeader().CommandID == data.DELIVER_SM_RESP
+	}, time.Second, 10*time.Millisecond)
+
+	t.Run("cut", func(t *testing.T) {
+		network.FailDials(errors.New("unreachable"))
+		network.Cut()
+		require.ErrorIs(t, network.Deliver(deliver), ErrNoClient)
+		require.Eventually(t, func() bool { return !s.IsBound() }, time.Second, time.Millisecond)
+
+		network.FailDials(nil)
+		select {
+		case <-rebound:
+		case <-time.After(time.Second):
+			t.Fatal("session is not rebound")
+		}
+		require.Nil(t, network.Deliver(deliver))
+	})
+
+	network.Close()
+	_, err = network.Dialer()("fake")
+	require.ErrorIs(t, err, ErrNetworkClosed)
+}
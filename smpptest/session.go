@@ -0,0 +1,196 @@
+package smpptest
+
+import (
+	"sync"
+	"time"
+
+	"github.com/linxGnu/gosmpp"
+	"github.com/linxGnu/gosmpp/clock"
+	"github.com/linxGnu/gosmpp/pdu"
+)
+
+var (
+	_ gosmpp.Transceiver = (*Session)(nil)
+	_ gosmpp.Transmitter = (*Session)(nil)
+	_ gosmpp.Receiver    = (*Session)(nil)
+)
+
+// Session is in-memory fake of bound session. It is safe for concurrent use.
+//
+// Submitted PDUs are captured and answered by Responder, Accept by default. Responses and PDUs passed to
+// Deliver are handled by callbacks of the settings like by gosmpp.Session: OnAllPDU, or OnMessage,
+// OnDeliveryReceipt and OnPDU, requests being responded automatically. WindowedRequestTracking is not
+// supported.
+type Session struct {
+	systemID string
+	settings gosmpp.Settings
+
+	mu        sync.Mutex
+	respond   Responder
+	delay     time.Duration
+	submitErr error
+	submitted []pdu.PDU
+	responses []pdu.PDU
+	closed    bool
+}
+
+// NewSession returns fake session bound as systemID, handling PDUs by callbacks of settings.
+func NewSession(systemID string, settings gosmpp.Settings) *Session {
+	return &Session{
+		systemID: systemID,
+		settings: settings,
+		respond:  Accept(),
+	}
+}
+
+// SetResponder sets responder of submitted requests.
+func (s *Session) SetResponder(r Responder) {
+	s.mu.Lock()
+	s.respond = r
+	s.mu.Unlock()
+}
+
+// SetDelay delays responses by d of Clock of the settings.
+//
+// Without delay, response is handled before Submit returns, so callbacks must not wait for the submitter.
+func (s *Session) SetDelay(d time.Duration) {
+	s.mu.Lock()
+	s.delay = d
+	s.mu.Unlock()
+}
+
+// SetSubmitError makes Submit fail with err, nil restores submitting.
+func (s *Session) SetSubmitError(err error) {
+	s.mu.Lock()
+	s.submitErr = err
+	s.mu.Unlock()
+}
+
+// Submit captures p and answers it by the responder. It fails with gosmpp.ErrConnectionClosing once the
+// session is closed.
+func (s *Session) Submit(p pdu.PDU) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return gosmpp.ErrConnectionClosing
+	}
+	if err := s.submitErr; err != nil {
+		s.mu.Unlock()
+		return err
+	}
+	s.submitted = append(s.submitted, p)
+	respond, delay := s.respond, s.delay
+	s.mu.Unlock()
+
+	if !p.CanResponse() {
+		return nil
+	}
+	resp := respond(p)
+	if resp == nil {
+		return nil
+	}
+
+	if delay > 0 {
+		clock.OrReal(s.settings.Clock).AfterFunc(delay, func() {
+			if !s.isClosed() {
+				s.handle(resp)
+			}
+		})
+	} else {
+		s.handle(resp)
+	}
+	return nil
+}
+
+// Deliver passes p received from SMSC, e.g. deliver_sm, to the handlers. It returns response sent back to
+// SMSC, nil if p is not responded.
+func (s *Session) Deliver(p pdu.PDU) (resp pdu.PDU) {
+	return s.handle(p)
+}
+
+// Submitted returns submitted PDUs, in submitting order.
+func (s *Session) Submitted() []pdu.PDU {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]pdu.PDU(nil), s.submitted...)
+}
+
+// Responses returns responses sent back to SMSC for delivered PDUs, in order of sending.
+func (s *Session) Responses() []pdu.PDU {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]pdu.PDU(nil), s.responses...)
+}
+
+// SystemID returns system_id the session is bound as.
+func (s *Session) SystemID() string {
+	return s.systemID
+}
+
+// Close closes the session, calling OnClosed with gosmpp.ExplicitClosing.
+func (s *Session) Close() error {
+	s.mu.Lock()
+	closed := s.closed
+	s.closed = true
+	s.mu.Unlock()
+
+	if !closed && s.settings.OnClosed != nil {
+		s.settings.OnClosed(gosmpp.ExplicitClosing)
+	}
+	return nil
+}
+
+func (s *Session) isClosed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closed
+}
+
+// handle passes p to the handlers like gosmpp.Session does.
+func (s *Session) handle(p pdu.PDU) (resp pdu.PDU) {
+	if s.settings.OnAllPDU != nil {
+		var closeBind bool
+		resp, closeBind = s.settings.OnAllPDU(p)
+		s.responded(resp)
+		if closeBind {
+			_ = s.Close()
+		}
+		return
+	}
+
+	responded := p.CanResponse()
+	if responded {
+		resp = p.GetResponse()
+		s.responded(resp)
+	}
+
+	if s.settings.OnMessage != nil {
+		if m, ok := gosmpp.ParseIncomingMessage(p); ok {
+			m.ReceivedAt = clock.OrReal(s.settings.Clock).Now()
+			s.settings.OnMessage(m)
+			return
+		}
+	}
+
+	if s.settings.OnDeliveryReceipt != nil {
+		if deliver, ok := p.(*pdu.DeliverSM); ok {
+			if r, ok := gosmpp.ParseReceipt(deliver); ok {
+				s.settings.OnDeliveryReceipt(r)
+				return
+			}
+		}
+	}
+
+	if s.settings.OnPDU != nil {
+		s.settings.OnPDU(p, responded)
+	}
+	return
+}
+
+func (s *Session) responded(resp pdu.PDU) {
+	if resp != nil {
+		s.mu.Lock()
+		s.responses = append(s.responses, resp)
+		s.mu.Unlock()
+	}
+}
@@ -0,0 +1,123 @@
+package smpptest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp"
+	"github.com/linxGnu/gosmpp/clock"
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newSubmitSM(dest string) pdu.PDU {
+	p := pdu.NewSubmitSM().(*pdu.SubmitSM)
+	_ = p.DestAddr.SetAddress(dest)
+	return p
+}
+
+func TestSession(t *testing.T) {
+	var (
+		responses []pdu.PDU
+		messages  []gosmpp.IncomingMessage
+		closed    []gosmpp.State
+	)
+	fake := NewSession("esme", gosmpp.Settings{
+		OnPDU: func(p pdu.PDU, _ bool) {
+			responses = append(responses, p)
+		},
+		OnMessage: func(m gosmpp.IncomingMessage) {
+			messages = append(messages, m)
+		},
+		OnClosed: func(state gosmpp.State) {
+			closed = append(closed, state)
+		},
+	})
+	require.Equal(t, "esme", fake.SystemID())
+
+	first := newSubmitSM("111")
+	require.Nil(t, fake.Submit(first))
+	require.Len(t, responses, 1)
+	require.Equal(t, first.GetSequenceNumber(), responses[0].GetSequenceNumber())
+	require.Equal(t, "1", responses[0].(*pdu.SubmitSMResp).MessageID)
+
+	fake.SetResponder(Script(RespondStatus(data.ESME_RTHROTTLED), Ignore()))
+	require.Nil(t, fake.Submit(newSubmitSM("222")))
+	require.Nil(t, fake.Submit(newSubmitSM("333")))
+	require.Nil(t, fake.Submit(newSubmitSM("444")))
+	require.Len(t, responses, 2)
+	require.Equal(t, data.ESME_RTHROTTLED, responses[1].GetHeader().CommandStatus)
+	require.Len(t, fake.Submitted(), 4)
+	require.Equal(t, "444", fake.Submitted()[3].(*pdu.SubmitSM).DestAddr.Address())
+
+	failure := errors.New("failure")
+	fake.SetSubmitError(failure)
+	require.ErrorIs(t, fake.Submit(newSubmitSM("555")), failure)
+	fake.SetSubmitError(nil)
+
+	t.Run("deliver", func(t *testing.T) {
+		deliver := pdu.NewDeliverSM().(*pdu.DeliverSM)
+		_ = deliver.SourceAddr.SetAddress("84901234567")
+		_ = deliver.Message.SetMessageWithEncoding("hello", data.GSM7BIT)
+
+		resp := fake.Deliver(deliver)
+		require.Equal(t, data.DELIVER_SM_RESP, resp.GetHeader().CommandID)
+		require.Equal(t, []pdu.PDU{resp}, fake.Responses())
+		require.Len(t, messages, 1)
+		require.Equal(t, "hello", messages[0].Text)
+	})
+
+	t.Run("delay", func(t *testing.T) {
+		fc := clock.NewFake(time.Now())
+		received := make(chan pdu.PDU, 1)
+		fake := NewSession("esme", gosmpp.Settings{
+			Clock: fc,
+			OnPDU: func(p pdu.PDU, _ bool) {
+				received <- p
+			},
+		})
+		fake.SetDelay(time.Second)
+
+		require.Nil(t, fake.Submit(newSubmitSM("111")))
+		fc.BlockUntil(1)
+		select {
+		case <-received:
+			t.Fatal("response is not delayed")
+		default:
+		}
+
+		fc.Advance(time.Second)
+		select {
+		case p := <-received:
+			require.Equal(t, data.SUBMIT_SM_RESP, p.GetHeader().CommandID)
+		case <-time.After(time.Second):
+			t.Fatal("delayed response is not handled")
+		}
+	})
+
+	require.Nil(t, fake.Close())
+	require.Nil(t, fake.Close())
+	require.Equal(t, []gosmpp.State{gosmpp.ExplicitClosing}, closed)
+	require.ErrorIs(t, fake.Submit(newSubmitSM("666")), gosmpp.ErrConnectionClosing)
+}
+
+func TestSessionOnAllPDU(t *testing.T) {
+	fake := NewSession("esme", gosmpp.Settings{
+		OnAllPDU: func(p pdu.PDU) (pdu.PDU, bool) {
+			if _, ok := p.(*pdu.Unbind); ok {
+				return p.GetResponse(), true
+			}
+			return nil, false
+		},
+	})
+
+	require.Nil(t, fake.Deliver(pdu.NewDeliverSM()))
+	require.Empty(t, fake.Responses())
+
+	resp := fake.Deliver(pdu.NewUnbind())
+	require.Equal(t, data.UNBIND_RESP, resp.GetHeader().CommandID)
+	require.ErrorIs(t, fake.Submit(pdu.NewSubmitSM()), gosmpp.ErrConnectionClosing)
+}
@@ -0,0 +1,86 @@
+// Package smpptest provides test doubles for unit tests of applications built on gosmpp, without SMSC
+// simulator or real sockets.
+//
+// Session is in-memory fake of a bound session, implementing gosmpp.Transceiver, gosmpp.Transmitter and
+// gosmpp.Receiver: submitted PDUs are captured and answered by scripted Responder, PDUs from SMSC are fed
+// to the handlers of gosmpp.Settings with Deliver.
+//
+//	fake := smpptest.NewSession("esme", settings)
+//	fake.SetResponder(smpptest.RespondStatus(data.ESME_RTHROTTLED))
+//	app := NewApp(fake)
+//
+// Network is in-memory network for code using *gosmpp.Session itself, e.g. gosmpp.Messenger: sessions
+// dialing it are bound to fake SMSC answering with the same Responder.
+//
+//	network := smpptest.NewNetwork()
+//	defer network.Close()
+//
+//	session, err := gosmpp.NewSession(gosmpp.TRXConnector(network.Dialer(), auth), settings, -1)
+//
+// Both are driven by Clock of the settings, e.g. clock.Fake, for timing without real sleeps.
+package smpptest
+
+import (
+	"strconv"
+	"sync/atomic"
+
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+)
+
+// Responder scripts response of SMSC to request submitted by client. Nil response leaves the request
+// unanswered, e.g. to test response timeouts.
+//
+// Responder might be called concurrently.
+type Responder func(req pdu.PDU) (resp pdu.PDU)
+
+// Accept responds to every request with ESME_ROK, assigning message_id "1", "2", ... to submit_sm, data_sm
+// and submit_multi.
+func Accept() Responder {
+	var messageID uint64
+	return func(req pdu.PDU) pdu.PDU {
+		resp := req.GetResponse()
+		switch r := resp.(type) {
+		case *pdu.SubmitSMResp:
+			r.MessageID = strconv.FormatUint(atomic.AddUint64(&messageID, 1), 10)
+		case *pdu.DataSMResp:
+			r.MessageID = strconv.FormatUint(atomic.AddUint64(&messageID, 1), 10)
+		case *pdu.SubmitMultiResp:
+			r.MessageID = strconv.FormatUint(atomic.AddUint64(&messageID, 1), 10)
+		}
+		return resp
+	}
+}
+
+// RespondStatus responds to every request with given command status.
+func RespondStatus(status data.CommandStatusType) Responder {
+	return func(req pdu.PDU) pdu.PDU {
+		resp := req.GetResponse()
+		if h, ok := resp.(interface {
+			SetCommandStatus(data.CommandStatusType)
+		}); ok {
+			h.SetCommandStatus(status)
+		}
+		return resp
+	}
+}
+
+// Ignore never responds.
+func Ignore() Responder {
+	return func(pdu.PDU) pdu.PDU {
+		return nil
+	}
+}
+
+// Script responds to consecutive requests with given responders in turn, the last one answers all the
+// requests after.
+func Script(responders ...Responder) Responder {
+	var n int64
+	return func(req pdu.PDU) pdu.PDU {
+		i := int(atomic.AddInt64(&n, 1) - 1)
+		if i >= len(responders) {
+			i = len(responders) - 1
+		}
+		return responders[i](req)
+	}
+}
@@ -0,0 +1,285 @@
+package gosmpp
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/linxGnu/gosmpp/clock"
+	"github.com/linxGnu/gosmpp/pdu"
+)
+
+const (
+	// SnapshotPDUs is number of the latest PDUs kept for Session.Snapshot.
+	SnapshotPDUs = 64
+
+	// SnapshotEvents is number of the latest events kept for Session.Snapshot.
+	SnapshotEvents = 64
+)
+
+// Directions of SnapshotPDU.
+const (
+	DirectionSent     = "sent"
+	DirectionReceived = "received"
+)
+
+// Snapshot is diagnostic dump of a Session, meant to be attached to support tickets when a bind misbehaves.
+// It is encoded as JSON, ReadSnapshot decodes it back for analysis tooling.
+type Snapshot struct {
+	Time     time.Time        `json:"time"`
+	Settings SnapshotSettings `json:"settings"`
+	Stats    SessionStats     `json:"stats"`
+
+	// History are the latest session events, oldest first.
+	History []SnapshotEvent `json:"history"`
+
+	// Outstanding are requests sent by the current bind still waiting for response, oldest first.
+	Outstanding []SnapshotRequest `json:"outstanding"`
+
+	// PDUs are the latest PDUs sent and received across binds, oldest first.
+	PDUs []SnapshotPDU `json:"pdus"`
+}
+
+// SnapshotSettings is configuration of the session, without callbacks.
+type SnapshotSettings struct {
+	BindType          string        `json:"bind_type"`
+	ReadTimeout       time.Duration `json:"read_timeout_ns"`
+	WriteTimeout      time.Duration `json:"write_timeout_ns"`
+	EnquireLink       time.Duration `json:"enquire_link_ns"`
+	RebindingInterval time.Duration `json:"rebinding_interval_ns"`
+	ResponseTimeout   time.Duration `json:"response_timeout_ns,omitempty"`
+	ReceiveWorkers    int           `json:"receive_workers,omitempty"`
+	Validation        string        `json:"validation"`
+
+	// Window settings are set if WindowedRequestTracking is used.
+	MaxWindowSize    uint8         `json:"max_window_size,omitempty"`
+	PduExpireTimeOut time.Duration `json:"pdu_expire_timeout_ns,omitempty"`
+}
+
+// SnapshotEvent is session Event kept in Snapshot.
+type SnapshotEvent struct {
+	Time  time.Time `json:"time"`
+	Type  string    `json:"type"`
+	State string    `json:"state,omitempty"`
+	PDU   string    `json:"pdu,omitempty"`
+	Err   string    `json:"error,omitempty"`
+}
+
+// SnapshotRequest is request waiting for response.
+type SnapshotRequest struct {
+	SequenceNumber int32     `json:"sequence_number"`
+	Command        string    `json:"command,omitempty"`
+	SentAt         time.Time `json:"sent_at"`
+}
+
+// SnapshotPDU is PDU sent to or received from SMSC. Dump is single line print of the PDU, with password masked.
+type SnapshotPDU struct {
+	Time           time.Time `json:"time"`
+	Direction      string    `json:"direction"`
+	Command        string    `json:"command"`
+	SequenceNumber int32     `json:"sequence_number"`
+	Status         string    `json:"status"`
+	Dump           string    `json:"dump"`
+}
+
+// ReadSnapshot decodes Snapshot encoded as JSON, e.g. by SnapshotHandler.
+func ReadSnapshot(r io.Reader) (*Snapshot, error) {
+	var s Snapshot
+	if err := json.NewDecoder(r).Decode(&s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Snapshot returns diagnostic dump of the session: its settings, counters, the latest events and PDUs and
+// requests waiting for response.
+func (s *Session) Snapshot() *Snapshot {
+	snapshot := &Snapshot{
+		Time: clock.OrReal(s.settings.Clock).Now(),
+		Settings: SnapshotSettings{
+			BindType:          bindTypeName(s.c.GetBindType()),
+			ReadTimeout:       s.settings.ReadTimeout,
+			WriteTimeout:      s.settings.WriteTimeout,
+			EnquireLink:       s.settings.EnquireLink,
+			RebindingInterval: s.rebindingInterval,
+			ResponseTimeout:   s.responseTimeout,
+			ReceiveWorkers:    s.settings.ReceiveWorkers,
+			Validation:        s.settings.Validation.String(),
+		},
+		Stats: s.Stats(),
+	}
+	if w := s.settings.WindowedRequestTracking; w != nil {
+		snapshot.Settings.MaxWindowSize = w.MaxWindowSize
+		snapshot.Settings.PduExpireTimeOut = w.PduExpireTimeOut
+	}
+
+	pdus, events := s.journal.entries()
+	snapshot.History = make([]SnapshotEvent, 0, len(events))
+	for _, e := range events {
+		snapshot.History = append(snapshot.History, snapshotEvent(e))
+	}
+	snapshot.PDUs = make([]SnapshotPDU, 0, len(pdus))
+	for _, p := range pdus {
+		snapshot.PDUs = append(snapshot.PDUs, p.snapshot())
+	}
+	snapshot.Outstanding = s.outstanding(pdus)
+	return snapshot
+}
+
+// SnapshotHandler returns http.Handler serving session Snapshot as JSON.
+func (s *Session) SnapshotHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(s.Snapshot())
+	})
+}
+
+// outstanding returns requests of the current bind waiting for response. Their commands are looked up in the
+// latest PDUs, or in request store if WindowedRequestTracking is set.
+func (s *Session) outstanding(pdus []journalPDU) []SnapshotRequest {
+	requests := []SnapshotRequest{}
+
+	if s.settings.WindowedRequestTracking != nil {
+		ctx, cancelFunc := context.WithTimeout(context.Background(), s.settings.StoreAccessTimeOut*time.Millisecond)
+		defer cancelFunc()
+		for _, r := range s.requestStore.List(ctx) {
+			requests = append(requests, SnapshotRequest{
+				SequenceNumber: r.PDU.GetSequenceNumber(),
+				Command:        r.PDU.GetHeader().CommandID.String(),
+				SentAt:         r.TimeSent,
+			})
+		}
+	} else if b := s.bound(); b != nil {
+		commands := make(map[int32]string)
+		for _, p := range pdus {
+			if p.direction == DirectionSent {
+				commands[p.pdu.GetSequenceNumber()] = p.pdu.GetHeader().CommandID.String()
+			}
+		}
+
+		b.stats.inflight.each(func(seq int32, sentAt int64) {
+			requests = append(requests, SnapshotRequest{
+				SequenceNumber: seq,
+				Command:        commands[seq],
+				SentAt:         time.Unix(0, sentAt),
+			})
+		})
+	}
+
+	sort.Slice(requests, func(i, j int) bool {
+		return requests[i].SentAt.Before(requests[j].SentAt)
+	})
+	return requests
+}
+
+func snapshotEvent(e Event) SnapshotEvent {
+	se := SnapshotEvent{Time: e.Time, Type: e.Type.String()}
+	if e.Type == EventUnbound {
+		se.State = e.State.String()
+	}
+	if e.PDU != nil {
+		se.PDU = pdu.Sprint(e.PDU)
+	}
+	if e.Err != nil {
+		se.Err = e.Err.Error()
+	}
+	return se
+}
+
+func bindTypeName(t pdu.BindingType) string {
+	switch t {
+	case pdu.Receiver:
+		return "receiver"
+
+	case pdu.Transmitter:
+		return "transmitter"
+
+	case pdu.Transceiver:
+		return "transceiver"
+
+	default:
+		return ""
+	}
+}
+
+// journalPDU is PDU kept in journal. It is printed lazily, on Snapshot only.
+type journalPDU struct {
+	time      time.Time
+	direction string
+	pdu       pdu.PDU
+}
+
+func (p journalPDU) snapshot() SnapshotPDU {
+	h := p.pdu.GetHeader()
+	return SnapshotPDU{
+		Time:           p.time,
+		Direction:      p.direction,
+		Command:        h.CommandID.String(),
+		SequenceNumber: h.SequenceNumber,
+		Status:         h.CommandStatus.String(),
+		Dump:           pdu.Sprint(p.pdu),
+	}
+}
+
+// journal keeps the latest PDUs and events of the session in rings, across binds.
+//
+// All methods are safe to call on nil receiver.
+type journal struct {
+	mu     sync.Mutex
+	pdus   ring[journalPDU]
+	events ring[Event]
+}
+
+func (j *journal) onPDU(now time.Time, direction string, p pdu.PDU) {
+	if j == nil {
+		return
+	}
+	j.mu.Lock()
+	j.pdus.push(SnapshotPDUs, journalPDU{time: now, direction: direction, pdu: p})
+	j.mu.Unlock()
+}
+
+func (j *journal) onEvent(e Event) {
+	if j == nil {
+		return
+	}
+	e.Labels = nil
+	j.mu.Lock()
+	j.events.push(SnapshotEvents, e)
+	j.mu.Unlock()
+}
+
+// entries returns copies of kept PDUs and events, oldest first.
+func (j *journal) entries() ([]journalPDU, []Event) {
+	if j == nil {
+		return nil, nil
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.pdus.list(), j.events.list()
+}
+
+// ring is fixed size buffer overwriting its oldest items.
+type ring[T any] struct {
+	items []T
+	next  int
+}
+
+func (r *ring[T]) push(size int, item T) {
+	if len(r.items) < size {
+		r.items = append(r.items, item)
+		return
+	}
+	r.items[r.next] = item
+	r.next = (r.next + 1) % size
+}
+
+func (r *ring[T]) list() []T {
+	items := make([]T, 0, len(r.items))
+	items = append(items, r.items[r.next:]...)
+	return append(items, r.items[:r.next]...)
+}
@@ -0,0 +1,97 @@
+package gosmpp_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp"
+	"github.com/linxGnu/gosmpp/pdu"
+	"github.com/linxGnu/gosmpp/server/smsctest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionSnapshot(t *testing.T) {
+	smsc := smsctest.NewPipeServer(nil)
+	defer smsc.Close()
+
+	s, err := gosmpp.NewSession(gosmpp.TRXConnector(smsc.Dialer(), gosmpp.Auth{SMSC: "pipe", SystemID: "esme", Password: "secret"}),
+		gosmpp.Settings{
+			ReadTimeout: 2 * time.Second,
+			EnquireLink: time.Second,
+		}, time.Second)
+	require.Nil(t, err)
+	defer func() {
+		_ = s.Close()
+	}()
+
+	for i := 0; i < 40; i++ {
+		_, err = s.SubmitAsync(pdu.NewSubmitSM()).Wait(context.Background())
+		require.Nil(t, err)
+	}
+
+	smsc.SetFault(func(pdu.PDU) smsctest.Fault { return smsctest.Fault{Drop: true} })
+	dropped := pdu.NewSubmitSM()
+	require.Nil(t, s.Transceiver().Submit(dropped))
+	require.Eventually(t, func() bool { return len(smsc.Submitted()) == 41 }, time.Second, 10*time.Millisecond)
+
+	snapshot := s.Snapshot()
+	require.Equal(t, "transceiver", snapshot.Settings.BindType)
+	require.Equal(t, time.Second, snapshot.Settings.RebindingInterval)
+	require.EqualValues(t, 81, snapshot.Stats.PDUsSent+snapshot.Stats.PDUsReceived)
+
+	require.Len(t, snapshot.History, 1)
+	require.Equal(t, "Bound", snapshot.History[0].Type)
+
+	require.Len(t, snapshot.Outstanding, 1)
+	require.Equal(t, dropped.GetSequenceNumber(), snapshot.Outstanding[0].SequenceNumber)
+	require.Equal(t, "SUBMIT_SM", snapshot.Outstanding[0].Command)
+	require.False(t, snapshot.Outstanding[0].SentAt.After(snapshot.Time))
+
+	require.Len(t, snapshot.PDUs, gosmpp.SnapshotPDUs)
+	last := snapshot.PDUs[len(snapshot.PDUs)-1]
+	require.Equal(t, gosmpp.DirectionSent, last.Direction)
+	require.Equal(t, dropped.GetSequenceNumber(), last.SequenceNumber)
+	require.Equal(t, "SUBMIT_SM", last.Command)
+	require.Equal(t, "ESME_ROK", last.Status)
+	require.Contains(t, last.Dump, "submit_sm seq=")
+
+	t.Run("handler", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		s.SnapshotHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+		require.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+		decoded, err := gosmpp.ReadSnapshot(rec.Body)
+		require.Nil(t, err)
+		require.Equal(t, snapshot.Settings, decoded.Settings)
+		require.Equal(t, snapshot.Outstanding[0].SequenceNumber, decoded.Outstanding[0].SequenceNumber)
+		require.Len(t, decoded.PDUs, gosmpp.SnapshotPDUs)
+		require.Equal(t, last.Dump, decoded.PDUs[len(decoded.PDUs)-1].Dump)
+	})
+
+	t.Run("rebind", func(t *testing.T) {
+		for _, c := range smsc.Sessions() {
+			_ = c.Close()
+		}
+		require.Eventually(t, func() bool {
+			for _, e := range s.Snapshot().History {
+				if e.Type == "Reconnected" {
+					return true
+				}
+			}
+			return false
+		}, 5*time.Second, 10*time.Millisecond)
+
+		var types []string
+		for _, e := range s.Snapshot().History {
+			types = append(types, e.Type)
+			if e.Type == "Unbound" {
+				require.NotEmpty(t, e.State)
+			}
+		}
+		require.Subset(t, types, []string{"Bound", "Unbound", "Reconnected"})
+		require.Empty(t, s.Snapshot().Outstanding)
+	})
+}
@@ -0,0 +1,531 @@
+// Package soak runs long soak tests of sessions: sustained traffic in both directions against SMSC, e.g.
+// the simulator of smsctest, for hours, while goroutines, live heap and open file descriptors are sampled.
+// Run fails when they grow beyond limits, i.e. something leaks per message, per bind or per close.
+//
+//	func TestSoak(t *testing.T) {
+//		if testing.Short() {
+//			t.Skip("soak test")
+//		}
+//		h := &soak.Harness{Duration: 2 * time.Hour, Churn: time.Minute}
+//		report, err := h.Run(context.Background())
+//		if err != nil {
+//			t.Fatal(err)
+//		}
+//		t.Log(report)
+//	}
+package soak
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/linxGnu/gosmpp"
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+	"github.com/linxGnu/gosmpp/server/smsctest"
+)
+
+const (
+	// DefaultSessions is default number of sessions bound at once.
+	DefaultSessions = 2
+
+	// DefaultRate is default number of messages submitted per second by each session.
+	DefaultRate = 50
+
+	// DefaultWindow is default number of messages in flight per session.
+	DefaultWindow = 10
+
+	// DefaultSampleInterval is default interval of sampling resources.
+	DefaultSampleInterval = 10 * time.Second
+
+	// DefaultSettle is default time resources are waited for to be released after sessions are closed.
+	DefaultSettle = 5 * time.Second
+
+	// DefaultMaxGoroutineGrowth is default number of goroutines allowed to be added.
+	DefaultMaxGoroutineGrowth = 10
+
+	// DefaultMaxHeapGrowth is default number of bytes of live heap allowed to be added.
+	DefaultMaxHeapGrowth = 16 << 20
+
+	// DefaultMaxFDGrowth is default number of open file descriptors allowed to be added.
+	DefaultMaxFDGrowth = 8
+)
+
+// ErrNoDuration indicates Harness is run without Duration.
+var ErrNoDuration = errors.New("soak: no duration")
+
+// Harness binds sessions to SMSC and submits messages requesting delivery receipts, which SMSC sends back, for
+// Duration. Once Warmup is over, resources of the first sample are steady state: later samples must not grow
+// beyond it by more than the limits. After traffic, sessions are closed and resources must settle back to those
+// sampled before binding.
+type Harness struct {
+	// SMSC the sessions are bound to, new smsctest.NewPipeServer sending receipts without recording if nil.
+	// Sessions dial Addr of server listening on a port, its Dialer otherwise.
+	SMSC *smsctest.Server
+
+	// Settings of sessions. ReadTimeout is 5 seconds and EnquireLink 1 second if zero.
+	// OnDeliveryReceipt is called after the receipt is counted.
+	Settings gosmpp.Settings
+
+	// Sessions is number of sessions bound at once, DefaultSessions by default.
+	Sessions int
+
+	// Rate is number of messages submitted per second by each session, DefaultRate by default.
+	Rate int
+
+	// Window is number of messages in flight per session, DefaultWindow by default.
+	Window int
+
+	// Duration of the traffic, required.
+	Duration time.Duration
+
+	// Churn is interval of closing one of sessions, round robin, and binding new one in its place.
+	// Sessions are kept for whole Duration if 0.
+	Churn time.Duration
+
+	// SampleInterval is interval of sampling resources, DefaultSampleInterval by default.
+	SampleInterval time.Duration
+
+	// Warmup is time of traffic before steady state, one SampleInterval by default.
+	Warmup time.Duration
+
+	// Settle is time resources are waited for to be released after sessions are closed, DefaultSettle by default.
+	Settle time.Duration
+
+	// MaxGoroutineGrowth, MaxHeapGrowth and MaxFDGrowth are limits of growth of goroutines, bytes of live heap
+	// and open file descriptors, DefaultMaxGoroutineGrowth, DefaultMaxHeapGrowth and DefaultMaxFDGrowth
+	// by default.
+	MaxGoroutineGrowth int
+	MaxHeapGrowth      int64
+	MaxFDGrowth        int
+
+	// OnSample is called with every sample, e.g. to log progress of long run.
+	OnSample func(Sample)
+}
+
+// Sample is state of resources and traffic at a time.
+type Sample struct {
+	Time       time.Time
+	Goroutines int
+
+	// HeapAlloc is bytes of live heap, sampled after garbage collection.
+	HeapAlloc int64
+
+	// FDs is number of open file descriptors, -1 if unknown on the platform.
+	FDs int
+
+	// Submitted, Failed and Receipts are numbers of messages accepted by SMSC, failed and receipts received
+	// so far.
+	Submitted int
+	Failed    int
+	Receipts  int
+}
+
+// Report is result of Harness.Run.
+type Report struct {
+	// Elapsed is time of the traffic.
+	Elapsed time.Duration
+
+	// Submitted, Failed and Receipts are numbers of messages accepted by SMSC, failed and receipts received.
+	// Messages in flight on closed sessions fail.
+	Submitted int
+	Failed    int
+	Receipts  int
+
+	// Binds is number of sessions bound, BindErrors of binds failed.
+	Binds      int
+	BindErrors int
+
+	// Baseline is sampled before binding, Steady once Warmup is over and Final after sessions are closed.
+	Baseline Sample
+	Steady   Sample
+	Final    Sample
+
+	// Samples are all samples taken during the traffic.
+	Samples []Sample
+}
+
+// String returns the report in lines of text.
+func (r Report) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Elapsed:    %s\n", r.Elapsed.Round(time.Second))
+	fmt.Fprintf(&b, "Submitted:  %d messages, %d failed, %d receipts\n", r.Submitted, r.Failed, r.Receipts)
+	fmt.Fprintf(&b, "Binds:      %d, %d failed\n", r.Binds, r.BindErrors)
+	for _, s := range []struct {
+		name   string
+		sample Sample
+	}{{"Baseline", r.Baseline}, {"Steady", r.Steady}, {"Final", r.Final}} {
+		fmt.Fprintf(&b, "%-11s %d goroutines, %d bytes of heap, %d fds\n",
+			s.name+":", s.sample.Goroutines, s.sample.HeapAlloc, s.sample.FDs)
+	}
+	return b.String()
+}
+
+// Leak is growth of resource beyond its limit.
+type Leak struct {
+	// Resource is "goroutines", "heap" or "fds".
+	Resource string
+
+	// AfterClose tells the growth is of resources left after sessions are closed, over the baseline.
+	// Otherwise, it is growth during the traffic, over the steady state.
+	AfterClose bool
+
+	From, To, Limit int64
+}
+
+// LeakError is returned by Run when resources grow beyond limits.
+type LeakError struct {
+	Leaks []Leak
+
+	// Stacks are stacks of goroutines when goroutines leak, in format of pprof goroutine profile.
+	Stacks string
+}
+
+func (e *LeakError) Error() string {
+	leaks := make([]string, len(e.Leaks))
+	for i, l := range e.Leaks {
+		phase := "during traffic"
+		if l.AfterClose {
+			phase = "after close"
+		}
+		leaks[i] = fmt.Sprintf("%s grew from %d to %d %s (limit %d)", l.Resource, l.From, l.To, phase, l.Limit)
+	}
+	return "soak: leak: " + strings.Join(leaks, "; ")
+}
+
+// Run binds sessions and runs the traffic for Duration, or until ctx is done, then closes the sessions.
+// It returns *LeakError if resources grow beyond limits, other errors if Harness is misconfigured or
+// sessions could not be bound at start.
+func (h *Harness) Run(ctx context.Context) (report Report, err error) {
+	if h.Duration <= 0 {
+		return report, ErrNoDuration
+	}
+
+	smsc := h.SMSC
+	if smsc == nil {
+		smsc = smsctest.NewPipeServer(nil)
+		smsc.DisableRecording = true
+		smsc.SetReceipts(&smsctest.Receipts{})
+	}
+
+	report.Baseline = h.sample(&report)
+
+	r := &run{harness: h, smsc: smsc, slots: make([]*gosmpp.Session, or(h.Sessions, DefaultSessions))}
+	for i := range r.slots {
+		if r.slots[i], err = r.bind(i); err != nil {
+			r.close()
+			if h.SMSC == nil {
+				smsc.Close()
+			}
+			return report, err
+		}
+	}
+
+	r.traffic(ctx, &report)
+	r.close()
+	if h.SMSC == nil {
+		smsc.Close()
+	}
+
+	// resources are given time to be released, e.g. goroutines of closed sessions to return
+	final := h.sample(&report)
+	for deadline := time.Now().Add(orDuration(h.Settle, DefaultSettle)); time.Now().Before(deadline); {
+		if len(h.leaks(report.Baseline, final, true)) == 0 {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+		final = h.sample(&report)
+	}
+	report.Final = final
+	r.counts(&report)
+
+	var leaks []Leak
+	if !report.Steady.Time.IsZero() && len(report.Samples) > 0 {
+		leaks = h.leaks(report.Steady, report.Samples[len(report.Samples)-1], false)
+	}
+	leaks = append(leaks, h.leaks(report.Baseline, report.Final, true)...)
+	if len(leaks) > 0 {
+		leakErr := &LeakError{Leaks: leaks}
+		for _, l := range leaks {
+			if l.Resource == "goroutines" {
+				var b bytes.Buffer
+				_ = pprof.Lookup("goroutine").WriteTo(&b, 1)
+				leakErr.Stacks = b.String()
+				break
+			}
+		}
+		return report, leakErr
+	}
+	return report, nil
+}
+
+// leaks returns resources of sample to grown beyond limits since from.
+func (h *Harness) leaks(from, to Sample, afterClose bool) (leaks []Leak) {
+	check := func(resource string, from, to, limit int64) {
+		if to-from > limit {
+			leaks = append(leaks, Leak{Resource: resource, AfterClose: afterClose, From: from, To: to, Limit: limit})
+		}
+	}
+	check("goroutines", int64(from.Goroutines), int64(to.Goroutines), int64(or(h.MaxGoroutineGrowth, DefaultMaxGoroutineGrowth)))
+	check("heap", from.HeapAlloc, to.HeapAlloc, orInt64(h.MaxHeapGrowth, DefaultMaxHeapGrowth))
+	if from.FDs >= 0 && to.FDs >= 0 {
+		check("fds", int64(from.FDs), int64(to.FDs), int64(or(h.MaxFDGrowth, DefaultMaxFDGrowth)))
+	}
+	return
+}
+
+// sample samples resources after garbage collection.
+func (h *Harness) sample(report *Report) Sample {
+	runtime.GC()
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	s := Sample{
+		Time:       time.Now(),
+		Goroutines: runtime.NumGoroutine(),
+		HeapAlloc:  int64(mem.HeapAlloc),
+		FDs:        openFDs(),
+		Submitted:  report.Submitted,
+		Failed:     report.Failed,
+		Receipts:   report.Receipts,
+	}
+	if h.OnSample != nil {
+		h.OnSample(s)
+	}
+	return s
+}
+
+// openFDs returns number of open file descriptors of the process, -1 if unknown.
+func openFDs() int {
+	for _, dir := range []string{"/proc/self/fd", "/dev/fd"} {
+		if entries, err := os.ReadDir(dir); err == nil {
+			return len(entries)
+		}
+	}
+	return -1
+}
+
+// callTimeout is time submitted message is waited for to be responded.
+const callTimeout = 30 * time.Second
+
+// run is state of Harness.Run.
+type run struct {
+	harness *Harness
+	smsc    *smsctest.Server
+
+	mu         sync.Mutex
+	slots      []*gosmpp.Session
+	binds      int
+	bindErrors int
+	submitted  int
+	failed     int
+	receipts   int
+}
+
+// bind binds session of slot i.
+func (r *run) bind(i int) (*gosmpp.Session, error) {
+	settings := r.harness.Settings
+	if settings.ReadTimeout <= 0 {
+		settings.ReadTimeout = 5 * time.Second
+	}
+	if settings.EnquireLink <= 0 {
+		settings.EnquireLink = time.Second
+	}
+	onReceipt := settings.OnDeliveryReceipt
+	settings.OnDeliveryReceipt = func(receipt gosmpp.Receipt) {
+		r.mu.Lock()
+		r.receipts++
+		r.mu.Unlock()
+		if onReceipt != nil {
+			onReceipt(receipt)
+		}
+	}
+
+	dialer, addr := r.smsc.Dialer(), "pipe"
+	if r.smsc.Listener != nil {
+		dialer, addr = gosmpp.NonTLSDialer, r.smsc.Addr
+	}
+	s, err := gosmpp.NewSession(gosmpp.TRXConnector(dialer, gosmpp.Auth{SMSC: addr, SystemID: fmt.Sprint("soak", i)}), settings, -1)
+
+	r.mu.Lock()
+	if err != nil {
+		r.bindErrors++
+	} else {
+		r.binds++
+	}
+	r.mu.Unlock()
+	return s, err
+}
+
+// traffic submits messages by all slots and takes samples until Duration elapsed or ctx is done.
+func (r *run) traffic(ctx context.Context, report *Report) {
+	h := r.harness
+	ctx, cancel := context.WithTimeout(ctx, h.Duration)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for i := range r.slots {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			r.submit(ctx, i)
+		}(i)
+	}
+	if h.Churn > 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.churn(ctx)
+		}()
+	}
+
+	start := time.Now()
+	warm := start.Add(orDuration(h.Warmup, orDuration(h.SampleInterval, DefaultSampleInterval)))
+	ticker := time.NewTicker(orDuration(h.SampleInterval, DefaultSampleInterval))
+	defer ticker.Stop()
+
+loop:
+	for {
+		select {
+		case <-ticker.C:
+			r.counts(report)
+			s := h.sample(report)
+			report.Samples = append(report.Samples, s)
+			if report.Steady.Time.IsZero() && !s.Time.Before(warm) {
+				report.Steady = s
+			}
+		case <-ctx.Done():
+			break loop
+		}
+	}
+
+	wg.Wait()
+	report.Elapsed = time.Since(start)
+}
+
+// submit submits messages by session of slot i at Rate, keeping up to Window of them in flight.
+func (r *run) submit(ctx context.Context, i int) {
+	h := r.harness
+	calls := make(chan *gosmpp.Call, or(h.Window, DefaultWindow)-1)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for c := range calls {
+			// responses of calls on closed sessions are lost
+			_, err := c.Wait(context.Background())
+			r.mu.Lock()
+			if err != nil {
+				r.failed++
+			} else {
+				r.submitted++
+			}
+			r.mu.Unlock()
+		}
+	}()
+	defer func() {
+		close(calls)
+		<-done
+	}()
+
+	ticker := time.NewTicker(time.Second / time.Duration(or(h.Rate, DefaultRate)))
+	defer ticker.Stop()
+
+	for n := 0; ; n++ {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+
+		r.mu.Lock()
+		s := r.slots[i]
+		r.mu.Unlock()
+
+		submit := pdu.NewSubmitSM().(*pdu.SubmitSM)
+		_ = submit.SourceAddr.SetAddress("soak")
+		_ = submit.DestAddr.SetAddress(fmt.Sprint(n))
+		_ = submit.Message.SetMessageWithEncoding(fmt.Sprintf("soak %d of session %d", n, i), data.GSM7BIT)
+		submit.RegisteredDelivery = data.SM_SMSC_RECEIPT_REQUESTED
+
+		select {
+		case calls <- s.SubmitAsync(submit, gosmpp.WithCallTimeout(callTimeout)):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// churn replaces sessions of slots, round robin, every Churn.
+func (r *run) churn(ctx context.Context) {
+	ticker := time.NewTicker(r.harness.Churn)
+	defer ticker.Stop()
+
+	for i := 0; ; i = (i + 1) % len(r.slots) {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+
+		s, err := r.bind(i)
+		if err != nil {
+			continue
+		}
+		r.mu.Lock()
+		old := r.slots[i]
+		r.slots[i] = s
+		r.mu.Unlock()
+		_ = old.Close()
+	}
+}
+
+// close closes sessions of all slots. Sessions are closed out of lock, their handlers count receipts.
+func (r *run) close() {
+	r.mu.Lock()
+	slots := append([]*gosmpp.Session(nil), r.slots...)
+	r.mu.Unlock()
+
+	for _, s := range slots {
+		if s != nil {
+			_ = s.Close()
+		}
+	}
+}
+
+// counts copies traffic counters to report.
+func (r *run) counts(report *Report) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	report.Submitted, report.Failed, report.Receipts = r.submitted, r.failed, r.receipts
+	report.Binds, report.BindErrors = r.binds, r.bindErrors
+}
+
+func or(n, def int) int {
+	if n <= 0 {
+		return def
+	}
+	return n
+}
+
+func orInt64(n, def int64) int64 {
+	if n <= 0 {
+		return def
+	}
+	return n
+}
+
+func orDuration(d, def time.Duration) time.Duration {
+	if d <= 0 {
+		return def
+	}
+	return d
+}
@@ -0,0 +1,78 @@
+package soak
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// soakDuration runs TestSoak for hours, e.g. go test ./soak -run TestSoak -soak 2h -timeout 3h
+var soakDuration = flag.Duration("soak", 0, "duration of soak test, short run if 0")
+
+func TestSoak(t *testing.T) {
+	h := &Harness{
+		Duration:       time.Second,
+		Churn:          200 * time.Millisecond,
+		SampleInterval: 100 * time.Millisecond,
+		Settle:         2 * time.Second,
+	}
+	if *soakDuration > 0 {
+		h.Duration, h.Churn, h.SampleInterval = *soakDuration, time.Minute, DefaultSampleInterval
+		h.OnSample = func(s Sample) {
+			t.Logf("%s: %d goroutines, %d bytes of heap, %d fds, %d submitted, %d failed, %d receipts",
+				s.Time.Format(time.RFC3339), s.Goroutines, s.HeapAlloc, s.FDs, s.Submitted, s.Failed, s.Receipts)
+		}
+	}
+
+	report, err := h.Run(context.Background())
+	require.Nil(t, err, "%v\n%s", err, report)
+	t.Log(report)
+
+	require.Greater(t, report.Submitted, 0)
+	require.Greater(t, report.Receipts, 0)
+	if h.Churn < h.Duration {
+		require.Greater(t, report.Binds, DefaultSessions)
+	}
+	require.Zero(t, report.BindErrors)
+	require.False(t, report.Steady.Time.IsZero())
+	require.NotEmpty(t, report.Samples)
+}
+
+func TestSoakLeak(t *testing.T) {
+	stop := make(chan struct{})
+	defer close(stop)
+
+	h := &Harness{
+		Duration:       time.Second,
+		SampleInterval: 100 * time.Millisecond,
+		Settle:         200 * time.Millisecond,
+
+		// goroutines are leaked by every sample
+		OnSample: func(Sample) {
+			for i := 0; i < 5; i++ {
+				go func() {
+					<-stop
+				}()
+			}
+		},
+	}
+
+	_, err := h.Run(context.Background())
+	var leakErr *LeakError
+	require.True(t, errors.As(err, &leakErr))
+	require.Len(t, leakErr.Leaks, 2)
+	require.Equal(t, "goroutines", leakErr.Leaks[0].Resource)
+	require.False(t, leakErr.Leaks[0].AfterClose)
+	require.True(t, leakErr.Leaks[1].AfterClose)
+	require.Contains(t, leakErr.Stacks, "TestSoakLeak")
+	require.Contains(t, err.Error(), "goroutines grew from")
+}
+
+func TestSoakNoDuration(t *testing.T) {
+	_, err := (&Harness{}).Run(context.Background())
+	require.Equal(t, ErrNoDuration, err)
+}
@@ -0,0 +1,96 @@
+package gosmpp
+
+import (
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// sessionCounters are accumulated through the whole session lifetime, across rebinds.
+type sessionCounters struct {
+	pdusSent        int64
+	pdusReceived    int64
+	submitErrors    int64
+	receivingErrors int64
+	rebinds         int64
+	timeouts        int64
+
+	collector MetricsCollector
+	labels    Labels
+}
+
+// SessionStats is point-in-time statistics of a Session.
+type SessionStats struct {
+	SystemID           string        `json:"system_id"`
+	Labels             Labels        `json:"labels,omitempty"`
+	Bound              bool          `json:"bound"`
+	PDUsSent           int64         `json:"pdus_sent"`
+	PDUsReceived       int64         `json:"pdus_received"`
+	SubmitErrors       int64         `json:"submit_errors"`
+	ReceivingErrors    int64         `json:"receiving_errors"`
+	Rebinds            int64         `json:"rebinds"`
+	Timeouts           int64         `json:"timeouts"`
+	QueueDepth         int           `json:"queue_depth"`
+	Outstanding        int           `json:"outstanding"`
+	LastEnquireLinkRTT time.Duration `json:"last_enquire_link_rtt_ns"`
+	EnquireLinkRTTAvg  time.Duration `json:"enquire_link_rtt_avg_ns"`
+	LastActivity       time.Time     `json:"last_activity"`
+}
+
+// Stats returns current statistics of the session.
+func (s *Session) Stats() (st SessionStats) {
+	st = SessionStats{
+		Labels:             s.Labels(),
+		Bound:              s.IsBound(),
+		PDUsSent:           atomic.LoadInt64(&s.counters.pdusSent),
+		PDUsReceived:       atomic.LoadInt64(&s.counters.pdusReceived),
+		SubmitErrors:       atomic.LoadInt64(&s.counters.submitErrors),
+		ReceivingErrors:    atomic.LoadInt64(&s.counters.receivingErrors),
+		Rebinds:            atomic.LoadInt64(&s.counters.rebinds),
+		Timeouts:           atomic.LoadInt64(&s.counters.timeouts),
+		Outstanding:        s.OutstandingCount(),
+		LastEnquireLinkRTT: s.LastEnquireLinkRTT(),
+		EnquireLinkRTTAvg:  s.EnquireLinkRTTAverage(),
+		LastActivity:       s.LastActivity(),
+	}
+
+	if b := s.bound(); b != nil {
+		st.SystemID = b.SystemID()
+		if b.out != nil {
+			st.QueueDepth = len(b.out.input)
+		}
+	}
+	return
+}
+
+// expvarMu serializes PublishExpvar, so that expvar.Publish of a taken name does not panic.
+var expvarMu sync.Mutex
+
+// PublishExpvar publishes session statistics via expvar under given name.
+//
+// Name must be unique within the process, error is returned and nothing published if it is taken.
+func (s *Session) PublishExpvar(name string) (err error) {
+	expvarMu.Lock()
+	defer expvarMu.Unlock()
+
+	if expvar.Get(name) != nil {
+		return fmt.Errorf("expvar %q is already published", name)
+	}
+
+	expvar.Publish(name, expvar.Func(func() interface{} {
+		return s.Stats()
+	}))
+	return
+}
+
+// StatsHandler returns http.Handler serving session statistics as JSON.
+func (s *Session) StatsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(s.Stats())
+	})
+}
@@ -0,0 +1,82 @@
+package gosmpp
+
+import (
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionStats(t *testing.T) {
+	auth := nextAuth()
+	s, err := NewSession(
+		TRXConnector(NonTLSDialer, auth),
+		Settings{
+			ReadTimeout: 2 * time.Second,
+			EnquireLink: 200 * time.Millisecond,
+		}, 2*time.Second)
+	require.Nil(t, err)
+	defer func() {
+		_ = s.Close()
+	}()
+
+	require.Nil(t, s.Transceiver().Submit(newSubmitSM(auth.SystemID)))
+	time.Sleep(500 * time.Millisecond)
+
+	st := s.Stats()
+	require.True(t, st.Bound)
+	require.Equal(t, "MelroseLabsSMSC", st.SystemID)
+	require.GreaterOrEqual(t, st.PDUsSent, int64(2))
+	require.GreaterOrEqual(t, st.PDUsReceived, int64(2))
+	require.Zero(t, st.Rebinds)
+
+	s.rebind()
+	require.EqualValues(t, 1, s.Stats().Rebinds)
+	require.GreaterOrEqual(t, s.Stats().PDUsSent, st.PDUsSent)
+
+	t.Run("expvar", func(t *testing.T) {
+		require.Nil(t, s.PublishExpvar("gosmpp_test_session"))
+		require.Error(t, s.PublishExpvar("gosmpp_test_session"))
+
+		var decoded SessionStats
+		require.Nil(t, json.Unmarshal([]byte(expvar.Get("gosmpp_test_session").String()), &decoded))
+		require.Equal(t, "MelroseLabsSMSC", decoded.SystemID)
+	})
+
+	t.Run("handler", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		s.StatsHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+		require.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+		var decoded SessionStats
+		require.Nil(t, json.Unmarshal(rec.Body.Bytes(), &decoded))
+		require.True(t, decoded.Bound)
+	})
+}
+
+func TestPublishExpvarConcurrently(t *testing.T) {
+	s := &Session{}
+	name := fmt.Sprintf("gosmpp_test_concurrent_%d", time.Now().UnixNano())
+
+	var wg sync.WaitGroup
+	var published int32
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if s.PublishExpvar(name) == nil {
+				atomic.AddInt32(&published, 1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	require.EqualValues(t, 1, published)
+	require.NotNil(t, expvar.Get(name))
+}
@@ -0,0 +1,232 @@
+package gosmpp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/linxGnu/gosmpp/data"
+)
+
+var (
+	// ErrTemplateNotFound indicates template is not registered.
+	ErrTemplateNotFound = errors.New("template not found")
+
+	// ErrNoTemplates indicates Messenger is not given templates, see WithTemplates.
+	ErrNoTemplates = errors.New("messenger has no templates")
+)
+
+// Template is message text with placeholders like {{name}}, replaced by values on rendering.
+type Template struct {
+	// Name identifies the template.
+	Name string
+
+	// Text is the message text.
+	Text string
+
+	// MaxSegments is maximal number of parts of rendered message, unlimited if zero.
+	MaxSegments int
+}
+
+// TemplateWarning reports message rendered from template which costs more than the template itself
+// because of substituted values: it has more parts or is encoded in UCS2 instead of GSM 7-bit.
+type TemplateWarning struct {
+	// Template is name of the template.
+	Template string
+
+	// Segments is number of parts of rendered message, BaseSegments of the template text without placeholders.
+	Segments, BaseSegments int
+
+	// UCS2Values are names of placeholders whose values could not be encoded in GSM 7-bit.
+	UCS2Values []string
+}
+
+func (w TemplateWarning) String() string {
+	msg := fmt.Sprintf("gosmpp: template %q is rendered into %d parts, %d without values", w.Template, w.Segments, w.BaseSegments)
+	if len(w.UCS2Values) > 0 {
+		msg += fmt.Sprintf(", UCS2 encoding forced by values of %s", strings.Join(w.UCS2Values, ", "))
+	}
+	return msg
+}
+
+// RenderedTemplate is message rendered from template.
+type RenderedTemplate struct {
+	// Text is the message text.
+	Text string
+
+	// Encoding is encoding of the text, GSM 7-bit if possible, UCS2 otherwise.
+	Encoding data.Encoding
+
+	// Segments is number of parts of the message.
+	Segments int
+
+	// Warning is set if substituted values increased cost of the message.
+	Warning *TemplateWarning
+}
+
+// Templates is registry of message templates.
+type Templates struct {
+	onWarning func(TemplateWarning)
+
+	mu        sync.RWMutex
+	templates map[string]*parsedTemplate
+}
+
+// TemplatesOption configures Templates.
+type TemplatesOption func(*Templates)
+
+// WithTemplateWarningHandler sets function called with warnings of messages sent by Messenger.SendTemplate.
+func WithTemplateWarningHandler(onWarning func(TemplateWarning)) TemplatesOption {
+	return func(t *Templates) {
+		t.onWarning = onWarning
+	}
+}
+
+// NewTemplates returns empty registry.
+func NewTemplates(opts ...TemplatesOption) *Templates {
+	t := &Templates{templates: make(map[string]*parsedTemplate)}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// Register registers template, replacing the one of the same name.
+func (t *Templates) Register(tmpl Template) error {
+	parsed, err := parseTemplate(tmpl)
+	if err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	t.templates[tmpl.Name] = parsed
+	t.mu.Unlock()
+	return nil
+}
+
+// Render renders template by name with values of its placeholders. Values of all placeholders are required.
+func (t *Templates) Render(name string, values map[string]string) (RenderedTemplate, error) {
+	t.mu.RLock()
+	parsed, ok := t.templates[name]
+	t.mu.RUnlock()
+	if !ok {
+		return RenderedTemplate{}, fmt.Errorf("gosmpp: %w: %q", ErrTemplateNotFound, name)
+	}
+	return parsed.render(values)
+}
+
+// WithTemplates sets templates of messages sent by Messenger.SendTemplate.
+func WithTemplates(templates *Templates) MessengerOption {
+	return func(m *Messenger) {
+		m.templates = templates
+	}
+}
+
+// SendTemplate renders template by name with values and sends it as SendText does. Warning of rendered message,
+// if any, is reported to handler of the templates before sending, see WithTemplateWarningHandler.
+func (m *Messenger) SendTemplate(ctx context.Context, from, to, name string, values map[string]string) (*MessageHandle, error) {
+	if m.templates == nil {
+		return nil, ErrNoTemplates
+	}
+
+	rendered, err := m.templates.Render(name, values)
+	if err != nil {
+		return nil, err
+	}
+	if rendered.Warning != nil && m.templates.onWarning != nil {
+		m.templates.onWarning(*rendered.Warning)
+	}
+	return m.SendText(ctx, from, to, rendered.Text)
+}
+
+// parsedTemplate is template split into literal texts around placeholders.
+type parsedTemplate struct {
+	Template
+
+	literals     []string // len(placeholders)+1 texts
+	placeholders []string
+	baseSegments int
+}
+
+func parseTemplate(tmpl Template) (*parsedTemplate, error) {
+	p := &parsedTemplate{Template: tmpl}
+
+	text := tmpl.Text
+	for {
+		start := strings.Index(text, "{{")
+		if start < 0 {
+			break
+		}
+		end := strings.Index(text[start:], "}}")
+		if end < 0 {
+			return nil, fmt.Errorf("gosmpp: template %q: unclosed placeholder", tmpl.Name)
+		}
+
+		name := strings.TrimSpace(text[start+2 : start+end])
+		if !isPlaceholderName(name) {
+			return nil, fmt.Errorf("gosmpp: template %q: invalid placeholder %q", tmpl.Name, text[start:start+end+2])
+		}
+		p.literals = append(p.literals, text[:start])
+		p.placeholders = append(p.placeholders, name)
+		text = text[start+end+2:]
+	}
+	p.literals = append(p.literals, text)
+
+	var err error
+	if p.baseSegments, err = segmentCount(strings.Join(p.literals, "")); err != nil {
+		return nil, fmt.Errorf("gosmpp: template %q: %w", tmpl.Name, err)
+	}
+	return p, nil
+}
+
+func (p *parsedTemplate) render(values map[string]string) (r RenderedTemplate, err error) {
+	var (
+		b    strings.Builder
+		ucs2 []string
+	)
+	b.WriteString(p.literals[0])
+	for i, name := range p.placeholders {
+		value, ok := values[name]
+		if !ok {
+			return r, fmt.Errorf("gosmpp: template %q: missing value of {{%s}}", p.Name, name)
+		}
+		if textEncoding(value) == data.UCS2 && textEncoding(p.Text) != data.UCS2 {
+			ucs2 = append(ucs2, name)
+		}
+		b.WriteString(value)
+		b.WriteString(p.literals[i+1])
+	}
+
+	r.Text, r.Encoding = b.String(), textEncoding(b.String())
+	if r.Segments, err = segmentCount(r.Text); err != nil {
+		return r, fmt.Errorf("gosmpp: template %q: %w", p.Name, err)
+	}
+	if p.MaxSegments > 0 && r.Segments > p.MaxSegments {
+		return r, fmt.Errorf("gosmpp: template %q: rendered into %d parts, exceeding %d", p.Name, r.Segments, p.MaxSegments)
+	}
+
+	if r.Segments > p.baseSegments || len(ucs2) > 0 {
+		r.Warning = &TemplateWarning{Template: p.Name, Segments: r.Segments, BaseSegments: p.baseSegments, UCS2Values: ucs2}
+	}
+	return r, nil
+}
+
+func isPlaceholderName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if !(r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// segmentCount returns number of parts text is split into by SendText.
+func segmentCount(text string) (int, error) {
+	p, err := PreviewMessage(text)
+	return p.SegmentCount(), err
+}
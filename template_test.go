@@ -0,0 +1,94 @@
+package gosmpp
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp/data"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplatesRender(t *testing.T) {
+	templates := NewTemplates()
+	require.Nil(t, templates.Register(Template{Name: "otp", Text: "Hi {{name}}, your code is {{ code }}", MaxSegments: 2}))
+
+	r, err := templates.Render("otp", map[string]string{"name": "Bob", "code": "1234", "unused": "x"})
+	require.Nil(t, err)
+	require.Equal(t, "Hi Bob, your code is 1234", r.Text)
+	require.Equal(t, data.GSM7BIT, r.Encoding)
+	require.Equal(t, 1, r.Segments)
+	require.Nil(t, r.Warning)
+
+	// value forcing UCS2
+	r, err = templates.Render("otp", map[string]string{"name": "Trần", "code": "1234"})
+	require.Nil(t, err)
+	require.Equal(t, data.UCS2, r.Encoding)
+	require.NotNil(t, r.Warning)
+	require.Equal(t, []string{"name"}, r.Warning.UCS2Values)
+	require.Equal(t, 1, r.Warning.Segments)
+
+	// value pushing into extra segment
+	r, err = templates.Render("otp", map[string]string{"name": strings.Repeat("a", 150), "code": "1234"})
+	require.Nil(t, err)
+	require.Equal(t, 2, r.Segments)
+	require.Equal(t, TemplateWarning{Template: "otp", Segments: 2, BaseSegments: 1}, *r.Warning)
+	require.Equal(t, `gosmpp: template "otp" is rendered into 2 parts, 1 without values`, r.Warning.String())
+
+	_, err = templates.Render("otp", map[string]string{"name": strings.Repeat("a", 400), "code": "1234"})
+	require.NotNil(t, err)
+
+	_, err = templates.Render("otp", map[string]string{"name": "Bob"})
+	require.EqualError(t, err, `gosmpp: template "otp": missing value of {{code}}`)
+
+	_, err = templates.Render("welcome", nil)
+	require.ErrorIs(t, err, ErrTemplateNotFound)
+}
+
+func TestTemplatesRegister(t *testing.T) {
+	templates := NewTemplates()
+	require.NotNil(t, templates.Register(Template{Name: "a", Text: "Hi {{name"}))
+	require.NotNil(t, templates.Register(Template{Name: "a", Text: "Hi {{first name}}"}))
+	require.NotNil(t, templates.Register(Template{Name: "a", Text: "Hi {{}}"}))
+
+	// template in UCS2 warns about length only
+	require.Nil(t, templates.Register(Template{Name: "vi", Text: "Xin chào bạn {{name}}"}))
+	r, err := templates.Render("vi", map[string]string{"name": "Trần"})
+	require.Nil(t, err)
+	require.Nil(t, r.Warning)
+}
+
+func TestMessengerSendTemplate(t *testing.T) {
+	auth := nextAuth()
+	s, err := NewSession(TRXConnector(NonTLSDialer, auth), Settings{ReadTimeout: 2 * time.Second}, -1)
+	require.Nil(t, err)
+	defer func() {
+		_ = s.Close()
+	}()
+
+	var warnings []TemplateWarning
+	templates := NewTemplates(WithTemplateWarningHandler(func(w TemplateWarning) {
+		warnings = append(warnings, w)
+	}))
+	require.Nil(t, templates.Register(Template{Name: "otp", Text: "Your code is {{code}}"}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err = NewMessenger(s).SendTemplate(ctx, "MyBank", "+84901234567", "otp", map[string]string{"code": "1234"})
+	require.Equal(t, ErrNoTemplates, err)
+
+	m := NewMessenger(s, WithTemplates(templates))
+	h, err := m.SendTemplate(ctx, "MyBank", "+84901234567", "otp", map[string]string{"code": "1234"})
+	require.Nil(t, err)
+	text, _ := h.Parts[0].Message.GetMessage()
+	require.Equal(t, "Your code is 1234", text)
+	require.Empty(t, warnings)
+
+	h, err = m.SendTemplate(ctx, "MyBank", "+84901234567", "otp", map[string]string{"code": "một hai"})
+	require.Nil(t, err)
+	require.Len(t, h.MessageIDs, 1)
+	require.Len(t, warnings, 1)
+}
@@ -3,6 +3,7 @@ package gosmpp
 import (
 	"context"
 	"errors"
+	"github.com/linxGnu/gosmpp/clock"
 	"github.com/linxGnu/gosmpp/pdu"
 	"sync"
 	"sync/atomic"
@@ -25,6 +26,7 @@ type transceivable struct {
 
 	aliveState   int32
 	requestStore RequestStore
+	stats        *linkStats
 }
 type TransceivableOption func(session *Session)
 
@@ -34,14 +36,24 @@ func newTransceivable(conn *Connection, settings Settings, requestStore RequestS
 		settings:     settings,
 		conn:         conn,
 		requestStore: requestStore,
+		stats:        newLinkStats(),
 	}
+	t.stats.clock = settings.Clock
 	t.ctx, t.cancel = context.WithCancel(context.Background())
 
 	t.out = newTransmittable(conn, Settings{
+		ReadTimeout: settings.ReadTimeout,
+
 		WriteTimeout: settings.WriteTimeout,
 
 		EnquireLink: settings.EnquireLink,
 
+		Clock: settings.Clock,
+
+		Reactor: settings.Reactor,
+
+		MaxCommandLength: settings.MaxCommandLength,
+
 		OnSubmitError: settings.OnSubmitError,
 
 		OnClosed: func(state State) {
@@ -66,6 +78,10 @@ func newTransceivable(conn *Connection, settings Settings, requestStore RequestS
 
 		OnPDU: settings.OnPDU,
 
+		OnMessage: settings.OnMessage,
+
+		OnDeliveryReceipt: settings.OnDeliveryReceipt,
+
 		OnAllPDU: settings.OnAllPDU,
 
 		OnReceivingError: settings.OnReceivingError,
@@ -86,23 +102,51 @@ func newTransceivable(conn *Connection, settings Settings, requestStore RequestS
 
 		WindowedRequestTracking: settings.WindowedRequestTracking,
 
+		Clock: settings.Clock,
+
+		ReceiveWorkers: settings.ReceiveWorkers,
+
+		Validation: settings.Validation,
+
 		response: func(p pdu.PDU) {
-			_ = t.Submit(p)
+			// OnAllPDU and OnReceivedPduRequest return nil to leave the request unanswered, their workers
+			// should not wait for room in the queue then
+			if p != nil {
+				_ = t.Submit(p)
+			}
 		},
 	},
 		requestStore,
 	)
+
+	t.out.stats = t.stats
+	t.in.stats = t.stats
 	return t
 }
 
 func (t *transceivable) start() {
 	if t.settings.WindowedRequestTracking != nil && t.settings.ExpireCheckTimer > 0 {
-		t.wg.Add(1)
-		go func() {
-			defer t.wg.Done()
-			t.windowCleanup()
-		}()
-
+		if t.settings.Reactor != nil {
+			t.settings.Reactor.schedule(t.settings.ExpireCheckTimer, func() bool {
+				if atomic.LoadInt32(&t.out.aliveState) != Alive {
+					return false
+				}
+				if t.expire() {
+					// closing waits for Reactor writers, must not block them
+					go func() {
+						_ = t.Close()
+					}()
+					return false
+				}
+				return true
+			})
+		} else {
+			t.wg.Add(1)
+			go func() {
+				defer t.wg.Done()
+				t.windowCleanup()
+			}()
+		}
 	}
 	t.out.start()
 	t.in.start()
@@ -147,26 +191,33 @@ func (t *transceivable) GetWindowSize() (int, error) {
 }
 
 func (t *transceivable) windowCleanup() {
-	ticker := time.NewTicker(t.settings.ExpireCheckTimer)
+	ticker := clock.OrReal(t.settings.Clock).NewTicker(t.settings.ExpireCheckTimer)
 	defer ticker.Stop()
 	for {
 		select {
 		case <-t.ctx.Done():
 			return
-		case <-ticker.C:
-			ctx, cancelFunc := context.WithTimeout(context.Background(), t.settings.StoreAccessTimeOut*time.Millisecond)
-			for _, request := range t.requestStore.List(ctx) {
-				if time.Since(request.TimeSent) > t.settings.PduExpireTimeOut {
-					_ = t.requestStore.Delete(ctx, request.GetSequenceNumber())
-					if t.settings.OnExpiredPduRequest != nil {
-						bindClose := t.settings.OnExpiredPduRequest(request.PDU)
-						if bindClose {
-							_ = t.Close()
-						}
-					}
-				}
+		case <-ticker.C():
+			if t.expire() {
+				_ = t.Close()
 			}
-			cancelFunc() //defer should not be used because we are inside loop
 		}
 	}
 }
+
+// expire removes expired requests from window, returns true if bind should be closed.
+func (t *transceivable) expire() (bindClose bool) {
+	clk := clock.OrReal(t.settings.Clock)
+	ctx, cancelFunc := context.WithTimeout(context.Background(), t.settings.StoreAccessTimeOut*time.Millisecond)
+	defer cancelFunc()
+
+	for _, request := range t.requestStore.List(ctx) {
+		if clk.Since(request.TimeSent) > t.settings.PduExpireTimeOut {
+			_ = t.requestStore.Delete(ctx, request.GetSequenceNumber())
+			if t.settings.OnExpiredPduRequest != nil && t.settings.OnExpiredPduRequest(request.PDU) {
+				bindClose = true
+			}
+		}
+	}
+	return
+}
@@ -404,4 +404,13 @@ func Test_newTransceivable(t *testing.T) {
 		trans := newTransceivable(nil, Settings{}, nil)
 		assert.NotNil(t, trans.in.settings.response)
 	})
+
+	t.Run("nil response is not queued", func(t *testing.T) {
+		trans := newTransceivable(nil, Settings{}, nil)
+		trans.in.settings.response(nil)
+		require.Len(t, trans.out.input, 0)
+
+		trans.in.settings.response(pdu.NewEnquireLink().GetResponse())
+		require.Len(t, trans.out.input, 1)
+	})
 }
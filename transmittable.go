@@ -9,6 +9,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/linxGnu/gosmpp/clock"
 	"github.com/linxGnu/gosmpp/pdu"
 )
 
@@ -29,6 +30,8 @@ type transmittable struct {
 	aliveState   int32
 	pendingWrite int32
 	requestStore RequestStore
+	stats        *linkStats
+	reactor      reactorTransmittable
 }
 
 func newTransmittable(conn *Connection, settings Settings, requestStore RequestStore) *transmittable {
@@ -56,8 +59,20 @@ func (t *transmittable) close(state State) (err error) {
 		// wait daemon
 		t.wg.Wait()
 
-		// try to send unbind
-		_, _ = t.write(pdu.NewUnbind())
+		if t.settings.Reactor != nil {
+			// wait for writer of Reactor, then write what is left like daemon does
+			t.reactor.writeMu.Lock()
+			if state == ConnectionIssue {
+				t.drain()
+			} else {
+				t.flush()
+			}
+			_, _ = t.write(pdu.NewUnbind())
+			t.reactor.writeMu.Unlock()
+		} else {
+			// try to send unbind
+			_, _ = t.write(pdu.NewUnbind())
+		}
 
 		// close connection
 		if state != StoppingProcessOnly {
@@ -104,10 +119,17 @@ func (t *transmittable) closing(state State) {
 
 // Submit a PDU.
 func (t *transmittable) Submit(p pdu.PDU) (err error) {
+	if err = t.checkLength(p); err != nil {
+		return
+	}
+
 	atomic.AddInt32(&t.pendingWrite, 1)
 
 	if atomic.LoadInt32(&t.aliveState) == Alive {
 		t.input <- p
+		if t.settings.Reactor != nil {
+			t.schedule()
+		}
 	} else {
 		err = ErrConnectionClosing
 	}
@@ -116,7 +138,23 @@ func (t *transmittable) Submit(p pdu.PDU) (err error) {
 	return
 }
 
+// checkLength checks p against MaxCommandLength of settings, before it is queued. Nil p is skipped by writing
+// loop as ever.
+func (t *transmittable) checkLength(p pdu.PDU) error {
+	if t.settings.MaxCommandLength <= 0 || p == nil {
+		return nil
+	}
+	buf := pdu.NewBuffer(make([]byte, 0, 64))
+	p.Marshal(buf)
+	return pdu.CheckLength(buf.Bytes(), t.settings.MaxCommandLength)
+}
+
 func (t *transmittable) start() {
+	if t.settings.Reactor != nil {
+		t.startOnReactor()
+		return
+	}
+
 	t.wg.Add(1)
 	if t.settings.EnquireLink > 0 {
 		go func() {
@@ -150,7 +188,7 @@ func (t *transmittable) loop() {
 }
 
 func (t *transmittable) loopWithEnquireLink() {
-	ticker := time.NewTicker(t.settings.EnquireLink)
+	ticker := clock.OrReal(t.settings.Clock).NewTicker(t.settings.EnquireLink)
 	defer func() {
 		ticker.Stop()
 		t.drain()
@@ -158,7 +196,9 @@ func (t *transmittable) loopWithEnquireLink() {
 
 	for {
 		select {
-		case <-ticker.C:
+		case <-ticker.C():
+			t.stats.expire(t.settings.ReadTimeout)
+
 			eqp := pdu.NewEnquireLink()
 			n, err := t.write(eqp)
 			if t.check(eqp, n, err) {
@@ -185,13 +225,15 @@ func (t *transmittable) check(p pdu.PDU, n int, err error) (closing bool) {
 	if err == nil {
 		return
 	}
+	t.stats.onSubmitError(p, err)
 
 	if t.settings.OnSubmitError != nil {
 		t.settings.OnSubmitError(p, err)
 	}
 
+	var sizeErr *pdu.SizeError
 	if n == 0 {
-		if errors.Is(err, ErrWindowsFull) {
+		if errors.Is(err, ErrWindowsFull) || errors.As(err, &sizeErr) {
 			closing = false
 		} else if nErr, ok := err.(net.Error); ok {
 			closing = nErr.Timeout()
@@ -227,13 +269,22 @@ func (t *transmittable) write(p pdu.PDU) (n int, err error) {
 			return 0, err
 		}
 		if length < int(t.settings.MaxWindowSize) {
+			if !t.stats.claim(p) {
+				return 0, nil
+			}
+			// taken before writing, response could complete the call at once
+			correlation := t.stats.correlationOf(p)
+			t.stats.onSending(p)
 			n, err = t.conn.WritePDU(p)
 			if err != nil {
+				t.stats.onWriteFailed(p)
 				return 0, err
 			}
+			t.stats.onWritten(p)
 			request := Request{
-				PDU:      p,
-				TimeSent: time.Now(),
+				PDU:         p,
+				TimeSent:    clock.OrReal(t.settings.Clock).Now(),
+				Correlation: correlation,
 			}
 			err = t.requestStore.Set(ctx, request)
 			if err != nil {
@@ -243,7 +294,15 @@ func (t *transmittable) write(p pdu.PDU) (n int, err error) {
 			return 0, ErrWindowsFull
 		}
 	} else {
-		n, err = t.conn.WritePDU(p)
+		if !t.stats.claim(p) {
+			return 0, nil
+		}
+		t.stats.onSending(p)
+		if n, err = t.conn.WritePDU(p); err == nil {
+			t.stats.onWritten(p)
+		} else {
+			t.stats.onWriteFailed(p)
+		}
 	}
 
 	return
@@ -13,6 +13,12 @@ type PDUCallback func(pdu pdu.PDU, responded bool)
 // and the bind can be closed by retuning true on closeBind.
 type AllPDUCallback func(pdu pdu.PDU) (responsePdu pdu.PDU, closeBind bool)
 
+// MessageCallback handles received mobile originated message.
+type MessageCallback func(IncomingMessage)
+
+// ReceiptCallback handles received delivery receipt.
+type ReceiptCallback func(Receipt)
+
 // PDUErrorCallback notifies fail-to-submit PDU with along error.
 type PDUErrorCallback func(pdu pdu.PDU, err error)
 
@@ -24,3 +30,6 @@ type ClosedCallback func(State)
 
 // RebindCallback notifies rebind event due to State.
 type RebindCallback func()
+
+// HandlerPanicCallback notifies panic recovered from user callback, handler is name of the callback (e.g. "OnPDU").
+type HandlerPanicCallback func(handler string, recovered interface{}, stack []byte)
@@ -0,0 +1,287 @@
+package gosmpp
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/linxGnu/gosmpp/clock"
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+)
+
+// USSD service operations, values of ussd_service_op TLV.
+const (
+	USSDPSSDIndication byte = 0x00
+	USSDPSSRIndication byte = 0x01
+	USSDUSSRRequest    byte = 0x02
+	USSDUSSNRequest    byte = 0x03
+	USSDPSSDResponse   byte = 0x10
+	USSDPSSRResponse   byte = 0x11
+	USSDUSSRConfirm    byte = 0x12
+	USSDUSSNConfirm    byte = 0x13
+)
+
+// DefaultUSSDTimeout is default inactivity timeout of USSD sessions.
+const DefaultUSSDTimeout = 3 * time.Minute
+
+// USSDSession is USSD dialog with a subscriber, from the request dialed by subscriber until it ends.
+type USSDSession struct {
+	// MSISDN is address of the subscriber.
+	MSISDN string
+
+	// Step is number of received subscriber inputs before the current one, zero when the session begins.
+	Step int
+
+	// Values is state of the session, kept between steps.
+	Values map[string]interface{}
+
+	key      string
+	number   byte
+	service  pdu.Address
+	msisdn   pdu.Address
+	dataSM   bool
+	sequence byte
+	timer    clock.Timer
+}
+
+// USSDReply is reply of USSDHandler to subscriber input.
+type USSDReply struct {
+	// Text is shown to subscriber.
+	Text string
+
+	// End ends the session, otherwise subscriber is asked for next input.
+	End bool
+}
+
+// USSDContinue returns reply asking subscriber for next input.
+func USSDContinue(text string) USSDReply {
+	return USSDReply{Text: text}
+}
+
+// USSDEnd returns reply ending the session.
+func USSDEnd(text string) USSDReply {
+	return USSDReply{Text: text, End: true}
+}
+
+// USSDHandler handles subscriber input of session: the dialed service string when session begins,
+// e.g. "*123#", and the answers on next steps.
+type USSDHandler func(s *USSDSession, input string) USSDReply
+
+// USSD implements menu-like USSD dialogs over deliver_sm/submit_sm or data_sm with ussd_service_op
+// and its_session_info TLVs. Subscriber requests are fed by HandlePDU, e.g. from Settings.OnPDU:
+//
+//	ussd := gosmpp.NewUSSD(session.Transmitter(), func(s *gosmpp.USSDSession, input string) gosmpp.USSDReply {
+//		if s.Step == 0 {
+//			return gosmpp.USSDContinue("1. Balance\n2. Top up")
+//		}
+//		return gosmpp.USSDEnd("Thank you")
+//	})
+//	settings.OnPDU = func(p pdu.PDU, _ bool) { _, _ = ussd.HandlePDU(p) }
+//
+// Sessions inactive longer than timeout are ended without reply.
+type USSD struct {
+	transmitter Transmitter
+	handler     USSDHandler
+	timeout     time.Duration
+	onTimeout   func(*USSDSession)
+	clock       clock.Clock
+
+	mu       sync.Mutex
+	sessions map[string]*USSDSession
+}
+
+// USSDOption configures USSD.
+type USSDOption func(*USSD)
+
+// WithUSSDTimeout sets inactivity timeout of sessions, DefaultUSSDTimeout by default.
+func WithUSSDTimeout(timeout time.Duration) USSDOption {
+	return func(u *USSD) {
+		u.timeout = timeout
+	}
+}
+
+// WithUSSDTimeoutHandler sets function called with sessions ended by timeout.
+func WithUSSDTimeoutHandler(onTimeout func(*USSDSession)) USSDOption {
+	return func(u *USSD) {
+		u.onTimeout = onTimeout
+	}
+}
+
+// WithUSSDClock sets clock of session timeouts, real clock by default.
+func WithUSSDClock(c clock.Clock) USSDOption {
+	return func(u *USSD) {
+		u.clock = c
+	}
+}
+
+// NewUSSD returns USSD replying to subscribers over transmitter.
+func NewUSSD(transmitter Transmitter, handler USSDHandler, opts ...USSDOption) *USSD {
+	u := &USSD{
+		transmitter: transmitter,
+		handler:     handler,
+		timeout:     DefaultUSSDTimeout,
+		sessions:    make(map[string]*USSDSession),
+	}
+	for _, opt := range opts {
+		opt(u)
+	}
+	u.clock = clock.OrReal(u.clock)
+	return u
+}
+
+// HandlePDU handles p if it is USSD request of subscriber, reporting whether it is.
+// Reply is submitted before returning.
+func (u *USSD) HandlePDU(p pdu.PDU) (bool, error) {
+	var (
+		s      USSDSession
+		params map[pdu.Tag]pdu.Field
+		input  string
+		err    error
+	)
+	switch p := p.(type) {
+	case *pdu.DeliverSM:
+		s.service, s.msisdn, params = p.DestAddr, p.SourceAddr, p.OptionalParameters
+		input, err = p.Message.GetMessage()
+
+	case *pdu.DataSM:
+		s.service, s.msisdn, params, s.dataSM = p.DestAddr, p.SourceAddr, p.OptionalParameters, true
+		if payload, ok := params[pdu.TagMessagePayload]; ok {
+			input, err = data.FromDataCoding(p.DataCoding).Decode(payload.Data)
+		}
+
+	default:
+		return false, nil
+	}
+
+	op, ok := params[pdu.TagUssdServiceOp]
+	if !ok || len(op.Data) != 1 {
+		return false, nil
+	}
+	if err != nil {
+		return true, err
+	}
+
+	s.MSISDN = s.msisdn.Address()
+	s.key = s.MSISDN
+	if info, ok := params[pdu.TagItsSessionInfo]; ok && len(info.Data) == 2 {
+		s.number, s.sequence = info.Data[0], info.Data[1]>>1
+		s.key = fmt.Sprintf("%s/%d", s.MSISDN, s.number)
+	}
+
+	session := u.session(s, op.Data[0])
+	if session == nil {
+		// answer of ended session
+		return true, nil
+	}
+
+	reply := u.handler(session, input)
+	session.Step++
+	if reply.End {
+		u.end(session)
+	}
+	return true, u.reply(session, reply)
+}
+
+// session returns session of request s, starting new one on PSSR/PSSD indication.
+func (u *USSD) session(s USSDSession, op byte) *USSDSession {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	session, ok := u.sessions[s.key]
+	switch op {
+	case USSDPSSRIndication, USSDPSSDIndication:
+		if ok {
+			session.timer.Stop()
+		}
+		session = &s
+		session.Values = make(map[string]interface{})
+		u.sessions[s.key] = session
+
+	case USSDUSSRConfirm:
+		if !ok {
+			return nil
+		}
+		session.sequence = s.sequence
+		session.timer.Stop()
+
+	default:
+		return nil
+	}
+
+	session.timer = u.clock.AfterFunc(u.timeout, func() {
+		if u.remove(session) && u.onTimeout != nil {
+			u.onTimeout(session)
+		}
+	})
+	return session
+}
+
+// end removes session, stopping its timeout.
+func (u *USSD) end(s *USSDSession) {
+	if u.remove(s) {
+		s.timer.Stop()
+	}
+}
+
+// remove removes session, reporting whether it was not removed already.
+func (u *USSD) remove(s *USSDSession) bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.sessions[s.key] != s {
+		return false
+	}
+	delete(u.sessions, s.key)
+	return true
+}
+
+// reply submits reply to subscriber of the session.
+func (u *USSD) reply(s *USSDSession, reply USSDReply) error {
+	op := USSDUSSRRequest
+	if reply.End {
+		op = USSDPSSRResponse
+	}
+
+	enc := textEncoding(reply.Text)
+	params := []pdu.Field{{Tag: pdu.TagUssdServiceOp, Data: []byte{op}}}
+	if s.key != s.MSISDN {
+		sequence := (s.sequence + 1) << 1
+		if reply.End {
+			sequence |= 0x01
+		}
+		params = append(params, pdu.Field{Tag: pdu.TagItsSessionInfo, Data: []byte{s.number, sequence}})
+	}
+
+	var p pdu.PDU
+	if s.dataSM {
+		payload, err := enc.Encode(reply.Text)
+		if err != nil {
+			return err
+		}
+
+		d := pdu.NewDataSM().(*pdu.DataSM)
+		d.ServiceType, d.SourceAddr, d.DestAddr, d.DataCoding = data.SERVICE_USSD, s.service, s.msisdn, enc.DataCoding()
+		params = append(params, pdu.Field{Tag: pdu.TagMessagePayload, Data: payload})
+		p = d
+	} else {
+		submit := pdu.NewSubmitSM().(*pdu.SubmitSM)
+		submit.ServiceType, submit.SourceAddr, submit.DestAddr = data.SERVICE_USSD, s.service, s.msisdn
+		if err := submit.Message.SetMessageWithEncoding(reply.Text, enc); err != nil {
+			return err
+		}
+		p = submit
+	}
+
+	for _, param := range params {
+		p.RegisterOptionalParam(param)
+	}
+	return u.transmitter.Submit(p)
+}
+
+// Sessions returns number of active sessions.
+func (u *USSD) Sessions() int {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return len(u.sessions)
+}
@@ -0,0 +1,147 @@
+package gosmpp
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp/clock"
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+
+	"github.com/stretchr/testify/require"
+)
+
+type submitRecorder struct {
+	mu        sync.Mutex
+	submitted []pdu.PDU
+}
+
+func (r *submitRecorder) Submit(p pdu.PDU) error {
+	r.mu.Lock()
+	r.submitted = append(r.submitted, p)
+	r.mu.Unlock()
+	return nil
+}
+
+func (r *submitRecorder) Close() error     { return nil }
+func (r *submitRecorder) SystemID() string { return "recorder" }
+
+func (r *submitRecorder) last() pdu.PDU {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.submitted[len(r.submitted)-1]
+}
+
+func newUSSDRequest(op byte, number, sequence byte, text string) *pdu.DeliverSM {
+	p := pdu.NewDeliverSM().(*pdu.DeliverSM)
+	_ = p.SourceAddr.SetAddress("84901234567")
+	_ = p.DestAddr.SetAddress("123")
+	_ = p.Message.SetMessageWithEncoding(text, data.GSM7BIT)
+	p.RegisterOptionalParam(pdu.Field{Tag: pdu.TagUssdServiceOp, Data: []byte{op}})
+	p.RegisterOptionalParam(pdu.Field{Tag: pdu.TagItsSessionInfo, Data: []byte{number, sequence << 1}})
+	return p
+}
+
+func TestUSSD(t *testing.T) {
+	var inputs []string
+	recorder := &submitRecorder{}
+	u := NewUSSD(recorder, func(s *USSDSession, input string) USSDReply {
+		inputs = append(inputs, input)
+		if s.Step == 0 {
+			s.Values["menu"] = "main"
+			return USSDContinue("1. Balance")
+		}
+		require.Equal(t, "main", s.Values["menu"])
+		return USSDEnd("Balance: 10")
+	})
+
+	ok, err := u.HandlePDU(newUSSDRequest(USSDPSSRIndication, 7, 0, "*123#"))
+	require.Nil(t, err)
+	require.True(t, ok)
+	require.Equal(t, 1, u.Sessions())
+
+	reply := recorder.last().(*pdu.SubmitSM)
+	require.Equal(t, "84901234567", reply.DestAddr.Address())
+	require.Equal(t, "123", reply.SourceAddr.Address())
+	require.Equal(t, []byte{USSDUSSRRequest}, reply.OptionalParameters[pdu.TagUssdServiceOp].Data)
+	require.Equal(t, []byte{7, 1 << 1}, reply.OptionalParameters[pdu.TagItsSessionInfo].Data)
+	text, _ := reply.Message.GetMessage()
+	require.Equal(t, "1. Balance", text)
+
+	ok, err = u.HandlePDU(newUSSDRequest(USSDUSSRConfirm, 7, 2, "1"))
+	require.Nil(t, err)
+	require.True(t, ok)
+	require.Equal(t, 0, u.Sessions())
+
+	reply = recorder.last().(*pdu.SubmitSM)
+	require.Equal(t, []byte{USSDPSSRResponse}, reply.OptionalParameters[pdu.TagUssdServiceOp].Data)
+	require.Equal(t, []byte{7, 3<<1 | 1}, reply.OptionalParameters[pdu.TagItsSessionInfo].Data)
+	require.Equal(t, []string{"*123#", "1"}, inputs)
+
+	// answer of ended session is ignored
+	ok, err = u.HandlePDU(newUSSDRequest(USSDUSSRConfirm, 7, 4, "2"))
+	require.Nil(t, err)
+	require.True(t, ok)
+	require.Len(t, inputs, 2)
+
+	// not USSD
+	ok, err = u.HandlePDU(pdu.NewDeliverSM())
+	require.Nil(t, err)
+	require.False(t, ok)
+}
+
+func TestUSSDDataSM(t *testing.T) {
+	recorder := &submitRecorder{}
+	u := NewUSSD(recorder, func(s *USSDSession, input string) USSDReply {
+		require.Equal(t, "*100#", input)
+		return USSDEnd("Xin chào bạn")
+	})
+
+	p := pdu.NewDataSM().(*pdu.DataSM)
+	_ = p.SourceAddr.SetAddress("84901234567")
+	p.RegisterOptionalParam(pdu.Field{Tag: pdu.TagUssdServiceOp, Data: []byte{USSDPSSRIndication}})
+	p.RegisterOptionalParam(pdu.Field{Tag: pdu.TagMessagePayload, Data: []byte("*100#")})
+
+	ok, err := u.HandlePDU(p)
+	require.Nil(t, err)
+	require.True(t, ok)
+
+	reply := recorder.last().(*pdu.DataSM)
+	require.Equal(t, data.UCS2Coding, reply.DataCoding)
+	require.Equal(t, []byte{USSDPSSRResponse}, reply.OptionalParameters[pdu.TagUssdServiceOp].Data)
+	_, hasInfo := reply.OptionalParameters[pdu.TagItsSessionInfo]
+	require.False(t, hasInfo)
+	text, _ := data.UCS2.Decode(reply.OptionalParameters[pdu.TagMessagePayload].Data)
+	require.Equal(t, "Xin chào bạn", text)
+}
+
+func TestUSSDTimeout(t *testing.T) {
+	c := clock.NewFake(time.Now())
+	timedOut := make(chan *USSDSession, 1)
+	u := NewUSSD(&submitRecorder{}, func(s *USSDSession, input string) USSDReply {
+		return USSDContinue("menu")
+	}, WithUSSDClock(c), WithUSSDTimeout(time.Minute), WithUSSDTimeoutHandler(func(s *USSDSession) {
+		timedOut <- s
+	}))
+
+	_, err := u.HandlePDU(newUSSDRequest(USSDPSSRIndication, 1, 0, "*123#"))
+	require.Nil(t, err)
+
+	c.Advance(50 * time.Second)
+	_, err = u.HandlePDU(newUSSDRequest(USSDUSSRConfirm, 1, 2, "1"))
+	require.Nil(t, err)
+
+	c.Advance(50 * time.Second)
+	require.Equal(t, 1, u.Sessions())
+
+	c.Advance(10 * time.Second)
+	select {
+	case s := <-timedOut:
+		require.Equal(t, "84901234567", s.MSISDN)
+		require.Equal(t, 2, s.Step)
+	case <-time.After(time.Second):
+		t.Fatal("session is not timed out")
+	}
+	require.Equal(t, 0, u.Sessions())
+}
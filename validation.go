@@ -0,0 +1,32 @@
+package gosmpp
+
+// ValidationMode is how session treats received PDUs deviating from SMPP specification, e.g. fields longer than
+// specified or reserved values, as found by pdu.Validate. SMSCs differ in how strictly they follow the
+// specification, the mode picks the behavior without changing decoding.
+//
+// EventDeviation is published for every deviating PDU in both modes.
+type ValidationMode byte
+
+const (
+	// ValidationPermissive accepts deviating PDUs, handling them as any other.
+	ValidationPermissive ValidationMode = iota
+
+	// ValidationStrict rejects deviating PDUs. Requests are responded with command status of their first
+	// deviation, responses fail calls of their requests with *pdu.ValidationError. Rejected PDUs are not passed
+	// to handlers.
+	ValidationStrict
+)
+
+// String interface.
+func (m ValidationMode) String() string {
+	switch m {
+	case ValidationPermissive:
+		return "permissive"
+
+	case ValidationStrict:
+		return "strict"
+
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,125 @@
+package gosmpp_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp"
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+	"github.com/linxGnu/gosmpp/server/smsctest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionValidation(t *testing.T) {
+	bind := func(t *testing.T, smsc *smsctest.Server, mode gosmpp.ValidationMode, received chan<- pdu.PDU) (*gosmpp.Session, <-chan gosmpp.Event) {
+		s, err := gosmpp.NewSession(gosmpp.TRXConnector(smsc.Dialer(), gosmpp.Auth{SMSC: "pipe", SystemID: "esme"}),
+			gosmpp.Settings{
+				ReadTimeout: 2 * time.Second,
+				Validation:  mode,
+
+				OnPDU: func(p pdu.PDU, _ bool) {
+					if _, ok := p.(*pdu.DeliverSM); ok {
+						received <- p
+					}
+				},
+			}, -1)
+		require.Nil(t, err)
+
+		events, stop := s.Events(10)
+		t.Cleanup(func() {
+			stop()
+			_ = s.Close()
+		})
+		return s, events
+	}
+
+	deviating := func() pdu.PDU {
+		p := newDeliverSM("+447700900123", "hello")
+		p.(*pdu.DeliverSM).SourceAddr.SetTon(7)
+		return p
+	}
+
+	expectDeviation := func(t *testing.T, events <-chan gosmpp.Event) gosmpp.Event {
+		for {
+			select {
+			case e := <-events:
+				if e.Type == gosmpp.EventDeviation {
+					return e
+				}
+			case <-time.After(time.Second):
+				t.Fatal("deviation is not published")
+			}
+		}
+	}
+
+	t.Run("permissive", func(t *testing.T) {
+		smsc := smsctest.NewPipeServer(nil)
+		defer smsc.Close()
+
+		received := make(chan pdu.PDU, 1)
+		_, events := bind(t, smsc, gosmpp.ValidationPermissive, received)
+
+		require.Nil(t, smsc.Sessions()[0].Submit(deviating()))
+
+		e := expectDeviation(t, events)
+		require.Equal(t, data.DELIVER_SM, e.PDU.GetHeader().CommandID)
+		require.Contains(t, e.Err.Error(), "source_addr_ton: reserved value 7")
+
+		select {
+		case <-received:
+		case <-time.After(time.Second):
+			t.Fatal("deviating deliver_sm is not handled")
+		}
+		smsc.ExpectReceived(t, data.DELIVER_SM_RESP, 1)
+		require.True(t, smsc.Received()[len(smsc.Received())-1].IsOk())
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		smsc := smsctest.NewPipeServer(nil)
+		defer smsc.Close()
+
+		received := make(chan pdu.PDU, 1)
+		s, events := bind(t, smsc, gosmpp.ValidationStrict, received)
+		client := smsc.Sessions()[0]
+
+		require.Nil(t, client.Submit(deviating()))
+		expectDeviation(t, events)
+
+		smsc.ExpectReceived(t, data.DELIVER_SM_RESP, 1)
+		resp := smsc.Received()[len(smsc.Received())-1]
+		require.Equal(t, data.ESME_RINVSRCTON, resp.GetHeader().CommandStatus)
+
+		// valid requests are still handled
+		require.Nil(t, client.Submit(newDeliverSM("+447700900123", "hello")))
+		select {
+		case p := <-received:
+			text, _ := p.(*pdu.DeliverSM).Message.GetMessage()
+			require.Equal(t, "hello", text)
+		case <-time.After(time.Second):
+			t.Fatal("valid deliver_sm is not handled")
+		}
+
+		t.Run("response", func(t *testing.T) {
+			smsc.SetFault(func(pdu.PDU) smsctest.Fault { return smsctest.Fault{Drop: true} })
+			submit := pdu.NewSubmitSM()
+			call := s.SubmitAsync(submit)
+			require.Eventually(t, call.Written, time.Second, 10*time.Millisecond)
+
+			resp := submit.GetResponse().(*pdu.SubmitSMResp)
+			resp.MessageID = strings.Repeat("f", 100)
+			require.Nil(t, client.Submit(resp))
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			_, err := call.Wait(ctx)
+
+			var verr *pdu.ValidationError
+			require.ErrorAs(t, err, &verr)
+			require.Equal(t, "message_id", verr.Deviations[0].Field)
+		})
+	})
+}
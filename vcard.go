@@ -0,0 +1,109 @@
+package gosmpp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/linxGnu/gosmpp/pdu"
+)
+
+// Smart messaging ports of vCard and vCalendar.
+const (
+	VCardPort     uint16 = 9204
+	VCalendarPort uint16 = 9205
+)
+
+// VCard is contact card in vCard 2.1 format.
+type VCard struct {
+	// Name is full name, required.
+	Name string
+
+	// Phone, Email, Organization and URL are optional.
+	Phone        string
+	Email        string
+	Organization string
+	URL          string
+}
+
+// Encode returns the card in vCard 2.1 format.
+func (c VCard) Encode() ([]byte, error) {
+	if c.Name == "" {
+		return nil, fmt.Errorf("gosmpp: vCard without name")
+	}
+
+	var b strings.Builder
+	b.WriteString("BEGIN:VCARD\r\nVERSION:2.1\r\n")
+	writeProperty(&b, "N", c.Name)
+	writeProperty(&b, "FN", c.Name)
+	writeProperty(&b, "TEL;PREF", c.Phone)
+	writeProperty(&b, "EMAIL;INTERNET", c.Email)
+	writeProperty(&b, "ORG", c.Organization)
+	writeProperty(&b, "URL", c.URL)
+	b.WriteString("END:VCARD\r\n")
+	return []byte(b.String()), nil
+}
+
+// VEvent is calendar event in vCalendar 1.0 format.
+type VEvent struct {
+	// Summary and Start are required.
+	Summary string
+	Start   time.Time
+
+	// End, Location and Description are optional.
+	End         time.Time
+	Location    string
+	Description string
+}
+
+// Encode returns the event in vCalendar 1.0 format.
+func (e VEvent) Encode() ([]byte, error) {
+	if e.Summary == "" || e.Start.IsZero() {
+		return nil, fmt.Errorf("gosmpp: vCalendar event without summary or start")
+	}
+
+	var b strings.Builder
+	b.WriteString("BEGIN:VCALENDAR\r\nVERSION:1.0\r\nBEGIN:VEVENT\r\n")
+	writeProperty(&b, "SUMMARY", e.Summary)
+	writeProperty(&b, "DTSTART", vCalendarTime(e.Start))
+	if !e.End.IsZero() {
+		writeProperty(&b, "DTEND", vCalendarTime(e.End))
+	}
+	writeProperty(&b, "LOCATION", e.Location)
+	writeProperty(&b, "DESCRIPTION", e.Description)
+	b.WriteString("END:VEVENT\r\nEND:VCALENDAR\r\n")
+	return []byte(b.String()), nil
+}
+
+// SendVCard sends contact card to vCard port of destination in 8-bit binary parts.
+func (m *Messenger) SendVCard(ctx context.Context, from, to string, card VCard) (*MessageHandle, error) {
+	payload, err := card.Encode()
+	if err != nil {
+		return nil, err
+	}
+	return m.sendBinary(ctx, from, to, pdu.UDH{pdu.NewIEApplicationPort(VCardPort, 0)}, payload)
+}
+
+// SendVCalendar sends calendar event to vCalendar port of destination in 8-bit binary parts.
+func (m *Messenger) SendVCalendar(ctx context.Context, from, to string, event VEvent) (*MessageHandle, error) {
+	payload, err := event.Encode()
+	if err != nil {
+		return nil, err
+	}
+	return m.sendBinary(ctx, from, to, pdu.UDH{pdu.NewIEApplicationPort(VCalendarPort, 0)}, payload)
+}
+
+// writeProperty writes property line if value is not empty. Line breaks in value are replaced by spaces.
+func writeProperty(b *strings.Builder, name, value string) {
+	if value == "" {
+		return
+	}
+
+	value = strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ").Replace(value)
+	fmt.Fprintf(b, "%s:%s\r\n", name, value)
+}
+
+func vCalendarTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
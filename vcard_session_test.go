@@ -0,0 +1,94 @@
+package gosmpp_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp"
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+	"github.com/linxGnu/gosmpp/server/smsctest"
+
+	"github.com/stretchr/testify/require"
+)
+
+// submittedBinary returns payload reassembled from binary parts submitted to smsc, checking each part is
+// addressed to destPort from srcPort.
+func submittedBinary(t *testing.T, smsc *smsctest.Server, destPort, srcPort uint16) (payload []byte, parts int) {
+	submitted := smsc.Submitted()
+	for i, p := range submitted {
+		submit := p.(*pdu.SubmitSM)
+		require.Equal(t, data.BINARY8BIT2, submit.Message.Encoding())
+		require.NotZero(t, submit.EsmClass&data.SM_UDH_GSM)
+
+		udh := submit.Message.UDH()
+		dest, src, found := udh.GetApplicationPort()
+		require.True(t, found)
+		require.Equal(t, destPort, dest)
+		require.Equal(t, srcPort, src)
+		if len(submitted) > 1 {
+			total, seq, _, found := udh.GetConcatInfo()
+			require.True(t, found)
+			require.EqualValues(t, len(submitted), total)
+			require.EqualValues(t, i+1, seq)
+		}
+
+		d, err := submit.Message.GetMessageData()
+		require.Nil(t, err)
+		payload = append(payload, d...)
+	}
+	return payload, len(submitted)
+}
+
+func TestMessengerSendVCard(t *testing.T) {
+	smsc := smsctest.NewPipeServer(nil)
+	defer smsc.Close()
+
+	s, err := gosmpp.NewSession(gosmpp.TRXConnector(smsc.Dialer(), gosmpp.Auth{SMSC: "pipe", SystemID: "esme"}),
+		gosmpp.Settings{ReadTimeout: 2 * time.Second}, -1)
+	require.Nil(t, err)
+	defer func() {
+		_ = s.Close()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	m := gosmpp.NewMessenger(s)
+	card := gosmpp.VCard{
+		Name:         "My Bank",
+		Phone:        "+84901234567",
+		Email:        "support@bank.example",
+		Organization: "My Bank Corporation",
+		URL:          "https://www.bank.example/contact",
+	}
+	h, err := m.SendVCard(ctx, "MyBank", "84901234567", card)
+	require.Nil(t, err)
+	require.Len(t, h.Parts, 2)
+
+	expected, err := card.Encode()
+	require.Nil(t, err)
+	payload, parts := submittedBinary(t, smsc, gosmpp.VCardPort, 0)
+	require.Equal(t, 2, parts)
+	require.Equal(t, string(expected), string(payload))
+
+	event := gosmpp.VEvent{Summary: "Appointment", Start: time.Now()}
+	h, err = m.SendVCalendar(ctx, "MyBank", "84901234567", event)
+	require.Nil(t, err)
+	require.Len(t, h.Parts, 1)
+	require.NotEmpty(t, h.MessageIDs[0])
+
+	submitted := smsc.Submitted()
+	require.Len(t, submitted, 3)
+	submit := submitted[2].(*pdu.SubmitSM)
+	destPort, _, found := submit.Message.UDH().GetApplicationPort()
+	require.True(t, found)
+	require.Equal(t, gosmpp.VCalendarPort, destPort)
+
+	expected, err = event.Encode()
+	require.Nil(t, err)
+	d, err := submit.Message.GetMessageData()
+	require.Nil(t, err)
+	require.Equal(t, string(expected), string(d))
+}
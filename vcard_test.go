@@ -0,0 +1,30 @@
+package gosmpp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVCard(t *testing.T) {
+	b, err := VCard{Name: "My Bank", Phone: "+84901234567", URL: "https://bank.example"}.Encode()
+	require.Nil(t, err)
+	require.Equal(t, "BEGIN:VCARD\r\nVERSION:2.1\r\nN:My Bank\r\nFN:My Bank\r\nTEL;PREF:+84901234567\r\n"+
+		"URL:https://bank.example\r\nEND:VCARD\r\n", string(b))
+
+	_, err = VCard{Phone: "123"}.Encode()
+	require.NotNil(t, err)
+}
+
+func TestVEvent(t *testing.T) {
+	start := time.Date(2024, 5, 1, 19, 30, 0, 0, time.FixedZone("ICT", 7*3600))
+	b, err := VEvent{Summary: "Meeting", Start: start, End: start.Add(time.Hour), Location: "Room 1\nFloor 2"}.Encode()
+	require.Nil(t, err)
+	require.Equal(t, "BEGIN:VCALENDAR\r\nVERSION:1.0\r\nBEGIN:VEVENT\r\nSUMMARY:Meeting\r\n"+
+		"DTSTART:20240501T123000Z\r\nDTEND:20240501T133000Z\r\nLOCATION:Room 1 Floor 2\r\n"+
+		"END:VEVENT\r\nEND:VCALENDAR\r\n", string(b))
+
+	_, err = VEvent{Summary: "no start"}.Encode()
+	require.NotNil(t, err)
+}
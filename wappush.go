@@ -0,0 +1,252 @@
+package gosmpp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/linxGnu/gosmpp/pdu"
+)
+
+// WDP ports of WAP Push over SMS.
+const (
+	WAPPushPort       uint16 = 2948
+	WAPPushSourcePort uint16 = 9200
+)
+
+// WAPPush is WAP Push message, e.g. ServiceIndication or ServiceLoad.
+type WAPPush interface {
+	// Payload returns WSP push PDU with WBXML content.
+	Payload() ([]byte, error)
+}
+
+// SIAction is signal action of ServiceIndication.
+type SIAction string
+
+// ServiceIndication actions.
+const (
+	SISignalNone   SIAction = "signal-none"
+	SISignalLow    SIAction = "signal-low"
+	SISignalMedium SIAction = "signal-medium"
+	SISignalHigh   SIAction = "signal-high"
+	SIDelete       SIAction = "delete"
+)
+
+// ServiceIndication is WAP Push Service Indication (SI): a text with link shown to user, who decides to open it.
+type ServiceIndication struct {
+	// Href is the link.
+	Href string
+
+	// Text is shown to user.
+	Text string
+
+	// ID identifies indication, e.g. to replace or delete it later. Optional.
+	ID string
+
+	// Created and Expires are optional times of creation and expiration.
+	Created, Expires time.Time
+
+	// Action is optional, signal-medium by default.
+	Action SIAction
+}
+
+// Payload implements WAPPush.
+func (si ServiceIndication) Payload() ([]byte, error) {
+	if si.Href == "" && si.Action != SIDelete {
+		return nil, fmt.Errorf("gosmpp: service indication without href")
+	}
+
+	var action byte
+	if si.Action != "" {
+		var ok bool
+		if action, ok = siActions[si.Action]; !ok {
+			return nil, fmt.Errorf("gosmpp: unknown service indication action %q", si.Action)
+		}
+	}
+
+	b := append(wspPushHeader(wspContentTypeSI), wbxmlVersion, wbxmlPublicIDSI, wbxmlCharsetUTF8, 0x00)
+
+	b = append(b, 0x05|wbxmlContent) // <si>
+	indication := byte(0x06 | wbxmlAttributes)
+	if si.Text != "" {
+		indication |= wbxmlContent
+	}
+	b = append(b, indication) // <indication>
+
+	if si.Href != "" {
+		b = wbxmlHref(b, si.Href, siHrefs, 0x0B)
+	}
+	if si.ID != "" {
+		b = wbxmlString(append(b, 0x11), si.ID)
+	}
+	if !si.Created.IsZero() {
+		b = wbxmlDate(append(b, 0x0A), si.Created)
+	}
+	if !si.Expires.IsZero() {
+		b = wbxmlDate(append(b, 0x10), si.Expires)
+	}
+	if action != 0 {
+		b = append(b, action)
+	}
+	b = append(b, wbxmlEnd)
+
+	if si.Text != "" {
+		b = append(wbxmlString(b, si.Text), wbxmlEnd) // </indication>
+	}
+	return append(b, wbxmlEnd), nil // </si>
+}
+
+// SLAction is execute action of ServiceLoad.
+type SLAction string
+
+// ServiceLoad actions.
+const (
+	SLExecuteLow  SLAction = "execute-low"
+	SLExecuteHigh SLAction = "execute-high"
+	SLCache       SLAction = "cache"
+)
+
+// ServiceLoad is WAP Push Service Load (SL): a link loaded by handset without user intervention.
+type ServiceLoad struct {
+	// Href is the link.
+	Href string
+
+	// Action is optional, execute-low by default.
+	Action SLAction
+}
+
+// Payload implements WAPPush.
+func (sl ServiceLoad) Payload() ([]byte, error) {
+	if sl.Href == "" {
+		return nil, fmt.Errorf("gosmpp: service load without href")
+	}
+
+	var action byte
+	if sl.Action != "" {
+		var ok bool
+		if action, ok = slActions[sl.Action]; !ok {
+			return nil, fmt.Errorf("gosmpp: unknown service load action %q", sl.Action)
+		}
+	}
+
+	b := append(wspPushHeader(wspContentTypeSL), wbxmlVersion, wbxmlPublicIDSL, wbxmlCharsetUTF8, 0x00)
+
+	b = append(b, 0x05|wbxmlAttributes) // <sl/>
+	b = wbxmlHref(b, sl.Href, slHrefs, 0x08)
+	if action != 0 {
+		b = append(b, action)
+	}
+	return append(b, wbxmlEnd), nil
+}
+
+// SendWAPPush sends WAP Push message to WAP Push port of destination in 8-bit binary parts.
+func (m *Messenger) SendWAPPush(ctx context.Context, from, to string, push WAPPush) (*MessageHandle, error) {
+	payload, err := push.Payload()
+	if err != nil {
+		return nil, err
+	}
+	return m.sendBinary(ctx, from, to, pdu.UDH{pdu.NewIEApplicationPort(WAPPushPort, WAPPushSourcePort)}, payload)
+}
+
+// WSP and WBXML encoding, see WAP-230-WSP, WAP-192-WBXML, WAP-167-ServiceInd and WAP-168-ServiceLoad.
+const (
+	wspContentTypeSI = 0xAE // application/vnd.wap.sic
+	wspContentTypeSL = 0xB0 // application/vnd.wap.slc
+
+	wbxmlVersion     = 0x02
+	wbxmlPublicIDSI  = 0x05
+	wbxmlPublicIDSL  = 0x06
+	wbxmlCharsetUTF8 = 0x6A
+
+	wbxmlEnd        = 0x01
+	wbxmlStrI       = 0x03
+	wbxmlOpaque     = 0xC3
+	wbxmlContent    = 0x40
+	wbxmlAttributes = 0x80
+)
+
+type wbxmlToken struct {
+	s     string
+	token byte
+}
+
+var (
+	siActions = map[SIAction]byte{
+		SISignalNone: 0x05, SISignalLow: 0x06, SISignalMedium: 0x07, SISignalHigh: 0x08, SIDelete: 0x09,
+	}
+	slActions = map[SLAction]byte{
+		SLExecuteLow: 0x05, SLExecuteHigh: 0x06, SLCache: 0x07,
+	}
+
+	// href attribute start tokens by prefix, longest first
+	siHrefs = []wbxmlToken{{"https://www.", 0x0F}, {"http://www.", 0x0D}, {"https://", 0x0E}, {"http://", 0x0C}}
+	slHrefs = []wbxmlToken{{"https://www.", 0x0C}, {"http://www.", 0x0A}, {"https://", 0x0B}, {"http://", 0x09}}
+
+	// attribute value tokens
+	wbxmlValues = []wbxmlToken{{".com/", 0x85}, {".edu/", 0x86}, {".net/", 0x87}, {".org/", 0x88}}
+)
+
+// wspPushHeader returns connectionless WSP push PDU header with content type and UTF-8 charset.
+func wspPushHeader(contentType byte) []byte {
+	return []byte{
+		0x01,                          // transaction id
+		0x06,                          // push
+		0x04,                          // headers length
+		0x03, contentType, 0x81, 0xEA, // content type with charset utf-8
+	}
+}
+
+// wbxmlHref appends href attribute, using start token of the longest matching prefix, or plain start token.
+func wbxmlHref(b []byte, href string, prefixes []wbxmlToken, plain byte) []byte {
+	for _, p := range prefixes {
+		if strings.HasPrefix(href, p.s) {
+			return wbxmlValue(append(b, p.token), href[len(p.s):])
+		}
+	}
+	return wbxmlValue(append(b, plain), href)
+}
+
+// wbxmlValue appends attribute value, replacing common parts with value tokens.
+func wbxmlValue(b []byte, s string) []byte {
+	for s != "" {
+		i, token := -1, wbxmlToken{}
+		for _, v := range wbxmlValues {
+			if j := strings.Index(s, v.s); j >= 0 && (i < 0 || j < i) {
+				i, token = j, v
+			}
+		}
+
+		if i < 0 {
+			return wbxmlString(b, s)
+		}
+		if i > 0 {
+			b = wbxmlString(b, s[:i])
+		}
+		b, s = append(b, token.token), s[i+len(token.s):]
+	}
+	return b
+}
+
+// wbxmlString appends inline string.
+func wbxmlString(b []byte, s string) []byte {
+	b = append(b, wbxmlStrI)
+	b = append(b, s...)
+	return append(b, 0x00)
+}
+
+// wbxmlDate appends date as opaque data: digits of YYYYMMDDhhmmss in UTC packed by two, trailing zero octets removed.
+func wbxmlDate(b []byte, t time.Time) []byte {
+	digits := t.UTC().Format("20060102150405")
+
+	date := make([]byte, 0, len(digits)/2)
+	for i := 0; i < len(digits); i += 2 {
+		date = append(date, (digits[i]-'0')<<4|(digits[i+1]-'0'))
+	}
+	for len(date) > 0 && date[len(date)-1] == 0 {
+		date = date[:len(date)-1]
+	}
+
+	b = append(b, wbxmlOpaque, byte(len(date)))
+	return append(b, date...)
+}
@@ -0,0 +1,51 @@
+package gosmpp_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp"
+	"github.com/linxGnu/gosmpp/server/smsctest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessengerSendWAPPush(t *testing.T) {
+	smsc := smsctest.NewPipeServer(nil)
+	defer smsc.Close()
+
+	s, err := gosmpp.NewSession(gosmpp.TRXConnector(smsc.Dialer(), gosmpp.Auth{SMSC: "pipe", SystemID: "esme"}),
+		gosmpp.Settings{ReadTimeout: 2 * time.Second}, -1)
+	require.Nil(t, err)
+	defer func() {
+		_ = s.Close()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	si := gosmpp.ServiceIndication{
+		Href: "https://www.example.com/offers/" + strings.Repeat("x", 120),
+		Text: "New offers",
+	}
+	h, err := gosmpp.NewMessenger(s).SendWAPPush(ctx, "Shop", "84901234567", si)
+	require.Nil(t, err)
+	require.Len(t, h.Parts, 2)
+	for i := range h.Parts {
+		require.NotEmpty(t, h.MessageIDs[i])
+	}
+
+	payload, parts := submittedBinary(t, smsc, gosmpp.WAPPushPort, gosmpp.WAPPushSourcePort)
+	require.Equal(t, 2, parts)
+
+	// WSP push of SI content type, followed by WBXML of SI
+	require.Equal(t, []byte{0x01, 0x06, 0x04, 0x03, 0xAE, 0x81, 0xEA}, payload[:7])
+	require.Equal(t, []byte{0x02, 0x05, 0x6A, 0x00, 0x45}, payload[7:12])
+	require.Contains(t, string(payload), "New offers")
+
+	expected, err := si.Payload()
+	require.Nil(t, err)
+	require.Equal(t, expected, payload)
+}
@@ -0,0 +1,69 @@
+package gosmpp
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func wbxmlHex(parts ...string) string {
+	var b strings.Builder
+	for _, p := range parts {
+		if strings.HasPrefix(p, "'") {
+			b.WriteString(hex.EncodeToString([]byte(strings.Trim(p, "'"))))
+		} else {
+			b.WriteString(strings.ToLower(strings.ReplaceAll(p, " ", "")))
+		}
+	}
+	return b.String()
+}
+
+func TestServiceIndication(t *testing.T) {
+	// example of WAP-167-ServiceInd
+	si := ServiceIndication{
+		Href:    "http://www.xyz.com/email/123/abc.wml",
+		Text:    "You have 4 new emails",
+		Created: time.Date(1999, 6, 25, 15, 23, 15, 0, time.UTC),
+		Expires: time.Date(1999, 6, 30, 0, 0, 0, 0, time.UTC),
+	}
+	b, err := si.Payload()
+	require.Nil(t, err)
+	require.Equal(t, wbxmlHex(
+		"01 06 04 03 AE 81 EA",
+		"02 05 6A 00 45 C6 0D 03", "'xyz'", "00 85 03", "'email/123/abc.wml'", "00",
+		"0A C3 07 19 99 06 25 15 23 15 10 C3 04 19 99 06 30 01",
+		"03", "'You have 4 new emails'", "00 01 01",
+	), hex.EncodeToString(b))
+
+	b, err = ServiceIndication{Href: "example.org/a", ID: "1", Action: SISignalHigh}.Payload()
+	require.Nil(t, err)
+	require.Equal(t, wbxmlHex(
+		"01 06 04 03 AE 81 EA 02 05 6A 00 45 86 0B 03", "'example'", "00 88 03", "'a'", "00",
+		"11 03", "'1'", "00 08 01 01",
+	), hex.EncodeToString(b))
+
+	_, err = ServiceIndication{Text: "no link"}.Payload()
+	require.NotNil(t, err)
+	_, err = ServiceIndication{Href: "http://a", Action: "blink"}.Payload()
+	require.NotNil(t, err)
+}
+
+func TestServiceLoad(t *testing.T) {
+	// example of WAP-168-ServiceLoad
+	b, err := ServiceLoad{Href: "http://www.xyz.com/ppaid/123/abc.wml"}.Payload()
+	require.Nil(t, err)
+	require.Equal(t, wbxmlHex(
+		"01 06 04 03 B0 81 EA",
+		"02 06 6A 00 85 0A 03", "'xyz'", "00 85 03", "'ppaid/123/abc.wml'", "00 01",
+	), hex.EncodeToString(b))
+
+	b, err = ServiceLoad{Href: "https://a.net/", Action: SLCache}.Payload()
+	require.Nil(t, err)
+	require.Equal(t, wbxmlHex("01 06 04 03 B0 81 EA 02 06 6A 00 85 0B 03", "'a'", "00 87 07 01"), hex.EncodeToString(b))
+
+	_, err = ServiceLoad{}.Payload()
+	require.NotNil(t, err)
+}
@@ -0,0 +1,256 @@
+package websocket
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+// Opcodes of frames, RFC 6455 section 5.2.
+const (
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xA
+)
+
+// Status codes of close frames, RFC 6455 section 7.4.1.
+const (
+	closeNormal          = 1000
+	closeProtocolError   = 1002
+	closeUnsupportedData = 1003
+)
+
+const maxControlPayload = 125
+
+// ErrProtocol indicates peer violated WebSocket protocol, the connection is closed.
+var ErrProtocol = errors.New("websocket: protocol error")
+
+// Conn is net.Conn exchanging binary WebSocket messages. Each Write is sent as one message, so PDUs written
+// whole are framed one per message. Read returns payload of received messages as a stream, regardless of
+// their boundaries.
+//
+// Pings are answered with pongs while reading. Read returns io.EOF once peer closes the connection.
+type Conn struct {
+	conn   net.Conn
+	br     *bufio.Reader
+	client bool // client masks its frames, server does not
+
+	rmu       sync.Mutex
+	remaining uint64 // unread payload of current data frame
+	masked    bool
+	mask      [4]byte
+	maskPos   int
+
+	wmu    sync.Mutex
+	closed bool // close frame sent
+}
+
+func newConn(conn net.Conn, br *bufio.Reader, client bool) *Conn {
+	if br == nil {
+		br = bufio.NewReader(conn)
+	}
+	return &Conn{conn: conn, br: br, client: client}
+}
+
+// Read reads payload of binary messages.
+func (c *Conn) Read(b []byte) (n int, err error) {
+	c.rmu.Lock()
+	defer c.rmu.Unlock()
+
+	for c.remaining == 0 {
+		if err = c.nextFrame(); err != nil {
+			return
+		}
+	}
+
+	if uint64(len(b)) > c.remaining {
+		b = b[:c.remaining]
+	}
+	n, err = c.br.Read(b)
+	c.remaining -= uint64(n)
+	if c.masked {
+		for i := range b[:n] {
+			b[i] ^= c.mask[c.maskPos%4]
+			c.maskPos++
+		}
+	}
+	return
+}
+
+// nextFrame reads header of next data frame, handling control frames before it.
+func (c *Conn) nextFrame() error {
+	var header [2]byte
+	if _, err := io.ReadFull(c.br, header[:]); err != nil {
+		return err
+	}
+	fin, opcode := header[0]&0x80 != 0, header[0]&0x0F
+	masked, length := header[1]&0x80 != 0, uint64(header[1]&0x7F)
+
+	if header[0]&0x70 != 0 {
+		return c.fail(closeProtocolError, "reserved bits are set")
+	}
+	if masked == c.client {
+		return c.fail(closeProtocolError, "invalid masking of frame")
+	}
+
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(c.br, ext[:]); err != nil {
+			return err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(c.br, ext[:]); err != nil {
+			return err
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+
+	c.masked, c.maskPos = masked, 0
+	if masked {
+		if _, err := io.ReadFull(c.br, c.mask[:]); err != nil {
+			return err
+		}
+	}
+
+	switch opcode {
+	case opBinary, opContinuation:
+		c.remaining = length
+		return nil
+	case opText:
+		return c.fail(closeUnsupportedData, "text messages are not supported")
+	case opClose, opPing, opPong:
+		if !fin || length > maxControlPayload {
+			return c.fail(closeProtocolError, "invalid control frame")
+		}
+	default:
+		return c.fail(closeProtocolError, fmt.Sprintf("unknown opcode %d", opcode))
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(c.br, payload); err != nil {
+		return err
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= c.mask[i%4]
+		}
+	}
+
+	switch opcode {
+	case opPing:
+		return c.writeFrame(opPong, payload)
+	case opClose:
+		_ = c.writeClose(closeNormal)
+		return io.EOF
+	}
+	return nil
+}
+
+// fail sends close frame with status and returns ErrProtocol.
+func (c *Conn) fail(status uint16, reason string) error {
+	_ = c.writeClose(status)
+	return fmt.Errorf("%w: %s", ErrProtocol, reason)
+}
+
+// Write sends b as one binary message.
+func (c *Conn) Write(b []byte) (int, error) {
+	if err := c.writeFrame(opBinary, b); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+func (c *Conn) writeClose(status uint16) error {
+	var payload [2]byte
+	binary.BigEndian.PutUint16(payload[:], status)
+	return c.writeFrame(opClose, payload[:])
+}
+
+func (c *Conn) writeFrame(opcode byte, payload []byte) error {
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+
+	if c.closed {
+		return net.ErrClosed
+	}
+	if opcode == opClose {
+		c.closed = true
+	}
+
+	frame := make([]byte, 0, 14+len(payload))
+	frame = append(frame, 0x80|opcode)
+
+	var maskBit byte
+	if c.client {
+		maskBit = 0x80
+	}
+	switch n := len(payload); {
+	case n <= maxControlPayload:
+		frame = append(frame, maskBit|byte(n))
+	case n <= 0xFFFF:
+		frame = append(frame, maskBit|126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(n))
+	default:
+		frame = append(frame, maskBit|127)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(n))
+	}
+
+	if c.client {
+		var mask [4]byte
+		if _, err := rand.Read(mask[:]); err != nil {
+			return err
+		}
+		frame = append(frame, mask[:]...)
+		for i, v := range payload {
+			frame = append(frame, v^mask[i%4])
+		}
+	} else {
+		frame = append(frame, payload...)
+	}
+
+	_, err := c.conn.Write(frame)
+	return err
+}
+
+// Close sends close frame and closes the underlying connection.
+func (c *Conn) Close() error {
+	_ = c.writeClose(closeNormal)
+	return c.conn.Close()
+}
+
+// LocalAddr returns local address of the underlying connection.
+func (c *Conn) LocalAddr() net.Addr {
+	return c.conn.LocalAddr()
+}
+
+// RemoteAddr returns remote address of the underlying connection.
+func (c *Conn) RemoteAddr() net.Addr {
+	return c.conn.RemoteAddr()
+}
+
+// SetDeadline sets deadlines of the underlying connection.
+func (c *Conn) SetDeadline(t time.Time) error {
+	return c.conn.SetDeadline(t)
+}
+
+// SetReadDeadline sets read deadline of the underlying connection.
+func (c *Conn) SetReadDeadline(t time.Time) error {
+	return c.conn.SetReadDeadline(t)
+}
+
+// SetWriteDeadline sets write deadline of the underlying connection.
+func (c *Conn) SetWriteDeadline(t time.Time) error {
+	return c.conn.SetWriteDeadline(t)
+}
@@ -0,0 +1,211 @@
+// Package websocket carries SMPP over WebSocket (RFC 6455) connections, so that binds can traverse networks
+// allowing only HTTPS egress. PDUs are framed in binary messages, one per message.
+//
+// Client dials WebSocket URL given as SMSC address:
+//
+//	session, err := gosmpp.NewSession(
+//		gosmpp.TRXConnector(websocket.Dialer(nil, nil), gosmpp.Auth{SMSC: "wss://smsc.example.com/smpp", ...}),
+//		settings, 5*time.Second)
+//
+// Server serves SMPP over WebSocket endpoint of HTTP server:
+//
+//	http.Handle("/smpp", websocket.Handler(srv))
+package websocket
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/linxGnu/gosmpp"
+	"github.com/linxGnu/gosmpp/server"
+)
+
+// Subprotocol is WebSocket subprotocol of SMPP, requested by Dialer and accepted by Upgrade.
+const Subprotocol = "smpp"
+
+// acceptGUID is appended to key of handshake, RFC 6455 section 1.3.
+const acceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// ErrHandshake indicates WebSocket handshake failed.
+var ErrHandshake = errors.New("websocket: handshake failed")
+
+// Dialer returns gosmpp.Dialer connecting to WebSocket URL given as SMSC address, ws:// or wss://.
+// Connections of wss:// URLs use tlsConfig, which may be nil. Header is sent with handshake request,
+// e.g. Authorization of a proxy, it may be nil.
+func Dialer(tlsConfig *tls.Config, header http.Header) gosmpp.Dialer {
+	return func(addr string) (net.Conn, error) {
+		return Dial(addr, tlsConfig, header)
+	}
+}
+
+// Dial connects to WebSocket URL, see Dialer.
+func Dial(rawURL string, tlsConfig *tls.Config, header http.Header) (net.Conn, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	host := u.Host
+	var conn net.Conn
+	switch u.Scheme {
+	case "ws":
+		if u.Port() == "" {
+			host = net.JoinHostPort(u.Hostname(), "80")
+		}
+		conn, err = net.Dial("tcp", host)
+	case "wss":
+		if u.Port() == "" {
+			host = net.JoinHostPort(u.Hostname(), "443")
+		}
+		config := tlsConfig.Clone()
+		if config == nil {
+			config = &tls.Config{}
+		}
+		if config.ServerName == "" {
+			config.ServerName = u.Hostname()
+		}
+		conn, err = tls.Dial("tcp", host, config)
+	default:
+		return nil, fmt.Errorf("websocket: unsupported scheme of %q, expected ws or wss", rawURL)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ws, err := handshake(conn, u, header)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return ws, nil
+}
+
+// handshake sends opening handshake of client over conn and reads response of server.
+func handshake(conn net.Conn, u *url.URL, header http.Header) (*Conn, error) {
+	var nonce [16]byte
+	if _, err := rand.Read(nonce[:]); err != nil {
+		return nil, err
+	}
+	key := base64.StdEncoding.EncodeToString(nonce[:])
+
+	req := &http.Request{
+		Method:     http.MethodGet,
+		URL:        &url.URL{Path: u.Path, RawQuery: u.RawQuery},
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     header.Clone(),
+		Host:       u.Host,
+	}
+	if req.Header == nil {
+		req.Header = make(http.Header)
+	}
+	if req.URL.Path == "" {
+		req.URL.Path = "/"
+	}
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Sec-WebSocket-Key", key)
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Protocol", Subprotocol)
+	if err := req.Write(conn); err != nil {
+		return nil, err
+	}
+
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, req)
+	if err != nil {
+		return nil, err
+	}
+	_ = resp.Body.Close()
+
+	switch {
+	case resp.StatusCode != http.StatusSwitchingProtocols:
+		return nil, fmt.Errorf("%w: unexpected response %s", ErrHandshake, resp.Status)
+	case !headerContains(resp.Header, "Upgrade", "websocket"):
+		return nil, fmt.Errorf("%w: missing Upgrade header", ErrHandshake)
+	case resp.Header.Get("Sec-WebSocket-Accept") != acceptKey(key):
+		return nil, fmt.Errorf("%w: invalid Sec-WebSocket-Accept", ErrHandshake)
+	}
+	return newConn(conn, br, true), nil
+}
+
+// Upgrade completes opening handshake of WebSocket request and returns its connection. Response with error
+// status is written if request is not valid handshake.
+func Upgrade(w http.ResponseWriter, r *http.Request) (*Conn, error) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return nil, fmt.Errorf("%w: method %s", ErrHandshake, r.Method)
+	}
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if !headerContains(r.Header, "Connection", "upgrade") || !headerContains(r.Header, "Upgrade", "websocket") ||
+		key == "" {
+		http.Error(w, "websocket handshake expected", http.StatusBadRequest)
+		return nil, fmt.Errorf("%w: not websocket request", ErrHandshake)
+	}
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		http.Error(w, "unsupported websocket version", http.StatusUpgradeRequired)
+		return nil, fmt.Errorf("%w: unsupported version", ErrHandshake)
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "websocket not supported", http.StatusInternalServerError)
+		return nil, fmt.Errorf("%w: response does not support hijacking", ErrHandshake)
+	}
+	conn, brw, err := hijacker.Hijack()
+	if err != nil {
+		return nil, err
+	}
+
+	response := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + acceptKey(key) + "\r\n"
+	if headerContains(r.Header, "Sec-WebSocket-Protocol", Subprotocol) {
+		response += "Sec-WebSocket-Protocol: " + Subprotocol + "\r\n"
+	}
+	if _, err = conn.Write([]byte(response + "\r\n")); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return newConn(conn, brw.Reader, false), nil
+}
+
+// Handler returns http.Handler serving SMPP over WebSocket by srv, see Server.ServeConn.
+func Handler(srv *server.Server) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := Upgrade(w, r)
+		if err != nil {
+			return
+		}
+		_ = srv.ServeConn(conn)
+	})
+}
+
+func acceptKey(key string) string {
+	h := sha1.Sum([]byte(key + acceptGUID))
+	return base64.StdEncoding.EncodeToString(h[:])
+}
+
+// headerContains reports whether comma separated values of header contain token, ignoring case.
+func headerContains(header http.Header, name, token string) bool {
+	for _, value := range header.Values(name) {
+		for _, v := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(v), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,130 @@
+package websocket
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp"
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/server/smsctest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionOverWebSocket(t *testing.T) {
+	smsc := smsctest.NewPipeServer(map[string]string{"shop": "secret"})
+	defer smsc.Close()
+
+	for name, newServer := range map[string]func(http.Handler) *httptest.Server{
+		"ws":  httptest.NewServer,
+		"wss": httptest.NewTLSServer,
+	} {
+		t.Run(name, func(t *testing.T) {
+			ts := newServer(Handler(smsc.Config))
+			defer ts.Close()
+
+			dialer := Dialer(&tls.Config{InsecureSkipVerify: true}, http.Header{"X-Tenant": {"shop"}})
+			session, err := gosmpp.NewSession(
+				gosmpp.TRXConnector(dialer, gosmpp.Auth{
+					SMSC:     name + "://" + ts.Listener.Addr().String() + "/smpp",
+					SystemID: "shop",
+					Password: "secret",
+				}),
+				gosmpp.Settings{ReadTimeout: 2 * time.Second}, -1)
+			require.Nil(t, err)
+			defer func() {
+				_ = session.Close()
+			}()
+
+			h, err := gosmpp.NewMessenger(session).SendText(context.Background(), "MyShop", "+84901234567",
+				strings.Repeat("long text ", 30))
+			require.Nil(t, err)
+			require.Len(t, h.MessageIDs, 3)
+		})
+	}
+	smsc.ExpectReceived(t, data.SUBMIT_SM, 6)
+}
+
+func TestDialErrors(t *testing.T) {
+	_, err := Dial("http://localhost/smpp", nil, nil)
+	require.ErrorContains(t, err, "unsupported scheme")
+
+	ts := httptest.NewServer(http.NotFoundHandler())
+	defer ts.Close()
+	_, err = Dial("ws://"+ts.Listener.Addr().String(), nil, nil)
+	require.ErrorIs(t, err, ErrHandshake)
+
+	w := httptest.NewRecorder()
+	_, err = Upgrade(w, httptest.NewRequest(http.MethodGet, "/smpp", nil))
+	require.ErrorIs(t, err, ErrHandshake)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestAcceptKey(t *testing.T) {
+	// example of RFC 6455 section 1.3
+	require.Equal(t, "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=", acceptKey("dGhlIHNhbXBsZSBub25jZQ=="))
+}
+
+func TestConnFrames(t *testing.T) {
+	client, server := net.Pipe()
+	c := newConn(server, nil, false)
+	peer := bufio.NewReader(client)
+
+	go func() {
+		// fragmented binary message, masked, with ping in the middle
+		_, _ = client.Write([]byte{0x02, 0x83, 1, 2, 3, 4, 'a' ^ 1, 'b' ^ 2, 'c' ^ 3})
+		_, _ = client.Write([]byte{0x89, 0x81, 0, 0, 0, 0, 'p'})
+		_, _ = client.Write([]byte{0x80, 0x82, 0, 0, 0, 0, 'd', 'e'})
+	}()
+
+	// pong echoes ping, sent unmasked by server
+	pong := make(chan []byte, 1)
+	go func() {
+		b := make([]byte, 3)
+		_, _ = io.ReadFull(peer, b)
+		pong <- b
+	}()
+
+	b := make([]byte, 5)
+	_, err := io.ReadFull(c, b)
+	require.Nil(t, err)
+	require.Equal(t, "abcde", string(b))
+	require.Equal(t, []byte{0x8A, 0x01, 'p'}, <-pong)
+
+	go func() {
+		_, _ = c.Write(make([]byte, 300))
+	}()
+	header := make([]byte, 4)
+	_, err = io.ReadFull(peer, header)
+	require.Nil(t, err)
+	require.Equal(t, []byte{0x82, 126, 0x01, 0x2C}, header)
+	_, err = io.ReadFull(peer, make([]byte, 300))
+	require.Nil(t, err)
+
+	// unmasked frame of client is rejected with close frame
+	go func() {
+		_, _ = client.Write([]byte{0x82, 0x01, 'x'})
+	}()
+	errs := make(chan error, 1)
+	go func() {
+		_, err := c.Read(b)
+		errs <- err
+	}()
+	closeFrame := make([]byte, 4)
+	_, err = io.ReadFull(peer, closeFrame)
+	require.Nil(t, err)
+	require.Equal(t, []byte{0x88, 0x02, 0x03, 0xEA}, closeFrame)
+	require.ErrorIs(t, <-errs, ErrProtocol)
+
+	_, err = c.Write([]byte("late"))
+	require.ErrorIs(t, err, net.ErrClosed)
+	require.Nil(t, c.Close())
+}
@@ -0,0 +1,121 @@
+package gosmpp
+
+import (
+	"context"
+	"sync"
+
+	"github.com/linxGnu/gosmpp/pdu"
+)
+
+// submitWindow limits requests of Session.Submit awaiting response, see WithSubmitWindow.
+//
+// All methods are safe to call on nil receiver.
+type submitWindow struct {
+	block bool
+
+	// slots holds a token per request awaiting response
+	slots chan struct{}
+	held  seqMap[struct{}]
+
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+// WithSubmitWindow limits requests submitted by Submit and SubmitAsync of the session and awaiting response
+// (submit_sm, data_sm, ...) to size, as SMSC enforcing window size expects. Once the window is full, Submit
+// waits until a response frees a slot if block is true, or fails with ErrWindowsFull otherwise.
+//
+// Slot is freed by the response, by failure of writing the request, or once the bind is closed, as responses
+// are lost then. Requests never responded keep their slots until the bind is closed, so window goes well with
+// EnquireLink detecting dead binds. Waiting Submit fails with ErrSessionClosed once the session is closed.
+//
+// Unlike WindowedRequestTracking, which drops requests over MaxWindowSize when writing them, the window holds
+// them back before they are queued.
+//
+// SubmitAndWait and Messenger give up waiting for a slot once their context is done, failing with its error.
+func WithSubmitWindow(size int, block bool) SessionOption {
+	return func(s *Session) {
+		if size > 0 {
+			s.window = &submitWindow{
+				block:  block,
+				slots:  make(chan struct{}, size),
+				closed: make(chan struct{}),
+			}
+		}
+	}
+}
+
+// acquire takes slot for request p, waiting for it if the window is full and blocks, until ctx is done.
+func (w *submitWindow) acquire(ctx context.Context, p pdu.PDU) error {
+	if w == nil || !isAllowPDU(p) {
+		return nil
+	}
+
+	select {
+	case w.slots <- struct{}{}:
+	default:
+		if !w.block {
+			return ErrWindowsFull
+		}
+		select {
+		case w.slots <- struct{}{}:
+		case <-w.closed:
+			return ErrSessionClosed
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	seq := p.GetSequenceNumber()
+	if _, held := w.held.load(seq); held {
+		// request submitted again keeps its slot
+		<-w.slots
+		return nil
+	}
+	w.held.store(seq, struct{}{})
+	return nil
+}
+
+// release frees slot of request of sequence number seq, if it holds one.
+func (w *submitWindow) release(seq int32) {
+	if w == nil {
+		return
+	}
+	if _, ok := w.held.take(seq); ok {
+		<-w.slots
+	}
+}
+
+// releaseAll frees all slots, responses of their requests are lost.
+func (w *submitWindow) releaseAll() {
+	if w == nil {
+		return
+	}
+	for range w.held.drain() {
+		<-w.slots
+	}
+}
+
+// full tells whether all slots are taken.
+func (w *submitWindow) full() bool {
+	return w != nil && len(w.slots) == cap(w.slots)
+}
+
+// free returns number of free slots, -1 without window.
+func (w *submitWindow) free() int {
+	if w == nil {
+		return -1
+	}
+	return cap(w.slots) - len(w.slots)
+}
+
+// close wakes up waiting submitters.
+func (w *submitWindow) close() {
+	if w == nil {
+		return
+	}
+	w.closeOnce.Do(func() {
+		close(w.closed)
+	})
+	w.releaseAll()
+}
@@ -0,0 +1,111 @@
+package gosmpp_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp"
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+	"github.com/linxGnu/gosmpp/server/smsctest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newWindowedSession(t *testing.T, smsc *smsctest.Server, size int, block bool) *gosmpp.Session {
+	s, err := gosmpp.NewSession(gosmpp.TRXConnector(smsc.Dialer(), gosmpp.Auth{SMSC: "pipe", SystemID: "esme"}),
+		gosmpp.Settings{ReadTimeout: 2 * time.Second}, -1, gosmpp.WithSubmitWindow(size, block))
+	require.Nil(t, err)
+	t.Cleanup(func() {
+		_ = s.Close()
+	})
+	return s
+}
+
+func TestSubmitWindow(t *testing.T) {
+	smsc := smsctest.NewPipeServer(nil)
+	defer smsc.Close()
+	smsc.SetFault(smsctest.Delay(200 * time.Millisecond))
+
+	s := newWindowedSession(t, smsc, 2, false)
+	require.Nil(t, s.Submit(newTextSubmitSM("1", "first")))
+	require.Nil(t, s.Submit(newTextSubmitSM("1", "second")))
+	require.Zero(t, s.AvailableCapacity())
+	require.True(t, s.Saturated())
+
+	err := s.Submit(newTextSubmitSM("1", "third"))
+	require.True(t, errors.Is(err, gosmpp.ErrWindowsFull))
+
+	// responses free the window
+	require.Eventually(t, func() bool {
+		return s.AvailableCapacity() == 2
+	}, time.Second, 10*time.Millisecond)
+	require.Nil(t, s.Submit(newTextSubmitSM("1", "third")))
+	smsc.ExpectReceived(t, data.SUBMIT_SM, 3)
+}
+
+func TestSubmitWindowBlocking(t *testing.T) {
+	smsc := smsctest.NewPipeServer(nil)
+	defer smsc.Close()
+	smsc.SetFault(smsctest.Delay(100 * time.Millisecond))
+
+	s := newWindowedSession(t, smsc, 1, true)
+	started := time.Now()
+	for i := 0; i < 3; i++ {
+		require.Nil(t, s.Submit(newTextSubmitSM("1", "text")))
+	}
+
+	// every submit waits for response of the previous one
+	require.GreaterOrEqual(t, time.Since(started), 200*time.Millisecond)
+	smsc.ExpectReceived(t, data.SUBMIT_SM, 3)
+}
+
+func TestSubmitWindowClosed(t *testing.T) {
+	smsc := smsctest.NewPipeServer(nil)
+	defer smsc.Close()
+	smsc.SetFault(func(pdu.PDU) smsctest.Fault { return smsctest.Fault{Drop: true} })
+
+	s := newWindowedSession(t, smsc, 1, true)
+	require.Nil(t, s.Submit(newTextSubmitSM("1", "first")))
+
+	waiting := make(chan error, 1)
+	go func() {
+		waiting <- s.Submit(newTextSubmitSM("1", "second"))
+	}()
+	select {
+	case err := <-waiting:
+		t.Fatalf("submit over window returned %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	require.Nil(t, s.Close())
+	select {
+	case err := <-waiting:
+		require.NotNil(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("submit waits after session is closed")
+	}
+}
+
+func TestSubmitWindowContext(t *testing.T) {
+	smsc := smsctest.NewPipeServer(nil)
+	defer smsc.Close()
+	smsc.SetFault(func(pdu.PDU) smsctest.Fault { return smsctest.Fault{Drop: true} })
+
+	s := newWindowedSession(t, smsc, 1, true)
+	require.Nil(t, s.Submit(newTextSubmitSM("1", "first")))
+
+	// waiting for slot is given up once ctx is done
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	started := time.Now()
+	_, err := s.SubmitAndWait(ctx, newTextSubmitSM("1", "second"))
+	require.True(t, errors.Is(err, context.DeadlineExceeded))
+	require.Less(t, time.Since(started), time.Second)
+
+	_, err = gosmpp.NewMessenger(s).SendText(ctx, "MyShop", "1", "third")
+	require.True(t, errors.Is(err, context.DeadlineExceeded))
+	smsc.ExpectReceived(t, data.SUBMIT_SM, 1)
+}